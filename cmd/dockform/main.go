@@ -8,6 +8,7 @@ import (
 	"syscall"
 
 	"github.com/gcstr/dockform/internal/cli"
+	"github.com/gcstr/dockform/internal/drain"
 	"github.com/gcstr/dockform/internal/sshmux"
 )
 
@@ -19,11 +20,19 @@ var (
 func run() int {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	dc := drain.New()
+	ctx = drain.WithController(ctx, dc)
 
-	sigCh := make(chan os.Signal, 1)
+	// The first signal lets a running apply finish the resource it is on; a
+	// second one, or a first one while nothing can drain, cancels outright.
+	sigCh := make(chan os.Signal, 2)
 	notifySignal(sigCh, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		<-sigCh
+		if dc.Request() {
+			fmt.Fprintln(os.Stderr, "Stopping after the current resource; interrupt again to abort immediately.")
+			<-sigCh
+		}
 		cancel()
 	}()
 
@@ -7,8 +7,10 @@ import (
 	"sync"
 	"syscall"
 	"testing"
+	"time"
 
 	"github.com/gcstr/dockform/internal/cli"
+	"github.com/gcstr/dockform/internal/drain"
 )
 
 func TestRunCancelsOnSignal(t *testing.T) {
@@ -45,3 +47,36 @@ func TestRunCancelsOnSignal(t *testing.T) {
 		t.Fatalf("expected context to be canceled")
 	}
 }
+
+func TestRunDrainsOnFirstSignalWhenApplyIsRunning(t *testing.T) {
+	var sigs chan<- os.Signal
+	ready := make(chan struct{})
+	execCLI = func(ctx context.Context) int {
+		defer drain.FromContext(ctx).Begin()()
+		close(ready)
+		for !drain.Requested(ctx) {
+			time.Sleep(time.Millisecond)
+		}
+		if ctx.Err() != nil {
+			t.Errorf("expected the first signal to drain, not cancel")
+		}
+		sigs <- syscall.SIGTERM
+		<-ctx.Done()
+		return 130
+	}
+	notifySignal = func(c chan<- os.Signal, sig ...os.Signal) {
+		sigs = c
+		go func() {
+			<-ready
+			c <- syscall.SIGTERM
+		}()
+	}
+	defer func() {
+		execCLI = cli.Execute
+		notifySignal = signal.Notify
+	}()
+
+	if code := run(); code != 130 {
+		t.Fatalf("expected exit code 130, got %d", code)
+	}
+}
@@ -2,11 +2,16 @@ package applycmd_test
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 
+	"github.com/gcstr/dockform/internal/apperr"
 	"github.com/gcstr/dockform/internal/cli"
 	"github.com/gcstr/dockform/internal/cli/clitest"
+	"github.com/gcstr/dockform/internal/ui"
 )
 
 func TestApply_PrintsPlan_WhenRemovalsPresent(t *testing.T) {
@@ -74,6 +79,8 @@ case "$cmd" in
   network)
     sub="$1"; shift
     if [ "$sub" = "ls" ]; then exit 0; fi ;;
+  run)
+    echo "empty"; exit 0 ;;
   compose)
     for a in "$@"; do [ "$a" = "--services" ] && { echo "nginx"; exit 0; }; done
     prev=""
@@ -217,6 +224,9 @@ func TestApply_SkipConfirmation_BypassesPrompt(t *testing.T) {
 	if strings.Contains(got, "canceled") {
 		t.Fatalf("did not expect apply to be canceled when skipping confirmation; got: %s", got)
 	}
+	if !strings.Contains(got, "│ Stacks:") {
+		t.Fatalf("expected per-stack apply results; got: %s", got)
+	}
 }
 
 func TestApply_PruneErrors_NonStrictByDefault(t *testing.T) {
@@ -233,6 +243,8 @@ case "$cmd" in
   network)
     sub="$1"; shift
     if [ "$sub" = "ls" ]; then exit 0; fi ;;
+  run)
+    echo "empty"; exit 0 ;;
   compose)
     for a in "$@"; do [ "$a" = "--services" ] && { echo "nginx"; exit 0; }; done
     prev=""
@@ -288,6 +300,8 @@ case "$cmd" in
   network)
     sub="$1"; shift
     if [ "$sub" = "ls" ]; then exit 0; fi ;;
+  run)
+    echo "empty"; exit 0 ;;
   compose)
     for a in "$@"; do [ "$a" = "--services" ] && { echo "nginx"; exit 0; }; done
     prev=""
@@ -332,3 +346,297 @@ exit 0
 		t.Fatalf("expected prune-related error, got: %v", err)
 	}
 }
+
+func TestApply_DryRun_PrintsOperationsWithoutPrompt(t *testing.T) {
+	defer clitest.WithStubDocker(t)()
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"apply", "--dry-run", "--manifest", clitest.BasicConfigPath(t)})
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("apply --dry-run execute: %v", err)
+	}
+	got := out.String()
+	if strings.Contains(got, "Type yes to confirm") {
+		t.Fatalf("expected no confirmation prompt in dry run; got: %s", got)
+	}
+	if !strings.Contains(got, "docker compose up") || !strings.Contains(got, "remove volume orphan-vol") {
+		t.Fatalf("expected recorded compose up and volume removal; got: %s", got)
+	}
+	if strings.Contains(got, "│ Done.") {
+		t.Fatalf("dry run must not report completion; got: %s", got)
+	}
+}
+
+// backupConfigPath writes a manifest declaring two context volumes: "legacy",
+// which the backup stub reports as existing without dockform's label, and
+// "fresh", which does not exist yet.
+func backupConfigPath(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "website"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "website", "docker-compose.yaml"), []byte("services: {}\n"), 0o644); err != nil {
+		t.Fatalf("write compose: %v", err)
+	}
+	cfg := strings.Join([]string{
+		"identifier: demo",
+		"contexts:",
+		"  default:",
+		"    volumes:",
+		"      legacy: {}",
+		"      fresh: {}",
+		"stacks:",
+		"  default/website:",
+		"    root: website",
+		"    files:",
+		"      - docker-compose.yaml",
+	}, "\n") + "\n"
+	path := filepath.Join(dir, "dockform.yml")
+	if err := os.WriteFile(path, []byte(cfg), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+// withBackupStub installs a docker stub that records mutating volume calls in
+// the returned log file.
+func withBackupStub(t *testing.T) (string, func()) {
+	t.Helper()
+	logPath := filepath.Join(t.TempDir(), "docker.log")
+	undo := clitest.WithCustomDockerStub(t, `#!/bin/sh
+cmd="$1"; shift
+case "$cmd" in
+  volume)
+    sub="$1"; shift
+    case "$sub" in
+      ls) echo "legacy"; exit 0 ;;
+      inspect) echo '{"Name":"legacy","Driver":"local","Labels":{}}'; exit 0 ;;
+      create) echo "volume create $*" >> "`+logPath+`"; exit 0 ;;
+    esac
+    ;;
+  compose)
+    for a in "$@"; do [ "$a" = "ps" ] && { echo "[]"; exit 0; }; done
+    exit 0 ;;
+esac
+exit 0
+`)
+	return logPath, undo
+}
+
+func TestApply_BackupVolumesBefore_SkipsExternalAndMissing(t *testing.T) {
+	logPath, undo := withBackupStub(t)
+	defer undo()
+
+	backupDir := t.TempDir()
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"apply", "--skip-confirmation", "--backup-volumes-before", "--backup-dir", backupDir, "--manifest", backupConfigPath(t)})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("apply execute: %v\n%s", err, out.String())
+	}
+	got := out.String()
+	if !strings.Contains(got, "default/legacy skipped (external)") {
+		t.Fatalf("expected unlabeled volume to be skipped as external; got: %s", got)
+	}
+	if !strings.Contains(got, "default/fresh skipped (not created yet)") {
+		t.Fatalf("expected missing volume to be skipped; got: %s", got)
+	}
+	if !strings.Contains(got, "Volume backups: 0 backed up, 2 skipped → "+backupDir) {
+		t.Fatalf("expected backup summary under backup dir; got: %s", got)
+	}
+	if _, err := os.Stat(logPath); err != nil {
+		t.Fatalf("expected apply to create the missing volume after backing up: %v", err)
+	}
+}
+
+func TestApply_BackupVolumesBefore_FailsEarlyWhenDirUnwritable(t *testing.T) {
+	logPath, undo := withBackupStub(t)
+	defer undo()
+
+	blocker := filepath.Join(t.TempDir(), "not-a-dir")
+	if err := os.WriteFile(blocker, []byte("x"), 0o644); err != nil {
+		t.Fatalf("write blocker: %v", err)
+	}
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"apply", "--skip-confirmation", "--backup-volumes-before", "--backup-dir", blocker, "--manifest", backupConfigPath(t)})
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "cannot write volume backups") {
+		t.Fatalf("expected backup dir error, got: %v", err)
+	}
+	if _, statErr := os.Stat(logPath); !os.IsNotExist(statErr) {
+		t.Fatalf("expected no mutations before failing, but docker log exists")
+	}
+}
+
+func TestApply_HealthRollback_RejectsNonPositiveTimeout(t *testing.T) {
+	defer clitest.WithStubDocker(t)()
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"apply", "--skip-confirmation", "--health-rollback", "--health-timeout", "0s", "--manifest", clitest.BasicConfigPath(t)})
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--health-timeout must be positive") {
+		t.Fatalf("expected health timeout validation error, got: %v", err)
+	}
+}
+
+func TestApply_SummaryOnly_HidesPlanDetail(t *testing.T) {
+	defer clitest.WithStubDocker(t)()
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetIn(strings.NewReader("yes\n"))
+	root.SetArgs([]string{"apply", "--summary-only", "--manifest", clitest.BasicConfigPath(t)})
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("apply execute: %v", err)
+	}
+	got := out.String()
+	if strings.Contains(got, " will be deleted") || strings.Contains(got, " will be created") {
+		t.Fatalf("expected no per-resource plan lines with --summary-only; got: %s", got)
+	}
+	if !strings.Contains(got, "Plan:") || !strings.Contains(got, "Applied:") {
+		t.Fatalf("expected plan and result counters; got: %s", got)
+	}
+	if !strings.Contains(got, "Type yes to confirm") && !strings.Contains(got, "Answer:") {
+		t.Fatalf("expected the confirmation prompt to still be shown; got: %s", got)
+	}
+	if !regexp.MustCompile(`│ \d+ to create, \d+ to update, \d+ to remove, \d+ unchanged`).MatchString(got) {
+		t.Fatalf("expected the change tally before the prompt; got: %s", got)
+	}
+}
+
+func TestApply_SummaryOnly_RejectsLong(t *testing.T) {
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"apply", "--summary-only", "--long", "--manifest", clitest.BasicConfigPath(t)})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "cannot be combined") {
+		t.Fatalf("expected --summary-only/--long conflict, got: %v", err)
+	}
+}
+
+func TestApply_SkipValidate_BypassesValidationWithWarning(t *testing.T) {
+	defer clitest.WithStubDocker(t)()
+
+	cfgPath := clitest.BasicConfigPath(t)
+	content, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	// The env file does not exist, which validation rejects.
+	if err := os.WriteFile(cfgPath, append(content, []byte("    env-file:\n      - missing.env\n")...), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"apply", "--manifest", cfgPath, "--skip-confirmation"})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "missing.env") {
+		t.Fatalf("expected validation error naming missing.env without --skip-validate, got: %v", err)
+	}
+
+	root = cli.TestNewRootCmd()
+	out.Reset()
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"apply", "--manifest", cfgPath, "--skip-confirmation", "--skip-validate"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("apply with --skip-validate: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "validation skipped") {
+		t.Fatalf("expected skipped-validation warning; got: %s", out.String())
+	}
+}
+
+func TestApply_NonEmptyVolumeDeletion_RequiresAllowDataLoss(t *testing.T) {
+	stub := strings.Replace(applyUpToDateDockerStub, `echo "empty"`, `echo "notempty"`, 1)
+	defer clitest.WithCustomDockerStub(t, stub)()
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"apply", "--manifest", clitest.BasicConfigPath(t), "--skip-confirmation"})
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--allow-data-loss") {
+		t.Fatalf("expected refusal without --allow-data-loss, got: %v", err)
+	}
+	if !strings.Contains(out.String(), "DESTRUCTIVE: volume orphan-vol is non-empty") {
+		t.Fatalf("expected DESTRUCTIVE warning in plan; got: %s", out.String())
+	}
+
+	root = cli.TestNewRootCmd()
+	out.Reset()
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"apply", "--manifest", clitest.BasicConfigPath(t), "--skip-confirmation", "--allow-data-loss"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("apply with --allow-data-loss: %v\n%s", err, out.String())
+	}
+}
+
+func TestApply_Diff_RejectsSummaryOnly(t *testing.T) {
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"apply", "--diff", "--summary-only", "--manifest", clitest.BasicConfigPath(t)})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "--diff and --summary-only cannot be combined") {
+		t.Fatalf("expected --diff/--summary-only conflict, got: %v", err)
+	}
+}
+
+func TestApply_Diff_PrintsChangesInlineWithoutUpFrontPlan(t *testing.T) {
+	defer clitest.WithStubDocker(t)()
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"apply", "--diff", "--skip-confirmation", "--manifest", clitest.BasicConfigPath(t)})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("apply --diff: %v", err)
+	}
+	got := ui.StripANSI(out.String())
+	if !strings.Contains(got, "default/website") || !strings.Contains(got, "nginx will be created") {
+		t.Fatalf("expected the stack change printed inline; got: %s", got)
+	}
+	if strings.Contains(got, "Plan:") {
+		t.Fatalf("expected no up-front plan with --skip-confirmation; got: %s", got)
+	}
+}
+
+func TestApply_ExpectIdentifierMismatch_FailsBeforeDockerCalls(t *testing.T) {
+	log := filepath.Join(t.TempDir(), "docker.log")
+	defer clitest.WithCustomDockerStub(t, "#!/bin/sh\necho \"$@\" >> "+log+"\nexit 1\n")()
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"apply", "--manifest", clitest.BasicConfigPath(t), "--skip-confirmation", "--expect-identifier", "prod"})
+	err := root.Execute()
+	if !apperr.IsKind(err, apperr.Precondition) || !strings.Contains(err.Error(), "'prod'") {
+		t.Fatalf("expected identifier mismatch error, got: %v", err)
+	}
+	if b, _ := os.ReadFile(log); len(b) > 0 {
+		t.Fatalf("expected no docker calls before the identifier check, got:\n%s", b)
+	}
+}
@@ -0,0 +1,182 @@
+package applycmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gcstr/dockform/internal/planner"
+	"github.com/gcstr/dockform/internal/ui"
+)
+
+// Event is one line of the --output-events stream. Every event carries its
+// type and an RFC 3339 timestamp; the other fields depend on the type:
+//
+//   - plan_built: create, update, delete (planned action counts), duration_ms
+//   - resource_start: action, kind, name, context
+//   - resource_done: action, kind, name, context, status ("ok" or "failed"),
+//     changed, duration_ms, error (when failed)
+//   - stack_result: name, context, status ("ok" or "failed"), created,
+//     updated, skipped, restarted (service names), duration_ms, error (when
+//     failed)
+//   - warning: message
+//   - summary: status ("ok", "failed" or "no_changes"), create, update, delete,
+//     duration_ms (whole run), error (when failed)
+//
+// Fields that do not apply to an event are omitted.
+type Event struct {
+	Event      string   `json:"event"`
+	Time       string   `json:"time"`
+	Action     string   `json:"action,omitempty"`
+	Kind       string   `json:"kind,omitempty"`
+	Name       string   `json:"name,omitempty"`
+	Context    string   `json:"context,omitempty"`
+	Status     string   `json:"status,omitempty"`
+	Changed    *bool    `json:"changed,omitempty"`
+	DurationMs *int64   `json:"duration_ms,omitempty"`
+	Create     *int     `json:"create,omitempty"`
+	Update     *int     `json:"update,omitempty"`
+	Delete     *int     `json:"delete,omitempty"`
+	Created    []string `json:"created,omitempty"`
+	Updated    []string `json:"updated,omitempty"`
+	Skipped    []string `json:"skipped,omitempty"`
+	Restarted  []string `json:"restarted,omitempty"`
+	Message    string   `json:"message,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// eventStream writes events as newline-delimited JSON. Each event is written
+// with a single Write call, and flushed when the writer buffers, so the stream
+// stays line-atomic when resources are applied in parallel.
+type eventStream struct {
+	mu      sync.Mutex
+	out     io.Writer
+	started time.Time
+	counts  [3]int // planned create, update, delete
+}
+
+func newEventStream(out io.Writer) *eventStream {
+	return &eventStream{out: out, started: time.Now()}
+}
+
+func (s *eventStream) emit(e Event) {
+	e.Time = time.Now().UTC().Format(time.RFC3339Nano)
+	line, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, _ = s.out.Write(append(line, '\n'))
+	if f, ok := s.out.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+}
+
+func (s *eventStream) planBuilt(plan *planner.Plan, took time.Duration) {
+	create, update, del := plan.CountChanges()
+	s.counts = [3]int{create, update, del}
+	ms := took.Milliseconds()
+	s.emit(Event{Event: "plan_built", Create: &create, Update: &update, Delete: &del, DurationMs: &ms})
+}
+
+// planned returns the action counts of the built plan, zero before one is built.
+func (s *eventStream) planned() (create, update, del int) {
+	return s.counts[0], s.counts[1], s.counts[2]
+}
+
+func (s *eventStream) summary(create, update, del int, err error) {
+	ms := time.Since(s.started).Milliseconds()
+	e := Event{Event: "summary", Status: "ok", Create: &create, Update: &update, Delete: &del, DurationMs: &ms}
+	switch {
+	case err != nil:
+		e.Status = "failed"
+		e.Error = err.Error()
+	case create+update+del == 0:
+		e.Status = "no_changes"
+	}
+	s.emit(e)
+}
+
+// stackResults emits a stack_result event for every stack apply reached.
+func (s *eventStream) stackResults(results []planner.StackApplyResult) {
+	for _, r := range results {
+		ms := r.Duration.Milliseconds()
+		e := Event{
+			Event: "stack_result", Name: r.Stack, Context: r.Context, Status: "ok",
+			Created: r.Created, Updated: r.Updated, Skipped: r.Skipped, Restarted: r.Restarted,
+			DurationMs: &ms,
+		}
+		if r.Err != nil {
+			e.Status = "failed"
+			e.Error = r.Err.Error()
+		}
+		s.emit(e)
+	}
+}
+
+func (s *eventStream) warning(msg string) {
+	s.emit(Event{Event: "warning", Message: msg})
+}
+
+// warningWriter returns a writer that turns every non-empty line written to it
+// into a warning event, so printer warnings end up in the stream instead of
+// as human-readable text.
+func (s *eventStream) warningWriter() io.Writer {
+	return &lineEventWriter{stream: s}
+}
+
+type lineEventWriter struct {
+	mu     sync.Mutex
+	stream *eventStream
+	buf    bytes.Buffer
+}
+
+func (w *lineEventWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf.Write(p)
+	for {
+		line, err := w.buf.ReadString('\n')
+		if err != nil {
+			// Keep the partial line until the rest arrives.
+			w.buf.Reset()
+			w.buf.WriteString(line)
+			return len(p), nil
+		}
+		msg := strings.TrimSpace(ui.StripANSI(line))
+		msg = strings.TrimSpace(strings.TrimPrefix(msg, "[warn]"))
+		if msg != "" {
+			w.stream.warning(msg)
+		}
+	}
+}
+
+// ResourceStarted emits a resource_start event; eventStream is the planner's
+// Events for the run.
+func (s *eventStream) ResourceStarted(e planner.ResourceEvent) {
+	s.emit(Event{Event: "resource_start", Action: e.Action, Kind: e.Kind, Name: e.Name, Context: e.Context})
+}
+
+// ResourceFinished emits a resource_done event.
+func (s *eventStream) ResourceFinished(e planner.ResourceEvent) {
+	changed := e.Changed
+	ms := e.Duration.Milliseconds()
+	ev := Event{
+		Event: "resource_done", Action: e.Action, Kind: e.Kind, Name: e.Name, Context: e.Context,
+		Status: "ok", Changed: &changed, DurationMs: &ms,
+	}
+	if e.Err != "" {
+		ev.Status = "failed"
+		ev.Error = e.Err
+	}
+	s.emit(ev)
+}
+
+// Warning emits a warning event for a warning of the planner.
+func (s *eventStream) Warning(message string) {
+	s.warning(message)
+}
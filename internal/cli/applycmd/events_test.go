@@ -0,0 +1,94 @@
+package applycmd_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/cli"
+	"github.com/gcstr/dockform/internal/cli/applycmd"
+	"github.com/gcstr/dockform/internal/cli/clitest"
+)
+
+func TestApply_OutputEvents_StreamsNDJSON(t *testing.T) {
+	defer clitest.WithStubDocker(t)()
+
+	root := cli.TestNewRootCmd()
+	var out, errOut bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&errOut)
+	root.SetArgs([]string{"apply", "--manifest", clitest.BasicConfigPath(t), "--skip-confirmation", "--output-events"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("apply execute: %v\n%s", err, out.String())
+	}
+
+	var events []applycmd.Event
+	sc := bufio.NewScanner(&out)
+	for sc.Scan() {
+		var e applycmd.Event
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			t.Fatalf("stdout line is not a JSON event: %q (%v)", sc.Text(), err)
+		}
+		if e.Event == "" || e.Time == "" {
+			t.Fatalf("event without type or time: %q", sc.Text())
+		}
+		events = append(events, e)
+	}
+	if len(events) < 3 {
+		t.Fatalf("expected several events, got %d", len(events))
+	}
+	var planBuilt *applycmd.Event
+	for i := range events {
+		if events[i].Event == "plan_built" {
+			planBuilt = &events[i]
+			break
+		}
+	}
+	if planBuilt == nil || planBuilt.Delete == nil || *planBuilt.Delete == 0 {
+		t.Fatalf("expected plan_built with planned deletions, got %+v", events)
+	}
+	last := events[len(events)-1]
+	if last.Event != "summary" || last.Status != "ok" || last.DurationMs == nil {
+		t.Fatalf("expected ok summary last, got %+v", last)
+	}
+	starts, dones, stacks := 0, 0, 0
+	for _, e := range events {
+		switch e.Event {
+		case "stack_result":
+			stacks++
+			if e.Name == "" || e.Context == "" || e.Status != "ok" || e.DurationMs == nil {
+				t.Fatalf("stack_result missing fields: %+v", e)
+			}
+		case "resource_start":
+			starts++
+		case "resource_done":
+			dones++
+			if e.Action == "" || e.Status == "" || e.DurationMs == nil || e.Kind == "process" {
+				t.Fatalf("resource_done missing fields: %+v", e)
+			}
+		}
+	}
+	if starts == 0 || starts != dones {
+		t.Fatalf("expected matching resource_start/resource_done events, got %d/%d", starts, dones)
+	}
+	if stacks == 0 {
+		t.Fatalf("expected a stack_result event per applied stack, got %+v", events)
+	}
+	if strings.Contains(errOut.String(), "Done.") {
+		t.Fatalf("expected human output suppressed, stderr: %s", errOut.String())
+	}
+}
+
+func TestApply_OutputEvents_RequiresSkipConfirmation(t *testing.T) {
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"apply", "--manifest", clitest.BasicConfigPath(t), "--output-events"})
+	if err := root.Execute(); err == nil || !apperr.IsKind(err, apperr.InvalidInput) {
+		t.Fatalf("expected invalid input error, got: %v", err)
+	}
+}
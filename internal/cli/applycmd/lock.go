@@ -0,0 +1,98 @@
+package applycmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/ui"
+)
+
+// lockPollInterval is how often a waiting apply checks the lock again and
+// reports that it is still waiting.
+var lockPollInterval = 5 * time.Second
+
+// lockHolder is the content of the lock file, identifying the run holding it.
+type lockHolder struct {
+	Holder string    `json:"holder"`
+	Since  time.Time `json:"since"`
+}
+
+// applyLock is an advisory lock file next to the manifest that keeps two
+// applies of the same manifest from running at the same time.
+type applyLock struct {
+	path string
+}
+
+// lockPath returns the lock file used for the manifest in baseDir.
+func lockPath(baseDir string) string {
+	return filepath.Join(baseDir, ".dockform", "apply.lock")
+}
+
+// acquireApplyLock takes the apply lock for the manifest in baseDir. When
+// another run holds it, it waits up to timeout for the lock to be released,
+// reporting the holder every lockPollInterval; a zero timeout fails at once.
+func acquireApplyLock(ctx context.Context, baseDir string, timeout time.Duration, pr ui.Printer) (*applyLock, error) {
+	path := lockPath(baseDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, apperr.Wrap("cli.apply.lock", apperr.Internal, err, "create lock directory")
+	}
+	host, _ := os.Hostname()
+	self, err := json.Marshal(lockHolder{Holder: fmt.Sprintf("%s pid %d", host, os.Getpid()), Since: time.Now().UTC()})
+	if err != nil {
+		return nil, apperr.Wrap("cli.apply.lock", apperr.Internal, err, "encode lock holder")
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err == nil {
+			_, werr := f.Write(self)
+			cerr := f.Close()
+			if werr = errors.Join(werr, cerr); werr != nil {
+				_ = os.Remove(path)
+				return nil, apperr.Wrap("cli.apply.lock", apperr.Internal, werr, "write lock file %s", path)
+			}
+			return &applyLock{path: path}, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, apperr.Wrap("cli.apply.lock", apperr.Internal, err, "create lock file %s", path)
+		}
+
+		holder := readLockHolder(path)
+		if !time.Now().Before(deadline) {
+			return nil, apperr.New("cli.apply.lock", apperr.Conflict, "apply lock is held by %s; remove %s if no other apply is running", holder, path)
+		}
+		pr.Warn("waiting for lock held by %s", holder)
+		wait := min(lockPollInterval, time.Until(deadline))
+		select {
+		case <-ctx.Done():
+			return nil, apperr.Wrap("cli.apply.lock", apperr.Timeout, ctx.Err(), "waiting for apply lock")
+		case <-time.After(wait):
+		}
+	}
+}
+
+// readLockHolder describes the run holding the lock file at path.
+func readLockHolder(path string) string {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "another run"
+	}
+	var h lockHolder
+	if json.Unmarshal(b, &h) != nil || h.Holder == "" {
+		return "another run"
+	}
+	return fmt.Sprintf("%s since %s", h.Holder, h.Since.Format(time.RFC3339))
+}
+
+// release removes the lock file.
+func (l *applyLock) release() {
+	_ = os.Remove(l.path)
+}
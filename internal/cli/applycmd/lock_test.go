@@ -0,0 +1,79 @@
+package applycmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/ui"
+)
+
+func TestAcquireApplyLock_FailsFastWhenHeld(t *testing.T) {
+	dir := t.TempDir()
+	var errOut bytes.Buffer
+	pr := ui.StdPrinter{Out: &bytes.Buffer{}, Err: &errOut}
+
+	held, err := acquireApplyLock(context.Background(), dir, 0, pr)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	defer held.release()
+
+	_, err = acquireApplyLock(context.Background(), dir, 0, pr)
+	if !apperr.IsKind(err, apperr.Conflict) || !strings.Contains(err.Error(), "pid ") {
+		t.Fatalf("expected Conflict naming the holder, got %v", err)
+	}
+	if errOut.Len() != 0 {
+		t.Fatalf("expected no waiting messages without a timeout, got %q", errOut.String())
+	}
+}
+
+func TestAcquireApplyLock_WaitsForRelease(t *testing.T) {
+	orig := lockPollInterval
+	lockPollInterval = 5 * time.Millisecond
+	defer func() { lockPollInterval = orig }()
+
+	dir := t.TempDir()
+	var errOut bytes.Buffer
+	pr := ui.StdPrinter{Out: &bytes.Buffer{}, Err: &errOut}
+
+	held, err := acquireApplyLock(context.Background(), dir, 0, pr)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	time.AfterFunc(30*time.Millisecond, held.release)
+
+	lock, err := acquireApplyLock(context.Background(), dir, 5*time.Second, pr)
+	if err != nil {
+		t.Fatalf("expected lock after release, got %v", err)
+	}
+	lock.release()
+	if !strings.Contains(errOut.String(), "waiting for lock held by") {
+		t.Fatalf("expected waiting message, got %q", errOut.String())
+	}
+	if _, err := os.Stat(lockPath(dir)); !os.IsNotExist(err) {
+		t.Fatalf("expected lock file removed, stat err: %v", err)
+	}
+}
+
+func TestAcquireApplyLock_TimesOut(t *testing.T) {
+	orig := lockPollInterval
+	lockPollInterval = 5 * time.Millisecond
+	defer func() { lockPollInterval = orig }()
+
+	dir := t.TempDir()
+	pr := ui.StdPrinter{Out: &bytes.Buffer{}, Err: &bytes.Buffer{}}
+	held, err := acquireApplyLock(context.Background(), dir, 0, pr)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	defer held.release()
+
+	if _, err := acquireApplyLock(context.Background(), dir, 20*time.Millisecond, pr); !apperr.IsKind(err, apperr.Conflict) {
+		t.Fatalf("expected Conflict after timeout, got %v", err)
+	}
+}
@@ -24,6 +24,8 @@ case "$cmd" in
   network)
     sub="$1"; shift
     if [ "$sub" = "ls" ]; then exit 0; fi ;;
+  run)
+    echo "empty"; exit 0 ;;
   compose)
     for a in "$@"; do [ "$a" = "--services" ] && { echo "nginx"; exit 0; }; done
     prev=""
@@ -2,9 +2,15 @@ package applycmd
 
 import (
 	"context"
+	"io"
+	"strings"
+	"time"
 
+	"github.com/gcstr/dockform/internal/apperr"
 	"github.com/gcstr/dockform/internal/cli/common"
+	"github.com/gcstr/dockform/internal/cli/volumecmd"
 	"github.com/gcstr/dockform/internal/planner"
+	"github.com/gcstr/dockform/internal/ui"
 	"github.com/spf13/cobra"
 )
 
@@ -13,105 +19,472 @@ func New() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "apply",
 		Short: "Apply the desired state",
+		Long: `Apply the desired state.
+
+With --output-events, human-readable output is replaced by newline-delimited
+JSON events on stdout, one object per line, flushed as they happen:
+
+  plan_built      create, update, delete, duration_ms
+  resource_start  action, kind, name, context
+  resource_done   action, kind, name, context, status (ok|failed), changed,
+                  duration_ms, error
+  stack_result    name, context, status (ok|failed), created, updated,
+                  skipped, restarted, duration_ms, error
+  warning         message
+  summary         status (ok|failed|no_changes), create, update, delete,
+                  duration_ms, error
+
+Every event has "event" (its type) and "time" (RFC 3339). Fields that do not
+apply are omitted. --output-events requires --skip-confirmation.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			skipConfirm, _ := cmd.Flags().GetBool("skip-confirmation")
-
-			// Setup CLI context with all standard initialization
-			ctx, err := common.SetupCLIContext(cmd)
-			if err != nil {
-				return err
+			summaryOnly, _ := cmd.Flags().GetBool("summary-only")
+			if long, _ := cmd.Flags().GetBool("long"); long && summaryOnly {
+				return apperr.New("cli.apply", apperr.InvalidInput, "--summary-only and --long cannot be combined")
+			}
+			if diff, _ := cmd.Flags().GetBool("diff"); diff {
+				if summaryOnly {
+					return apperr.New("cli.apply", apperr.InvalidInput, "--diff and --summary-only cannot be combined")
+				}
+				if outputEvents, _ := cmd.Flags().GetBool("output-events"); outputEvents {
+					return apperr.New("cli.apply", apperr.InvalidInput, "--diff and --output-events cannot be combined")
+				}
 			}
 
-			// Configure sequential processing if requested (default is parallel)
-			sequential, _ := cmd.Flags().GetBool("sequential")
-			if sequential {
-				ctx.Planner = ctx.Planner.WithParallel(false)
+			// --output-events replaces all human-readable output with an NDJSON
+			// event stream on stdout; there is nobody to answer a prompt.
+			var events *eventStream
+			if outputEvents, _ := cmd.Flags().GetBool("output-events"); outputEvents {
+				if dryRun, _ := cmd.Flags().GetBool("dry-run"); !skipConfirm && !dryRun {
+					return apperr.New("cli.apply", apperr.InvalidInput, "--output-events requires --skip-confirmation")
+				}
+				events = newEventStream(cmd.OutOrStdout())
+				cmd.SetOut(io.Discard)
+				cmd.SetErr(events.warningWriter())
 			}
+			err := runApply(cmd, skipConfirm, summaryOnly, events)
+			if events != nil {
+				create, update, del := events.planned()
+				events.summary(create, update, del, err)
+			}
+			return err
+		},
+	}
+	cmd.Flags().Bool("skip-confirmation", false, "Skip confirmation prompt and apply immediately")
+	cmd.Flags().String("expect-identifier", "", "Fail before planning unless the resolved identifier equals this value")
+	cmd.Flags().Bool("output-events", false, "Stream newline-delimited JSON events on stdout instead of human-readable output (see help for the schema)")
+	cmd.Flags().Duration("lock-timeout", 0, "Wait up to this long for a concurrent apply of the same manifest to release its lock; 0 fails immediately")
+	cmd.Flags().Bool("skip-validate", false, "Skip manifest and environment validation before planning (faster, but errors surface later)")
+	cmd.Flags().Bool("sequential", false, "Use sequential processing instead of the default parallel processing (slower but uses less CPU and Docker daemon resources)")
+	cmd.Flags().Int("parallel-filesets", planner.DefaultFilesetParallelism, "Maximum number of filesets indexed and synced concurrently; filesets sharing a volume, and cold-mode filesets, are synced one at a time")
+	cmd.Flags().Bool("long", false, "Show the full plan including unchanged resources")
+	cmd.Flags().Bool("diff", false, "Print each resource's planned change immediately before applying it; with --skip-confirmation the up-front plan is not printed")
+	cmd.Flags().Bool("summary-only", false, "Show only plan and result counters instead of the per-resource plan and service results")
+	cmd.Flags().Bool("dry-run", false, "Run the full apply path without making changes and print the operations that would be executed")
+	cmd.Flags().StringSlice("wait-for", nil, "Wait until a service is healthy before applying, as context/stack/service (repeatable)")
+	cmd.Flags().Duration("wait-timeout", 5*time.Minute, "Maximum time to wait for each --wait-for service")
+	cmd.Flags().Bool("backup-volumes-before", false, "Snapshot every managed volume before making changes; the apply is aborted if a backup fails")
+	cmd.Flags().String("backup-dir", "", "Directory for --backup-volumes-before snapshots (defaults to ./.dockform/backups next to manifest)")
+	cmd.Flags().Bool("only-filesets", false, "Only ensure volumes and sync filesets (then restart their services), skipping all stack and network changes")
+	cmd.Flags().Bool("no-restart", false, "Do not restart (hot) or stop/start (cold) services when their filesets change")
+	cmd.Flags().Bool("recreate-changed-filesets-services", false, "Restart (hot) or stop/start (cold) the services mounting a changed fileset's volume even when the fileset has no restart_services")
+	cmd.Flags().Bool("recreate-networks", false, "Recreate managed networks whose driver, options or IPAM differ from the manifest, disconnecting and reconnecting their containers")
+	cmd.Flags().Bool("recreate-stopped", false, "Recreate managed containers that exist but are not running instead of starting them again")
+	cmd.Flags().Bool("strict-ownership", false, "Fail the apply when a fileset's ownership or permissions cannot be applied to every path")
+	cmd.Flags().Bool("recreate-if-image-updated", false, "Recreate services whose image tag (e.g. :latest) now resolves to a different local image than their container runs, such as after a rebuild or docker pull")
+	cmd.Flags().Bool("relabel-all", false, "Reapply the current dockform and stack labels to every managed container; Docker cannot change labels in place, so this recreates the containers of up-to-date services too")
+	cmd.Flags().Bool("health-rollback", false, "Roll recreated services back to their previous image when they do not become healthy within --health-timeout")
+	cmd.Flags().Duration("health-timeout", 2*time.Minute, "Maximum time to wait for recreated services to become healthy with --health-rollback")
+	cmd.Flags().Bool("allow-data-loss", false, "Allow the apply to delete managed volumes that are not empty")
+	cmd.Flags().Bool("prune-after-each-stack", false, "Remove each stack's orphaned containers right after that stack converges instead of at the end of the apply")
+	cmd.Flags().Bool("strict-prune", false, "Fail apply when prune operations encounter errors")
+	cmd.Flags().Bool("verbose-prune-errors", false, "Print detailed prune error details when not using --strict-prune")
+	cmd.Flags().StringSlice("prune-filter", nil, "Only prune these resource kinds: containers, networks, volumes (comma-separated; default all)")
+	common.AddTargetFlags(cmd)
+	return cmd
+}
 
-			// Build the plan with rolling logs (or direct when verbose). The rolling
-			// log shows BuildPlan progress only — we deliberately do not hand it the
-			// plan as its final report, because the TUI renders inline and clips a
-			// tall plan to the terminal height, hiding creates/destroys before the
-			// confirm prompt (dockform-ltv). The full plan is printed below instead.
-			var builtPlan *planner.Plan
-			verbose, _ := cmd.Flags().GetBool("verbose")
-			_, _, err = common.RunWithRollingOrDirect(cmd, verbose, func(runCtx context.Context) (string, error) {
-				return "", ctx.WithRunContext(runCtx, func() error {
-					plan, err := ctx.BuildPlan()
-					if err != nil {
-						return err
-					}
-					builtPlan = plan
-					return nil
-				})
-			})
+// runApply plans and applies for the apply command. When events is non-nil,
+// progress is reported on it and the plan review is skipped.
+func runApply(cmd *cobra.Command, skipConfirm, summaryOnly bool, events *eventStream) error {
+	// Setup CLI context with all standard initialization; it also enforces
+	// --expect-identifier before any daemon is contacted.
+	ctx, err := common.SetupCLIContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Only one apply of a manifest runs at a time; --lock-timeout waits for
+	// a concurrent run to finish instead of failing right away. Dry runs
+	// change nothing and do not take the lock.
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); !dryRun {
+		lockTimeout, _ := cmd.Flags().GetDuration("lock-timeout")
+		if lockTimeout < 0 {
+			return apperr.New("cli.apply", apperr.InvalidInput, "--lock-timeout must not be negative")
+		}
+		lock, err := acquireApplyLock(ctx.Ctx, ctx.Config.BaseDir, lockTimeout, ctx.Printer)
+		if err != nil {
+			return err
+		}
+		defer lock.release()
+	}
+
+	// The planner reports its progress and warnings to the event stream;
+	// its own printer would only repeat the warnings as text.
+	if events != nil {
+		ctx.Planner = ctx.Planner.WithPrinter(ui.NoopPrinter{}).WithEvents(events)
+	}
+
+	waitFor, _ := cmd.Flags().GetStringSlice("wait-for")
+	waitTargets, err := parseWaitTargets(waitFor, ctx.Config)
+	if err != nil {
+		return err
+	}
+
+	// Configure sequential processing if requested (default is parallel)
+	sequential, _ := cmd.Flags().GetBool("sequential")
+	if sequential {
+		ctx.Planner = ctx.Planner.WithParallel(false)
+	}
+
+	// --parallel-filesets bounds how many filesets are indexed at once.
+	parallelFilesets, _ := cmd.Flags().GetInt("parallel-filesets")
+	if parallelFilesets < 1 {
+		return apperr.New("cli.apply", apperr.InvalidInput, "--parallel-filesets must be at least 1")
+	}
+	ctx.Planner = ctx.Planner.WithFilesetParallelism(parallelFilesets)
+
+	// --only-filesets plans and applies just volumes and filesets,
+	// skipping compose work for stacks (and therefore prune, whose
+	// orphan detection needs the full picture).
+	onlyFilesets, _ := cmd.Flags().GetBool("only-filesets")
+	noRestart, _ := cmd.Flags().GetBool("no-restart")
+	ctx.Planner = ctx.Planner.WithOnlyFilesets(onlyFilesets).WithNoRestart(noRestart)
+
+	// Filesets without restart_services only update files on disk unless
+	// --recreate-changed-filesets-services restarts the services mounting
+	// their target volume.
+	restartMounting, _ := cmd.Flags().GetBool("recreate-changed-filesets-services")
+	ctx.Planner = ctx.Planner.WithRestartMountingServices(restartMounting)
+
+	// Networks that drifted from their spec are only reported unless
+	// --recreate-networks opts into replacing them.
+	recreateNetworks, _ := cmd.Flags().GetBool("recreate-networks")
+	ctx.Planner = ctx.Planner.WithRecreateNetworks(recreateNetworks)
+
+	// Stopped managed containers are started again; --recreate-stopped
+	// replaces them with fresh ones instead.
+	recreateStopped, _ := cmd.Flags().GetBool("recreate-stopped")
+	ctx.Planner = ctx.Planner.WithRecreateStopped(recreateStopped)
+
+	// A rebuilt or re-pulled mutable tag leaves the config hash unchanged;
+	// --recreate-if-image-updated compares image IDs to catch it.
+	recreateIfImageUpdated, _ := cmd.Flags().GetBool("recreate-if-image-updated")
+	ctx.Planner = ctx.Planner.WithRecreateIfImageUpdated(recreateIfImageUpdated)
+
+	// Labels travel with the compose project, so only changed services get
+	// new ones; --relabel-all recreates the up-to-date services as well.
+	relabelAll, _ := cmd.Flags().GetBool("relabel-all")
+	ctx.Planner = ctx.Planner.WithRelabelAll(relabelAll)
+
+	// Ownership that cannot be applied to some fileset paths is reported
+	// after the apply; --strict-ownership fails the apply instead.
+	strictOwnership, _ := cmd.Flags().GetBool("strict-ownership")
+	ctx.Planner = ctx.Planner.WithStrictOwnership(strictOwnership)
+
+	// --prune-filter restricts prune (and the removals in the plan) to the
+	// listed resource kinds.
+	pruneFilterFlags, _ := cmd.Flags().GetStringSlice("prune-filter")
+	pruneFilter, err := planner.ParsePruneFilter(pruneFilterFlags)
+	if err != nil {
+		return err
+	}
+	ctx.Planner = ctx.Planner.WithPruneFilter(pruneFilter)
+
+	// --health-rollback waits for recreated services to become healthy and
+	// puts unhealthy ones back on the image they ran before.
+	if healthRollback, _ := cmd.Flags().GetBool("health-rollback"); healthRollback {
+		healthTimeout, _ := cmd.Flags().GetDuration("health-timeout")
+		if healthTimeout <= 0 {
+			return apperr.New("cli.apply", apperr.InvalidInput, "--health-timeout must be positive")
+		}
+		ctx.Planner = ctx.Planner.WithHealthRollback(healthTimeout)
+	}
+
+	// Build the plan with rolling logs (or direct when verbose). The rolling
+	// log shows BuildPlan progress only — we deliberately do not hand it the
+	// plan as its final report, because the TUI renders inline and clips a
+	// tall plan to the terminal height, hiding creates/destroys before the
+	// confirm prompt (dockform-ltv). The full plan is printed below instead.
+	var builtPlan *planner.Plan
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	if events != nil {
+		verbose = true // the rolling-log TUI is human output too
+	}
+	planStarted := time.Now()
+	_, _, err = common.RunWithRollingOrDirect(cmd, verbose, func(runCtx context.Context) (string, error) {
+		return "", ctx.WithRunContext(runCtx, func() error {
+			plan, err := ctx.BuildPlan()
 			if err != nil {
 				return err
 			}
-			long, _ := cmd.Flags().GetBool("long")
-
-			// If the plan has no create/update/delete actions, inform and exit early
-			// (before the review render, so we don't print both "No changes…" and this).
-			if builtPlan != nil && builtPlan.Resources != nil {
-				createCount, updateCount, deleteCount := builtPlan.Resources.CountActions()
-				if createCount == 0 && updateCount == 0 && deleteCount == 0 {
-					ctx.Printer.Plain("Nothing to apply. Exiting.")
-					return nil
-				}
+			builtPlan = plan
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+	if events != nil && builtPlan != nil {
+		events.planBuilt(builtPlan, time.Since(planStarted))
+	}
+	long, _ := cmd.Flags().GetBool("long")
+
+	// If the plan has no create/update/delete actions, inform and exit early
+	// (before the review render, so we don't print both "No changes…" and this).
+	if builtPlan != nil && builtPlan.Resources != nil {
+		createCount, updateCount, deleteCount := builtPlan.Resources.CountActions()
+		if createCount == 0 && updateCount == 0 && deleteCount == 0 {
+			for _, w := range builtPlan.Resources.Warnings {
+				ctx.Printer.Warn("%s", w)
 			}
+			ctx.Printer.Plain("Nothing to apply. Exiting.")
+			return nil
+		}
+	}
 
-			// Print the plan for review. Goes through the normal printer so it
-			// scrolls naturally instead of being clipped by the rolling-log TUI.
-			// --long shows all resources including no-ops; default is changes-only.
-			if builtPlan != nil {
-				ctx.Printer.Plain("%s", builtPlan.Render(planner.PlanRenderOptions{Full: long}))
+	// Print the plan for review. Goes through the normal printer so it
+	// scrolls naturally instead of being clipped by the rolling-log TUI.
+	// --long shows all resources including no-ops; default is changes-only.
+	// --summary-only replaces the preview with its counters, so the
+	// confirmation below still states what is about to happen. With --diff
+	// and nothing to confirm, the changes are only printed as they apply.
+	inlineDiff, _ := cmd.Flags().GetBool("diff")
+	if builtPlan != nil && !(inlineDiff && skipConfirm) {
+		if summaryOnly && builtPlan.Resources != nil {
+			for _, w := range builtPlan.Resources.Warnings {
+				ctx.Printer.Warn("%s", w)
 			}
+			createCount, updateCount, deleteCount := builtPlan.Resources.CountActions()
+			ctx.Printer.Plain("%s", strings.TrimRight(ui.FormatPlanSummary(createCount, updateCount, deleteCount), "\n"))
+		} else {
+			ctx.Printer.Plain("%s", builtPlan.Render(planner.PlanRenderOptions{Full: long}))
+		}
+	}
 
-			// Get confirmation from user
-			confirmed, err := common.GetConfirmation(cmd, ctx.Printer, common.ConfirmationOptions{
-				SkipConfirmation: skipConfirm,
-				Message:          "",
-			})
+	// Dry run: execute the full apply path with mutating docker calls
+	// intercepted, then report what would have been executed.
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	var recorder *planner.DryRunRecorder
+	if dryRun {
+		recorder = planner.NewDryRunRecorder()
+		ctx.Planner = ctx.Planner.WithDryRun(recorder)
+	}
+
+	// Deleting volumes that still hold data needs an explicit opt-in; the
+	// plan above already flags them as DESTRUCTIVE.
+	allowDataLoss, _ := cmd.Flags().GetBool("allow-data-loss")
+	ctx.Planner = ctx.Planner.WithAllowDataLoss(allowDataLoss)
+	if builtPlan != nil && builtPlan.Resources != nil && len(builtPlan.Resources.DataLoss) > 0 && !allowDataLoss && !dryRun {
+		return apperr.New("cli.apply", apperr.Precondition, "plan deletes %d non-empty volume(s): %s; re-run with --allow-data-loss to proceed", len(builtPlan.Resources.DataLoss), strings.Join(builtPlan.Resources.DataLoss, ", "))
+	}
+
+	// Get confirmation from user
+	summary := ""
+	if builtPlan != nil && builtPlan.Resources != nil {
+		summary = builtPlan.Resources.Counts().String()
+	}
+	confirmed, err := common.GetConfirmation(cmd, ctx.Printer, common.ConfirmationOptions{
+		SkipConfirmation: skipConfirm || dryRun,
+		Message:          "",
+		Summary:          summary,
+	})
+	if err != nil {
+		return err
+	}
+
+	if !confirmed {
+		return nil
+	}
+
+	// Block on cross-stack dependencies before touching anything.
+	if len(waitTargets) > 0 {
+		waitTimeout, _ := cmd.Flags().GetDuration("wait-timeout")
+		if err := waitForTargets(ctx, waitTargets, waitTimeout); err != nil {
+			return err
+		}
+	}
+
+	// Snapshot managed volumes so a bad apply can be rolled back. A dry
+	// run mutates nothing, so there is nothing to protect.
+	backup, _ := cmd.Flags().GetBool("backup-volumes-before")
+	if backup && !dryRun {
+		backupDir, _ := cmd.Flags().GetString("backup-dir")
+		if strings.TrimSpace(backupDir) == "" {
+			backupDir = volumecmd.DefaultBackupDir(ctx.Config.BaseDir)
+		}
+		if _, err := volumecmd.BackupVolumes(ctx.Ctx, ctx, backupDir); err != nil {
+			return err
+		}
+	}
+
+	// Apply + Prune with rolling logs (or direct when verbose). --diff
+	// prints between operations, which the rolling-log TUI would swallow.
+	ctx.Planner = ctx.Planner.WithInlineDiff(inlineDiff)
+	strictPrune, _ := cmd.Flags().GetBool("strict-prune")
+	verbosePruneErrors, _ := cmd.Flags().GetBool("verbose-prune-errors")
+	// Orphaned containers are removed in the prune after every stack has
+	// converged; --prune-after-each-stack frees them as each stack finishes.
+	if pruneEachStack, _ := cmd.Flags().GetBool("prune-after-each-stack"); pruneEachStack {
+		ctx.Planner = ctx.Planner.WithPruneEachStack(planner.CleanupOptions{
+			Strict:        strictPrune,
+			VerboseErrors: verbosePruneErrors,
+		})
+	}
+	var stackResults []planner.StackApplyResult
+	_, _, err = common.RunWithRollingOrDirect(cmd, verbose || inlineDiff, func(runCtx context.Context) (string, error) {
+		err := ctx.WithRunContext(runCtx, func() error {
+			// Pass the pre-built plan to avoid redundant state detection
+			results, err := ctx.ApplyPlanWithContext(builtPlan)
+			stackResults = results
 			if err != nil {
 				return err
 			}
-
-			if !confirmed {
+			if onlyFilesets {
 				return nil
 			}
-
-			// Apply + Prune with rolling logs (or direct when verbose)
-			strictPrune, _ := cmd.Flags().GetBool("strict-prune")
-			verbosePruneErrors, _ := cmd.Flags().GetBool("verbose-prune-errors")
-			_, _, err = common.RunWithRollingOrDirect(cmd, verbose, func(runCtx context.Context) (string, error) {
-				err := ctx.WithRunContext(runCtx, func() error {
-					// Pass the pre-built plan to avoid redundant state detection
-					if err := ctx.ApplyPlanWithContext(builtPlan); err != nil {
-						return err
-					}
-					// Also pass the plan to prune to reuse execution context
-					return ctx.PrunePlanWithOptions(builtPlan, planner.CleanupOptions{
-						Strict:        strictPrune,
-						VerboseErrors: verbosePruneErrors,
-					})
-				})
-				if err != nil {
-					return "", err
-				}
-				return "│ Done.", nil
+			// Also pass the plan to prune to reuse execution context
+			return ctx.PrunePlanWithOptions(builtPlan, planner.CleanupOptions{
+				Strict:        strictPrune,
+				VerboseErrors: verbosePruneErrors,
 			})
-			if err != nil {
-				return err
-			}
+		})
+		if err != nil {
+			return "", err
+		}
+		if recorder != nil {
+			return "", nil
+		}
+		return "│ Done.", nil
+	})
+	if recorder != nil {
+		if err != nil {
+			return err
+		}
+		printDryRunOps(ctx, recorder.Ops())
+		return nil
+	}
+	if events != nil {
+		events.stackResults(stackResults)
+	}
+	if summaryOnly {
+		printApplySummary(ctx, builtPlan, ctx.Planner.ServiceResults(), err)
+	} else {
+		printServiceResults(ctx, ctx.Planner.ServiceResults())
+		printStackResults(ctx, stackResults)
+	}
+	if n := ctx.Planner.RelabeledContainers(); n > 0 || relabelAll {
+		ctx.Printer.Plain("│ Relabeled %d %s", n, pluralContainers(n))
+	}
+	if failures := ctx.Planner.OwnershipFailures(); len(failures) > 0 && !strictOwnership {
+		ctx.Printer.Warn("%s", planner.DescribeOwnershipFailures(failures))
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
 
-			return nil
-		},
+// printDryRunOps prints the docker operations a dry run intercepted.
+func printDryRunOps(ctx *common.CLIContext, ops []planner.DryRunOp) {
+	if len(ops) == 0 {
+		ctx.Printer.Plain("│ Dry run: no docker operations would be executed.")
+		return
+	}
+	ctx.Printer.Plain("│ Dry run: no changes were made. Dockform would execute:")
+	for _, op := range ops {
+		ctx.Printer.Plain("│   %s", op.String())
+	}
+}
+
+// printApplySummary prints the counters of an apply run with --summary-only.
+// Failed services are still named, since they need attention. The resource
+// counters are only printed for a successful apply; a failed one reports its
+// error instead.
+func printApplySummary(ctx *common.CLIContext, plan *planner.Plan, results []planner.ServiceApplyResult, applyErr error) {
+	if applyErr == nil && plan != nil && plan.Resources != nil {
+		createCount, updateCount, deleteCount := plan.Resources.CountActions()
+		ctx.Printer.Plain("│ Applied: %d created, %d changed, %d destroyed", createCount, updateCount, deleteCount)
+	}
+	if len(results) == 0 {
+		return
+	}
+	var failed []planner.ServiceApplyResult
+	for _, r := range results {
+		if r.Failed() {
+			failed = append(failed, r)
+		}
+	}
+	ctx.Printer.Plain("│ Services: %d ok, %d failed", len(results)-len(failed), len(failed))
+	for _, r := range failed {
+		ctx.Printer.Plain("│   %s: %s", r.StackKey(), ui.RedText(r.String()))
+	}
+}
+
+// printStackResults prints what apply did to each stack it reached and how
+// long the stack took.
+func printStackResults(ctx *common.CLIContext, results []planner.StackApplyResult) {
+	if len(results) == 0 {
+		return
+	}
+	ctx.Printer.Plain("│ Stacks:")
+	for _, r := range results {
+		took := r.Duration.Round(100 * time.Millisecond)
+		if r.Err != nil {
+			ctx.Printer.Plain("│   %s: %s (%s)", r.StackKey(), ui.RedText("failed"), took)
+			continue
+		}
+		ctx.Printer.Plain("│   %s: %s (%s)", r.StackKey(), describeStackResult(r), took)
+	}
+}
+
+// describeStackResult names the services apply created, updated and restarted
+// in a stack, or reports it up to date.
+func describeStackResult(r planner.StackApplyResult) string {
+	var parts []string
+	for _, g := range []struct {
+		verb     string
+		services []string
+	}{{"created", r.Created}, {"updated", r.Updated}, {"restarted", r.Restarted}} {
+		if len(g.services) > 0 {
+			parts = append(parts, g.verb+" "+strings.Join(g.services, ", "))
+		}
+	}
+	if len(parts) == 0 {
+		return "up to date"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// pluralContainers returns "container" or "containers" for n.
+func pluralContainers(n int) string {
+	if n == 1 {
+		return "container"
+	}
+	return "containers"
+}
+
+// printServiceResults prints the observed state of every service compose up touched.
+func printServiceResults(ctx *common.CLIContext, results []planner.ServiceApplyResult) {
+	if len(results) == 0 {
+		return
+	}
+	ctx.Printer.Plain("│ Services:")
+	for _, r := range results {
+		if r.Failed() {
+			ctx.Printer.Plain("│   %s: %s", r.StackKey(), ui.RedText(r.String()))
+		} else {
+			ctx.Printer.Plain("│   %s: %s", r.StackKey(), r.String())
+		}
 	}
-	cmd.Flags().Bool("skip-confirmation", false, "Skip confirmation prompt and apply immediately")
-	cmd.Flags().Bool("sequential", false, "Use sequential processing instead of the default parallel processing (slower but uses less CPU and Docker daemon resources)")
-	cmd.Flags().Bool("long", false, "Show the full plan including unchanged resources")
-	cmd.Flags().Bool("strict-prune", false, "Fail apply when prune operations encounter errors")
-	cmd.Flags().Bool("verbose-prune-errors", false, "Print detailed prune error details when not using --strict-prune")
-	common.AddTargetFlags(cmd)
-	return cmd
 }
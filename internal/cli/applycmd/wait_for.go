@@ -0,0 +1,122 @@
+package applycmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/cli/common"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+	"github.com/gcstr/dockform/internal/ui"
+)
+
+// waitForPollInterval is a var so tests can shorten it.
+var waitForPollInterval = 2 * time.Second
+
+// waitTarget is a service that must be healthy before apply proceeds.
+type waitTarget struct {
+	Context string
+	Stack   string
+	Service string
+	Project string          // compose project name of the stack
+	Spec    *manifest.Stack // the stack's manifest entry, when the manifest declares it
+}
+
+func (t waitTarget) String() string { return t.Context + "/" + t.Stack + "/" + t.Service }
+
+// statusReader is the subset of the docker client used for readiness polling.
+type statusReader interface {
+	ComposeServiceStatuses(ctx context.Context, workingDir string, files, profiles, envFiles []string, projectName string, services []string, inlineEnv []string) ([]dockercli.ServiceStatus, error)
+	LastHealthCheck(ctx context.Context, name string) (string, error)
+}
+
+// parseWaitTargets parses --wait-for values of the form context/stack/service.
+// The stack does not need to be part of the apply; its project name is taken
+// from the manifest when known and defaults to the stack name.
+func parseWaitTargets(values []string, cfg *manifest.Config) ([]waitTarget, error) {
+	stacks := cfg.GetAllStacks()
+	var out []waitTarget
+	for _, v := range values {
+		parts := strings.Split(strings.TrimSpace(v), "/")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return nil, apperr.New("cli.apply", apperr.InvalidInput, "invalid --wait-for %q: expected context/stack/service", v)
+		}
+		if _, ok := cfg.Contexts[parts[0]]; !ok {
+			return nil, apperr.New("cli.apply", apperr.InvalidInput, "invalid --wait-for %q: unknown context %s", v, parts[0])
+		}
+		t := waitTarget{Context: parts[0], Stack: parts[1], Service: parts[2], Project: parts[1]}
+		if st, ok := stacks[manifest.MakeStackKey(t.Context, t.Stack)]; ok {
+			t.Spec = &st
+			if st.Project != nil && st.Project.Name != "" {
+				t.Project = st.Project.Name
+			}
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// serviceStatus reads the status of t's service. A stack declared in the
+// manifest is inspected through its compose files; any other stack through
+// its project name alone.
+func serviceStatus(ctx context.Context, docker statusReader, t waitTarget) (dockercli.ServiceStatus, error) {
+	var statuses []dockercli.ServiceStatus
+	var err error
+	if st := t.Spec; st != nil {
+		statuses, err = docker.ComposeServiceStatuses(dockercli.WithStack(ctx, *st), st.Root, st.Files, st.Profiles, st.EnvFile, t.Project, []string{t.Service}, st.EnvInline)
+	} else {
+		statuses, err = docker.ComposeServiceStatuses(ctx, "", nil, nil, nil, t.Project, []string{t.Service}, nil)
+	}
+	if err != nil || len(statuses) == 0 {
+		return dockercli.ServiceStatus{Service: t.Service, State: "missing"}, err
+	}
+	return statuses[0], nil
+}
+
+// waitForService polls the service of t until it is ready or timeout elapses.
+// A service is ready when all its containers are running and healthy (running
+// is enough without a healthcheck), or when it ran to completion.
+func waitForService(ctx context.Context, docker statusReader, t waitTarget, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		st, err := serviceStatus(ctx, docker, t)
+		if err != nil {
+			return apperr.Wrap("cli.apply", apperr.External, err, "wait for %s", t)
+		}
+		if st.Healthy() || st.Completed() {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			if st.Health != "" && st.Container != "" {
+				// Best-effort: the status alone still names the problem.
+				if line, err := docker.LastHealthCheck(ctx, st.Container); err == nil {
+					st.HealthLog = line
+				}
+			}
+			return apperr.New("cli.apply", apperr.Timeout, "timed out after %s waiting for %s to become healthy (%s)", timeout, t, st.HealthSummary())
+		}
+		select {
+		case <-ctx.Done():
+			return apperr.Wrap("cli.apply", apperr.Timeout, ctx.Err(), "wait for %s (%s)", t, st.HealthSummary())
+		case <-time.After(waitForPollInterval):
+		}
+	}
+}
+
+// waitForTargets blocks until every target is ready, checking them in order.
+func waitForTargets(clictx *common.CLIContext, targets []waitTarget, timeout time.Duration) error {
+	stdPr := clictx.Printer.(ui.StdPrinter)
+	for _, t := range targets {
+		docker := clictx.Factory.GetClientForContext(t.Context, clictx.Config)
+		if err := common.SpinnerOperation(stdPr, fmt.Sprintf("Waiting for %s to become healthy...", t), func() error {
+			return waitForService(clictx.Ctx, docker, t, timeout)
+		}); err != nil {
+			return err
+		}
+		clictx.Printer.Plain("│ %s %s is healthy", ui.SuccessMark(), t)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package applycmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+type fakeStatuses struct {
+	responses []dockercli.ServiceStatus
+	calls     int
+	project   string
+	services  []string
+}
+
+func (f *fakeStatuses) ComposeServiceStatuses(ctx context.Context, workingDir string, files, profiles, envFiles []string, projectName string, services []string, inlineEnv []string) ([]dockercli.ServiceStatus, error) {
+	f.project, f.services = projectName, services
+	i := min(f.calls, len(f.responses)-1)
+	f.calls++
+	return []dockercli.ServiceStatus{f.responses[i]}, nil
+}
+
+func (f *fakeStatuses) LastHealthCheck(ctx context.Context, name string) (string, error) {
+	return "connection refused", nil
+}
+
+func TestWaitForService_PollsUntilHealthy(t *testing.T) {
+	orig := waitForPollInterval
+	waitForPollInterval = time.Millisecond
+	defer func() { waitForPollInterval = orig }()
+
+	docker := &fakeStatuses{responses: []dockercli.ServiceStatus{
+		{Service: "db", State: "missing"},
+		{Service: "db", Container: "db-1", State: "running", Health: "starting"},
+		{Service: "db", Container: "db-1", State: "running", Health: "healthy"},
+	}}
+	target := waitTarget{Context: "default", Stack: "infra", Service: "db", Project: "infra"}
+	if err := waitForService(context.Background(), docker, target, time.Second); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+	if docker.calls != 3 {
+		t.Fatalf("expected 3 polls, got %d", docker.calls)
+	}
+	if docker.project != "infra" || strings.Join(docker.services, ",") != "db" {
+		t.Fatalf("unexpected project/services: %s %v", docker.project, docker.services)
+	}
+}
+
+func TestWaitForService_CompletedServiceIsReady(t *testing.T) {
+	docker := &fakeStatuses{responses: []dockercli.ServiceStatus{{Service: "migrate", Container: "migrate-1", State: "exited", ExitCode: 0}}}
+	target := waitTarget{Context: "default", Stack: "infra", Service: "migrate", Project: "infra"}
+	if err := waitForService(context.Background(), docker, target, time.Second); err != nil {
+		t.Fatalf("expected a service that exited 0 to count as ready, got %v", err)
+	}
+}
+
+func TestWaitForService_TimeoutNamesTarget(t *testing.T) {
+	orig := waitForPollInterval
+	waitForPollInterval = time.Millisecond
+	defer func() { waitForPollInterval = orig }()
+
+	docker := &fakeStatuses{responses: []dockercli.ServiceStatus{
+		{Service: "db", Container: "db-1", State: "running", Health: "unhealthy"},
+	}}
+	target := waitTarget{Context: "default", Stack: "infra", Service: "db", Project: "infra"}
+	err := waitForService(context.Background(), docker, target, 10*time.Millisecond)
+	if err == nil || !apperr.IsKind(err, apperr.Timeout) {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "default/infra/db") || !strings.Contains(err.Error(), "db unhealthy; last health check: connection refused") {
+		t.Fatalf("expected error to name the unmet dependency, got %v", err)
+	}
+}
+
+func TestParseWaitTargets(t *testing.T) {
+	cfg := &manifest.Config{
+		Contexts: map[string]manifest.ContextConfig{"default": {}},
+		Stacks: map[string]manifest.Stack{
+			"default/infra": {Project: &manifest.Project{Name: "shared-infra"}},
+		},
+	}
+	targets, err := parseWaitTargets([]string{"default/infra/db", "default/cache/redis"}, cfg)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if targets[0].Project != "shared-infra" || targets[1].Project != "cache" || targets[0].Spec == nil || targets[1].Spec != nil {
+		t.Fatalf("unexpected projects: %+v", targets)
+	}
+	for _, bad := range []string{"default/infra", "other/infra/db", "default//db"} {
+		if _, err := parseWaitTargets([]string{bad}, cfg); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
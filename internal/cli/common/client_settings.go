@@ -0,0 +1,75 @@
+package common
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/spf13/cobra"
+)
+
+// ClientSettings resolves the per-run docker client settings from the global
+// flags: --timeout, --pull-timeout, --context-timeout, --retries and --trace.
+// A nil cmd yields dockercli.DefaultSettings.
+func ClientSettings(cmd *cobra.Command) (dockercli.Settings, error) {
+	s := dockercli.DefaultSettings()
+	if cmd == nil {
+		return s, nil
+	}
+
+	// --context-timeout bounds every daemon reachability probe of the run.
+	if f := cmd.Flags().Lookup("context-timeout"); f != nil && f.Changed {
+		d, _ := cmd.Flags().GetDuration("context-timeout")
+		if d <= 0 {
+			return s, apperr.New("cli.root", apperr.InvalidInput, "--context-timeout must be positive, got %s", d)
+		}
+		s.ProbeTimeout = d
+	}
+
+	// --timeout and --pull-timeout bound each docker command of the run.
+	for _, name := range []string{"timeout", "pull-timeout"} {
+		if f := cmd.Flags().Lookup(name); f != nil && f.Changed {
+			d, _ := cmd.Flags().GetDuration(name)
+			if d <= 0 {
+				return s, apperr.New("cli.root", apperr.InvalidInput, "--%s must be positive, got %s", name, d)
+			}
+			if name == "timeout" {
+				s.CommandTimeout = d
+			} else {
+				s.PullTimeout = d
+			}
+		}
+	}
+
+	// --retries sets how often docker commands are retried on transient errors.
+	if f := cmd.Flags().Lookup("retries"); f != nil && f.Changed {
+		n, _ := cmd.Flags().GetInt("retries")
+		if n < 0 {
+			return s, apperr.New("cli.root", apperr.InvalidInput, "--retries must not be negative, got %d", n)
+		}
+		s.Retries = n
+	}
+
+	// --trace prints every docker CLI invocation to stderr.
+	if traceEnabled(cmd) {
+		s.Tracer = dockercli.NewTracer(cmd.ErrOrStderr())
+	}
+	return s, nil
+}
+
+// traceEnabled resolves whether docker invocations are traced. An explicitly
+// set --trace flag wins; otherwise DOCKFORM_TRACE (when parseable) decides.
+func traceEnabled(cmd *cobra.Command) bool {
+	if f := cmd.Flags().Lookup("trace"); f != nil && f.Changed {
+		v, _ := cmd.Flags().GetBool("trace")
+		return v
+	}
+	if raw, ok := os.LookupEnv("DOCKFORM_TRACE"); ok {
+		if v, err := strconv.ParseBool(strings.TrimSpace(raw)); err == nil {
+			return v
+		}
+	}
+	return false
+}
@@ -36,9 +36,11 @@ func ReadTargetOptions(cmd *cobra.Command) TargetOptions {
 	}
 }
 
-// CreateClientFactory creates a Docker client factory for multi-context support.
-func CreateClientFactory() *dockercli.DefaultClientFactory {
-	return dockercli.NewClientFactory()
+// CreateClientFactory creates a Docker client factory for multi-context support
+// whose clients follow the run's global flags (see ClientSettings).
+func CreateClientFactory(cmd *cobra.Command) *dockercli.DefaultClientFactory {
+	s, _ := ClientSettings(cmd) // validated by the root command
+	return dockercli.NewClientFactory().WithSettings(s)
 }
 
 // ValidateWithFactory runs validation against the configuration using a client factory.
@@ -162,3 +164,23 @@ func MaskSecretsSimple(yamlStr string, stack manifest.Stack, strategy string) st
 
 	return yamlStr
 }
+
+// ContextHeader renders the header introducing one context's section of a plan
+// grouped by daemon: the context name with its identifier and endpoint, styled
+// like DisplayDaemonInfo.
+func ContextHeader(cfg *manifest.Config, contextName string) string {
+	labelStyle := lipgloss.NewStyle().Faint(true).Bold(true)
+	labelWidth := len("Identifier:")
+
+	endpoint := "docker context " + contextName
+	if host := cfg.Contexts[contextName].Host; host != "" {
+		endpoint = host
+	}
+
+	lines := []string{fmt.Sprintf("│ %s  %s", labelStyle.Render(fmt.Sprintf("%-*s", labelWidth, "Context:")), ui.Italic(contextName))}
+	if cfg.Identifier != "" {
+		lines = append(lines, fmt.Sprintf("│ %s  %s", labelStyle.Render(fmt.Sprintf("%-*s", labelWidth, "Identifier:")), ui.Italic(cfg.Identifier)))
+	}
+	lines = append(lines, fmt.Sprintf("│ %s  %s", labelStyle.Render(fmt.Sprintf("%-*s", labelWidth, "Endpoint:")), ui.Italic(endpoint)))
+	return strings.Join(lines, "\n")
+}
@@ -5,6 +5,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -81,6 +83,39 @@ func createSampleManifest(t *testing.T) (string, string) {
 	return manifestPath, root
 }
 
+func TestLoadConfigWithWarnings_RemoteManifestNeedsBaseDirForRelativeRoots(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("API_KEY", "x")
+	path, root := createSampleManifest(t)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(content)
+	}))
+	defer srv.Close()
+
+	load := func(baseDir string) (*manifest.Config, error) {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("manifest", srv.URL+"/dockform.yml", "")
+		cmd.Flags().String("base-dir", baseDir, "")
+		cmd.SetContext(context.Background())
+		return LoadConfigWithWarnings(cmd, &capturePrinter{})
+	}
+
+	if _, err := load(""); err == nil || !strings.Contains(err.Error(), "not self-contained") {
+		t.Fatalf("expected self-contained error for relative stack root, got %v", err)
+	}
+	cfg, err := load(root)
+	if err != nil {
+		t.Fatalf("load with --base-dir: %v", err)
+	}
+	if got := cfg.Stacks["default/app"].Root; got != filepath.Join(root, "stack") {
+		t.Fatalf("expected stack root resolved from --base-dir, got %s", got)
+	}
+}
+
 func TestLoadConfigWithWarningsEmitsMessages(t *testing.T) {
 	path, _ := createSampleManifest(t)
 
@@ -399,7 +434,7 @@ exit 0
 			"default": {},
 		},
 	}
-	factory := CreateClientFactory()
+	factory := CreateClientFactory(nil)
 	if factory == nil {
 		t.Fatalf("expected client factory")
 	}
@@ -409,7 +444,7 @@ exit 0
 }
 
 func TestCreatePlannerWithFactory(t *testing.T) {
-	factory := CreateClientFactory()
+	factory := CreateClientFactory(nil)
 	p := CreatePlannerWithFactory(factory, ui.StdPrinter{})
 	if p == nil {
 		t.Fatalf("expected planner")
@@ -521,7 +556,7 @@ func TestCLIContextOperations(t *testing.T) {
 	if _, err := ctx.BuildPlan(); err != nil {
 		t.Fatalf("BuildPlan failed: %v", err)
 	}
-	if err := ctx.ApplyPlan(); err == nil {
+	if _, err := ctx.ApplyPlan(); err == nil {
 		t.Fatalf("expected apply to fail without docker client")
 	}
 	if err := ctx.PrunePlan(); err == nil {
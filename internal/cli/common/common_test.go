@@ -183,6 +183,31 @@ func TestGetConfirmationNonTTYYes(t *testing.T) {
 	}
 }
 
+func TestGetConfirmation_ShowsSummaryOnlyWhenPrompting(t *testing.T) {
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetIn(strings.NewReader("yes\n"))
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	pr := ui.StdPrinter{Out: &out, Err: &out}
+	summary := "3 to create, 1 to update, 2 to remove, 5 unchanged"
+	if _, err := GetConfirmation(cmd, pr, ConfirmationOptions{Summary: summary}); err != nil {
+		t.Fatalf("confirmation error: %v", err)
+	}
+	got := out.String()
+	if i, j := strings.Index(got, summary), strings.Index(got, "Type yes to confirm"); i < 0 || j < i {
+		t.Fatalf("expected the summary above the prompt; got:\n%s", got)
+	}
+
+	out.Reset()
+	if _, err := GetConfirmation(cmd, pr, ConfirmationOptions{Summary: summary, SkipConfirmation: true}); err != nil {
+		t.Fatalf("confirmation error: %v", err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("expected no output when skipping confirmation; got:\n%s", out.String())
+	}
+}
+
 func TestGetDestroyConfirmationNonTTY(t *testing.T) {
 	cmd := &cobra.Command{}
 	var in bytes.Buffer
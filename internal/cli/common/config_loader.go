@@ -1,6 +1,7 @@
 package common
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -14,6 +15,7 @@ import (
 
 // LoadConfigWithWarnings loads the configuration from the --manifest flag and displays warnings.
 func LoadConfigWithWarnings(cmd *cobra.Command, pr ui.Printer) (*manifest.Config, error) {
+	source, _ := cmd.Flags().GetString("manifest")
 	file, err := ResolveManifestPath(cmd, pr, ".", 3)
 	if err != nil {
 		return nil, err
@@ -22,22 +24,64 @@ func LoadConfigWithWarnings(cmd *cobra.Command, pr ui.Printer) (*manifest.Config
 		_ = cmd.Flags().Set("manifest", file)
 	}
 
-	cfg, missing, err := manifest.LoadWithWarnings(file)
-	if err == nil {
-		for _, name := range missing {
-			pr.Warn("environment variable %s is not set; replacing with empty string", name)
+	baseDir, _ := cmd.Flags().GetString("base-dir")
+	cfg, missing, err := manifest.LoadWithWarningsInDir(file, baseDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range missing {
+		pr.Warn("environment variable %s is not set; replacing with empty string", name)
+	}
+	ApplyGnupgHome(cmd, &cfg)
+	if manifest.IsRemote(source) && !strings.HasPrefix(source, "git+") && baseDir == "" {
+		if err := checkSelfContained(&cfg, source); err != nil {
+			return nil, err
+		}
+	}
+	return &cfg, nil
+}
+
+// ApplyGnupgHome records the --gnupg-home flag on the manifest's sops.pgp
+// settings, so every sops run of the command uses that GnuPG home instead of
+// keyring_dir. Manifests without sops.pgp are left as they are.
+func ApplyGnupgHome(cmd *cobra.Command, cfg *manifest.Config) {
+	home, _ := cmd.Flags().GetString("gnupg-home")
+	if strings.TrimSpace(home) == "" || cfg.Sops == nil || cfg.Sops.Pgp == nil {
+		return
+	}
+	cfg.Sops.Pgp.GnupgHome = home
+}
+
+// checkSelfContained rejects a manifest fetched over HTTP that points at local
+// files relative to itself: only the manifest was downloaded, so they can only
+// resolve from a --base-dir.
+func checkSelfContained(cfg *manifest.Config, source string) error {
+	within := func(p string) bool {
+		return p != "" && strings.HasPrefix(p, cfg.BaseDir+string(filepath.Separator))
+	}
+	for key, stack := range cfg.Stacks {
+		if within(stack.Root) {
+			return apperr.New("common.LoadConfigWithWarnings", apperr.InvalidInput, "manifest from %s is not self-contained: stack %s uses the relative root %s; use absolute paths or pass --base-dir", source, key, strings.TrimPrefix(stack.Root, cfg.BaseDir+string(filepath.Separator)))
+		}
+	}
+	for key, fs := range cfg.DiscoveredFilesets {
+		if within(fs.SourceAbs) {
+			return apperr.New("common.LoadConfigWithWarnings", apperr.InvalidInput, "manifest from %s is not self-contained: fileset %s uses the relative source %s; use absolute paths or pass --base-dir", source, key, fs.Source)
 		}
-		return &cfg, nil
 	}
-	return nil, err
+	return nil
 }
 
 // ResolveManifestPath determines the manifest path to load.
-// If --manifest is set, it is returned as-is.
+// If --manifest is a URL, the manifest is fetched and the local copy returned.
+// If --manifest is set otherwise, it is returned as-is.
 // If omitted and a manifest exists in CWD defaults, returns empty string (loader defaults apply).
 // If omitted and no CWD manifest exists, it attempts discovery and interactive selection.
 func ResolveManifestPath(cmd *cobra.Command, pr ui.Printer, root string, maxDepth int) (string, error) {
 	file, _ := cmd.Flags().GetString("manifest")
+	if manifest.IsRemote(file) {
+		return fetchRemoteManifest(cmd, file)
+	}
 	if strings.TrimSpace(file) != "" {
 		return file, nil
 	}
@@ -60,6 +104,23 @@ func ResolveManifestPath(cmd *cobra.Command, pr ui.Printer, root string, maxDept
 	return selectedPath, nil
 }
 
+// fetchRemoteManifest downloads the manifest at src into the user cache.
+func fetchRemoteManifest(cmd *cobra.Command, src string) (string, error) {
+	cacheDir, err := manifest.DefaultRemoteCacheDir()
+	if err != nil {
+		return "", err
+	}
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	remote, err := manifest.FetchRemote(ctx, src, cacheDir)
+	if err != nil {
+		return "", err
+	}
+	return remote.Path, nil
+}
+
 func hasManifestInCurrentDir(dir string) (bool, error) {
 	candidates := []string{"dockform.yml", "dockform.yaml", "Dockform.yml", "Dockform.yaml"}
 	for _, name := range candidates {
@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/gcstr/dockform/internal/apperr"
 	"github.com/gcstr/dockform/internal/ui"
 	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
@@ -15,6 +16,9 @@ import (
 type ConfirmationOptions struct {
 	SkipConfirmation bool
 	Message          string
+	// Summary is a one-line tally of the changes, e.g. "3 to create, 1 to
+	// update, 2 to remove, 5 unchanged", shown right before the prompt.
+	Summary string
 }
 
 // GetConfirmation handles user confirmation with TTY detection and appropriate prompting.
@@ -27,6 +31,10 @@ func GetConfirmation(cmd *cobra.Command, pr ui.Printer, opts ConfirmationOptions
 		opts.Message = "│ Dockform will apply the changes listed above.\n│ Type yes to confirm.\n│"
 	}
 
+	if opts.Summary != "" {
+		pr.Plain("│ %s\n│", opts.Summary)
+	}
+
 	tty := detectTTY(cmd)
 
 	if tty.In && tty.Out {
@@ -130,3 +138,17 @@ func GetDestroyConfirmation(cmd *cobra.Command, pr ui.Printer, opts DestroyConfi
 	pr.Plain("")
 	return false, nil
 }
+
+// CheckExpectedIdentifier fails when the command's --expect-identifier flag is
+// set and does not match the resolved identifier. It is a scripted guardrail for
+// destructive runs in CI, where the typed-identifier prompt is skipped.
+func CheckExpectedIdentifier(cmd *cobra.Command, identifier string) error {
+	if cmd.Flags().Lookup("expect-identifier") == nil {
+		return nil
+	}
+	expected, _ := cmd.Flags().GetString("expect-identifier")
+	if expected == "" || expected == identifier {
+		return nil
+	}
+	return apperr.New("common.CheckExpectedIdentifier", apperr.Precondition, "identifier mismatch: manifest resolves to '%s' but --expect-identifier is '%s'; refusing to continue", identifier, expected)
+}
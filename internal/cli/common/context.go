@@ -7,6 +7,7 @@ import (
 	"github.com/gcstr/dockform/internal/manifest"
 	"github.com/gcstr/dockform/internal/planner"
 	"github.com/gcstr/dockform/internal/ui"
+	"github.com/gcstr/dockform/internal/validator"
 	"github.com/spf13/cobra"
 )
 
@@ -35,16 +36,27 @@ func (ctx *CLIContext) GetDefaultClient() *dockercli.Client {
 }
 
 // SetupCLIContext performs the standard CLI setup: load config, create client factory, validate, and create planner.
+// A mismatching --expect-identifier fails right after the manifest is loaded,
+// before any daemon is contacted.
 func SetupCLIContext(cmd *cobra.Command) (*CLIContext, error) {
 	pr := ui.StdPrinter{Out: cmd.OutOrStdout(), Err: cmd.ErrOrStderr()}
+	cfg, err := LoadTargetedConfig(cmd, pr)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckExpectedIdentifier(cmd, cfg.Identifier); err != nil {
+		return nil, err
+	}
+	return SetupCLIContextWithConfig(cmd, pr, cfg)
+}
 
-	// Load configuration with warnings
+// LoadTargetedConfig loads the configuration with warnings and narrows it to
+// the targeting flags, when the command registers them.
+func LoadTargetedConfig(cmd *cobra.Command, pr ui.Printer) (*manifest.Config, error) {
 	cfg, err := LoadConfigWithWarnings(cmd, pr)
 	if err != nil {
 		return nil, err
 	}
-
-	// Apply target filtering if flags are registered
 	if cmd.Flags().Lookup("deployment") != nil {
 		opts := ReadTargetOptions(cmd)
 		if !opts.IsEmpty() {
@@ -54,12 +66,18 @@ func SetupCLIContext(cmd *cobra.Command) (*CLIContext, error) {
 			}
 		}
 	}
+	return cfg, nil
+}
 
+// SetupCLIContextWithConfig performs the standard CLI setup for a config
+// loaded with LoadTargetedConfig: create client factory, validate, and create
+// planner.
+func SetupCLIContextWithConfig(cmd *cobra.Command, pr ui.StdPrinter, cfg *manifest.Config) (*CLIContext, error) {
 	// Display context info
 	DisplayDaemonInfo(pr, cfg)
 
 	// Create client factory for multi-context support
-	factory := CreateClientFactory()
+	factory := CreateClientFactory(cmd)
 
 	// Fail fast (bounded) if any selected context's daemon is unreachable, before
 	// validation does any unbounded per-context daemon work.
@@ -70,12 +88,22 @@ func SetupCLIContext(cmd *cobra.Command) (*CLIContext, error) {
 	// Install run-scoped SSH multiplexing (best-effort) before any docker work.
 	ActivateSSHMux(cmd, cfg)
 
-	// Validate in spinner
-	err = SpinnerOperation(pr, "Validating...", func() error {
-		return ValidateWithFactory(cmd.Context(), cfg, factory)
-	})
-	if err != nil {
-		return nil, err
+	// Validate in spinner, unless the command lets the user skip it
+	if skipValidate(cmd) {
+		pr.Warn("validation skipped (--skip-validate); manifest and environment errors will surface during apply")
+	} else {
+		var warnings []string
+		err := SpinnerOperation(pr, "Validating...", func() error {
+			var verr error
+			warnings, verr = validator.ValidateWithWarnings(cmd.Context(), *cfg, factory)
+			return verr
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range warnings {
+			pr.Warn("%s", w)
+		}
 	}
 
 	// Create planner with factory
@@ -90,6 +118,16 @@ func SetupCLIContext(cmd *cobra.Command) (*CLIContext, error) {
 	}, nil
 }
 
+// skipValidate reports whether the command registers --skip-validate and the
+// user set it.
+func skipValidate(cmd *cobra.Command) bool {
+	if cmd.Flags().Lookup("skip-validate") == nil {
+		return false
+	}
+	skip, _ := cmd.Flags().GetBool("skip-validate")
+	return skip
+}
+
 // BuildPlan creates a plan using the CLI context with spinner UI.
 func (ctx *CLIContext) BuildPlan() (*planner.Plan, error) {
 	var planObj *planner.Plan
@@ -104,21 +142,29 @@ func (ctx *CLIContext) BuildPlan() (*planner.Plan, error) {
 	return planObj, err
 }
 
-// ApplyPlan executes the plan with dynamic spinner.
-func (ctx *CLIContext) ApplyPlan() error {
+// ApplyPlan executes the plan with dynamic spinner and returns the per-stack results.
+func (ctx *CLIContext) ApplyPlan() ([]planner.StackApplyResult, error) {
+	var results []planner.StackApplyResult
 	stdPr := ctx.Printer.(ui.StdPrinter)
-	return DynamicSpinnerOperation(stdPr, "Applying", func(s *ui.Spinner) error {
-		return ctx.Planner.WithSpinner(s, "Applying").Apply(ctx.Ctx, *ctx.Config)
+	err := DynamicSpinnerOperation(stdPr, "Applying", func(s *ui.Spinner) error {
+		var err error
+		results, err = ctx.Planner.WithSpinner(s, "Applying").Apply(ctx.Ctx, *ctx.Config)
+		return err
 	})
+	return results, err
 }
 
 // ApplyPlanWithContext executes the plan with progress tracking, reusing a pre-built plan.
 // This avoids redundant state detection by passing the execution context from the plan.
-func (ctx *CLIContext) ApplyPlanWithContext(plan *planner.Plan) error {
+func (ctx *CLIContext) ApplyPlanWithContext(plan *planner.Plan) ([]planner.StackApplyResult, error) {
+	var results []planner.StackApplyResult
 	stdPr := ctx.Printer.(ui.StdPrinter)
-	return DynamicSpinnerOperation(stdPr, "Applying", func(s *ui.Spinner) error {
-		return ctx.Planner.WithSpinner(s, "Applying").ApplyWithPlan(ctx.Ctx, *ctx.Config, plan)
+	err := DynamicSpinnerOperation(stdPr, "Applying", func(s *ui.Spinner) error {
+		var err error
+		results, err = ctx.Planner.WithSpinner(s, "Applying").ApplyWithPlan(ctx.Ctx, *ctx.Config, plan)
+		return err
 	})
+	return results, err
 }
 
 // PrunePlan executes pruning with spinner.
@@ -0,0 +1,19 @@
+package common
+
+import "fmt"
+
+// ExitCodeError makes Execute exit with Code. When Err is set it is printed
+// like any other command error; a nil Err exits silently.
+type ExitCodeError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitCodeError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+func (e *ExitCodeError) Unwrap() error { return e.Err }
@@ -15,9 +15,19 @@ import (
 )
 
 // ReachabilityProbeTimeout bounds each per-context daemon probe so an unreachable
-// host (e.g. a down SSH context) cannot hang the command. Tests override it; it is
+// host (e.g. a down SSH context) cannot hang the command. --context-timeout
+// overrides it per run through the client settings. Tests override it; it is
 // not safe to mutate from parallel (t.Parallel) tests.
-var ReachabilityProbeTimeout = 10 * time.Second
+var ReachabilityProbeTimeout = dockercli.DefaultProbeTimeout
+
+// ProbeTimeout returns the timeout for a daemon reachability probe of client:
+// its --context-timeout setting, or ReachabilityProbeTimeout.
+func ProbeTimeout(client *dockercli.Client) time.Duration {
+	if d, ok := client.ProbeTimeout(); ok {
+		return d
+	}
+	return ReachabilityProbeTimeout
+}
 
 // ContextProbeResult is the outcome of probing a single Docker context's daemon.
 type ContextProbeResult struct {
@@ -61,7 +71,7 @@ func EnsureContextsReachable(ctx context.Context, cfg *manifest.Config, factory
 }
 
 // ProbeContextsReachability probes every context in cfg in parallel, each bounded
-// by ReachabilityProbeTimeout, and returns one result per context sorted by name.
+// by ProbeTimeout, and returns one result per context sorted by name.
 // Callers that need per-context pass/fail reporting (e.g. `dockform doctor`) should
 // use this directly instead of EnsureContextsReachable, which only returns an
 // aggregated error.
@@ -91,17 +101,18 @@ func ProbeContextsReachability(ctx context.Context, cfg *manifest.Config, factor
 // probeContext returns an empty string when the context's daemon is reachable, or
 // a short human-readable cause when it is not.
 func probeContext(ctx context.Context, name string, cfg *manifest.Config, factory dockercli.ClientFactory) string {
-	probeCtx, cancel := context.WithTimeout(ctx, ReachabilityProbeTimeout)
+	client := factory.GetClientForContext(name, cfg)
+	timeout := ProbeTimeout(client)
+	probeCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	client := factory.GetClientForContext(name, cfg)
 	if err := client.CheckDaemon(probeCtx); err != nil {
 		// Parent context cancelled or expired — not our probe timeout; surface as-is.
 		if ctx.Err() != nil {
 			return err.Error()
 		}
 		if errors.Is(probeCtx.Err(), context.DeadlineExceeded) {
-			return fmt.Sprintf("timed out after %s", ReachabilityProbeTimeout)
+			return fmt.Sprintf("daemon unreachable (timeout after %s)", timeout)
 		}
 		return err.Error()
 	}
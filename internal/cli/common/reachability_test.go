@@ -10,6 +10,7 @@ import (
 
 	"github.com/gcstr/dockform/internal/apperr"
 	"github.com/gcstr/dockform/internal/cli/clitest"
+	"github.com/gcstr/dockform/internal/dockercli"
 	"github.com/gcstr/dockform/internal/manifest"
 )
 
@@ -40,7 +41,7 @@ func TestEnsureContextsReachable(t *testing.T) {
 		restore := clitest.WithCustomDockerStub(t, reachabilityStub)
 		defer restore()
 
-		factory := CreateClientFactory()
+		factory := CreateClientFactory(nil)
 		cfg := &manifest.Config{
 			Identifier: "demo",
 			Contexts: map[string]manifest.ContextConfig{
@@ -57,7 +58,7 @@ func TestEnsureContextsReachable(t *testing.T) {
 		restore := clitest.WithCustomDockerStub(t, reachabilityStub)
 		defer restore()
 
-		factory := CreateClientFactory()
+		factory := CreateClientFactory(nil)
 		cfg := &manifest.Config{
 			Identifier: "demo",
 			Contexts: map[string]manifest.ContextConfig{
@@ -85,7 +86,7 @@ func TestEnsureContextsReachable(t *testing.T) {
 		restore := clitest.WithCustomDockerStub(t, reachabilityStub)
 		defer restore()
 
-		factory := CreateClientFactory()
+		factory := CreateClientFactory(nil)
 		cfg := &manifest.Config{
 			Identifier: "demo",
 			Contexts: map[string]manifest.ContextConfig{
@@ -126,7 +127,7 @@ func TestEnsureContextsReachable(t *testing.T) {
 		restore := clitest.WithCustomDockerStub(t, reachabilityStub)
 		defer restore()
 
-		factory := CreateClientFactory()
+		factory := CreateClientFactory(nil)
 		cfg := &manifest.Config{
 			Identifier: "demo",
 			Contexts:   map[string]manifest.ContextConfig{},
@@ -161,7 +162,7 @@ exit 0
 	restore := clitest.WithCustomDockerStub(t, stub)
 	defer restore()
 
-	factory := CreateClientFactory()
+	factory := CreateClientFactory(nil)
 	cfg := &manifest.Config{
 		Identifier: "demo",
 		Contexts: map[string]manifest.ContextConfig{
@@ -187,7 +188,7 @@ func TestEnsureContextsReachable_Timeout(t *testing.T) {
 	ReachabilityProbeTimeout = 200 * time.Millisecond
 	defer func() { ReachabilityProbeTimeout = old }()
 
-	factory := CreateClientFactory()
+	factory := CreateClientFactory(nil)
 	cfg := &manifest.Config{
 		Identifier: "demo",
 		Contexts: map[string]manifest.ContextConfig{
@@ -215,7 +216,35 @@ func TestEnsureContextsReachable_Timeout(t *testing.T) {
 	if !strings.Contains(msg, "slow") {
 		t.Errorf("expected error to mention 'slow', got: %s", msg)
 	}
-	if !strings.Contains(msg, "timed out") {
-		t.Errorf("expected error to contain 'timed out', got: %s", msg)
+	if !strings.Contains(msg, "daemon unreachable (timeout after 200ms)") {
+		t.Errorf("expected error to report the probe timeout, got: %s", msg)
+	}
+}
+
+func TestEnsureContextsReachable_ContextTimeoutOverride(t *testing.T) {
+	restore := clitest.WithCustomDockerStub(t, reachabilityTimeoutStub)
+	defer restore()
+
+	// The package default stays long; the per-run setting
+	// (--context-timeout) must win.
+	old := ReachabilityProbeTimeout
+	ReachabilityProbeTimeout = time.Minute
+	defer func() { ReachabilityProbeTimeout = old }()
+
+	settings := dockercli.DefaultSettings()
+	settings.ProbeTimeout = 150 * time.Millisecond
+	factory := dockercli.NewClientFactory().WithSettings(settings)
+	cfg := &manifest.Config{
+		Identifier: "demo",
+		Contexts:   map[string]manifest.ContextConfig{"slow": {}},
+	}
+
+	start := time.Now()
+	err := EnsureContextsReachable(context.Background(), cfg, factory)
+	if elapsed := time.Since(start); elapsed >= 1500*time.Millisecond {
+		t.Errorf("EnsureContextsReachable took %s; expected the context timeout to apply", elapsed)
+	}
+	if err == nil || !strings.Contains(err.Error(), "timeout after 150ms") {
+		t.Fatalf("expected a timeout after 150ms, got %v", err)
 	}
 }
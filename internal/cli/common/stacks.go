@@ -0,0 +1,65 @@
+package common
+
+import (
+	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+// ResolveStack finds a stack by its context/stack key or, when unambiguous, by
+// its bare stack name.
+func ResolveStack(cfg *manifest.Config, input string) (string, manifest.Stack, error) {
+	allStacks := cfg.GetAllStacks()
+	if stack, ok := allStacks[input]; ok {
+		return input, stack, nil
+	}
+	if !strings.Contains(input, "/") {
+		var matches []string
+		for k := range allStacks {
+			if strings.HasSuffix(k, "/"+input) {
+				matches = append(matches, k)
+			}
+		}
+		if len(matches) == 1 {
+			return matches[0], allStacks[matches[0]], nil
+		}
+		if len(matches) > 1 {
+			return "", manifest.Stack{}, apperr.New("common.ResolveStack", apperr.InvalidInput, "stack %q is ambiguous; use context/stack format", input)
+		}
+	}
+	return "", manifest.Stack{}, apperr.New("common.ResolveStack", apperr.InvalidInput, "unknown stack %q", input)
+}
+
+// ResolveStackService resolves the stack and optional service named by args,
+// given as "stack service" or as a single argument. A lone argument that is
+// not a stack is read as stack/service, so "website/web" and
+// "hetzner/website/web" name the web service of the website stack.
+func ResolveStackService(cfg *manifest.Config, args []string) (string, manifest.Stack, string, error) {
+	if len(args) == 2 {
+		key, stack, err := ResolveStack(cfg, args[0])
+		return key, stack, args[1], err
+	}
+	key, stack, err := ResolveStack(cfg, args[0])
+	if err == nil {
+		return key, stack, "", nil
+	}
+	i := strings.LastIndex(args[0], "/")
+	if i <= 0 || i == len(args[0])-1 {
+		return "", manifest.Stack{}, "", err
+	}
+	key, stack, serr := ResolveStack(cfg, args[0][:i])
+	if serr != nil {
+		return "", manifest.Stack{}, "", err
+	}
+	return key, stack, args[0][i+1:], nil
+}
+
+// StackProjectName returns the compose project of a stack, falling back to the
+// stack name when the manifest doesn't set one.
+func StackProjectName(stackName string, stack manifest.Stack) string {
+	if stack.Project != nil && stack.Project.Name != "" {
+		return stack.Project.Name
+	}
+	return stackName
+}
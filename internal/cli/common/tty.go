@@ -24,3 +24,10 @@ func detectTTY(cmd *cobra.Command) ttyStatus {
 	}
 	return s
 }
+
+// IsInteractive reports whether cmd's stdin and stdout are both terminals, so
+// a command run on its behalf can be given a TTY.
+func IsInteractive(cmd *cobra.Command) bool {
+	s := detectTTY(cmd)
+	return s.In && s.Out
+}
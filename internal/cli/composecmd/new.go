@@ -52,6 +52,7 @@ func newRenderCmd() *cobra.Command {
 			for _, name := range missing {
 				pr.Warn("environment variable %s is not set; replacing with empty string", name)
 			}
+			common.ApplyGnupgHome(cmd, &cfg)
 
 			allStacks := cfg.GetAllStacks()
 			stackKey := stackInput
@@ -107,7 +108,7 @@ func newRenderCmd() *cobra.Command {
 			// Fail fast (bounded) if the stack's context daemon is unreachable, before
 			// shelling out to `docker compose config` (which can hang on a down host).
 			if _, ok := cfg.Contexts[contextName]; ok {
-				factory := common.CreateClientFactory()
+				factory := common.CreateClientFactory(cmd)
 				renderCfg := cfg
 				renderCfg.Contexts = map[string]manifest.ContextConfig{contextName: cfg.Contexts[contextName]}
 				if err := common.EnsureContextsReachable(cmd.Context(), &renderCfg, factory); err != nil {
@@ -116,13 +117,14 @@ func newRenderCmd() *cobra.Command {
 			}
 
 			// Compose raw config
+			settings, _ := common.ClientSettings(cmd)
 			var docker *dockercli.Client
 			if ctxCfg, ok := cfg.Contexts[contextName]; ok && ctxCfg.Host != "" {
-				docker = dockercli.NewWithHost(contextName, ctxCfg.Host).WithIdentifier(identifier)
+				docker = dockercli.NewWithHost(contextName, ctxCfg.Host).WithIdentifier(identifier).WithSettings(settings)
 			} else {
-				docker = dockercli.New(contextName).WithIdentifier(identifier)
+				docker = dockercli.New(contextName).WithIdentifier(identifier).WithSettings(settings)
 			}
-			raw, err := docker.ComposeConfigRaw(cmd.Context(), stack.Root, stack.Files, stack.Profiles, stack.EnvFile, inline)
+			raw, err := docker.ComposeConfigRaw(dockercli.WithStack(cmd.Context(), stack), stack.Root, stack.Files, stack.Profiles, stack.EnvFile, inline)
 			if err != nil {
 				return err
 			}
@@ -3,22 +3,35 @@ package dashboardcmd
 import (
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/gcstr/dockform/internal/apperr"
 	"github.com/gcstr/dockform/internal/cli/buildinfo"
 	"github.com/gcstr/dockform/internal/cli/common"
 	"github.com/gcstr/dockform/internal/cli/dashboardcmd/data"
+	"github.com/gcstr/dockform/internal/dockercli"
 	"github.com/gcstr/dockform/internal/manifest"
 	"github.com/spf13/cobra"
 )
 
 // New creates the `dockform dashboard` command.
 func New() *cobra.Command {
+	var statusTimeout time.Duration
+	var statusConcurrency int
+	var logTail int
+	var logSince string
+	var logTimestamps bool
 	cmd := &cobra.Command{
 		Use:   "dashboard",
 		Short: "Launch the Dockform dashboard (fullscreen TUI)",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			logOpts, err := dashboardLogOptions(logTail, logSince, logTimestamps, cmd.Flags().Changed("tail"))
+			if err != nil {
+				return err
+			}
 			cliCtx, err := common.SetupCLIContext(cmd)
 			if err != nil {
 				return err
@@ -41,16 +54,52 @@ func New() *cobra.Command {
 			contextName := dockerContextName(cliCtx.Config)
 
 			m := newModel(cliCtx.Ctx, docker, stacks, buildinfo.Version(), identifier, manifestPath, contextName, "", "")
-			m.statusProvider = data.NewStatusProvider(docker, identifier)
+			m.statusProvider = data.NewStatusProvider(docker, identifier).WithConcurrency(statusConcurrency)
+			m.statusTimeout = statusTimeout
+			m.logOpts = logOpts
+			// With several daemons the dashboard shows one at a time; ctrl+d
+			// moves to the next.
+			if m.daemons = daemonNames(cliCtx.Config); len(m.daemons) > 1 {
+				m.allStacks = stacks
+				m.clientFor = daemonClients(cliCtx.Factory, identifier)
+				m.statusConcurrency = statusConcurrency
+				m = m.scopeToDaemon(contextName)
+			}
 
 			p := tea.NewProgram(m, tea.WithAltScreen())
 			_, err = p.Run()
 			return err
 		},
 	}
+	cmd.Flags().DurationVar(&statusTimeout, "status-timeout", defaultStatusTimeout, "Time budget for each container status refresh")
+	cmd.Flags().IntVar(&statusConcurrency, "status-concurrency", data.DefaultStatusConcurrency, "Number of stacks whose statuses are fetched in parallel")
+	cmd.Flags().IntVar(&logTail, "tail", defaultLogTail, "Lines of log history loaded when a container is selected (0 = all)")
+	cmd.Flags().StringVar(&logSince, "tail-since", "", "Load log history from a time window (e.g. 10m) or timestamp; without --tail, all lines in the window are loaded")
+	cmd.Flags().BoolVar(&logTimestamps, "log-timestamps", true, "Prefix log lines with their timestamp")
 	return cmd
 }
 
+// dashboardLogOptions builds the log stream options from the dashboard flags.
+// --tail-since loads a time window; the line count then only caps it when
+// --tail was given explicitly.
+func dashboardLogOptions(tail int, since string, timestamps, tailSet bool) (dockercli.LogsOptions, error) {
+	if tail < 0 {
+		return dockercli.LogsOptions{}, apperr.New("cli.dashboard", apperr.InvalidInput, "--tail must not be negative")
+	}
+	since = strings.TrimSpace(since)
+	if since != "" {
+		if _, err := time.ParseDuration(since); err != nil {
+			if _, err := time.Parse(time.RFC3339, since); err != nil {
+				return dockercli.LogsOptions{}, apperr.New("cli.dashboard", apperr.InvalidInput, "invalid --tail-since %q (expected a duration like 10m or an RFC3339 timestamp)", since)
+			}
+		}
+		if !tailSet {
+			tail = 0
+		}
+	}
+	return dockercli.LogsOptions{Follow: true, Tail: tail, Since: since, Timestamps: timestamps}, nil
+}
+
 // min and max moved to util.go for reuse in the package
 
 var manifestFilenames = []string{"dockform.yml", "dockform.yaml", "Dockform.yml", "Dockform.yaml"}
@@ -64,6 +113,19 @@ func dockerContextName(cfg *manifest.Config) string {
 	return strings.TrimSpace(name)
 }
 
+// daemonNames returns the manifest's context names, sorted.
+func daemonNames(cfg *manifest.Config) []string {
+	if cfg == nil {
+		return nil
+	}
+	names := make([]string, 0, len(cfg.Contexts))
+	for name := range cfg.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func resolveManifestPath(cmd *cobra.Command, cfg *manifest.Config) string {
 	flagVal, _ := cmd.Flags().GetString("manifest")
 	flagVal = strings.TrimSpace(flagVal)
@@ -0,0 +1,84 @@
+package dashboardcmd
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/gcstr/dockform/internal/cli/dashboardcmd/data"
+	"github.com/gcstr/dockform/internal/dockercli"
+)
+
+// stacksOfDaemon returns the stacks deployed to daemon. Stack names are
+// "context/stack" keys.
+func stacksOfDaemon(stacks []data.StackSummary, daemon string) []data.StackSummary {
+	prefix := daemon + "/"
+	out := make([]data.StackSummary, 0, len(stacks))
+	for _, s := range stacks {
+		if strings.HasPrefix(s.Name, prefix) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// scopeToDaemon points the dashboard at daemon: its stacks, a client and
+// status provider for it, and fresh Docker, volume and network panes. Replies
+// still in flight for the previous daemon are ignored once they arrive.
+func (m model) scopeToDaemon(daemon string) model {
+	if m.logCancel != nil {
+		m.logCancel()
+		m.logCancel = nil
+	}
+	m.daemonGen++
+	m.contextName = daemon
+	m.stacks = stacksOfDaemon(m.allStacks, daemon)
+	m.list.SetItems(stackItemsFromSummaries(m.stacks))
+	m.list.Select(0)
+	m.containerNetworks = make(map[string][]string)
+	m.containerVolumes = make(map[string][]string)
+	buildAttachmentMaps(m.stacks, m.containerNetworks, m.containerVolumes)
+	m.statusByKey = make(map[data.Key]data.Status)
+	m.dockerHost, m.engineVersion = "", ""
+	m.volumes, m.networks = nil, nil
+	m.volumesLoading, m.networksLoading = false, false
+	m.selectedName, m.pendingSelName = "", ""
+	m.logsBuf = m.logsBuf[:0]
+	m.logsPaused, m.logsHeld = false, nil
+	m.logsPager.SetContent("")
+	if m.clientFor != nil {
+		m.dockerClient = m.clientFor(daemon)
+		m.statusProvider = data.NewStatusProvider(m.dockerClient, m.identifier).WithConcurrency(m.statusConcurrency)
+	}
+	return m
+}
+
+// nextDaemon scopes the dashboard to the daemon after the current one and
+// loads what it shows. It does nothing with fewer than two daemons.
+func (m model) nextDaemon() (model, tea.Cmd) {
+	if len(m.daemons) < 2 {
+		return m, nil
+	}
+	next := m.daemons[0]
+	for i, d := range m.daemons {
+		if d == m.contextName {
+			next = m.daemons[(i+1)%len(m.daemons)]
+			break
+		}
+	}
+	m = m.scopeToDaemon(next)
+	cmds := []tea.Cmd{m.fetchDockerInfoCmd(), m.startInitialLogsCmd()}
+	if cmd := m.fetchVolumesCmd(); cmd != nil {
+		m.volumesLoading = true
+		cmds = append(cmds, cmd)
+	}
+	if cmd := m.fetchNetworksCmd(); cmd != nil {
+		m.networksLoading = true
+		cmds = append(cmds, cmd)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// daemonClients returns a func handing out the client of each daemon.
+func daemonClients(factory *dockercli.DefaultClientFactory, identifier string) func(string) *dockercli.Client {
+	return func(daemon string) *dockercli.Client { return factory.GetClient(daemon, identifier) }
+}
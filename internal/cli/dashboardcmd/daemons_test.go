@@ -0,0 +1,66 @@
+package dashboardcmd
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/gcstr/dockform/internal/cli/dashboardcmd/components"
+	"github.com/gcstr/dockform/internal/cli/dashboardcmd/data"
+	"github.com/gcstr/dockform/internal/dockercli"
+)
+
+func TestNextDaemonScopesStacksAndClient(t *testing.T) {
+	m := newDashboardModel()
+	m.daemons = []string{"edge", "prod"}
+	m.allStacks = []data.StackSummary{
+		{Name: "edge/proxy", Services: []data.ServiceSummary{{Service: "traefik", ContainerName: "edge-traefik", Networks: []string{"public"}}}},
+		{Name: "prod/web", Services: []data.ServiceSummary{{Service: "app", ContainerName: "prod-app"}}},
+	}
+	clients := map[string]*dockercli.Client{}
+	m.clientFor = func(daemon string) *dockercli.Client {
+		clients[daemon] = newStubDockerClient()
+		return clients[daemon]
+	}
+	m = m.scopeToDaemon("edge")
+	if m.contextName != "edge" || len(m.list.Items()) != 1 || m.dockerClient != clients["edge"] {
+		t.Fatalf("expected the dashboard scoped to edge, got %q with %d items", m.contextName, len(m.list.Items()))
+	}
+	if nets := m.containerNetworks["edge-traefik"]; len(nets) != 1 {
+		t.Fatalf("expected attachments of edge stacks, got %v", m.containerNetworks)
+	}
+	m.dockerHost = "ssh://edge"
+
+	updated, cmd := m.Update(tea.KeyPressMsg(tea.Key{Code: 'd', Mod: tea.ModCtrl}))
+	m = updated.(model)
+	if cmd == nil || m.contextName != "prod" || m.dockerClient != clients["prod"] || m.dockerHost != "" {
+		t.Fatalf("expected ctrl+d to move to prod, got %q (host %q)", m.contextName, m.dockerHost)
+	}
+	it, _ := m.list.SelectedItem().(components.StackItem)
+	if len(m.list.Items()) != 1 || it.TitleText != "prod/web" {
+		t.Fatalf("expected only prod stacks listed, got %+v", m.list.Items())
+	}
+	if m.statusProvider == nil || m.statusProvider.Docker() != clients["prod"] {
+		t.Fatalf("expected a status provider for prod")
+	}
+
+	// Replies for the daemon left behind are dropped.
+	updated, _ = m.Update(dockerInfoMsg{host: "ssh://edge", gen: m.daemonGen - 1})
+	m = updated.(model)
+	if m.dockerHost != "" {
+		t.Fatalf("expected a stale reply to be ignored, got host %q", m.dockerHost)
+	}
+
+	updated, _ = m.Update(tea.KeyPressMsg(tea.Key{Code: 'd', Mod: tea.ModCtrl}))
+	if m = updated.(model); m.contextName != "edge" {
+		t.Fatalf("expected cycling to wrap to edge, got %q", m.contextName)
+	}
+}
+
+func TestNextDaemonWithSingleDaemonDoesNothing(t *testing.T) {
+	m := newDashboardModel()
+	m.daemons = []string{"ctx"}
+	updated, cmd := m.Update(tea.KeyPressMsg(tea.Key{Code: 'd', Mod: tea.ModCtrl}))
+	if m = updated.(model); cmd != nil || m.contextName != "ctx" || m.daemonGen != 0 {
+		t.Fatalf("expected no switch with one daemon")
+	}
+}
@@ -33,6 +33,7 @@ func NewLoader(cfg *manifest.Config, docker ComposeClient) (*Loader, error) {
 // StackSummary represents the information needed to populate the stacks column.
 type StackSummary struct {
 	Name     string
+	Project  string // compose project name; empty when it could not be resolved
 	Services []ServiceSummary
 }
 
@@ -64,17 +65,17 @@ func (l *Loader) StackSummaries(ctx context.Context) ([]StackSummary, error) {
 
 	for _, name := range stackNames {
 		stack := allStacks[name]
-		services, err := l.loadServices(ctx, name, stack)
+		project, services, err := l.loadServices(ctx, name, stack)
 		if err != nil {
 			return nil, err
 		}
-		summaries = append(summaries, StackSummary{Name: name, Services: services})
+		summaries = append(summaries, StackSummary{Name: name, Project: project, Services: services})
 	}
 
 	return summaries, nil
 }
 
-func (l *Loader) loadServices(ctx context.Context, stackName string, stack manifest.Stack) ([]ServiceSummary, error) {
+func (l *Loader) loadServices(ctx context.Context, stackName string, stack manifest.Stack) (string, []ServiceSummary, error) {
 	workingDir := stack.Root
 	if workingDir == "" {
 		workingDir = l.cfg.BaseDir
@@ -86,9 +87,9 @@ func (l *Loader) loadServices(ctx context.Context, stackName string, stack manif
 	envFiles := normalizePaths(workingDir, stack.EnvFile)
 	inline := append([]string(nil), stack.EnvInline...)
 
-	doc, err := l.docker.ComposeConfigFull(ctx, workingDir, files, stack.Profiles, envFiles, inline)
+	doc, err := l.docker.ComposeConfigFull(dockercli.WithStack(ctx, stack), workingDir, files, stack.Profiles, envFiles, inline)
 	if err != nil {
-		return nil, apperr.Wrap("dashboard.data.loadContainers", apperr.Internal, err, "failed for stack: %s", stackName)
+		return "", nil, apperr.Wrap("dashboard.data.loadContainers", apperr.Internal, err, "failed for stack: %s", stackName)
 	}
 
 	services := make([]ServiceSummary, 0, len(doc.Services))
@@ -123,7 +124,11 @@ func (l *Loader) loadServices(ctx context.Context, stackName string, stack manif
 	sort.Slice(services, func(i, j int) bool {
 		return services[i].Service < services[j].Service
 	})
-	return services, nil
+	project := strings.TrimSpace(doc.Name)
+	if stack.Project != nil && strings.TrimSpace(stack.Project.Name) != "" {
+		project = strings.TrimSpace(stack.Project.Name)
+	}
+	return project, services, nil
 }
 
 func normalizePaths(base string, rels []string) []string {
@@ -62,7 +62,7 @@ func TestStackSummariesBuildsServices(t *testing.T) {
 		ExpectEnvFiles:  envFiles,
 		ExpectProfiles:  []string{"default"},
 		ExpectInlineEnv: []string{"API_KEY=value"},
-		Doc: dockercli.ComposeConfigDoc{Services: map[string]dockercli.ComposeService{
+		Doc: dockercli.ComposeConfigDoc{Name: "paperless", Services: map[string]dockercli.ComposeService{
 			"paperless-redis": {Image: " redis:8 ", ContainerName: " paperless-redis "},
 			"paperless-ngx":   {Image: "", ContainerName: "paperless"},
 		}},
@@ -97,6 +97,9 @@ func TestStackSummariesBuildsServices(t *testing.T) {
 	if stack.Name != "paperless" {
 		t.Fatalf("expected stack name 'paperless', got %q", stack.Name)
 	}
+	if stack.Project != "paperless" {
+		t.Fatalf("expected project from compose config, got %q", stack.Project)
+	}
 	if len(stack.Services) != 2 {
 		t.Fatalf("expected 2 services, got %d", len(stack.Services))
 	}
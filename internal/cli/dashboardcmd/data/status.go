@@ -5,18 +5,34 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 
+	"github.com/gcstr/dockform/internal/apperr"
 	"github.com/gcstr/dockform/internal/dockercli"
 )
 
+// DefaultStatusConcurrency is the number of stacks whose statuses are fetched in parallel.
+const DefaultStatusConcurrency = 4
+
 // StatusProvider resolves container names for services and fetches their docker ps status.
 type StatusProvider struct {
-	docker     *dockercli.Client
-	identifier string // io.dockform.identifier
+	docker      *dockercli.Client
+	identifier  string // io.dockform.identifier
+	concurrency int    // max stacks fetched in parallel
 }
 
 func NewStatusProvider(d *dockercli.Client, identifier string) *StatusProvider {
-	return &StatusProvider{docker: d, identifier: strings.TrimSpace(identifier)}
+	return &StatusProvider{docker: d, identifier: strings.TrimSpace(identifier), concurrency: DefaultStatusConcurrency}
+}
+
+// WithConcurrency bounds how many stacks are fetched in parallel. Values below
+// one fall back to DefaultStatusConcurrency.
+func (sp *StatusProvider) WithConcurrency(n int) *StatusProvider {
+	if n < 1 {
+		n = DefaultStatusConcurrency
+	}
+	sp.concurrency = n
+	return sp
 }
 
 // Docker exposes the underlying docker client; used by the TUI to stream logs.
@@ -60,14 +76,79 @@ func (sp *StatusProvider) ResolveContainerName(ctx context.Context, stackName st
 	return rows[0].Names, nil
 }
 
-// FetchAll returns a map from (stack, service) to Status. Unknown entries are omitted.
+// StackStatuses is the outcome of fetching the statuses of one stack.
+type StackStatuses struct {
+	Stack    string
+	Statuses map[Key]Status
+	Err      error
+}
+
+// FetchStream fetches per-stack statuses concurrently, at most Concurrency at a
+// time, and delivers each stack's result on the returned channel as soon as it
+// is available. The channel is closed once every stack has been reported or ctx
+// is done, so slow stacks never hold back the others.
+func (sp *StatusProvider) FetchStream(ctx context.Context, stacks []StackSummary) <-chan StackStatuses {
+	out := make(chan StackStatuses, len(stacks))
+	limit := sp.concurrency
+	if limit < 1 {
+		limit = DefaultStatusConcurrency
+	}
+	go func() {
+		defer close(out)
+		sem := make(chan struct{}, limit)
+		var wg sync.WaitGroup
+		for _, st := range stacks {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				wg.Wait()
+				return
+			}
+			wg.Add(1)
+			go func(st StackSummary) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				statuses, err := sp.FetchStack(ctx, st)
+				out <- StackStatuses{Stack: st.Name, Statuses: statuses, Err: err}
+			}(st)
+		}
+		wg.Wait()
+	}()
+	return out
+}
+
+// FetchAll returns a map from (stack, service) to Status. Unknown entries are
+// omitted. Stacks that fail don't discard the others: their results are
+// returned alongside an error describing the failures.
 func (sp *StatusProvider) FetchAll(ctx context.Context, stacks []StackSummary) (map[Key]Status, error) {
 	out := make(map[Key]Status)
-	// Build filters once for this manifest (identifier limits scope)
+	var errs []error
+	for res := range sp.FetchStream(ctx, stacks) {
+		if res.Err != nil {
+			errs = append(errs, apperr.Wrap("dashboard.data.FetchAll", apperr.External, res.Err, "stack %s", res.Stack))
+			continue
+		}
+		for k, v := range res.Statuses {
+			out[k] = v
+		}
+	}
+	if len(errs) > 0 {
+		return out, apperr.Aggregate("dashboard.data.FetchAll", apperr.External, fmt.Sprintf("failed to fetch statuses for %d stack(s)", len(errs)), errs...)
+	}
+	return out, nil
+}
+
+// FetchStack returns the statuses of one stack's services. Containers are
+// scoped to the stack's compose project when it is known.
+func (sp *StatusProvider) FetchStack(ctx context.Context, st StackSummary) (map[Key]Status, error) {
+	out := make(map[Key]Status)
 	filters := []string{}
 	if sp.identifier != "" {
 		filters = append(filters, "label=io.dockform.identifier="+sp.identifier)
 	}
+	if project := strings.TrimSpace(st.Project); project != "" {
+		filters = append(filters, "label=com.docker.compose.project="+project)
+	}
 	rows, err := sp.docker.PsJSON(ctx, true, filters)
 	if err != nil {
 		return nil, err
@@ -84,26 +165,23 @@ func (sp *StatusProvider) FetchAll(ctx context.Context, stacks []StackSummary) (
 			byService[svc] = r
 		}
 	}
-	// Walk desired services
-	for _, st := range stacks {
-		for _, svc := range st.Services {
-			key := Key{Stack: st.Name, Service: svc.Service}
-			cand := dockercli.PsJSONRow{}
-			if cname := strings.TrimSpace(svc.ContainerName); cname != "" {
-				if r, ok := byName[cname]; ok {
-					cand = r
-				}
-			} else if r, ok := byService[strings.TrimSpace(svc.Service)]; ok {
+	for _, svc := range st.Services {
+		key := Key{Stack: st.Name, Service: svc.Service}
+		cand := dockercli.PsJSONRow{}
+		if cname := strings.TrimSpace(svc.ContainerName); cname != "" {
+			if r, ok := byName[cname]; ok {
 				cand = r
 			}
-			if strings.TrimSpace(cand.Names) == "" {
-				continue // not found
-			}
-			out[key] = Status{
-				ContainerName: cand.Names,
-				State:         strings.TrimSpace(cand.State),
-				StatusText:    strings.TrimSpace(cand.Status),
-			}
+		} else if r, ok := byService[strings.TrimSpace(svc.Service)]; ok {
+			cand = r
+		}
+		if strings.TrimSpace(cand.Names) == "" {
+			continue // not found
+		}
+		out[key] = Status{
+			ContainerName: cand.Names,
+			State:         strings.TrimSpace(cand.State),
+			StatusText:    strings.TrimSpace(cand.Status),
 		}
 	}
 	return out, nil
@@ -2,8 +2,12 @@ package data
 
 import (
 	"context"
+	"io"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
+	"unsafe"
 
 	"github.com/gcstr/dockform/internal/dockercli"
 )
@@ -78,3 +82,68 @@ func TestResolveContainerNamePrefersExplicitName(t *testing.T) {
 		t.Fatalf("expected name to be trimmed, got %q", name)
 	}
 }
+
+// projectExec answers docker ps per compose project filter; the "slow" project
+// blocks until the context is cancelled.
+type projectExec struct{}
+
+func (projectExec) Run(ctx context.Context, args ...string) (string, error) {
+	for _, a := range args {
+		switch a {
+		case "label=com.docker.compose.project=web":
+			return `{"Names":"web-app-1","State":"running","Status":"Up 2m","Labels":"com.docker.compose.service=app"}` + "\n", nil
+		case "label=com.docker.compose.project=slow":
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+	}
+	return "", nil
+}
+
+func (projectExec) RunInDir(ctx context.Context, _ string, args ...string) (string, error) {
+	return projectExec{}.Run(ctx, args...)
+}
+
+func (projectExec) RunInDirWithEnv(ctx context.Context, _ string, _ []string, args ...string) (string, error) {
+	return projectExec{}.Run(ctx, args...)
+}
+
+func (projectExec) RunWithStdin(ctx context.Context, _ io.Reader, args ...string) (string, error) {
+	return projectExec{}.Run(ctx, args...)
+}
+
+func (projectExec) RunWithStdout(ctx context.Context, _ io.Writer, args ...string) error {
+	_, err := projectExec{}.Run(ctx, args...)
+	return err
+}
+
+func (projectExec) RunDetailed(ctx context.Context, _ dockercli.Options, args ...string) (dockercli.Result, error) {
+	out, err := projectExec{}.Run(ctx, args...)
+	return dockercli.Result{Stdout: out}, err
+}
+
+func TestFetchAll_ReturnsPartialResultsWhenAStackTimesOut(t *testing.T) {
+	client := dockercli.New("")
+	val := reflect.ValueOf(client).Elem().FieldByName("exec")
+	reflect.NewAt(val.Type(), unsafe.Pointer(val.UnsafeAddr())).Elem().Set(reflect.ValueOf(projectExec{}))
+	sp := NewStatusProvider(client, "demo").WithConcurrency(2)
+
+	stacks := []StackSummary{
+		{Name: "default/slow", Project: "slow", Services: []ServiceSummary{{Service: "db"}}},
+		{Name: "default/web", Project: "web", Services: []ServiceSummary{{Service: "app"}}},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	statuses, err := sp.FetchAll(ctx, stacks)
+	if err == nil || !strings.Contains(err.Error(), "1 stack(s)") {
+		t.Fatalf("expected error for the slow stack, got %v", err)
+	}
+	got, ok := statuses[Key{Stack: "default/web", Service: "app"}]
+	if !ok || got.ContainerName != "web-app-1" || got.State != "running" {
+		t.Fatalf("expected web status despite slow stack, got %#v", statuses)
+	}
+	if _, ok := statuses[Key{Stack: "default/slow", Service: "db"}]; ok {
+		t.Fatalf("did not expect a status for the slow stack")
+	}
+}
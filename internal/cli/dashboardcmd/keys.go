@@ -14,6 +14,8 @@ type keyMap struct {
 	CyclePane  key.Binding
 	Select     key.Binding
 	Command    key.Binding
+	NextDaemon key.Binding
+	PauseLogs  key.Binding
 }
 
 func newKeyMap() keyMap {
@@ -24,7 +26,7 @@ func newKeyMap() keyMap {
 		),
 		Filter: key.NewBinding(
 			key.WithKeys("/"),
-			key.WithHelp("/", "filter stacks"),
+			key.WithHelp("/", "filter stacks or logs"),
 		),
 		MoveUp: key.NewBinding(
 			key.WithKeys("up", "k"),
@@ -54,6 +56,14 @@ func newKeyMap() keyMap {
 			key.WithKeys("ctrl+p"),
 			key.WithHelp("ctrl+p", "commands"),
 		),
+		PauseLogs: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "pause/resume logs"),
+		),
+		NextDaemon: key.NewBinding(
+			key.WithKeys("ctrl+d"),
+			key.WithHelp("ctrl+d", "next daemon"),
+		),
 		Quit: key.NewBinding(
 			key.WithKeys("q", "ctrl+c"),
 			key.WithHelp("q", "quit"),
@@ -70,8 +80,8 @@ func (k keyMap) ShortHelp() []key.Binding {
 // FullHelp returns all key bindings grouped.
 func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.MoveUp, k.MoveDown, k.NextPage, k.PrevPage}, // navigation column
-		{k.Filter, k.Select, k.CyclePane, k.Command},   // actions column
-		{k.Quit}, // misc column
+		{k.MoveUp, k.MoveDown, k.NextPage, k.PrevPage},            // navigation column
+		{k.Filter, k.Select, k.CyclePane, k.PauseLogs, k.Command}, // actions column
+		{k.NextDaemon, k.Quit},                                    // misc column
 	}
 }
@@ -3,6 +3,7 @@ package dashboardcmd
 import (
 	"bufio"
 	"context"
+	"fmt"
 	"io"
 	"strings"
 	"time"
@@ -31,28 +32,68 @@ func (m *model) streamLogsCmd(name string) tea.Cmd {
 	if m.logLines == nil {
 		m.logLines = make(chan string, 256)
 	}
+	opts := m.logOpts
+	opts.Follow = true
 	go func() {
 		sc := bufio.NewScanner(pr)
 		for sc.Scan() {
-			m.logLines <- sc.Text()
+			line := sc.Text()
+			if opts.Timestamps {
+				line = formatLogTimestamp(line)
+			}
+			m.logLines <- line
 		}
 	}()
 	go func() {
-		_ = m.statusProvider.Docker().StreamContainerLogs(ctx, name, 300, "", pw)
+		_ = m.statusProvider.Docker().FollowContainerLogs(ctx, name, opts, pw)
 		_ = pw.Close()
 	}()
 	return func() tea.Msg { return logStreamStartedMsg{cancel: cancel} }
 }
 
+// logTimestampLayout is how the dashboard renders docker log timestamps: a
+// fixed width, local time, second precision.
+const logTimestampLayout = "2006-01-02 15:04:05"
+
+// formatLogTimestamp rewrites the RFC3339Nano timestamp docker prefixes a
+// line with (--timestamps) in logTimestampLayout. Lines without one, such as
+// the recreated-container marker, are returned unchanged.
+func formatLogTimestamp(line string) string {
+	ts, rest, ok := strings.Cut(line, " ")
+	if !ok {
+		ts, rest = line, ""
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return line
+	}
+	return t.Local().Format(logTimestampLayout) + " " + rest
+}
+
+// maxLogLines is how many log lines the dashboard retains.
+const maxLogLines = 1000
+
+// appendLogLines appends lines to buf, keeping only the last maxLogLines.
+func appendLogLines(buf []string, lines ...string) []string {
+	buf = append(buf, lines...)
+	if len(buf) > maxLogLines {
+		buf = buf[len(buf)-maxLogLines:]
+	}
+	return buf
+}
+
+// withFlushedLogs moves streamed lines into the pager, or into logsHeld while
+// the stream is paused so the stream never blocks and no line is lost.
 func (m *model) withFlushedLogs() model {
 	drained := false
 	for m.logLines != nil {
 		select {
 		case ln := <-m.logLines:
-			m.logsBuf = append(m.logsBuf, ln)
-			if len(m.logsBuf) > 1000 {
-				m.logsBuf = m.logsBuf[len(m.logsBuf)-1000:]
+			if m.logsPaused {
+				m.logsHeld = appendLogLines(m.logsHeld, ln)
+				continue
 			}
+			m.logsBuf = appendLogLines(m.logsBuf, ln)
 			drained = true
 		default:
 			goto done
@@ -60,7 +101,30 @@ func (m *model) withFlushedLogs() model {
 	}
 done:
 	if drained {
-		m.logsPager.SetContent(strings.Join(m.logsBuf, "\n"))
+		m.renderLogs()
 	}
 	return *m
 }
+
+// toggleLogsPaused pauses the log stream, or resumes it and catches up on the
+// lines that arrived meanwhile.
+func (m model) toggleLogsPaused() model {
+	m.logsPaused = !m.logsPaused
+	if !m.logsPaused && len(m.logsHeld) > 0 {
+		m.logsBuf = appendLogLines(m.logsBuf, m.logsHeld...)
+		m.logsHeld = nil
+		m.renderLogs()
+	}
+	return m
+}
+
+// logsTitle is the Logs pane title, marked while the stream is paused.
+func (m model) logsTitle() string {
+	if !m.logsPaused {
+		return "Logs"
+	}
+	if n := len(m.logsHeld); n > 0 {
+		return fmt.Sprintf("Logs · PAUSED (%d new)", n)
+	}
+	return "Logs · PAUSED"
+}
@@ -1,6 +1,12 @@
 package dashboardcmd
 
-import "testing"
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
 
 func TestStreamLogsCmdWithoutProvider(t *testing.T) {
 	m := newDashboardModel()
@@ -16,3 +22,63 @@ func TestStreamLogsCmdWithoutProvider(t *testing.T) {
 		t.Fatalf("expected buffered logs to flush")
 	}
 }
+
+func TestFormatLogTimestamp(t *testing.T) {
+	orig := time.Local
+	time.Local = time.UTC
+	defer func() { time.Local = orig }()
+
+	if got := formatLogTimestamp("2024-05-01T12:03:04.123456789Z GET /health 200"); got != "2024-05-01 12:03:04 GET /health 200" {
+		t.Fatalf("unexpected formatted line %q", got)
+	}
+	if got := formatLogTimestamp("── container recreated ──"); got != "── container recreated ──" {
+		t.Fatalf("expected lines without a timestamp unchanged, got %q", got)
+	}
+}
+
+func TestDashboardLogOptions(t *testing.T) {
+	opts, err := dashboardLogOptions(defaultLogTail, "10m", true, false)
+	if err != nil {
+		t.Fatalf("options: %v", err)
+	}
+	if opts.Tail != 0 || opts.Since != "10m" || !opts.Timestamps || !opts.Follow {
+		t.Fatalf("expected the whole 10m window, got %+v", opts)
+	}
+	if opts, _ := dashboardLogOptions(50, "10m", false, true); opts.Tail != 50 {
+		t.Fatalf("expected an explicit --tail to cap the window, got %+v", opts)
+	}
+	if _, err := dashboardLogOptions(defaultLogTail, "yesterday", false, false); err == nil {
+		t.Fatalf("expected invalid --tail-since to be rejected")
+	}
+}
+
+func TestPauseHoldsLinesAndResumeCatchesUp(t *testing.T) {
+	m := newDashboardModel()
+	m.activePane = 1
+	m.logLines = make(chan string, 4)
+	m.logLines <- "before pause"
+	updated, _ := m.Update(logsTickMsg{})
+	m = updated.(model)
+
+	updated, _ = m.Update(tea.KeyPressMsg(tea.Key{Code: 'p', Text: "p"}))
+	m = updated.(model)
+	m.logLines <- "while paused 1"
+	m.logLines <- "while paused 2"
+	updated, _ = m.Update(logsTickMsg{})
+	m = updated.(model)
+	if len(m.logsBuf) != 1 || len(m.logsHeld) != 2 || strings.Contains(m.logsPager.View(), "while paused") {
+		t.Fatalf("expected lines held while paused, buf=%q held=%q", m.logsBuf, m.logsHeld)
+	}
+	if got := m.logsTitle(); got != "Logs · PAUSED (2 new)" {
+		t.Fatalf("expected a paused indicator, got %q", got)
+	}
+
+	updated, _ = m.Update(tea.KeyPressMsg(tea.Key{Code: 'p', Text: "p"}))
+	m = updated.(model)
+	if m.logsPaused || len(m.logsHeld) != 0 || len(m.logsBuf) != 3 || !strings.Contains(m.logsPager.View(), "while paused 2") {
+		t.Fatalf("expected resume to catch up, buf=%q", m.logsBuf)
+	}
+	if m.logsTitle() != "Logs" {
+		t.Fatalf("expected the indicator gone after resume")
+	}
+}
@@ -0,0 +1,86 @@
+package dashboardcmd
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/textinput"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/gcstr/dockform/internal/cli/dashboardcmd/theme"
+)
+
+var logMatchStyle = lipgloss.NewStyle().Foreground(theme.BgBase).Background(theme.Accent)
+
+func newLogFilterInput() textinput.Model {
+	in := textinput.New()
+	in.Prompt = "/ "
+	in.Placeholder = "substring or regex..."
+	return in
+}
+
+// compileLogFilter turns the query into a pattern: a regular expression when
+// it is one, a literal substring otherwise. An empty query filters nothing.
+func compileLogFilter(query string) *regexp.Regexp {
+	if query == "" {
+		return nil
+	}
+	if re, err := regexp.Compile(query); err == nil {
+		return re
+	}
+	return regexp.MustCompile(regexp.QuoteMeta(query))
+}
+
+// filterLogLines returns the lines matching re with every match highlighted,
+// or all lines unchanged when re is nil.
+func filterLogLines(lines []string, re *regexp.Regexp) []string {
+	if re == nil {
+		return lines
+	}
+	highlight := func(s string) string { return logMatchStyle.Render(s) }
+	out := make([]string, 0, len(lines))
+	for _, ln := range lines {
+		if re.MatchString(ln) {
+			out = append(out, re.ReplaceAllStringFunc(ln, highlight))
+		}
+	}
+	return out
+}
+
+// renderLogs shows the buffered lines that pass the log filter in the pager.
+func (m *model) renderLogs() {
+	m.logsPager.SetContent(strings.Join(filterLogLines(m.logsBuf, m.logFilterRe), "\n"))
+}
+
+// updateLogFilter handles a key while the log filter is being typed: enter
+// keeps the filter, esc clears it, anything else edits it and refilters.
+func (m model) updateLogFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		m.logFilterEditing = false
+		m.logFilter.Blur()
+		return m, nil
+	case "esc":
+		m.logFilterEditing = false
+		m.logFilter.Blur()
+		m.logFilter.SetValue("")
+		m.logFilterRe = nil
+		m.renderLogs()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.logFilter, cmd = m.logFilter.Update(msg)
+	m.logFilterRe = compileLogFilter(m.logFilter.Value())
+	m.renderLogs()
+	return m, cmd
+}
+
+// logFilterLine renders the filter prompt shown above the logs while it is
+// typed or applied, or "" when there is no filter.
+func (m model) logFilterLine(width int) string {
+	if !m.logFilterEditing && m.logFilter.Value() == "" {
+		return ""
+	}
+	m.logFilter.SetWidth(max(1, width-lipgloss.Width(m.logFilter.Prompt)-1))
+	return m.logFilter.View()
+}
@@ -0,0 +1,70 @@
+package dashboardcmd
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+func TestCompileLogFilter(t *testing.T) {
+	if compileLogFilter("") != nil {
+		t.Fatalf("expected no filter for an empty query")
+	}
+	if re := compileLogFilter(`err(or)?\b`); !re.MatchString("an error here") || re.MatchString("erroneous") {
+		t.Fatalf("expected a valid regex to be used as one")
+	}
+	if re := compileLogFilter("[warn"); !re.MatchString("x [warn] y") {
+		t.Fatalf("expected an invalid regex to match literally")
+	}
+}
+
+func TestLogFilterOnLogsPane(t *testing.T) {
+	m := newDashboardModel()
+	m.logsBuf = []string{"GET /health 200", "ERROR db timeout", "GET /api 500"}
+	m.renderLogs()
+
+	// On the Stacks pane "/" stays the stack filter.
+	updated, _ := m.Update(tea.KeyPressMsg(tea.Key{Code: '/', Text: "/"}))
+	if updated.(model).logFilterEditing {
+		t.Fatalf("expected / on the Stacks pane to leave the log filter alone")
+	}
+
+	m.activePane = 1
+	updated, _ = m.Update(tea.KeyPressMsg(tea.Key{Code: '/', Text: "/"}))
+	m = updated.(model)
+	if !m.logFilterEditing {
+		t.Fatalf("expected / on the Logs pane to open the log filter")
+	}
+	for _, r := range "GET.*[0-9]00|quit" {
+		updated, _ = m.Update(tea.KeyPressMsg(tea.Key{Code: r, Text: string(r)}))
+		m = updated.(model)
+	}
+	// Typing q edits the filter instead of quitting.
+	if m.quitting {
+		t.Fatalf("expected keys to go to the filter while typing")
+	}
+	view := m.logsPager.View()
+	if strings.Contains(view, "ERROR") || !strings.Contains(view, "/health") || !strings.Contains(view, "/api") {
+		t.Fatalf("expected only matching lines, got:\n%s", view)
+	}
+
+	updated, _ = m.Update(tea.KeyPressMsg(tea.Key{Code: tea.KeyEnter}))
+	m = updated.(model)
+	m.logLines = make(chan string, 2)
+	m.logLines <- "ERROR again"
+	m.logLines <- "GET /metrics 200"
+	updated, _ = m.Update(logsTickMsg{})
+	m = updated.(model)
+	if view := m.logsPager.View(); strings.Contains(view, "ERROR") || !strings.Contains(view, "/metrics") {
+		t.Fatalf("expected streamed lines to be filtered live, got:\n%s", view)
+	}
+
+	updated, _ = m.Update(tea.KeyPressMsg(tea.Key{Code: '/', Text: "/"}))
+	m = updated.(model)
+	updated, _ = m.Update(tea.KeyPressMsg(tea.Key{Code: tea.KeyEscape}))
+	m = updated.(model)
+	if m.logFilterEditing || m.logFilterRe != nil || !strings.Contains(m.logsPager.View(), "ERROR again") {
+		t.Fatalf("expected esc to clear the filter")
+	}
+}
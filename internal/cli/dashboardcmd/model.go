@@ -2,12 +2,14 @@ package dashboardcmd
 
 import (
 	"context"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/v2/help"
 	"github.com/charmbracelet/bubbles/v2/list"
+	"github.com/charmbracelet/bubbles/v2/textinput"
 	"github.com/charmbracelet/lipgloss/v2"
 	"github.com/gcstr/dockform/internal/cli/dashboardcmd/components"
 	"github.com/gcstr/dockform/internal/cli/dashboardcmd/data"
@@ -34,6 +36,16 @@ type model struct {
 	networks          []dockercli.NetworkSummary
 	containerNetworks map[string][]string
 	containerVolumes  map[string][]string
+	// daemon scoping: the dashboard shows one manifest context at a time
+	daemons           []string                       // context names, sorted
+	allStacks         []data.StackSummary            // stacks of every context
+	clientFor         func(string) *dockercli.Client // client of a context; nil in tests
+	statusConcurrency int                            // for status providers built on switch
+	daemonGen         int                            // bumped on switch; replies of older ones are dropped
+	// volumesLoading and networksLoading are set while a refresh of the pane
+	// is in flight, so a slow `docker system df` is not piled up every tick.
+	volumesLoading  bool
+	networksLoading bool
 
 	keys      keyMap
 	help      help.Model
@@ -43,10 +55,19 @@ type model struct {
 	// live state
 	statusProvider *data.StatusProvider
 	statusByKey    map[data.Key]data.Status
+	statusTimeout  time.Duration // per-refresh budget for fetching statuses
 	logCancel      context.CancelFunc
 	selectedName   string
 	logsBuf        []string
 	logLines       chan string
+	logOpts        dockercli.LogsOptions // history window and timestamps for log streams
+	// log filter: "/" on the Logs pane narrows logsBuf to matching lines
+	logFilter        textinput.Model
+	logFilterEditing bool
+	logFilterRe      *regexp.Regexp // nil shows every line
+	// pause: while set, streamed lines wait in logsHeld instead of moving the pager
+	logsPaused bool
+	logsHeld   []string
 	// debounce
 	pendingSelName string
 	debounceTimer  *time.Timer
@@ -116,11 +137,17 @@ func newModel(ctx context.Context, docker *dockercli.Client, stacks []data.Stack
 		logsPager:         components.NewLogsPager(),
 		statusByKey:       make(map[data.Key]data.Status),
 		logsBuf:           make([]string, 0, 512),
+		logOpts:           dockercli.LogsOptions{Follow: true, Tail: defaultLogTail},
 		headerCache:       make(map[string]string),
 		commandList:       newCommandPalette(),
+		logFilter:         newLogFilterInput(),
 	}
 }
 
+// defaultLogTail is how many lines of history are loaded when a container is
+// selected, unless --tail or --tail-since say otherwise.
+const defaultLogTail = 300
+
 func stackItemsFromSummaries(summaries []data.StackSummary) []list.Item {
 	items := make([]list.Item, 0)
 	for _, summary := range summaries {
@@ -73,13 +73,30 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 		}
+		// Keep draining the in-flight refresh; each stack arrives as its own message.
+		cmds = append(cmds, msg.next)
 		return m, tea.Batch(cmds...)
+	case statusesDoneMsg:
+		return m, m.tickStatuses()
 	case statusTickMsg:
-		return m, m.refreshStatusesCmd()
+		// Volumes and networks refresh on the same tick as statuses.
+		cmds := []tea.Cmd{m.refreshStatusesCmd()}
+		if cmd := m.fetchVolumesCmd(); cmd != nil && !m.volumesLoading {
+			m.volumesLoading = true
+			cmds = append(cmds, cmd)
+		}
+		if cmd := m.fetchNetworksCmd(); cmd != nil && !m.networksLoading {
+			m.networksLoading = true
+			cmds = append(cmds, cmd)
+		}
+		return m, tea.Batch(cmds...)
 	case logsTickMsg:
 		m = m.withFlushedLogs()
 		return m, m.tickLogs()
 	case dockerInfoMsg:
+		if msg.gen != m.daemonGen {
+			return m, nil
+		}
 		if strings.TrimSpace(msg.host) != "" {
 			m.dockerHost = strings.TrimSpace(msg.host)
 		}
@@ -88,12 +105,20 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 	case volumesMsg:
-		if msg.volumes != nil {
+		if msg.gen != m.daemonGen {
+			return m, nil
+		}
+		m.volumesLoading = false
+		if msg.err == nil {
 			m.volumes = msg.volumes
 		}
 		return m, nil
 	case networksMsg:
-		if msg.networks != nil {
+		if msg.gen != m.daemonGen {
+			return m, nil
+		}
+		m.networksLoading = false
+		if msg.err == nil {
 			m.networks = msg.networks
 		}
 		return m, nil
@@ -110,12 +135,16 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.selectedName = msg.name
 		m.logsBuf = m.logsBuf[:0]
+		m.logsPaused, m.logsHeld = false, nil
 		m.logsPager.SetContent("")
 		return m, m.streamLogsCmd(msg.name)
 	case logStreamStartedMsg:
 		m.logCancel = msg.cancel
 		return m, nil
 	case tea.KeyMsg:
+		if m.logFilterEditing && msg.String() != "ctrl+c" {
+			return m.updateLogFilter(msg)
+		}
 		if key.Matches(msg, m.keys.Quit) {
 			m.quitting = true
 			if m.logCancel != nil {
@@ -168,6 +197,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		switch {
+		case key.Matches(msg, m.keys.NextDaemon):
+			return m.nextDaemon()
+		case key.Matches(msg, m.keys.PauseLogs) && m.activePane == 1:
+			return m.toggleLogsPaused(), nil
+		case key.Matches(msg, m.keys.Filter) && m.activePane == 1:
+			// On the Logs pane "/" filters log lines; on Stacks the list
+			// handles it as the stack filter.
+			m.logFilterEditing = true
+			return m, m.logFilter.Focus()
 		case key.Matches(msg, m.keys.CyclePane):
 			m.activePane = (m.activePane + 1) % 2
 			return m, nil
@@ -264,16 +302,30 @@ func (m model) tickStatuses() tea.Cmd {
 }
 
 type statusTickMsg struct{}
-type statusesMsg struct{ statuses map[data.Key]data.Status }
+type statusesDoneMsg struct{}
+
+// statusesMsg carries the statuses of one stack; next waits for the following one.
+type statusesMsg struct {
+	statuses map[data.Key]data.Status
+	next     tea.Cmd
+}
 type dockerInfoMsg struct {
 	host    string
 	version string
+	gen     int // daemonGen the reply belongs to
 }
+
+// volumesMsg and networksMsg carry a refreshed pane; on err the pane keeps
+// what it showed.
 type volumesMsg struct {
 	volumes []dockercli.VolumeSummary
+	err     error
+	gen     int
 }
 type networksMsg struct {
 	networks []dockercli.NetworkSummary
+	err      error
+	gen      int
 }
 
 func (m model) startInitialLogsCmd() tea.Cmd {
@@ -291,34 +343,54 @@ func (m model) startInitialLogsCmd() tea.Cmd {
 	}
 }
 
+// defaultStatusTimeout bounds a status refresh when no --status-timeout is set.
+const defaultStatusTimeout = 3 * time.Second
+
 func (m model) refreshStatusesCmd() tea.Cmd {
 	if m.statusProvider == nil {
 		return m.tickStatuses()
 	}
 	stacks := m.stacks
-	return tea.Sequence(
-		func() tea.Msg {
-			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-			defer cancel()
-			statuses, err := m.statusProvider.FetchAll(ctx, stacks)
-			if err == nil {
-				return statusesMsg{statuses: statuses}
+	timeout := m.statusTimeout
+	if timeout <= 0 {
+		timeout = defaultStatusTimeout
+	}
+	sp := m.statusProvider
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		return waitForStatuses(sp.FetchStream(ctx, stacks), cancel)()
+	}
+}
+
+// waitForStatuses delivers the next stack's statuses from an in-flight refresh.
+// Failed stacks keep their previous statuses rather than blanking the list.
+func waitForStatuses(ch <-chan data.StackStatuses, cancel context.CancelFunc) tea.Cmd {
+	return func() tea.Msg {
+		for res := range ch {
+			if res.Err != nil {
+				continue
 			}
-			return nil
-		},
-		m.tickStatuses(),
-	)
+			return statusesMsg{statuses: res.Statuses, next: waitForStatuses(ch, cancel)}
+		}
+		cancel()
+		return statusesDoneMsg{}
+	}
 }
 
 func (m model) fetchDockerInfoCmd() tea.Cmd {
 	if m.dockerClient == nil {
 		return nil
 	}
-	ctx := m.ctx
+	ctx, gen := m.ctx, m.daemonGen
 	return func() tea.Msg {
 		host, _ := m.dockerClient.ContextHost(ctx)
-		version, _ := m.dockerClient.ServerVersion(ctx)
-		return dockerInfoMsg{host: strings.TrimSpace(host), version: strings.TrimSpace(version)}
+		var version string
+		if caps, err := m.dockerClient.Ping(ctx); err == nil && caps.ServerVersion != "" {
+			version = caps.String()
+		} else {
+			version, _ = m.dockerClient.ServerVersion(ctx)
+		}
+		return dockerInfoMsg{host: strings.TrimSpace(host), version: strings.TrimSpace(version), gen: gen}
 	}
 }
 
@@ -326,13 +398,19 @@ func (m model) fetchVolumesCmd() tea.Cmd {
 	if m.dockerClient == nil {
 		return nil
 	}
-	ctx := m.ctx
+	ctx, gen := m.ctx, m.daemonGen
 	return func() tea.Msg {
 		vols, err := m.dockerClient.VolumeSummaries(ctx)
 		if err != nil {
-			return nil
+			return volumesMsg{err: err, gen: gen}
 		}
-		return volumesMsg{volumes: vols}
+		// Disk usage is best effort: the pane still lists volumes without it.
+		if sizes, err := m.dockerClient.VolumeSizes(ctx); err == nil {
+			for i := range vols {
+				vols[i].Size = sizes[vols[i].Name]
+			}
+		}
+		return volumesMsg{volumes: vols, gen: gen}
 	}
 }
 
@@ -340,12 +418,12 @@ func (m model) fetchNetworksCmd() tea.Cmd {
 	if m.dockerClient == nil {
 		return nil
 	}
-	ctx := m.ctx
+	ctx, gen := m.ctx, m.daemonGen
 	return func() tea.Msg {
 		nets, err := m.dockerClient.NetworkSummaries(ctx)
 		if err != nil {
-			return nil
+			return networksMsg{err: err, gen: gen}
 		}
-		return networksMsg{networks: nets}
+		return networksMsg{networks: nets, gen: gen}
 	}
 }
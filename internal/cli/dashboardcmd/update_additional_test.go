@@ -2,6 +2,7 @@ package dashboardcmd
 
 import (
 	"context"
+	"errors"
 	"io"
 	"reflect"
 	"testing"
@@ -47,6 +48,8 @@ func (stubExec) Run(ctx context.Context, args ...string) (string, error) {
 		}
 	case "network":
 		return "net\tbridge\n", nil
+	case "system":
+		return `{"Volumes":[{"Name":"vol","Size":"12MB"}]}`, nil
 	case "ps":
 		return `{"ID":"1","Names":"container","Image":"img","Status":"Up","State":"running","Labels":"com.docker.compose.project=stack,com.docker.compose.service=svc"}` + "\n", nil
 	}
@@ -201,8 +204,8 @@ func TestModelFetchCommandsWithStubDocker(t *testing.T) {
 	}
 	if cmd := m.fetchVolumesCmd(); cmd == nil {
 		t.Fatalf("expected volumes command")
-	} else if msg := cmd().(volumesMsg); len(msg.volumes) == 0 {
-		t.Fatalf("expected volume data")
+	} else if msg := cmd().(volumesMsg); len(msg.volumes) == 0 || msg.volumes[0].Size != "12MB" {
+		t.Fatalf("expected volume data with disk usage, got %+v", msg.volumes)
 	}
 	if cmd := m.fetchNetworksCmd(); cmd == nil {
 		t.Fatalf("expected networks command")
@@ -216,3 +219,51 @@ func TestModelFetchCommandsWithStubDocker(t *testing.T) {
 		t.Fatalf("expected init command batch")
 	}
 }
+
+func TestWaitForStatusesDeliversStacksIncrementally(t *testing.T) {
+	ch := make(chan data.StackStatuses, 3)
+	ch <- data.StackStatuses{Stack: "a", Statuses: map[data.Key]data.Status{{Stack: "a", Service: "x"}: {State: "running"}}}
+	ch <- data.StackStatuses{Stack: "b", Err: context.DeadlineExceeded}
+	ch <- data.StackStatuses{Stack: "c", Statuses: map[data.Key]data.Status{{Stack: "c", Service: "y"}: {State: "exited"}}}
+	close(ch)
+	cancelled := false
+
+	msg := waitForStatuses(ch, func() { cancelled = true })()
+	first, ok := msg.(statusesMsg)
+	if !ok || len(first.statuses) != 1 || first.next == nil {
+		t.Fatalf("expected first stack statuses with a follow-up command, got %#v", msg)
+	}
+	second, ok := first.next().(statusesMsg)
+	if !ok {
+		t.Fatalf("expected failed stack to be skipped")
+	}
+	if _, ok := second.statuses[data.Key{Stack: "c", Service: "y"}]; !ok {
+		t.Fatalf("expected third stack statuses, got %#v", second.statuses)
+	}
+	if _, ok := second.next().(statusesDoneMsg); !ok || !cancelled {
+		t.Fatalf("expected done message and cancelled context once the channel is drained")
+	}
+}
+
+func TestStatusTickRefreshesVolumesAndNetworks(t *testing.T) {
+	m := newDashboardModel()
+	m.ctx = context.Background()
+	m.dockerClient = newStubDockerClient()
+	m.volumes = []dockercli.VolumeSummary{{Name: "old"}}
+
+	updated, cmd := m.Update(statusTickMsg{})
+	m = updated.(model)
+	if cmd == nil || !m.volumesLoading || !m.networksLoading {
+		t.Fatalf("expected the tick to start volume and network refreshes")
+	}
+	updated, _ = m.Update(volumesMsg{err: errors.New("daemon gone")})
+	m = updated.(model)
+	if m.volumesLoading || len(m.volumes) != 1 || m.volumes[0].Name != "old" {
+		t.Fatalf("expected a failed refresh to keep the pane, got %+v", m.volumes)
+	}
+	updated, _ = m.Update(volumesMsg{})
+	m = updated.(model)
+	if len(m.volumes) != 0 {
+		t.Fatalf("expected an empty refresh to clear the pane, got %+v", m.volumes)
+	}
+}
@@ -127,7 +127,7 @@ func (m model) renderColumns(bodyHeight int) string {
 	rightStyle := box.Align(lipgloss.Left).Height(innerHeight).MaxHeight(innerHeight)
 
 	leftTitle := "Stacks"
-	centerTitle := "Logs"
+	centerTitle := m.logsTitle()
 
 	leftW, centerW, _ := computeColumnWidths(m.width)
 
@@ -161,7 +161,7 @@ func (m model) renderColumns(bodyHeight int) string {
 		centerHeader = renderHeaderWithPadding(centerTitle, centerW, centerPadding, "dash")
 	}
 	m.logsPager.SetSize(centerW-(paddingHorizontal+1)*2, max(1, innerHeight-3))
-	centerContent := centerHeader + "\n\n" + m.logsPager.View()
+	centerContent := centerHeader + "\n" + m.logFilterLine(centerW-centerPadding) + "\n" + m.logsPager.View()
 
 	leftView := leftStyle.Width(leftW).Render(leftContent)
 	centerView := centerStyle.Width(centerW).Render(centerContent)
@@ -234,7 +234,7 @@ func (m model) renderColumns(bodyHeight int) string {
 func (m model) renderVolumesSection(contentWidth int) string {
 	active := m.selectedVolumeSet()
 	if len(active) == 0 {
-		return lipgloss.NewStyle().Foreground(theme.FgHalfMuted).Italic(true).Render("(no volumes attached)")
+		return lipgloss.NewStyle().Foreground(theme.FgHalfMuted).Italic(true).Render("(none)")
 	}
 	blocks := make([]string, 0, len(active))
 	lineWidth := contentWidth - 2
@@ -243,15 +243,15 @@ func (m model) renderVolumesSection(contentWidth int) string {
 	}
 	for _, vol := range m.volumes {
 		mount := truncateRight(displayVolumeMount(vol.Mountpoint), lineWidth)
-		driver := truncateRight(displayVolumeDriver(vol.Driver), lineWidth)
+		detail := truncateRight(displayVolumeDetail(vol.Driver, vol.Size), lineWidth)
 		nameKey := strings.TrimSpace(vol.Name)
 		if _, ok := active[nameKey]; !ok {
 			continue
 		}
-		blocks = append(blocks, components.RenderVolume(vol.Name, mount, driver, true))
+		blocks = append(blocks, components.RenderVolume(vol.Name, mount, detail, true))
 	}
 	if len(blocks) == 0 {
-		return lipgloss.NewStyle().Foreground(theme.FgHalfMuted).Italic(true).Render("(no volumes attached)")
+		return lipgloss.NewStyle().Foreground(theme.FgHalfMuted).Italic(true).Render("(none)")
 	}
 	return strings.Join(blocks, "\n\n")
 }
@@ -260,7 +260,7 @@ func (m model) renderVolumesSection(contentWidth int) string {
 func (m model) renderNetworksSection(contentWidth int) string {
 	active := m.selectedNetworkSet()
 	if len(active) == 0 {
-		return lipgloss.NewStyle().Foreground(theme.FgHalfMuted).Italic(true).Render("(no networks attached)")
+		return lipgloss.NewStyle().Foreground(theme.FgHalfMuted).Italic(true).Render("(none)")
 	}
 	lines := make([]string, 0, len(active))
 	for _, n := range m.networks {
@@ -273,7 +273,7 @@ func (m model) renderNetworksSection(contentWidth int) string {
 		lines = append(lines, components.RenderNetwork(name, driver, true))
 	}
 	if len(lines) == 0 {
-		return lipgloss.NewStyle().Foreground(theme.FgHalfMuted).Italic(true).Render("(no networks attached)")
+		return lipgloss.NewStyle().Foreground(theme.FgHalfMuted).Italic(true).Render("(none)")
 	}
 	return strings.Join(lines, "\n")
 }
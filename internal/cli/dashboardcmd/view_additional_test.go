@@ -23,15 +23,15 @@ func TestModelViewRendersColumnsAndPalette(t *testing.T) {
 
 func TestRenderSectionsAndHelpers(t *testing.T) {
 	m := newDashboardModel()
-	m.volumes = []dockercli.VolumeSummary{{Name: "vol1", Driver: "local", Mountpoint: "/data"}}
+	m.volumes = []dockercli.VolumeSummary{{Name: "vol1", Driver: "local", Mountpoint: "/data", Size: "1.2GB"}}
 	m.networks = []dockercli.NetworkSummary{{Name: "net1", Driver: "bridge"}}
 	m.containerVolumes = map[string][]string{"container": {"vol1"}}
 	m.containerNetworks = map[string][]string{"container": {"net1"}}
 	m.width = 100
 	m.height = 30
 	volSection := m.renderVolumesSection(40)
-	if !strings.Contains(volSection, "vol1") {
-		t.Fatalf("expected volume details in section")
+	if !strings.Contains(volSection, "vol1") || !strings.Contains(volSection, "local · 1.2GB") {
+		t.Fatalf("expected volume details and disk usage in section, got %q", volSection)
 	}
 	netSection := m.renderNetworksSection(40)
 	if !strings.Contains(netSection, "net1") {
@@ -44,3 +44,14 @@ func TestRenderSectionsAndHelpers(t *testing.T) {
 		t.Fatalf("unexpected truncation result: %q", got)
 	}
 }
+
+func TestRenderSectionsShowNoneWhenEmpty(t *testing.T) {
+	m := newDashboardModel()
+	m.containerVolumes = map[string][]string{"container": {"gone"}}
+	if got := m.renderVolumesSection(40); !strings.Contains(got, "(none)") {
+		t.Fatalf("expected (none) for a volume docker no longer lists, got %q", got)
+	}
+	if got := m.renderNetworksSection(40); !strings.Contains(got, "(none)") {
+		t.Fatalf("expected (none) without networks, got %q", got)
+	}
+}
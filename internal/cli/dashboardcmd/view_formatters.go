@@ -79,6 +79,16 @@ func displayVolumeDriver(driver string) string {
 	return d
 }
 
+// displayVolumeDetail returns the volume driver followed by its disk usage
+// when known, e.g. "local · 1.2GB".
+func displayVolumeDetail(driver, size string) string {
+	d := displayVolumeDriver(driver)
+	if sz := strings.TrimSpace(size); sz != "" {
+		return d + " · " + sz
+	}
+	return d
+}
+
 // displayNetworkDriver returns a clean network driver or placeholder.
 func displayNetworkDriver(driver string) string {
 	d := strings.TrimSpace(driver)
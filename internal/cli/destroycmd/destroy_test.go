@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/gcstr/dockform/internal/apperr"
 	"github.com/gcstr/dockform/internal/cli"
 	"github.com/gcstr/dockform/internal/cli/clitest"
 )
@@ -366,3 +367,86 @@ exit 0
 		t.Fatalf("expected destroy-related error, got: %v", err)
 	}
 }
+
+func TestDestroy_Exclude_ListsKeptResources(t *testing.T) {
+	undo := clitest.WithCustomDockerStub(t, `#!/bin/sh
+cmd="$1"; shift
+case "$cmd" in
+  version)
+    exit 0 ;;
+  volume)
+    sub="$1"; shift
+    if [ "$sub" = "ls" ]; then echo "app-volume"; echo "shared-volume"; exit 0; fi ;;
+  network)
+    sub="$1"; shift
+    if [ "$sub" = "ls" ]; then echo "app-network"; exit 0; fi ;;
+  ps)
+    exit 0 ;;
+  inspect)
+    echo "{}"
+    exit 0 ;;
+esac
+exit 0
+`)
+	defer undo()
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetIn(strings.NewReader("wrong\n"))
+	root.SetArgs([]string{"destroy", "--exclude", "volume:shared-volume", "--exclude", "network:app-network", "--manifest", clitest.BasicConfigPath(t)})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("destroy execute: %v", err)
+	}
+	got := out.String()
+	if !strings.Contains(got, "shared-volume will be kept") || !strings.Contains(got, "app-network will be kept") {
+		t.Fatalf("expected excluded resources listed as kept; got: %s", got)
+	}
+	if !strings.Contains(got, "app-volume will be deleted") {
+		t.Fatalf("expected non-excluded volume to be destroyed; got: %s", got)
+	}
+}
+
+func TestDestroy_Exclude_InvalidValue(t *testing.T) {
+	root := cli.TestNewRootCmd()
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"destroy", "--exclude", "container:web", "--manifest", clitest.BasicConfigPath(t)})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "unknown resource type") {
+		t.Fatalf("expected invalid --exclude error, got %v", err)
+	}
+}
+
+func TestDestroy_ExpectIdentifier_FailsOnMismatch(t *testing.T) {
+	defer clitest.WithStubDocker(t)()
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"destroy", "--manifest", clitest.BasicConfigPath(t), "--skip-confirmation", "--expect-identifier", "prod"})
+	err := root.Execute()
+	if err == nil {
+		t.Fatalf("expected identifier mismatch error")
+	}
+	if !apperr.IsKind(err, apperr.Precondition) || !strings.Contains(err.Error(), "'demo'") || !strings.Contains(err.Error(), "'prod'") {
+		t.Fatalf("expected precondition error naming both identifiers, got: %v", err)
+	}
+	if strings.Contains(out.String(), "Done.") {
+		t.Fatalf("destroy must not run on mismatch; got: %s", out.String())
+	}
+}
+
+func TestDestroy_ExpectIdentifier_ProceedsOnMatch(t *testing.T) {
+	defer clitest.WithStubDocker(t)()
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"destroy", "--manifest", clitest.BasicConfigPath(t), "--skip-confirmation", "--expect-identifier", "demo"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("destroy with matching identifier: %v", err)
+	}
+}
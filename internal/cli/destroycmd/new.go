@@ -6,6 +6,7 @@ import (
 
 	"github.com/gcstr/dockform/internal/cli/common"
 	"github.com/gcstr/dockform/internal/planner"
+	"github.com/gcstr/dockform/internal/ui"
 	"github.com/spf13/cobra"
 )
 
@@ -27,23 +28,57 @@ regardless of what's in your current configuration file.
 
 Use --stack or --context to scope the destroy. When scoped, only the targeted
 stacks' services and their own fileset volumes are removed; shared context-level
-networks and volumes are preserved.`,
+networks and volumes are preserved.
+
+Use --exclude volume:<name> or --exclude network:<name> (repeatable) to keep
+specific volumes or networks during an otherwise full destroy. Excluded
+resources are listed in the plan as kept.
+
+Use --expect-identifier <id> in scripts to refuse the destroy unless the
+resolved identifier is <id>, guarding against running against the wrong
+environment when confirmation is skipped.
+
+Use --prune-filter to restrict destroy to some resource kinds, e.g.
+--prune-filter containers removes containers but never networks or volumes.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			skipConfirm, _ := cmd.Flags().GetBool("skip-confirmation")
+			excludeFlags, _ := cmd.Flags().GetStringSlice("exclude")
+			exclusions, err := planner.ParseDestroyExclusions(excludeFlags)
+			if err != nil {
+				return err
+			}
+			pruneFilterFlags, _ := cmd.Flags().GetStringSlice("prune-filter")
+			pruneFilter, err := planner.ParsePruneFilter(pruneFilterFlags)
+			if err != nil {
+				return err
+			}
 
-			// Setup CLI context with all standard initialization
-			ctx, err := common.SetupCLIContext(cmd)
+			// Load the manifest and fail fast on --expect-identifier before
+			// validation or any daemon work.
+			pr := ui.StdPrinter{Out: cmd.OutOrStdout(), Err: cmd.ErrOrStderr()}
+			cfg, err := common.LoadTargetedConfig(cmd, pr)
 			if err != nil {
 				return err
 			}
 
 			// Allow environment to override identifier for discovery/confirmation independence
-			identifier := common.GetFirstIdentifier(ctx.Config)
+			identifier := common.GetFirstIdentifier(cfg)
 			if override := os.Getenv("DOCKFORM_RUN_ID"); override != "" {
 				identifier = override
 				// Update project identifier to use the override
-				ctx.Config.Identifier = override
+				cfg.Identifier = override
 			}
+			if err := common.CheckExpectedIdentifier(cmd, identifier); err != nil {
+				return err
+			}
+
+			// Setup CLI context with all standard initialization
+			ctx, err := common.SetupCLIContextWithConfig(cmd, pr, cfg)
+			if err != nil {
+				return err
+			}
+
+			ctx.Planner = ctx.Planner.WithDestroyExclusions(exclusions).WithPruneFilter(pruneFilter)
 
 			// Build destroy plan using the planner
 			plan, err := ctx.BuildDestroyPlan()
@@ -59,6 +94,10 @@ networks and volumes are preserved.`,
 			}
 
 			ctx.Printer.Plain("%s", out)
+			if _, _, toDelete := plan.Resources.CountActions(); toDelete == 0 {
+				ctx.Printer.Plain("Nothing to destroy: all discovered resources are excluded.")
+				return nil
+			}
 
 			// Get confirmation from user (requires typing identifier)
 			confirmed, err := common.GetDestroyConfirmation(cmd, ctx.Printer, common.DestroyConfirmationOptions{
@@ -98,8 +137,11 @@ networks and volumes are preserved.`,
 		},
 	}
 	cmd.Flags().Bool("skip-confirmation", false, "Skip confirmation prompt and destroy immediately")
+	cmd.Flags().String("expect-identifier", "", "Fail unless the resolved identifier equals this value")
 	cmd.Flags().Bool("strict", false, "Fail destroy when cleanup operations encounter errors")
 	cmd.Flags().Bool("verbose-errors", false, "Print detailed cleanup error details when not using --strict")
+	cmd.Flags().StringSlice("exclude", nil, "Keep a resource during destroy: volume:<name> or network:<name> (repeatable)")
+	cmd.Flags().StringSlice("prune-filter", nil, "Only destroy these resource kinds: containers, networks, volumes (comma-separated; default all)")
 	common.AddTargetFlags(cmd)
 	return cmd
 }
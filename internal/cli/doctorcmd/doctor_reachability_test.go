@@ -200,7 +200,7 @@ func TestDoctorCmd_ContextFlag_ScopesToSingleContext(t *testing.T) {
 	if !strings.Contains(output, `[context] Active context reachable — "up1"`) {
 		t.Errorf("expected single active-context check for up1, got: %q", output)
 	}
-	if !strings.Contains(output, "8 PASS, 0 WARN, 0 FAIL") {
+	if !strings.Contains(output, "9 PASS, 0 WARN, 0 FAIL") {
 		t.Errorf("expected all checks to pass when scoped to the reachable up1 context, got: %q", output)
 	}
 }
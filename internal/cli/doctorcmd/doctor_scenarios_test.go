@@ -43,10 +43,10 @@ func TestDoctorCmd_AllHealthy(t *testing.T) {
 	}
 
 	// Check summary
-	if !strings.Contains(output, "Summary: 8 checks") {
+	if !strings.Contains(output, "Summary: 9 checks") {
 		t.Errorf("missing summary line, got: %q", output)
 	}
-	if !strings.Contains(output, "8 PASS, 0 WARN, 0 FAIL") {
+	if !strings.Contains(output, "9 PASS, 0 WARN, 0 FAIL") {
 		t.Errorf("expected all pass, got: %q", output)
 	}
 	if !strings.Contains(output, "All good!") {
@@ -57,6 +57,37 @@ func TestDoctorCmd_AllHealthy(t *testing.T) {
 	}
 }
 
+func TestDoctorCmd_GpgUsesGnupgHomeOverride(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("gpgconf stub requires a POSIX shell")
+	}
+	defer withHealthyDoctorStub(t)()
+	// A gpgconf that reports the agent socket under whatever home it runs with.
+	dir := t.TempDir()
+	stub := "#!/bin/sh\necho \"$GNUPGHOME/S.gpg-agent\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "gpgconf"), []byte(stub), 0o755); err != nil {
+		t.Fatalf("write gpgconf stub: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	home := filepath.Join(t.TempDir(), "gnupg")
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"doctor", "--gnupg-home", home})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("doctor command failed: %v", err)
+	}
+	output := out.String()
+	if !strings.Contains(output, "home: "+home) {
+		t.Errorf("expected the gpg check to report the override home, got: %q", output)
+	}
+	if !strings.Contains(output, home+"/S.gpg-agent") {
+		t.Errorf("expected gpgconf to run with GNUPGHOME set to the override, got: %q", output)
+	}
+}
+
 func TestDoctorCmd_WithContext(t *testing.T) {
 	defer withHealthyDoctorStub(t)()
 
@@ -427,6 +458,53 @@ exit 0
 	}
 }
 
+func TestDoctorCmd_SELinuxEnforcingWarns(t *testing.T) {
+	defer withDoctorStub(t, `#!/bin/sh
+cmd="$1"; shift
+case "$cmd" in
+  version)
+    echo "20.0.0"
+    exit 0
+    ;;
+  context)
+    echo '"unix:///var/run/docker.sock"'
+    exit 0
+    ;;
+  compose)
+    echo "2.29.0"
+    exit 0
+    ;;
+  info)
+    echo '["name=seccomp,profile=builtin","name=selinux"]'
+    exit 0
+    ;;
+  run)
+    # helper container reading /sys/fs/selinux/enforce
+    echo "1"
+    exit 0
+    ;;
+esac
+exit 0
+`)()
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"doctor"})
+
+	if err := root.Execute(); err == nil {
+		t.Fatalf("expected error (exit code 2) when SELinux is enforcing")
+	}
+	output := out.String()
+	if !strings.Contains(output, "[mac] Volume labeling (SELinux/AppArmor) — SELinux enforcing") {
+		t.Errorf("expected SELinux enforcing warning, got: %q", output)
+	}
+	if !strings.Contains(output, "container_file_t") {
+		t.Errorf("expected relabeling remedy, got: %q", output)
+	}
+}
+
 func TestDoctorCmd_IndentedOutput(t *testing.T) {
 	defer withDoctorStub(t, `#!/bin/sh
 cmd="$1"; shift
@@ -4,15 +4,19 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/gcstr/dockform/internal/apperr"
 	"github.com/gcstr/dockform/internal/cli/buildinfo"
 	"github.com/gcstr/dockform/internal/cli/common"
 	"github.com/gcstr/dockform/internal/dockercli"
 	"github.com/gcstr/dockform/internal/manifest"
+	"github.com/gcstr/dockform/internal/secrets"
 	"github.com/gcstr/dockform/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -51,7 +55,8 @@ func New() *cobra.Command {
 			if ctxName == "" {
 				ctxName = "default"
 			}
-			docker := dockercli.New(ctxName)
+			settings, _ := common.ClientSettings(cmd)
+			docker := dockercli.New(ctxName).WithSettings(settings)
 
 			// Header lines
 			ctx := cmd.Context()
@@ -70,7 +75,7 @@ func New() *cobra.Command {
 
 			// [context] — probe every context configured in the manifest (or just
 			// the --context override, if given), each bounded by
-			// common.ReachabilityProbeTimeout so a down host reports as
+			// common.ProbeTimeout (--context-timeout) so a down host reports as
 			// unreachable instead of hanging.
 			results = append(results, checkContextsReachable(ctx, cmd, ctxOverride, ctxName, docker)...)
 
@@ -79,8 +84,8 @@ func New() *cobra.Command {
 
 			// [sops]
 			results = append(results, checkSops())
-			// [gpg]
-			results = append(results, checkGpg())
+			// [gpg] — with the GnuPG home sops will be run with
+			results = append(results, checkGpg(gnupgHome(cmd)))
 
 			// [helper]
 			results = append(results, checkHelperImage(ctx, docker))
@@ -91,6 +96,12 @@ func New() *cobra.Command {
 			// [vol-perms]
 			results = append(results, checkVolumePerms(ctx, docker))
 
+			// [mac]
+			results = append(results, checkVolumeLabeling(ctx, docker))
+
+			// [ports] — only when the manifest publishes host ports.
+			results = append(results, checkHostPorts(ctx, cmd, ctxOverride)...)
+
 			// Render
 			// Top header
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Dockform (v%s) Doctor — health scan\n", buildinfo.Version())
@@ -177,12 +188,13 @@ func checkEngine(ctx context.Context, docker *dockercli.Client) checkResult {
 	// Bounded: exec.CommandContext only kills the docker CLI once the deadline
 	// fires, and the plain command context has none. Without this timeout, a
 	// docker-over-SSH call to a dead host hangs the doctor command forever.
-	probeCtx, cancel := context.WithTimeout(ctx, common.ReachabilityProbeTimeout)
+	timeout := common.ProbeTimeout(docker)
+	probeCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 	if err := docker.CheckDaemon(probeCtx); err != nil {
 		summary := "daemon not reachable"
 		if probeCtx.Err() != nil && ctx.Err() == nil {
-			summary = fmt.Sprintf("timed out after %s", common.ReachabilityProbeTimeout)
+			summary = fmt.Sprintf("daemon unreachable (timeout after %s)", timeout)
 		}
 		return checkResult{
 			id:      "engine",
@@ -193,6 +205,9 @@ func checkEngine(ctx context.Context, docker *dockercli.Client) checkResult {
 			note:    "Remedy: Ensure Docker is running and your user can access it.",
 		}
 	}
+	if caps, err := docker.Ping(probeCtx); err == nil && caps.ServerVersion != "" {
+		return checkResult{id: "engine", title: "Docker Engine reachable", status: StatusPass, summary: "v" + caps.String()}
+	}
 	ver, err := docker.ServerVersion(probeCtx)
 	if err != nil || strings.TrimSpace(ver) == "" {
 		return checkResult{id: "engine", title: "Docker Engine reachable", status: StatusPass, summary: "version unknown"}
@@ -222,7 +237,7 @@ func checkContextsReachable(ctx context.Context, cmd *cobra.Command, ctxOverride
 			"Note: manifest contexts were not checked (no manifest loaded); only the active context was probed.")}
 	}
 
-	factory := common.CreateClientFactory()
+	factory := common.CreateClientFactory(cmd)
 	probeResults := common.ProbeContextsReachability(ctx, cfg, factory)
 	results := make([]checkResult, 0, len(probeResults))
 	for _, r := range probeResults {
@@ -255,7 +270,7 @@ func checkContextsReachable(ctx context.Context, cmd *cobra.Command, ctxOverride
 // which is local metadata lookup (docker context inspect) rather than a call to
 // the remote daemon, but is still bounded defensively.
 func checkSingleContextReachable(ctx context.Context, docker *dockercli.Client, ctxName, degradedNote string) checkResult {
-	probeCtx, cancel := context.WithTimeout(ctx, common.ReachabilityProbeTimeout)
+	probeCtx, cancel := context.WithTimeout(ctx, common.ProbeTimeout(docker))
 	defer cancel()
 
 	var sub []string
@@ -280,6 +295,13 @@ func loadManifestQuietly(cmd *cobra.Command) (*manifest.Config, error) {
 }
 
 func checkCompose(ctx context.Context, docker *dockercli.Client) checkResult {
+	variant, err := docker.ComposeVariant(ctx)
+	if apperr.IsKind(err, apperr.Precondition) {
+		return checkResult{id: "compose", title: "Docker Compose (v2+)", status: StatusFail, summary: "docker-compose v1", note: "Remedy: Install docker compose plugin (v2+) or docker-compose v2.", errMsg: err.Error()}
+	}
+	if err != nil {
+		return checkResult{id: "compose", title: "Docker Compose (v2+)", status: StatusFail, summary: "not found", note: "Remedy: Install docker compose plugin (v2+) or the standalone docker-compose binary."}
+	}
 	ver, err := docker.ComposeVersion(ctx)
 	if err != nil {
 		return checkResult{id: "compose", title: "Docker Compose (v2+)", status: StatusFail, summary: "not found", note: "Remedy: Install docker compose plugin (v2+).", errMsg: err.Error()}
@@ -292,7 +314,11 @@ func checkCompose(ctx context.Context, docker *dockercli.Client) checkResult {
 		return checkResult{id: "compose", title: "Docker Compose (v2+)", status: StatusFail, summary: summary, note: "Remedy: Install docker compose plugin (v2+)."}
 	}
 	short := strings.TrimSpace(ver)
-	return checkResult{id: "compose", title: "Docker Compose plugin", status: StatusPass, summary: short}
+	title := "Docker Compose plugin"
+	if variant == dockercli.ComposeStandalone {
+		title = "Docker Compose (docker-compose)"
+	}
+	return checkResult{id: "compose", title: title, status: StatusPass, summary: short}
 }
 
 // isComposeV2OrLater parses a version string (e.g. "2.29.0", "v5.0.2") and returns true if major >= 2.
@@ -339,22 +365,44 @@ func checkSops() checkResult {
 	return checkResult{id: "sops", title: "SOPS present", status: StatusPass, summary: ver, sub: sub}
 }
 
-func checkGpg() checkResult {
+// gnupgHome returns the GnuPG home apply would run sops with: --gnupg-home,
+// else the manifest's sops.pgp.keyring_dir. "" means gpg's default home.
+func gnupgHome(cmd *cobra.Command) string {
+	var opts secrets.SopsOptions
+	opts.GnupgHome, _ = cmd.Flags().GetString("gnupg-home")
+	if cfg, err := loadManifestQuietly(cmd); err == nil && cfg.Sops != nil && cfg.Sops.Pgp != nil {
+		opts.PgpKeyringDir = cfg.Sops.Pgp.KeyringDir
+	}
+	return opts.GnupgHomeDir()
+}
+
+func checkGpg(home string) checkResult {
 	if _, err := exec.LookPath("gpg"); err != nil {
 		// Not fatal; only warn if gpg not present
 		return checkResult{id: "gpg", title: "GnuPG", status: StatusWarn, summary: "gpg not found", note: "Tip: Install GnuPG if using PGP with SOPS."}
 	}
+	// Run gpg as sops will, against the same home.
+	gpgCmd := func(args ...string) *exec.Cmd {
+		c := exec.Command(args[0], args[1:]...)
+		if home != "" {
+			c.Env = append(os.Environ(), "GNUPGHOME="+home)
+		}
+		return c
+	}
 	// Version and loopback support
-	out, _ := exec.Command("gpg", "--version").CombinedOutput()
+	out, _ := gpgCmd("gpg", "--version").CombinedOutput()
 	lines := strings.Split(strings.ReplaceAll(string(out), "\r\n", "\n"), "\n")
 	var ver string
 	if len(lines) > 0 {
 		ver = strings.TrimSpace(lines[0])
 	}
 	sub := []string{}
+	if home != "" {
+		sub = append(sub, "home: "+home)
+	}
 	// Agent socket dir (best effort)
 	if _, err := exec.LookPath("gpgconf"); err == nil {
-		if b, err := exec.Command("gpgconf", "--list-dirs", "agent-socket").CombinedOutput(); err == nil {
+		if b, err := gpgCmd("gpgconf", "--list-dirs", "agent-socket").CombinedOutput(); err == nil {
 			socket := strings.TrimSpace(string(b))
 			if socket != "" {
 				sub = append(sub, "agent socket: "+socket)
@@ -362,7 +410,7 @@ func checkGpg() checkResult {
 		}
 	}
 	// Check loopback support by looking for pinentry-mode mention in help
-	helpOut, _ := exec.Command("gpg", "--help").CombinedOutput()
+	helpOut, _ := gpgCmd("gpg", "--help").CombinedOutput()
 	loopbackSupported := strings.Contains(string(helpOut), "pinentry-mode")
 	if loopbackSupported {
 		sub = append(sub, "loopback: supported")
@@ -420,6 +468,48 @@ func checkVolumePerms(ctx context.Context, docker *dockercli.Client) checkResult
 	return checkResult{id: "vol-perms", title: "Volume create/remove", status: StatusPass, summary: "ok"}
 }
 
+// checkVolumeLabeling detects SELinux or AppArmor on the daemon host. Under
+// enforcing SELinux, containers can only write files labeled for container
+// use, so bind mounts next to filesets fail with "permission denied" unless
+// relabeled.
+func checkVolumeLabeling(ctx context.Context, docker *dockercli.Client) checkResult {
+	const id, title = "mac", "Volume labeling (SELinux/AppArmor)"
+	opts, err := docker.SecurityOptions(ctx)
+	if err != nil {
+		return checkResult{id: id, title: title, status: StatusWarn, summary: "check failed — " + strings.TrimSpace(err.Error()), note: "Note: Could not read the daemon's security options."}
+	}
+	selinux, apparmor := slices.Contains(opts, "selinux"), slices.Contains(opts, "apparmor")
+
+	var sub []string
+	if apparmor {
+		sub = append(sub, "apparmor: enabled (the docker-default profile does not restrict volume writes)")
+	}
+	if !selinux {
+		if apparmor {
+			return checkResult{id: id, title: title, status: StatusPass, summary: "AppArmor, no SELinux", sub: sub}
+		}
+		return checkResult{id: id, title: title, status: StatusPass, summary: "no SELinux or AppArmor"}
+	}
+
+	// The daemon only reports SELinux support; read the host mode from a
+	// helper container, where selinuxfs is mounted read-only.
+	mode := "enabled"
+	if out, err := docker.RunInHelperImage(ctx, "cat /sys/fs/selinux/enforce 2>/dev/null || true"); err == nil {
+		switch strings.TrimSpace(out) {
+		case "1":
+			mode = "enforcing"
+		case "0":
+			mode = "permissive"
+		}
+	}
+	if mode == "permissive" {
+		sub = append(sub, "selinux: permissive (denials are logged, not enforced)")
+		return checkResult{id: id, title: title, status: StatusPass, summary: "SELinux permissive", sub: sub}
+	}
+	return checkResult{id: id, title: title, status: StatusWarn, summary: "SELinux " + mode, sub: sub,
+		note: "Note: Containers can only write files labeled container_file_t, so fileset syncs into volumes that are also bind-mounted may fail with \"permission denied\". Remedy: add :z (shared) or :Z (private) to those bind mounts in compose, or relabel the host path with chcon -Rt container_file_t <path>."}
+}
+
 // printIndentedLines prints multi-line text with proper indentation and pipe continuation.
 // Each line is prefixed with "│     " to maintain visual alignment under the check item.
 func PrintIndentedLines(w io.Writer, text string) {
@@ -0,0 +1,137 @@
+package doctorcmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gcstr/dockform/internal/cli/common"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+	"github.com/gcstr/dockform/internal/planner"
+	"github.com/gcstr/dockform/internal/validator"
+	"github.com/spf13/cobra"
+)
+
+// checkHostPorts verifies, per context, that the host ports the manifest's
+// stacks publish are not already bound on the daemon host by anything other
+// than the manifest's own containers. With a --context override only that
+// context is checked. It returns no result when no manifest is loaded or no
+// stack publishes a port.
+func checkHostPorts(ctx context.Context, cmd *cobra.Command, ctxOverride string) []checkResult {
+	cfg, err := loadManifestQuietly(cmd)
+	if err != nil || cfg == nil {
+		return nil
+	}
+	byContext := map[string][]string{}
+	for key := range cfg.GetAllStacks() {
+		contextName, _, err := manifest.ParseStackKey(key)
+		if err == nil && (ctxOverride == "" || contextName == ctxOverride) {
+			byContext[contextName] = append(byContext[contextName], key)
+		}
+	}
+	contexts := make([]string, 0, len(byContext))
+	for name := range byContext {
+		contexts = append(contexts, name)
+	}
+	sort.Strings(contexts)
+
+	factory := common.CreateClientFactory(cmd)
+	var results []checkResult
+	for _, contextName := range contexts {
+		docker := factory.GetClientForContext(contextName, cfg)
+		if r, ok := checkContextPorts(ctx, cfg, contextName, byContext[contextName], docker); ok {
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+// checkContextPorts runs the host port check for the stacks of one context.
+func checkContextPorts(ctx context.Context, cfg *manifest.Config, contextName string, stackKeys []string, docker *dockercli.Client) (checkResult, bool) {
+	id, title := "ports:"+contextName, fmt.Sprintf("Host ports free on %q", contextName)
+	sort.Strings(stackKeys)
+
+	var published []validator.PublishedPort
+	var skipped []string
+	stacks := cfg.GetAllStacks()
+	for _, key := range stackKeys {
+		stack := stacks[key]
+		inline, err := planner.NewServiceStateDetector(nil).BuildInlineEnv(ctx, stack, cfg.Sops)
+		if err == nil {
+			var doc dockercli.ComposeConfigDoc
+			if doc, err = docker.ComposeConfigFull(dockercli.WithStack(ctx, stack), stack.Root, stack.Files, stack.Profiles, stack.EnvFile, inline); err == nil {
+				published = append(published, validator.PublishedPorts(key, doc)...)
+				continue
+			}
+		}
+		skipped = append(skipped, fmt.Sprintf("%s not checked: %v", key, err))
+	}
+	if len(published) == 0 && len(skipped) == 0 {
+		return checkResult{}, false
+	}
+
+	listening, err := docker.ListeningPorts(ctx)
+	if err != nil {
+		return checkResult{id: id, title: title, status: StatusWarn, summary: "check failed — " + strings.TrimSpace(err.Error()), note: "Note: Could not read the host's bound ports from a helper container."}, true
+	}
+	containers, err := docker.PsJSON(ctx, false, nil)
+	if err != nil {
+		return checkResult{id: id, title: title, status: StatusWarn, summary: "check failed — " + strings.TrimSpace(err.Error()), note: "Note: Could not list running containers."}, true
+	}
+
+	occupied := occupiedPorts(published, listening, containers, cfg.Identifier)
+	sub := append(occupied, skipped...)
+	switch {
+	case len(occupied) > 0:
+		return checkResult{id: id, title: title, status: StatusFail, summary: fmt.Sprintf("%d of %d published port(s) in use", len(occupied), len(published)), sub: sub,
+			note: "Remedy: Stop whatever holds the port, or publish the service on another host port."}, true
+	case len(skipped) > 0:
+		return checkResult{id: id, title: title, status: StatusWarn, summary: fmt.Sprintf("%d published port(s) free, %d stack(s) not checked", len(published), len(skipped)), sub: sub}, true
+	}
+	return checkResult{id: id, title: title, status: StatusPass, summary: fmt.Sprintf("%d published port(s) free", len(published))}, true
+}
+
+// occupiedPorts describes each published port that is already bound on the
+// host by something other than a container of this identifier: another
+// container (named) or a host process.
+func occupiedPorts(published []validator.PublishedPort, listening []dockercli.HostPort, containers []dockercli.PsJSONRow, identifier string) []string {
+	var out []string
+	for _, p := range published {
+		holder := ""
+		for _, c := range containers {
+			if holdsPort(c.HostPorts(), p) {
+				if c.LabelValue("io.dockform.identifier") != identifier {
+					holder = "container " + c.Names
+				}
+				break
+			}
+		}
+		if holder == "" && !publishedByAny(containers, p) && holdsPort(listening, p) {
+			holder = "a host process"
+		}
+		if holder != "" {
+			out = append(out, fmt.Sprintf("%d/%s (%s, service %s): in use by %s", p.Port, p.Protocol, p.Stack, p.Service, holder))
+		}
+	}
+	return out
+}
+
+func publishedByAny(containers []dockercli.PsJSONRow, p validator.PublishedPort) bool {
+	for _, c := range containers {
+		if holdsPort(c.HostPorts(), p) {
+			return true
+		}
+	}
+	return false
+}
+
+func holdsPort(bound []dockercli.HostPort, p validator.PublishedPort) bool {
+	for _, b := range bound {
+		if b.Port == p.Port && b.Protocol == p.Protocol && validator.HostIPsOverlap(b.IP, p.HostIP) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package doctorcmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/validator"
+)
+
+func TestOccupiedPorts(t *testing.T) {
+	published := []validator.PublishedPort{
+		{Stack: "default/web", Service: "nginx", Protocol: "tcp", Port: 80},
+		{Stack: "default/web", Service: "nginx", Protocol: "tcp", Port: 443},
+		{Stack: "default/db", Service: "postgres", HostIP: "127.0.0.1", Protocol: "tcp", Port: 5432},
+		{Stack: "default/dns", Service: "coredns", Protocol: "udp", Port: 53},
+		{Stack: "default/app", Service: "api", Protocol: "tcp", Port: 8080},
+	}
+	listening := []dockercli.HostPort{
+		{Protocol: "tcp", IP: "0.0.0.0", Port: 80},
+		{Protocol: "tcp", IP: "0.0.0.0", Port: 443},
+		{Protocol: "tcp", IP: "10.0.0.5", Port: 5432}, // different address: no clash
+		{Protocol: "tcp", IP: "0.0.0.0", Port: 53},    // tcp, not udp
+		{Protocol: "tcp", IP: "::", Port: 8080},
+	}
+	containers := []dockercli.PsJSONRow{
+		{Names: "web-nginx-1", Labels: "io.dockform.identifier=demo", Ports: "0.0.0.0:80->80/tcp"},
+		{Names: "legacy-proxy", Labels: "io.dockform.identifier=other", Ports: "0.0.0.0:443->443/tcp"},
+	}
+
+	got := occupiedPorts(published, listening, containers, "demo")
+	want := []string{
+		"443/tcp (default/web, service nginx): in use by container legacy-proxy",
+		"8080/tcp (default/app, service api): in use by a host process",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("occupiedPorts mismatch:\n got: %q\nwant: %q", got, want)
+	}
+}
@@ -0,0 +1,107 @@
+package execcmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/cli/common"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+	"github.com/gcstr/dockform/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// New creates the `exec` command.
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exec <[context/]stack/service> -- <command> [args...]",
+		Short: "Run a command in a running service container",
+		Long: `Run a command in a running container of a stack's service, like
+docker exec, on the daemon of the stack's context.
+
+The command runs in the first running replica of the service. Stdin is
+attached, and a TTY is allocated when stdin and stdout are terminals. The exit
+code of the command becomes the exit code of dockform. Containers that do not
+carry the manifest's io.dockform.identifier label are refused.`,
+		Example: `  dockform exec default/web -- sh
+  dockform exec website/web -- cat /etc/hosts`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if dash := cmd.ArgsLenAtDash(); dash != 1 || len(args) < 2 {
+				return apperr.New("cli.exec", apperr.InvalidInput, "usage: dockform exec <[context/]stack/service> -- <command> [args...]")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pr := ui.StdPrinter{Out: cmd.OutOrStdout(), Err: cmd.ErrOrStderr()}
+			cfg, err := common.LoadConfigWithWarnings(cmd, pr)
+			if err != nil {
+				return err
+			}
+			stackKey, stack, service, err := common.ResolveStackService(cfg, args[:1])
+			if err != nil {
+				return err
+			}
+			if service == "" {
+				return apperr.New("cli.exec", apperr.InvalidInput, "no service given for %s; use %s/<service>", stackKey, args[0])
+			}
+			contextName, stackName, err := manifest.ParseStackKey(stackKey)
+			if err != nil {
+				return err
+			}
+			docker := common.CreateClientFactory(cmd).GetClientForContext(contextName, cfg)
+
+			name, err := serviceContainer(cmd, docker, cfg.Identifier, common.StackProjectName(stackName, stack), service)
+			if err != nil {
+				return err
+			}
+			if name == "" {
+				return apperr.New("cli.exec", apperr.NotFound, "no running container found for %s/%s", stackKey, service)
+			}
+			code, err := docker.Exec(cmd.Context(), name, args[1:], dockercli.ExecOptions{
+				TTY:    common.IsInteractive(cmd),
+				Stdin:  cmd.InOrStdin(),
+				Stdout: cmd.OutOrStdout(),
+				Stderr: cmd.ErrOrStderr(),
+			})
+			if ctxErr := cmd.Context().Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if err != nil {
+				return err
+			}
+			if code != 0 {
+				return &common.ExitCodeError{Code: code}
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+// serviceContainer returns the first running container of a service, by name,
+// or "" when none is running.
+func serviceContainer(cmd *cobra.Command, docker *dockercli.Client, identifier, project, service string) (string, error) {
+	filters := []string{
+		"label=com.docker.compose.project=" + project,
+		"label=com.docker.compose.service=" + service,
+	}
+	if identifier != "" {
+		filters = append(filters, "label="+dockercli.LabelIdentifier+"="+identifier)
+	}
+	rows, err := docker.PsJSON(cmd.Context(), false, filters)
+	if err != nil {
+		return "", apperr.Wrap("cli.exec", apperr.External, err, "list containers for project %s", project)
+	}
+	names := make([]string, 0, len(rows))
+	for _, r := range rows {
+		if name := strings.TrimSpace(r.Names); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "", nil
+	}
+	sort.Strings(names)
+	return names[0], nil
+}
@@ -0,0 +1,92 @@
+package execcmd_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/cli"
+	"github.com/gcstr/dockform/internal/cli/clitest"
+	"github.com/gcstr/dockform/internal/cli/common"
+)
+
+// execStub answers `docker ps` with two replicas of the web service, reports
+// them as managed by the demo identifier and exits 7 from `docker exec`,
+// recording the ps and exec invocations.
+func execStub(t *testing.T) (string, func()) {
+	t.Helper()
+	argsLog := filepath.Join(t.TempDir(), "exec-args")
+	undo := clitest.WithCustomDockerStub(t, `#!/bin/sh
+cmd="$1"; shift
+case "$cmd" in
+  ps)
+    echo "ps $*" >> "`+argsLog+`"
+    echo '{"Names":"website-web-2","State":"running"}'
+    echo '{"Names":"website-web-1","State":"running"}'
+    exit 0 ;;
+  inspect)
+    echo 'abc {"io.dockform.identifier":"demo"}'
+    exit 0 ;;
+  exec)
+    echo "exec $*" >> "`+argsLog+`"
+    echo "hello from $*"
+    exit 7 ;;
+esac
+exit 0
+`)
+	return argsLog, undo
+}
+
+func TestExec_RunsInFirstReplicaAndPassesExitCode(t *testing.T) {
+	argsLog, undo := execStub(t)
+	defer undo()
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"exec", "default/website/web", "--manifest", clitest.BasicConfigPath(t), "--", "sh", "-c", "true"})
+	err := root.Execute()
+	var exitErr *common.ExitCodeError
+	if !errors.As(err, &exitErr) || exitErr.Code != 7 {
+		t.Fatalf("expected exit code 7, got %v\n%s", err, out.String())
+	}
+	b, rerr := os.ReadFile(argsLog)
+	if rerr != nil {
+		t.Fatalf("read args log: %v", rerr)
+	}
+	got := string(b)
+	for _, want := range []string{"label=com.docker.compose.service=web", "label=io.dockform.identifier=demo", "exec -i website-web-1 sh -c true"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in docker invocations; got:\n%s", want, got)
+		}
+	}
+	if !strings.Contains(out.String(), "hello from -i website-web-1 sh -c true") {
+		t.Fatalf("expected command output to be streamed; got:\n%s", out.String())
+	}
+}
+
+func TestExec_RequiresServiceAndCommand(t *testing.T) {
+	_, undo := execStub(t)
+	defer undo()
+
+	for _, tt := range []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"website/web", "sh"}, want: "usage: dockform exec"},
+		{args: []string{"website", "--", "sh"}, want: "no service given for default/website"},
+	} {
+		root := cli.TestNewRootCmd()
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetErr(&out)
+		root.SetArgs(append([]string{"exec", "--manifest", clitest.BasicConfigPath(t)}, tt.args...))
+		if err := root.Execute(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Fatalf("expected %q for %v, got %v", tt.want, tt.args, err)
+		}
+	}
+}
@@ -100,7 +100,7 @@ func runCheck(cmd *cobra.Command, args []string) error {
 	common.DisplayDaemonInfo(pr, cfg)
 
 	// Create client factory for multi-context support.
-	factory := common.CreateClientFactory()
+	factory := common.CreateClientFactory(cmd)
 
 	// imagescmd doesn't use SetupCLIContext, so probe context reachability here.
 	if err := common.EnsureContextsReachable(cmd.Context(), cfg, factory); err != nil {
@@ -190,7 +190,7 @@ func buildCheckInputs(ctx context.Context, cfg *manifest.Config, factory *docker
 		client := factory.GetClientForContext(ctxName, cfg)
 
 		// Get the full compose config to extract service images.
-		doc, err := client.ComposeConfigFull(ctx, stack.RootAbs, stack.Files, stack.Profiles, stack.EnvFile, stack.EnvInline)
+		doc, err := client.ComposeConfigFull(dockercli.WithStack(ctx, stack), stack.RootAbs, stack.Files, stack.Profiles, stack.EnvFile, stack.EnvInline)
 		if err != nil {
 			return nil, apperr.Wrap("imagescmd.buildCheckInputs", apperr.External, err, "failed to get compose config for stack %s", stackKey)
 		}
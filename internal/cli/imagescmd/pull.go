@@ -57,7 +57,7 @@ func runPull(cmd *cobra.Command, args []string) error {
 
 	common.DisplayDaemonInfo(pr, cfg)
 
-	factory := common.CreateClientFactory()
+	factory := common.CreateClientFactory(cmd)
 
 	// imagescmd doesn't use SetupCLIContext, so probe context reachability here.
 	if err := common.EnsureContextsReachable(cmd.Context(), cfg, factory); err != nil {
@@ -176,12 +176,12 @@ func executePull(ctx context.Context, stale []images.ImageStatus, allStacks map[
 			projName = g.stack.Project.Name
 		}
 
-		if _, err := client.ComposePull(ctx, g.stack.RootAbs, g.stack.Files, g.stack.Profiles, g.stack.EnvFile, projName, g.services, g.stack.EnvInline); err != nil {
+		if _, err := client.ComposePull(dockercli.WithStack(ctx, g.stack), g.stack.RootAbs, g.stack.Files, g.stack.Profiles, g.stack.EnvFile, projName, g.services, g.stack.EnvInline); err != nil {
 			return err
 		}
 
 		if recreate {
-			if _, err := client.ComposeUp(ctx, g.stack.RootAbs, g.stack.Files, g.stack.Profiles, g.stack.EnvFile, projName, g.stack.EnvInline); err != nil {
+			if _, err := client.ComposeUp(dockercli.WithStack(ctx, g.stack), g.stack.RootAbs, g.stack.Files, g.stack.Profiles, g.stack.EnvFile, projName, g.stack.EnvInline); err != nil {
 				return err
 			}
 		}
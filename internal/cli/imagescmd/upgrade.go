@@ -57,7 +57,7 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 	common.DisplayDaemonInfo(pr, cfg)
 
 	// Create client factory for multi-context support.
-	factory := common.CreateClientFactory()
+	factory := common.CreateClientFactory(cmd)
 
 	// imagescmd doesn't use SetupCLIContext, so probe context reachability here.
 	if err := common.EnsureContextsReachable(cmd.Context(), cfg, factory); err != nil {
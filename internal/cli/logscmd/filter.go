@@ -0,0 +1,104 @@
+package logscmd
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/dockercli"
+)
+
+// lineFilter decides which log lines are printed. A line is kept when it
+// matches include (if set) and does not match exclude (if set).
+type lineFilter struct {
+	include *regexp.Regexp
+	exclude *regexp.Regexp
+	// timestamps means each line starts with a docker timestamp, which is
+	// skipped when matching so anchored patterns still work.
+	timestamps bool
+}
+
+func newLineFilter(grep, grepV string, timestamps bool) (lineFilter, error) {
+	f := lineFilter{timestamps: timestamps}
+	var err error
+	if grep != "" {
+		if f.include, err = regexp.Compile(grep); err != nil {
+			return f, apperr.Wrap("cli.logs", apperr.InvalidInput, err, "invalid --grep pattern %q", grep)
+		}
+	}
+	if grepV != "" {
+		if f.exclude, err = regexp.Compile(grepV); err != nil {
+			return f, apperr.Wrap("cli.logs", apperr.InvalidInput, err, "invalid --grep-v pattern %q", grepV)
+		}
+	}
+	return f, nil
+}
+
+func (f lineFilter) keep(line string) bool {
+	if line == dockercli.ContainerRecreatedMarker || line == dockercli.ContainerRestartedMarker {
+		return true
+	}
+	msg := line
+	if f.timestamps {
+		if i := strings.IndexByte(msg, ' '); i >= 0 {
+			msg = msg[i+1:]
+		}
+	}
+	if f.include != nil && !f.include.MatchString(msg) {
+		return false
+	}
+	if f.exclude != nil && f.exclude.MatchString(msg) {
+		return false
+	}
+	return true
+}
+
+// lineWriter buffers one container's log stream into whole lines, filters
+// them and writes the survivors to out with the container prefix. Writers for
+// several containers share mu so their lines never interleave mid-line.
+type lineWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	filter lineFilter
+	buf    []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := strings.TrimSuffix(string(w.buf[:i]), "\r")
+		w.buf = w.buf[i+1:]
+		if err := w.emit(line); err != nil {
+			return len(p), err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush emits a trailing line that was not newline-terminated.
+func (w *lineWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	line := string(w.buf)
+	w.buf = nil
+	return w.emit(line)
+}
+
+func (w *lineWriter) emit(line string) error {
+	if !w.filter.keep(line) {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err := io.WriteString(w.out, w.prefix+line+"\n")
+	return err
+}
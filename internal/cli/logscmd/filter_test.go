@@ -0,0 +1,58 @@
+package logscmd
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+)
+
+func TestLineWriter_FiltersWholeLinesAcrossWrites(t *testing.T) {
+	filter, err := newLineFilter("^ERROR", "ignored", true)
+	if err != nil {
+		t.Fatalf("filter: %v", err)
+	}
+	var out bytes.Buffer
+	w := &lineWriter{mu: &sync.Mutex{}, out: &out, prefix: "web-1 | ", filter: filter}
+
+	chunks := []string{
+		"2024-01-01T00:00:00Z ERR",
+		"OR disk full\n2024-01-01T00:00:01Z INFO ok\n",
+		"2024-01-01T00:00:02Z ERROR ignored\n2024-01-01T00:00:03Z ERROR tail",
+	}
+	for _, c := range chunks {
+		if _, err := w.Write([]byte(c)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	want := "web-1 | 2024-01-01T00:00:00Z ERROR disk full\nweb-1 | 2024-01-01T00:00:03Z ERROR tail\n"
+	if out.String() != want {
+		t.Fatalf("unexpected output:\n%q\nwant:\n%q", out.String(), want)
+	}
+}
+
+func TestNewLineFilter_RejectsInvalidPattern(t *testing.T) {
+	if _, err := newLineFilter("(", "", false); err == nil {
+		t.Fatalf("expected error for invalid --grep pattern")
+	}
+	if _, err := newLineFilter("", "[", false); err == nil {
+		t.Fatalf("expected error for invalid --grep-v pattern")
+	}
+}
+
+func TestLineFilter_KeepsRecreatedMarker(t *testing.T) {
+	filter, err := newLineFilter("^ERROR", "", true)
+	if err != nil {
+		t.Fatalf("filter: %v", err)
+	}
+	if !filter.keep(dockercli.ContainerRecreatedMarker) {
+		t.Fatalf("expected the recreated marker to survive --grep")
+	}
+	if !filter.keep(dockercli.ContainerRestartedMarker) {
+		t.Fatalf("expected the restarted marker to survive --grep")
+	}
+}
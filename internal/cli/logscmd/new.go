@@ -0,0 +1,150 @@
+package logscmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/cli/common"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+	"github.com/gcstr/dockform/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// New creates the `logs` command.
+func New() *cobra.Command {
+	var opts dockercli.LogsOptions
+	var grep, grepV string
+	cmd := &cobra.Command{
+		Use:   "logs <[context/]stack>[/service] | <[context/]stack> [service]",
+		Short: "Print the logs of a stack's containers",
+		Long: `Print the logs of a stack's containers without the dashboard.
+
+The service may be given as a second argument or appended to the stack, as in
+"website/web" or "hetzner/website/web".
+
+Lines are prefixed with the container name when more than one container is
+shown. --grep keeps only lines matching a regular expression and --grep-v drops
+lines matching one; both apply to whole lines as they stream, after any
+--timestamps prefix, so partial lines are never matched.
+
+With --follow, a container restarted while streaming is picked up again after
+a "── container restarted ──" line, and one recreated (e.g. by an apply) after a
+"── container recreated ──" line, with the new container's logs. Following
+stops once a container stays stopped or is removed without a replacement.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filter, err := newLineFilter(grep, grepV, opts.Timestamps)
+			if err != nil {
+				return err
+			}
+			pr := ui.StdPrinter{Out: cmd.OutOrStdout(), Err: cmd.ErrOrStderr()}
+			cfg, err := common.LoadConfigWithWarnings(cmd, pr)
+			if err != nil {
+				return err
+			}
+			stackKey, stack, service, err := common.ResolveStackService(cfg, args)
+			if err != nil {
+				return err
+			}
+			contextName, stackName, err := manifest.ParseStackKey(stackKey)
+			if err != nil {
+				return err
+			}
+			docker := common.CreateClientFactory(cmd).GetClientForContext(contextName, cfg)
+
+			names, err := stackContainers(cmd.Context(), docker, cfg.Identifier, common.StackProjectName(stackName, stack), service)
+			if err != nil {
+				return err
+			}
+			if len(names) == 0 {
+				target := stackKey
+				if service != "" {
+					target += "/" + service
+				}
+				return apperr.New("cli.logs", apperr.NotFound, "no containers found for %s", target)
+			}
+			return streamLogs(cmd.Context(), docker, names, opts, filter, cmd.OutOrStdout())
+		},
+	}
+	cmd.Flags().BoolVarP(&opts.Follow, "follow", "f", false, "Follow log output")
+	cmd.Flags().IntVar(&opts.Tail, "tail", 0, "Number of lines to show from the end of each container's logs (0 = all)")
+	cmd.Flags().StringVar(&opts.Since, "since", "", "Show logs since a timestamp or relative duration (e.g. 10m)")
+	cmd.Flags().BoolVar(&opts.Timestamps, "timestamps", false, "Prefix each line with the container timestamp")
+	cmd.Flags().StringVar(&grep, "grep", "", "Only print lines matching this regular expression")
+	cmd.Flags().StringVar(&grepV, "grep-v", "", "Skip lines matching this regular expression")
+	return cmd
+}
+
+// stackContainers lists the containers of a compose project, optionally
+// limited to one service, sorted by name.
+func stackContainers(ctx context.Context, docker *dockercli.Client, identifier, project, service string) ([]string, error) {
+	filters := []string{"label=com.docker.compose.project=" + project}
+	if identifier != "" {
+		filters = append(filters, "label="+dockercli.LabelIdentifier+"="+identifier)
+	}
+	if service != "" {
+		filters = append(filters, "label=com.docker.compose.service="+service)
+	}
+	rows, err := docker.PsJSON(ctx, true, filters)
+	if err != nil {
+		return nil, apperr.Wrap("cli.logs", apperr.External, err, "list containers for project %s", project)
+	}
+	names := make([]string, 0, len(rows))
+	for _, r := range rows {
+		if name := strings.TrimSpace(r.Names); name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// streamLogs streams every container's logs concurrently through its own
+// filtering line writer. With several containers each line carries the
+// container name, padded so the log text lines up.
+func streamLogs(ctx context.Context, docker *dockercli.Client, names []string, opts dockercli.LogsOptions, filter lineFilter, out io.Writer) error {
+	width := 0
+	for _, n := range names {
+		width = max(width, len(n))
+	}
+	var mu sync.Mutex
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		w := &lineWriter{mu: &mu, out: out, filter: filter}
+		if len(names) > 1 {
+			w.prefix = fmt.Sprintf("%-*s | ", width, name)
+		}
+		wg.Add(1)
+		go func(i int, name string, w *lineWriter) {
+			defer wg.Done()
+			err := docker.FollowContainerLogs(ctx, name, opts, w)
+			if ferr := w.Flush(); err == nil {
+				err = ferr
+			}
+			if err != nil && ctx.Err() == nil {
+				errs[i] = apperr.Wrap("cli.logs", apperr.External, err, "logs for %s", name)
+			}
+		}(i, name, w)
+	}
+	wg.Wait()
+	var failed []error
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	if len(failed) == 1 {
+		return failed[0]
+	}
+	if len(failed) > 1 {
+		return apperr.Aggregate("cli.logs", apperr.External, fmt.Sprintf("failed to read logs for %d containers", len(failed)), failed...)
+	}
+	return nil
+}
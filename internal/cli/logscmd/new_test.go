@@ -0,0 +1,135 @@
+package logscmd_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/cli"
+	"github.com/gcstr/dockform/internal/cli/clitest"
+)
+
+// logsStub answers `docker ps` with two replicas of the web service and prints
+// a few log lines per container (the last without a trailing newline),
+// recording the ps and logs invocations.
+func logsStub(t *testing.T) (string, func()) {
+	t.Helper()
+	argsLog := filepath.Join(t.TempDir(), "logs-args")
+	undo := clitest.WithCustomDockerStub(t, `#!/bin/sh
+cmd="$1"; shift
+case "$cmd" in
+  ps)
+    echo "ps $*" >> "`+argsLog+`"
+    echo '{"Names":"website-web-2","State":"running"}'
+    echo '{"Names":"website-web-1","State":"running"}'
+    exit 0 ;;
+  logs)
+    echo "$*" >> "`+argsLog+`"
+    ts=""
+    for a in "$@"; do [ "$a" = "--timestamps" ] && ts="2024-01-01T00:00:00Z "; done
+    for last; do :; done
+    echo "${ts}GET /health 200"
+    echo "${ts}ERROR upstream $last"
+    printf "${ts}GET /partial 500"
+    exit 0 ;;
+esac
+exit 0
+`)
+	return argsLog, undo
+}
+
+func TestLogs_GrepKeepsPrefixPerReplica(t *testing.T) {
+	argsLog, undo := logsStub(t)
+	defer undo()
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"logs", "website", "web", "--grep", "^ERROR|500$", "--timestamps", "--manifest", clitest.BasicConfigPath(t)})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("logs execute: %v\n%s", err, out.String())
+	}
+	got := out.String()
+	for _, want := range []string{
+		"website-web-1 | 2024-01-01T00:00:00Z ERROR upstream website-web-1\n",
+		"website-web-2 | 2024-01-01T00:00:00Z ERROR upstream website-web-2\n",
+		"website-web-1 | 2024-01-01T00:00:00Z GET /partial 500\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in output; got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "/health") {
+		t.Fatalf("expected non-matching lines to be filtered; got:\n%s", got)
+	}
+	b, err := os.ReadFile(argsLog)
+	if err != nil {
+		t.Fatalf("read args log: %v", err)
+	}
+	if !strings.Contains(string(b), "--timestamps website-web-1") {
+		t.Fatalf("expected --timestamps to be passed to docker logs; got: %s", b)
+	}
+}
+
+func TestLogs_GrepVInvertsMatch(t *testing.T) {
+	_, undo := logsStub(t)
+	defer undo()
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"logs", "default/website", "--grep-v", "^GET", "--manifest", clitest.BasicConfigPath(t)})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("logs execute: %v\n%s", err, out.String())
+	}
+	got := out.String()
+	if strings.Contains(got, "GET ") || !strings.Contains(got, "ERROR upstream website-web-2") {
+		t.Fatalf("expected only non-GET lines; got:\n%s", got)
+	}
+}
+
+func TestLogs_UnknownStack(t *testing.T) {
+	_, undo := logsStub(t)
+	defer undo()
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"logs", "missing", "--manifest", clitest.BasicConfigPath(t)})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), `unknown stack "missing"`) {
+		t.Fatalf("expected unknown stack error, got: %v", err)
+	}
+}
+
+func TestLogs_StackSlashService(t *testing.T) {
+	for _, target := range []string{"website/web", "default/website/web"} {
+		t.Run(target, func(t *testing.T) {
+			argsLog, undo := logsStub(t)
+			defer undo()
+
+			root := cli.TestNewRootCmd()
+			var out bytes.Buffer
+			root.SetOut(&out)
+			root.SetErr(&out)
+			root.SetArgs([]string{"logs", target, "--tail", "5", "--since", "10m", "--manifest", clitest.BasicConfigPath(t)})
+			if err := root.Execute(); err != nil {
+				t.Fatalf("logs execute: %v\n%s", err, out.String())
+			}
+			b, err := os.ReadFile(argsLog)
+			if err != nil {
+				t.Fatalf("read args log: %v", err)
+			}
+			got := string(b)
+			for _, want := range []string{"label=com.docker.compose.project=website", "label=com.docker.compose.service=web", "--tail 5", "--since 10m"} {
+				if !strings.Contains(got, want) {
+					t.Fatalf("expected %q in docker invocations; got:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
@@ -115,3 +115,37 @@ func TestManifestCommandIncludesRender(t *testing.T) {
 		t.Fatalf("expected manifest command to include render subcommand")
 	}
 }
+
+func TestManifest_Diff_ViaConfigAlias(t *testing.T) {
+	left := clitest.BasicConfigPath(t)
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "website"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "website", "docker-compose.yaml"), []byte("services: {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	right := filepath.Join(dir, "dockform.yml")
+	manifestBody := "identifier: prod\ncontexts:\n  default: {}\nstacks:\n  default/website:\n    root: website\n    files:\n      - docker-compose.yaml\n    profiles: [web]\n"
+	if err := os.WriteFile(right, []byte(manifestBody), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"config", "diff", right, "--manifest", left})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("config diff: %v\n%s", err, out.String())
+	}
+	got := out.String()
+	for _, want := range []string{"~ identifier: demo -> prod", "+ stacks.default/website.profiles[0]: web"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "root") {
+		t.Errorf("stack roots resolve to the same relative path and must not differ:\n%s", got)
+	}
+}
@@ -1,6 +1,8 @@
 package manifestcmd
 
 import (
+	"fmt"
+
 	"github.com/gcstr/dockform/internal/cli/common"
 	"github.com/gcstr/dockform/internal/manifest"
 	"github.com/gcstr/dockform/internal/ui"
@@ -10,11 +12,13 @@ import (
 // New creates the top-level `manifest` command and wires subcommands
 func New() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "manifest",
-		Short: "Work with the manifest file",
+		Use:     "manifest",
+		Aliases: []string{"config"},
+		Short:   "Work with the manifest file",
 	}
 
 	cmd.AddCommand(newRenderCmd())
+	cmd.AddCommand(newDiffCmd())
 	return cmd
 }
 
@@ -49,3 +53,66 @@ func newRenderCmd() *cobra.Command {
 	}
 	return cmd
 }
+
+func newDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <other-manifest>",
+		Short: "Compare the manifest with another one, e.g. staging against prod",
+		Long: `Compare the manifest with another one, e.g. staging against prod.
+
+Both manifests are loaded the way plan and apply load them: environment
+variables are interpolated, stacks and filesets are discovered and merged
+with their overrides, and defaults are applied. The effective settings are
+then compared one by one. Paths are shown relative to each manifest's
+directory and secret-looking values are masked.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pr := ui.StdPrinter{Out: cmd.OutOrStdout(), Err: cmd.ErrOrStderr()}
+			file, err := common.ResolveManifestPath(cmd, pr, ".", 3)
+			if err != nil {
+				return err
+			}
+			left, missing, err := manifest.LoadWithWarnings(file)
+			if err != nil {
+				return err
+			}
+			for _, name := range missing {
+				pr.Warn("environment variable %s is not set; replacing with empty string", name)
+			}
+			right, missing, err := manifest.LoadWithWarnings(args[0])
+			if err != nil {
+				return err
+			}
+			for _, name := range missing {
+				pr.Warn("%s: environment variable %s is not set; replacing with empty string", args[0], name)
+			}
+
+			entries, err := manifest.Diff(left, right)
+			if err != nil {
+				return err
+			}
+			if len(entries) == 0 {
+				pr.Plain("No differences")
+				return nil
+			}
+			for _, e := range entries {
+				pr.Plain("%s", formatDiffEntry(e))
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+// formatDiffEntry renders one difference: "+" for settings only the other
+// manifest has, "-" for settings it lacks and "~" for changed values.
+func formatDiffEntry(e manifest.DiffEntry) string {
+	switch e.Kind {
+	case manifest.DiffAdded:
+		return fmt.Sprintf("+ %s: %s", e.Path, e.New)
+	case manifest.DiffRemoved:
+		return fmt.Sprintf("- %s: %s", e.Path, e.Old)
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", e.Path, e.Old, e.New)
+	}
+}
@@ -0,0 +1,120 @@
+package plancmd
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/planner"
+)
+
+// jsonSchemaVersion is bumped whenever a field of the --output json document
+// is renamed, removed or changes meaning. Adding fields does not bump it.
+const jsonSchemaVersion = 1
+
+// jsonPlan is the document written by `plan --output json`.
+type jsonPlan struct {
+	SchemaVersion int            `json:"schema_version"`
+	Resources     []jsonResource `json:"resources"`
+	Warnings      []string       `json:"warnings"`
+	Summary       jsonSummary    `json:"summary"`
+}
+
+// jsonResource is one planned resource. Parent names the stack of a service
+// or the fileset of a file; Action is one of create, update, delete,
+// reconcile or noop.
+type jsonResource struct {
+	Context string `json:"context"`
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Parent  string `json:"parent,omitempty"`
+	Action  string `json:"action"`
+	Detail  string `json:"detail,omitempty"`
+	Problem string `json:"problem,omitempty"`
+}
+
+type jsonSummary struct {
+	Create int `json:"create"`
+	Update int `json:"update"`
+	Delete int `json:"delete"`
+}
+
+// writePlanJSON writes plan to w as a single indented JSON document. Resources
+// are listed per context in plan order: volumes, networks, services by stack,
+// orphaned containers, then files by fileset.
+func writePlanJSON(w io.Writer, plan *planner.Plan) error {
+	doc := jsonPlan{SchemaVersion: jsonSchemaVersion, Resources: []jsonResource{}, Warnings: []string{}}
+	if plan != nil && plan.Resources != nil {
+		create, update, del := plan.CountChanges()
+		doc.Summary = jsonSummary{Create: create, Update: update, Delete: del}
+		doc.Warnings = append(doc.Warnings, plan.Resources.Warnings...)
+		if len(plan.ByContext) == 0 {
+			doc.Resources = appendJSONResources(doc.Resources, "", plan.Resources)
+		}
+		for _, name := range plan.GetContextNames() {
+			if cp := plan.ByContext[name]; cp != nil && cp.Resources != nil {
+				doc.Resources = appendJSONResources(doc.Resources, name, cp.Resources)
+			}
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return apperr.Wrap("plancmd.writePlanJSON", apperr.Internal, err, "failed to encode JSON output")
+	}
+	return nil
+}
+
+func appendJSONResources(out []jsonResource, contextName string, rp *planner.ResourcePlan) []jsonResource {
+	add := func(parent string, res planner.Resource) {
+		out = append(out, jsonResource{
+			Context: contextName,
+			Kind:    string(res.Type),
+			Name:    res.Name,
+			Parent:  parent,
+			Action:  jsonAction(res.Action),
+			Detail:  res.Details,
+			Problem: res.Problem,
+		})
+	}
+	for _, res := range rp.Volumes {
+		add("", res)
+	}
+	for _, res := range rp.Networks {
+		add("", res)
+	}
+	for _, stack := range sortedKeys(rp.Stacks) {
+		for _, res := range rp.Stacks[stack] {
+			add(stack, res)
+		}
+	}
+	for _, res := range rp.Containers {
+		add(res.Parent, res)
+	}
+	for _, fileset := range sortedKeys(rp.Filesets) {
+		for _, res := range rp.Filesets[fileset] {
+			add(fileset, res)
+		}
+	}
+	return out
+}
+
+// jsonAction spells the planner's no-op action without the hyphen so every
+// action in the schema is a plain identifier.
+func jsonAction(a planner.Action) string {
+	if a == planner.ActionNoop {
+		return "noop"
+	}
+	return string(a)
+}
+
+func sortedKeys(m map[string][]planner.Resource) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -2,7 +2,14 @@ package plancmd
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/gcstr/dockform/internal/apperr"
 	"github.com/gcstr/dockform/internal/cli/common"
 	"github.com/gcstr/dockform/internal/planner"
 	"github.com/gcstr/dockform/internal/ui"
@@ -14,7 +21,43 @@ func New() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "plan",
 		Short: "Show the plan to reach the desired state",
-		RunE: func(cmd *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			// With --detailed-exitcode, errors exit 1 and a plan with changes
+			// exits 2, so CI can tell drift from failure without parsing output.
+			detailed, _ := cmd.Flags().GetBool("detailed-exitcode")
+			if detailed {
+				defer func() { err = detailedExitError(err) }()
+			}
+			failOn, _ := cmd.Flags().GetStringSlice("fail-on")
+			if err := validateFailOn(failOn); err != nil {
+				return err
+			}
+			groupBy, _ := cmd.Flags().GetString("group-by")
+			if err := validateGroupBy(groupBy); err != nil {
+				return err
+			}
+			watch, _ := cmd.Flags().GetBool("watch")
+			interval, _ := cmd.Flags().GetDuration("interval")
+			onceOnChange, _ := cmd.Flags().GetBool("once-on-change")
+			if err := validateWatch(watch, interval, onceOnChange, failOn); err != nil {
+				return err
+			}
+			if watch && detailed {
+				return apperr.New("cli.plan", apperr.InvalidInput, "--detailed-exitcode cannot be combined with --watch; use --once-on-change")
+			}
+			output, _ := cmd.Flags().GetString("output")
+			if err := validateOutput(output, watch); err != nil {
+				return err
+			}
+
+			// --output json keeps stdout for the JSON document alone. With
+			// stdout discarded the banner, spinners and borders go nowhere and
+			// the manifest picker, which needs a terminal, never shows.
+			stdout := cmd.OutOrStdout()
+			if output == "json" {
+				cmd.SetOut(io.Discard)
+			}
+
 			// Setup CLI context with all standard initialization
 			ctx, err := common.SetupCLIContext(cmd)
 			if err != nil {
@@ -27,17 +70,61 @@ func New() *cobra.Command {
 				ctx.Planner = ctx.Planner.WithParallel(false)
 			}
 
+			// --parallel-filesets bounds how many filesets are indexed at once.
+			parallelFilesets, _ := cmd.Flags().GetInt("parallel-filesets")
+			if parallelFilesets < 1 {
+				return apperr.New("cli.plan", apperr.InvalidInput, "--parallel-filesets must be at least 1")
+			}
+			ctx.Planner = ctx.Planner.WithFilesetParallelism(parallelFilesets)
+
+			if checkImages, _ := cmd.Flags().GetBool("check-images"); checkImages {
+				ctx.Planner = ctx.Planner.WithCheckImages(true)
+			}
+
+			if noFilesetRead, _ := cmd.Flags().GetBool("no-fileset-read"); noFilesetRead {
+				ctx.Planner = ctx.Planner.WithSkipFilesetRead(true)
+			}
+
+			if fileset, _ := cmd.Flags().GetString("fileset"); fileset != "" {
+				ctx.Planner = ctx.Planner.WithPlanFileset(fileset)
+			}
+
 			long, _ := cmd.Flags().GetBool("long")
 			renderOpts := planner.PlanRenderOptions{Full: long}
+			render := func(plan *planner.Plan) string {
+				if groupBy == "daemon" {
+					return plan.RenderByContext(renderOpts, func(contextName string) string {
+						return common.ContextHeader(ctx.Config, contextName)
+					})
+				}
+				return plan.Render(renderOpts)
+			}
+
+			if watch {
+				return watchPlan(ctx, watchOptions{interval: interval, onceOnChange: onceOnChange, render: render})
+			}
+
+			if output == "json" {
+				plan, err := ctx.BuildPlan()
+				if err != nil {
+					return err
+				}
+				if err := writePlanJSON(stdout, plan); err != nil {
+					return err
+				}
+				return finishPlan(plan, failOn, detailed)
+			}
 
 			// Build plan normally
+			var builtPlan *planner.Plan
 			verbose, _ := cmd.Flags().GetBool("verbose")
 			if verbose {
 				plan, err := ctx.BuildPlan()
 				if err != nil {
 					return err
 				}
-				ctx.Printer.Plain("%s", plan.Render(renderOpts))
+				builtPlan = plan
+				ctx.Printer.Plain("%s", render(plan))
 			} else {
 				var out string
 				_, err = ui.RunWithRollingLog(cmd.Context(), func(runCtx context.Context) (string, error) {
@@ -57,7 +144,8 @@ func New() *cobra.Command {
 							return runCtx.Err()
 						}
 
-						out = plan.Render(renderOpts)
+						builtPlan = plan
+						out = render(plan)
 						return nil
 					})
 				})
@@ -66,18 +154,136 @@ func New() *cobra.Command {
 				}
 				ctx.Printer.Plain("%s", out)
 			}
-			return nil
+			return finishPlan(builtPlan, failOn, detailed)
 		},
 	}
 
 	// Add sequential flag
 	cmd.Flags().Bool("sequential", false, "Use sequential processing instead of the default parallel processing (slower but uses less CPU and Docker daemon resources)")
+	cmd.Flags().Int("parallel-filesets", planner.DefaultFilesetParallelism, "Maximum number of filesets indexed concurrently while planning and syncing")
 
 	// Add long flag
 	cmd.Flags().Bool("long", false, "Show the full plan including unchanged resources")
 
+	// Add image availability check
+	cmd.Flags().Bool("check-images", false, "Check that the images of services to be started are present or pullable (via docker manifest inspect, without pulling)")
+
+	// Skip remote fileset index reads
+	cmd.Flags().Bool("no-fileset-read", false, "Skip reading the remote fileset indexes (helper containers); filesets whose volume exists are reported as changes unknown")
+
+	// Plan a single fileset
+	cmd.Flags().String("fileset", "", "Plan only the named fileset's content, skipping all other resources")
+
+	// Add fail-on guard
+	cmd.Flags().StringSlice("fail-on", nil, "Exit non-zero if the plan contains any action of the given kind (create, update, delete); repeatable")
+
+	// Add machine-readable output
+	cmd.Flags().String("output", "text", "Output format: \"text\" or \"json\" (a versioned document on stdout, without spinners or borders)")
+
+	// Add CI exit codes
+	cmd.Flags().Bool("detailed-exitcode", false, "Exit 0 when there are no changes, 2 when the plan has changes, and 1 on errors")
+
+	// Add grouping
+	cmd.Flags().String("group-by", "", "Group plan output; \"daemon\" shows each context's resources under its own header")
+
+	// Add drift monitoring
+	cmd.Flags().Bool("watch", false, "Re-run the plan every --interval and print a timestamped drift summary, without applying")
+	cmd.Flags().Duration("interval", time.Minute, "Time between plans with --watch")
+	cmd.Flags().Bool("once-on-change", false, "With --watch, exit non-zero the first time drift is detected")
+
 	// Add targeting flags
 	common.AddTargetFlags(cmd)
 
 	return cmd
 }
+
+func validateGroupBy(groupBy string) error {
+	if groupBy != "" && groupBy != "daemon" {
+		return apperr.New("cli.plan", apperr.InvalidInput, "invalid --group-by value %q (expected: daemon)", groupBy)
+	}
+	return nil
+}
+
+func validateOutput(output string, watch bool) error {
+	switch output {
+	case "text":
+		return nil
+	case "json":
+		if watch {
+			return apperr.New("cli.plan", apperr.InvalidInput, "--output json cannot be combined with --watch")
+		}
+		return nil
+	}
+	return apperr.New("cli.plan", apperr.InvalidInput, "invalid --output value %q (expected: text, json)", output)
+}
+
+func validateWatch(watch bool, interval time.Duration, onceOnChange bool, failOn []string) error {
+	if !watch {
+		if onceOnChange {
+			return apperr.New("cli.plan", apperr.InvalidInput, "--once-on-change requires --watch")
+		}
+		return nil
+	}
+	if interval <= 0 {
+		return apperr.New("cli.plan", apperr.InvalidInput, "--interval must be positive")
+	}
+	if len(failOn) > 0 {
+		return apperr.New("cli.plan", apperr.InvalidInput, "--fail-on cannot be combined with --watch; use --once-on-change")
+	}
+	return nil
+}
+
+// failOnKinds are the plan action kinds accepted by --fail-on.
+var failOnKinds = []string{"create", "update", "delete"}
+
+func validateFailOn(kinds []string) error {
+	for _, k := range kinds {
+		if !slices.Contains(failOnKinds, k) {
+			return apperr.New("cli.plan", apperr.InvalidInput, "invalid --fail-on value %q (expected one of: %s)", k, strings.Join(failOnKinds, ", "))
+		}
+	}
+	return nil
+}
+
+// finishPlan applies the exit-status checks after a plan was printed: the
+// --fail-on guard, then, with detailed, exit code 2 when anything changes.
+func finishPlan(plan *planner.Plan, failOn []string, detailed bool) error {
+	if err := checkFailOn(plan, failOn); err != nil {
+		return err
+	}
+	if detailed && plan != nil && plan.Resources != nil && plan.Resources.HasChanges() {
+		return &common.ExitCodeError{Code: 2}
+	}
+	return nil
+}
+
+// detailedExitError maps any plan failure to exit code 1, keeping interrupts
+// and exit codes already chosen as they are.
+func detailedExitError(err error) error {
+	var exitErr *common.ExitCodeError
+	if err == nil || errors.Is(err, context.Canceled) || errors.As(err, &exitErr) {
+		return err
+	}
+	return &common.ExitCodeError{Code: 1, Err: err}
+}
+
+// checkFailOn returns an error when the plan contains actions of any of the
+// requested kinds, so CI pipelines can block e.g. destructive changes.
+func checkFailOn(plan *planner.Plan, kinds []string) error {
+	if len(kinds) == 0 || plan == nil || plan.Resources == nil {
+		return nil
+	}
+	create, update, del := plan.Resources.CountActions()
+	counts := map[string]int{"create": create, "update": update, "delete": del}
+
+	var hits []string
+	for _, k := range failOnKinds {
+		if slices.Contains(kinds, k) && counts[k] > 0 {
+			hits = append(hits, fmt.Sprintf("%d to %s", counts[k], k))
+		}
+	}
+	if len(hits) == 0 {
+		return nil
+	}
+	return apperr.New("cli.plan", apperr.Precondition, "plan contains disallowed actions (--fail-on %s): %s", strings.Join(kinds, ","), strings.Join(hits, ", "))
+}
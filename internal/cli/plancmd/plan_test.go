@@ -2,11 +2,14 @@ package plancmd_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 
 	"github.com/gcstr/dockform/internal/cli"
 	"github.com/gcstr/dockform/internal/cli/clitest"
+	"github.com/gcstr/dockform/internal/cli/common"
 )
 
 func TestPlan_PrintsPlan_WhenRemovalsPresent(t *testing.T) {
@@ -82,3 +85,176 @@ func TestPlan_InvalidConfigPath_ReturnsError(t *testing.T) {
 		t.Fatalf("expected error for invalid config path, got nil")
 	}
 }
+
+func TestPlan_FailOn(t *testing.T) {
+	defer clitest.WithStubDocker(t)()
+
+	run := func(args ...string) (string, error) {
+		root := cli.TestNewRootCmd()
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetErr(&out)
+		root.SetArgs(append([]string{"plan", "--manifest", clitest.BasicConfigPath(t)}, args...))
+		err := root.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("--fail-on", "delete")
+	if err == nil || !strings.Contains(err.Error(), "1 to delete") {
+		t.Fatalf("expected --fail-on delete to fail on the orphan volume, got: %v", err)
+	}
+	if !strings.Contains(out, " will be deleted") {
+		t.Fatalf("expected the plan to be printed before failing; got: %s", out)
+	}
+
+	if _, err := run("--fail-on", "update"); err != nil {
+		t.Fatalf("expected --fail-on update to pass without updates, got: %v", err)
+	}
+
+	if _, err := run("--fail-on", "create,delete"); err == nil || !strings.Contains(err.Error(), "1 to create, 1 to delete") {
+		t.Fatalf("expected both kinds reported, got: %v", err)
+	}
+
+	if _, err := run("--fail-on", "destroy"); err == nil || !strings.Contains(err.Error(), "invalid --fail-on") {
+		t.Fatalf("expected invalid kind error, got: %v", err)
+	}
+}
+
+func TestPlan_GroupByDaemon(t *testing.T) {
+	defer clitest.WithStubDocker(t)()
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"plan", "--group-by", "daemon", "--manifest", clitest.BasicConfigPath(t)})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("plan execute: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{"Endpoint:", "docker context default", "default/website", "orphan-vol"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in grouped plan; got: %s", want, got)
+		}
+	}
+
+	root = cli.TestNewRootCmd()
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"plan", "--group-by", "stack", "--manifest", clitest.BasicConfigPath(t)})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "invalid --group-by") {
+		t.Fatalf("expected invalid --group-by error, got: %v", err)
+	}
+}
+
+func TestPlan_ParallelFilesetsMustBePositive(t *testing.T) {
+	defer clitest.WithStubDocker(t)()
+
+	run := func(n string) error {
+		root := cli.TestNewRootCmd()
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetErr(&out)
+		root.SetArgs([]string{"plan", "--parallel-filesets", n, "--manifest", clitest.BasicConfigPath(t)})
+		return root.Execute()
+	}
+	if err := run("0"); err == nil || !strings.Contains(err.Error(), "--parallel-filesets must be at least 1") {
+		t.Fatalf("expected --parallel-filesets 0 to be rejected, got %v", err)
+	}
+	if err := run("2"); err != nil {
+		t.Fatalf("plan with --parallel-filesets 2: %v", err)
+	}
+}
+
+func TestPlan_FilesetUnknownName(t *testing.T) {
+	defer clitest.WithStubDocker(t)()
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"plan", "--fileset", "nope", "--verbose", "--manifest", clitest.BasicConfigPath(t)})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), `unknown fileset "nope"`) {
+		t.Fatalf("expected unknown fileset error, got: %v", err)
+	}
+}
+
+func TestPlan_OutputJSON(t *testing.T) {
+	undo := clitest.WithCustomDockerStub(t, upToDateDockerStub)
+	defer undo()
+
+	root := cli.TestNewRootCmd()
+	var out, errOut bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&errOut)
+	root.SetArgs([]string{"plan", "--output", "json", "--manifest", clitest.BasicConfigPath(t)})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("plan --output json execute: %v", err)
+	}
+
+	var doc struct {
+		SchemaVersion int `json:"schema_version"`
+		Resources     []struct {
+			Context, Kind, Name, Parent, Action string
+		} `json:"resources"`
+		Summary struct{ Create, Update, Delete int } `json:"summary"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatalf("stdout is not a single JSON document: %v\n%s", err, out.String())
+	}
+	if doc.SchemaVersion != 1 {
+		t.Fatalf("expected schema_version 1, got %d", doc.SchemaVersion)
+	}
+	var sawVolume, sawService bool
+	for _, r := range doc.Resources {
+		if r.Kind == "volume" && r.Name == "orphan-vol" && r.Action == "delete" && r.Context == "default" {
+			sawVolume = true
+		}
+		if r.Kind == "service" && r.Name == "nginx" && r.Parent == "website" && r.Action == "noop" {
+			sawService = true
+		}
+	}
+	if !sawVolume || !sawService {
+		t.Fatalf("expected orphan-vol delete and nginx noop resources, got %+v", doc.Resources)
+	}
+	if doc.Summary.Delete != 1 {
+		t.Fatalf("expected 1 delete in summary, got %+v", doc.Summary)
+	}
+}
+
+func TestPlan_OutputRejectsUnknownFormat(t *testing.T) {
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"plan", "--output", "yaml", "--manifest", clitest.BasicConfigPath(t)})
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "invalid --output value") {
+		t.Fatalf("expected invalid --output error, got %v", err)
+	}
+}
+
+func TestPlan_DetailedExitCode(t *testing.T) {
+	undo := clitest.WithCustomDockerStub(t, upToDateDockerStub)
+	defer undo()
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"plan", "--detailed-exitcode", "--manifest", clitest.BasicConfigPath(t)})
+	err := root.Execute()
+	var exitErr *common.ExitCodeError
+	if !errors.As(err, &exitErr) || exitErr.Code != 2 || exitErr.Err != nil {
+		t.Fatalf("expected silent exit code 2 for a plan with changes, got %v", err)
+	}
+
+	root = cli.TestNewRootCmd()
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"plan", "--detailed-exitcode", "--manifest", "/path/does/not/exist.yml"})
+	err = root.Execute()
+	if !errors.As(err, &exitErr) || exitErr.Code != 1 || exitErr.Err == nil {
+		t.Fatalf("expected exit code 1 carrying the error, got %v", err)
+	}
+}
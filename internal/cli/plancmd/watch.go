@@ -0,0 +1,66 @@
+package plancmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/cli/common"
+	"github.com/gcstr/dockform/internal/planner"
+)
+
+// watchOptions configures plan --watch.
+type watchOptions struct {
+	interval     time.Duration
+	onceOnChange bool
+	render       func(*planner.Plan) string
+}
+
+// watchPlan rebuilds the plan every interval until the command is canceled,
+// printing a timestamped drift summary each cycle. The full plan is printed on
+// the first cycle and whenever it differs from the previous one. A cycle whose
+// plan fails to build is reported and retried on the next tick. With
+// onceOnChange, the first cycle that finds drift ends the watch with an error.
+func watchPlan(ctx *common.CLIContext, opts watchOptions) error {
+	var last string
+	first := true
+	for {
+		plan, err := ctx.Planner.BuildPlan(ctx.Ctx, *ctx.Config)
+		stamp := time.Now().UTC().Format(time.RFC3339)
+		switch {
+		case ctx.Ctx.Err() != nil:
+			return nil
+		case err != nil:
+			ctx.Printer.Warn("%s plan failed: %v", stamp, err)
+		default:
+			summary := driftSummary(plan)
+			ctx.Printer.Plain("%s %s", stamp, summary)
+			if out := opts.render(plan); first || out != last {
+				ctx.Printer.Plain("%s", out)
+				last = out
+			}
+			first = false
+			if opts.onceOnChange && summary != noDrift {
+				return apperr.New("cli.plan", apperr.Precondition, "drift detected: %s", summary)
+			}
+		}
+
+		select {
+		case <-ctx.Ctx.Done():
+			return nil
+		case <-time.After(opts.interval):
+		}
+	}
+}
+
+// noDrift is the summary of a plan without changes.
+const noDrift = "no drift"
+
+// driftSummary condenses a plan into a one-line drift count.
+func driftSummary(plan *planner.Plan) string {
+	create, update, del := plan.CountChanges()
+	if create+update+del == 0 {
+		return noDrift
+	}
+	return fmt.Sprintf("drift: %d to create, %d to update, %d to delete", create, update, del)
+}
@@ -0,0 +1,76 @@
+package plancmd_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/cli"
+	"github.com/gcstr/dockform/internal/cli/clitest"
+)
+
+func TestPlan_Watch_OnceOnChangeExitsOnDrift(t *testing.T) {
+	defer clitest.WithStubDocker(t)()
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"plan", "--manifest", clitest.BasicConfigPath(t), "--watch", "--interval", "10ms", "--once-on-change"})
+
+	err := root.Execute()
+	if err == nil || !apperr.IsKind(err, apperr.Precondition) {
+		t.Fatalf("expected precondition error on drift, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "drift detected") {
+		t.Fatalf("expected drift in error, got: %v", err)
+	}
+	if !strings.Contains(out.String(), " drift: ") {
+		t.Fatalf("expected timestamped drift summary; got: %s", out.String())
+	}
+}
+
+func TestPlan_Watch_StopsOnCancel(t *testing.T) {
+	defer clitest.WithStubDocker(t)()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"plan", "--manifest", clitest.BasicConfigPath(t), "--watch", "--interval", "20ms"})
+
+	if err := root.ExecuteContext(ctx); err != nil {
+		t.Fatalf("expected watch to end cleanly on cancel, got: %v", err)
+	}
+	got := out.String()
+	if n := strings.Count(got, " drift: "); n < 2 {
+		t.Fatalf("expected a drift summary per cycle, got %d; output: %s", n, got)
+	}
+	if n := strings.Count(got, "will be deleted"); n != 1 {
+		t.Fatalf("expected the unchanged plan printed exactly once, got %d times; output: %s", n, got)
+	}
+}
+
+func TestPlan_Watch_RejectsInvalidFlags(t *testing.T) {
+	cases := [][]string{
+		{"--once-on-change"},
+		{"--watch", "--interval", "0s"},
+		{"--watch", "--fail-on", "delete"},
+	}
+	for _, args := range cases {
+		root := cli.TestNewRootCmd()
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetErr(&out)
+		root.SetArgs(append([]string{"plan", "--manifest", clitest.BasicConfigPath(t)}, args...))
+		if err := root.Execute(); err == nil || !apperr.IsKind(err, apperr.InvalidInput) {
+			t.Fatalf("%v: expected invalid input error, got: %v", args, err)
+		}
+	}
+}
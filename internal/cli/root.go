@@ -17,14 +17,18 @@ import (
 	"github.com/gcstr/dockform/internal/cli/dashboardcmd"
 	"github.com/gcstr/dockform/internal/cli/destroycmd"
 	"github.com/gcstr/dockform/internal/cli/doctorcmd"
+	"github.com/gcstr/dockform/internal/cli/execcmd"
 	"github.com/gcstr/dockform/internal/cli/imagescmd"
 	"github.com/gcstr/dockform/internal/cli/initcmd"
+	"github.com/gcstr/dockform/internal/cli/logscmd"
 	"github.com/gcstr/dockform/internal/cli/manifestcmd"
 	"github.com/gcstr/dockform/internal/cli/plancmd"
 	"github.com/gcstr/dockform/internal/cli/secretcmd"
+	"github.com/gcstr/dockform/internal/cli/stackcmd"
 	"github.com/gcstr/dockform/internal/cli/validatecmd"
 	"github.com/gcstr/dockform/internal/cli/versioncmd"
 	"github.com/gcstr/dockform/internal/cli/volumecmd"
+	"github.com/gcstr/dockform/internal/dockercli"
 	"github.com/gcstr/dockform/internal/logger"
 	"github.com/spf13/cobra"
 )
@@ -48,6 +52,13 @@ func Execute(ctx context.Context) int {
 		if errors.Is(err, context.Canceled) {
 			return 130
 		}
+		var exitErr *common.ExitCodeError
+		if errors.As(err, &exitErr) {
+			if exitErr.Err != nil {
+				printUserFriendly(exitErr.Err)
+			}
+			return exitErr.Code
+		}
 		printUserFriendly(err)
 		switch {
 		case apperr.IsKind(err, apperr.InvalidInput):
@@ -100,17 +111,30 @@ func newRootCmd() *cobra.Command {
 			commandPath := cmd.CommandPath()
 			l = l.With("command", commandPath)
 			cmd.SetContext(logger.WithContext(cmd.Context(), l))
+
+			// Reject bad --timeout, --retries and similar values before any
+			// command builds a docker client from them.
+			if _, err := common.ClientSettings(cmd); err != nil {
+				return err
+			}
 			return nil
 		},
 	}
 
-	cmd.PersistentFlags().String("manifest", "", "Path to manifest file or directory (defaults: dockform.yml, dockform.yaml, Dockform.yml, Dockform.yaml in current directory)")
+	cmd.PersistentFlags().String("manifest", "", "Path to manifest file or directory, or a URL: https://... for a single file, git+https://repo[//dir]#ref for a repository (defaults: dockform.yml, dockform.yaml, Dockform.yml, Dockform.yaml in current directory)")
+	cmd.PersistentFlags().String("base-dir", "", "Directory relative stack roots and fileset sources in the manifest resolve from (defaults to the manifest's directory)")
+	cmd.PersistentFlags().String("gnupg-home", "", "GnuPG home directory sops uses for PGP in this run, overriding sops.pgp.keyring_dir (e.g. an ephemeral keyring on CI)")
 	cmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Verbose error output")
 	// Logging flags
 	cmd.PersistentFlags().String("log-level", "info", "Log level: debug, info, warn, error")
 	cmd.PersistentFlags().String("log-format", "auto", "Log format: auto, pretty, json")
 	cmd.PersistentFlags().String("log-file", "", "Write logs to file using the format specified by --log-format (in addition to stderr)")
 	cmd.PersistentFlags().Bool("no-color", false, "Disable color in pretty logs")
+	cmd.PersistentFlags().Bool("trace", false, "Print every docker command run, with secrets redacted, its exit status and duration to stderr (or set DOCKFORM_TRACE=1)")
+	cmd.PersistentFlags().Duration("context-timeout", dockercli.DefaultProbeTimeout, "How long to wait for each Docker daemon to answer the reachability check before reporting it unreachable")
+	cmd.PersistentFlags().Duration("timeout", 0, "Fail any single docker command that runs longer than this (e.g. 2m) instead of waiting on a wedged daemon; each --retries attempt gets the full limit; unset means no limit")
+	cmd.PersistentFlags().Duration("pull-timeout", 0, "Time limit for docker commands that pull images or bring services up, which may need longer than --timeout (defaults to --timeout)")
+	cmd.PersistentFlags().Int("retries", 4, "How often to retry a docker command that fails with a dropped connection, with exponential backoff; only read-only commands are retried unless the connection failed before the command ran (0 disables)")
 	cmd.PersistentFlags().Bool("ssh-multiplex", true, "Reuse one SSH connection per host for a run (ControlMaster); disable with --ssh-multiplex=false or DOCKFORM_SSH_MULTIPLEX=false")
 
 	cmd.AddCommand(initcmd.New())
@@ -127,6 +151,9 @@ func newRootCmd() *cobra.Command {
 	cmd.AddCommand(doctorcmd.New())
 	cmd.AddCommand(dashboardcmd.New())
 	cmd.AddCommand(imagescmd.New())
+	cmd.AddCommand(logscmd.New())
+	cmd.AddCommand(execcmd.New())
+	cmd.AddCommand(stackcmd.New())
 
 	// Register optional developer-only commands
 	registerDocsCmd(cmd)
@@ -10,6 +10,7 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gcstr/dockform/internal/apperr"
 	"github.com/gcstr/dockform/internal/cli/clitest"
@@ -509,3 +510,74 @@ func TestProvideDockerTroubleshootingHintsNonDefaultContext(t *testing.T) {
 		t.Fatalf("expected context ps hint, got: %s", s)
 	}
 }
+
+func TestRoot_TraceLogsDockerInvocations(t *testing.T) {
+	defer clitest.WithStubDocker(t)()
+	t.Setenv("DOCKFORM_TRACE", "1")
+
+	cmd := newRootCmd()
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs([]string{"validate", "--manifest", clitest.BasicConfigPath(t)})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if !strings.Contains(errOut.String(), "trace: docker version") {
+		t.Fatalf("expected docker invocations traced to stderr, got:\n%s", errOut.String())
+	}
+
+	cmd = newRootCmd()
+	errOut.Reset()
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs([]string{"validate", "--trace=false", "--manifest", clitest.BasicConfigPath(t)})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if strings.Contains(errOut.String(), "trace:") {
+		t.Fatalf("expected --trace=false to override DOCKFORM_TRACE, got:\n%s", errOut.String())
+	}
+}
+
+func TestRoot_ContextTimeoutFailsFast(t *testing.T) {
+	defer clitest.WithCustomDockerStub(t, `#!/bin/sh
+if [ "$1" = "version" ]; then exec sleep 5; fi
+exit 0
+`)()
+
+	cmd := newRootCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"validate", "--context-timeout", "200ms", "--manifest", clitest.BasicConfigPath(t)})
+	start := time.Now()
+	err := cmd.Execute()
+	if elapsed := time.Since(start); elapsed >= 3*time.Second {
+		t.Fatalf("validate took %s; expected --context-timeout to bound the probe", elapsed)
+	}
+	if !apperr.IsKind(err, apperr.Unavailable) || !strings.Contains(err.Error(), "daemon unreachable (timeout after 200ms)") {
+		t.Fatalf("expected an unreachable timeout error, got %v", err)
+	}
+
+	cmd = newRootCmd()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"validate", "--context-timeout", "0s", "--manifest", clitest.BasicConfigPath(t)})
+	if err := cmd.Execute(); !apperr.IsKind(err, apperr.InvalidInput) {
+		t.Fatalf("expected a non-positive --context-timeout to be rejected, got %v", err)
+	}
+}
+
+func TestRoot_RejectsInvalidTimeoutsAndRetries(t *testing.T) {
+	for flag, value := range map[string]string{"--timeout": "0s", "--pull-timeout": "-1m", "--retries": "-1"} {
+		cmd := newRootCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs([]string{"validate", flag, value, "--manifest", clitest.BasicConfigPath(t)})
+		if err := cmd.Execute(); !apperr.IsKind(err, apperr.InvalidInput) {
+			t.Fatalf("expected %s %s to be rejected, got %v", flag, value, err)
+		}
+	}
+}
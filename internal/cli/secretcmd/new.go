@@ -25,8 +25,10 @@ func New() *cobra.Command {
 	}
 	cmd.AddCommand(newCreateCmd())
 	cmd.AddCommand(newRekeyCmd())
+	cmd.AddCommand(newEncryptCmd())
 	cmd.AddCommand(newDecryptCmd())
 	cmd.AddCommand(newEditCmd())
+	cmd.AddCommand(newDiffCmd())
 	return cmd
 }
 
@@ -43,7 +45,12 @@ func loadConfigWithManifestSelection(cmd *cobra.Command, pr ui.Printer) (manifes
 	if file != "" {
 		_ = cmd.Flags().Set("manifest", file)
 	}
-	return manifest.Load(file)
+	cfg, err := manifest.Load(file)
+	if err != nil {
+		return manifest.Config{}, err
+	}
+	common.ApplyGnupgHome(cmd, &cfg)
+	return cfg, nil
 }
 
 func resolveRecipientsAndKey(cfg manifest.Config) (sopsResolved, error) {
@@ -70,17 +77,38 @@ func resolveRecipientsAndKey(cfg manifest.Config) (sopsResolved, error) {
 	pgpAgent := false
 	pgpMode := ""
 	pgpPass := ""
+	pgpHome := ""
 	if cfg.Sops.Pgp != nil {
 		pgpRecipients = cfg.Sops.Pgp.Recipients
 		pgpDir = cfg.Sops.Pgp.KeyringDir
 		pgpAgent = cfg.Sops.Pgp.UseAgent
 		pgpMode = cfg.Sops.Pgp.PinentryMode
 		pgpPass = cfg.Sops.Pgp.Passphrase
+		pgpHome = cfg.Sops.Pgp.GnupgHome
 	}
 	if len(ageRecipients) == 0 && len(pgpRecipients) == 0 {
 		return sopsResolved{}, apperr.New("cli.resolveRecipientsAndKey", apperr.InvalidInput, "no sops recipients configured (age or pgp)")
 	}
-	return sopsResolved{opts: secrets.SopsOptions{AgeKeyFile: ageKey, AgeRecipients: ageRecipients, PgpKeyringDir: pgpDir, PgpUseAgent: pgpAgent, PgpPinentryMode: pgpMode, PgpPassphrase: pgpPass, PgpRecipients: pgpRecipients}, ageRecipients: ageRecipients}, nil
+	return sopsResolved{opts: secrets.SopsOptions{AgeKeyFile: ageKey, AgeRecipients: ageRecipients, PgpKeyringDir: pgpDir, PgpUseAgent: pgpAgent, PgpPinentryMode: pgpMode, PgpPassphrase: pgpPass, PgpRecipients: pgpRecipients, GnupgHome: pgpHome}, ageRecipients: ageRecipients}, nil
+}
+
+// decryptOptions returns the SOPS settings needed to decrypt only; unlike
+// resolveRecipientsAndKey it does not require recipients to be configured.
+func decryptOptions(cfg manifest.Config) secrets.SopsOptions {
+	var opts secrets.SopsOptions
+	if cfg.Sops != nil {
+		if cfg.Sops.Age != nil {
+			opts.AgeKeyFile = cfg.Sops.Age.KeyFile
+		}
+		if cfg.Sops.Pgp != nil {
+			opts.PgpKeyringDir = cfg.Sops.Pgp.KeyringDir
+			opts.PgpUseAgent = cfg.Sops.Pgp.UseAgent
+			opts.PgpPinentryMode = cfg.Sops.Pgp.PinentryMode
+			opts.PgpPassphrase = cfg.Sops.Pgp.Passphrase
+			opts.GnupgHome = cfg.Sops.Pgp.GnupgHome
+		}
+	}
+	return opts
 }
 
 func newCreateCmd() *cobra.Command {
@@ -109,7 +137,7 @@ func newCreateCmd() *cobra.Command {
 			if err := os.WriteFile(path, []byte("SECRET_KEY=secret\n"), 0o600); err != nil {
 				return err
 			}
-			if err := secrets.EncryptDotenvFileWithSops(context.Background(), path, resolved.opts.AgeRecipients, resolved.opts.AgeKeyFile, resolved.opts.PgpRecipients, resolved.opts.PgpKeyringDir, resolved.opts.PgpUseAgent, resolved.opts.PgpPinentryMode, resolved.opts.PgpPassphrase); err != nil {
+			if err := secrets.EncryptDotenvFile(context.Background(), path, resolved.opts); err != nil {
 				return err
 			}
 			if _, err := fmt.Fprintln(cmd.OutOrStdout(), "secret created:", path); err != nil {
@@ -149,15 +177,7 @@ func newRekeyCmd() *cobra.Command {
 				if !filepath.IsAbs(path) {
 					path = filepath.Join(cfg.BaseDir, path)
 				}
-				pairs, err := secrets.DecryptAndParse(cmd.Context(), path, resolved.opts)
-				if err != nil {
-					return err
-				}
-				plain := strings.Join(pairs, "\n") + "\n"
-				if err := os.WriteFile(path, []byte(plain), 0o600); err != nil {
-					return err
-				}
-				if err := secrets.EncryptDotenvFileWithSops(cmd.Context(), path, resolved.opts.AgeRecipients, resolved.opts.AgeKeyFile, resolved.opts.PgpRecipients, resolved.opts.PgpKeyringDir, resolved.opts.PgpUseAgent, resolved.opts.PgpPinentryMode, resolved.opts.PgpPassphrase); err != nil {
+				if err := secrets.ReencryptFile(cmd.Context(), path, resolved.opts); err != nil {
 					return err
 				}
 				if _, err := fmt.Fprintf(cmd.OutOrStdout(), "%s reencrypted\n", p); err != nil {
@@ -170,6 +190,54 @@ func newRekeyCmd() *cobra.Command {
 	return cmd
 }
 
+func newEncryptCmd() *cobra.Command {
+	var force bool
+	cmd := &cobra.Command{
+		Use:   "encrypt <path>",
+		Short: "Encrypt a plaintext secrets file in place with the configured recipients",
+		Long: `Encrypt a plaintext secrets file in place with the sops.age and sops.pgp
+recipients configured in the manifest. .yaml, .yml and .json files are
+encrypted as YAML and JSON; anything else is treated as dotenv.
+
+A file that is already encrypted is left alone unless --force is given, in
+which case it is decrypted and encrypted again for the configured recipients.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pr := ui.StdPrinter{Out: cmd.OutOrStdout(), Err: cmd.ErrOrStderr()}
+			cfg, err := loadConfigWithManifestSelection(cmd, pr)
+			if err != nil {
+				return err
+			}
+			resolved, err := resolveRecipientsAndKey(cfg)
+			if err != nil {
+				return err
+			}
+			path := args[0]
+			encrypted, err := secrets.IsSopsEncrypted(path)
+			if err != nil {
+				return err
+			}
+			switch {
+			case encrypted && !force:
+				return apperr.New("cli.newSecretEncryptCmd", apperr.InvalidInput, "%s is already encrypted; use --force to re-encrypt it for the configured recipients", path)
+			case encrypted:
+				err = secrets.ReencryptFile(cmd.Context(), path, resolved.opts)
+			default:
+				err = secrets.EncryptFile(cmd.Context(), path, resolved.opts)
+			}
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintln(cmd.OutOrStdout(), "secret encrypted:", path); err != nil {
+				return err
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&force, "force", false, "Re-encrypt a file that is already encrypted")
+	return cmd
+}
+
 func newDecryptCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "decrypt <path>",
@@ -181,19 +249,7 @@ func newDecryptCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			var opts secrets.SopsOptions
-			if cfg.Sops != nil {
-				if cfg.Sops.Age != nil {
-					opts.AgeKeyFile = cfg.Sops.Age.KeyFile
-				}
-				if cfg.Sops.Pgp != nil {
-					opts.PgpKeyringDir = cfg.Sops.Pgp.KeyringDir
-					opts.PgpUseAgent = cfg.Sops.Pgp.UseAgent
-					opts.PgpPinentryMode = cfg.Sops.Pgp.PinentryMode
-					opts.PgpPassphrase = cfg.Sops.Pgp.Passphrase
-				}
-			}
-			pairs, err := secrets.DecryptAndParse(cmd.Context(), args[0], opts)
+			pairs, err := secrets.DecryptAndParse(cmd.Context(), args[0], decryptOptions(cfg))
 			if err != nil {
 				return err
 			}
@@ -255,7 +311,7 @@ func newEditCmd() *cobra.Command {
 			if err := os.WriteFile(path, b, 0o600); err != nil {
 				return err
 			}
-			if err := secrets.EncryptDotenvFileWithSops(cmd.Context(), path, resolved.opts.AgeRecipients, resolved.opts.AgeKeyFile, resolved.opts.PgpRecipients, resolved.opts.PgpKeyringDir, resolved.opts.PgpUseAgent, resolved.opts.PgpPinentryMode, resolved.opts.PgpPassphrase); err != nil {
+			if err := secrets.EncryptDotenvFile(cmd.Context(), path, resolved.opts); err != nil {
 				return err
 			}
 			if _, err := fmt.Fprintln(cmd.OutOrStdout(), "secret updated:", path); err != nil {
@@ -266,3 +322,108 @@ func newEditCmd() *cobra.Command {
 	}
 	return cmd
 }
+
+func newDiffCmd() *cobra.Command {
+	var ref string
+	cmd := &cobra.Command{
+		Use:   "diff <path> [other]",
+		Short: "Show which secret keys changed, without printing values",
+		Long: `Decrypt two versions of a SOPS-encrypted dotenv file and report added,
+removed and changed keys. Values are never printed.
+
+With a single path, the working file is compared to its version at --ref
+(HEAD by default) in the enclosing git repository. With two paths, the
+first is treated as the old version and the second as the new one.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pr := ui.StdPrinter{Out: cmd.OutOrStdout(), Err: cmd.ErrOrStderr()}
+			cfg, err := loadConfigWithManifestSelection(cmd, pr)
+			if err != nil {
+				return err
+			}
+			opts := decryptOptions(cfg)
+
+			oldPath, newPath := args[0], args[0]
+			oldLabel := ref + ":" + args[0]
+			if len(args) == 2 {
+				if cmd.Flags().Changed("ref") {
+					return apperr.New("cli.newSecretDiffCmd", apperr.InvalidInput, "--ref cannot be combined with two paths")
+				}
+				newPath = args[1]
+				oldLabel = args[0]
+			} else {
+				tmp, err := gitShowToTemp(cmd.Context(), ref, args[0])
+				if err != nil {
+					return err
+				}
+				defer func() { _ = os.Remove(tmp) }()
+				oldPath = tmp
+			}
+
+			before, err := secrets.DecryptAndParse(cmd.Context(), oldPath, opts)
+			if err != nil {
+				return err
+			}
+			after, err := secrets.DecryptAndParse(cmd.Context(), newPath, opts)
+			if err != nil {
+				return err
+			}
+			d := secrets.DiffPairs(before, after)
+
+			out := cmd.OutOrStdout()
+			if d.Empty() {
+				_, err := fmt.Fprintf(out, "no key changes between %s and %s\n", oldLabel, newPath)
+				return err
+			}
+			for _, k := range d.Added {
+				if _, err := fmt.Fprintf(out, "+ %s=***\n", k); err != nil {
+					return err
+				}
+			}
+			for _, k := range d.Removed {
+				if _, err := fmt.Fprintf(out, "- %s=***\n", k); err != nil {
+					return err
+				}
+			}
+			for _, k := range d.Changed {
+				if _, err := fmt.Fprintf(out, "~ %s=***\n", k); err != nil {
+					return err
+				}
+			}
+			_, err = fmt.Fprintf(out, "%d added, %d removed, %d changed\n", len(d.Added), len(d.Removed), len(d.Changed))
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&ref, "ref", "HEAD", "Git revision to compare the working file against")
+	return cmd
+}
+
+// gitShowToTemp writes the committed content of path at ref into a private
+// temporary file and returns its name. The caller removes it.
+func gitShowToTemp(ctx context.Context, ref, path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	c := exec.CommandContext(ctx, "git", "-C", filepath.Dir(abs), "show", ref+":./"+filepath.Base(abs))
+	var stderr strings.Builder
+	c.Stderr = &stderr
+	b, err := c.Output()
+	if err != nil {
+		return "", apperr.Wrap("cli.newSecretDiffCmd", apperr.External, err, "git show %s:%s: %s", ref, path, strings.TrimSpace(stderr.String()))
+	}
+	tmp, err := os.CreateTemp("", "dockform-secret-*"+filepath.Ext(abs))
+	if err != nil {
+		return "", err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
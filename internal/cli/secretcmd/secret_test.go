@@ -175,6 +175,53 @@ func TestSecret_Rekey_Success(t *testing.T) {
 	}
 }
 
+func TestSecret_Rekey_KeepsYAMLStructure(t *testing.T) {
+	requireSops(t)
+	dir := t.TempDir()
+	keyPath, recipient := writeTempAgeKey(t, dir)
+	// Isolate sops config from CI environment (cross-platform)
+	t.Setenv("HOME", dir)
+	t.Setenv("USERPROFILE", dir) // Windows uses USERPROFILE
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, ".config"))
+	t.Setenv("SOPS_AGE_KEY_FILE", keyPath)
+	target := filepath.Join(dir, "secrets.yaml")
+	if err := os.WriteFile(target, []byte("db:\n  user: app\n  password: s3cret\n"), 0o600); err != nil {
+		t.Fatalf("write plaintext: %v", err)
+	}
+	enc := exec.Command("sops", "--encrypt", "--age", recipient, "--in-place", target)
+	enc.Env = os.Environ()
+	if out, err := enc.CombinedOutput(); err != nil {
+		t.Fatalf("sops encrypt: %v\n%s", err, out)
+	}
+	cfgPath := filepath.Join(dir, "dockform.yml")
+	cfg := "identifier: test-id\ncontexts:\n  default: {}\nsops:\n  age:\n    key_file: " + keyPath + "\n    recipients:\n      - " + recipient + "\nstacks:\n  default/app:\n    root: .\n    secrets:\n      sops:\n        - secrets.yaml\n"
+	if err := os.WriteFile(cfgPath, []byte(cfg), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"secrets", "rekey", "--manifest", cfgPath})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("secret rekey execute: %v\n%s", err, out.String())
+	}
+	b, _ := os.ReadFile(target)
+	if !strings.Contains(string(b), "sops:") || strings.Contains(string(b), "sops_mac=") {
+		t.Fatalf("expected the file to stay SOPS-encrypted YAML, got:\n%s", b)
+	}
+	dec := exec.Command("sops", "--decrypt", target)
+	dec.Env = os.Environ()
+	plain, err := dec.Output()
+	if err != nil {
+		t.Fatalf("decrypt after rekey: %v", err)
+	}
+	if !strings.Contains(string(plain), "db:\n") || !strings.Contains(string(plain), "password: s3cret") {
+		t.Fatalf("expected the nested YAML back after rekey, got:\n%s", plain)
+	}
+}
+
 func TestSecret_Rekey_DecryptError(t *testing.T) {
 	dir := t.TempDir()
 	keyPath, recipient := writeTempAgeKey(t, dir)
@@ -215,3 +262,147 @@ func TestSecret_Rekey_NoSecretsConfigured(t *testing.T) {
 		t.Fatalf("expected helpful message about no secrets; got: %q", got)
 	}
 }
+
+func TestSecret_Diff_TwoPathsMasksValues(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "dockform.yml")
+	if err := os.WriteFile(cfgPath, []byte("identifier: test-id\ncontexts:\n  default: {}\n"), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	oldPath := filepath.Join(dir, "old.env")
+	newPath := filepath.Join(dir, "new.env")
+	_ = os.WriteFile(oldPath, []byte("KEEP=same\nROTATE=oldvalue\nGONE=bye\n"), 0o600)
+	_ = os.WriteFile(newPath, []byte("KEEP=same\nROTATE=newvalue\nADDED=hello\n"), 0o600)
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"secrets", "diff", oldPath, newPath, "--manifest", cfgPath})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("secrets diff execute: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{"+ ADDED=***", "- GONE=***", "~ ROTATE=***", "1 added, 1 removed, 1 changed"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in output; got:\n%s", want, got)
+		}
+	}
+	for _, secret := range []string{"oldvalue", "newvalue", "hello", "bye", "KEEP"} {
+		if strings.Contains(got, secret) {
+			t.Fatalf("output leaked %q:\n%s", secret, got)
+		}
+	}
+}
+
+func TestSecret_Diff_AgainstGitHead(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH; skipping")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		c := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+		if b, err := c.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, b)
+		}
+	}
+	cfgPath := filepath.Join(dir, "dockform.yml")
+	if err := os.WriteFile(cfgPath, []byte("identifier: test-id\ncontexts:\n  default: {}\n"), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	target := filepath.Join(dir, "secrets.env")
+	_ = os.WriteFile(target, []byte("A=1\nB=2\n"), 0o600)
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-q", "-m", "init")
+	_ = os.WriteFile(target, []byte("A=1\nB=3\n"), 0o600)
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"secrets", "diff", target, "--manifest", cfgPath})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("secrets diff execute: %v", err)
+	}
+	if got := out.String(); !strings.Contains(got, "~ B=***") || strings.Contains(got, "A=") {
+		t.Fatalf("unexpected diff output:\n%s", got)
+	}
+}
+
+func TestSecret_Encrypt_InPlaceAndRefusesEncryptedWithoutForce(t *testing.T) {
+	requireSops(t)
+	dir := t.TempDir()
+	keyPath, _ := writeTempAgeKey(t, dir)
+	// Isolate sops config from CI environment (cross-platform)
+	t.Setenv("HOME", dir)
+	t.Setenv("USERPROFILE", dir) // Windows uses USERPROFILE
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, ".config"))
+	t.Setenv("SOPS_AGE_KEY_FILE", keyPath)
+	cfgPath := filepath.Join(dir, "dockform.yml")
+	cfg := "identifier: test-id\ncontexts:\n  default: {}\nsops:\n  age:\n    key_file: " + keyPath + "\n"
+	if err := os.WriteFile(cfgPath, []byte(cfg), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	target := filepath.Join(dir, "secrets.env")
+	if err := os.WriteFile(target, []byte("TOKEN=abc\n"), 0o600); err != nil {
+		t.Fatalf("write plaintext: %v", err)
+	}
+
+	run := func(args ...string) (string, error) {
+		root := cli.TestNewRootCmd()
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetErr(&out)
+		root.SetArgs(append(args, "--manifest", cfgPath))
+		err := root.Execute()
+		return out.String(), err
+	}
+	if _, err := run("secrets", "encrypt", target); err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	b, _ := os.ReadFile(target)
+	if strings.Contains(string(b), "TOKEN=abc") {
+		t.Fatalf("expected the file to be encrypted in place, got:\n%s", b)
+	}
+	if _, err := run("secrets", "encrypt", target); err == nil || !strings.Contains(err.Error(), "already encrypted") {
+		t.Fatalf("expected already-encrypted error, got %v", err)
+	}
+	if _, err := run("secrets", "encrypt", target, "--force"); err != nil {
+		t.Fatalf("encrypt --force: %v", err)
+	}
+	out, err := run("secrets", "decrypt", target)
+	if err != nil {
+		t.Fatalf("decrypt: %v", err)
+	}
+	if out != "TOKEN=abc\n" {
+		t.Fatalf("unexpected plaintext after decrypt: %q", out)
+	}
+}
+
+func TestSecret_Encrypt_RefusesEncryptedFile(t *testing.T) {
+	dir := t.TempDir()
+	keyPath, _ := writeTempAgeKey(t, dir)
+	cfgPath := filepath.Join(dir, "dockform.yml")
+	cfg := "identifier: test-id\ncontexts:\n  default: {}\nsops:\n  age:\n    key_file: " + keyPath + "\n"
+	if err := os.WriteFile(cfgPath, []byte(cfg), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	target := filepath.Join(dir, "secrets.env")
+	content := "TOKEN=ENC[AES256_GCM,data:x]\nsops_mac=ENC[AES256_GCM,data:y]\n"
+	if err := os.WriteFile(target, []byte(content), 0o600); err != nil {
+		t.Fatalf("write secret: %v", err)
+	}
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"secrets", "encrypt", target, "--manifest", cfgPath})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "use --force") {
+		t.Fatalf("expected refusal without --force, got %v", err)
+	}
+	if b, _ := os.ReadFile(target); string(b) != content {
+		t.Fatalf("expected the encrypted file untouched, got:\n%s", b)
+	}
+}
@@ -0,0 +1,16 @@
+package stackcmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// New creates the `stack` command group for day-2 operations on a single stack.
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stack",
+		Short: "Operate on a single stack's running services",
+	}
+	cmd.AddCommand(newRestartCmd())
+	cmd.AddCommand(newScaleCmd())
+	return cmd
+}
@@ -0,0 +1,136 @@
+package stackcmd
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/cli/common"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/logger"
+	"github.com/gcstr/dockform/internal/manifest"
+	"github.com/gcstr/dockform/internal/planner"
+	"github.com/gcstr/dockform/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func newRestartCmd() *cobra.Command {
+	var services []string
+	cmd := &cobra.Command{
+		Use:   "restart <[context/]stack>",
+		Short: "Restart a stack's running containers in dependency order",
+		Long: `Restart a stack's running containers in dependency order.
+
+Containers are found by their compose project and the manifest identifier, and
+restarted service by service so that a service's depends_on targets restart
+before it. --service limits the restart to the named services, still in
+dependency order; their dependencies are not restarted.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pr := ui.StdPrinter{Out: cmd.OutOrStdout(), Err: cmd.ErrOrStderr()}
+			cfg, err := common.LoadConfigWithWarnings(cmd, pr)
+			if err != nil {
+				return err
+			}
+			stackKey, stack, err := common.ResolveStack(cfg, args[0])
+			if err != nil {
+				return err
+			}
+			contextName, stackName, err := manifest.ParseStackKey(stackKey)
+			if err != nil {
+				return err
+			}
+			docker := common.CreateClientFactory(cmd).GetClientForContext(contextName, cfg)
+			ctx := cmd.Context()
+
+			inline, err := planner.NewServiceStateDetector(nil).BuildInlineEnv(ctx, stack, cfg.Sops)
+			if err != nil {
+				return err
+			}
+			doc, err := docker.ComposeConfigFull(dockercli.WithStack(ctx, stack), stack.Root, stack.Files, stack.Profiles, stack.EnvFile, inline)
+			if err != nil {
+				return apperr.Wrap("cli.stack.restart", apperr.External, err, "load compose config for stack %s", stackKey)
+			}
+			deps := make(map[string][]string, len(doc.Services))
+			for name, svc := range doc.Services {
+				deps[name] = svc.DependsOn
+			}
+			for _, s := range services {
+				if _, ok := deps[s]; !ok {
+					return apperr.New("cli.stack.restart", apperr.InvalidInput, "stack %s has no service %q", stackKey, s)
+				}
+			}
+
+			containers, err := runningContainers(ctx, docker, cfg.Identifier, common.StackProjectName(stackName, stack))
+			if err != nil {
+				return err
+			}
+			order := planner.DependencyOrder(deps, services)
+			restarted := 0
+			for _, svc := range order {
+				for _, name := range containers[svc] {
+					if err := restartContainer(ctx, docker, svc, name); err != nil {
+						return err
+					}
+					pr.Plain("│ restarted %s (%s)", svc, name)
+					restarted++
+				}
+			}
+			if restarted == 0 {
+				return apperr.New("cli.stack.restart", apperr.NotFound, "no running containers found for %s", stackKey)
+			}
+			pr.Plain("│ Restarted %d container(s) in %s", restarted, stackKey)
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVar(&services, "service", nil, "Only restart these services (repeatable or comma-separated)")
+	return cmd
+}
+
+// runningContainers maps each service of a compose project to its running
+// containers, sorted by name.
+func runningContainers(ctx context.Context, docker *dockercli.Client, identifier, project string) (map[string][]string, error) {
+	filters := []string{"label=com.docker.compose.project=" + project}
+	if identifier != "" {
+		filters = append(filters, "label="+dockercli.LabelIdentifier+"="+identifier)
+	}
+	rows, err := docker.PsJSON(ctx, false, filters)
+	if err != nil {
+		return nil, apperr.Wrap("cli.stack.restart", apperr.External, err, "list containers for project %s", project)
+	}
+	out := map[string][]string{}
+	for _, r := range rows {
+		name := strings.TrimSpace(r.Names)
+		svc := composeServiceLabel(r.Labels)
+		if name == "" || svc == "" {
+			continue
+		}
+		out[svc] = append(out[svc], name)
+	}
+	for _, names := range out {
+		sort.Strings(names)
+	}
+	return out, nil
+}
+
+// composeServiceLabel extracts com.docker.compose.service from the
+// comma-separated label list docker ps prints.
+func composeServiceLabel(labels string) string {
+	for _, kv := range strings.Split(labels, ",") {
+		if v, ok := strings.CutPrefix(kv, "com.docker.compose.service="); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+func restartContainer(ctx context.Context, docker *dockercli.Client, service, name string) error {
+	log := logger.FromContext(ctx).With("component", "restart")
+	st := logger.StartStep(log, "service_restart", service, "resource_kind", "service", "container", name)
+	if err := docker.RestartContainer(ctx, name); err != nil {
+		return st.Fail(apperr.Wrap("cli.stack.restart", apperr.External, err, "restart %s (%s)", service, name))
+	}
+	st.OK(true)
+	return nil
+}
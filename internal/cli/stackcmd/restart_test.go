@@ -0,0 +1,88 @@
+package stackcmd_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/cli"
+	"github.com/gcstr/dockform/internal/cli/clitest"
+)
+
+// restartStub serves a compose config where web depends on api and api on db,
+// lists one running container per service and records restarts in order.
+func restartStub(t *testing.T) (string, func()) {
+	t.Helper()
+	restartLog := filepath.Join(t.TempDir(), "restarts")
+	undo := clitest.WithCustomDockerStub(t, `#!/bin/sh
+cmd="$1"; shift
+case "$cmd" in
+  compose)
+    echo '{"services":{"web":{"depends_on":{"api":{"condition":"service_started"}}},"api":{"depends_on":["db"]},"db":{}}}'
+    exit 0 ;;
+  ps)
+    echo '{"Names":"website-web-1","Labels":"com.docker.compose.project=website,com.docker.compose.service=web"}'
+    echo '{"Names":"website-db-1","Labels":"com.docker.compose.service=db,com.docker.compose.project=website"}'
+    echo '{"Names":"website-api-1","Labels":"com.docker.compose.depends_on=db:service_started:false,com.docker.compose.service=api"}'
+    exit 0 ;;
+  container)
+    [ "$1" = "restart" ] && echo "$2" >> "`+restartLog+`"
+    exit 0 ;;
+esac
+exit 0
+`)
+	return restartLog, undo
+}
+
+func runRestart(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs(append([]string{"stack", "restart", "--manifest", clitest.BasicConfigPath(t)}, args...))
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestStackRestart_DependencyOrder(t *testing.T) {
+	restartLog, undo := restartStub(t)
+	defer undo()
+
+	out, err := runRestart(t, "default/website")
+	if err != nil {
+		t.Fatalf("stack restart: %v\n%s", err, out)
+	}
+	b, err := os.ReadFile(restartLog)
+	if err != nil {
+		t.Fatalf("read restart log: %v", err)
+	}
+	if got := strings.Fields(string(b)); strings.Join(got, ",") != "website-db-1,website-api-1,website-web-1" {
+		t.Fatalf("expected db, api, web restart order, got %v", got)
+	}
+	if !strings.Contains(out, "Restarted 3 container(s) in default/website") {
+		t.Fatalf("expected summary, got:\n%s", out)
+	}
+}
+
+func TestStackRestart_ServiceFilter(t *testing.T) {
+	restartLog, undo := restartStub(t)
+	defer undo()
+
+	if out, err := runRestart(t, "website", "--service", "web,db"); err != nil {
+		t.Fatalf("stack restart: %v\n%s", err, out)
+	}
+	b, err := os.ReadFile(restartLog)
+	if err != nil {
+		t.Fatalf("read restart log: %v", err)
+	}
+	if got := strings.Fields(string(b)); strings.Join(got, ",") != "website-db-1,website-web-1" {
+		t.Fatalf("expected only db then web, got %v", got)
+	}
+
+	if _, err := runRestart(t, "website", "--service", "nope"); err == nil || !strings.Contains(err.Error(), `no service "nope"`) {
+		t.Fatalf("expected unknown service to be rejected, got %v", err)
+	}
+}
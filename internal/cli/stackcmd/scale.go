@@ -0,0 +1,115 @@
+package stackcmd
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/cli/common"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/logger"
+	"github.com/gcstr/dockform/internal/manifest"
+	"github.com/gcstr/dockform/internal/planner"
+	"github.com/gcstr/dockform/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func newScaleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scale <[context/]stack> <service>=<replicas>...",
+		Short: "Change the number of containers of a stack's services",
+		Long: `Change the number of containers of a stack's services.
+
+Each service is scaled with docker compose up --scale, without recreating its
+existing containers or touching other services. New containers get the
+identifier label like those created by apply.
+
+The new scale is not written to the manifest: the next apply brings the
+services back to the scale their compose files declare.`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			targets, err := parseScaleTargets(args[1:])
+			if err != nil {
+				return err
+			}
+
+			pr := ui.StdPrinter{Out: cmd.OutOrStdout(), Err: cmd.ErrOrStderr()}
+			cfg, err := common.LoadConfigWithWarnings(cmd, pr)
+			if err != nil {
+				return err
+			}
+			stackKey, stack, err := common.ResolveStack(cfg, args[0])
+			if err != nil {
+				return err
+			}
+			contextName, _, err := manifest.ParseStackKey(stackKey)
+			if err != nil {
+				return err
+			}
+			docker := common.CreateClientFactory(cmd).GetClientForContext(contextName, cfg)
+			ctx := cmd.Context()
+
+			inline, err := planner.NewServiceStateDetector(nil).BuildInlineEnv(ctx, stack, cfg.Sops)
+			if err != nil {
+				return err
+			}
+			services, err := docker.ComposeConfigServices(dockercli.WithStack(ctx, stack), stack.Root, stack.Files, stack.Profiles, stack.EnvFile, inline)
+			if err != nil {
+				return apperr.Wrap("cli.stack.scale", apperr.External, err, "load compose config for stack %s", stackKey)
+			}
+			for _, t := range targets {
+				if !slices.Contains(services, t.service) || stack.IsIgnoredService(t.service) {
+					return apperr.New("cli.stack.scale", apperr.InvalidInput, "stack %s has no managed service %q", stackKey, t.service)
+				}
+			}
+
+			proj := ""
+			if stack.Project != nil {
+				proj = stack.Project.Name
+			}
+			log := logger.FromContext(ctx).With("component", "scale")
+			for _, t := range targets {
+				st := logger.StartStep(log, "service_scale", t.service, "resource_kind", "service", "stack", stackKey, "replicas", t.replicas)
+				if _, err := docker.ComposeScale(dockercli.WithStack(ctx, stack), stack.Root, stack.Files, stack.Profiles, stack.EnvFile, proj, t.service, t.replicas, inline); err != nil {
+					return st.Fail(apperr.Wrap("cli.stack.scale", apperr.External, err, "scale %s in %s", t.service, stackKey))
+				}
+				st.OK(true)
+				pr.Plain("│ scaled %s to %d", t.service, t.replicas)
+			}
+			pr.Warn("%s now differs from the manifest; the next apply restores the declared scale", stackKey)
+			return nil
+		},
+	}
+	return cmd
+}
+
+type scaleTarget struct {
+	service  string
+	replicas int
+}
+
+// parseScaleTargets parses service=replicas arguments, sorted by service.
+func parseScaleTargets(args []string) ([]scaleTarget, error) {
+	seen := map[string]bool{}
+	targets := make([]scaleTarget, 0, len(args))
+	for _, arg := range args {
+		service, count, ok := strings.Cut(arg, "=")
+		service = strings.TrimSpace(service)
+		if !ok || service == "" {
+			return nil, apperr.New("cli.stack.scale", apperr.InvalidInput, "invalid scale %q (expected <service>=<replicas>)", arg)
+		}
+		replicas, err := strconv.Atoi(strings.TrimSpace(count))
+		if err != nil || replicas < 0 {
+			return nil, apperr.New("cli.stack.scale", apperr.InvalidInput, "invalid replicas for %s: %q (expected a non-negative integer)", service, count)
+		}
+		if seen[service] {
+			return nil, apperr.New("cli.stack.scale", apperr.InvalidInput, "service %s is scaled more than once", service)
+		}
+		seen[service] = true
+		targets = append(targets, scaleTarget{service: service, replicas: replicas})
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].service < targets[j].service })
+	return targets, nil
+}
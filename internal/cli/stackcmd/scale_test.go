@@ -0,0 +1,75 @@
+package stackcmd_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/cli"
+	"github.com/gcstr/dockform/internal/cli/clitest"
+)
+
+// scaleStub lists web and db as the stack's services and records the
+// arguments of every compose up.
+func scaleStub(t *testing.T) (string, func()) {
+	t.Helper()
+	upLog := filepath.Join(t.TempDir(), "ups")
+	undo := clitest.WithCustomDockerStub(t, `#!/bin/sh
+cmd="$1"; shift
+case "$cmd" in
+  compose)
+    for a in "$@"; do
+      if [ "$a" = "--services" ]; then printf 'web\ndb\n'; exit 0; fi
+      if [ "$a" = "up" ]; then echo "$*" >> "`+upLog+`"; exit 0; fi
+    done
+    exit 0 ;;
+esac
+exit 0
+`)
+	return upLog, undo
+}
+
+func runScale(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs(append([]string{"stack", "scale", "--manifest", clitest.BasicConfigPath(t)}, args...))
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestStackScale_ScalesServiceAndWarns(t *testing.T) {
+	upLog, undo := scaleStub(t)
+	defer undo()
+
+	out, err := runScale(t, "default/website", "web=3")
+	if err != nil {
+		t.Fatalf("stack scale: %v\n%s", err, out)
+	}
+	b, err := os.ReadFile(upLog)
+	if err != nil {
+		t.Fatalf("read up log: %v", err)
+	}
+	if !strings.Contains(string(b), "up -d --no-deps --no-recreate --scale web=3 web") {
+		t.Fatalf("expected scoped compose up --scale, got: %s", b)
+	}
+	if !strings.Contains(out, "scaled web to 3") || !strings.Contains(out, "differs from the manifest") {
+		t.Fatalf("expected scale report and divergence warning, got:\n%s", out)
+	}
+}
+
+func TestStackScale_RejectsInvalidTargets(t *testing.T) {
+	_, undo := scaleStub(t)
+	defer undo()
+
+	for _, arg := range []string{"web=-1", "web=two", "web", "=2", "cache=1"} {
+		if _, err := runScale(t, "website", arg); err == nil || !apperr.IsKind(err, apperr.InvalidInput) {
+			t.Fatalf("%s: expected invalid input error, got: %v", arg, err)
+		}
+	}
+}
@@ -3,7 +3,11 @@ package validatecmd
 import (
 	"fmt"
 
+	"github.com/gcstr/dockform/internal/apperr"
 	"github.com/gcstr/dockform/internal/cli/common"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/ui"
+	"github.com/gcstr/dockform/internal/validator"
 	"github.com/spf13/cobra"
 )
 
@@ -13,11 +17,50 @@ func New() *cobra.Command {
 		Use:   "validate",
 		Short: "Validate configuration and environment",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Setup CLI context (which includes validation)
-			_, err := common.SetupCLIContext(cmd)
+			strictUnused, _ := cmd.Flags().GetBool("strict-unused")
+			if offline, _ := cmd.Flags().GetBool("offline"); offline {
+				if strictUnused {
+					return apperr.New("cli.validate", apperr.InvalidInput, "--strict-unused needs the resolved compose config and cannot be combined with --offline")
+				}
+				return runOffline(cmd)
+			}
+
+			// Validate directly rather than through SetupCLIContext, so every
+			// problem is listed with its manifest key instead of only the first.
+			pr := ui.StdPrinter{Out: cmd.OutOrStdout(), Err: cmd.ErrOrStderr()}
+			cfg, err := common.LoadConfigWithWarnings(cmd, pr)
 			if err != nil {
 				return err
 			}
+			common.DisplayDaemonInfo(pr, cfg)
+			factory := common.CreateClientFactory(cmd)
+			if err := common.EnsureContextsReachable(cmd.Context(), cfg, factory); err != nil {
+				return err
+			}
+			common.ActivateSSHMux(cmd, cfg)
+
+			var warnings []string
+			var docs map[string]dockercli.ComposeConfigDoc
+			err = common.SpinnerOperation(pr, "Validating...", func() error {
+				var verr error
+				warnings, docs, verr = validator.ValidateWithComposeDocs(cmd.Context(), *cfg, factory)
+				return verr
+			})
+			if err != nil {
+				return reportProblems(pr, err)
+			}
+			for _, w := range warnings {
+				pr.Warn("%s", w)
+			}
+
+			// Report declarations no compose service references
+			unused := validator.UnusedDeclarations(*cfg, docs)
+			for _, u := range unused {
+				pr.Warn("%s", u)
+			}
+			if strictUnused && len(unused) > 0 {
+				return apperr.New("cli.validate", apperr.InvalidInput, "%d unused declaration(s) found (--strict-unused)", len(unused))
+			}
 
 			// If we get here, validation was successful
 			if _, err := fmt.Fprintln(cmd.OutOrStdout(), "validation successful"); err != nil {
@@ -26,5 +69,46 @@ func New() *cobra.Command {
 			return nil
 		},
 	}
+	cmd.Flags().Bool("offline", false, "Validate the manifest and referenced files without contacting any docker daemon")
+	cmd.Flags().Bool("strict-unused", false, "Fail when a declared volume, network or fileset target is not used by any compose service")
 	return cmd
 }
+
+// runOffline validates the manifest without a docker daemon: no reachability
+// check, and none of the validations that need docker compose or the daemon.
+func runOffline(cmd *cobra.Command) error {
+	pr := ui.StdPrinter{Out: cmd.OutOrStdout(), Err: cmd.ErrOrStderr()}
+	cfg, err := common.LoadConfigWithWarnings(cmd, pr)
+	if err != nil {
+		return err
+	}
+	common.DisplayDaemonInfo(pr, cfg)
+
+	warnings, err := validator.ValidateOffline(cmd.Context(), *cfg)
+	if err != nil {
+		return reportProblems(pr, err)
+	}
+	for _, w := range warnings {
+		pr.Warn("%s", w)
+	}
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), "validation successful (offline)")
+	return err
+}
+
+// reportProblems prints each validation problem in err with the manifest line
+// it concerns and returns a summary error. Errors that are not validation
+// problems, such as an interrupt, are returned unchanged.
+func reportProblems(pr ui.Printer, err error) error {
+	problems := validator.Problems(err)
+	if len(problems) == 0 {
+		return err
+	}
+	for _, p := range problems {
+		pr.Plain("│ %s %s", ui.RedText("×"), p.String())
+	}
+	noun := "problems"
+	if len(problems) == 1 {
+		noun = "problem"
+	}
+	return apperr.Wrap("cli.validate", apperr.InvalidInput, err, "validation failed: %d %s found", len(problems), noun)
+}
@@ -93,3 +93,101 @@ func writeFile(t *testing.T, path string, data string) {
 		t.Fatalf("write file: %v", err)
 	}
 }
+
+func TestValidate_StrictUnused(t *testing.T) {
+	defer clitest.WithStubDocker(t)()
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "website"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "website", "docker-compose.yaml"), []byte("services: {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := "identifier: demo\ncontexts:\n  default:\n    volumes:\n      stale-data: {}\nstacks:\n  default/website:\n    root: website\n    files:\n      - docker-compose.yaml\n"
+	cfgPath := filepath.Join(dir, "dockform.yml")
+	if err := os.WriteFile(cfgPath, []byte(cfg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) (string, error) {
+		root := cli.TestNewRootCmd()
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetErr(&out)
+		root.SetArgs(append([]string{"validate", "--manifest", cfgPath}, args...))
+		err := root.Execute()
+		return out.String(), err
+	}
+
+	out, err := run()
+	if err != nil {
+		t.Fatalf("expected unused declarations to only warn, got: %v", err)
+	}
+	if !strings.Contains(out, "volume stale-data in context default is declared but not used by any service") {
+		t.Fatalf("expected unused volume warning, got: %s", out)
+	}
+
+	if _, err := run("--strict-unused"); err == nil || !strings.Contains(err.Error(), "1 unused declaration(s)") {
+		t.Fatalf("expected --strict-unused to fail, got: %v", err)
+	}
+}
+
+func TestValidate_Offline_SkipsDaemon(t *testing.T) {
+	undo := clitest.WithCustomDockerStub(t, `#!/bin/sh
+echo "docker must not be called" 1>&2; exit 1
+`)
+	defer undo()
+
+	run := func(args ...string) (string, error) {
+		root := cli.TestNewRootCmd()
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetErr(&out)
+		root.SetArgs(append([]string{"validate", "--manifest", clitest.BasicConfigPath(t)}, args...))
+		err := root.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("--offline")
+	if err != nil {
+		t.Fatalf("expected offline validation to succeed without docker, got: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "validation successful (offline)") {
+		t.Fatalf("expected offline success message, got: %q", out)
+	}
+	if _, err := run("--offline", "--strict-unused"); err == nil {
+		t.Fatalf("expected --strict-unused to be rejected with --offline")
+	}
+}
+
+func TestValidate_ListsEveryProblem(t *testing.T) {
+	defer clitest.WithStubDocker(t)()
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "website"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "website", "docker-compose.yaml"), "services: {}\n")
+	cfgPath := filepath.Join(dir, "dockform.yml")
+	writeFile(t, cfgPath, "identifier: demo\ncontexts:\n  default: {}\nstacks:\n  default/website:\n    root: website\n    files:\n      - docker-compose.yaml\n    env-file:\n      - missing.env\n  default/api:\n    root: api\n    files:\n      - compose.yaml\n")
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"validate", "--manifest", cfgPath})
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "validation failed: 2 problems found") {
+		t.Fatalf("expected a summary error, got: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		cfgPath + ":12: stack default/api root",
+		cfgPath + ":9: stack default/website env file missing.env",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in output, got: %s", want, got)
+		}
+	}
+}
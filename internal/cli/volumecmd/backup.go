@@ -0,0 +1,92 @@
+package volumecmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/cli/common"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/ui"
+)
+
+// DefaultBackupDir returns the directory pre-apply backups are written to when
+// no --backup-dir is given.
+func DefaultBackupDir(baseDir string) string {
+	return filepath.Join(baseDir, ".dockform", "backups")
+}
+
+// BackupVolumes snapshots every manifest volume that dockform manages into a
+// new timestamped directory under dir and returns that directory. Volumes that
+// don't exist yet are skipped, as are external volumes — those present on the
+// daemon without this manifest's identifier label. Any failure aborts the
+// backup so callers can stop before mutating anything.
+func BackupVolumes(ctx context.Context, clictx *common.CLIContext, dir string) (string, error) {
+	pr := clictx.Printer
+	runDir := filepath.Join(dir, time.Now().UTC().Format("2006-01-02T15-04-05Z"))
+	if err := os.MkdirAll(runDir, 0o755); err != nil {
+		return "", apperr.Wrap("cli.apply.backup", apperr.Precondition, err, "cannot write volume backups to %s", dir)
+	}
+
+	targets := manifestVolumes(clictx.Config)
+	var lines []string
+	var backedUp, skipped int
+	stdPr := pr.(ui.StdPrinter)
+	err := common.SpinnerOperation(stdPr, fmt.Sprintf("Backing up %d volumes...", len(targets)), func() error {
+		for _, t := range targets {
+			docker := clictx.Factory.GetClientForContext(t.Context, clictx.Config)
+			reason, err := backupSkipReason(ctx, docker, t.Volume, clictx.Config.Identifier)
+			if err != nil {
+				return apperr.Wrap("cli.apply.backup", apperr.External, err, "back up volume %s", t)
+			}
+			if reason != "" {
+				skipped++
+				lines = append(lines, fmt.Sprintf("│ - %s skipped (%s)", t, reason))
+				continue
+			}
+			res, err := createSnapshot(ctx, docker, nil, t.Context, t.Volume, runDir, "pre-apply backup")
+			if err != nil {
+				return apperr.Wrap("cli.apply.backup", apperr.External, err, "back up volume %s", t)
+			}
+			backedUp++
+			lines = append(lines, fmt.Sprintf("│ %s %s  %s", ui.SuccessMark(), t, formatSize(res.Bytes)))
+		}
+		return nil
+	})
+	for _, line := range lines {
+		pr.Plain("%s", line)
+	}
+	if err != nil {
+		return "", err
+	}
+	summary := fmt.Sprintf("%d backed up", backedUp)
+	if skipped > 0 {
+		summary += fmt.Sprintf(", %d skipped", skipped)
+	}
+	pr.Plain("│ Volume backups: %s → %s", summary, runDir)
+	return runDir, nil
+}
+
+// backupSkipReason reports why a volume should not be backed up, or "" when it
+// should be.
+func backupSkipReason(ctx context.Context, docker *dockercli.Client, volName, identifier string) (string, error) {
+	exists, err := docker.VolumeExists(ctx, volName)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "not created yet", nil
+	}
+	details, err := docker.InspectVolume(ctx, volName)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(details.Labels[dockercli.LabelIdentifier]) != identifier {
+		return "external", nil
+	}
+	return "", nil
+}
@@ -1,6 +1,7 @@
 package volumecmd
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/gcstr/dockform/internal/dockercli"
@@ -49,3 +50,33 @@ func TestManifestHasVolume(t *testing.T) {
 		}
 	}
 }
+
+func TestManifestVolumes_SortedAndDeduplicated(t *testing.T) {
+	cfg := &manifest.Config{
+		Contexts: map[string]manifest.ContextConfig{
+			"b": {Volumes: map[string]manifest.TopLevelResourceSpec{"data": {}}},
+			"a": {Volumes: map[string]manifest.TopLevelResourceSpec{"logs": {}, "cache": {}}},
+		},
+		DiscoveredFilesets: map[string]manifest.FilesetSpec{
+			"a/web/cache": {TargetVolume: "cache", Context: "a"},
+			"b/web/site":  {TargetVolume: "site", Context: "b"},
+		},
+	}
+	var got []string
+	for _, v := range manifestVolumes(cfg) {
+		got = append(got, v.String())
+	}
+	want := "a/cache,a/logs,b/data,b/site"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("manifestVolumes = %v, want %s", got, want)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	cases := map[int64]string{0: "0 B", 1023: "1023 B", 1536: "1.5 KiB", 5 * 1024 * 1024: "5.0 MiB"}
+	for in, want := range cases {
+		if got := formatSize(in); got != want {
+			t.Fatalf("formatSize(%d) = %q, want %q", in, got, want)
+		}
+	}
+}
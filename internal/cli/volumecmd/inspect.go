@@ -0,0 +1,261 @@
+package volumecmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gcstr/dockform/internal/cli/common"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+	"github.com/gcstr/dockform/internal/planner"
+	"github.com/gcstr/dockform/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// volumeInspection is a docker volume inspect enriched with what dockform
+// knows about the volume's role in the deployment.
+type volumeInspection struct {
+	Context    string            `json:"context,omitempty"`
+	Name       string            `json:"name"`
+	Driver     string            `json:"driver"`
+	Mountpoint string            `json:"mountpoint"`
+	Options    map[string]string `json:"options"`
+	Labels     map[string]string `json:"labels"`
+	Managed    bool              `json:"managed"`
+	Declared   bool              `json:"declared"`
+	Mounts     []volumeMount     `json:"mounts"`
+	Filesets   []string          `json:"filesets"`
+	Containers []string          `json:"containers"`
+	Bytes      *int64            `json:"bytes,omitempty"`
+	Files      *int64            `json:"files,omitempty"`
+	Warnings   []string          `json:"warnings,omitempty"`
+}
+
+// volumeMount is a compose service that mounts the volume.
+type volumeMount struct {
+	Stack    string `json:"stack"`
+	Service  string `json:"service"`
+	Target   string `json:"target"`
+	ReadOnly bool   `json:"read_only,omitempty"`
+}
+
+// inspectVolume gathers the docker details of volName and cross-references them
+// with the manifest: the identifier label, the stacks whose services mount it,
+// the filesets that target it and the containers using it. Information that
+// cannot be gathered is reported as a warning rather than failing the inspect.
+func inspectVolume(ctx context.Context, cfg *manifest.Config, docker *dockercli.Client, contextName, volName string, withSize bool) (volumeInspection, error) {
+	details, err := docker.InspectVolume(ctx, volName)
+	if err != nil {
+		return volumeInspection{}, err
+	}
+	info := volumeInspection{
+		Context:    contextName,
+		Name:       details.Name,
+		Driver:     details.Driver,
+		Mountpoint: details.Mountpoint,
+		Options:    details.Options,
+		Labels:     details.Labels,
+		Declared:   manifestHasVolume(cfg, contextName, volName),
+		Mounts:     []volumeMount{},
+		Filesets:   []string{},
+		Containers: []string{},
+	}
+	if info.Name == "" {
+		info.Name = volName
+	}
+	info.Managed = cfg.Identifier != "" && details.Labels[dockercli.LabelIdentifier] == cfg.Identifier
+
+	stacks := cfg.GetStacksForContext(contextName)
+	stackNames := make([]string, 0, len(stacks))
+	for name := range stacks {
+		stackNames = append(stackNames, name)
+	}
+	sort.Strings(stackNames)
+	for _, stackName := range stackNames {
+		stack := stacks[stackName]
+		inline, err := planner.NewServiceStateDetector(nil).BuildInlineEnv(ctx, stack, cfg.Sops)
+		if err != nil {
+			info.Warnings = append(info.Warnings, fmt.Sprintf("stack %s: %v", stackName, err))
+			continue
+		}
+		doc, err := docker.ComposeConfigFull(dockercli.WithStack(ctx, stack), stack.Root, stack.Files, stack.Profiles, stack.EnvFile, inline)
+		if err != nil {
+			info.Warnings = append(info.Warnings, fmt.Sprintf("stack %s: %v", stackName, err))
+			continue
+		}
+		info.Mounts = append(info.Mounts, composeMounts(stackName, common.StackProjectName(stackName, stack), doc, volName)...)
+	}
+
+	for name, fs := range cfg.GetFilesetsForContext(contextName) {
+		if fs.TargetVolume == volName {
+			info.Filesets = append(info.Filesets, name)
+		}
+	}
+	sort.Strings(info.Filesets)
+
+	if containers, err := docker.ListContainersUsingVolume(ctx, volName); err != nil {
+		info.Warnings = append(info.Warnings, fmt.Sprintf("containers: %v", err))
+	} else if len(containers) > 0 {
+		sort.Strings(containers)
+		info.Containers = containers
+	}
+
+	if withSize {
+		if bytes, files, err := docker.TarStatsFromVolume(ctx, volName); err != nil {
+			info.Warnings = append(info.Warnings, fmt.Sprintf("size: %v", err))
+		} else {
+			info.Bytes, info.Files = &bytes, &files
+		}
+	}
+	return info, nil
+}
+
+// composeMounts returns the services of a compose project that mount volName.
+// A service volume key matches under the docker names it may resolve to: the
+// key itself, its project-scoped name and an explicit top-level name.
+func composeMounts(stackName, project string, doc dockercli.ComposeConfigDoc, volName string) []volumeMount {
+	var out []volumeMount
+	for svcName, svc := range doc.Services {
+		for _, m := range svc.Volumes {
+			if m.Type != "volume" || m.Source == "" {
+				continue
+			}
+			resolved := m.Source == volName || project+"_"+m.Source == volName
+			if top, ok := doc.Volumes[m.Source]; ok && top.Name != "" {
+				resolved = top.Name == volName
+			}
+			if resolved {
+				out = append(out, volumeMount{Stack: stackName, Service: svcName, Target: m.Target, ReadOnly: m.ReadOnly})
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Service != out[j].Service {
+			return out[i].Service < out[j].Service
+		}
+		return out[i].Target < out[j].Target
+	})
+	return out
+}
+
+func newInspectCmd() *cobra.Command {
+	var jsonOut bool
+	var noSize bool
+	cmd := &cobra.Command{
+		Use:   "inspect <[context/]volume>",
+		Short: "Show a volume with its role in the deployment",
+		Long: `Show a volume with its role in the deployment.
+
+Combines docker volume inspect with what dockform knows about the volume:
+whether it carries the manifest identifier, which stack services mount it,
+which filesets target it, the containers using it and its size. The size is
+measured with a short-lived helper container; --no-size skips it.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Inspect is read-only, so it skips the manifest validation of
+			// SetupCLIContext. With --json the header and warnings go to stderr
+			// to keep stdout parseable.
+			pr := ui.StdPrinter{Out: cmd.OutOrStdout(), Err: cmd.ErrOrStderr()}
+			if jsonOut {
+				pr.Out = cmd.ErrOrStderr()
+			}
+			cfg, err := common.LoadConfigWithWarnings(cmd, pr)
+			if err != nil {
+				return err
+			}
+			if !jsonOut {
+				common.DisplayDaemonInfo(pr, cfg)
+			}
+			factory := common.CreateClientFactory(cmd)
+			if err := common.EnsureContextsReachable(cmd.Context(), cfg, factory); err != nil {
+				return err
+			}
+			clictx := &common.CLIContext{Ctx: cmd.Context(), Config: cfg, Factory: factory, Printer: pr}
+			contextName, volName, docker, err := resolveVolumeTarget(clictx, args[0])
+			if err != nil {
+				return err
+			}
+			info, err := inspectVolume(cmd.Context(), clictx.Config, docker, contextName, volName, !noSize)
+			if err != nil {
+				return err
+			}
+			if jsonOut {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(info)
+			}
+			renderVolumeInspection(clictx.Printer, info)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output the inspection as JSON")
+	cmd.Flags().BoolVar(&noSize, "no-size", false, "Skip measuring the volume size with a helper container")
+	return cmd
+}
+
+// renderVolumeInspection prints the inspection as aligned key/value rows.
+func renderVolumeInspection(pr ui.Printer, info volumeInspection) {
+	yesNo := func(b bool) string {
+		if b {
+			return "yes"
+		}
+		return "no"
+	}
+	orNone := func(items []string) string {
+		if len(items) == 0 {
+			return "-"
+		}
+		return strings.Join(items, ", ")
+	}
+
+	name := info.Name
+	if info.Context != "" {
+		name = info.Context + "/" + info.Name
+	}
+	size := "-"
+	if info.Bytes != nil {
+		size = formatSize(*info.Bytes)
+		if info.Files != nil {
+			size += fmt.Sprintf(" (%d files)", *info.Files)
+		}
+	}
+	var mounts []string
+	for _, m := range info.Mounts {
+		mount := fmt.Sprintf("%s/%s:%s", m.Stack, m.Service, m.Target)
+		if m.ReadOnly {
+			mount += " (ro)"
+		}
+		mounts = append(mounts, mount)
+	}
+	var opts []string
+	for k, v := range info.Options {
+		opts = append(opts, k+"="+v)
+	}
+	sort.Strings(opts)
+
+	rows := [][2]string{
+		{"VOLUME", name},
+		{"DRIVER", info.Driver},
+		{"MOUNTPOINT", info.Mountpoint},
+		{"OPTIONS", orNone(opts)},
+		{"MANAGED", yesNo(info.Managed)},
+		{"IN MANIFEST", yesNo(info.Declared)},
+		{"MOUNTED BY", orNone(mounts)},
+		{"FILESETS", orNone(info.Filesets)},
+		{"CONTAINERS", orNone(info.Containers)},
+		{"SIZE", size},
+	}
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row[0]))
+	}
+	for _, row := range rows {
+		pr.Plain("%-*s  %s", width, row[0], row[1])
+	}
+	for _, w := range info.Warnings {
+		pr.Warn("%s", w)
+	}
+}
@@ -0,0 +1,103 @@
+package volumecmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/cli"
+	"github.com/gcstr/dockform/internal/cli/clitest"
+)
+
+const inspectDockerStub = `#!/bin/sh
+cmd="$1"; shift
+case "$cmd" in
+  version)
+    exit 0 ;;
+  volume)
+    if [ "$1" = "inspect" ]; then
+      echo '{"Name":"website_data","Driver":"local","Mountpoint":"/var/lib/docker/volumes/website_data/_data","Labels":{"io.dockform.identifier":"demo"},"Options":{}}'
+    fi
+    exit 0 ;;
+  compose)
+    for a in "$@"; do
+      if [ "$a" = "json" ]; then
+        echo '{"name":"website","services":{"web":{"image":"nginx:alpine","volumes":[{"type":"volume","source":"website_data","target":"/data"}]},"cache":{"image":"redis","volumes":[{"type":"volume","source":"other","target":"/data"}]}},"volumes":{"website_data":{"name":"website_data"},"other":{"name":"website_other"}}}'
+        exit 0
+      fi
+    done
+    exit 0 ;;
+  ps)
+    echo "website-web-1"
+    exit 0 ;;
+  run)
+    echo "3 2048"
+    exit 0 ;;
+esac
+exit 0
+`
+
+func TestVolumeInspect_JSON(t *testing.T) {
+	cfgPath := volumeConfigPath(t)
+	defer clitest.WithCustomDockerStub(t, inspectDockerStub)()
+
+	root := cli.TestNewRootCmd()
+	var out, errOut bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&errOut)
+	root.SetArgs([]string{"volume", "inspect", "website_data", "--json", "--manifest", cfgPath})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("volume inspect: %v\n%s", err, errOut.String())
+	}
+
+	var got struct {
+		Managed bool `json:"managed"`
+		Mounts  []struct {
+			Stack   string `json:"stack"`
+			Service string `json:"service"`
+			Target  string `json:"target"`
+		} `json:"mounts"`
+		Filesets   []string `json:"filesets"`
+		Containers []string `json:"containers"`
+		Bytes      *int64   `json:"bytes"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v\n%s", err, out.String())
+	}
+	if !got.Managed {
+		t.Errorf("expected the identifier label to mark the volume managed")
+	}
+	if len(got.Mounts) != 1 || got.Mounts[0].Service != "web" || got.Mounts[0].Target != "/data" {
+		t.Errorf("expected only web to mount the volume, got %+v", got.Mounts)
+	}
+	if len(got.Containers) != 1 || got.Containers[0] != "website-web-1" {
+		t.Errorf("unexpected containers %v", got.Containers)
+	}
+	if got.Bytes == nil || *got.Bytes != 2048 {
+		t.Errorf("expected the measured size, got %v", got.Bytes)
+	}
+}
+
+func TestVolumeInspect_Table(t *testing.T) {
+	cfgPath := volumeConfigPath(t)
+	defer clitest.WithCustomDockerStub(t, inspectDockerStub)()
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"volume", "inspect", "website_data", "--no-size", "--manifest", cfgPath})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("volume inspect: %v\n%s", err, out.String())
+	}
+	got := out.String()
+	for _, want := range []string{"default/website_data", "website/web:/data", "website-web-1"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output:\n%s", want, got)
+		}
+	}
+	if !strings.Contains(got, "SIZE") || strings.Contains(got, "2.0 KiB") {
+		t.Errorf("expected --no-size to leave the size unmeasured:\n%s", got)
+	}
+}
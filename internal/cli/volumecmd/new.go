@@ -1,6 +1,7 @@
 package volumecmd
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -25,11 +26,12 @@ import (
 func New() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "volume",
-		Short: "Manage Docker volumes (snapshots, restore)",
+		Short: "Manage Docker volumes (inspect, snapshots, restore)",
 		Args:  cobra.NoArgs,
 	}
 	cmd.AddCommand(newSnapshotCmd())
 	cmd.AddCommand(newRestoreCmd())
+	cmd.AddCommand(newInspectCmd())
 	return cmd
 }
 
@@ -154,19 +156,123 @@ func manifestHasVolume(cfg *manifest.Config, contextName, name string) bool {
 	return false
 }
 
+// snapshotResult describes a snapshot written to local storage.
+type snapshotResult struct {
+	TarPath  string
+	JSONPath string
+	Bytes    int64
+}
+
+// createSnapshot streams volName into a timestamped tar.zst under
+// outDir/<context>/<volume> and writes the JSON metadata sidecar next to it.
+// When pr is non-nil the streaming step runs behind a spinner.
+func createSnapshot(ctx context.Context, docker *dockercli.Client, pr *ui.StdPrinter, contextName, volName, outDir, note string) (snapshotResult, error) {
+	// Inspect volume to get spec
+	details, err := docker.InspectVolume(ctx, volName)
+	if err != nil {
+		return snapshotResult{}, err
+	}
+	short := computeSpecHash(details)
+	ts := time.Now().UTC().Format("2006-01-02T15-04-05Z")
+	// Key snapshots by context so the same volume name on different hosts
+	// doesn't collide.
+	volDir := filepath.Join(outDir, contextName, volName)
+	if err := os.MkdirAll(volDir, 0o755); err != nil {
+		return snapshotResult{}, apperr.Wrap("cli.volume.snapshot", apperr.Internal, err, "mkdir %s", volDir)
+	}
+	base := fmt.Sprintf("%s__spec-%s", ts, short)
+	tarPath := filepath.Join(volDir, base+".tar.zst")
+	jsonPath := filepath.Join(volDir, base+".json")
+
+	// Stream tar.zst to file
+	f, err := os.Create(tarPath)
+	if err != nil {
+		return snapshotResult{}, apperr.Wrap("cli.volume.snapshot", apperr.Internal, err, "create tar.zst")
+	}
+	defer func() { _ = f.Close() }()
+
+	stream := func() error { return docker.StreamTarZstdFromVolume(ctx, volName, f) }
+	if pr != nil {
+		err = common.SpinnerOperation(*pr, "Creating snapshot...", stream)
+	} else {
+		err = stream()
+	}
+	if err != nil {
+		return snapshotResult{}, err
+	}
+
+	// Compute stats and checksum
+	uncompressed, fileCount, err := docker.TarStatsFromVolume(ctx, volName)
+	if err != nil {
+		// Non-fatal, but helpful; continue without stats
+		uncompressed, fileCount = 0, 0
+	}
+	sum, err := util.Sha256FileHex(tarPath)
+	if err != nil {
+		return snapshotResult{}, apperr.Wrap("cli.volume.snapshot", apperr.Internal, err, "checksum tar.zst")
+	}
+
+	meta := snapshotMeta{
+		DockformVersion:   buildinfo.Version(),
+		CreatedAt:         time.Now().UTC().Format(time.RFC3339),
+		VolumeName:        volName,
+		SpecHash:          short,
+		Driver:            details.Driver,
+		DriverOpts:        details.Options,
+		Labels:            details.Labels,
+		UncompressedBytes: uncompressed,
+		FileCount:         fileCount,
+		Notes:             note,
+	}
+	meta.Checksum.Algo = "sha256"
+	meta.Checksum.TarZst = sum
+
+	// Write JSON sidecar
+	jb, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return snapshotResult{}, apperr.Wrap("cli.volume.snapshot", apperr.Internal, err, "encode json")
+	}
+	if err := os.WriteFile(jsonPath, jb, 0o644); err != nil {
+		return snapshotResult{}, apperr.Wrap("cli.volume.snapshot", apperr.Internal, err, "write json")
+	}
+
+	res := snapshotResult{TarPath: tarPath, JSONPath: jsonPath}
+	if st, err := os.Stat(tarPath); err == nil {
+		res.Bytes = st.Size()
+	}
+	return res, nil
+}
+
 func newSnapshotCmd() *cobra.Command {
 	var outDirFlag string
 	var note string
+	var all bool
+	var concurrency int
+	var strict bool
 	cmd := &cobra.Command{
-		Use:   "snapshot <[context/]volume>",
+		Use:   "snapshot [<[context/]volume>]",
 		Short: "Create a snapshot of a Docker volume to local storage",
 		Long: `Create a snapshot of a Docker volume to local storage.
 
 For multi-context setups, address the volume as <context>/<volume>
 (e.g. hetzner-two/netbird_data). A bare volume name is allowed only when a
-single context is configured.`,
-		Args:  cobra.ExactArgs(1),
+single context is configured.
+
+With --all, every volume declared in the manifest is snapshotted, up to
+--concurrency at a time. Failures are collected and reported at the end;
+--strict stops scheduling new snapshots after the first failure.
+
+Use 'snapshot list [<dir>]' to browse existing snapshots. A volume literally
+named "list" must be addressed as <context>/list.`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if all == (len(args) == 1) {
+				return apperr.New("cli.volume.snapshot", apperr.InvalidInput, "specify either a volume or --all")
+			}
+			if concurrency < 1 {
+				return apperr.New("cli.volume.snapshot", apperr.InvalidInput, "--concurrency must be at least 1")
+			}
+
 			ctx := cmd.Context()
 			clictx, err := common.SetupCLIContext(cmd)
 			if err != nil {
@@ -174,87 +280,36 @@ single context is configured.`,
 			}
 
 			pr := clictx.Printer
-			contextName, volName, docker, err := resolveVolumeTarget(clictx, args[0])
-			if err != nil {
-				return err
-			}
 			// Default output next to manifest
 			outDir := outDirFlag
 			if strings.TrimSpace(outDir) == "" {
 				outDir = filepath.Join(clictx.Config.BaseDir, ".dockform", "snapshots")
 			}
-			// Inspect volume to get spec
-			details, err := docker.InspectVolume(ctx, volName)
-			if err != nil {
-				return err
-			}
-			short := computeSpecHash(details)
-			ts := time.Now().UTC().Format("2006-01-02T15-04-05Z")
-			// Key snapshots by context so the same volume name on different hosts
-			// doesn't collide.
-			volDir := filepath.Join(outDir, contextName, volName)
-			if err := os.MkdirAll(volDir, 0o755); err != nil {
-				return apperr.Wrap("cli.volume.snapshot", apperr.Internal, err, "mkdir %s", volDir)
-			}
-			base := fmt.Sprintf("%s__spec-%s", ts, short)
-			tarPath := filepath.Join(volDir, base+".tar.zst")
-			jsonPath := filepath.Join(volDir, base+".json")
 
-			// Stream tar.zst to file
-			f, err := os.Create(tarPath)
-			if err != nil {
-				return apperr.Wrap("cli.volume.snapshot", apperr.Internal, err, "create tar.zst")
+			if all {
+				return snapshotAll(ctx, clictx, outDir, note, concurrency, strict)
 			}
-			defer func() { _ = f.Close() }()
 
-			stdPr := pr.(ui.StdPrinter)
-			if err := common.SpinnerOperation(stdPr, "Creating snapshot...", func() error {
-				return docker.StreamTarZstdFromVolume(ctx, volName, f)
-			}); err != nil {
-				return err
-			}
-
-			// Compute stats and checksum
-			uncompressed, fileCount, err := docker.TarStatsFromVolume(ctx, volName)
-			if err != nil {
-				// Non-fatal, but helpful; continue without stats
-				uncompressed, fileCount = 0, 0
-			}
-			sum, err := util.Sha256FileHex(tarPath)
+			contextName, volName, docker, err := resolveVolumeTarget(clictx, args[0])
 			if err != nil {
-				return apperr.Wrap("cli.volume.snapshot", apperr.Internal, err, "checksum tar.zst")
-			}
-
-			meta := snapshotMeta{
-				DockformVersion:   buildinfo.Version(),
-				CreatedAt:         time.Now().UTC().Format(time.RFC3339),
-				VolumeName:        volName,
-				SpecHash:          short,
-				Driver:            details.Driver,
-				DriverOpts:        details.Options,
-				Labels:            details.Labels,
-				UncompressedBytes: uncompressed,
-				FileCount:         fileCount,
-				Notes:             note,
+				return err
 			}
-			meta.Checksum.Algo = "sha256"
-			meta.Checksum.TarZst = sum
-
-			// Write JSON sidecar
-			jb, err := json.MarshalIndent(meta, "", "  ")
+			stdPr := pr.(ui.StdPrinter)
+			res, err := createSnapshot(ctx, docker, &stdPr, contextName, volName, outDir, note)
 			if err != nil {
-				return apperr.Wrap("cli.volume.snapshot", apperr.Internal, err, "encode json")
-			}
-			if err := os.WriteFile(jsonPath, jb, 0o644); err != nil {
-				return apperr.Wrap("cli.volume.snapshot", apperr.Internal, err, "write json")
+				return err
 			}
-			pr.Info("Snapshot written: %s", tarPath)
-			pr.Plain("Metadata: %s", jsonPath)
+			pr.Info("Snapshot written: %s", res.TarPath)
+			pr.Plain("Metadata: %s", res.JSONPath)
 			return nil
 		},
 	}
 	cmd.Flags().StringVarP(&outDirFlag, "output", "o", "", "Output directory for snapshots (defaults to ./.dockform/snapshots next to manifest)")
 	cmd.Flags().StringVar(&note, "note", "", "Optional note to include in metadata")
+	cmd.Flags().BoolVar(&all, "all", false, "Snapshot every volume declared in the manifest")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 1, "Number of volumes to snapshot in parallel with --all")
+	cmd.Flags().BoolVar(&strict, "strict", false, "With --all, stop starting new snapshots after the first failure")
+	cmd.AddCommand(newSnapshotListCmd())
 	return cmd
 }
 
@@ -12,7 +12,7 @@ func ctxWith(names ...string) *common.CLIContext {
 	for _, n := range names {
 		cfg.Contexts[n] = manifest.ContextConfig{}
 	}
-	return &common.CLIContext{Config: cfg, Factory: common.CreateClientFactory()}
+	return &common.CLIContext{Config: cfg, Factory: common.CreateClientFactory(nil)}
 }
 
 func TestResolveVolumeTarget(t *testing.T) {
@@ -0,0 +1,180 @@
+package volumecmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/cli/common"
+	"github.com/gcstr/dockform/internal/manifest"
+	"github.com/gcstr/dockform/internal/ui"
+)
+
+// volumeTarget is a manifest-declared volume on a specific context.
+type volumeTarget struct {
+	Context string
+	Volume  string
+}
+
+func (v volumeTarget) String() string { return v.Context + "/" + v.Volume }
+
+// batchSnapshotResult is the outcome of snapshotting one volume in a batch.
+type batchSnapshotResult struct {
+	Target   volumeTarget
+	Snapshot snapshotResult
+	InUseBy  []string
+	Duration time.Duration
+	Err      error
+	Skipped  bool
+}
+
+// manifestVolumes lists every volume declared in the manifest — context-level
+// volumes and fileset target volumes — sorted by context and name.
+func manifestVolumes(cfg *manifest.Config) []volumeTarget {
+	var out []volumeTarget
+	for contextName, cc := range cfg.Contexts {
+		seen := map[string]struct{}{}
+		for name := range cc.Volumes {
+			seen[name] = struct{}{}
+		}
+		for _, fs := range cfg.GetFilesetsForContext(contextName) {
+			if fs.TargetVolume != "" {
+				seen[fs.TargetVolume] = struct{}{}
+			}
+		}
+		for name := range seen {
+			out = append(out, volumeTarget{Context: contextName, Volume: name})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Context != out[j].Context {
+			return out[i].Context < out[j].Context
+		}
+		return out[i].Volume < out[j].Volume
+	})
+	return out
+}
+
+// snapshotAll snapshots every manifest volume with a bounded worker pool. Each
+// volume is checked and written independently; failures are collected and
+// reported in the summary. With strict, no new snapshots start after the first
+// failure.
+func snapshotAll(ctx context.Context, clictx *common.CLIContext, outDir, note string, concurrency int, strict bool) error {
+	pr := clictx.Printer
+	targets := manifestVolumes(clictx.Config)
+	if len(targets) == 0 {
+		pr.Info("No volumes declared in manifest")
+		return nil
+	}
+
+	results := make([]batchSnapshotResult, len(targets))
+	var stop atomic.Bool
+
+	stdPr := pr.(ui.StdPrinter)
+	_ = common.SpinnerOperation(stdPr, fmt.Sprintf("Creating %d snapshots...", len(targets)), func() error {
+		sem := make(chan struct{}, concurrency)
+		var wg sync.WaitGroup
+		for i, t := range targets {
+			sem <- struct{}{}
+			if stop.Load() || ctx.Err() != nil {
+				<-sem
+				results[i] = batchSnapshotResult{Target: t, Skipped: true}
+				continue
+			}
+			wg.Add(1)
+			go func(i int, t volumeTarget) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				results[i] = snapshotOne(ctx, clictx, t, outDir, note)
+				if results[i].Err != nil && strict {
+					stop.Store(true)
+				}
+			}(i, t)
+		}
+		wg.Wait()
+		return nil
+	})
+
+	var errs []error
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, apperr.Wrap("cli.volume.snapshot", apperr.External, r.Err, "%s", r.Target))
+		}
+	}
+	printSnapshotSummary(pr, results)
+	if len(errs) == 0 {
+		return nil
+	}
+	return apperr.Aggregate("cli.volume.snapshot", apperr.External, fmt.Sprintf("%d of %d snapshots failed", len(errs), len(targets)), errs...)
+}
+
+// snapshotOne snapshots a single volume, running its own existence and in-use
+// checks so one volume's state never affects another's.
+func snapshotOne(ctx context.Context, clictx *common.CLIContext, t volumeTarget, outDir, note string) batchSnapshotResult {
+	start := time.Now()
+	res := batchSnapshotResult{Target: t}
+	docker := clictx.Factory.GetClientForContext(t.Context, clictx.Config)
+
+	exists, err := docker.VolumeExists(ctx, t.Volume)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	if !exists {
+		res.Err = apperr.New("cli.volume.snapshot", apperr.NotFound, "volume %q not found in Docker context", t.Volume)
+		return res
+	}
+	// Running writers make the snapshot crash-consistent only; surface them.
+	if users, err := docker.ListRunningContainersUsingVolume(ctx, t.Volume); err == nil {
+		res.InUseBy = users
+	}
+
+	res.Snapshot, res.Err = createSnapshot(ctx, docker, nil, t.Context, t.Volume, outDir, note)
+	res.Duration = time.Since(start)
+	return res
+}
+
+func printSnapshotSummary(pr ui.Printer, results []batchSnapshotResult) {
+	var ok, failed, skipped int
+	for _, r := range results {
+		switch {
+		case r.Skipped:
+			skipped++
+			pr.Plain("│ - %s skipped", r.Target)
+		case r.Err != nil:
+			failed++
+			pr.Plain("│ %s %s: %s", ui.RedText("×"), r.Target, apperr.DeepestMessage(r.Err))
+		default:
+			ok++
+			line := fmt.Sprintf("│ %s %s  %s  %s", ui.SuccessMark(), r.Target, formatSize(r.Snapshot.Bytes), r.Duration.Round(time.Millisecond))
+			if len(r.InUseBy) > 0 {
+				line += fmt.Sprintf("  (in use by %s)", strings.Join(r.InUseBy, ", "))
+			}
+			pr.Plain("%s", line)
+		}
+	}
+	summary := fmt.Sprintf("%d succeeded, %d failed", ok, failed)
+	if skipped > 0 {
+		summary += fmt.Sprintf(", %d skipped", skipped)
+	}
+	pr.Plain("│ Snapshots: %s", summary)
+}
+
+// formatSize renders a byte count with a binary unit suffix.
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,244 @@
+package volumecmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/cli/common"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// snapshotFileTime is the timestamp prefix createSnapshot puts in file names.
+const snapshotFileTime = "2006-01-02T15-04-05Z"
+
+// snapshotEntry describes one snapshot archive found on disk.
+type snapshotEntry struct {
+	Context     string    `json:"context,omitempty"`
+	Volume      string    `json:"volume"`
+	CreatedAt   time.Time `json:"created_at"`
+	Path        string    `json:"path"`
+	Bytes       int64     `json:"bytes"`
+	Compression string    `json:"compression"`
+	Checksum    string    `json:"checksum,omitempty"`
+	Identifier  string    `json:"identifier,omitempty"`
+	SpecHash    string    `json:"spec_hash,omitempty"`
+	Notes       string    `json:"notes,omitempty"`
+	HasMetadata bool      `json:"has_metadata"`
+}
+
+// listSnapshots walks dir for snapshot archives. Snapshots and pre-apply
+// backups are laid out as <context>/<volume>/<timestamp>__spec-<hash>.tar.zst
+// with a JSON sidecar; the sidecar is preferred, and archives without one are
+// described from their path and file name. Results are sorted by context and
+// volume, newest first.
+func listSnapshots(dir string) ([]snapshotEntry, error) {
+	if _, err := os.Stat(dir); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, apperr.New("cli.volume.snapshot.list", apperr.NotFound, "snapshot directory not found: %s", dir)
+		}
+		return nil, apperr.Wrap("cli.volume.snapshot.list", apperr.Internal, err, "stat %s", dir)
+	}
+
+	var entries []snapshotEntry
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		compression := ""
+		switch {
+		case strings.HasSuffix(path, ".tar.zst"):
+			compression = "zstd"
+		case strings.HasSuffix(path, ".tar"):
+			compression = "none"
+		default:
+			return nil
+		}
+		entry, err := describeSnapshot(dir, path, compression)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, apperr.Wrap("cli.volume.snapshot.list", apperr.Internal, err, "scan %s", dir)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Context != b.Context {
+			return a.Context < b.Context
+		}
+		if a.Volume != b.Volume {
+			return a.Volume < b.Volume
+		}
+		return a.CreatedAt.After(b.CreatedAt)
+	})
+	return entries, nil
+}
+
+// describeSnapshot builds the entry for one archive under root.
+func describeSnapshot(root, path, compression string) (snapshotEntry, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return snapshotEntry{}, err
+	}
+	entry := snapshotEntry{Path: path, Bytes: info.Size(), Compression: compression, CreatedAt: info.ModTime().UTC()}
+
+	// Context and volume come from the two directories above the archive.
+	if rel, err := filepath.Rel(root, path); err == nil {
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		if len(parts) >= 2 {
+			entry.Volume = parts[len(parts)-2]
+		}
+		if len(parts) >= 3 {
+			entry.Context = parts[len(parts)-3]
+		}
+	}
+	base := filepath.Base(path)
+	if ts, _, ok := strings.Cut(base, "__"); ok {
+		if t, err := time.Parse(snapshotFileTime, ts); err == nil {
+			entry.CreatedAt = t
+		}
+	}
+
+	sidecar := strings.TrimSuffix(strings.TrimSuffix(path, ".zst"), ".tar") + ".json"
+	b, err := os.ReadFile(sidecar)
+	if errors.Is(err, os.ErrNotExist) {
+		return entry, nil
+	}
+	if err != nil {
+		return snapshotEntry{}, err
+	}
+	var meta snapshotMeta
+	if err := json.Unmarshal(b, &meta); err != nil {
+		// An unreadable sidecar leaves the archive listed from its path alone.
+		return entry, nil
+	}
+	entry.HasMetadata = true
+	if meta.VolumeName != "" {
+		entry.Volume = meta.VolumeName
+	}
+	if t, err := time.Parse(time.RFC3339, meta.CreatedAt); err == nil {
+		entry.CreatedAt = t
+	}
+	entry.Checksum = meta.Checksum.TarZst
+	if entry.Checksum != "" && meta.Checksum.Algo != "" {
+		entry.Checksum = meta.Checksum.Algo + ":" + entry.Checksum
+	}
+	entry.Identifier = meta.Labels[dockercli.LabelIdentifier]
+	entry.SpecHash = meta.SpecHash
+	entry.Notes = meta.Notes
+	return entry, nil
+}
+
+func newSnapshotListCmd() *cobra.Command {
+	var jsonOut bool
+	cmd := &cobra.Command{
+		Use:   "list [<dir>]",
+		Short: "List snapshots in a directory with their metadata",
+		Long: `List snapshots in a directory with their metadata.
+
+Walks <dir> (defaults to ./.dockform/snapshots next to the manifest) for
+snapshot archives, including pre-apply backup directories, and prints the
+volume, timestamp, size, compression, checksum and source identifier of each.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pr := ui.StdPrinter{Out: cmd.OutOrStdout(), Err: cmd.ErrOrStderr()}
+			var dir string
+			if len(args) == 1 {
+				dir = args[0]
+			} else {
+				cfg, err := common.LoadConfigWithWarnings(cmd, pr)
+				if err != nil {
+					return err
+				}
+				dir = filepath.Join(cfg.BaseDir, ".dockform", "snapshots")
+			}
+
+			entries, err := listSnapshots(dir)
+			if err != nil {
+				return err
+			}
+			if jsonOut {
+				if entries == nil {
+					entries = []snapshotEntry{}
+				}
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(entries)
+			}
+			renderSnapshotTable(pr, entries)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output snapshots as JSON")
+	return cmd
+}
+
+// renderSnapshotTable prints one aligned row per snapshot.
+func renderSnapshotTable(pr ui.Printer, entries []snapshotEntry) {
+	if len(entries) == 0 {
+		pr.Plain("No snapshots found.")
+		return
+	}
+	header := []string{"VOLUME", "CREATED", "SIZE", "COMPRESSION", "CHECKSUM", "IDENTIFIER"}
+	rows := make([][]string, 0, len(entries))
+	for _, e := range entries {
+		volume := e.Volume
+		if e.Context != "" {
+			volume = e.Context + "/" + e.Volume
+		}
+		checksum := "-"
+		if e.Checksum != "" {
+			checksum = shortChecksum(e.Checksum)
+		}
+		identifier := "-"
+		if e.Identifier != "" {
+			identifier = e.Identifier
+		}
+		rows = append(rows, []string{volume, e.CreatedAt.UTC().Format(time.RFC3339), formatSize(e.Bytes), e.Compression, checksum, identifier})
+	}
+
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+	for _, row := range append([][]string{header}, rows...) {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = fmt.Sprintf("%-*s", widths[i], cell)
+		}
+		pr.Plain("%s", strings.TrimRight(strings.Join(cells, "  "), " "))
+	}
+}
+
+// shortChecksum abbreviates "algo:hex" to its first 12 hex digits.
+func shortChecksum(sum string) string {
+	algo, hex, ok := strings.Cut(sum, ":")
+	if !ok {
+		hex, algo = algo, ""
+	}
+	if len(hex) > 12 {
+		hex = hex[:12]
+	}
+	if algo == "" {
+		return hex
+	}
+	return algo + ":" + hex
+}
@@ -555,3 +555,101 @@ exit 0
 		t.Errorf("expected error about restore failure; got: %s", errMsg)
 	}
 }
+
+func TestVolumeSnapshot_AllRequiresExclusiveTarget(t *testing.T) {
+	defer clitest.WithStubDocker(t)()
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"volume", "snapshot", "website_data", "--all", "--manifest", volumeConfigPath(t)})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "either a volume or --all") {
+		t.Fatalf("expected mutual-exclusion error, got: %v", err)
+	}
+}
+
+func TestVolumeSnapshot_All_AggregatesFailures(t *testing.T) {
+	cfgPath := volumeConfigPath(t)
+	undo := clitest.WithCustomDockerStub(t, `#!/bin/sh
+cmd="$1"; shift
+case "$cmd" in
+  version)
+    exit 0 ;;
+  volume)
+    sub="$1"; shift
+    case "$sub" in
+      ls)
+        exit 0 ;;
+    esac
+    ;;
+esac
+exit 0
+`)
+	defer undo()
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"volume", "snapshot", "--all", "--concurrency", "2", "--manifest", cfgPath, "-o", t.TempDir()})
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "1 of 1 snapshots failed") {
+		t.Fatalf("expected aggregated failure, got: %v\nOutput: %s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "0 succeeded, 1 failed") {
+		t.Fatalf("expected summary line; got: %s", out.String())
+	}
+}
+
+func TestVolumeSnapshotList(t *testing.T) {
+	dir := t.TempDir()
+	volDir := filepath.Join(dir, "default", "website_data")
+	if err := os.MkdirAll(volDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	base := "2026-01-02T03-04-05Z__spec-abcd1234"
+	if err := os.WriteFile(filepath.Join(volDir, base+".tar.zst"), []byte("archive"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sidecar := `{"created_at":"2026-01-02T03:04:05Z","volume_name":"website_data","spec_hash":"abcd1234","labels":{"io.dockform.identifier":"demo"},"checksum":{"algo":"sha256","tar_zst":"0123456789abcdef0123"}}`
+	if err := os.WriteFile(filepath.Join(volDir, base+".json"), []byte(sidecar), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// An older archive without metadata is still listed from its path.
+	if err := os.WriteFile(filepath.Join(volDir, "2025-12-31T00-00-00Z__spec-ffff0000.tar"), []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	root := cli.TestNewRootCmd()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"volume", "snapshot", "list", dir})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("snapshot list: %v\nOutput: %s", err, out.String())
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "VOLUME") {
+		t.Fatalf("expected header and two rows, got:\n%s", out.String())
+	}
+	for _, want := range []string{"default/website_data", "2026-01-02T03:04:05Z", "zstd", "sha256:0123456789ab", "demo"} {
+		if !strings.Contains(lines[1], want) {
+			t.Errorf("expected newest row to contain %q, got %q", want, lines[1])
+		}
+	}
+	if !strings.Contains(lines[2], "2025-12-31T00:00:00Z") || !strings.Contains(lines[2], "none") {
+		t.Errorf("expected older uncompressed row, got %q", lines[2])
+	}
+
+	root = cli.TestNewRootCmd()
+	out.Reset()
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"volume", "snapshot", "list", dir, "--json"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("snapshot list --json: %v", err)
+	}
+	if !strings.Contains(out.String(), `"identifier": "demo"`) || !strings.Contains(out.String(), `"has_metadata": false`) {
+		t.Fatalf("unexpected JSON output:\n%s", out.String())
+	}
+}
@@ -0,0 +1,108 @@
+package dockercli
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+// Capabilities describes the daemon behind a client and the tooling used to
+// drive it.
+type Capabilities struct {
+	ServerVersion  string // Docker Engine version, e.g. "27.1.1"
+	APIVersion     string // Engine API version, e.g. "1.46"
+	OS             string // Daemon OS, e.g. "linux"
+	Arch           string // Daemon architecture in GOARCH form, e.g. "amd64"
+	ComposeVersion string // Compose plugin version; empty when the plugin is missing
+	Swarm          bool   // Daemon is an active swarm node
+}
+
+// Platform returns the daemon platform as "os/arch", or "" when unknown.
+func (c Capabilities) Platform() string {
+	if c.OS == "" || c.Arch == "" {
+		return ""
+	}
+	return c.OS + "/" + c.Arch
+}
+
+// String renders a one-line summary, e.g. "27.1.1 (API 1.46, linux/amd64, swarm)".
+func (c Capabilities) String() string {
+	var details []string
+	if c.APIVersion != "" {
+		details = append(details, "API "+c.APIVersion)
+	}
+	if p := c.Platform(); p != "" {
+		details = append(details, p)
+	}
+	if c.Swarm {
+		details = append(details, "swarm")
+	}
+	if len(details) == 0 {
+		return c.ServerVersion
+	}
+	return c.ServerVersion + " (" + strings.Join(details, ", ") + ")"
+}
+
+// MatchesPlatform reports whether a compose platform such as "linux/arm64/v8"
+// runs natively on the daemon. The variant is ignored, and an unknown daemon
+// platform matches everything.
+func (c Capabilities) MatchesPlatform(platform string) bool {
+	if c.Platform() == "" || strings.TrimSpace(platform) == "" {
+		return true
+	}
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(platform)), "/")
+	if parts[0] != strings.ToLower(c.OS) {
+		return false
+	}
+	return len(parts) < 2 || parts[1] == strings.ToLower(c.Arch)
+}
+
+// serverVersionJSON is the subset of `docker version --format '{{json .Server}}'`
+// that Ping reads.
+type serverVersionJSON struct {
+	Version    string `json:"Version"`
+	APIVersion string `json:"ApiVersion"`
+	Os         string `json:"Os"`
+	Arch       string `json:"Arch"`
+}
+
+// Ping checks that the daemon is reachable and detects its capabilities. A
+// successful result is cached for the lifetime of the client; failures are
+// not, so a later call probes again.
+func (c *Client) Ping(ctx context.Context) (Capabilities, error) {
+	c.capsMu.Lock()
+	defer c.capsMu.Unlock()
+	if c.caps != nil {
+		return *c.caps, nil
+	}
+
+	out, err := c.exec.RunDetailed(ctx, Options{Probe: true}, "version", "--format", "{{json .Server}}")
+	if err != nil {
+		if ctx.Err() != nil {
+			return Capabilities{}, ctx.Err()
+		}
+		if c.contextName != "" && c.contextName != "default" {
+			return Capabilities{}, apperr.Wrap("dockercli.Ping", apperr.Unavailable, err, "docker daemon not reachable (context=%s)", c.contextName)
+		}
+		return Capabilities{}, apperr.Wrap("dockercli.Ping", apperr.Unavailable, err, "docker daemon not reachable")
+	}
+	var sv serverVersionJSON
+	if err := json.Unmarshal([]byte(strings.TrimSpace(out.Stdout)), &sv); err != nil {
+		return Capabilities{}, apperr.Wrap("dockercli.Ping", apperr.External, err, "parse docker version output")
+	}
+	caps := Capabilities{ServerVersion: sv.Version, APIVersion: sv.APIVersion, OS: sv.Os, Arch: sv.Arch}
+
+	// Swarm state and the compose plugin are best-effort: the daemon answered,
+	// so a failure here only means the feature is unavailable.
+	if state, err := c.exec.Run(ctx, "info", "--format", "{{.Swarm.LocalNodeState}}"); err == nil {
+		caps.Swarm = strings.TrimSpace(state) == "active"
+	}
+	if v, err := c.ComposeVersion(ctx); err == nil {
+		caps.ComposeVersion = v
+	}
+
+	c.caps = &caps
+	return caps, nil
+}
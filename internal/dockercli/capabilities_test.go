@@ -0,0 +1,75 @@
+package dockercli
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+func TestPing_DetectsCapabilitiesAndCaches(t *testing.T) {
+	stub := &infoExecStub{
+		serverOut:       `{"Version":"27.1.1","ApiVersion":"1.46","Os":"linux","Arch":"arm64"}`,
+		swarmOut:        "active\n",
+		composeShortOut: "2.29.1\n",
+	}
+	c := &Client{exec: stub}
+
+	caps, err := c.Ping(context.Background())
+	if err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+	want := Capabilities{ServerVersion: "27.1.1", APIVersion: "1.46", OS: "linux", Arch: "arm64", ComposeVersion: "2.29.1", Swarm: true}
+	if caps != want {
+		t.Fatalf("unexpected capabilities: %#v", caps)
+	}
+	if got := caps.String(); got != "27.1.1 (API 1.46, linux/arm64, swarm)" {
+		t.Fatalf("unexpected summary: %q", got)
+	}
+
+	calls := len(stub.calls)
+	if _, err := c.Ping(context.Background()); err != nil {
+		t.Fatalf("second ping: %v", err)
+	}
+	if len(stub.calls) != calls {
+		t.Fatalf("expected cached capabilities, got %d extra calls", len(stub.calls)-calls)
+	}
+}
+
+func TestPing_FailureIsNotCached(t *testing.T) {
+	stub := &infoExecStub{serverErr: errors.New("connection refused")}
+	c := &Client{exec: stub, contextName: "remote"}
+
+	_, err := c.Ping(context.Background())
+	if err == nil || !apperr.IsKind(err, apperr.Unavailable) {
+		t.Fatalf("expected unavailable error, got %v", err)
+	}
+
+	stub.serverErr = nil
+	stub.serverOut = `{"Version":"27.1.1","Os":"linux","Arch":"amd64"}`
+	caps, err := c.Ping(context.Background())
+	if err != nil || caps.Swarm || caps.Platform() != "linux/amd64" {
+		t.Fatalf("expected fresh probe after failure, got %#v %v", caps, err)
+	}
+}
+
+func TestCapabilities_MatchesPlatform(t *testing.T) {
+	caps := Capabilities{OS: "linux", Arch: "amd64"}
+	cases := map[string]bool{
+		"":               true,
+		"linux":          true,
+		"linux/amd64":    true,
+		"Linux/AMD64":    true,
+		"linux/arm64/v8": false,
+		"windows/amd64":  false,
+	}
+	for platform, want := range cases {
+		if got := caps.MatchesPlatform(platform); got != want {
+			t.Fatalf("MatchesPlatform(%q) = %v, want %v", platform, got, want)
+		}
+	}
+	if !(Capabilities{}).MatchesPlatform("linux/arm64") {
+		t.Fatalf("unknown daemon platform must match everything")
+	}
+}
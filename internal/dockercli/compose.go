@@ -25,25 +25,176 @@ func (c *Client) runInDirOptionalEnv(ctx context.Context, workingDir string, inl
 // ComposeUp runs docker compose up -d with the given parameters.
 // workingDir is where compose files and relative paths are resolved.
 func (c *Client) ComposeUp(ctx context.Context, workingDir string, files, profiles, envFiles []string, projectName string, inlineEnv []string) (string, error) {
+	ctx = pulling(ctx)
+	files, done, err := withComposeOverride(ctx, files)
+	if err != nil {
+		return "", err
+	}
+	defer done()
 	// Choose compose files (overlay or user files)
-	chosenFiles := files
-	if c.identifier != "" {
-		if pth, err := c.buildLabeledProjectTemp(ctx, workingDir, files, profiles, envFiles, projectName, c.identifier, inlineEnv); err == nil && pth != "" {
-			defer func() { _ = os.Remove(pth) }()
-			chosenFiles = []string{pth}
-		}
+	chosenFiles, cleanup, err := c.labeledProjectFiles(ctx, workingDir, files, profiles, envFiles, projectName, c.identifier, inlineEnv)
+	if err != nil {
+		return "", err
 	}
+	defer cleanup()
 	args := c.composeBaseArgs(chosenFiles, profiles, envFiles, projectName)
 	args = append(args, "up", "-d")
 
 	return c.runInDirOptionalEnv(ctx, workingDir, inlineEnv, args...)
 }
 
+// ComposeUpServices runs docker compose up -d --no-deps for the given
+// services only, leaving their dependencies and the project's other services
+// as they are.
+func (c *Client) ComposeUpServices(ctx context.Context, workingDir string, files, profiles, envFiles []string, projectName string, services []string, inlineEnv []string) (string, error) {
+	ctx = pulling(ctx)
+	files, done, err := withComposeOverride(ctx, files)
+	if err != nil {
+		return "", err
+	}
+	defer done()
+	if len(services) == 0 {
+		return "", apperr.New("dockercli.ComposeUpServices", apperr.InvalidInput, "at least one service is required")
+	}
+	chosenFiles, cleanup, err := c.labeledProjectFiles(ctx, workingDir, files, profiles, envFiles, projectName, c.identifier, inlineEnv)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+	args := c.composeBaseArgs(chosenFiles, profiles, envFiles, projectName)
+	args = append(args, "up", "-d", "--no-deps")
+	args = append(args, services...)
+	return c.runInDirOptionalEnv(ctx, workingDir, inlineEnv, args...)
+}
+
+// ComposeRecreateServices runs docker compose up -d --no-deps --force-recreate
+// for the given services, replacing their containers even when their config
+// is unchanged.
+func (c *Client) ComposeRecreateServices(ctx context.Context, workingDir string, files, profiles, envFiles []string, projectName string, services []string, inlineEnv []string) (string, error) {
+	files, done, err := withComposeOverride(ctx, files)
+	if err != nil {
+		return "", err
+	}
+	defer done()
+	if len(services) == 0 {
+		return "", apperr.New("dockercli.ComposeRecreateServices", apperr.InvalidInput, "at least one service is required")
+	}
+	chosenFiles, cleanup, err := c.labeledProjectFiles(ctx, workingDir, files, profiles, envFiles, projectName, c.identifier, inlineEnv)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+	args := c.composeBaseArgs(chosenFiles, profiles, envFiles, projectName)
+	args = append(args, "up", "-d", "--no-deps", "--force-recreate")
+	args = append(args, services...)
+	return c.runInDirOptionalEnv(ctx, workingDir, inlineEnv, args...)
+}
+
+// ComposeScale runs docker compose up -d --no-deps --no-recreate --scale for a
+// single service, adding or removing its containers without touching the
+// project's other services or recreating existing containers.
+func (c *Client) ComposeScale(ctx context.Context, workingDir string, files, profiles, envFiles []string, projectName, service string, replicas int, inlineEnv []string) (string, error) {
+	files, done, err := withComposeOverride(ctx, files)
+	if err != nil {
+		return "", err
+	}
+	defer done()
+	if err := requireNonEmpty(service, "dockercli.ComposeScale", "service name is required"); err != nil {
+		return "", err
+	}
+	if replicas < 0 {
+		return "", apperr.New("dockercli.ComposeScale", apperr.InvalidInput, "replicas must not be negative, got %d", replicas)
+	}
+	chosenFiles, cleanup, err := c.labeledProjectFiles(ctx, workingDir, files, profiles, envFiles, projectName, c.identifier, inlineEnv)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+	args := c.composeBaseArgs(chosenFiles, profiles, envFiles, projectName)
+	args = append(args, "up", "-d", "--no-deps", "--no-recreate", "--scale", fmt.Sprintf("%s=%d", service, replicas), service)
+	return c.runInDirOptionalEnv(ctx, workingDir, inlineEnv, args...)
+}
+
+// ComposeUpServiceImage recreates a single service pinned to image, without
+// touching its dependencies. An overlay compose file overrides the service's
+// image, and --pull never keeps compose from resolving it against a registry.
+func (c *Client) ComposeUpServiceImage(ctx context.Context, workingDir string, files, profiles, envFiles []string, projectName, service, image string, inlineEnv []string) (string, error) {
+	ctx = pulling(ctx)
+	files, done, err := withComposeOverride(ctx, files)
+	if err != nil {
+		return "", err
+	}
+	defer done()
+	if err := requireNonEmpty(service, "dockercli.ComposeUpServiceImage", "service name is required"); err != nil {
+		return "", err
+	}
+	if err := requireNonEmpty(image, "dockercli.ComposeUpServiceImage", "image is required"); err != nil {
+		return "", err
+	}
+	chosenFiles, cleanup, err := c.labeledProjectFiles(ctx, workingDir, files, profiles, envFiles, projectName, c.identifier, inlineEnv)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+	overlay, err := yaml.Marshal(map[string]any{
+		"services": map[string]any{service: map[string]any{"image": image}},
+	})
+	if err != nil {
+		return "", apperr.Wrap("dockercli.ComposeUpServiceImage", apperr.Internal, err, "marshal image overlay")
+	}
+	f, err := os.CreateTemp("", "dockform-image-*.yml")
+	if err != nil {
+		return "", apperr.Wrap("dockercli.ComposeUpServiceImage", apperr.Internal, err, "create image overlay")
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	if _, err := f.Write(overlay); err != nil {
+		_ = f.Close()
+		return "", apperr.Wrap("dockercli.ComposeUpServiceImage", apperr.Internal, err, "write image overlay")
+	}
+	if err := f.Close(); err != nil {
+		return "", apperr.Wrap("dockercli.ComposeUpServiceImage", apperr.Internal, err, "write image overlay")
+	}
+	args := c.composeBaseArgs(append(append([]string(nil), chosenFiles...), f.Name()), profiles, envFiles, projectName)
+	args = append(args, "up", "-d", "--no-deps", "--force-recreate", "--pull", "never", service)
+	return c.runInDirOptionalEnv(ctx, workingDir, inlineEnv, args...)
+}
+
+// ComposeRun runs command in a one-off container of service (`docker compose run
+// --rm -T`). Each inline env key is forwarded into the container with -e so the
+// command sees the stack's resolved environment without values appearing in argv.
+func (c *Client) ComposeRun(ctx context.Context, workingDir string, files, profiles, envFiles []string, projectName, service string, command []string, inlineEnv []string) (string, error) {
+	ctx = pulling(ctx)
+	files, done, err := withComposeOverride(ctx, files)
+	if err != nil {
+		return "", err
+	}
+	defer done()
+	if err := requireNonEmpty(service, "dockercli.ComposeRun", "service name is required"); err != nil {
+		return "", err
+	}
+	args := c.composeBaseArgs(files, profiles, envFiles, projectName)
+	args = append(args, "run", "--rm", "-T")
+	for _, kv := range inlineEnv {
+		if k, _, ok := strings.Cut(kv, "="); ok && k != "" {
+			args = append(args, "-e", k)
+		}
+	}
+	args = append(args, service)
+	args = append(args, command...)
+	return c.runInDirOptionalEnv(ctx, workingDir, inlineEnv, args...)
+}
+
 // ComposePull runs `docker compose pull [services...]` using the given compose
 // configuration. When services is empty, compose pulls images for every
 // service in the project. The returned string is the raw stdout of the
 // command (typically empty on success for modern compose versions).
 func (c *Client) ComposePull(ctx context.Context, workingDir string, files, profiles, envFiles []string, projectName string, services []string, inlineEnv []string) (string, error) {
+	ctx = pulling(ctx)
+	files, done, err := withComposeOverride(ctx, files)
+	if err != nil {
+		return "", err
+	}
+	defer done()
 	args := c.composeBaseArgs(files, profiles, envFiles, projectName)
 	args = append(args, "pull")
 	args = append(args, services...)
@@ -52,6 +203,11 @@ func (c *Client) ComposePull(ctx context.Context, workingDir string, files, prof
 
 // ComposeConfigServices returns the list of service names that would be part of the project.
 func (c *Client) ComposeConfigServices(ctx context.Context, workingDir string, files, profiles, envFiles []string, inlineEnv []string) ([]string, error) {
+	files, done, err := withComposeOverride(ctx, files)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
 	args := c.composeBaseArgs(files, profiles, envFiles, "")
 	args = append(args, "config", "--services")
 	out, err := c.runInDirOptionalEnv(ctx, workingDir, inlineEnv, args...)
@@ -63,10 +219,15 @@ func (c *Client) ComposeConfigServices(ctx context.Context, workingDir string, f
 
 // ComposeConfigFull renders the effective compose config and parses desired services info (image, etc.).
 func (c *Client) ComposeConfigFull(ctx context.Context, workingDir string, files, profiles, envFiles []string, inlineEnv []string) (ComposeConfigDoc, error) {
-	cacheKey := c.composeCacheKey(workingDir, files, profiles, envFiles, inlineEnv)
+	cacheKey := c.composeCacheKey(ctx, workingDir, files, profiles, envFiles, inlineEnv)
 	if doc, ok := c.loadComposeCache(cacheKey); ok {
 		return doc, nil
 	}
+	files, done, err := withComposeOverride(ctx, files)
+	if err != nil {
+		return ComposeConfigDoc{}, err
+	}
+	defer done()
 	args := c.composeBaseArgs(files, profiles, envFiles, "")
 	// Prefer JSON when available
 	argsJSON := append(append([]string{}, args...), "config", "--format", "json")
@@ -97,19 +258,82 @@ func (c *Client) ComposeConfigFull(ctx context.Context, workingDir string, files
 // profiles and env files, resolved relative to workingDir. Inline environment
 // variables are provided via the process environment for the command.
 func (c *Client) ComposeConfigRaw(ctx context.Context, workingDir string, files, profiles, envFiles []string, inlineEnv []string) (string, error) {
+	files, done, err := withComposeOverride(ctx, files)
+	if err != nil {
+		return "", err
+	}
+	defer done()
 	args := c.composeBaseArgs(files, profiles, envFiles, "")
 	args = append(args, "config")
 	return c.runInDirOptionalEnv(ctx, workingDir, inlineEnv, args...)
 }
 
-// ComposePs lists running (or created) compose services for the project.
+// ComposePs lists the compose containers of the project, including stopped ones.
 func (c *Client) ComposePs(ctx context.Context, workingDir string, files, profiles, envFiles []string, projectName string, inlineEnv []string) ([]ComposePsItem, error) {
+	files, done, err := withComposeOverride(ctx, files)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
 	args := c.composeBaseArgs(files, profiles, envFiles, projectName)
-	args = append(args, "ps", "--format", "json")
+	args = append(args, "ps", "-a", "--format", "json")
 	out, err := c.runInDirOptionalEnv(ctx, workingDir, inlineEnv, args...)
 	if err != nil {
 		return nil, err
 	}
+	return parseComposePsJSON("dockercli.ComposePs", out)
+}
+
+// ComposeServiceStatuses inspects the container of each expected service after a
+// compose up, including stopped containers, and reports its state and exit code.
+// Services without a container are reported with state "missing".
+func (c *Client) ComposeServiceStatuses(ctx context.Context, workingDir string, files, profiles, envFiles []string, projectName string, services []string, inlineEnv []string) ([]ServiceStatus, error) {
+	files, done, err := withComposeOverride(ctx, files)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	args := c.composeBaseArgs(files, profiles, envFiles, projectName)
+	args = append(args, "ps", "-a", "--format", "json")
+	out, err := c.runInDirOptionalEnv(ctx, workingDir, inlineEnv, args...)
+	if err != nil {
+		return nil, err
+	}
+	var items []ComposePsItem
+	if strings.TrimSpace(out) != "" {
+		items, err = parseComposePsJSON("dockercli.ComposeServiceStatuses", out)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return serviceStatusesFromPs(services, items), nil
+}
+
+// serviceStatusesFromPs maps compose ps items onto the expected services, in the
+// order given. When a service has several containers, a failed one wins, then
+// one that is not healthy yet, so neither is masked by a healthy or completed
+// replica.
+func serviceStatusesFromPs(services []string, items []ComposePsItem) []ServiceStatus {
+	out := make([]ServiceStatus, 0, len(services))
+	for _, svc := range services {
+		st := ServiceStatus{Service: svc, State: "missing"}
+		for _, it := range items {
+			if it.Service != svc {
+				continue
+			}
+			cand := ServiceStatus{Service: svc, Container: it.Name, State: it.State, ExitCode: it.ExitCode, Health: it.Health}
+			if st.State == "missing" || (cand.Failed() && !st.Failed()) || (!cand.Healthy() && !cand.Completed() && (st.Healthy() || st.Completed())) {
+				st = cand
+			}
+		}
+		out = append(out, st)
+	}
+	return out
+}
+
+// parseComposePsJSON decodes `docker compose ps --format json` output, which is a
+// JSON array, a single object or NDJSON depending on the compose version.
+func parseComposePsJSON(op, out string) ([]ComposePsItem, error) {
 	// Try array first
 	var items []ComposePsItem
 	if err := json.Unmarshal([]byte(out), &items); err == nil {
@@ -133,7 +357,7 @@ func (c *Client) ComposePs(ctx context.Context, workingDir string, files, profil
 	if len(results) > 0 {
 		return results, nil
 	}
-	return nil, apperr.New("dockercli.ComposePs", apperr.External, "unexpected compose ps json: %s", util.Truncate(out, 256))
+	return nil, apperr.New(op, apperr.External, "unexpected compose ps json: %s", util.Truncate(out, 256))
 }
 
 // parseComposeHashLines parses `docker compose config --hash *` output, which is
@@ -154,14 +378,17 @@ func parseComposeHashLines(out string) map[string]string {
 // If identifier is non-empty, a temporary overlay compose file is used to add
 // the label `io.dockform.identifier: <identifier>` to that service before hashing.
 func (c *Client) ComposeConfigHash(ctx context.Context, workingDir string, files, profiles, envFiles []string, projectName string, service string, identifier string, inlineEnv []string) (string, error) {
+	files, done, err := withComposeOverride(ctx, files)
+	if err != nil {
+		return "", err
+	}
+	defer done()
 	// Choose compose files (overlay or user files)
-	chosenFiles := files
-	if identifier != "" {
-		if pth, err := c.buildLabeledProjectTemp(ctx, workingDir, files, profiles, envFiles, projectName, identifier, inlineEnv); err == nil && pth != "" {
-			defer func() { _ = os.Remove(pth) }()
-			chosenFiles = []string{pth}
-		}
+	chosenFiles, cleanup, err := c.labeledProjectFiles(ctx, workingDir, files, profiles, envFiles, projectName, identifier, inlineEnv)
+	if err != nil {
+		return "", err
 	}
+	defer cleanup()
 	args := c.composeBaseArgs(chosenFiles, profiles, envFiles, projectName)
 	args = append(args, "config", "--hash", service)
 	out, err := c.runInDirOptionalEnv(ctx, workingDir, inlineEnv, args...)
@@ -184,10 +411,15 @@ func (c *Client) ComposeConfigHash(ctx context.Context, workingDir string, files
 // ComposeConfigHashes returns compose config hashes for multiple services, reusing a single
 // labeled overlay compose file when identifier is provided to avoid repeated `compose config`.
 func (c *Client) ComposeConfigHashes(ctx context.Context, workingDir string, files, profiles, envFiles []string, projectName string, services []string, identifier string, inlineEnv []string) (map[string]string, error) {
+	files, done, err := withComposeOverride(ctx, files)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
 	// Choose compose files (overlay or user files)
 	chosenFiles := files
 	if identifier != "" {
-		if pth, err := c.buildLabeledProjectTemp(ctx, workingDir, files, profiles, envFiles, projectName, identifier, inlineEnv); err == nil && pth != "" {
+		if pth, err := c.buildLabeledProjectTemp(withoutOutput(ctx), workingDir, files, profiles, envFiles, projectName, identifier, inlineEnv); err == nil && pth != "" {
 			defer func() { _ = os.Remove(pth) }()
 			chosenFiles = []string{pth}
 		} else if err != nil {
@@ -212,7 +444,7 @@ func (c *Client) ComposeConfigHashes(ctx context.Context, workingDir string, fil
 	return result, nil
 }
 
-func (c *Client) composeCacheKey(workingDir string, files, profiles, envFiles []string, inlineEnv []string) string {
+func (c *Client) composeCacheKey(ctx context.Context, workingDir string, files, profiles, envFiles []string, inlineEnv []string) string {
 	var b strings.Builder
 	writePart := func(label string, vals []string) {
 		b.WriteString(label)
@@ -227,11 +459,35 @@ func (c *Client) composeCacheKey(workingDir string, files, profiles, envFiles []
 	writePart("profiles", profiles)
 	writePart("envfiles", envFiles)
 	writePart("inline", inlineEnv)
+	writePart("override", []string{composeOverrideDigest(ctx)})
 	return b.String()
 }
 
+// labeledProjectFiles returns the compose files to run with: the labeled
+// project when identifier is set, files otherwise, and a func that removes the
+// temporary project. A project that cannot be labeled falls back to files,
+// except under WithEnvHashLabel: without the label every service would look
+// changed and be recreated, so the error is returned instead.
+func (c *Client) labeledProjectFiles(ctx context.Context, workingDir string, files, profiles, envFiles []string, projectName, identifier string, inlineEnv []string) ([]string, func(), error) {
+	if identifier == "" {
+		return files, func() {}, nil
+	}
+	pth, err := c.buildLabeledProjectTemp(withoutOutput(ctx), workingDir, files, profiles, envFiles, projectName, identifier, inlineEnv)
+	if err != nil {
+		if envHashLabel(ctx) {
+			return nil, nil, err
+		}
+		return files, func() {}, nil
+	}
+	if pth == "" {
+		return files, func() {}, nil
+	}
+	return []string{pth}, func() { _ = os.Remove(pth) }, nil
+}
+
 // buildLabeledProjectTemp loads the effective compose yaml via `docker compose config`,
 // injects io.dockform.identifier=<identifier> label into all services, writes to a temp file, and returns its path.
+// Under WithEnvHashLabel, services also get LabelEnvHash.
 func (c *Client) buildLabeledProjectTemp(ctx context.Context, workingDir string, files, profiles, envFiles []string, projectName string, identifier string, inlineEnv []string) (string, error) {
 	if identifier == "" {
 		return "", nil
@@ -253,6 +509,13 @@ func (c *Client) buildLabeledProjectTemp(ctx context.Context, workingDir string,
 	if services == nil {
 		services = map[string]any{}
 	}
+	var env []string
+	hashEnv := envHashLabel(ctx)
+	if hashEnv {
+		if env, err = stackEnv(workingDir, envFiles, inlineEnv); err != nil {
+			return "", err
+		}
+	}
 	for name, val := range services {
 		service, _ := val.(map[string]any)
 		if service == nil {
@@ -262,7 +525,13 @@ func (c *Client) buildLabeledProjectTemp(ctx context.Context, workingDir string,
 		if labels == nil {
 			labels = map[string]any{}
 		}
+		for k, v := range stackLabels(ctx) {
+			labels[k] = v
+		}
 		labels["io.dockform.identifier"] = identifier
+		if hashEnv {
+			labels[LabelEnvHash] = serviceEnvHash(env, service)
+		}
 		service["labels"] = labels
 		services[name] = service
 	}
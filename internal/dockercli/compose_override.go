@@ -0,0 +1,75 @@
+package dockercli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+// composeOverrideKey is a context key type used to pass a generated compose
+// override document to compose operations.
+type composeOverrideKey struct{}
+
+// WithComposeOverride returns a context under which compose operations layer
+// doc, a compose file dockform generated, on top of the files they are given.
+// It is written to a temporary file only while each command runs, so nothing
+// lands next to the user's compose files.
+func WithComposeOverride(ctx context.Context, doc []byte) context.Context {
+	if len(doc) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, composeOverrideKey{}, doc)
+}
+
+// WithStack returns a context carrying everything a stack adds to its compose
+// project: its labels (see WithStackLabels), its generated override (see
+// WithComposeOverride) and, with recreate_on_env_change, the env hash label
+// (see WithEnvHashLabel).
+func WithStack(ctx context.Context, stack manifest.Stack) context.Context {
+	ctx = WithEnvHashLabel(ctx, stack.RecreateOnEnvChange)
+	return WithComposeOverride(WithStackLabels(ctx, stack.Labels), stack.ComposeOverride)
+}
+
+// composeOverride returns the document set by WithComposeOverride, if any.
+func composeOverride(ctx context.Context) []byte {
+	doc, _ := ctx.Value(composeOverrideKey{}).([]byte)
+	return doc
+}
+
+// composeOverrideDigest identifies the override in ctx for cache keys.
+func composeOverrideDigest(ctx context.Context) string {
+	doc := composeOverride(ctx)
+	if len(doc) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(doc)
+	return hex.EncodeToString(sum[:8])
+}
+
+// withComposeOverride returns files with the override from ctx written to a
+// temporary file and appended, and a func that removes that file.
+func withComposeOverride(ctx context.Context, files []string) ([]string, func(), error) {
+	doc := composeOverride(ctx)
+	if len(doc) == 0 {
+		return files, func() {}, nil
+	}
+	f, err := os.CreateTemp("", "dockform-override-*.yml")
+	if err != nil {
+		return nil, nil, apperr.Wrap("dockercli.Compose", apperr.Internal, err, "create compose override")
+	}
+	remove := func() { _ = os.Remove(f.Name()) }
+	if _, err := f.Write(doc); err != nil {
+		_ = f.Close()
+		remove()
+		return nil, nil, apperr.Wrap("dockercli.Compose", apperr.Internal, err, "write compose override")
+	}
+	if err := f.Close(); err != nil {
+		remove()
+		return nil, nil, apperr.Wrap("dockercli.Compose", apperr.Internal, err, "write compose override")
+	}
+	return append(append([]string(nil), files...), f.Name()), remove, nil
+}
@@ -0,0 +1,31 @@
+package dockercli
+
+import (
+	"errors"
+	"strings"
+)
+
+// composeSchemaMarkers are fragments of the errors compose reports when a file
+// uses syntax its schema does not know, typically because the key was added in
+// a newer compose release.
+var composeSchemaMarkers = []string{
+	"additional property",   // "services.web Additional property develop is not allowed"
+	"additional properties", // "additional properties 'develop' not allowed"
+	"unsupported config option",
+	"is unsupported", // `Version in "./compose.yaml" is unsupported`
+}
+
+// IsComposeSchemaError reports whether err, or any error it wraps, is compose
+// rejecting a file against its schema rather than failing for another reason
+// such as a missing file or an unreachable daemon.
+func IsComposeSchemaError(err error) bool {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		msg := strings.ToLower(e.Error())
+		for _, marker := range composeSchemaMarkers {
+			if strings.Contains(msg, marker) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -2,12 +2,15 @@ package dockercli
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/manifest"
 	"github.com/goccy/go-yaml"
 )
 
@@ -63,7 +66,7 @@ func (f *fakeExec) dispatch(args []string) (string, error) {
 	if hasSuffix(args, []string{"config"}) {
 		return f.outConfigYAML, f.errConfigYAML
 	}
-	if hasSuffix(args, []string{"ps", "--format", "json"}) {
+	if hasSuffix(args, []string{"ps", "--format", "json"}) || hasSuffix(args, []string{"ps", "-a", "--format", "json"}) {
 		return f.outPs, f.errPs
 	}
 	if hasSuffix(args, []string{"config", "--hash"}) || contains(args, "--hash") {
@@ -111,6 +114,29 @@ func TestComposeUp_UsesInlineEnvPath(t *testing.T) {
 	}
 }
 
+func TestComposeUpServiceImage_PinsImageForSingleService(t *testing.T) {
+	f := &fakeExec{}
+	c := &Client{exec: f}
+	if _, err := c.ComposeUpServiceImage(context.Background(), "/tmp", []string{"a.yml"}, nil, nil, "proj", "web", "sha256:abc", nil); err != nil {
+		t.Fatalf("compose up service image: %v", err)
+	}
+	if !hasSuffix(f.lastArgs, []string{"up", "-d", "--no-deps", "--force-recreate", "--pull", "never", "web"}) {
+		t.Fatalf("expected scoped up for web; got %#v", f.lastArgs)
+	}
+	files := 0
+	for _, a := range f.lastArgs {
+		if a == "-f" {
+			files++
+		}
+	}
+	if files != 2 {
+		t.Fatalf("expected the compose file plus an image overlay; got %#v", f.lastArgs)
+	}
+	if _, err := c.ComposeUpServiceImage(context.Background(), "/tmp", []string{"a.yml"}, nil, nil, "proj", "web", "", nil); err == nil {
+		t.Fatalf("expected error for empty image")
+	}
+}
+
 func TestComposeConfigServices_ParsesLines(t *testing.T) {
 	f := &fakeExec{outServices: "web\napi\n"}
 	c := &Client{exec: f}
@@ -150,10 +176,10 @@ func TestComposeConfigFull_YAMLFallback(t *testing.T) {
 
 func TestComposePs_Parsers(t *testing.T) {
 	// Array
-	f := &fakeExec{outPs: `[{"Name":"c1","Service":"web"}]`}
+	f := &fakeExec{outPs: `[{"ID":"abc123","Name":"c1","Service":"web"}]`}
 	c := &Client{exec: f}
 	items, err := c.ComposePs(context.Background(), ".", nil, nil, nil, "proj", nil)
-	if err != nil || len(items) != 1 || items[0].Service != "web" {
+	if err != nil || len(items) != 1 || items[0].Service != "web" || items[0].ID != "abc123" {
 		t.Fatalf("array parse: %v %#v", err, items)
 	}
 	// Single object
@@ -175,6 +201,63 @@ func TestComposePs_Parsers(t *testing.T) {
 	}
 }
 
+func TestComposeServiceStatuses_ReportsPerService(t *testing.T) {
+	f := &fakeExec{outPs: `[{"Name":"p-web-1","Service":"web","State":"running"},` +
+		`{"Name":"p-worker-1","Service":"worker","State":"exited","ExitCode":1},` +
+		`{"Name":"p-worker-2","Service":"worker","State":"running"}]`}
+	c := &Client{exec: f}
+	got, err := c.ComposeServiceStatuses(context.Background(), ".", nil, nil, nil, "p", []string{"web", "worker", "cron"}, nil)
+	if err != nil {
+		t.Fatalf("statuses: %v", err)
+	}
+	if !contains(f.lastArgs, "-a") {
+		t.Fatalf("expected ps -a to include stopped containers: %v", f.lastArgs)
+	}
+	want := []string{"web started", "worker exited (code 1)", "cron has no container"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d statuses, got %#v", len(want), got)
+	}
+	for i, w := range want {
+		if got[i].String() != w {
+			t.Fatalf("status %d: got %q want %q", i, got[i].String(), w)
+		}
+	}
+	if got[0].Failed() || !got[1].Failed() || got[2].Failed() {
+		t.Fatalf("unexpected Failed() results: %#v", got)
+	}
+}
+
+func TestComposeServiceStatuses_ReplicaNotYetHealthyWins(t *testing.T) {
+	f := &fakeExec{outPs: `[{"Name":"p-web-1","Service":"web","State":"running","Health":"healthy"},` +
+		`{"Name":"p-web-2","Service":"web","State":"running","Health":"starting"}]`}
+	c := &Client{exec: f}
+	got, err := c.ComposeServiceStatuses(context.Background(), ".", nil, nil, nil, "p", []string{"web"}, nil)
+	if err != nil {
+		t.Fatalf("statuses: %v", err)
+	}
+	if len(got) != 1 || got[0].Container != "p-web-2" || got[0].Healthy() {
+		t.Fatalf("expected the starting replica to be reported, got %#v", got)
+	}
+}
+
+func TestServiceStatus_HealthSummary(t *testing.T) {
+	tests := []struct {
+		st   ServiceStatus
+		want string
+	}{
+		{ServiceStatus{Service: "web", State: "running"}, "web started"},
+		{ServiceStatus{Service: "web", State: "running", Health: "starting"}, "web starting"},
+		{ServiceStatus{Service: "web", State: "running", Health: "unhealthy", HealthLog: "connection refused"}, "web unhealthy; last health check: connection refused"},
+		{ServiceStatus{Service: "worker", State: "exited", ExitCode: 1}, "worker exited (code 1)"},
+		{ServiceStatus{Service: "cron", State: "missing"}, "cron has no container"},
+	}
+	for _, tt := range tests {
+		if got := tt.st.HealthSummary(); got != tt.want {
+			t.Errorf("HealthSummary(%+v) = %q, want %q", tt.st, got, tt.want)
+		}
+	}
+}
+
 func TestComposeConfigHash_ParsesLastField(t *testing.T) {
 	f := &fakeExec{outHash: "web deadbeefcafebabe\n"}
 	c := &Client{exec: f}
@@ -243,6 +326,33 @@ func TestBuildLabeledProjectTemp_AddsIdentifierLabel(t *testing.T) {
 	}
 }
 
+func TestBuildLabeledProjectTemp_AddsStackLabels(t *testing.T) {
+	yam := "services:\n  web:\n    image: nginx\n    labels:\n      keep: me\n"
+	f := &fakeExec{outConfigYAML: yam}
+	c := &Client{exec: f}
+	ctx := WithStackLabels(context.Background(), map[string]string{"team": "payments", LabelIdentifier: "spoofed"})
+	path, err := c.buildLabeledProjectTemp(ctx, t.TempDir(), []string{"compose.yml"}, nil, nil, "proj", "demo", nil)
+	if err != nil {
+		t.Fatalf("build labeled: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read tmp: %v", err)
+	}
+	var doc struct {
+		Services map[string]struct {
+			Labels map[string]string `yaml:"labels"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	labels := doc.Services["web"].Labels
+	if labels["team"] != "payments" || labels["keep"] != "me" || labels[LabelIdentifier] != "demo" {
+		t.Fatalf("unexpected labels: %#v", labels)
+	}
+}
+
 func TestParseComposeHashLines(t *testing.T) {
 	out := "web bf6121f2\ncache 781cb76a\nworker 37fd6b88\n"
 	got := parseComposeHashLines(out)
@@ -314,3 +424,129 @@ func TestComposeUp_UsesOverlayWhenIdentifier(t *testing.T) {
 		t.Logf("compose args: %s", joined)
 	}
 }
+
+func TestBuildLabeledProjectTemp_EnvHashFollowsEnvFile(t *testing.T) {
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, "stack.env")
+	yam := "services:\n  web:\n    image: nginx\n    environment:\n      MODE: prod\n"
+	c := &Client{exec: &fakeExec{outConfigYAML: yam}}
+	ctx := WithEnvHashLabel(context.Background(), true)
+
+	envHash := func(content string) string {
+		t.Helper()
+		if err := os.WriteFile(envFile, []byte(content), 0o644); err != nil {
+			t.Fatalf("write env file: %v", err)
+		}
+		path, err := c.buildLabeledProjectTemp(ctx, dir, []string{"compose.yml"}, nil, []string{"stack.env"}, "proj", "demo", []string{"TOKEN=abc"})
+		if err != nil {
+			t.Fatalf("build labeled: %v", err)
+		}
+		defer func() { _ = os.Remove(path) }()
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read tmp: %v", err)
+		}
+		var doc struct {
+			Services map[string]struct {
+				Labels map[string]string `yaml:"labels"`
+			} `yaml:"services"`
+		}
+		if err := yaml.Unmarshal(b, &doc); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		return doc.Services["web"].Labels[LabelEnvHash]
+	}
+
+	first := envHash("FEATURE_FLAG=on\n")
+	if first == "" {
+		t.Fatalf("expected %s label on web", LabelEnvHash)
+	}
+	if again := envHash("FEATURE_FLAG=on\n"); again != first {
+		t.Fatalf("expected a stable hash for an unchanged env, got %s then %s", first, again)
+	}
+	if changed := envHash("FEATURE_FLAG=off\n"); changed == first {
+		t.Fatalf("expected the hash to change when only an env file value changed")
+	}
+
+	ctx = context.Background()
+	if h := envHash("FEATURE_FLAG=on\n"); h != "" {
+		t.Fatalf("expected no env hash label when disabled, got %q", h)
+	}
+}
+
+func TestComposeConfigHash_EnvHashLabelFailsOnUnreadableEnvFile(t *testing.T) {
+	f := &fakeExec{outConfigYAML: "services:\n  web:\n    image: nginx\n", outHash: "web abc\n"}
+	c := &Client{exec: f}
+	dir := t.TempDir()
+
+	// Without the env hash label an unlabeled project is still hashed.
+	if _, err := c.ComposeConfigHash(context.Background(), dir, []string{"compose.yml"}, nil, []string{"missing.env"}, "proj", "web", "demo", nil); err != nil {
+		t.Fatalf("expected fallback without the env hash label, got %v", err)
+	}
+	ctx := WithStack(context.Background(), manifest.Stack{RecreateOnEnvChange: true})
+	if _, err := c.ComposeConfigHash(ctx, dir, []string{"compose.yml"}, nil, []string{"missing.env"}, "proj", "web", "demo", nil); err == nil || !strings.Contains(err.Error(), "missing.env") {
+		t.Fatalf("expected the unreadable env file to fail the hash, got %v", err)
+	}
+}
+
+func TestComposeDependsOn_ShortAndLongForms(t *testing.T) {
+	var doc ComposeConfigDoc
+	if err := json.Unmarshal([]byte(`{"services":{"web":{"depends_on":{"db":{"condition":"service_healthy"},"cache":{}}},"worker":{"depends_on":["db"]}}}`), &doc); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	if got := strings.Join(doc.Services["web"].DependsOn, ","); got != "cache,db" {
+		t.Fatalf("expected map form names, got %q", got)
+	}
+	if got := strings.Join(doc.Services["worker"].DependsOn, ","); got != "db" {
+		t.Fatalf("expected list form names, got %q", got)
+	}
+
+	var ydoc ComposeConfigDoc
+	if err := yaml.Unmarshal([]byte("services:\n  web:\n    depends_on:\n      db:\n        condition: service_started\n"), &ydoc); err != nil {
+		t.Fatalf("yaml: %v", err)
+	}
+	if got := strings.Join(ydoc.Services["web"].DependsOn, ","); got != "db" {
+		t.Fatalf("expected yaml map form names, got %q", got)
+	}
+}
+
+func TestIsComposeSchemaError(t *testing.T) {
+	schema := apperr.Wrap("dockercli.ComposeConfigFull", apperr.Internal,
+		apperr.Wrap("dockercli.Exec", apperr.External, io.EOF, "validating compose.yaml: services.web Additional property develop is not allowed"),
+		"parse compose yaml")
+	if !IsComposeSchemaError(schema) {
+		t.Fatalf("expected a schema error to be detected through the wrap chain")
+	}
+	other := apperr.Wrap("dockercli.Exec", apperr.External, io.EOF, "open compose.yaml: no such file or directory")
+	if IsComposeSchemaError(other) || IsComposeSchemaError(nil) {
+		t.Fatalf("expected other errors not to be treated as schema errors")
+	}
+}
+
+func TestWithComposeOverride_LayersTemporaryFile(t *testing.T) {
+	doc := []byte("services:\n  web:\n    healthcheck:\n      test: [CMD-SHELL, \"true\"]\n")
+	var override string
+	stub := &scriptExec{onRun: func(args []string) (string, error) {
+		if got := args[:3]; strings.Join(got, " ") != "compose -f compose.yaml" {
+			t.Fatalf("expected the user's file first, got %v", args)
+		}
+		override = args[4]
+		if args[3] != "-f" {
+			t.Fatalf("expected the override layered as a second file, got %v", args)
+		}
+		b, err := os.ReadFile(override)
+		if err != nil || string(b) != string(doc) {
+			t.Fatalf("override file = %q (%v), want %q", b, err, doc)
+		}
+		return "web\n", nil
+	}}
+	c := &Client{exec: stub}
+
+	ctx := WithComposeOverride(context.Background(), doc)
+	if _, err := c.ComposeConfigServices(ctx, ".", []string{"compose.yaml"}, nil, nil, nil); err != nil {
+		t.Fatalf("services: %v", err)
+	}
+	if _, err := os.Stat(override); !os.IsNotExist(err) {
+		t.Fatalf("expected the override file removed after the command, got %v", err)
+	}
+}
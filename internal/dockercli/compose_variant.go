@@ -0,0 +1,87 @@
+package dockercli
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+// ComposeVariant is the Compose implementation `compose` commands run with.
+type ComposeVariant string
+
+const (
+	// ComposePlugin is the Compose CLI plugin, run as `docker compose`.
+	ComposePlugin ComposeVariant = "docker compose"
+	// ComposeStandalone is the standalone binary, run as `docker-compose`.
+	ComposeStandalone ComposeVariant = "docker-compose"
+)
+
+// lookPath finds the standalone compose binary; a var so tests can stub it.
+var lookPath = exec.LookPath
+
+// composeDetector remembers which compose variant is installed. SystemExec
+// holds it by pointer so every copy of an exec shares one detection.
+type composeDetector struct {
+	mu      sync.Mutex
+	variant ComposeVariant
+	err     error
+}
+
+// detect returns the installed compose variant, preferring the plugin. A
+// standalone docker-compose v1 is refused, since dockform runs compose with
+// arguments only v2 understands. It looks once; a lookup cut short by ctx is
+// tried again next time.
+func (d *composeDetector) detect(ctx context.Context, env []string) (ComposeVariant, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.variant != "" || d.err != nil {
+		return d.variant, d.err
+	}
+	cmd := exec.CommandContext(ctx, "docker", "compose", "version")
+	cmd.Env = env
+	if err := cmd.Run(); err == nil {
+		d.variant = ComposePlugin
+		return d.variant, nil
+	}
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	if path, err := lookPath("docker-compose"); err == nil {
+		cmd := exec.CommandContext(ctx, path, "version", "--short")
+		cmd.Env = env
+		out, err := cmd.Output()
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if v := strings.TrimPrefix(strings.TrimSpace(string(out)), "v"); err == nil && strings.HasPrefix(v, "1.") {
+			d.err = apperr.New("dockercli.Compose", apperr.Precondition, "docker-compose v1 is not supported (found %s): install the Docker Compose plugin (docker compose) or docker-compose v2", v)
+			return "", d.err
+		}
+		d.variant = ComposeStandalone
+		return d.variant, nil
+	}
+	d.err = apperr.New("dockercli.Compose", apperr.NotFound, "docker compose is not available: install the Docker Compose plugin (docker compose) or the standalone docker-compose binary")
+	return "", d.err
+}
+
+// composeVariant returns the compose variant s runs compose commands with.
+// An exec built without detection, as in tests, assumes the plugin.
+func (s SystemExec) composeVariant(ctx context.Context) (ComposeVariant, error) {
+	if s.compose == nil {
+		return ComposePlugin, nil
+	}
+	return s.compose.detect(ctx, s.environ(nil))
+}
+
+// ComposeVariant reports whether compose commands run through the
+// `docker compose` plugin or the standalone `docker-compose` binary, and
+// fails when neither is installed.
+func (c *Client) ComposeVariant(ctx context.Context) (ComposeVariant, error) {
+	if s, ok := c.exec.(SystemExec); ok {
+		return s.composeVariant(ctx)
+	}
+	return ComposePlugin, nil
+}
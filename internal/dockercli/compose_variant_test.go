@@ -0,0 +1,102 @@
+package dockercli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+// writeStub writes an executable shell script named name into dir.
+func writeStub(t *testing.T, dir, name, script string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatalf("write %s stub: %v", name, err)
+	}
+}
+
+func TestRunDetailed_UsesStandaloneComposeWithoutPlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell stub")
+	}
+	dir := t.TempDir()
+	writeStub(t, dir, "docker", "echo \"docker: 'compose' is not a docker command.\" 1>&2\nexit 1\n")
+	writeStub(t, dir, "docker-compose", "echo \"docker-compose $*\"\n")
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	s := newSystemExec("", "")
+	res, err := s.RunDetailed(context.Background(), Options{}, "compose", "-p", "demo", "ps")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.TrimSpace(res.Stdout); got != "docker-compose -p demo ps" {
+		t.Fatalf("stdout = %q, want the standalone binary without the compose arg", got)
+	}
+	c := &Client{exec: s}
+	if v, err := c.ComposeVariant(context.Background()); err != nil || v != ComposeStandalone {
+		t.Fatalf("variant = %q, %v; want %q", v, err, ComposeStandalone)
+	}
+}
+
+func TestComposeVariant(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell stub")
+	}
+	oldLookPath := lookPath
+	t.Cleanup(func() { lookPath = oldLookPath })
+	lookPath = func(string) (string, error) { return "", os.ErrNotExist }
+
+	t.Run("plugin", func(t *testing.T) {
+		dir := t.TempDir()
+		writeStub(t, dir, "docker", "exit 0\n")
+		t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+		c := &Client{exec: newSystemExec("", "")}
+		if v, err := c.ComposeVariant(context.Background()); err != nil || v != ComposePlugin {
+			t.Fatalf("variant = %q, %v; want %q", v, err, ComposePlugin)
+		}
+	})
+
+	t.Run("neither installed fails before running", func(t *testing.T) {
+		dir := t.TempDir()
+		counter := filepath.Join(dir, "calls.txt")
+		writeStub(t, dir, "docker", "echo x >> '"+counter+"'\nexit 1\n")
+		t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+		s := newSystemExec("", "")
+		_, err := s.RunDetailed(context.Background(), Options{}, "compose", "ps")
+		if !apperr.IsKind(err, apperr.NotFound) || !strings.Contains(err.Error(), "docker-compose") {
+			t.Fatalf("error = %v, want NotFound naming both variants", err)
+		}
+		if _, err := s.RunDetailed(context.Background(), Options{}, "compose", "ls"); err == nil {
+			t.Fatalf("expected the cached detection error")
+		}
+		data, _ := os.ReadFile(counter)
+		if n := strings.Count(string(data), "x"); n != 1 {
+			t.Fatalf("docker ran %d times, want one detection probe", n)
+		}
+	})
+
+	t.Run("standalone v1 refused", func(t *testing.T) {
+		dir := t.TempDir()
+		writeStub(t, dir, "docker", "exit 1\n")
+		writeStub(t, dir, "docker-compose", "echo 1.29.2\n")
+		t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+		lookPath = func(name string) (string, error) { return filepath.Join(dir, name), nil }
+		t.Cleanup(func() { lookPath = func(string) (string, error) { return "", os.ErrNotExist } })
+		c := &Client{exec: newSystemExec("", "")}
+		_, err := c.ComposeVariant(context.Background())
+		if !apperr.IsKind(err, apperr.Precondition) || !strings.Contains(err.Error(), "docker-compose v1 is not supported") {
+			t.Fatalf("error = %v, want docker-compose v1 refused", err)
+		}
+	})
+
+	t.Run("stub exec assumes plugin", func(t *testing.T) {
+		c := &Client{exec: &execStub{}}
+		if v, err := c.ComposeVariant(context.Background()); err != nil || v != ComposePlugin {
+			t.Fatalf("variant = %q, %v; want %q", v, err, ComposePlugin)
+		}
+	})
+}
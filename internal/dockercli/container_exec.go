@@ -0,0 +1,67 @@
+package dockercli
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+// ExecOptions controls an interactive `docker exec`.
+type ExecOptions struct {
+	TTY    bool      // allocate a pseudo-terminal (-t)
+	Stdin  io.Reader // attached with -i when set
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Exec runs cmd inside a running container with its streams attached and
+// returns the command's exit code. It refuses containers that do not carry
+// the client's identifier label, so only containers dockform manages can be
+// entered. A non-zero exit code is not an error: the command ran and its
+// output, including docker's own complaints, already went to opts.Stderr.
+func (c *Client) Exec(ctx context.Context, name string, cmd []string, opts ExecOptions) (int, error) {
+	name = strings.TrimSpace(name)
+	if err := requireNonEmpty(name, "dockercli.Exec", "container name required"); err != nil {
+		return 0, err
+	}
+	if len(cmd) == 0 {
+		return 0, apperr.New("dockercli.Exec", apperr.InvalidInput, "command required")
+	}
+	id, err := c.inspectContainerIdentity(ctx, name)
+	if err != nil {
+		return 0, apperr.Wrap("dockercli.Exec", apperr.NotFound, err, "inspect container %s", name)
+	}
+	if id.Identifier == "" || (c.identifier != "" && id.Identifier != c.identifier) {
+		return 0, apperr.New("dockercli.Exec", apperr.Forbidden, "container %s is not managed by dockform (missing label %s=%s)", name, LabelIdentifier, c.identifier)
+	}
+
+	args := []string{"exec"}
+	if opts.Stdin != nil {
+		args = append(args, "-i")
+	}
+	if opts.TTY {
+		args = append(args, "-t")
+	}
+	args = append(args, name)
+	args = append(args, cmd...)
+
+	stdout, stderr := opts.Stdout, opts.Stderr
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if stderr == nil {
+		stderr = io.Discard
+	}
+	ctx = context.WithValue(ctx, stdOutWriterKey{}, stdout)
+	ctx = context.WithValue(ctx, stdErrWriterKey{}, stderr)
+	res, err := c.exec.RunDetailed(ctx, Options{Stdin: opts.Stdin}, args...)
+	var exitErr *exec.ExitError
+	if err != nil && errors.As(err, &exitErr) && res.ExitCode > 0 {
+		return res.ExitCode, nil
+	}
+	return res.ExitCode, err
+}
@@ -0,0 +1,68 @@
+package dockercli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+func TestExec_RefusesUnmanagedContainer(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels string
+	}{
+		{name: "no identifier label", labels: `{"com.docker.compose.service":"web"}`},
+		{name: "other identifier", labels: `{"io.dockform.identifier":"other"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &execStub{outInspect: "abc " + tt.labels}
+			c := &Client{exec: stub, identifier: "demo"}
+			_, err := c.Exec(context.Background(), "web-1", []string{"sh"}, ExecOptions{})
+			if !apperr.IsKind(err, apperr.Forbidden) {
+				t.Fatalf("error = %v, want Forbidden", err)
+			}
+			if stub.lastArgs[0] != "inspect" {
+				t.Fatalf("expected nothing to run after the label check, last args %#v", stub.lastArgs)
+			}
+		})
+	}
+}
+
+func TestExec_StreamsOutputAndPassesExitCode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell stub")
+	}
+	dir := t.TempDir()
+	script := "#!/bin/sh\n" +
+		"if [ \"$1\" = inspect ]; then echo 'abc {\"io.dockform.identifier\":\"demo\"}'; exit 0; fi\n" +
+		"echo \"$*\"\n" +
+		"echo oops 1>&2\n" +
+		"exit 3\n"
+	if err := os.WriteFile(filepath.Join(dir, "docker"), []byte(script), 0o755); err != nil {
+		t.Fatalf("write stub: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	c := &Client{exec: SystemExec{}, identifier: "demo"}
+	var stdout, stderr bytes.Buffer
+	code, err := c.Exec(context.Background(), "web-1", []string{"ls", "-l"}, ExecOptions{TTY: true, Stdin: strings.NewReader(""), Stdout: &stdout, Stderr: &stderr})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if code != 3 {
+		t.Fatalf("exit code = %d, want 3", code)
+	}
+	if got := strings.TrimSpace(stdout.String()); got != "exec -i -t web-1 ls -l" {
+		t.Fatalf("stdout = %q", got)
+	}
+	if strings.TrimSpace(stderr.String()) != "oops" {
+		t.Fatalf("stderr = %q, want it passed through", stderr.String())
+	}
+}
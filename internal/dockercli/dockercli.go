@@ -8,6 +8,8 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gcstr/dockform/internal/apperr"
 	"github.com/gcstr/dockform/internal/util"
@@ -26,14 +28,25 @@ const LabelPrefix = "io.dockform."
 // LabelIdentifier is the full label key for the Dockform identifier
 const LabelIdentifier = LabelPrefix + "identifier"
 
+// LabelComposeProfiles is the comma-separated list of compose profiles a
+// container was started under, when the container carries it.
+const LabelComposeProfiles = "com.docker.compose.profiles"
+
 // Client provides higher-level helpers around docker CLI.
 type Client struct {
 	exec         Exec
 	identifier   string
 	contextName  string
 	hostOverride string // Manifest-provided DOCKER_HOST override
+	probeTimeout time.Duration
 
 	composeCache *LRUCache[string, ComposeConfigDoc]
+
+	helperMu    sync.Mutex
+	helperReady bool // HelperImage is known to be present
+
+	capsMu sync.Mutex
+	caps   *Capabilities // cached result of a successful Ping
 }
 
 func New(contextName string) *Client {
@@ -58,7 +71,7 @@ func NewWithHost(contextName, host string) *Client {
 // newSystemExec creates a SystemExec, enabling the SSH concurrency semaphore for
 // remote contexts (non-empty context name that isn't "default", or SSH host override).
 func newSystemExec(contextName, hostOverride string) SystemExec {
-	s := SystemExec{ContextName: contextName, HostOverride: hostOverride}
+	s := SystemExec{ContextName: contextName, HostOverride: hostOverride, compose: &composeDetector{}}
 	if isRemoteContext(contextName, hostOverride) {
 		s.sem = make(chan struct{}, MaxConcurrentSSH)
 	}
@@ -78,6 +91,34 @@ func (c *Client) WithIdentifier(id string) *Client {
 	return c
 }
 
+// Settings are the per-run limits and diagnostics of the docker commands a
+// Client runs, as set by the global CLI flags.
+type Settings struct {
+	CommandTimeout time.Duration // --timeout; 0 means no limit
+	PullTimeout    time.Duration // --pull-timeout; 0 falls back to CommandTimeout
+	ProbeTimeout   time.Duration // --context-timeout; 0 leaves the caller's default
+	Retries        int           // --retries; 0 disables retries
+	Tracer         *Tracer       // --trace; nil disables tracing
+}
+
+// DefaultSettings returns the Settings of a Client that was never given any.
+func DefaultSettings() Settings {
+	return Settings{Retries: maxRetries}
+}
+
+// WithSettings applies per-run settings to the commands the client runs.
+func (c *Client) WithSettings(s Settings) *Client {
+	if se, ok := c.exec.(SystemExec); ok {
+		se.CommandTimeout = s.CommandTimeout
+		se.PullTimeout = s.PullTimeout
+		se.Retries = &s.Retries
+		se.Tracer = s.Tracer
+		c.exec = se
+	}
+	c.probeTimeout = s.ProbeTimeout
+	return c
+}
+
 func (c *Client) loadComposeCache(key string) (ComposeConfigDoc, bool) {
 	if c.composeCache == nil {
 		return ComposeConfigDoc{}, false
@@ -162,6 +203,19 @@ func (c *Client) InspectContainerLabels(ctx context.Context, containerName strin
 	return filtered, nil
 }
 
+// InspectContainerImage returns the ID of the image a container was created
+// from. The ID stays valid after the tag it was pulled by moves on.
+func (c *Client) InspectContainerImage(ctx context.Context, containerName string) (string, error) {
+	if containerName == "" {
+		return "", apperr.New("dockercli.InspectContainerImage", apperr.InvalidInput, "container name required")
+	}
+	out, err := c.exec.Run(ctx, "inspect", "-f", "{{.Image}}", containerName)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
 // InspectMultipleContainerLabels returns selected labels from multiple containers in a single call
 func (c *Client) InspectMultipleContainerLabels(ctx context.Context, containerNames []string, keys []string) (map[string]map[string]string, error) {
 	if len(containerNames) == 0 {
@@ -210,23 +264,9 @@ func (c *Client) InspectMultipleContainerLabels(ctx context.Context, containerNa
 	return result, nil
 }
 
-// UpdateContainerLabels adds or updates labels for a running container.
-func (c *Client) UpdateContainerLabels(ctx context.Context, containerName string, labels map[string]string) error {
-	if len(labels) == 0 {
-		return nil
-	}
-	args := []string{"container", "update"}
-	for k, v := range labels {
-		args = append(args, "--label-add", fmt.Sprintf("%s=%s", k, v))
-	}
-	args = append(args, containerName)
-	_, err := c.exec.Run(ctx, args...)
-	return err
-}
-
 // ListComposeContainersAll lists all containers with compose labels (project/service) across the Docker context.
 func (c *Client) ListComposeContainersAll(ctx context.Context) ([]PsBrief, error) {
-	format := `{{.Label "com.docker.compose.project"}};{{.Label "com.docker.compose.service"}};{{.Names}}`
+	format := `{{.Label "com.docker.compose.project"}};{{.Label "com.docker.compose.service"}};{{.Names}};{{.Label "` + LabelComposeProfiles + `"}}`
 	args := []string{"ps", "-a", "--format", format}
 	if c.identifier != "" {
 		args = append(args, "--filter", "label="+LabelIdentifier+"="+c.identifier)
@@ -237,8 +277,8 @@ func (c *Client) ListComposeContainersAll(ctx context.Context) ([]PsBrief, error
 	}
 	var items []PsBrief
 	for _, line := range util.SplitNonEmptyLines(out) {
-		parts := strings.SplitN(line, ";", 3)
-		if len(parts) != 3 {
+		parts := strings.SplitN(line, ";", 4)
+		if len(parts) < 3 {
 			continue
 		}
 		proj := strings.TrimSpace(parts[0])
@@ -247,11 +287,26 @@ func (c *Client) ListComposeContainersAll(ctx context.Context) ([]PsBrief, error
 		if proj == "" || svc == "" {
 			continue
 		}
-		items = append(items, PsBrief{Project: proj, Service: svc, Name: name})
+		item := PsBrief{Project: proj, Service: svc, Name: name}
+		if len(parts) == 4 {
+			item.Profiles = splitProfiles(parts[3])
+		}
+		items = append(items, item)
 	}
 	return items, nil
 }
 
+// splitProfiles parses a comma-separated profiles label value.
+func splitProfiles(v string) []string {
+	var out []string
+	for _, p := range strings.Split(v, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
 // SyncDirToVolume streams a tar of localDir to the named volume's targetPath.
 // Requirements:
 // - targetPath must be absolute and not '/'
@@ -402,6 +457,9 @@ func (c *Client) ExtractTarToVolume(ctx context.Context, volumeName, targetPath
 	if volumeName == "" || !strings.HasPrefix(targetPath, "/") {
 		return apperr.New("dockercli.ExtractTarToVolume", apperr.InvalidInput, "invalid volume or target path")
 	}
+	if err := c.EnsureHelperImage(ctx); err != nil {
+		return err
+	}
 	mountPath := normalizeVolumeMountPath(targetPath)
 	escapedPath := util.ShellEscape(mountPath)
 	cmd := []string{
@@ -461,6 +519,9 @@ func (c *Client) RunVolumeScript(ctx context.Context, volumeName, targetPath, sc
 	if err := requireNonEmpty(script, "dockercli.RunVolumeScript", "script cannot be empty"); err != nil {
 		return VolumeScriptResult{}, err
 	}
+	if err := c.EnsureHelperImage(ctx); err != nil {
+		return VolumeScriptResult{}, err
+	}
 
 	// Build docker run command
 	cmd := []string{"run", "--rm"}
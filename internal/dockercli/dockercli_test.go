@@ -129,21 +129,6 @@ func TestInspectContainerLabels_FilterAndErrors(t *testing.T) {
 	}
 }
 
-func TestUpdateContainerLabels_BuildsArgs(t *testing.T) {
-	stub := &execStub{}
-	c := &Client{exec: stub}
-	_ = c.UpdateContainerLabels(context.Background(), "name", map[string]string{"k1": "v1", "k2": "v2"})
-	if len(stub.lastArgs) == 0 || stub.lastArgs[0] != "container" || stub.lastArgs[1] != "update" {
-		t.Fatalf("unexpected args: %#v", stub.lastArgs)
-	}
-	if !contains(stub.lastArgs, "--label-add") || !contains(stub.lastArgs, "k1=v1") || !contains(stub.lastArgs, "k2=v2") {
-		t.Fatalf("missing label args: %#v", stub.lastArgs)
-	}
-	if stub.lastArgs[len(stub.lastArgs)-1] != "name" {
-		t.Fatalf("container name position mismatch: %#v", stub.lastArgs)
-	}
-}
-
 func TestListComposeContainersAll_ParsesAndFilters(t *testing.T) {
 	stub := &execStub{outPs: "proj;web;name1\ninvalid\nproj;;name2\n"}
 	c := &Client{exec: stub}
@@ -152,6 +137,16 @@ func TestListComposeContainersAll_ParsesAndFilters(t *testing.T) {
 		t.Fatalf("parse items: %v %#v", err, items)
 	}
 
+	stub = &execStub{outPs: "proj;web;name1;\nproj;debug;name2;tools, debug\n"}
+	c = &Client{exec: stub}
+	items, err = c.ListComposeContainersAll(context.Background())
+	if err != nil || len(items) != 2 || items[0].Profiles != nil {
+		t.Fatalf("parse items with profiles label: %v %#v", err, items)
+	}
+	if got := items[1].Profiles; len(got) != 2 || got[0] != "tools" || got[1] != "debug" {
+		t.Fatalf("expected parsed profiles, got %#v", got)
+	}
+
 	c = &Client{exec: stub, identifier: "demo"}
 	_, _ = c.ListComposeContainersAll(context.Background())
 	joined := strings.Join(stub.lastArgs, " ")
@@ -0,0 +1,96 @@
+package dockercli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/secrets"
+)
+
+// LabelEnvHash is the label carrying a hash of a service's resolved environment.
+const LabelEnvHash = LabelPrefix + "env-hash"
+
+// envHashKey is a context key type used to turn on LabelEnvHash for compose
+// operations.
+type envHashKey struct{}
+
+// WithEnvHashLabel returns a context under which compose operations stamp
+// every service with LabelEnvHash. The label is part of the labeled project,
+// so it also feeds the compose config hash: a service whose resolved
+// environment changed is planned as drifted and recreated even when compose
+// itself would not notice.
+func WithEnvHashLabel(ctx context.Context, enabled bool) context.Context {
+	if !enabled {
+		return ctx
+	}
+	return context.WithValue(ctx, envHashKey{}, true)
+}
+
+// envHashLabel reports whether WithEnvHashLabel is set on ctx.
+func envHashLabel(ctx context.Context) bool {
+	enabled, _ := ctx.Value(envHashKey{}).(bool)
+	return enabled
+}
+
+// stackEnv returns the key=value pairs a stack resolves for interpolation: its
+// env files in order, then the inline variables (which include decrypted SOPS
+// secrets).
+func stackEnv(workingDir string, envFiles, inlineEnv []string) ([]string, error) {
+	var env []string
+	for _, f := range envFiles {
+		pth := f
+		if !filepath.IsAbs(pth) {
+			pth = filepath.Join(workingDir, pth)
+		}
+		pairs, err := secrets.ReadDotenvFile(pth)
+		if err != nil {
+			return nil, apperr.Wrap("dockercli.stackEnv", apperr.InvalidInput, err, "read env file %s for the env hash label", f)
+		}
+		env = append(env, pairs...)
+	}
+	return append(env, inlineEnv...), nil
+}
+
+// serviceEnvHash hashes the stack environment together with the environment
+// compose resolved for one service. Later stack values win over earlier ones,
+// and the result does not depend on declaration order.
+func serviceEnvHash(env []string, service map[string]any) string {
+	values := map[string]string{}
+	for _, kv := range env {
+		if k, v, ok := strings.Cut(kv, "="); ok && k != "" {
+			values["stack:"+k] = v
+		}
+	}
+	switch e := service["environment"].(type) {
+	case map[string]any:
+		for k, v := range e {
+			if v == nil {
+				values["service:"+k] = ""
+				continue
+			}
+			values["service:"+k] = fmt.Sprint(v)
+		}
+	case []any:
+		for _, item := range e {
+			k, v, _ := strings.Cut(fmt.Sprint(item), "=")
+			values["service:"+k] = v
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%s\n", k, values[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -34,11 +34,13 @@ type Exec interface {
 // "Connection reset by peer" failures during parallel plan building.
 const MaxConcurrentSSH = 2
 
-// sshMaxRetries and sshRetryBaseDelay are vars (not consts) so tests can shrink
-// the backoff; the same pattern is used for reachabilityProbeTimeout.
+// maxRetries is how often a command failing with a transient error is retried
+// unless Settings.Retries says otherwise. maxRetries and retryBaseDelay are vars
+// (not consts) so tests can shrink the backoff; the same pattern is used for
+// reachabilityProbeTimeout.
 var (
-	sshMaxRetries     = 4
-	sshRetryBaseDelay = 1 * time.Second
+	maxRetries     = 4
+	retryBaseDelay = 1 * time.Second
 )
 
 // SystemExec is a real implementation that shells out to the docker CLI.
@@ -46,8 +48,13 @@ type SystemExec struct {
 	ContextName    string
 	HostOverride   string // When set, uses DOCKER_HOST instead of DOCKER_CONTEXT
 	DefaultTimeout time.Duration
+	CommandTimeout time.Duration // bounds each buffered command; 0 means no limit
+	PullTimeout    time.Duration // bounds commands that may pull images instead of CommandTimeout
+	Retries        *int          // retries of a command failing with a transient error; nil means maxRetries
+	Tracer         *Tracer       // traces every invocation when set
 	Logger         LoggerHook
-	sem            chan struct{} // limits concurrent commands; nil means unlimited
+	sem            chan struct{}    // limits concurrent commands; nil means unlimited
+	compose        *composeDetector // finds docker compose or docker-compose; nil assumes the plugin
 }
 
 // Options controls execution behavior per call.
@@ -91,25 +98,17 @@ func (s *SystemExec) WithDefaultTimeout(d time.Duration) *SystemExec { s.Default
 // WithLogger sets a logger hook to observe command execution.
 func (s *SystemExec) WithLogger(h LoggerHook) *SystemExec { s.Logger = h; return s }
 
-func isSSHConnectionError(stderr string) bool {
-	return strings.Contains(stderr, "kex_exchange_identification") ||
-		strings.Contains(stderr, "Connection reset by peer") ||
-		strings.Contains(stderr, "Connection closed by") ||
-		strings.Contains(stderr, "ssh_exchange_identification") ||
-		strings.Contains(stderr, "banner exchange")
-}
-
 func (s SystemExec) RunDetailed(ctx context.Context, opts Options, args ...string) (Result, error) {
 	l := logger.FromContext(ctx).With("component", "dockercli")
+	_, streamingStdout := ctx.Value(stdOutWriterKey{}).(io.Writer)
+	_, streamingStderr := ctx.Value(stdErrWriterKey{}).(io.Writer)
+	streaming := streamingStdout || streamingStderr
+	if opts.Timeout <= 0 && !streaming && !opts.Probe {
+		opts.Timeout = s.commandTimeout(ctx)
+	}
 	if opts.Timeout <= 0 && s.DefaultTimeout > 0 {
 		opts.Timeout = s.DefaultTimeout
 	}
-	if opts.Timeout > 0 {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
-		defer cancel()
-	}
-
 	if s.sem != nil && !opts.Probe {
 		select {
 		case s.sem <- struct{}{}:
@@ -124,76 +123,67 @@ func (s SystemExec) RunDetailed(ctx context.Context, opts Options, args ...strin
 	}
 	st := logger.StartStep(l, "docker_exec", strings.Join(args, " "), "resource_kind", "process")
 
-	baseEnv := os.Environ()
-	if s.HostOverride != "" {
-		baseEnv = append(baseEnv, fmt.Sprintf("DOCKER_HOST=%s", s.HostOverride))
-	} else if s.ContextName != "" {
-		baseEnv = append(baseEnv, fmt.Sprintf("DOCKER_CONTEXT=%s", s.ContextName))
-	}
-	if len(opts.Env) > 0 {
-		baseEnv = append(baseEnv, opts.Env...)
+	baseEnv := s.environ(opts.Env)
+
+	// Compose commands run through the standalone docker-compose binary when
+	// that is what is installed; args keep the "compose" form for logging.
+	bin, binArgs := "docker", args
+	if len(args) > 0 && args[0] == "compose" {
+		variant, err := s.composeVariant(ctx)
+		if err != nil {
+			_ = st.Fail(err)
+			return Result{}, err
+		}
+		if variant == ComposeStandalone {
+			bin, binArgs = "docker-compose", args[1:]
+		}
 	}
 
-	_, streamingStdout := ctx.Value(stdOutWriterKey{}).(io.Writer)
-	canRetry := s.sem != nil && opts.Stdin == nil && !streamingStdout && !opts.Probe
+	canRetry := opts.Stdin == nil && !streaming && !opts.Probe
 	maxAttempts := 1
 	if canRetry {
-		maxAttempts = sshMaxRetries + 1
+		maxAttempts = s.retries() + 1
 	}
 
 	start := time.Now()
 	var res Result
 	var runErr error
+	var timedOut bool
 
 	for attempt := range maxAttempts {
 		if attempt > 0 {
-			delay := sshRetryBaseDelay * time.Duration(1<<uint(attempt-1))
-			l.Debug("ssh_retry", "attempt", attempt+1, "delay", delay.String(), "args", strings.Join(args, " "))
+			delay := retryBaseDelay * time.Duration(1<<uint(attempt-1))
+			l.Debug("docker_retry", "attempt", attempt+1, "delay", delay.String(), "command", commandName(args), "stderr", strings.TrimSpace(res.Stderr))
 			select {
 			case <-time.After(delay):
 			case <-ctx.Done():
-				return res, ctx.Err()
+				runErr = ctx.Err()
+			}
+			if ctx.Err() != nil {
+				break
 			}
 		}
 
-		cmd := exec.CommandContext(ctx, "docker", args...)
-		cmd.Env = baseEnv
-		if opts.Dir != "" {
-			cmd.Dir = opts.Dir
-		}
-		if opts.Stdin != nil {
-			cmd.Stdin = opts.Stdin
-		}
-
-		var stdout, stderr bytes.Buffer
-		if sw, ok := ctx.Value(stdOutWriterKey{}).(io.Writer); ok && sw != nil {
-			cmd.Stdout = sw
-		} else {
-			cmd.Stdout = &stdout
-		}
-		cmd.Stderr = &stderr
-
-		runErr = cmd.Run()
-
-		exitCode := 0
-		if cmd.ProcessState != nil {
-			exitCode = cmd.ProcessState.ExitCode()
-		}
-		outStr := ""
-		if !streamingStdout {
-			outStr = stdout.String()
-		}
-		res = Result{Stdout: outStr, Stderr: stderr.String(), ExitCode: exitCode, Duration: time.Since(start)}
+		res, timedOut, runErr = s.runAttempt(ctx, opts, baseEnv, bin, binArgs, streamingStdout)
+		res.Duration = time.Since(start)
 
-		if runErr == nil || !isSSHConnectionError(res.Stderr) {
+		// A timed-out attempt is not retried: a wedged daemon would only
+		// multiply the wait.
+		if runErr == nil || timedOut || !retryable(args, res.Stderr) {
 			break
 		}
 	}
 
+	s.Tracer.trace(s, opts.Dir, args, res, runErr)
 	if s.Logger != nil {
 		s.Logger(ExecEvent{Phase: "finish", Args: args, Dir: opts.Dir, Duration: res.Duration, ExitCode: res.ExitCode, Err: runErr, Stderr: res.Stderr})
 	}
 
+	if timedOut {
+		runErr = apperr.Wrap("dockercli.Exec", apperr.External, context.DeadlineExceeded, "operation timed out after %s: docker %s", opts.Timeout, commandName(args))
+		_ = st.Fail(runErr, "exit_code", res.ExitCode, "stderr", res.Stderr)
+		return res, runErr
+	}
 	if runErr != nil {
 		_ = st.Fail(runErr, "exit_code", res.ExitCode, "stderr", res.Stderr)
 		return res, apperr.Wrap("dockercli.Exec", apperr.External, runErr, "%s", res.Stderr)
@@ -202,6 +192,68 @@ func (s SystemExec) RunDetailed(ctx context.Context, opts Options, args ...strin
 	return res, nil
 }
 
+// runAttempt runs the command once. opts.Timeout bounds this attempt alone,
+// so backoff between retries never eats into it; timedOut reports that the
+// attempt, not ctx, ran out of time.
+func (s SystemExec) runAttempt(ctx context.Context, opts Options, env []string, bin string, args []string, streamingStdout bool) (res Result, timedOut bool, err error) {
+	attemptCtx := ctx
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		attemptCtx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(attemptCtx, bin, args...)
+	cmd.Env = env
+	if opts.Dir != "" {
+		cmd.Dir = opts.Dir
+	}
+	if opts.Stdin != nil {
+		cmd.Stdin = opts.Stdin
+	}
+	ownProcessGroup(cmd)
+
+	var stdout, stderr bytes.Buffer
+	if sw, ok := ctx.Value(stdOutWriterKey{}).(io.Writer); ok && sw != nil {
+		cmd.Stdout = sw
+	} else {
+		cmd.Stdout = &stdout
+	}
+	cmd.Stderr = &stderr
+	if sw, ok := ctx.Value(stdErrWriterKey{}).(io.Writer); ok && sw != nil {
+		cmd.Stderr = sw
+	}
+	if tee, ok := ctx.Value(outputKey{}).(io.Writer); ok && tee != nil {
+		cmd.Stdout = io.MultiWriter(cmd.Stdout, tee)
+		cmd.Stderr = io.MultiWriter(cmd.Stderr, tee)
+	}
+
+	err = cmd.Run()
+
+	exitCode := 0
+	if cmd.ProcessState != nil {
+		exitCode = cmd.ProcessState.ExitCode()
+	}
+	outStr := ""
+	if !streamingStdout {
+		outStr = stdout.String()
+	}
+	timedOut = err != nil && attemptCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil
+	return Result{Stdout: outStr, Stderr: stderr.String(), ExitCode: exitCode}, timedOut, err
+}
+
+// environ returns the environment docker commands run with: the process
+// environment, the daemon to talk to, then extra.
+func (s SystemExec) environ(extra []string) []string {
+	env := os.Environ()
+	if s.HostOverride != "" {
+		env = append(env, fmt.Sprintf("DOCKER_HOST=%s", s.HostOverride))
+	} else if s.ContextName != "" {
+		env = append(env, fmt.Sprintf("DOCKER_CONTEXT=%s", s.ContextName))
+	}
+	return append(env, extra...)
+}
+
 func (s SystemExec) Run(ctx context.Context, args ...string) (string, error) {
 	res, err := s.RunDetailed(ctx, Options{}, args...)
 	return res.Stdout, err
@@ -225,6 +277,31 @@ func (s SystemExec) RunWithStdin(ctx context.Context, stdin io.Reader, args ...s
 // stdOutWriterKey is a context key type used to pass a stdout writer to RunDetailed
 type stdOutWriterKey struct{}
 
+// stdErrWriterKey is a context key type used to pass a stderr writer to
+// RunDetailed, for commands whose stderr belongs to the user, such as exec.
+type stdErrWriterKey struct{}
+
+// outputKey is a context key type used to pass a writer that receives a copy
+// of a command's output to RunDetailed.
+type outputKey struct{}
+
+// WithOutput returns a context under which docker commands also copy their
+// stdout and stderr to w as they run, so long commands such as compose up can
+// show progress live. Output is still captured and returned as usual. w may be
+// written from two goroutines at once and must be safe for that.
+func WithOutput(ctx context.Context, w io.Writer) context.Context {
+	return context.WithValue(ctx, outputKey{}, w)
+}
+
+// withoutOutput returns ctx without the writer set by WithOutput, for helper
+// commands whose output is not progress, such as rendering the compose config.
+func withoutOutput(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(outputKey{}).(io.Writer); !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, outputKey{}, nil)
+}
+
 // RunWithStdout executes the docker command and streams stdout to the provided writer.
 // It does not buffer stdout in memory.
 func (s SystemExec) RunWithStdout(ctx context.Context, stdout io.Writer, args ...string) error {
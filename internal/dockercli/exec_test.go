@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -213,9 +214,9 @@ func TestRunDetailed_Probe_SkipsRetry(t *testing.T) {
 	defer func() { _ = os.Setenv("PATH", oldPath) }()
 
 	// Shrink the backoff so the non-probe baseline doesn't sleep ~15s.
-	oldDelay := sshRetryBaseDelay
-	sshRetryBaseDelay = time.Millisecond
-	defer func() { sshRetryBaseDelay = oldDelay }()
+	oldDelay := retryBaseDelay
+	retryBaseDelay = time.Millisecond
+	defer func() { retryBaseDelay = oldDelay }()
 
 	s := SystemExec{sem: make(chan struct{}, MaxConcurrentSSH)}
 
@@ -228,11 +229,11 @@ func TestRunDetailed_Probe_SkipsRetry(t *testing.T) {
 		t.Fatalf("probe call: expected 1 docker invocation, got %d", n)
 	}
 
-	// Non-probe baseline: 1 + sshMaxRetries attempts (proves retry still active by default).
+	// Non-probe baseline: 1 + maxRetries attempts (proves retry still active by default).
 	_ = os.Remove(counter)
 	_, _ = s.RunDetailed(context.Background(), Options{}, "fail")
-	if n := countLines(t, counter); n != sshMaxRetries+1 {
-		t.Fatalf("non-probe call: expected %d invocations, got %d", sshMaxRetries+1, n)
+	if n := countLines(t, counter); n != maxRetries+1 {
+		t.Fatalf("non-probe call: expected %d invocations, got %d", maxRetries+1, n)
 	}
 }
 
@@ -255,3 +256,70 @@ func TestRunDetailed_Probe_SkipsSemaphore(t *testing.T) {
 		t.Fatal("non-probe call should block on full semaphore and hit ctx deadline")
 	}
 }
+
+func TestRunDetailed_TraceRedactsAndReportsExit(t *testing.T) {
+	defer withDockerExecStub(t)()
+	var buf bytes.Buffer
+	ctx := context.Background()
+	s := SystemExec{ContextName: "prod", Tracer: NewTracer(&buf)}
+
+	if _, err := s.Run(ctx, "run", "-e", "DB_PASSWORD=hunter2", "--label", "API_TOKEN=abc", "alpine"); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	_, _ = s.Run(ctx, "fail")
+
+	out := buf.String()
+	if strings.Contains(out, "hunter2") || strings.Contains(out, "abc") {
+		t.Fatalf("expected secrets redacted, got:\n%s", out)
+	}
+	if !strings.Contains(out, "trace: docker run -e DB_PASSWORD=*** --label API_TOKEN=*** alpine [context=prod] → exit 0 in ") {
+		t.Fatalf("expected traced run line, got:\n%s", out)
+	}
+	if !strings.Contains(out, "trace: docker fail [context=prod] → exit 2 in ") {
+		t.Fatalf("expected traced failure with exit status, got:\n%s", out)
+	}
+}
+
+func TestRedactArgs(t *testing.T) {
+	got := strings.Join(RedactArgs([]string{"login", "--password", "pw", "--username", "me", "--build-arg", "VERSION=1", "--password=pw2", "TAG=v1"}), " ")
+	want := "login --password *** --username me --build-arg VERSION=*** --password=*** TAG=v1"
+	if got != want {
+		t.Fatalf("RedactArgs = %q, want %q", got, want)
+	}
+}
+
+func TestRunDetailed_WithOutputCopiesStdoutAndStderr(t *testing.T) {
+	defer withDockerExecStub(t)()
+	var buf lockedBuffer
+	s := SystemExec{}
+	out, err := s.Run(WithOutput(context.Background(), &buf), "fail")
+	if err == nil {
+		t.Fatalf("expected error from fail script")
+	}
+	if !strings.Contains(out, "FAIL OUT") || !strings.Contains(err.Error(), "failure details from docker") {
+		t.Fatalf("output must still be captured; out=%q err=%v", out, err)
+	}
+	got := buf.String()
+	if !strings.Contains(got, "FAIL OUT") || !strings.Contains(got, "failure details from docker") {
+		t.Fatalf("expected stdout and stderr copied to the writer, got %q", got)
+	}
+}
+
+// lockedBuffer is a bytes.Buffer safe for the concurrent stdout and stderr
+// writes of a running command.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
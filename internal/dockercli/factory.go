@@ -19,17 +19,26 @@ type ClientFactory interface {
 // DefaultClientFactory is the standard implementation of ClientFactory.
 // It caches clients per context+identifier combination for efficient reuse.
 type DefaultClientFactory struct {
-	clients map[string]*Client
-	mu      sync.RWMutex
+	clients  map[string]*Client
+	settings Settings
+	mu       sync.RWMutex
 }
 
 // NewClientFactory creates a new DefaultClientFactory.
 func NewClientFactory() *DefaultClientFactory {
 	return &DefaultClientFactory{
-		clients: make(map[string]*Client),
+		clients:  make(map[string]*Client),
+		settings: DefaultSettings(),
 	}
 }
 
+// WithSettings sets the per-run settings applied to every client the factory
+// creates from now on.
+func (f *DefaultClientFactory) WithSettings(s Settings) *DefaultClientFactory {
+	f.settings = s
+	return f
+}
+
 // cacheKey generates a unique key for the client cache.
 func cacheKey(contextName, identifier string) string {
 	return contextName + ":" + identifier
@@ -57,7 +66,7 @@ func (f *DefaultClientFactory) GetClient(contextName, identifier string) *Client
 		return client
 	}
 
-	client := New(contextName).WithIdentifier(identifier)
+	client := New(contextName).WithIdentifier(identifier).WithSettings(f.settings)
 	f.clients[key] = client
 	return client
 }
@@ -94,7 +103,7 @@ func (f *DefaultClientFactory) getOrCreateClientWithHost(contextName, identifier
 		return client
 	}
 
-	client := NewWithHost(contextName, host).WithIdentifier(identifier)
+	client := NewWithHost(contextName, host).WithIdentifier(identifier).WithSettings(f.settings)
 	f.clients[key] = client
 	return client
 }
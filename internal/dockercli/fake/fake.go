@@ -0,0 +1,689 @@
+// Package fake provides an in-memory stand-in for dockercli.Client, so code
+// that drives Docker through the planner's DockerClient interface can be tested
+// without spawning docker processes or installing shell stubs.
+//
+// A Client reports the state held in its exported fields and updates that
+// state on mutating calls (creating a volume adds it to Volumes, writing a file
+// stores it in VolumeFiles, and so on). Every call is recorded and can be
+// inspected with Calls or CallsTo; Errors makes a method fail.
+package fake
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"io"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+)
+
+// Call is one recorded method call.
+type Call struct {
+	Method string
+	Args   []string
+}
+
+// Client is a programmable fake Docker client. Set its fields before use;
+// once calls are in flight, read state back through the accessor methods,
+// which are safe for concurrent use.
+type Client struct {
+	mu    sync.Mutex
+	calls []Call
+
+	// Volumes
+	Volumes         []string
+	NonEmptyVolumes map[string]bool              // volumes IsVolumeEmpty reports as holding data
+	VolumeFiles     map[string]map[string]string // volume -> path relative to the mount -> content
+	ScriptResult    dockercli.VolumeScriptResult // returned by RunVolumeScript
+
+	// Networks
+	Networks         []string
+	ComposeNetworks  []string // subset of Networks owned by a compose stack
+	NetworkInspects  map[string]dockercli.NetworkInspect
+	NetworkEndpoints map[string]dockercli.NetworkEndpoint // "container/network" -> endpoint
+
+	// Containers
+	Containers                   []dockercli.PsBrief
+	ContainerLabels              map[string]map[string]string     // container -> labels
+	ContainerImages              map[string]string                // container -> image reference
+	HealthChecks                 map[string]string                // container -> last health check output
+	ContainersUsingVolume        map[string][]string              // volume -> container names
+	RunningContainersUsingVolume map[string][]string              // volume -> running container names
+	StoppedWith                  map[string]dockercli.StopOptions // container -> options it was last stopped with
+
+	// Images
+	Images   map[string]dockercli.ImageAvailability // image reference -> availability, default present
+	ImageIDs map[string]string                      // image reference -> local image ID
+
+	// Compose
+	Version         string                                // reported compose plugin version
+	ComposeConfigs  map[string]dockercli.ComposeConfigDoc // stack root -> resolved compose config
+	ConfigHashes    map[string]string                     // service -> config hash
+	ComposePsItems  map[string][]dockercli.ComposePsItem  // project -> compose ps output
+	ServiceStatuses map[string]dockercli.ServiceStatus    // service -> status after up, default running
+
+	// Errors makes the named method return the error, e.g.
+	// Errors["ListVolumes"] = errors.New("daemon unavailable").
+	Errors map[string]error
+}
+
+// New returns an empty fake client reporting compose version 2.29.0.
+func New() *Client {
+	return &Client{
+		Version:                      "2.29.0",
+		NonEmptyVolumes:              map[string]bool{},
+		VolumeFiles:                  map[string]map[string]string{},
+		NetworkInspects:              map[string]dockercli.NetworkInspect{},
+		NetworkEndpoints:             map[string]dockercli.NetworkEndpoint{},
+		ContainerLabels:              map[string]map[string]string{},
+		ContainerImages:              map[string]string{},
+		HealthChecks:                 map[string]string{},
+		ContainersUsingVolume:        map[string][]string{},
+		RunningContainersUsingVolume: map[string][]string{},
+		StoppedWith:                  map[string]dockercli.StopOptions{},
+		Images:                       map[string]dockercli.ImageAvailability{},
+		ImageIDs:                     map[string]string{},
+		ComposeConfigs:               map[string]dockercli.ComposeConfigDoc{},
+		ConfigHashes:                 map[string]string{},
+		ComposePsItems:               map[string][]dockercli.ComposePsItem{},
+		ServiceStatuses:              map[string]dockercli.ServiceStatus{},
+		Errors:                       map[string]error{},
+	}
+}
+
+// Calls returns every recorded call in call order.
+func (c *Client) Calls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.calls)
+}
+
+// CallsTo returns the recorded calls of one method in call order.
+func (c *Client) CallsTo(method string) []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []Call
+	for _, call := range c.calls {
+		if call.Method == method {
+			out = append(out, call)
+		}
+	}
+	return out
+}
+
+// VolumeNames returns the current volumes, sorted.
+func (c *Client) VolumeNames() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return sorted(c.Volumes)
+}
+
+// NetworkNames returns the current networks, sorted.
+func (c *Client) NetworkNames() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return sorted(c.Networks)
+}
+
+// File returns the content of a file in a volume.
+func (c *Client) File(volume, relPath string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	content, ok := c.VolumeFiles[volume][cleanRel(relPath)]
+	return content, ok
+}
+
+// call records a call and returns the error programmed for the method. The
+// caller must hold c.mu.
+func (c *Client) call(method string, args ...string) error {
+	c.calls = append(c.calls, Call{Method: method, Args: args})
+	return c.Errors[method]
+}
+
+// Volume operations
+
+func (c *Client) ListVolumes(ctx context.Context) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("ListVolumes"); err != nil {
+		return nil, err
+	}
+	return slices.Clone(c.Volumes), nil
+}
+
+func (c *Client) CreateVolume(ctx context.Context, name string, labels map[string]string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("CreateVolume", name); err != nil {
+		return err
+	}
+	if !slices.Contains(c.Volumes, name) {
+		c.Volumes = append(c.Volumes, name)
+	}
+	return nil
+}
+
+func (c *Client) RemoveVolume(ctx context.Context, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("RemoveVolume", name); err != nil {
+		return err
+	}
+	c.Volumes = slices.DeleteFunc(c.Volumes, func(v string) bool { return v == name })
+	delete(c.VolumeFiles, name)
+	delete(c.NonEmptyVolumes, name)
+	return nil
+}
+
+// IsVolumeEmpty reports a volume as empty unless it is in NonEmptyVolumes or
+// holds files in VolumeFiles.
+func (c *Client) IsVolumeEmpty(ctx context.Context, volumeName string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("IsVolumeEmpty", volumeName); err != nil {
+		return false, err
+	}
+	return !c.NonEmptyVolumes[volumeName] && len(c.VolumeFiles[volumeName]) == 0, nil
+}
+
+// Volume file operations
+
+// ReadFileFromVolume returns the file content, or "" when it does not exist,
+// like the real client.
+func (c *Client) ReadFileFromVolume(ctx context.Context, volumeName, targetPath, relFile string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("ReadFileFromVolume", volumeName, targetPath, relFile); err != nil {
+		return "", err
+	}
+	return c.VolumeFiles[volumeName][cleanRel(relFile)], nil
+}
+
+func (c *Client) ReadIndexFilesFromVolumes(ctx context.Context, volumeNames []string, relFile string) (map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("ReadIndexFilesFromVolumes", append(slices.Clone(volumeNames), relFile)...); err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(volumeNames))
+	for _, v := range volumeNames {
+		out[v] = c.VolumeFiles[v][cleanRel(relFile)]
+	}
+	return out, nil
+}
+
+func (c *Client) WriteFileToVolume(ctx context.Context, volumeName, targetPath, relFile, content string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("WriteFileToVolume", volumeName, targetPath, relFile); err != nil {
+		return err
+	}
+	c.putFile(volumeName, relFile, content)
+	return nil
+}
+
+// ExtractTarToVolume stores the regular files of the archive in VolumeFiles.
+func (c *Client) ExtractTarToVolume(ctx context.Context, volumeName, targetPath string, tarReader io.Reader) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("ExtractTarToVolume", volumeName, targetPath); err != nil {
+		return err
+	}
+	tr := tar.NewReader(tarReader)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		b, err := io.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		c.putFile(volumeName, hdr.Name, string(b))
+	}
+}
+
+// RemovePathsFromVolume deletes the files at, or below, each path.
+func (c *Client) RemovePathsFromVolume(ctx context.Context, volumeName, targetPath string, relPaths []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("RemovePathsFromVolume", append([]string{volumeName, targetPath}, relPaths...)...); err != nil {
+		return err
+	}
+	files := c.VolumeFiles[volumeName]
+	for _, p := range relPaths {
+		p = cleanRel(p)
+		for name := range files {
+			if name == p || strings.HasPrefix(name, p+"/") {
+				delete(files, name)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Client) RunVolumeScript(ctx context.Context, volumeName, targetPath, script string, env []string) (dockercli.VolumeScriptResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("RunVolumeScript", volumeName, targetPath, script); err != nil {
+		return dockercli.VolumeScriptResult{}, err
+	}
+	return c.ScriptResult, nil
+}
+
+// Network operations
+
+func (c *Client) ListNetworks(ctx context.Context) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("ListNetworks"); err != nil {
+		return nil, err
+	}
+	return slices.Clone(c.Networks), nil
+}
+
+func (c *Client) ListComposeNetworks(ctx context.Context) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("ListComposeNetworks"); err != nil {
+		return nil, err
+	}
+	return slices.Clone(c.ComposeNetworks), nil
+}
+
+// CreateNetwork adds the network and records an inspect result matching opts.
+func (c *Client) CreateNetwork(ctx context.Context, name string, labels map[string]string, opts ...dockercli.NetworkCreateOpts) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("CreateNetwork", name); err != nil {
+		return err
+	}
+	if !slices.Contains(c.Networks, name) {
+		c.Networks = append(c.Networks, name)
+	}
+	inspect := dockercli.NetworkInspect{Name: name, Driver: "bridge"}
+	if len(opts) > 0 {
+		o := opts[0]
+		if o.Driver != "" {
+			inspect.Driver = o.Driver
+		}
+		inspect.Options = o.Options
+		inspect.Internal = o.Internal
+		inspect.Attachable = o.Attachable
+		inspect.EnableIPv6 = o.IPv6
+	}
+	c.NetworkInspects[name] = inspect
+	return nil
+}
+
+func (c *Client) RemoveNetwork(ctx context.Context, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("RemoveNetwork", name); err != nil {
+		return err
+	}
+	c.Networks = slices.DeleteFunc(c.Networks, func(n string) bool { return n == name })
+	delete(c.NetworkInspects, name)
+	return nil
+}
+
+// InspectNetwork returns the programmed inspect result, or a bridge network
+// when there is none.
+func (c *Client) InspectNetwork(ctx context.Context, name string) (dockercli.NetworkInspect, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("InspectNetwork", name); err != nil {
+		return dockercli.NetworkInspect{}, err
+	}
+	if inspect, ok := c.NetworkInspects[name]; ok {
+		return inspect, nil
+	}
+	return dockercli.NetworkInspect{Name: name, Driver: "bridge"}, nil
+}
+
+// ContainerNetworkEndpoint returns the programmed endpoint of container on
+// network, or an empty one.
+func (c *Client) ContainerNetworkEndpoint(ctx context.Context, container, network string) (dockercli.NetworkEndpoint, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("ContainerNetworkEndpoint", container, network); err != nil {
+		return dockercli.NetworkEndpoint{}, err
+	}
+	return c.NetworkEndpoints[container+"/"+network], nil
+}
+
+// ConnectNetwork records the endpoint in NetworkEndpoints.
+func (c *Client) ConnectNetwork(ctx context.Context, network, container string, endpoint dockercli.NetworkEndpoint) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("ConnectNetwork", network, container); err != nil {
+		return err
+	}
+	c.NetworkEndpoints[container+"/"+network] = endpoint
+	return nil
+}
+
+func (c *Client) DisconnectNetwork(ctx context.Context, network, container string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("DisconnectNetwork", network, container); err != nil {
+		return err
+	}
+	delete(c.NetworkEndpoints, container+"/"+network)
+	return nil
+}
+
+// Container operations
+
+func (c *Client) ListComposeContainersAll(ctx context.Context) ([]dockercli.PsBrief, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("ListComposeContainersAll"); err != nil {
+		return nil, err
+	}
+	return slices.Clone(c.Containers), nil
+}
+
+func (c *Client) ListContainersUsingVolume(ctx context.Context, volumeName string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("ListContainersUsingVolume", volumeName); err != nil {
+		return nil, err
+	}
+	return slices.Clone(c.ContainersUsingVolume[volumeName]), nil
+}
+
+func (c *Client) ListRunningContainersUsingVolume(ctx context.Context, volumeName string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("ListRunningContainersUsingVolume", volumeName); err != nil {
+		return nil, err
+	}
+	return slices.Clone(c.RunningContainersUsingVolume[volumeName]), nil
+}
+
+func (c *Client) RestartContainer(ctx context.Context, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.call("RestartContainer", name)
+}
+
+// StopContainers records the options each container was stopped with in
+// StoppedWith.
+func (c *Client) StopContainers(ctx context.Context, names []string, opts ...dockercli.StopOptions) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("StopContainers", names...); err != nil {
+		return err
+	}
+	var o dockercli.StopOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	for _, n := range names {
+		c.StoppedWith[n] = o
+	}
+	return nil
+}
+
+func (c *Client) StartContainers(ctx context.Context, names []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.call("StartContainers", names...)
+}
+
+func (c *Client) RemoveContainer(ctx context.Context, name string, force bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("RemoveContainer", name); err != nil {
+		return err
+	}
+	c.Containers = slices.DeleteFunc(c.Containers, func(p dockercli.PsBrief) bool { return p.Name == name })
+	delete(c.ContainerLabels, name)
+	return nil
+}
+
+// InspectContainerLabels returns the requested labels of a container, or all
+// of them when keys is empty.
+func (c *Client) InspectContainerLabels(ctx context.Context, containerName string, keys []string) (map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("InspectContainerLabels", containerName); err != nil {
+		return nil, err
+	}
+	return pickLabels(c.ContainerLabels[containerName], keys), nil
+}
+
+func (c *Client) InspectMultipleContainerLabels(ctx context.Context, containerNames []string, keys []string) (map[string]map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("InspectMultipleContainerLabels", containerNames...); err != nil {
+		return nil, err
+	}
+	out := make(map[string]map[string]string, len(containerNames))
+	for _, name := range containerNames {
+		out[name] = pickLabels(c.ContainerLabels[name], keys)
+	}
+	return out, nil
+}
+
+func (c *Client) InspectContainerImage(ctx context.Context, containerName string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("InspectContainerImage", containerName); err != nil {
+		return "", err
+	}
+	return c.ContainerImages[containerName], nil
+}
+
+func (c *Client) LastHealthCheck(ctx context.Context, name string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("LastHealthCheck", name); err != nil {
+		return "", err
+	}
+	return c.HealthChecks[name], nil
+}
+
+// WaitHealthy succeeds unless an error is programmed for it.
+func (c *Client) WaitHealthy(ctx context.Context, name string, timeout time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.call("WaitHealthy", name)
+}
+
+// Image operations
+
+func (c *Client) CheckImageAvailability(ctx context.Context, imageRef string) (dockercli.ImageAvailability, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("CheckImageAvailability", imageRef); err != nil {
+		return dockercli.ImageUnknown, err
+	}
+	if a, ok := c.Images[imageRef]; ok {
+		return a, nil
+	}
+	return dockercli.ImagePresent, nil
+}
+
+func (c *Client) ImageID(ctx context.Context, imageRef string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("ImageID", imageRef); err != nil {
+		return "", err
+	}
+	return c.ImageIDs[imageRef], nil
+}
+
+// Compose operations
+
+func (c *Client) ComposeVersion(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("ComposeVersion"); err != nil {
+		return "", err
+	}
+	return c.Version, nil
+}
+
+// ComposeConfigFull returns the config programmed for root, keeping only the
+// services enabled by profiles.
+func (c *Client) ComposeConfigFull(ctx context.Context, root string, files []string, profiles []string, envFiles []string, inline []string) (dockercli.ComposeConfigDoc, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("ComposeConfigFull", root); err != nil {
+		return dockercli.ComposeConfigDoc{}, err
+	}
+	return c.config(root, profiles), nil
+}
+
+func (c *Client) ComposeConfigServices(ctx context.Context, root string, files []string, profiles []string, envFiles []string, inline []string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("ComposeConfigServices", root); err != nil {
+		return nil, err
+	}
+	doc := c.config(root, profiles)
+	services := make([]string, 0, len(doc.Services))
+	for name := range doc.Services {
+		services = append(services, name)
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+func (c *Client) ComposeConfigHash(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project, serviceName, identifier string, inline []string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("ComposeConfigHash", root, serviceName); err != nil {
+		return "", err
+	}
+	return c.ConfigHashes[serviceName], nil
+}
+
+func (c *Client) ComposeConfigHashes(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project string, services []string, identifier string, inline []string) (map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("ComposeConfigHashes", append([]string{root}, services...)...); err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(services))
+	for _, s := range services {
+		out[s] = c.ConfigHashes[s]
+	}
+	return out, nil
+}
+
+func (c *Client) ComposePs(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project string, inline []string) ([]dockercli.ComposePsItem, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("ComposePs", root, project); err != nil {
+		return nil, err
+	}
+	return slices.Clone(c.ComposePsItems[project]), nil
+}
+
+func (c *Client) ComposeUp(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project string, inline []string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return "", c.call("ComposeUp", root, project)
+}
+
+func (c *Client) ComposeUpServices(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project string, services []string, inline []string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return "", c.call("ComposeUpServices", append([]string{root, project}, services...)...)
+}
+
+func (c *Client) ComposeRecreateServices(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project string, services []string, inline []string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return "", c.call("ComposeRecreateServices", append([]string{root, project}, services...)...)
+}
+
+func (c *Client) ComposeUpServiceImage(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project, service, image string, inline []string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return "", c.call("ComposeUpServiceImage", root, project, service, image)
+}
+
+func (c *Client) ComposeRun(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project, service string, command []string, inline []string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return "", c.call("ComposeRun", append([]string{root, project, service}, command...)...)
+}
+
+// ComposeServiceStatuses returns the programmed status of each service, or a
+// running one when there is none.
+func (c *Client) ComposeServiceStatuses(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project string, services []string, inline []string) ([]dockercli.ServiceStatus, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("ComposeServiceStatuses", append([]string{root, project}, services...)...); err != nil {
+		return nil, err
+	}
+	out := make([]dockercli.ServiceStatus, 0, len(services))
+	for _, s := range services {
+		st, ok := c.ServiceStatuses[s]
+		if !ok {
+			st = dockercli.ServiceStatus{Service: s, Container: project + "-" + s + "-1", State: "running"}
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
+
+// config returns the compose config for root with the services outside the
+// enabled profiles removed. The caller must hold c.mu.
+func (c *Client) config(root string, profiles []string) dockercli.ComposeConfigDoc {
+	doc := c.ComposeConfigs[root]
+	services := make(map[string]dockercli.ComposeService, len(doc.Services))
+	for name, svc := range doc.Services {
+		if len(svc.Profiles) == 0 || slices.ContainsFunc(svc.Profiles, func(p string) bool {
+			return slices.Contains(profiles, p) || slices.Contains(profiles, "*")
+		}) {
+			services[name] = svc
+		}
+	}
+	doc.Services = services
+	return doc
+}
+
+// putFile stores a file in a volume. The caller must hold c.mu.
+func (c *Client) putFile(volume, relPath, content string) {
+	if c.VolumeFiles[volume] == nil {
+		c.VolumeFiles[volume] = map[string]string{}
+	}
+	c.VolumeFiles[volume][cleanRel(relPath)] = content
+}
+
+func cleanRel(p string) string {
+	return strings.TrimPrefix(path.Clean("/"+p), "/")
+}
+
+func pickLabels(labels map[string]string, keys []string) map[string]string {
+	out := map[string]string{}
+	for k, v := range labels {
+		if len(keys) == 0 || slices.Contains(keys, k) {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func sorted(s []string) []string {
+	out := slices.Clone(s)
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,114 @@
+package fake_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/dockercli/fake"
+	"github.com/gcstr/dockform/internal/manifest"
+	"github.com/gcstr/dockform/internal/planner"
+)
+
+var _ planner.DockerClient = (*fake.Client)(nil)
+
+func TestFake_PlanAndPruneWithoutDocker(t *testing.T) {
+	f := fake.New()
+	f.Volumes = []string{"data", "stale"}
+	f.ComposeConfigs["/srv/web"] = dockercli.ComposeConfigDoc{Services: map[string]dockercli.ComposeService{
+		"nginx": {Image: "nginx:1.27"},
+		"debug": {Image: "busybox", Profiles: []string{"debug"}},
+	}}
+	cfg := manifest.Config{
+		Identifier: "demo",
+		Contexts:   map[string]manifest.ContextConfig{"default": {}},
+		Stacks:     map[string]manifest.Stack{"default/web": {Root: "/srv/web", Files: []string{"compose.yml"}}},
+		DiscoveredFilesets: map[string]manifest.FilesetSpec{
+			"site": {TargetVolume: "data", TargetPath: "/data", Context: "default"},
+		},
+	}
+
+	plan, err := planner.NewWithDocker(f).BuildPlan(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("BuildPlan: %v", err)
+	}
+	if _, _, del := plan.CountChanges(); del != 1 {
+		t.Fatalf("expected the stale volume to be deleted, got %d deletions", del)
+	}
+
+	if err := planner.NewWithDocker(f).Prune(context.Background(), cfg); err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if got := f.VolumeNames(); !slices.Equal(got, []string{"data"}) {
+		t.Fatalf("expected only data to remain, got %v", got)
+	}
+	if calls := f.CallsTo("RemoveVolume"); len(calls) != 1 || calls[0].Args[0] != "stale" {
+		t.Fatalf("expected one RemoveVolume(stale), got %v", calls)
+	}
+}
+
+func TestFake_ProfilesAndErrors(t *testing.T) {
+	f := fake.New()
+	f.ComposeConfigs["/srv/web"] = dockercli.ComposeConfigDoc{Services: map[string]dockercli.ComposeService{
+		"nginx": {},
+		"debug": {Profiles: []string{"debug"}},
+	}}
+	ctx := context.Background()
+
+	got, _ := f.ComposeConfigServices(ctx, "/srv/web", nil, nil, nil, nil)
+	if !slices.Equal(got, []string{"nginx"}) {
+		t.Fatalf("expected profiled service hidden, got %v", got)
+	}
+	got, _ = f.ComposeConfigServices(ctx, "/srv/web", nil, []string{"debug"}, nil, nil)
+	if !slices.Equal(got, []string{"debug", "nginx"}) {
+		t.Fatalf("expected profiled service enabled, got %v", got)
+	}
+
+	boom := errors.New("daemon unavailable")
+	f.Errors["ListVolumes"] = boom
+	if _, err := f.ListVolumes(ctx); !errors.Is(err, boom) {
+		t.Fatalf("expected programmed error, got %v", err)
+	}
+}
+
+func TestFake_VolumeFiles(t *testing.T) {
+	f := fake.New()
+	ctx := context.Background()
+	_ = f.CreateVolume(ctx, "data", nil)
+	if empty, _ := f.IsVolumeEmpty(ctx, "data"); !empty {
+		t.Fatal("expected new volume to be empty")
+	}
+	_ = f.WriteFileToVolume(ctx, "data", "/app", "conf/app.ini", "x=1")
+	if got, _ := f.ReadFileFromVolume(ctx, "data", "/app", "./conf/app.ini"); got != "x=1" {
+		t.Fatalf("expected written content, got %q", got)
+	}
+	if empty, _ := f.IsVolumeEmpty(ctx, "data"); empty {
+		t.Fatal("expected volume with files to be non-empty")
+	}
+	_ = f.RemovePathsFromVolume(ctx, "data", "/app", []string{"conf"})
+	if _, ok := f.File("data", "conf/app.ini"); ok {
+		t.Fatal("expected file under removed directory to be deleted")
+	}
+}
+
+func TestFake_RecordsStopOptionsAndEndpoints(t *testing.T) {
+	f := fake.New()
+	ctx := context.Background()
+
+	_ = f.StopContainers(ctx, []string{"web"}, dockercli.StopOptions{Signal: "SIGQUIT"})
+	if got := f.StoppedWith["web"]; got.Signal != "SIGQUIT" {
+		t.Fatalf("expected the stop signal recorded, got %+v", got)
+	}
+
+	ep := dockercli.NetworkEndpoint{Aliases: []string{"api"}}
+	_ = f.ConnectNetwork(ctx, "front", "web", ep)
+	if got, _ := f.ContainerNetworkEndpoint(ctx, "web", "front"); !slices.Equal(got.Aliases, ep.Aliases) {
+		t.Fatalf("expected the endpoint recorded, got %+v", got)
+	}
+	_ = f.DisconnectNetwork(ctx, "front", "web")
+	if got, _ := f.ContainerNetworkEndpoint(ctx, "web", "front"); len(got.Aliases) != 0 {
+		t.Fatalf("expected the endpoint removed, got %+v", got)
+	}
+}
@@ -0,0 +1,138 @@
+package dockercli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+// healthPollInterval is how often WaitHealthy inspects the container; a var
+// so tests can shorten it.
+var healthPollInterval = time.Second
+
+// containerState is the subset of `docker inspect` .State used to decide
+// whether a container is healthy.
+type containerState struct {
+	Status   string `json:"Status"`
+	Running  bool   `json:"Running"`
+	ExitCode int    `json:"ExitCode"`
+	Health   *struct {
+		Status string `json:"Status"`
+		Log    []struct {
+			ExitCode int    `json:"ExitCode"`
+			Output   string `json:"Output"`
+		} `json:"Log"`
+	} `json:"Health"`
+}
+
+// ready reports whether the container is healthy, or running when it has no
+// healthcheck.
+func (s containerState) ready() bool {
+	if s.Health != nil && s.Health.Status != "" {
+		return s.Running && s.Health.Status == "healthy"
+	}
+	return s.Running
+}
+
+// completed reports whether the container ran to completion, as one-shot
+// services such as migrations do.
+func (s containerState) completed() bool {
+	return s.Status == "exited" && s.ExitCode == 0
+}
+
+// stopped reports whether the container has stopped for good, so waiting
+// longer cannot make it healthy.
+func (s containerState) stopped() bool {
+	return s.Status == "exited" || s.Status == "dead"
+}
+
+// lastCheck returns the last line of output of the most recent health check,
+// or "" when there is none.
+func (s containerState) lastCheck() string {
+	if s.Health == nil || len(s.Health.Log) == 0 {
+		return ""
+	}
+	return lastLine(s.Health.Log[len(s.Health.Log)-1].Output)
+}
+
+// describe renders the state as e.g. "unhealthy" or "exited (code 1)",
+// followed by the output of the last health check when there is one.
+func (s containerState) describe() string {
+	desc := s.Status
+	if s.stopped() {
+		desc = fmt.Sprintf("%s (code %d)", s.Status, s.ExitCode)
+	} else if s.Health != nil && s.Health.Status != "" {
+		desc = s.Health.Status
+	}
+	if line := s.lastCheck(); line != "" {
+		desc += "; last health check: " + line
+	}
+	return desc
+}
+
+// lastLine returns the last non-empty line of s, trimmed.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// inspectState returns the .State of a container.
+func (c *Client) inspectState(ctx context.Context, op, name string) (containerState, error) {
+	out, err := c.exec.Run(ctx, "inspect", "-f", "{{json .State}}", name)
+	if err != nil {
+		return containerState{}, err
+	}
+	var st containerState
+	if err := json.Unmarshal([]byte(strings.TrimSpace(out)), &st); err != nil {
+		return containerState{}, apperr.Wrap(op, apperr.Internal, err, "parse state of container %s", name)
+	}
+	return st, nil
+}
+
+// LastHealthCheck returns the last line of output of a container's most
+// recent health check, or "" when it has no healthcheck or none ran yet.
+func (c *Client) LastHealthCheck(ctx context.Context, name string) (string, error) {
+	if err := requireNonEmpty(name, "dockercli.LastHealthCheck", "container name required"); err != nil {
+		return "", err
+	}
+	st, err := c.inspectState(ctx, "dockercli.LastHealthCheck", name)
+	if err != nil {
+		return "", err
+	}
+	return st.lastCheck(), nil
+}
+
+// WaitHealthy polls a container until .State.Health.Status reports healthy,
+// or until .State.Running when it has no healthcheck. A container that exited
+// with code 0 ran to completion and counts as done. It fails when the
+// container stops otherwise or timeout elapses, describing the last state seen
+// and the output of its last health check.
+func (c *Client) WaitHealthy(ctx context.Context, name string, timeout time.Duration) error {
+	if err := requireNonEmpty(name, "dockercli.WaitHealthy", "container name required"); err != nil {
+		return err
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		st, err := c.inspectState(ctx, "dockercli.WaitHealthy", name)
+		if err != nil {
+			return err
+		}
+		switch {
+		case st.ready(), st.completed():
+			return nil
+		case st.stopped():
+			return apperr.New("dockercli.WaitHealthy", apperr.External, "container %s %s", name, st.describe())
+		case !time.Now().Before(deadline):
+			return apperr.New("dockercli.WaitHealthy", apperr.Timeout, "container %s did not become healthy within %s: %s", name, timeout, st.describe())
+		}
+		select {
+		case <-ctx.Done():
+			return apperr.Wrap("dockercli.WaitHealthy", apperr.Timeout, ctx.Err(), "wait for container %s: %s", name, st.describe())
+		case <-time.After(healthPollInterval):
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package dockercli
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+func TestWaitHealthy(t *testing.T) {
+	tests := []struct {
+		name    string
+		state   string
+		wantErr string
+		kind    apperr.Kind
+	}{
+		{name: "healthy", state: `{"Status":"running","Running":true,"Health":{"Status":"healthy"}}`},
+		{name: "running without healthcheck", state: `{"Status":"running","Running":true}`},
+		{
+			name:    "unhealthy reports last check output",
+			state:   `{"Status":"running","Running":true,"Health":{"Status":"unhealthy","Log":[{"ExitCode":1,"Output":"old"},{"ExitCode":1,"Output":"connecting\ncurl: (7) connection refused\n"}]}}`,
+			wantErr: "container web did not become healthy within 0s: unhealthy; last health check: curl: (7) connection refused",
+			kind:    apperr.Timeout,
+		},
+		{name: "exited 0 ran to completion", state: `{"Status":"exited","Running":false,"ExitCode":0}`},
+		{
+			name:    "exited fails without waiting",
+			state:   `{"Status":"exited","Running":false,"ExitCode":3}`,
+			wantErr: "container web exited (code 3)",
+			kind:    apperr.External,
+		},
+		{
+			name:    "restarting times out",
+			state:   `{"Status":"restarting","Running":false}`,
+			wantErr: "did not become healthy within 0s: restarting",
+			kind:    apperr.Timeout,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &execStub{outInspect: tt.state}
+			c := &Client{exec: stub}
+			err := c.WaitHealthy(context.Background(), "web", 0)
+			if !containsArgSeq(stub.lastArgs, []string{"inspect", "-f", "{{json .State}}", "web"}) {
+				t.Fatalf("inspect args mismatch: %#v", stub.lastArgs)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+			if !apperr.IsKind(err, tt.kind) {
+				t.Fatalf("error kind mismatch for %v, want %s", err, tt.kind)
+			}
+		})
+	}
+}
+
+func TestWaitHealthy_PollsUntilHealthy(t *testing.T) {
+	old := healthPollInterval
+	healthPollInterval = time.Millisecond
+	t.Cleanup(func() { healthPollInterval = old })
+
+	stub := &sequenceExec{execStub: &execStub{}, states: []string{
+		`{"Status":"running","Running":true,"Health":{"Status":"starting"}}`,
+		`{"Status":"running","Running":true,"Health":{"Status":"healthy"}}`,
+	}}
+	c := &Client{exec: stub}
+	if err := c.WaitHealthy(context.Background(), "db", time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stub.calls != 2 {
+		t.Fatalf("expected 2 inspections, got %d", stub.calls)
+	}
+}
+
+func TestLastHealthCheck(t *testing.T) {
+	tests := map[string]string{
+		`{"Status":"running","Running":true,"Health":{"Status":"unhealthy","Log":[{"ExitCode":1,"Output":"old"},{"ExitCode":1,"Output":"connecting\ncurl: (7) connection refused\n"}]}}`: "curl: (7) connection refused",
+		`{"Status":"running","Running":true,"Health":{"Status":"starting"}}`: "",
+		`{"Status":"running","Running":true}`:                                "",
+	}
+	for state, want := range tests {
+		c := &Client{exec: &execStub{outInspect: state}}
+		got, err := c.LastHealthCheck(context.Background(), "web")
+		if err != nil || got != want {
+			t.Errorf("LastHealthCheck(%s) = %q, %v; want %q", state, got, err, want)
+		}
+	}
+}
+
+// sequenceExec answers each inspect with the next state in turn.
+type sequenceExec struct {
+	*execStub
+	states []string
+	calls  int
+}
+
+func (e *sequenceExec) Run(ctx context.Context, args ...string) (string, error) {
+	out := e.states[min(e.calls, len(e.states)-1)]
+	e.calls++
+	return out, nil
+}
@@ -0,0 +1,42 @@
+package dockercli
+
+import (
+	"context"
+	"time"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+// helperPullRetryDelay is a var so tests can shorten it.
+var helperPullRetryDelay = 2 * time.Second
+
+// EnsureHelperImage makes sure HelperImage is present on the daemon, pulling it
+// when missing and retrying the pull once to ride out transient registry
+// failures. Success is remembered for the lifetime of the client.
+func (c *Client) EnsureHelperImage(ctx context.Context) error {
+	c.helperMu.Lock()
+	defer c.helperMu.Unlock()
+	if c.helperReady {
+		return nil
+	}
+	if ok, _ := c.ImageExists(ctx, HelperImage); ok {
+		c.helperReady = true
+		return nil
+	}
+
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(helperPullRetryDelay):
+			}
+		}
+		if _, err = c.exec.Run(pulling(ctx), "pull", HelperImage); err == nil {
+			c.helperReady = true
+			return nil
+		}
+	}
+	return apperr.Wrap("dockercli.EnsureHelperImage", apperr.Unavailable, err, "helper image %s unavailable; pre-pull it with 'docker pull %s' or run 'dockform doctor'", HelperImage, HelperImage)
+}
@@ -0,0 +1,71 @@
+package dockercli
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+// helperExec simulates a daemon without the helper image whose first pulls fail.
+type helperExec struct {
+	fakeExec
+	pullFailures int
+	calls        []string
+}
+
+func (h *helperExec) Run(ctx context.Context, args ...string) (string, error) {
+	h.calls = append(h.calls, strings.Join(args, " "))
+	switch args[0] {
+	case "image":
+		return "", errors.New("No such image")
+	case "pull":
+		if h.pullFailures > 0 {
+			h.pullFailures--
+			return "", errors.New("registry unavailable")
+		}
+	}
+	return "", nil
+}
+
+func TestEnsureHelperImage_RetriesPullOnce(t *testing.T) {
+	orig := helperPullRetryDelay
+	helperPullRetryDelay = 0
+	defer func() { helperPullRetryDelay = orig }()
+
+	h := &helperExec{pullFailures: 1}
+	c := &Client{exec: h}
+	if err := c.EnsureHelperImage(context.Background()); err != nil {
+		t.Fatalf("expected transient pull failure to be retried, got %v", err)
+	}
+	if got := strings.Join(h.calls, "|"); got != "image inspect "+HelperImage+"|pull "+HelperImage+"|pull "+HelperImage {
+		t.Fatalf("unexpected calls: %s", got)
+	}
+
+	// Presence is remembered; no further daemon calls.
+	h.calls = nil
+	if err := c.EnsureHelperImage(context.Background()); err != nil || len(h.calls) != 0 {
+		t.Fatalf("expected cached success, got err=%v calls=%v", err, h.calls)
+	}
+}
+
+func TestEnsureHelperImage_ActionableErrorBlocksExtract(t *testing.T) {
+	orig := helperPullRetryDelay
+	helperPullRetryDelay = 0
+	defer func() { helperPullRetryDelay = orig }()
+
+	h := &helperExec{pullFailures: 2}
+	c := &Client{exec: h}
+	err := c.ExtractTarToVolume(context.Background(), "data", "/data", strings.NewReader(""))
+	if err == nil || !apperr.IsKind(err, apperr.Unavailable) {
+		t.Fatalf("expected unavailable error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "helper image "+HelperImage+" unavailable") {
+		t.Fatalf("expected actionable message, got %v", err)
+	}
+	if h.lastArgs != nil {
+		t.Fatalf("tar extraction must not run without the helper image, ran %v", h.lastArgs)
+	}
+}
@@ -0,0 +1,54 @@
+package dockercli
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+// ImageAvailability says whether compose will be able to start a service from
+// an image.
+type ImageAvailability string
+
+const (
+	// ImagePresent means the image is already on the daemon.
+	ImagePresent ImageAvailability = "present"
+	// ImagePullable means the registry has a manifest for the image.
+	ImagePullable ImageAvailability = "pullable"
+	// ImageMissing means the registry has no such repository or tag.
+	ImageMissing ImageAvailability = "missing"
+	// ImageUnauthorized means the registry refused to show the manifest.
+	ImageUnauthorized ImageAvailability = "unauthorized"
+	// ImageUnknown means the registry could not be asked, e.g. it is offline.
+	ImageUnknown ImageAvailability = "unknown"
+)
+
+// CheckImageAvailability reports whether imageRef is present locally or can be
+// pulled, without pulling it: a local `image inspect` is tried first, then the
+// registry is asked with `docker manifest inspect`. Registry failures that are
+// neither "not found" nor an auth error return ImageUnknown with the error.
+func (c *Client) CheckImageAvailability(ctx context.Context, imageRef string) (ImageAvailability, error) {
+	if err := requireNonEmpty(imageRef, "dockercli.CheckImageAvailability", "image reference required"); err != nil {
+		return ImageUnknown, err
+	}
+	imageRef = strings.TrimSpace(imageRef)
+	if ok, _ := c.ImageExists(ctx, imageRef); ok {
+		return ImagePresent, nil
+	}
+	_, err := c.exec.Run(ctx, "manifest", "inspect", imageRef)
+	if err == nil {
+		return ImagePullable, nil
+	}
+	if ctx.Err() != nil {
+		return ImageUnknown, ctx.Err()
+	}
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "unauthorized"), strings.Contains(msg, "denied"), strings.Contains(msg, "authentication required"):
+		return ImageUnauthorized, nil
+	case strings.Contains(msg, "no such manifest"), strings.Contains(msg, "manifest unknown"), strings.Contains(msg, "not found"):
+		return ImageMissing, nil
+	}
+	return ImageUnknown, apperr.Wrap("dockercli.CheckImageAvailability", apperr.External, err, "inspect manifest of %s", imageRef)
+}
@@ -0,0 +1,37 @@
+package dockercli
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCheckImageAvailability(t *testing.T) {
+	cases := []struct {
+		name        string
+		inspectErr  error
+		manifestErr error
+		want        ImageAvailability
+		wantErr     bool
+	}{
+		{name: "present", want: ImagePresent},
+		{name: "pullable", inspectErr: errors.New("No such image"), want: ImagePullable},
+		{name: "missing", inspectErr: errors.New("No such image"), manifestErr: errors.New("no such manifest: docker.io/library/nginx:nope"), want: ImageMissing},
+		{name: "unauthorized", inspectErr: errors.New("No such image"), manifestErr: errors.New("unauthorized: authentication required"), want: ImageUnauthorized},
+		{name: "offline", inspectErr: errors.New("No such image"), manifestErr: errors.New("dial tcp: lookup registry: no such host"), want: ImageUnknown, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			stub := &scriptExec{onRun: func(args []string) (string, error) {
+				if args[0] == "image" {
+					return "", tc.inspectErr
+				}
+				return "", tc.manifestErr
+			}}
+			got, err := (&Client{exec: stub}).CheckImageAvailability(context.Background(), "nginx:nope")
+			if got != tc.want || (err != nil) != tc.wantErr {
+				t.Fatalf("got (%s, %v), want %s (error: %v)", got, err, tc.want, tc.wantErr)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
 )
 
 // ServerVersion returns the Docker Engine server version for the configured context.
@@ -37,6 +39,33 @@ func (c *Client) ContextHost(ctx context.Context) (string, error) {
 	return s, nil
 }
 
+// SecurityOptions returns the names of the security features the daemon runs
+// with, e.g. "selinux", "apparmor" or "seccomp", from `docker info`.
+func (c *Client) SecurityOptions(ctx context.Context) ([]string, error) {
+	out, err := c.exec.Run(ctx, "info", "--format", "{{json .SecurityOptions}}")
+	if err != nil {
+		return nil, err
+	}
+	out = strings.TrimSpace(out)
+	if out == "" || out == "null" {
+		return nil, nil
+	}
+	var raw []string
+	if err := json.Unmarshal([]byte(out), &raw); err != nil {
+		return nil, apperr.Wrap("dockercli.SecurityOptions", apperr.External, err, "parse docker info security options")
+	}
+	// Entries look like "name=seccomp,profile=builtin".
+	names := make([]string, 0, len(raw))
+	for _, opt := range raw {
+		for _, kv := range strings.Split(opt, ",") {
+			if name, ok := strings.CutPrefix(kv, "name="); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	return names, nil
+}
+
 // ComposeVersion returns the docker compose plugin version (short form) if available.
 func (c *Client) ComposeVersion(ctx context.Context) (string, error) {
 	// Prefer short output when available
@@ -135,6 +164,19 @@ func (c *Client) ImageRepoDigestMap(ctx context.Context, imageIDs []string) (map
 	return result, nil
 }
 
+// ImageID returns the ID (sha256:…) of the local image a reference resolves
+// to, i.e. the image a container created from it now would run.
+func (c *Client) ImageID(ctx context.Context, imageRef string) (string, error) {
+	if strings.TrimSpace(imageRef) == "" {
+		return "", apperr.New("dockercli.ImageID", apperr.InvalidInput, "image reference required")
+	}
+	out, err := c.exec.Run(ctx, "image", "inspect", "--format", "{{.Id}}", imageRef)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
 // ImageExists returns true if the given image is present locally in the configured context.
 func (c *Client) ImageExists(ctx context.Context, imageRef string) (bool, error) {
 	if strings.TrimSpace(imageRef) == "" {
@@ -23,6 +23,8 @@ type infoExecStub struct {
 	composeLongErr  error
 
 	imageInspectErr error
+
+	swarmOut string
 }
 
 func (s *infoExecStub) Run(ctx context.Context, args ...string) (string, error) {
@@ -30,6 +32,8 @@ func (s *infoExecStub) Run(ctx context.Context, args ...string) (string, error)
 	switch {
 	case len(args) == 3 && args[0] == "version" && args[1] == "--format":
 		return s.serverOut, s.serverErr
+	case len(args) == 3 && args[0] == "info" && args[1] == "--format":
+		return s.swarmOut, nil
 	case len(args) == 5 && args[0] == "context" && args[1] == "inspect":
 		return s.contextOut, s.contextErr
 	case len(args) == 3 && args[0] == "compose" && args[1] == "version" && args[2] == "--short":
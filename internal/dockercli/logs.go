@@ -7,20 +7,48 @@ import (
 	"strings"
 )
 
-// StreamContainerLogs streams logs for a container to w until ctx is canceled.
+// StreamContainerLogs streams logs for a container to w until ctx is canceled,
+// carrying on with the replacement container when it is recreated (see
+// FollowContainerLogs).
 // tail specifies how many lines to include initially (0 = default). since is optional RFC3339 timestamp.
 func (c *Client) StreamContainerLogs(ctx context.Context, name string, tail int, since string, w io.Writer) error {
 	name = strings.TrimSpace(name)
 	if name == "" {
 		return nil
 	}
-	args := []string{"logs", "--follow"}
-	if tail > 0 {
-		args = append(args, "--tail", fmt.Sprint(tail))
+	return c.FollowContainerLogs(ctx, name, LogsOptions{Follow: true, Tail: tail, Since: since}, w)
+}
+
+// LogsOptions controls a `docker logs` invocation.
+type LogsOptions struct {
+	Follow     bool
+	Tail       int    // lines to include initially (0 = all)
+	Since      string // optional timestamp or relative duration, e.g. 10m
+	Timestamps bool   // prefix each line with the container timestamp
+}
+
+// ContainerLogs writes a container's logs to w, what it wrote to stderr as
+// well as to stdout, following them until ctx is canceled when opts.Follow is
+// set.
+func (c *Client) ContainerLogs(ctx context.Context, name string, opts LogsOptions, w io.Writer) error {
+	if err := requireNonEmpty(name, "dockercli.ContainerLogs", "container name required"); err != nil {
+		return err
+	}
+	args := []string{"logs"}
+	if opts.Follow {
+		args = append(args, "--follow")
+	}
+	if opts.Tail > 0 {
+		args = append(args, "--tail", fmt.Sprint(opts.Tail))
+	}
+	if strings.TrimSpace(opts.Since) != "" {
+		args = append(args, "--since", opts.Since)
 	}
-	if strings.TrimSpace(since) != "" {
-		args = append(args, "--since", since)
+	if opts.Timestamps {
+		args = append(args, "--timestamps")
 	}
-	args = append(args, name)
+	args = append(args, strings.TrimSpace(name))
+	// docker logs replays the container's stderr on its own stderr.
+	ctx = context.WithValue(ctx, stdErrWriterKey{}, w)
 	return c.exec.RunWithStdout(ctx, w, args...)
 }
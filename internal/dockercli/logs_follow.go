@@ -0,0 +1,217 @@
+package dockercli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+// ContainerRecreatedMarker is written between the logs of a followed container
+// and those of the container that replaced it.
+const ContainerRecreatedMarker = "── container recreated ──"
+
+// ContainerRestartedMarker is written where a followed container's logs resume
+// after it was restarted.
+const ContainerRestartedMarker = "── container restarted ──"
+
+// Compose labels identifying which service replica a container runs.
+const (
+	labelComposeProject         = "com.docker.compose.project"
+	labelComposeService         = "com.docker.compose.service"
+	labelComposeContainerNumber = "com.docker.compose.container-number"
+)
+
+// How often a followed log stream checks whether its container came back once
+// the stream ends, how long it waits for a removed (or restarting) container
+// to be replaced, and how long for a container that merely stopped.
+var (
+	logsReconnectPoll    = time.Second
+	logsReconnectTimeout = 5 * time.Minute
+	logsExitedGrace      = 30 * time.Second
+)
+
+// containerIdentity is what a compose container is recognised by across
+// recreations: its replica of a service in a project.
+type containerIdentity struct {
+	ID         string
+	Project    string
+	Service    string
+	Number     string
+	Identifier string
+}
+
+// logsResume is where a followed log stream carries on after it ended.
+type logsResume struct {
+	id containerIdentity
+	// restarted is set when the same container is running again; since then
+	// holds when it started, so the logs already shown are not repeated.
+	restarted bool
+	since     string
+}
+
+// FollowContainerLogs writes a container's logs to w like ContainerLogs, and
+// keeps going when the container is restarted or, for a compose container,
+// recreated (e.g. by an apply): once the stream ends it waits for the
+// container to run again or for a new container of the same service replica,
+// writes ContainerRestartedMarker or ContainerRecreatedMarker and resumes.
+// It returns when the container stays stopped for a short while, or is
+// removed and not replaced within a few minutes.
+func (c *Client) FollowContainerLogs(ctx context.Context, name string, opts LogsOptions, w io.Writer) error {
+	if !opts.Follow {
+		return c.ContainerLogs(ctx, name, opts, w)
+	}
+	if err := requireNonEmpty(name, "dockercli.FollowContainerLogs", "container name required"); err != nil {
+		return err
+	}
+	id, idErr := c.inspectContainerIdentity(ctx, strings.TrimSpace(name))
+	lw := &lineEndWriter{w: w, atLineStart: true}
+	target := name
+	for {
+		err := c.ContainerLogs(ctx, target, opts, lw)
+		if ctx.Err() != nil || idErr != nil {
+			return err
+		}
+		next, ok := c.waitForResume(ctx, id)
+		if !ok {
+			return err
+		}
+		if !lw.atLineStart {
+			if _, werr := io.WriteString(lw, "\n"); werr != nil {
+				return werr
+			}
+		}
+		marker := ContainerRecreatedMarker
+		if next.restarted {
+			marker = ContainerRestartedMarker
+		}
+		if _, werr := io.WriteString(lw, marker+"\n"); werr != nil {
+			return werr
+		}
+		// A replacement's logs are all new, so show them from the start; a
+		// restarted container's from when it started again.
+		opts.Tail, opts.Since = 0, next.since
+		id, target = next.id, next.id.ID
+	}
+}
+
+// inspectContainerIdentity reads the ID and compose labels of a container.
+func (c *Client) inspectContainerIdentity(ctx context.Context, name string) (containerIdentity, error) {
+	out, err := c.exec.Run(ctx, "inspect", "-f", "{{.Id}} {{json .Config.Labels}}", name)
+	if err != nil {
+		return containerIdentity{}, err
+	}
+	idPart, labelsPart, _ := strings.Cut(strings.TrimSpace(out), " ")
+	var labels map[string]string
+	if labelsPart != "" && labelsPart != "null" {
+		if err := json.Unmarshal([]byte(labelsPart), &labels); err != nil {
+			return containerIdentity{}, apperr.Wrap("dockercli.inspectContainerIdentity", apperr.Internal, err, "parse labels json")
+		}
+	}
+	return containerIdentity{
+		ID:         idPart,
+		Project:    labels[labelComposeProject],
+		Service:    labels[labelComposeService],
+		Number:     labels[labelComposeContainerNumber],
+		Identifier: labels[LabelIdentifier],
+	}, nil
+}
+
+// waitForResume polls until prev is running again or, for a compose
+// container, a running container of the same service replica with a
+// different ID shows up. It waits up to logsReconnectTimeout while prev is
+// removed or being restarted by its restart policy, and logsExitedGrace while
+// it sits stopped. It returns false when ctx is canceled or it gives up.
+func (c *Client) waitForResume(ctx context.Context, prev containerIdentity) (logsResume, bool) {
+	var replicaFilters []string
+	if prev.Service != "" {
+		replicaFilters = []string{
+			"label=" + labelComposeProject + "=" + prev.Project,
+			"label=" + labelComposeService + "=" + prev.Service,
+		}
+		if prev.Number != "" {
+			replicaFilters = append(replicaFilters, "label="+labelComposeContainerNumber+"="+prev.Number)
+		}
+		if prev.Identifier != "" {
+			replicaFilters = append(replicaFilters, fmt.Sprintf("label=%s=%s", LabelIdentifier, prev.Identifier))
+		}
+	}
+
+	deadline := time.NewTimer(logsReconnectTimeout)
+	defer deadline.Stop()
+	tick := time.NewTicker(logsReconnectPoll)
+	defer tick.Stop()
+	var stoppedSince time.Time
+	for {
+		state, exists, err := c.containerState(ctx, prev.ID)
+		if err == nil {
+			switch {
+			case state == "running":
+				started, _ := c.exec.Run(ctx, "inspect", "-f", "{{.State.StartedAt}}", prev.ID)
+				return logsResume{id: prev, restarted: true, since: strings.TrimSpace(started)}, true
+			case !exists && prev.Service == "":
+				return logsResume{}, false
+			case exists && state != "restarting":
+				if stoppedSince.IsZero() {
+					stoppedSince = time.Now()
+				} else if time.Since(stoppedSince) >= logsExitedGrace {
+					return logsResume{}, false
+				}
+			default:
+				stoppedSince = time.Time{}
+			}
+		}
+		if replicaFilters != nil {
+			if rows, err := c.PsJSON(ctx, false, replicaFilters); err == nil {
+				for _, r := range rows {
+					if r.ID != "" && !strings.HasPrefix(prev.ID, r.ID) {
+						next := prev
+						next.ID = r.ID
+						return logsResume{id: next}, true
+					}
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return logsResume{}, false
+		case <-deadline.C:
+			return logsResume{}, false
+		case <-tick.C:
+		}
+	}
+}
+
+// containerState reports the state (running, exited, restarting, ...) of the
+// container with the given ID, and whether it still exists at all.
+func (c *Client) containerState(ctx context.Context, id string) (string, bool, error) {
+	rows, err := c.PsJSON(ctx, true, []string{"id=" + id})
+	if err != nil {
+		return "", false, err
+	}
+	for _, r := range rows {
+		if r.ID != "" && strings.HasPrefix(id, r.ID) {
+			return r.State, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// lineEndWriter remembers whether the last byte written ended a line, so a
+// marker can be started on a fresh line.
+type lineEndWriter struct {
+	w           io.Writer
+	atLineStart bool
+}
+
+func (l *lineEndWriter) Write(p []byte) (int, error) {
+	n, err := l.w.Write(p)
+	if n > 0 {
+		l.atLineStart = p[n-1] == '\n'
+	}
+	return n, err
+}
@@ -0,0 +1,185 @@
+package dockercli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFollowContainerLogs_ResumesOnRecreatedContainer(t *testing.T) {
+	oldPoll := logsReconnectPoll
+	logsReconnectPoll = time.Millisecond
+	defer func() { logsReconnectPoll = oldPoll }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var psFilters []string
+	streams := 0
+	stub := &scriptExec{
+		onRun: func(args []string) (string, error) {
+			switch args[0] {
+			case "inspect":
+				return `0123456789abcdef {"com.docker.compose.project":"app","com.docker.compose.service":"web","com.docker.compose.container-number":"1"}`, nil
+			case "ps":
+				psFilters = args
+				// The old container is still listed while the new one starts.
+				return `{"ID":"0123456789ab","Names":"app-web-1"}` + "\n" + `{"ID":"fedcba987654","Names":"app-web-1"}`, nil
+			}
+			return "", nil
+		},
+		onRunWithStdout: func(args []string, w io.Writer) error {
+			streams++
+			if streams == 2 {
+				cancel()
+			}
+			return nil
+		},
+	}
+	c := &Client{exec: stub}
+
+	var out bytes.Buffer
+	if err := c.FollowContainerLogs(ctx, "app-web-1", LogsOptions{Follow: true, Tail: 50}, &out); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+	want := "STREAM\n" + ContainerRecreatedMarker + "\nSTREAM"
+	if out.String() != want {
+		t.Fatalf("output = %q, want %q", out.String(), want)
+	}
+	if got := strings.Join(stub.lastArgs, " "); got != "logs --follow fedcba987654" {
+		t.Errorf("expected the new container followed from the start, got %q", got)
+	}
+	if !strings.Contains(strings.Join(psFilters, " "), "label=com.docker.compose.container-number=1") {
+		t.Errorf("expected the replacement looked up by replica, got %v", psFilters)
+	}
+}
+
+func TestFollowContainerLogs_RemovedNonComposeContainerStops(t *testing.T) {
+	stub := &scriptExec{
+		onRun: func(args []string) (string, error) {
+			switch args[0] {
+			case "inspect":
+				return `0123456789abcdef {}`, nil
+			case "ps":
+				// The container was removed.
+				return "", nil
+			}
+			t.Fatalf("unexpected docker call %v", args)
+			return "", nil
+		},
+	}
+	c := &Client{exec: stub}
+	var out bytes.Buffer
+	if err := c.FollowContainerLogs(context.Background(), "plain", LogsOptions{Follow: true}, &out); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+	if out.String() != "STREAM" {
+		t.Fatalf("expected a single stream, got %q", out.String())
+	}
+}
+
+func TestFollowContainerLogs_ResumesRestartedContainerSinceStart(t *testing.T) {
+	oldPoll := logsReconnectPoll
+	logsReconnectPoll = time.Millisecond
+	defer func() { logsReconnectPoll = oldPoll }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	streams := 0
+	stub := &scriptExec{
+		onRun: func(args []string) (string, error) {
+			switch {
+			case args[0] == "inspect" && args[2] == "{{.State.StartedAt}}":
+				return "2026-10-16T09:30:00.123456789Z\n", nil
+			case args[0] == "inspect":
+				return `0123456789abcdef {"com.docker.compose.project":"app","com.docker.compose.service":"web"}`, nil
+			case args[0] == "ps":
+				return `{"ID":"0123456789ab","Names":"app-web-1","State":"running"}`, nil
+			}
+			return "", nil
+		},
+		onRunWithStdout: func(args []string, w io.Writer) error {
+			streams++
+			if streams == 2 {
+				cancel()
+			}
+			return nil
+		},
+	}
+	c := &Client{exec: stub}
+
+	var out bytes.Buffer
+	if err := c.FollowContainerLogs(ctx, "app-web-1", LogsOptions{Follow: true, Tail: 50}, &out); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+	want := "STREAM\n" + ContainerRestartedMarker + "\nSTREAM"
+	if out.String() != want {
+		t.Fatalf("output = %q, want %q", out.String(), want)
+	}
+	if got := strings.Join(stub.lastArgs, " "); got != "logs --follow --since 2026-10-16T09:30:00.123456789Z 0123456789abcdef" {
+		t.Errorf("expected the same container followed from its restart, got %q", got)
+	}
+}
+
+func TestFollowContainerLogs_StoppedContainerGivesUpAfterGrace(t *testing.T) {
+	oldPoll, oldGrace := logsReconnectPoll, logsExitedGrace
+	logsReconnectPoll, logsExitedGrace = time.Millisecond, 20*time.Millisecond
+	defer func() { logsReconnectPoll, logsExitedGrace = oldPoll, oldGrace }()
+
+	stub := &scriptExec{
+		onRun: func(args []string) (string, error) {
+			switch args[0] {
+			case "inspect":
+				return `0123456789abcdef {"com.docker.compose.project":"app","com.docker.compose.service":"web"}`, nil
+			case "ps":
+				if slices.Contains(args, "-a") {
+					return `{"ID":"0123456789ab","Names":"app-web-1","State":"exited"}`, nil
+				}
+			}
+			return "", nil
+		},
+	}
+	c := &Client{exec: stub}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var out bytes.Buffer
+	if err := c.FollowContainerLogs(ctx, "app-web-1", LogsOptions{Follow: true}, &out); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("expected follow to give up on a stopped container well before the reconnect timeout")
+	}
+	if out.String() != "STREAM" {
+		t.Fatalf("expected a single stream, got %q", out.String())
+	}
+}
+
+func TestContainerLogs_IncludesStderr(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell stub")
+	}
+	dir := t.TempDir()
+	script := "#!/bin/sh\n" +
+		"echo listening\n" +
+		"echo 'warning: cache miss' 1>&2\n"
+	if err := os.WriteFile(filepath.Join(dir, "docker"), []byte(script), 0o755); err != nil {
+		t.Fatalf("write stub: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	c := &Client{exec: SystemExec{}}
+	var out bytes.Buffer
+	if err := c.ContainerLogs(context.Background(), "web-1", LogsOptions{}, &out); err != nil {
+		t.Fatalf("logs: %v", err)
+	}
+	if got := out.String(); got != "listening\nwarning: cache miss\n" {
+		t.Fatalf("output = %q, want both streams", got)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/gcstr/dockform/internal/apperr"
 	"github.com/gcstr/dockform/internal/util"
 )
 
@@ -104,6 +105,67 @@ func (c *Client) RemoveNetwork(ctx context.Context, name string) error {
 	return err
 }
 
+// NetworkEndpoint is how a container is attached to a network: the DNS
+// aliases it answers to there and the addresses it was given statically.
+type NetworkEndpoint struct {
+	Aliases     []string
+	IPv4Address string
+	IPv6Address string
+}
+
+// ContainerNetworkEndpoint returns how a container is attached to a network,
+// or an empty endpoint when it is not attached to it.
+func (c *Client) ContainerNetworkEndpoint(ctx context.Context, container, network string) (NetworkEndpoint, error) {
+	out, err := c.exec.Run(ctx, "inspect", "-f", "{{json .NetworkSettings.Networks}}", container)
+	if err != nil {
+		return NetworkEndpoint{}, err
+	}
+	var networks map[string]struct {
+		Aliases    []string `json:"Aliases"`
+		IPAMConfig *struct {
+			IPv4Address string `json:"IPv4Address"`
+			IPv6Address string `json:"IPv6Address"`
+		} `json:"IPAMConfig"`
+	}
+	if err := json.Unmarshal([]byte(out), &networks); err != nil {
+		return NetworkEndpoint{}, apperr.Wrap("dockercli.ContainerNetworkEndpoint", apperr.Internal, err, "parse networks of container %s", container)
+	}
+	n, ok := networks[network]
+	if !ok {
+		return NetworkEndpoint{}, nil
+	}
+	ep := NetworkEndpoint{Aliases: n.Aliases}
+	if n.IPAMConfig != nil {
+		ep.IPv4Address, ep.IPv6Address = n.IPAMConfig.IPv4Address, n.IPAMConfig.IPv6Address
+	}
+	return ep, nil
+}
+
+// ConnectNetwork attaches a container to a network with the given endpoint
+// aliases and static addresses.
+func (c *Client) ConnectNetwork(ctx context.Context, network, container string, endpoint NetworkEndpoint) error {
+	args := []string{"network", "connect"}
+	for _, a := range endpoint.Aliases {
+		args = append(args, "--alias", a)
+	}
+	if endpoint.IPv4Address != "" {
+		args = append(args, "--ip", endpoint.IPv4Address)
+	}
+	if endpoint.IPv6Address != "" {
+		args = append(args, "--ip6", endpoint.IPv6Address)
+	}
+	args = append(args, network, container)
+	_, err := c.exec.Run(ctx, args...)
+	return err
+}
+
+// DisconnectNetwork detaches a container from a network, forcibly so that
+// stopped containers are released too.
+func (c *Client) DisconnectNetwork(ctx context.Context, network, container string) error {
+	_, err := c.exec.Run(ctx, "network", "disconnect", "--force", network, container)
+	return err
+}
+
 // NetworkSummaries returns basic metadata for docker networks (name & driver).
 func (c *Client) NetworkSummaries(ctx context.Context) ([]NetworkSummary, error) {
 	args := []string{"network", "ls", "--format", "{{.Name}}\t{{.Driver}}"}
@@ -101,3 +101,48 @@ func TestRemoveNetwork_Args(t *testing.T) {
 		t.Fatalf("unexpected args: %#v", stub.lastArgs)
 	}
 }
+
+func TestConnectDisconnectNetwork_Args(t *testing.T) {
+	stub := &netExecStub{}
+	c := &Client{exec: stub}
+	if err := c.DisconnectNetwork(context.Background(), "n1", "web-1"); err != nil {
+		t.Fatalf("disconnect network: %v", err)
+	}
+	if !containsArgSeq(stub.lastArgs, []string{"network", "disconnect", "--force", "n1", "web-1"}) {
+		t.Fatalf("unexpected disconnect args: %#v", stub.lastArgs)
+	}
+	if err := c.ConnectNetwork(context.Background(), "n1", "web-1", NetworkEndpoint{}); err != nil {
+		t.Fatalf("connect network: %v", err)
+	}
+	if !containsArgSeq(stub.lastArgs, []string{"network", "connect", "n1", "web-1"}) {
+		t.Fatalf("unexpected connect args: %#v", stub.lastArgs)
+	}
+	ep := NetworkEndpoint{Aliases: []string{"web", "app-web-1"}, IPv4Address: "10.10.0.5", IPv6Address: "fd00::5"}
+	if err := c.ConnectNetwork(context.Background(), "n1", "web-1", ep); err != nil {
+		t.Fatalf("connect network: %v", err)
+	}
+	want := []string{"network", "connect", "--alias", "web", "--alias", "app-web-1", "--ip", "10.10.0.5", "--ip6", "fd00::5", "n1", "web-1"}
+	if !containsArgSeq(stub.lastArgs, want) {
+		t.Fatalf("unexpected connect args: %#v", stub.lastArgs)
+	}
+}
+
+func TestContainerNetworkEndpoint_ReadsAliasesAndStaticIPs(t *testing.T) {
+	exec := &scriptExec{onRun: func(args []string) (string, error) {
+		return `{"n1":{"Aliases":["web","abc123"],"IPAMConfig":{"IPv4Address":"10.10.0.5","IPv6Address":""}},"bridge":{"Aliases":null,"IPAMConfig":null}}`, nil
+	}}
+	c := &Client{exec: exec}
+	ep, err := c.ContainerNetworkEndpoint(context.Background(), "web-1", "n1")
+	if err != nil {
+		t.Fatalf("endpoint: %v", err)
+	}
+	if strings.Join(ep.Aliases, ",") != "web,abc123" || ep.IPv4Address != "10.10.0.5" || ep.IPv6Address != "" {
+		t.Fatalf("unexpected endpoint: %+v", ep)
+	}
+	if !containsArgSeq(exec.lastArgs, []string{"inspect", "-f", "{{json .NetworkSettings.Networks}}", "web-1"}) {
+		t.Fatalf("unexpected args: %#v", exec.lastArgs)
+	}
+	if ep, err := c.ContainerNetworkEndpoint(context.Background(), "web-1", "other"); err != nil || len(ep.Aliases) != 0 || ep.IPv4Address != "" {
+		t.Fatalf("expected empty endpoint for an unattached network, got %+v, %v", ep, err)
+	}
+}
@@ -0,0 +1,122 @@
+package dockercli
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// HostPort is a port bound on the daemon host.
+type HostPort struct {
+	Protocol string // "tcp" or "udp"
+	IP       string // bound address; "0.0.0.0" or "::" for every address
+	Port     int
+}
+
+// listeningPortsScript prints the host's socket tables, each preceded by a
+// "# <table>" marker line.
+const listeningPortsScript = `for f in tcp tcp6 udp udp6; do echo "# $f"; cat /proc/net/$f 2>/dev/null; done`
+
+// ListeningPorts returns the TCP ports listening and the UDP ports bound on the
+// daemon host, read from /proc/net in a helper container sharing the host's
+// network namespace. Ports published by containers show up too, as docker
+// binds them on the host.
+func (c *Client) ListeningPorts(ctx context.Context) ([]HostPort, error) {
+	out, err := c.exec.Run(ctx, "run", "--rm", "--network", "host", HelperImage, "sh", "-c", listeningPortsScript)
+	if err != nil {
+		return nil, err
+	}
+	return parseProcNet(out), nil
+}
+
+// parseProcNet parses the output of listeningPortsScript. Only listening TCP
+// sockets are kept; every UDP socket is bound to its port.
+func parseProcNet(out string) []HostPort {
+	var ports []HostPort
+	seen := map[HostPort]bool{}
+	table := ""
+	s := bufio.NewScanner(strings.NewReader(out))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if name, ok := strings.CutPrefix(line, "# "); ok {
+			table = name
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[0] == "sl" {
+			continue
+		}
+		protocol := strings.TrimSuffix(table, "6")
+		if protocol == "tcp" && fields[3] != "0A" { // 0A = LISTEN
+			continue
+		}
+		addr, portHex, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			continue
+		}
+		port, err := strconv.ParseUint(portHex, 16, 16)
+		ip := decodeProcNetIP(addr)
+		if err != nil || port == 0 || ip == "" {
+			continue
+		}
+		hp := HostPort{Protocol: protocol, IP: ip, Port: int(port)}
+		if !seen[hp] {
+			seen[hp] = true
+			ports = append(ports, hp)
+		}
+	}
+	return ports
+}
+
+// decodeProcNetIP decodes a /proc/net address, stored as 32-bit words in host
+// (little-endian) byte order.
+func decodeProcNetIP(s string) string {
+	b, err := hex.DecodeString(s)
+	if err != nil || (len(b) != net.IPv4len && len(b) != net.IPv6len) {
+		return ""
+	}
+	for i := 0; i+4 <= len(b); i += 4 {
+		b[i], b[i+1], b[i+2], b[i+3] = b[i+3], b[i+2], b[i+1], b[i]
+	}
+	return net.IP(b).String()
+}
+
+// HostPorts returns the host ports a container publishes, parsed from the
+// Ports column of docker ps (e.g. "0.0.0.0:8080->80/tcp, [::]:8080->80/tcp").
+// Ranges are expanded; exposed but unpublished ports are skipped.
+func (r PsJSONRow) HostPorts() []HostPort {
+	var ports []HostPort
+	for _, entry := range strings.Split(r.Ports, ",") {
+		host, container, ok := strings.Cut(strings.TrimSpace(entry), "->")
+		if !ok {
+			continue
+		}
+		i := strings.LastIndex(host, ":")
+		if i < 0 {
+			continue
+		}
+		ip := strings.Trim(host[:i], "[]")
+		_, protocol, _ := strings.Cut(container, "/")
+		if protocol == "" {
+			protocol = "tcp"
+		}
+		loStr, hiStr, isRange := strings.Cut(host[i+1:], "-")
+		lo, err := strconv.Atoi(loStr)
+		if err != nil {
+			continue
+		}
+		hi := lo
+		if isRange {
+			if hi, err = strconv.Atoi(hiStr); err != nil || hi < lo {
+				continue
+			}
+		}
+		for p := lo; p <= hi; p++ {
+			ports = append(ports, HostPort{Protocol: protocol, IP: ip, Port: p})
+		}
+	}
+	return ports
+}
@@ -0,0 +1,64 @@
+package dockercli
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseProcNet(t *testing.T) {
+	out := `# tcp
+  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1 1 0 100 0 0 10 0
+   1: 0100007F:1538 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 2 1 0 100 0 0 10 0
+   2: 0100007F:9C40 0100007F:1538 01 00000000:00000000 00:00000000 00000000     0        0 3 1 0 100 0 0 10 0
+# tcp6
+  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000000000000:1F90 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 4 1 0 100 0 0 10 0
+# udp
+  sl  local_address rem_address   st tx_queue rx_queue tr tm->when ref pointer drops
+   0: 00000000:0035 00000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 5 2 0 0
+# udp6
+`
+	got := parseProcNet(out)
+	want := []HostPort{
+		{Protocol: "tcp", IP: "0.0.0.0", Port: 8080},
+		{Protocol: "tcp", IP: "127.0.0.1", Port: 5432},
+		{Protocol: "tcp", IP: "::", Port: 8080},
+		{Protocol: "udp", IP: "0.0.0.0", Port: 53},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseProcNet mismatch:\n got: %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestListeningPorts_RunsHelperOnHostNetwork(t *testing.T) {
+	exec := &scriptExec{onRun: func(args []string) (string, error) {
+		return "# tcp\n   0: 00000000:0050 00000000:0000 0A 0 0 0 0 0 0 0 1\n", nil
+	}}
+	c := &Client{exec: exec}
+	got, err := c.ListeningPorts(context.Background())
+	if err != nil {
+		t.Fatalf("ListeningPorts: %v", err)
+	}
+	if len(got) != 1 || got[0].Port != 80 {
+		t.Fatalf("expected port 80, got %+v", got)
+	}
+	if args := strings.Join(exec.lastArgs, " "); !strings.Contains(args, "run --rm --network host "+HelperImage) {
+		t.Fatalf("expected helper run on host network, got %q", args)
+	}
+}
+
+func TestPsJSONRow_HostPorts(t *testing.T) {
+	r := PsJSONRow{Ports: "0.0.0.0:8080->80/tcp, [::]:8080->80/tcp, 127.0.0.1:5000-5001->5000-5001/udp, 9000/tcp"}
+	want := []HostPort{
+		{Protocol: "tcp", IP: "0.0.0.0", Port: 8080},
+		{Protocol: "tcp", IP: "::", Port: 8080},
+		{Protocol: "udp", IP: "127.0.0.1", Port: 5000},
+		{Protocol: "udp", IP: "127.0.0.1", Port: 5001},
+	}
+	if got := r.HostPorts(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("HostPorts mismatch:\n got: %+v\nwant: %+v", got, want)
+	}
+}
@@ -0,0 +1,14 @@
+package dockercli
+
+import "time"
+
+// DefaultProbeTimeout bounds a daemon reachability probe when --context-timeout
+// is not set. A firewalled SSH host never refuses the connection, so without a
+// deadline the probe would block until the SSH client gives up.
+const DefaultProbeTimeout = 5 * time.Second
+
+// ProbeTimeout returns the reachability probe timeout set with WithSettings,
+// if any.
+func (c *Client) ProbeTimeout() (time.Duration, bool) {
+	return c.probeTimeout, c.probeTimeout > 0
+}
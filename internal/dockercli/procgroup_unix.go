@@ -0,0 +1,28 @@
+//go:build !windows
+
+package dockercli
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// ownProcessGroup starts cmd in a process group of its own. On Ctrl+C a
+// terminal sends SIGINT to its whole foreground group; outside of it the
+// docker command is left to finish the resource apply is on, while dockform
+// asks apply to stop after it. Cancelling the command's context kills the
+// whole group, so compose plugins and ssh connections go with it.
+//
+// A command reading stdin from a file such as the terminal stays in the
+// foreground group: a background group may not read from or configure the
+// terminal, which an interactive `docker exec -it` needs.
+func ownProcessGroup(cmd *exec.Cmd) {
+	if _, ok := cmd.Stdin.(*os.File); ok {
+		return
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,81 @@
+//go:build !windows
+
+package dockercli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestInterruptHelperProcess is not a real test: TestRunDetailed_FirstInterruptLeavesCommandRunning
+// re-runs the test binary with it to get a process that, like dockform, catches
+// SIGINT and runs a docker command.
+func TestInterruptHelperProcess(t *testing.T) {
+	if os.Getenv("DOCKFORM_TEST_INTERRUPT_HELPER") != "1" {
+		t.Skip("helper process")
+	}
+	signal.Notify(make(chan os.Signal, 1), os.Interrupt)
+	out, err := SystemExec{}.Run(context.Background(), "volume", "ls")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Print(out)
+	os.Exit(0)
+}
+
+func TestRunDetailed_FirstInterruptLeavesCommandRunning(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "started")
+	script := "#!/bin/sh\n" +
+		"touch '" + marker + "'\n" +
+		"sleep 1\n" +
+		"echo done\n"
+	if err := os.WriteFile(filepath.Join(dir, "docker"), []byte(script), 0o755); err != nil {
+		t.Fatalf("write stub: %v", err)
+	}
+
+	helper := exec.Command(os.Args[0], "-test.run=^TestInterruptHelperProcess$")
+	helper.Env = append(os.Environ(),
+		"DOCKFORM_TEST_INTERRUPT_HELPER=1",
+		"PATH="+dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	// The helper leads its own group, as dockform does in a terminal's
+	// foreground group, so the interrupt reaches every process in it.
+	helper.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	var stdout, stderr strings.Builder
+	helper.Stdout, helper.Stderr = &stdout, &stderr
+	if err := helper.Start(); err != nil {
+		t.Fatalf("start helper: %v", err)
+	}
+	defer func() { _ = syscall.Kill(-helper.Process.Pid, syscall.SIGKILL) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(marker); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("docker stub never started; stderr: %s", stderr.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// Ctrl+C: the terminal signals the whole foreground group.
+	if err := syscall.Kill(-helper.Process.Pid, syscall.SIGINT); err != nil {
+		t.Fatalf("interrupt: %v", err)
+	}
+
+	if err := helper.Wait(); err != nil {
+		t.Fatalf("expected the docker command to finish despite the interrupt, got %v; stderr: %s", err, stderr.String())
+	}
+	if strings.TrimSpace(stdout.String()) != "done" {
+		t.Fatalf("expected the command's output, got %q", stdout.String())
+	}
+}
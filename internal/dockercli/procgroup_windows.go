@@ -0,0 +1,9 @@
+//go:build windows
+
+package dockercli
+
+import "os/exec"
+
+// ownProcessGroup is a no-op on Windows, where Ctrl+C is not delivered to a
+// terminal's foreground process group.
+func ownProcessGroup(cmd *exec.Cmd) {}
@@ -16,6 +16,7 @@ type PsJSONRow struct {
 	Status string `json:"Status"`
 	State  string `json:"State"`
 	Labels string `json:"Labels"`
+	Ports  string `json:"Ports"`
 }
 
 // PsJSON returns docker ps entries as parsed rows. When all is true, includes stopped containers (-a).
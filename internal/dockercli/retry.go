@@ -0,0 +1,57 @@
+package dockercli
+
+import (
+	"strings"
+)
+
+// retries returns how often a command failing with a transient error is retried.
+func (s SystemExec) retries() int {
+	if s.Retries != nil {
+		return max(*s.Retries, 0)
+	}
+	return maxRetries
+}
+
+// readOnlyCommands are the docker commands that only read state, so running
+// one again after it failed midway is harmless.
+var readOnlyCommands = map[string]bool{
+	"version": true, "info": true, "ps": true, "inspect": true, "images": true,
+	"container inspect": true, "container ls": true,
+	"image inspect": true, "image ls": true,
+	"volume inspect": true, "volume ls": true,
+	"network inspect": true, "network ls": true,
+	"context inspect": true, "context ls": true, "context show": true,
+	"secret inspect": true, "secret ls": true,
+	"manifest inspect": true, "system df": true, "system info": true,
+	"compose version": true, "compose config": true, "compose ps": true, "compose ls": true, "compose images": true,
+}
+
+// isSSHHandshakeError reports whether stderr shows the SSH connection failing
+// before the docker command reached the daemon, so any command may be retried.
+func isSSHHandshakeError(stderr string) bool {
+	return strings.Contains(stderr, "kex_exchange_identification") ||
+		strings.Contains(stderr, "ssh_exchange_identification") ||
+		strings.Contains(stderr, "banner exchange")
+}
+
+// isTransientError reports whether stderr shows a dropped or refused
+// connection rather than the command itself failing.
+func isTransientError(stderr string) bool {
+	return isSSHHandshakeError(stderr) ||
+		strings.Contains(stderr, "Connection reset by peer") ||
+		strings.Contains(stderr, "Connection closed by") ||
+		strings.Contains(stderr, "connection reset by peer") ||
+		strings.Contains(stderr, "broken pipe") ||
+		strings.Contains(stderr, "i/o timeout") ||
+		strings.Contains(stderr, "error during connect")
+}
+
+// retryable reports whether a failed command may run again. Read-only
+// commands retry on any transient error; others only when the connection
+// failed before the command could have changed anything.
+func retryable(args []string, stderr string) bool {
+	if isSSHHandshakeError(stderr) {
+		return true
+	}
+	return readOnlyCommands[commandName(args)] && isTransientError(stderr)
+}
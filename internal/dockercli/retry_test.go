@@ -0,0 +1,152 @@
+package dockercli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestRunDetailed_RetriesOnlyReadsOnConnectionReset(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell stub")
+	}
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "calls.txt")
+	script := "#!/bin/sh\n" +
+		"echo x >> '" + counter + "'\n" +
+		"echo 'read: Connection reset by peer' 1>&2\n" +
+		"exit 1\n"
+	if err := os.WriteFile(filepath.Join(dir, "docker"), []byte(script), 0o755); err != nil {
+		t.Fatalf("write stub: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	oldDelay := retryBaseDelay
+	retryBaseDelay = time.Millisecond
+	defer func() { retryBaseDelay = oldDelay }()
+
+	tests := []struct {
+		name    string
+		retries int
+		args    []string
+		want    int
+	}{
+		{name: "read retried", retries: maxRetries, args: []string{"volume", "ls"}, want: maxRetries + 1},
+		{name: "retry count configurable", retries: 1, args: []string{"compose", "-p", "demo", "ps"}, want: 2},
+		{name: "retries disabled", retries: 0, args: []string{"inspect", "-f", "{{.Image}}", "web"}, want: 1},
+		{name: "mutation not retried", retries: maxRetries, args: []string{"volume", "create", "data"}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = os.Remove(counter)
+			s := SystemExec{Retries: &tt.retries}
+			if _, err := s.RunDetailed(context.Background(), Options{}, tt.args...); err == nil {
+				t.Fatal("expected error from failing stub")
+			}
+			if n := countLines(t, counter); n != tt.want {
+				t.Fatalf("expected %d invocations, got %d", tt.want, n)
+			}
+		})
+	}
+}
+
+func TestRunDetailed_RetryGetsFullTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell stub")
+	}
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "calls.txt")
+	// The first call drops the connection; the retry succeeds but takes
+	// most of the per-command budget.
+	script := "#!/bin/sh\n" +
+		"echo x >> '" + counter + "'\n" +
+		"if [ $(wc -l < '" + counter + "') -eq 1 ]; then echo 'read: Connection reset by peer' 1>&2; exit 1; fi\n" +
+		"sleep 0.3\n" +
+		"echo ok\n"
+	if err := os.WriteFile(filepath.Join(dir, "docker"), []byte(script), 0o755); err != nil {
+		t.Fatalf("write stub: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	oldDelay := retryBaseDelay
+	retryBaseDelay = 400 * time.Millisecond
+	defer func() { retryBaseDelay = oldDelay }()
+
+	// --timeout 500ms --retries 1: the backoff and the failed attempt must not
+	// count against the retry's own timeout.
+	c := (&Client{exec: SystemExec{}}).WithSettings(Settings{CommandTimeout: 500 * time.Millisecond, Retries: 1})
+	out, err := c.exec.Run(context.Background(), "volume", "ls")
+	if err != nil {
+		t.Fatalf("expected the retry to succeed within its own timeout, got %v", err)
+	}
+	if out != "ok\n" || countLines(t, counter) != 2 {
+		t.Fatalf("expected two attempts ending in ok, got %q after %d calls", out, countLines(t, counter))
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		args   []string
+		stderr string
+		want   bool
+	}{
+		{[]string{"volume", "create", "data"}, "kex_exchange_identification: read: Connection reset by peer", true},
+		{[]string{"volume", "create", "data"}, "Connection reset by peer", false},
+		{[]string{"compose", "-f", "a.yml", "up", "-d"}, "Connection closed by 10.0.0.1 port 22", false},
+		{[]string{"network", "inspect", "web"}, "error during connect: Get http://docker.example/v1.45/networks/web", true},
+		{[]string{"network", "inspect", "web"}, "Error: No such network: web", false},
+	}
+	for _, tt := range tests {
+		if got := retryable(tt.args, tt.stderr); got != tt.want {
+			t.Errorf("retryable(%q, %q) = %v, want %v", tt.args, tt.stderr, got, tt.want)
+		}
+	}
+}
+
+func TestRetryable_ClientCommands(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		call func(c *Client)
+		want bool
+	}{
+		{"InspectContainerLabels", func(c *Client) { _, _ = c.InspectContainerLabels(ctx, "web", nil) }, true},
+		{"InspectContainerImage", func(c *Client) { _, _ = c.InspectContainerImage(ctx, "web") }, true},
+		{"InspectMultipleContainerLabels", func(c *Client) { _, _ = c.InspectMultipleContainerLabels(ctx, []string{"web", "db"}, nil) }, true},
+		{"ListComposeContainersAll", func(c *Client) { _, _ = c.ListComposeContainersAll(ctx) }, true},
+		{"ServerVersion", func(c *Client) { _, _ = c.ServerVersion(ctx) }, true},
+		{"ContextHost", func(c *Client) { _, _ = c.ContextHost(ctx) }, true},
+		{"ImageInspectRepoDigests", func(c *Client) { _, _ = c.ImageInspectRepoDigests(ctx, "nginx") }, true},
+		{"ImageRepoDigestMap", func(c *Client) { _, _ = c.ImageRepoDigestMap(ctx, []string{"sha256:abc"}) }, true},
+		{"ImageID", func(c *Client) { _, _ = c.ImageID(ctx, "nginx") }, true},
+		{"ComposeContainerImageMap", func(c *Client) { _, _ = c.ComposeContainerImageMap(ctx) }, true},
+		{"ListVolumes", func(c *Client) { _, _ = c.ListVolumes(ctx) }, true},
+		{"InspectVolume", func(c *Client) { _, _ = c.InspectVolume(ctx, "data") }, true},
+		{"ListContainersUsingVolume", func(c *Client) { _, _ = c.ListContainersUsingVolume(ctx, "data") }, true},
+		{"VolumeSizes", func(c *Client) { _, _ = c.VolumeSizes(ctx) }, true},
+		{"ListNetworks", func(c *Client) { _, _ = c.ListNetworks(ctx) }, true},
+		{"InspectNetwork", func(c *Client) { _, _ = c.InspectNetwork(ctx, "web") }, true},
+		{"ContainerNetworkEndpoint", func(c *Client) { _, _ = c.ContainerNetworkEndpoint(ctx, "web", "front") }, true},
+		{"ListSecrets", func(c *Client) { _, _ = c.ListSecrets(ctx) }, true},
+		{"ComposeConfigRaw", func(c *Client) { _, _ = c.ComposeConfigRaw(ctx, "/x", []string{"compose.yml"}, nil, nil, nil) }, true},
+		{"CreateVolume", func(c *Client) { _ = c.CreateVolume(ctx, "data", nil) }, false},
+		{"RemoveContainer", func(c *Client) { _ = c.RemoveContainer(ctx, "web", true) }, false},
+		{"RestartContainer", func(c *Client) { _ = c.RestartContainer(ctx, "web") }, false},
+		{"DisconnectNetwork", func(c *Client) { _ = c.DisconnectNetwork(ctx, "front", "web") }, false},
+		{"ComposeUp", func(c *Client) { _, _ = c.ComposeUp(ctx, "/x", []string{"compose.yml"}, nil, nil, "demo", nil) }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &scriptExec{}
+			tt.call(&Client{exec: stub})
+			if len(stub.calls) == 0 {
+				t.Fatal("no docker command run")
+			}
+			args := stub.calls[0]
+			if got := retryable(args, "read: Connection reset by peer"); got != tt.want {
+				t.Fatalf("retryable(%q) = %v, want %v", args, got, tt.want)
+			}
+		})
+	}
+}
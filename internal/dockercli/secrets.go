@@ -0,0 +1,18 @@
+package dockercli
+
+import (
+	"context"
+
+	"github.com/gcstr/dockform/internal/util"
+)
+
+// ListSecrets returns names of docker (swarm) secrets. Secrets are not labeled by
+// dockform, so the list is not filtered by identifier. Fails when the daemon is
+// not a swarm manager.
+func (c *Client) ListSecrets(ctx context.Context) ([]string, error) {
+	out, err := c.exec.Run(ctx, "secret", "ls", "--format", "{{.Name}}")
+	if err != nil {
+		return nil, err
+	}
+	return util.SplitNonEmptyLines(out), nil
+}
@@ -0,0 +1,25 @@
+package dockercli
+
+import "context"
+
+// stackLabelsKey is a context key type used to pass a stack's labels to the
+// labeled compose project.
+type stackLabelsKey struct{}
+
+// WithStackLabels returns a context under which compose operations set labels
+// on every service of the project, next to the identifier label. The labels
+// are part of the labeled project, so they also feed the compose config hash:
+// changing them makes the services drift and compose recreates their
+// containers, as Docker cannot relabel a running container.
+func WithStackLabels(ctx context.Context, labels map[string]string) context.Context {
+	if len(labels) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, stackLabelsKey{}, labels)
+}
+
+// stackLabels returns the labels set by WithStackLabels, if any.
+func stackLabels(ctx context.Context) map[string]string {
+	labels, _ := ctx.Value(stackLabelsKey{}).(map[string]string)
+	return labels
+}
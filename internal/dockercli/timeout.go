@@ -0,0 +1,81 @@
+package dockercli
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+type pullingKey struct{}
+
+// pulling marks the commands run under ctx as ones that may pull images.
+func pulling(ctx context.Context) context.Context {
+	return context.WithValue(ctx, pullingKey{}, true)
+}
+
+// commandTimeout returns the timeout for a docker command run under ctx, or 0
+// when none is set. Pulling commands use the pull timeout when there is one.
+func (s SystemExec) commandTimeout(ctx context.Context) time.Duration {
+	if p, _ := ctx.Value(pullingKey{}).(bool); p && s.PullTimeout > 0 {
+		return s.PullTimeout
+	}
+	return max(s.CommandTimeout, 0)
+}
+
+// flagsWithValue are the flags whose value commandName skips to find the
+// subcommand: compose's global flags and the output flags of the read commands.
+var flagsWithValue = map[string]bool{
+	"-f": true, "--file": true, "-p": true, "--project-name": true,
+	"--profile": true, "--env-file": true, "--project-directory": true,
+	"--format": true, "--filter": true,
+}
+
+// subcommands are the verbs of the docker commands that take one. A word
+// after such a command only names the invocation when it is one of them, so
+// an argument such as a container name never ends up in the name.
+var subcommands = map[string]map[string]bool{
+	"container": {"ls": true, "inspect": true, "create": true, "start": true, "stop": true, "restart": true, "pause": true, "unpause": true, "kill": true, "rm": true, "exec": true, "logs": true, "prune": true},
+	"image":     {"ls": true, "inspect": true, "pull": true, "push": true, "tag": true, "rm": true, "prune": true},
+	"volume":    {"ls": true, "inspect": true, "create": true, "rm": true, "prune": true},
+	"network":   {"ls": true, "inspect": true, "create": true, "connect": true, "disconnect": true, "rm": true, "prune": true},
+	"context":   {"ls": true, "inspect": true, "show": true, "create": true, "use": true, "rm": true},
+	"system":    {"df": true, "info": true, "events": true, "prune": true},
+	"secret":    {"ls": true, "inspect": true, "create": true, "rm": true},
+	"manifest":  {"inspect": true, "create": true, "push": true},
+	"compose": {
+		"version": true, "config": true, "ps": true, "ls": true, "images": true, "top": true, "port": true, "logs": true, "events": true,
+		"up": true, "down": true, "create": true, "start": true, "stop": true, "restart": true, "pause": true, "unpause": true,
+		"kill": true, "rm": true, "pull": true, "push": true, "build": true, "run": true, "exec": true,
+	},
+}
+
+// commandName names a docker invocation for messages and retry decisions,
+// e.g. "volume ls", "compose up" or "inspect", without flags, their values or
+// the objects the command acts on.
+func commandName(args []string) string {
+	i, name := nextWord(args, 0)
+	verbs, ok := subcommands[name]
+	if !ok {
+		return name
+	}
+	if _, verb := nextWord(args, i+1); verbs[verb] {
+		return name + " " + verb
+	}
+	return name
+}
+
+// nextWord returns the first argument from args[i:] that is neither a flag
+// nor the value of one, and its index.
+func nextWord(args []string, i int) (int, string) {
+	for ; i < len(args); i++ {
+		a := args[i]
+		if flagsWithValue[a] {
+			i++
+			continue
+		}
+		if !strings.HasPrefix(a, "-") {
+			return i, a
+		}
+	}
+	return len(args), ""
+}
@@ -0,0 +1,93 @@
+package dockercli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+func TestRunDetailed_CommandTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell stub")
+	}
+	dir := t.TempDir()
+	script := "#!/bin/sh\nexec sleep 1\n"
+	if err := os.WriteFile(filepath.Join(dir, "docker"), []byte(script), 0o755); err != nil {
+		t.Fatalf("write stub: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	c := (&Client{exec: SystemExec{}}).WithSettings(Settings{CommandTimeout: 100 * time.Millisecond})
+	ctx := context.Background()
+	_, err := c.exec.Run(ctx, "volume", "ls", "--format", "{{.Name}}")
+	if !apperr.IsKind(err, apperr.External) || !strings.Contains(err.Error(), "operation timed out after 100ms: docker volume ls") {
+		t.Fatalf("expected a timeout error naming the command, got %v", err)
+	}
+
+	// Pulls get their own, larger budget.
+	c.WithSettings(Settings{CommandTimeout: 100 * time.Millisecond, PullTimeout: 5 * time.Second})
+	if _, err := c.ComposePull(ctx, dir, []string{"compose.yaml"}, nil, nil, "demo", nil, nil); err != nil {
+		t.Fatalf("expected the pull timeout to apply to compose pull, got %v", err)
+	}
+}
+
+func TestRunDetailed_CancelDuringBackoffReportsFinish(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell stub")
+	}
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "calls.txt")
+	writeCountingFailStub(t, dir, counter)
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	oldDelay := retryBaseDelay
+	retryBaseDelay = time.Minute
+	defer func() { retryBaseDelay = oldDelay }()
+
+	var trace strings.Builder
+	var finished bool
+	s := SystemExec{Tracer: NewTracer(&trace), CommandTimeout: time.Minute}
+	s.WithLogger(func(e ExecEvent) { finished = finished || e.Phase == "finish" })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err := s.RunDetailed(ctx, Options{}, "volume", "ls")
+	if !errors.Is(err, context.DeadlineExceeded) || !apperr.IsKind(err, apperr.External) {
+		t.Fatalf("expected an external error wrapping the cancelled run, got %v", err)
+	}
+	if strings.Contains(err.Error(), "operation timed out") {
+		t.Fatalf("the caller's deadline is not the command timeout, got %v", err)
+	}
+	if !finished || !strings.Contains(trace.String(), "trace: docker volume ls") {
+		t.Fatalf("expected the finish event and trace line, finished=%v trace=%q", finished, trace.String())
+	}
+	if n := countLines(t, counter); n != 1 {
+		t.Fatalf("expected one attempt before the backoff was cancelled, got %d", n)
+	}
+}
+
+func TestCommandName(t *testing.T) {
+	tests := map[string][]string{
+		"volume ls":       {"volume", "ls", "--format", "{{.Name}}"},
+		"compose up":      {"compose", "-f", "a.yml", "--profile", "web", "-p", "demo", "up", "-d"},
+		"compose pull":    {"compose", "--project-directory", "/x", "pull", "web"},
+		"version":         {"version", "--format", "{{.Server.Version}}"},
+		"inspect":         {"inspect", "-f", "{{json .Config.Labels}}", "web"},
+		"ps":              {"ps", "-a", "--format", "{{.Names}}"},
+		"image inspect":   {"image", "inspect", "--format", "{{json .RepoDigests}}", "nginx"},
+		"context inspect": {"context", "inspect", "remote", "--format", "{{json .Endpoints.docker.Host}}"},
+		"container rm":    {"container", "rm", "-f", "web"},
+		"network":         {"network", "--help"},
+	}
+	for want, args := range tests {
+		if got := commandName(args); got != want {
+			t.Errorf("commandName(%q) = %q, want %q", args, got, want)
+		}
+	}
+}
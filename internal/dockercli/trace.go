@@ -0,0 +1,89 @@
+package dockercli
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tracer writes one line per docker CLI invocation: the redacted command, the
+// daemon it targeted, its exit status and how long it took.
+type Tracer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewTracer returns a Tracer writing to w.
+func NewTracer(w io.Writer) *Tracer { return &Tracer{w: w} }
+
+// trace records a finished invocation. Environment values are never written,
+// and argument values that may carry secrets are redacted.
+func (t *Tracer) trace(s SystemExec, dir string, args []string, res Result, err error) {
+	if t == nil || t.w == nil {
+		return
+	}
+	var b strings.Builder
+	b.WriteString("trace: docker ")
+	b.WriteString(strings.Join(RedactArgs(args), " "))
+	switch {
+	case s.HostOverride != "":
+		fmt.Fprintf(&b, " [host=%s]", s.HostOverride)
+	case s.ContextName != "":
+		fmt.Fprintf(&b, " [context=%s]", s.ContextName)
+	}
+	if dir != "" {
+		fmt.Fprintf(&b, " [dir=%s]", dir)
+	}
+	status := fmt.Sprintf("exit %d", res.ExitCode)
+	if err != nil && res.ExitCode == 0 {
+		status = "error: " + err.Error()
+	}
+	fmt.Fprintf(&b, " → %s in %s\n", status, res.Duration.Round(time.Millisecond))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, _ = io.WriteString(t.w, b.String())
+}
+
+// sensitiveKey matches KEY=VALUE arguments whose value should not be shown.
+var sensitiveKey = regexp.MustCompile(`(?i)(pass|secret|token|key|credential|auth)`)
+
+// RedactArgs returns args with secret-looking values replaced by "***": the
+// values of -e/--env/--build-arg assignments, --password, and any KEY=VALUE
+// whose key looks sensitive.
+func RedactArgs(args []string) []string {
+	out := make([]string, len(args))
+	redactNext := ""
+	for i, a := range args {
+		out[i] = a
+		switch redactNext {
+		case "value":
+			if k, _, ok := strings.Cut(a, "="); ok {
+				out[i] = k + "=***"
+			}
+			redactNext = ""
+			continue
+		case "all":
+			out[i] = "***"
+			redactNext = ""
+			continue
+		}
+		switch {
+		case a == "-e" || a == "--env" || a == "--build-arg":
+			redactNext = "value"
+		case a == "--password":
+			redactNext = "all"
+		case strings.HasPrefix(a, "--password="):
+			out[i] = "--password=***"
+		case strings.HasPrefix(a, "-"):
+		default:
+			if k, _, ok := strings.Cut(a, "="); ok && sensitiveKey.MatchString(k) {
+				out[i] = k + "=***"
+			}
+		}
+	}
+	return out
+}
@@ -7,13 +7,23 @@ import (
 )
 
 type ComposeConfigDoc struct {
-	Services map[string]ComposeService `json:"services" yaml:"services"`
+	Name     string                             `json:"name" yaml:"name"` // resolved compose project name
+	Services map[string]ComposeService          `json:"services" yaml:"services"`
+	Volumes  map[string]ComposeTopLevelResource `json:"volumes" yaml:"volumes"`
+	Networks map[string]ComposeTopLevelResource `json:"networks" yaml:"networks"`
+}
+
+// ComposeTopLevelResource is a top-level compose volume or network. Name is the
+// actual docker name the logical key resolves to.
+type ComposeTopLevelResource struct {
+	Name string `json:"name" yaml:"name"`
 }
 
 type ComposePort struct {
 	Target    int         `json:"target" yaml:"target"`
 	Published interface{} `json:"published" yaml:"published"`
 	Protocol  string      `json:"protocol" yaml:"protocol"`
+	HostIP    string      `json:"host_ip" yaml:"host_ip"`
 }
 
 type ComposeService struct {
@@ -23,12 +33,25 @@ type ComposeService struct {
 	Networks      ComposeServiceNetworks `json:"networks" yaml:"networks"`
 	Volumes       []ComposeServiceVolume `json:"volumes" yaml:"volumes"`
 	Labels        map[string]string      `json:"labels" yaml:"labels"`
+	Profiles      []string               `json:"profiles" yaml:"profiles"`
+	Platform      string                 `json:"platform" yaml:"platform"`
+	Build         any                    `json:"build" yaml:"build"` // nil unless the service builds its image
+	StopSignal    string                 `json:"stop_signal" yaml:"stop_signal"`
+	StopGrace     string                 `json:"stop_grace_period" yaml:"stop_grace_period"`
+	DependsOn     ComposeDependsOn       `json:"depends_on" yaml:"depends_on"`
 }
 
 type ComposeServiceVolume struct {
-	Type   string `json:"type" yaml:"type"`
-	Source string `json:"source" yaml:"source"`
-	Target string `json:"target" yaml:"target"`
+	Type     string                `json:"type" yaml:"type"`
+	Source   string                `json:"source" yaml:"source"`
+	Target   string                `json:"target" yaml:"target"`
+	ReadOnly bool                  `json:"read_only" yaml:"read_only"`
+	Volume   *ComposeVolumeOptions `json:"volume,omitempty" yaml:"volume,omitempty"`
+}
+
+// ComposeVolumeOptions holds the volume-specific mount options of a service volume.
+type ComposeVolumeOptions struct {
+	Subpath string `json:"subpath" yaml:"subpath"`
 }
 
 type ComposeServiceNetworks []string
@@ -57,16 +80,121 @@ func (c *ComposeServiceNetworks) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("compose service networks: unexpected format: %s", string(data))
 }
 
+// ComposeDependsOn lists the services a service depends on, sorted. Compose
+// accepts both the short list form and the long map form; only the names are kept.
+type ComposeDependsOn []string
+
+func (d *ComposeDependsOn) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	return d.set(v)
+}
+
+func (d *ComposeDependsOn) UnmarshalYAML(unmarshal func(any) error) error {
+	var v any
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	return d.set(v)
+}
+
+func (d *ComposeDependsOn) set(v any) error {
+	var names []string
+	switch t := v.(type) {
+	case nil:
+	case []any:
+		for _, item := range t {
+			names = append(names, fmt.Sprint(item))
+		}
+	case map[string]any:
+		for name := range t {
+			names = append(names, name)
+		}
+	default:
+		return fmt.Errorf("compose depends_on: unexpected format: %v", v)
+	}
+	sort.Strings(names)
+	*d = names
+	return nil
+}
+
 // ComposePsItem is a subset of fields from `docker compose ps --format json`.
 type ComposePsItem struct {
+	ID         string             `json:"ID"`
 	Name       string             `json:"Name"`
 	Service    string             `json:"Service"`
 	Image      string             `json:"Image"`
 	State      string             `json:"State"`
+	ExitCode   int                `json:"ExitCode"`
+	Health     string             `json:"Health"`
 	Project    string             `json:"Project"`
 	Publishers []ComposePublisher `json:"Publishers"`
 }
 
+// ServiceStatus is the observed container state of a compose service after up.
+type ServiceStatus struct {
+	Service   string
+	Container string
+	State     string
+	ExitCode  int
+	Health    string // healthcheck status ("healthy", "unhealthy", "starting"), empty without one
+	HealthLog string // last line of the last health check's output, when fetched
+}
+
+// Failed reports whether the service's container did not come up: it is stopped
+// with a non-zero exit code, dead, stuck restarting or never started. A service
+// without any container (e.g. scaled to zero) is not considered failed.
+func (s ServiceStatus) Failed() bool {
+	switch s.State {
+	case "running", "missing", "":
+		return false
+	case "exited":
+		return s.ExitCode != 0
+	}
+	return true
+}
+
+// Healthy reports whether the service is running and, when it defines a
+// healthcheck, reported healthy.
+func (s ServiceStatus) Healthy() bool {
+	return s.State == "running" && (s.Health == "" || s.Health == "healthy")
+}
+
+// Completed reports whether the service ran to completion: it exited with
+// code 0, as one-shot services such as migrations do when they finish.
+func (s ServiceStatus) Completed() bool {
+	return s.State == "exited" && s.ExitCode == 0
+}
+
+// String renders the status as e.g. "web started" or "worker exited (code 1)".
+func (s ServiceStatus) String() string {
+	switch s.State {
+	case "running":
+		return s.Service + " started"
+	case "exited", "dead":
+		return fmt.Sprintf("%s %s (code %d)", s.Service, s.State, s.ExitCode)
+	case "missing":
+		return s.Service + " has no container"
+	}
+	return s.Service + " " + s.State
+}
+
+// HealthSummary says why the service is not ready or healthy, e.g.
+// "web unhealthy; last health check: connection refused", with the output of
+// its last health check when there is one.
+func (s ServiceStatus) HealthSummary() string {
+	summary := s.String()
+	if s.State == "running" && s.Health != "" {
+		summary = s.Service + " " + s.Health
+	}
+	if s.HealthLog != "" {
+		summary += "; last health check: " + s.HealthLog
+	}
+	return summary
+}
+
 type ComposePublisher struct {
 	URL           string `json:"URL"`
 	TargetPort    int    `json:"TargetPort"`
@@ -76,7 +204,8 @@ type ComposePublisher struct {
 
 // PsBrief represents a container with compose labels.
 type PsBrief struct {
-	Project string
-	Service string
-	Name    string
+	Project  string
+	Service  string
+	Name     string
+	Profiles []string // from the compose profiles label, when present
 }
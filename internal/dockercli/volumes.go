@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gcstr/dockform/internal/apperr"
 	"github.com/gcstr/dockform/internal/util"
@@ -34,6 +37,7 @@ type VolumeSummary struct {
 	Name       string
 	Driver     string
 	Mountpoint string
+	Size       string // disk usage as reported by docker, e.g. "1.2GB"; empty when unknown
 }
 
 func (c *Client) CreateVolume(ctx context.Context, name string, labels map[string]string) error {
@@ -160,6 +164,32 @@ func (c *Client) VolumeSummaries(ctx context.Context) ([]VolumeSummary, error) {
 	return summaries, nil
 }
 
+// VolumeSizes returns the disk usage of every volume by name, as reported by
+// `docker system df -v`, e.g. "1.2GB". Docker measures the volumes to answer,
+// which can take a while on large ones.
+func (c *Client) VolumeSizes(ctx context.Context) (map[string]string, error) {
+	out, err := c.exec.Run(ctx, "system", "df", "-v", "--format", "json")
+	if err != nil {
+		return nil, err
+	}
+	var usage struct {
+		Volumes []struct {
+			Name string `json:"Name"`
+			Size string `json:"Size"`
+		} `json:"Volumes"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(out)), &usage); err != nil {
+		return nil, apperr.Wrap("dockercli.VolumeSizes", apperr.Internal, err, "parse system df output")
+	}
+	sizes := make(map[string]string, len(usage.Volumes))
+	for _, v := range usage.Volumes {
+		if name := strings.TrimSpace(v.Name); name != "" {
+			sizes[name] = strings.TrimSpace(v.Size)
+		}
+	}
+	return sizes, nil
+}
+
 // StreamTarFromVolume streams a tar of the root of the volume to w.
 // The tar is created using numeric owners and includes xattrs/acls when supported.
 func (c *Client) StreamTarFromVolume(ctx context.Context, volumeName string, w io.Writer) error {
@@ -258,17 +288,42 @@ func (c *Client) ListRunningContainersUsingVolume(ctx context.Context, volumeNam
 	return util.SplitNonEmptyLines(out), nil
 }
 
-// StopContainers stops the given containers gracefully.
-func (c *Client) StopContainers(ctx context.Context, names []string) error {
+// StopOptions controls how StopContainers stops a container. Zero values
+// leave the choice to the container's own configuration.
+type StopOptions struct {
+	Signal  string        // signal sent to request the stop, e.g. SIGQUIT
+	Timeout time.Duration // grace period before the container is killed
+}
+
+// args returns the `docker container stop` flags for the options.
+func (o StopOptions) args() []string {
+	var args []string
+	if s := strings.TrimSpace(o.Signal); s != "" {
+		args = append(args, "--signal", s)
+	}
+	if o.Timeout > 0 {
+		args = append(args, "--time", strconv.Itoa(int(math.Ceil(o.Timeout.Seconds()))))
+	}
+	return args
+}
+
+// StopContainers stops the given containers gracefully. An optional
+// StopOptions sets the stop signal and grace period for all of them.
+func (c *Client) StopContainers(ctx context.Context, names []string, opts ...StopOptions) error {
 	if len(names) == 0 {
 		return nil
 	}
+	var flags []string
+	if len(opts) > 0 {
+		flags = opts[0].args()
+	}
 	// Stop sequentially for clearer error surfacing
 	for _, n := range names {
 		if strings.TrimSpace(n) == "" {
 			continue
 		}
-		if _, err := c.exec.Run(ctx, "container", "stop", n); err != nil {
+		args := append(append([]string{"container", "stop"}, flags...), n)
+		if _, err := c.exec.Run(ctx, args...); err != nil {
 			return err
 		}
 	}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"strings"
 	"testing"
+	"time"
 )
 
 type volExecStub struct{ lastArgs []string }
@@ -15,6 +16,9 @@ func (v *volExecStub) Run(ctx context.Context, args ...string) (string, error) {
 	if len(args) >= 2 && args[0] == "volume" && args[1] == "ls" {
 		return "vol1\n\nvol2\n", nil
 	}
+	if len(args) >= 2 && args[0] == "system" && args[1] == "df" {
+		return `{"Images":[],"Volumes":[{"Name":"vol1","Driver":"local","Size":"1.2GB"},{"Name":"vol2","Size":"0B"}]}`, nil
+	}
 	return "", nil
 }
 func (v *volExecStub) RunInDir(ctx context.Context, dir string, args ...string) (string, error) {
@@ -262,3 +266,35 @@ func TestRemoveVolume_Args(t *testing.T) {
 		t.Fatalf("unexpected args: %#v", stub.lastArgs)
 	}
 }
+
+func TestStopContainers_PassesSignalAndTimeout(t *testing.T) {
+	stub := &scriptExec{}
+	c := &Client{exec: stub}
+	if err := c.StopContainers(context.Background(), []string{"web-1"}, StopOptions{Signal: "SIGQUIT", Timeout: 1500 * time.Millisecond}); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if got := strings.Join(stub.lastArgs, " "); got != "container stop --signal SIGQUIT --time 2 web-1" {
+		t.Fatalf("unexpected args: %s", got)
+	}
+	if err := c.StopContainers(context.Background(), []string{"web-1"}); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if got := strings.Join(stub.lastArgs, " "); got != "container stop web-1" {
+		t.Fatalf("expected default stop without options, got: %s", got)
+	}
+}
+
+func TestVolumeSizes_ParsesSystemDf(t *testing.T) {
+	stub := &volExecStub{}
+	c := &Client{exec: stub}
+	sizes, err := c.VolumeSizes(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !containsArgSeq(stub.lastArgs, []string{"system", "df", "-v"}) {
+		t.Fatalf("expected system df -v, got %#v", stub.lastArgs)
+	}
+	if sizes["vol1"] != "1.2GB" || sizes["vol2"] != "0B" || len(sizes) != 2 {
+		t.Fatalf("unexpected sizes: %#v", sizes)
+	}
+}
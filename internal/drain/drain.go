@@ -0,0 +1,83 @@
+// Package drain implements the first phase of a two-phase shutdown: an
+// interrupt asks long-running operations to stop at their next safe point
+// instead of cancelling them mid-write. A second interrupt is expected to
+// cancel the context outright.
+package drain
+
+import (
+	"context"
+	"sync"
+)
+
+// Controller tracks whether a graceful stop was requested and whether any
+// operation able to honor it is running. The zero value is not usable; use
+// New. All methods are safe on a nil Controller, which never drains.
+type Controller struct {
+	mu       sync.Mutex
+	active   int
+	draining bool
+}
+
+// New returns a Controller with no drain requested.
+func New() *Controller { return &Controller{} }
+
+type ctxKey struct{}
+
+// WithController attaches c to ctx.
+func WithController(ctx context.Context, c *Controller) context.Context {
+	return context.WithValue(ctx, ctxKey{}, c)
+}
+
+// FromContext returns the Controller attached to ctx, or nil.
+func FromContext(ctx context.Context) *Controller {
+	c, _ := ctx.Value(ctxKey{}).(*Controller)
+	return c
+}
+
+// Begin marks the start of an operation that checks Draining between its
+// atomic units. The returned func marks its end.
+func (c *Controller) Begin() (end func()) {
+	if c == nil {
+		return func() {}
+	}
+	c.mu.Lock()
+	c.active++
+	c.mu.Unlock()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.mu.Lock()
+			c.active--
+			c.mu.Unlock()
+		})
+	}
+}
+
+// Request asks running operations to stop at their next safe point. It
+// reports whether any operation started with Begin is running; when none is,
+// nothing will honor the request and the caller should cancel instead.
+func (c *Controller) Request() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.draining = true
+	return c.active > 0
+}
+
+// Draining reports whether a graceful stop was requested.
+func (c *Controller) Draining() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.draining
+}
+
+// Requested reports whether a graceful stop was requested on the Controller
+// attached to ctx.
+func Requested(ctx context.Context) bool {
+	return FromContext(ctx).Draining()
+}
@@ -0,0 +1,50 @@
+package drain
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRequestReportsActiveOperations(t *testing.T) {
+	c := New()
+	if c.Request() {
+		t.Fatalf("expected no active operation to honor the request")
+	}
+	if !c.Draining() {
+		t.Fatalf("expected draining after Request")
+	}
+
+	c = New()
+	end := c.Begin()
+	if !c.Request() {
+		t.Fatalf("expected the active operation to honor the request")
+	}
+	end()
+	end()
+	if c.Request() {
+		t.Fatalf("expected no active operation after end")
+	}
+}
+
+func TestRequestedFromContext(t *testing.T) {
+	if Requested(context.Background()) {
+		t.Fatalf("expected no drain without a controller")
+	}
+	c := New()
+	ctx := WithController(context.Background(), c)
+	if Requested(ctx) {
+		t.Fatalf("expected no drain before Request")
+	}
+	c.Request()
+	if !Requested(ctx) {
+		t.Fatalf("expected drain after Request")
+	}
+}
+
+func TestNilControllerNeverDrains(t *testing.T) {
+	var c *Controller
+	c.Begin()()
+	if c.Request() || c.Draining() {
+		t.Fatalf("expected nil controller to never drain")
+	}
+}
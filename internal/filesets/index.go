@@ -3,6 +3,7 @@ package filesets
 import (
 	"encoding/json"
 	"io/fs"
+	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/bmatcuk/doublestar/v4"
+	"github.com/gcstr/dockform/internal/apperr"
 	"github.com/gcstr/dockform/internal/util"
 )
 
@@ -19,6 +21,9 @@ type FileEntry struct {
 	Path   string `json:"path"`
 	Size   int64  `json:"size"`
 	Sha256 string `json:"sha256"`
+	// ModTime is the source file's modification time in Unix nanoseconds, used
+	// by BuildFastIndex to skip rehashing; zero for rendered files.
+	ModTime int64 `json:"mtime,omitempty"`
 }
 
 type Index struct {
@@ -32,7 +37,38 @@ type Index struct {
 	TreeHash  string      `json:"tree_hash"`
 }
 
+// hashFile returns the hex SHA-256 of a source file; a var so tests can
+// observe which files are read.
+var hashFile = util.Sha256FileHex
+
 func BuildLocalIndex(sourceDir string, targetPath string, excludes []string) (Index, error) {
+	return buildIndex(sourceDir, targetPath, excludes, nil, nil)
+}
+
+// BuildRenderedIndex is BuildLocalIndex for a templated fileset: sizes and
+// hashes describe each file as rendered by r, so drift follows the rendered
+// output. A nil r indexes the files as they are on disk.
+func BuildRenderedIndex(sourceDir string, targetPath string, excludes []string, r *Renderer) (Index, error) {
+	return buildIndex(sourceDir, targetPath, excludes, r, nil)
+}
+
+// BuildFastIndex is BuildLocalIndex that trusts prev, normally the index last
+// written to the volume: a file whose size and modification time match its
+// entry there keeps that entry's hash instead of being read again. A file
+// changed without touching either goes unnoticed, which is why it is opt-in.
+func BuildFastIndex(sourceDir string, targetPath string, excludes []string, prev Index) (Index, error) {
+	known := make(map[string]FileEntry, len(prev.Files))
+	for _, f := range prev.Files {
+		if f.ModTime != 0 && f.Sha256 != "" {
+			known[f.Path] = f
+		}
+	}
+	return buildIndex(sourceDir, targetPath, excludes, nil, known)
+}
+
+// buildIndex indexes sourceDir, rendering files through r when it is set and
+// reusing the entries in known whose size and modification time still match.
+func buildIndex(sourceDir string, targetPath string, excludes []string, r *Renderer, known map[string]FileEntry) (Index, error) {
 	i := Index{
 		Version:   "v1",
 		Target:    targetPath,
@@ -41,13 +77,67 @@ func BuildLocalIndex(sourceDir string, targetPath string, excludes []string) (In
 		UID:       0,
 		GID:       0,
 	}
+
+	// Persist effective excludes into the index
+	i.Exclude = append(i.Exclude, normalizeExcludePatterns(excludes)...)
+	files := []FileEntry{}
+
+	err := walkSource(sourceDir, excludes, func(p, relSlash string, info fs.FileInfo) error {
+		if r != nil {
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			rendered, err := r.Render(relSlash, data)
+			if err != nil {
+				return err
+			}
+			files = append(files, FileEntry{Path: relSlash, Size: int64(len(rendered)), Sha256: util.Sha256StringHex(string(rendered))})
+			return nil
+		}
+		mtime := info.ModTime().UnixNano()
+		if prev, ok := known[relSlash]; ok && prev.Size == info.Size() && prev.ModTime == mtime {
+			files = append(files, prev)
+			return nil
+		}
+		sum, err := hashFile(p)
+		if err != nil {
+			return err
+		}
+		files = append(files, FileEntry{Path: relSlash, Size: info.Size(), Sha256: sum, ModTime: mtime})
+		return nil
+	})
+	if err != nil {
+		return Index{}, err
+	}
+	sort.Slice(files, func(i0, j int) bool { return files[i0].Path < files[j].Path })
+	i.Files = files
+	// Build tree hash: path + "\x00" + size + "\x00" + sha256 + "\n"
+	var b strings.Builder
+	for _, f := range files {
+		b.WriteString(f.Path)
+		b.WriteByte('\x00')
+		b.WriteString(strconv.FormatInt(f.Size, 10))
+		b.WriteByte('\x00')
+		b.WriteString(f.Sha256)
+		b.WriteByte('\n')
+	}
+	i.TreeHash = util.Sha256StringHex(b.String())
+	return i, nil
+}
+
+// walkSource calls fn for every regular, non-excluded file under sourceDir with
+// its absolute and slash-separated relative path. Symlinks are ignored.
+func walkSource(sourceDir string, excludes []string, fn func(p, relSlash string, info fs.FileInfo) error) error {
 	src := filepath.Clean(sourceDir)
 
 	// Normalize and freeze exclude patterns for determinism
 	normEx := normalizeExcludePatterns(excludes)
-	// Persist effective excludes into the index
-	i.Exclude = append(i.Exclude, normEx...)
-	files := []FileEntry{}
+	for _, pat := range normEx {
+		if !doublestar.ValidatePattern(pat) {
+			return apperr.New("filesets.walkSource", apperr.InvalidInput, "invalid exclude pattern %q", pat)
+		}
+	}
 
 	// Exclude matcher using doublestar against slash-normalized relative paths
 	isExcluded := func(relSlash string, isDir bool) bool {
@@ -61,7 +151,7 @@ func BuildLocalIndex(sourceDir string, targetPath string, excludes []string) (In
 		return false
 	}
 
-	err := filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -98,35 +188,18 @@ func BuildLocalIndex(sourceDir string, targetPath string, excludes []string) (In
 		if isExcluded(relSlash, false) {
 			return nil
 		}
-		sum, err := util.Sha256FileHex(p)
-		if err != nil {
-			return err
-		}
-		files = append(files, FileEntry{Path: relSlash, Size: info.Size(), Sha256: sum})
-		return nil
+		return fn(p, relSlash, info)
 	})
-	if err != nil {
-		return Index{}, err
-	}
-	sort.Slice(files, func(i0, j int) bool { return files[i0].Path < files[j].Path })
-	i.Files = files
-	// Build tree hash: path + "\x00" + size + "\x00" + sha256 + "\n"
-	var b strings.Builder
-	for _, f := range files {
-		b.WriteString(f.Path)
-		b.WriteByte('\x00')
-		b.WriteString(strconv.FormatInt(f.Size, 10))
-		b.WriteByte('\x00')
-		b.WriteString(f.Sha256)
-		b.WriteByte('\n')
-	}
-	i.TreeHash = util.Sha256StringHex(b.String())
-	return i, nil
 }
 
-// normalizeExcludePatterns returns a deterministic slice of patterns normalized to gitignore-like semantics:
+// normalizeExcludePatterns returns a deterministic slice of patterns normalized to gitignore-like semantics.
+// Patterns are doublestar globs matched against each path relative to the source directory, so
+// "logs/**" excludes the logs directory and everything below it and "**/*.tmp" excludes .tmp files
+// at any depth, including the top level. Normalization:
 // - trim spaces and skip empty
 // - convert OS-specific separators to forward slashes
+// - strip leading "/" and "./": patterns are always anchored at the source directory
+// - skip patterns naming the source directory itself ("/", "."), which is never excluded
 // - if a pattern ends with '/', expand to pattern + "**" to exclude dir and all contents
 // - ensure order is stable by sorting unique patterns
 func normalizeExcludePatterns(in []string) []string {
@@ -142,6 +215,17 @@ func normalizeExcludePatterns(in []string) []string {
 		}
 		// convert to slash-normalized pattern
 		p = filepath.ToSlash(p)
+		// anchor at the source directory
+		for {
+			trimmed := strings.TrimPrefix(strings.TrimPrefix(p, "/"), "./")
+			if trimmed == p {
+				break
+			}
+			p = trimmed
+		}
+		if p == "" || p == "." {
+			continue
+		}
 		// directory pattern ending with '/'
 		if strings.HasSuffix(p, "/") {
 			p = p + "**"
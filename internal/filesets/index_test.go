@@ -236,3 +236,148 @@ func TestBuildLocalIndex_DeterministicTwice(t *testing.T) {
 		t.Fatalf("files not sorted: %+v", i1.Files)
 	}
 }
+
+func TestBuildRenderedIndex_HashesRenderedContent(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.conf"), []byte("host={{ .HOST }}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := BuildRenderedIndex(dir, "/etc", nil, NewRenderer([]string{"HOST=a.example"}))
+	if err != nil {
+		t.Fatalf("index: %v", err)
+	}
+	b, err := BuildRenderedIndex(dir, "/etc", nil, NewRenderer([]string{"HOST=b.example"}))
+	if err != nil {
+		t.Fatalf("index: %v", err)
+	}
+	if a.TreeHash == b.TreeHash {
+		t.Fatalf("expected different variables to change the tree hash")
+	}
+	if a.Files[0].Size != int64(len("host=a.example\n")) {
+		t.Fatalf("expected rendered size, got %d", a.Files[0].Size)
+	}
+
+	if _, err := BuildRenderedIndex(dir, "/etc", nil, NewRenderer(nil)); err == nil {
+		t.Fatalf("expected an undefined variable to fail rendering")
+	}
+}
+
+func TestCheckTemplates(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ok.conf"), []byte("{{ .HOST }}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "blob.bin"), []byte("{{ broken\x00"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckTemplates(dir, nil); err != nil {
+		t.Fatalf("expected binary files to be skipped, got: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "bad.conf"), []byte("{{ .HOST "), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckTemplates(dir, nil); err == nil || !strings.Contains(err.Error(), "bad.conf") {
+		t.Fatalf("expected a parse error naming bad.conf, got: %v", err)
+	}
+	if err := CheckTemplates(dir, []string{"bad.conf"}); err != nil {
+		t.Fatalf("expected excluded files to be skipped, got: %v", err)
+	}
+}
+
+func TestBuildLocalIndex_ExcludeGlobEdgeCases(t *testing.T) {
+	dir := t.TempDir()
+	for _, p := range []string{
+		"keep.txt", "a.tmp", "deep/x/b.tmp", "logs/app.log", "logs/old/app.log",
+		"sub/logs/kept.log", "root.txt", "sub/root.txt",
+	} {
+		full := filepath.Join(dir, p)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(p), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	paths := func(excludes ...string) []string {
+		t.Helper()
+		i, err := BuildLocalIndex(dir, "/t", excludes)
+		if err != nil {
+			t.Fatalf("build %v: %v", excludes, err)
+		}
+		var out []string
+		for _, f := range i.Files {
+			out = append(out, f.Path)
+		}
+		return out
+	}
+
+	// ** recurses at any depth, including the top level; logs/** is anchored.
+	got := paths("logs/**", "**/*.tmp")
+	want := []string{"keep.txt", "root.txt", "sub/logs/kept.log", "sub/root.txt"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("paths=%v want=%v", got, want)
+	}
+
+	// A leading slash or ./ anchors at the source directory like no prefix.
+	got = paths("/root.txt", "./logs/")
+	want = []string{"a.tmp", "deep/x/b.tmp", "keep.txt", "sub/logs/kept.log", "sub/root.txt"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("paths=%v want=%v", got, want)
+	}
+
+	// Patterns naming the source directory itself exclude nothing.
+	if got := paths("/", ".", "./"); len(got) != 8 {
+		t.Fatalf("expected every file kept, got %v", got)
+	}
+
+	if _, err := BuildLocalIndex(dir, "/t", []string{"logs/[a-"}); err == nil {
+		t.Fatalf("expected an error for an invalid pattern")
+	}
+}
+
+func TestBuildFastIndex_SkipsReadingUnchangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	large := filepath.Join(dir, "media.bin")
+	if err := os.WriteFile(large, make([]byte, 4<<20), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("v1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	prev, err := BuildLocalIndex(dir, "/data", nil)
+	if err != nil {
+		t.Fatalf("index: %v", err)
+	}
+
+	// Change notes.txt in size; media.bin keeps its size and mtime.
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("v22"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var read []string
+	old := hashFile
+	hashFile = func(p string) (string, error) {
+		read = append(read, filepath.Base(p))
+		return old(p)
+	}
+	t.Cleanup(func() { hashFile = old })
+
+	fast, err := BuildFastIndex(dir, "/data", nil, prev)
+	if err != nil {
+		t.Fatalf("fast index: %v", err)
+	}
+	if strings.Join(read, ",") != "notes.txt" {
+		t.Fatalf("expected only notes.txt to be read, read %v", read)
+	}
+	read = nil
+	strict, err := BuildLocalIndex(dir, "/data", nil)
+	if err != nil {
+		t.Fatalf("strict index: %v", err)
+	}
+	if len(read) != 2 {
+		t.Fatalf("expected strict mode to read both files, read %v", read)
+	}
+	if fast.TreeHash != strict.TreeHash {
+		t.Fatalf("fast and strict tree hashes differ: %s vs %s", fast.TreeHash, strict.TreeHash)
+	}
+}
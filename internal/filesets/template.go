@@ -0,0 +1,82 @@
+package filesets
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+// Renderer renders the files of a templated fileset as Go templates, with the
+// stack's resolved environment as data ({{ .HOSTNAME }}). Binary files are
+// passed through unchanged. A nil *Renderer renders nothing.
+type Renderer struct {
+	Vars map[string]string
+}
+
+// NewRenderer returns a Renderer over key=value pairs; later pairs win.
+func NewRenderer(env []string) *Renderer {
+	vars := make(map[string]string, len(env))
+	for _, kv := range env {
+		k, v, ok := bytes.Cut([]byte(kv), []byte("="))
+		if ok && len(k) > 0 {
+			vars[string(k)] = string(v)
+		}
+	}
+	return &Renderer{Vars: vars}
+}
+
+// Render returns the rendered content of the file at rel. Referencing a
+// variable the environment does not define is an error.
+func (r *Renderer) Render(rel string, data []byte) ([]byte, error) {
+	if r == nil || isBinary(data) {
+		return data, nil
+	}
+	tmpl, err := parseTemplate(rel, data)
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, r.Vars); err != nil {
+		return nil, apperr.Wrap("filesets.Render", apperr.InvalidInput, err, "render template %s", rel)
+	}
+	return out.Bytes(), nil
+}
+
+// CheckTemplates parses every non-binary, non-excluded file under sourceDir as
+// a Go template, without rendering it.
+func CheckTemplates(sourceDir string, excludes []string) error {
+	return walkSource(sourceDir, excludes, func(p, relSlash string, _ fs.FileInfo) error {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if isBinary(data) {
+			return nil
+		}
+		_, err = parseTemplate(relSlash, data)
+		return err
+	})
+}
+
+func parseTemplate(rel string, data []byte) (*template.Template, error) {
+	tmpl, err := template.New(filepath.Base(rel)).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, apperr.Wrap("filesets.parseTemplate", apperr.InvalidInput, err, "parse template %s", rel)
+	}
+	return tmpl, nil
+}
+
+// isBinary reports whether data looks like a binary file: it holds a NUL byte
+// in its first 8000 bytes (the heuristic git uses) or is not valid UTF-8.
+func isBinary(data []byte) bool {
+	head := data
+	if len(head) > 8000 {
+		head = head[:8000]
+	}
+	return bytes.IndexByte(head, 0) >= 0 || !utf8.Valid(data)
+}
@@ -0,0 +1,36 @@
+package manifest
+
+import (
+	"sort"
+
+	"github.com/goccy/go-yaml"
+)
+
+// renderComposeOverride renders the compose override document layered on top
+// of a stack's files when compose runs (see Stack.ComposeOverride): ignored
+// services are moved behind IgnoredServicesProfile and healthcheck overrides
+// are set. It returns nil when the stack needs no override. Output is
+// deterministic so the compose config hash only changes when the settings do.
+func renderComposeOverride(stack Stack) ([]byte, error) {
+	specs := map[string]yaml.MapSlice{}
+	for _, svc := range stack.IgnoreServices {
+		specs[svc] = append(specs[svc], yaml.MapItem{Key: "profiles", Value: []string{IgnoredServicesProfile}})
+	}
+	for svc, hc := range stack.Healthchecks {
+		specs[svc] = append(specs[svc], yaml.MapItem{Key: "healthcheck", Value: healthcheckSpec(hc)})
+	}
+	if len(specs) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(specs))
+	for svc := range specs {
+		names = append(names, svc)
+	}
+	sort.Strings(names)
+	services := yaml.MapSlice{}
+	for _, svc := range names {
+		services = append(services, yaml.MapItem{Key: svc, Value: specs[svc]})
+	}
+	return yaml.Marshal(yaml.MapSlice{{Key: "services", Value: services}})
+}
@@ -0,0 +1,97 @@
+package manifest
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+// StackDefaults holds settings applied to every stack, so homogeneous
+// deployments don't repeat them per stack. A stack's own value always wins.
+type StackDefaults struct {
+	Profiles    []string          `yaml:"profiles"`    // Replaced by the stack's profiles when it sets any
+	EnvFile     []string          `yaml:"env-file"`    // Prepended to the stack's env files; relative to the manifest
+	Environment *Environment      `yaml:"environment"` // Inline vars prepended (stack wins per key); resolve only for stacks without an environment block
+	Project     *Project          `yaml:"project"`     // Used when the stack sets no project
+	Labels      map[string]string `yaml:"labels"`      // Merged into every stack's labels (stack wins per key)
+}
+
+// applyStackDefaults merges d into stack. Env files and inline variables are
+// prepended so the stack's entries take precedence (compose reads env files
+// in order, and inline variables are deduplicated last-wins); labels are merged
+// with the stack's winning per key; profiles and the project name are replaced
+// wholesale by the stack's own value.
+func applyStackDefaults(stack Stack, d StackDefaults, baseDir string) Stack {
+	if len(stack.Profiles) == 0 && len(d.Profiles) > 0 {
+		stack.Profiles = append([]string(nil), d.Profiles...)
+	}
+	if len(d.EnvFile) > 0 {
+		files := make([]string, 0, len(d.EnvFile)+len(stack.EnvFile))
+		for _, f := range d.EnvFile {
+			files = append(files, resolveDefaultPath(baseDir, f))
+		}
+		stack.EnvFile = append(files, stack.EnvFile...)
+	}
+	if d.Environment != nil {
+		env := Environment{}
+		if stack.Environment != nil {
+			env = *stack.Environment
+		}
+		if len(d.Environment.Inline) > 0 {
+			env.Inline = append(append([]string(nil), d.Environment.Inline...), env.Inline...)
+		}
+		if stack.Environment == nil {
+			env.Resolve = d.Environment.Resolve
+		}
+		stack.Environment = &env
+	}
+	if stack.Project == nil && d.Project != nil && d.Project.Name != "" {
+		p := *d.Project
+		stack.Project = &p
+	}
+	if len(d.Labels) > 0 {
+		labels := make(map[string]string, len(d.Labels)+len(stack.Labels))
+		for k, v := range d.Labels {
+			labels[k] = v
+		}
+		for k, v := range stack.Labels {
+			labels[k] = v
+		}
+		stack.Labels = labels
+	}
+	return stack
+}
+
+// resolveDefaultPath anchors a defaults path at the manifest directory, since
+// the defaults block is not tied to any stack root.
+func resolveDefaultPath(baseDir, p string) string {
+	if p == "" || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Clean(filepath.Join(baseDir, p))
+}
+
+// validateProjectNames rejects stacks of one context that end up with the same
+// compose project name. It guards defaults.project, which would otherwise put
+// every stack that does not override it into a single compose project.
+func validateProjectNames(stacks map[string]Stack) error {
+	seen := map[string]string{}
+	keys := make([]string, 0, len(stacks))
+	for k := range stacks {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		stack := stacks[key]
+		if stack.Project == nil || stack.Project.Name == "" {
+			continue
+		}
+		id := stack.Context + "/" + stack.Project.Name
+		if other, ok := seen[id]; ok {
+			return apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "stacks %s and %s share compose project name %q; set project.name per stack", other, key, stack.Project.Name)
+		}
+		seen[id] = key
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+func TestLoad_DefaultsMergedIntoStacks(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "dockform.yml")
+	content := `identifier: myapp
+contexts:
+  default: {}
+defaults:
+  profiles: [prod]
+  env-file: [shared.env]
+  environment:
+    inline: [TZ=UTC, LOG_LEVEL=info]
+stacks:
+  default/web:
+    root: web
+    env-file: [web.env]
+  default/api:
+    root: api
+    profiles: [debug]
+    environment:
+      inline: [LOG_LEVEL=debug]
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	shared := filepath.Join(dir, "shared.env")
+
+	web := cfg.Stacks["default/web"]
+	if !reflect.DeepEqual(web.Profiles, []string{"prod"}) {
+		t.Fatalf("expected default profiles on web, got %v", web.Profiles)
+	}
+	if !reflect.DeepEqual(web.EnvFile, []string{shared, "web.env"}) {
+		t.Fatalf("expected default env file before the stack's, got %v", web.EnvFile)
+	}
+	if !reflect.DeepEqual(web.EnvInline, []string{"TZ=UTC", "LOG_LEVEL=info"}) {
+		t.Fatalf("expected default inline env on web, got %v", web.EnvInline)
+	}
+
+	api := cfg.Stacks["default/api"]
+	if !reflect.DeepEqual(api.Profiles, []string{"debug"}) {
+		t.Fatalf("stack profiles must replace defaults, got %v", api.Profiles)
+	}
+	if !reflect.DeepEqual(api.EnvInline, []string{"TZ=UTC", "LOG_LEVEL=debug"}) {
+		t.Fatalf("stack inline env must win per key, got %v", api.EnvInline)
+	}
+}
+
+func TestApplyStackDefaults_ProjectOverriddenByStack(t *testing.T) {
+	d := StackDefaults{Project: &Project{Name: "shared"}}
+	got := applyStackDefaults(Stack{Project: &Project{Name: "own"}}, d, "/base")
+	if got.Project.Name != "own" {
+		t.Fatalf("stack project must win, got %q", got.Project.Name)
+	}
+	got = applyStackDefaults(Stack{}, d, "/base")
+	if got.Project == nil || got.Project.Name != "shared" {
+		t.Fatalf("expected default project, got %#v", got.Project)
+	}
+	got.Project.Name = "mutated"
+	if d.Project.Name != "shared" {
+		t.Fatalf("defaults must not be shared with stacks")
+	}
+}
+
+func TestNormalize_DefaultProjectSharedByStacksRejected(t *testing.T) {
+	cfg := Config{
+		Identifier: "test",
+		Contexts:   map[string]ContextConfig{"default": {}},
+		Defaults:   StackDefaults{Project: &Project{Name: "shared"}},
+		Stacks: map[string]Stack{
+			"default/web": {Root: "web"},
+			"default/api": {Root: "api"},
+			"default/db":  {Root: "db", Project: &Project{Name: "db"}},
+		},
+	}
+	err := cfg.normalizeAndValidate(t.TempDir())
+	if err == nil || !apperr.IsKind(err, apperr.InvalidInput) || !strings.Contains(err.Error(), `share compose project name "shared"`) {
+		t.Fatalf("expected shared project rejection, got %v", err)
+	}
+
+	delete(cfg.Stacks, "default/api")
+	if err := cfg.normalizeAndValidate(t.TempDir()); err != nil {
+		t.Fatalf("single stack using the default project should be valid: %v", err)
+	}
+}
+
+func TestLoad_DefaultsMergedIntoDiscoveredStackWithOverlay(t *testing.T) {
+	dir := t.TempDir()
+	mustMkdir(t, filepath.Join(dir, "default", "web"))
+	mustWriteFile(t, filepath.Join(dir, "default", "web", "compose.yaml"), "services:\n  nginx: {}\n")
+	path := filepath.Join(dir, "dockform.yml")
+	content := `identifier: myapp
+contexts:
+  default: {}
+defaults:
+  labels:
+    team: x
+  environment:
+    inline: [TZ=UTC]
+    resolve: true
+stacks:
+  default/web:
+    labels:
+      app: y
+    environment:
+      inline: [LOG_LEVEL=debug]
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	web, ok := cfg.GetAllStacks()["default/web"]
+	if !ok {
+		t.Fatalf("expected discovered stack default/web")
+	}
+	if !reflect.DeepEqual(web.Labels, map[string]string{"team": "x", "app": "y"}) {
+		t.Fatalf("expected default and overlay labels, got %v", web.Labels)
+	}
+	if !reflect.DeepEqual(web.EnvInline, []string{"TZ=UTC", "LOG_LEVEL=debug"}) {
+		t.Fatalf("expected default and overlay inline env, got %v", web.EnvInline)
+	}
+	if web.Environment == nil || !reflect.DeepEqual(web.Environment.Inline, []string{"TZ=UTC", "LOG_LEVEL=debug"}) {
+		t.Fatalf("expected the merged environment block, got %+v", web.Environment)
+	}
+}
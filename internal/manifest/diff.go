@@ -0,0 +1,188 @@
+package manifest
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/goccy/go-yaml"
+)
+
+// DiffKind is how a setting differs between two manifests.
+type DiffKind string
+
+const (
+	DiffAdded   DiffKind = "added"
+	DiffRemoved DiffKind = "removed"
+	DiffChanged DiffKind = "changed"
+)
+
+// DiffEntry is one setting that differs between two manifests. Path is dotted,
+// e.g. "stacks.default/web.profiles[0]"; Old and New are display values with
+// secret-looking values masked.
+type DiffEntry struct {
+	Path string
+	Kind DiffKind
+	Old  string
+	New  string
+}
+
+// maskedValue replaces secret-looking values in diff output.
+const maskedValue = "********"
+
+// secretNamePattern matches setting and variable names whose values are masked.
+var secretNamePattern = regexp.MustCompile(`(?i)(password|passwd|passphrase|secret|token|api_?key|private_?key|credential)`)
+
+// Diff compares two loaded manifests after normalization and discovery. Both
+// are flattened into settings covering contexts, deployments, defaults, the
+// merged stacks and filesets; absolute paths are shown relative to each
+// manifest's directory so manifests living in different checkouts compare
+// equal when they describe the same layout.
+func Diff(a, b Config) ([]DiffEntry, error) {
+	left, err := flattenConfig(a)
+	if err != nil {
+		return nil, err
+	}
+	right, err := flattenConfig(b)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := map[string]struct{}{}
+	for p := range left {
+		paths[p] = struct{}{}
+	}
+	for p := range right {
+		paths[p] = struct{}{}
+	}
+	sorted := make([]string, 0, len(paths))
+	for p := range paths {
+		sorted = append(sorted, p)
+	}
+	sort.Strings(sorted)
+
+	var out []DiffEntry
+	for _, p := range sorted {
+		l, inLeft := left[p]
+		r, inRight := right[p]
+		switch {
+		case !inLeft && !(r == "{}" && hasChildren(left, p)):
+			out = append(out, DiffEntry{Path: p, Kind: DiffAdded, New: displayValue(p, r)})
+		case !inRight && !(l == "{}" && hasChildren(right, p)):
+			out = append(out, DiffEntry{Path: p, Kind: DiffRemoved, Old: displayValue(p, l)})
+		case inLeft && inRight && l != r:
+			out = append(out, DiffEntry{Path: p, Kind: DiffChanged, Old: displayValue(p, l), New: displayValue(p, r)})
+		}
+	}
+	return out, nil
+}
+
+// flattenConfig renders the effective settings of cfg as path -> value.
+func flattenConfig(cfg Config) (map[string]string, error) {
+	view := map[string]any{
+		"identifier":  cfg.Identifier,
+		"sops":        cfg.Sops,
+		"discovery":   cfg.Discovery,
+		"contexts":    cfg.Contexts,
+		"deployments": cfg.Deployments,
+		"defaults":    cfg.Defaults,
+		"stacks":      cfg.GetAllStacks(),
+		"filesets":    cfg.GetAllFilesets(),
+	}
+	raw, err := yaml.Marshal(view)
+	if err != nil {
+		return nil, apperr.Wrap("manifest.Diff", apperr.Internal, err, "marshal manifest")
+	}
+	var generic any
+	if err := yaml.Unmarshal(raw, &generic); err != nil {
+		return nil, apperr.Wrap("manifest.Diff", apperr.Internal, err, "unmarshal manifest")
+	}
+	out := map[string]string{}
+	flattenValue(out, "", generic, cfg.BaseDir)
+	return out, nil
+}
+
+// flattenValue records the leaves of v under prefix and reports whether it
+// recorded any. A map without non-empty leaves, such as a volume declared as
+// "data: {}", is recorded as "{}" so that declaring it still shows up.
+func flattenValue(out map[string]string, prefix string, v any, baseDir string) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case map[string]any:
+		emitted := false
+		for k, child := range t {
+			p := k
+			if prefix != "" {
+				p = prefix + "." + k
+			}
+			if flattenValue(out, p, child, baseDir) {
+				emitted = true
+			}
+		}
+		if !emitted && prefix != "" {
+			out[prefix] = "{}"
+			emitted = true
+		}
+		return emitted
+	case []any:
+		emitted := false
+		for i, child := range t {
+			if flattenValue(out, fmt.Sprintf("%s[%d]", prefix, i), child, baseDir) {
+				emitted = true
+			}
+		}
+		return emitted
+	case string:
+		if t == "" {
+			return false
+		}
+		out[prefix] = relativeToBase(t, baseDir)
+		return true
+	default:
+		out[prefix] = fmt.Sprint(t)
+		return true
+	}
+}
+
+// hasChildren reports whether settings holds any leaf below path, in which case
+// an empty map at path on the other side is not a difference of its own.
+func hasChildren(settings map[string]string, path string) bool {
+	for p := range settings {
+		if strings.HasPrefix(p, path+".") || strings.HasPrefix(p, path+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// relativeToBase shows an absolute path under baseDir relative to it.
+func relativeToBase(s, baseDir string) string {
+	if baseDir == "" || !filepath.IsAbs(s) {
+		return s
+	}
+	rel, err := filepath.Rel(baseDir, s)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return s
+	}
+	return filepath.ToSlash(rel)
+}
+
+// displayValue masks the value of a secret-looking setting, or the value part
+// of a secret-looking KEY=VALUE entry such as an inline environment variable.
+func displayValue(path, value string) string {
+	leaf := path
+	if i := strings.LastIndex(leaf, "."); i >= 0 {
+		leaf = leaf[i+1:]
+	}
+	if secretNamePattern.MatchString(leaf) && !strings.HasSuffix(leaf, "_file") && !strings.HasSuffix(leaf, "_dir") {
+		return maskedValue
+	}
+	if key, _, ok := strings.Cut(value, "="); ok && key != "" && !strings.ContainsAny(key, " /") && secretNamePattern.MatchString(key) {
+		return key + "=" + maskedValue
+	}
+	return value
+}
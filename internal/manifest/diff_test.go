@@ -0,0 +1,82 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeDiffManifest(t *testing.T, body string) Config {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "web"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "web", "compose.yaml"), []byte("services: {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "dockform.yml")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	return cfg
+}
+
+func TestDiff_ComparesEffectiveSettings(t *testing.T) {
+	staging := writeDiffManifest(t, `identifier: demo
+contexts:
+  default: {}
+stacks:
+  default/web:
+    root: web
+    environment:
+      inline: [LOG_LEVEL=debug, DB_PASSWORD=hunter2]
+`)
+	prod := writeDiffManifest(t, `identifier: demo
+contexts:
+  default:
+    volumes:
+      data: {}
+stacks:
+  default/web:
+    root: web
+    environment:
+      inline: [LOG_LEVEL=info, DB_PASSWORD=s3cret]
+`)
+
+	entries, err := Diff(staging, prod)
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	got := map[string]DiffEntry{}
+	for _, e := range entries {
+		got[e.Path] = e
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 differences (root paths must compare equal), got %+v", entries)
+	}
+	if e := got["stacks.default/web.environment.inline[0]"]; e.Kind != DiffChanged || e.Old != "LOG_LEVEL=debug" || e.New != "LOG_LEVEL=info" {
+		t.Errorf("unexpected inline change %+v", e)
+	}
+	if e := got["stacks.default/web.environment.inline[1]"]; e.Kind != DiffChanged || e.Old != "DB_PASSWORD="+maskedValue || e.New != "DB_PASSWORD="+maskedValue {
+		t.Errorf("expected the secret change reported but masked, got %+v", e)
+	}
+	if e := got["contexts.default.volumes.data"]; e.Kind != DiffAdded {
+		t.Errorf("expected the declared volume reported as added, got %+v", e)
+	}
+}
+
+func TestDiff_IdenticalManifests(t *testing.T) {
+	body := "identifier: demo\ncontexts:\n  default: {}\nstacks:\n  default/web:\n    root: web\n"
+	entries, err := Diff(writeDiffManifest(t, body), writeDiffManifest(t, body))
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected no differences, got %+v", entries)
+	}
+}
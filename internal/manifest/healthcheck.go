@@ -0,0 +1,80 @@
+package manifest
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/goccy/go-yaml"
+)
+
+// HealthcheckOverride injects a healthcheck into a compose service, for images
+// that ship without one. Durations use compose syntax (e.g. 10s, 1m30s).
+type HealthcheckOverride struct {
+	Command     string `yaml:"command"`      // Shell command run as CMD-SHELL
+	Interval    string `yaml:"interval"`     // Time between checks
+	Timeout     string `yaml:"timeout"`      // Time before a check is considered failed
+	Retries     *int   `yaml:"retries"`      // Consecutive failures before unhealthy
+	StartPeriod string `yaml:"start_period"` // Grace period before failures count
+}
+
+// validateHealthchecks checks the shape of every healthcheck override of a
+// stack, and that each names a service of its compose files.
+func validateHealthchecks(stackKey string, stack Stack) error {
+	for svc, hc := range stack.Healthchecks {
+		if strings.TrimSpace(svc) == "" {
+			return apperr.New("manifest.validateHealthchecks", apperr.InvalidInput, "stack %s: healthcheck service name must not be empty", stackKey)
+		}
+		if strings.TrimSpace(hc.Command) == "" {
+			return apperr.New("manifest.validateHealthchecks", apperr.InvalidInput, "stack %s: healthcheck for service %s: command is required", stackKey, svc)
+		}
+		for field, v := range map[string]string{"interval": hc.Interval, "timeout": hc.Timeout, "start_period": hc.StartPeriod} {
+			if v == "" {
+				continue
+			}
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				return apperr.New("manifest.validateHealthchecks", apperr.InvalidInput, "stack %s: healthcheck for service %s: %s must be a positive duration like 10s, got %q", stackKey, svc, field, v)
+			}
+		}
+		if hc.Retries != nil && *hc.Retries < 1 {
+			return apperr.New("manifest.validateHealthchecks", apperr.InvalidInput, "stack %s: healthcheck for service %s: retries must be at least 1", stackKey, svc)
+		}
+	}
+
+	defined, parsed := composeFileServices(stack)
+	if !parsed {
+		return apperr.New("manifest.validateHealthchecks", apperr.InvalidInput, "stack %s: healthchecks need a readable compose file to check the services against", stackKey)
+	}
+	names := make([]string, 0, len(stack.Healthchecks))
+	for svc := range stack.Healthchecks {
+		names = append(names, svc)
+	}
+	sort.Strings(names)
+	for _, svc := range names {
+		if _, ok := defined[svc]; !ok {
+			return apperr.New("manifest.validateHealthchecks", apperr.InvalidInput, "stack %s: healthchecks names %s, which is not a service of its compose files", stackKey, svc)
+		}
+	}
+	return nil
+}
+
+// healthcheckSpec renders a healthcheck override as a compose healthcheck,
+// leaving out the fields that are not set.
+func healthcheckSpec(hc HealthcheckOverride) yaml.MapSlice {
+	spec := yaml.MapSlice{{Key: "test", Value: []string{"CMD-SHELL", hc.Command}}}
+	if hc.Interval != "" {
+		spec = append(spec, yaml.MapItem{Key: "interval", Value: hc.Interval})
+	}
+	if hc.Timeout != "" {
+		spec = append(spec, yaml.MapItem{Key: "timeout", Value: hc.Timeout})
+	}
+	if hc.Retries != nil {
+		spec = append(spec, yaml.MapItem{Key: "retries", Value: *hc.Retries})
+	}
+	if hc.StartPeriod != "" {
+		spec = append(spec, yaml.MapItem{Key: "start_period", Value: hc.StartPeriod})
+	}
+	return spec
+}
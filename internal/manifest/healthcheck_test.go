@@ -0,0 +1,98 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+func healthcheckConfig(t *testing.T, hcs map[string]HealthcheckOverride) (Config, string) {
+	t.Helper()
+	base := t.TempDir()
+	root := filepath.Join(base, "app")
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "compose.yaml"), []byte("services:\n  nginx:\n    image: nginx\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return Config{
+		Identifier: "test",
+		Contexts:   map[string]ContextConfig{"default": {}},
+		Stacks: map[string]Stack{
+			"default/web": {Root: root, Files: []string{"compose.yaml"}, Healthchecks: hcs},
+		},
+	}, base
+}
+
+func TestNormalize_HealthcheckOverrideRenderedWithoutWriting(t *testing.T) {
+	retries := 3
+	cfg, base := healthcheckConfig(t, map[string]HealthcheckOverride{
+		"nginx": {Command: "wget -qO- http://localhost/ || exit 1", Interval: "10s", Retries: &retries, StartPeriod: "5s"},
+	})
+	if err := cfg.normalizeAndValidate(base); err != nil {
+		t.Fatalf("normalizeAndValidate: %v", err)
+	}
+	stack := cfg.Stacks["default/web"]
+	if len(stack.Files) != 1 || stack.Files[0] != "compose.yaml" {
+		t.Fatalf("expected the user's files untouched, got %v", stack.Files)
+	}
+	got := string(stack.ComposeOverride)
+	for _, want := range []string{"nginx:", "healthcheck:", "CMD-SHELL", "interval: 10s", "retries: 3", "start_period: 5s"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("override missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "timeout") {
+		t.Fatalf("unset fields must be omitted:\n%s", got)
+	}
+	if _, err := os.Stat(filepath.Join(base, ".dockform")); !os.IsNotExist(err) {
+		t.Fatalf("expected nothing written under the manifest directory, got %v", err)
+	}
+}
+
+func TestNormalize_HealthcheckRejectsUnknownService(t *testing.T) {
+	cfg, base := healthcheckConfig(t, map[string]HealthcheckOverride{"php": {Command: "true"}})
+	err := cfg.normalizeAndValidate(base)
+	if !apperr.IsKind(err, apperr.InvalidInput) || !strings.Contains(err.Error(), "php") {
+		t.Fatalf("expected InvalidInput naming php, got %v", err)
+	}
+}
+
+func TestValidateHealthchecks_RejectsBadShape(t *testing.T) {
+	zero := 0
+	cases := map[string]HealthcheckOverride{
+		"missing command":  {Interval: "10s"},
+		"bad interval":     {Command: "true", Interval: "ten seconds"},
+		"negative timeout": {Command: "true", Timeout: "-1s"},
+		"zero retries":     {Command: "true", Retries: &zero},
+	}
+	for name, hc := range cases {
+		err := validateHealthchecks("default/web", Stack{Healthchecks: map[string]HealthcheckOverride{"svc": hc}})
+		if err == nil || !apperr.IsKind(err, apperr.InvalidInput) {
+			t.Fatalf("%s: expected InvalidInput, got %v", name, err)
+		}
+	}
+}
+
+func TestNormalize_ValidatesHealthWait(t *testing.T) {
+	for value, ok := range map[string]bool{"60s": true, "1m30s": true, "0s": false, "-5s": false, "soon": false} {
+		cfg := Config{
+			Identifier: "test",
+			Contexts:   map[string]ContextConfig{"default": {}},
+			Stacks: map[string]Stack{
+				"default/web": {Root: "app", Files: []string{"compose.yaml"}, HealthWait: value},
+			},
+		}
+		err := cfg.normalizeAndValidate(t.TempDir())
+		if ok && err != nil {
+			t.Errorf("health_wait %q: unexpected error %v", value, err)
+		}
+		if !ok && (err == nil || !apperr.IsKind(err, apperr.InvalidInput)) {
+			t.Errorf("health_wait %q: expected InvalidInput, got %v", value, err)
+		}
+	}
+}
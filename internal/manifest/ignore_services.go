@@ -0,0 +1,114 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/goccy/go-yaml"
+)
+
+// IgnoredServicesProfile is the compose profile ignored services are moved
+// behind. Dockform never activates it, so compose config, ps and up leave the
+// services alone while a container started by hand keeps running.
+const IgnoredServicesProfile = "dockform-ignored"
+
+// validateIgnoreServices checks that every ignored service is named once and
+// is defined by one of the stack's compose files, and that no managed service
+// depends on an ignored one: compose refuses to start a service whose
+// dependency sits behind a profile that is not active.
+func validateIgnoreServices(stackKey string, stack Stack) error {
+	seen := map[string]struct{}{}
+	for _, name := range stack.IgnoreServices {
+		if strings.TrimSpace(name) == "" {
+			return apperr.New("manifest.validateIgnoreServices", apperr.InvalidInput, "stack %s: ignore_services entries must not be empty", stackKey)
+		}
+		if _, dup := seen[name]; dup {
+			return apperr.New("manifest.validateIgnoreServices", apperr.InvalidInput, "stack %s: ignore_services lists %s more than once", stackKey, name)
+		}
+		seen[name] = struct{}{}
+	}
+
+	defined, parsed := composeFileServices(stack)
+	if !parsed {
+		return apperr.New("manifest.validateIgnoreServices", apperr.InvalidInput, "stack %s: ignore_services needs a readable compose file to check the services against", stackKey)
+	}
+	for _, name := range stack.IgnoreServices {
+		if _, ok := defined[name]; !ok {
+			return apperr.New("manifest.validateIgnoreServices", apperr.InvalidInput, "stack %s: ignore_services names %s, which is not a service of its compose files", stackKey, name)
+		}
+	}
+	names := make([]string, 0, len(defined))
+	for name := range defined {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ignored := seen[name]; ignored {
+			continue
+		}
+		for _, dep := range defined[name] {
+			if _, ignored := seen[dep]; ignored {
+				return apperr.New("manifest.validateIgnoreServices", apperr.InvalidInput, "stack %s: service %s depends on %s, which ignore_services leaves unmanaged", stackKey, name, dep)
+			}
+		}
+	}
+	return nil
+}
+
+// composeFileServices returns the services declared across the stack's compose
+// files, each with the services it lists under depends_on, and whether at
+// least one file could be parsed.
+func composeFileServices(stack Stack) (map[string][]string, bool) {
+	services := map[string][]string{}
+	parsed := false
+	for _, file := range stack.Files {
+		pth := file
+		if !filepath.IsAbs(pth) {
+			pth = filepath.Join(stack.Root, pth)
+		}
+		content, err := os.ReadFile(pth)
+		if err != nil {
+			continue
+		}
+		var doc struct {
+			Services map[string]struct {
+				DependsOn any `yaml:"depends_on"`
+			} `yaml:"services"`
+		}
+		if err := yaml.Unmarshal(content, &doc); err != nil {
+			continue
+		}
+		parsed = true
+		for name, svc := range doc.Services {
+			deps := services[name]
+			switch d := svc.DependsOn.(type) {
+			case []any:
+				for _, dep := range d {
+					if s, ok := dep.(string); ok {
+						deps = append(deps, s)
+					}
+				}
+			case map[string]any:
+				for dep := range d {
+					deps = append(deps, dep)
+				}
+			}
+			sort.Strings(deps)
+			services[name] = deps
+		}
+	}
+	return services, parsed
+}
+
+// IsIgnoredService reports whether the stack excludes service from management.
+func (s Stack) IsIgnoredService(service string) bool {
+	for _, name := range s.IgnoreServices {
+		if name == service {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+func ignoreServicesConfig(t *testing.T, ignore ...string) (Config, string) {
+	t.Helper()
+	base := t.TempDir()
+	root := filepath.Join(base, "app")
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	compose := "services:\n  web:\n    image: nginx\n  tool:\n    image: busybox\n"
+	if err := os.WriteFile(filepath.Join(root, "compose.yaml"), []byte(compose), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return Config{
+		Identifier: "test",
+		Contexts:   map[string]ContextConfig{"default": {}},
+		Stacks: map[string]Stack{
+			"default/web": {Root: root, Files: []string{"compose.yaml"}, IgnoreServices: ignore},
+		},
+	}, base
+}
+
+func TestNormalize_IgnoreServicesOverrideRenderedWithoutWriting(t *testing.T) {
+	cfg, base := ignoreServicesConfig(t, "tool")
+	if err := cfg.normalizeAndValidate(base); err != nil {
+		t.Fatalf("normalizeAndValidate: %v", err)
+	}
+	stack := cfg.Stacks["default/web"]
+	if len(stack.Files) != 1 || stack.Files[0] != "compose.yaml" {
+		t.Fatalf("expected the user's files untouched, got %v", stack.Files)
+	}
+	got := string(stack.ComposeOverride)
+	if !strings.Contains(got, "tool:") || !strings.Contains(got, IgnoredServicesProfile) || strings.Contains(got, "web:") {
+		t.Fatalf("expected only tool moved behind %s:\n%s", IgnoredServicesProfile, got)
+	}
+	if _, err := os.Stat(filepath.Join(base, ".dockform")); !os.IsNotExist(err) {
+		t.Fatalf("expected nothing written under the manifest directory, got %v", err)
+	}
+}
+
+func TestNormalize_IgnoreServicesAndHealthchecksShareOneOverride(t *testing.T) {
+	cfg, base := ignoreServicesConfig(t, "tool")
+	stack := cfg.Stacks["default/web"]
+	stack.Healthchecks = map[string]HealthcheckOverride{"web": {Command: "true"}}
+	cfg.Stacks["default/web"] = stack
+	if err := cfg.normalizeAndValidate(base); err != nil {
+		t.Fatalf("normalizeAndValidate: %v", err)
+	}
+	want := "services:\n  tool:\n    profiles:\n    - " + IgnoredServicesProfile + "\n  web:\n    healthcheck:\n      test:\n      - CMD-SHELL\n      - \"true\"\n"
+	if got := string(cfg.Stacks["default/web"].ComposeOverride); got != want {
+		t.Fatalf("override =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestNormalize_IgnoreServicesRequiresParsableComposeFile(t *testing.T) {
+	cfg, base := ignoreServicesConfig(t, "tool")
+	stack := cfg.Stacks["default/web"]
+	stack.Files = []string{"missing.yaml"}
+	cfg.Stacks["default/web"] = stack
+	err := cfg.normalizeAndValidate(base)
+	if !apperr.IsKind(err, apperr.InvalidInput) || !strings.Contains(err.Error(), "readable compose file") {
+		t.Fatalf("expected InvalidInput for no readable compose file, got %v", err)
+	}
+}
+
+func TestNormalize_IgnoreServicesRejectsDependencyOfManagedService(t *testing.T) {
+	for name, compose := range map[string]string{
+		"list form": "services:\n  web:\n    image: nginx\n    depends_on: [tool]\n  tool:\n    image: busybox\n",
+		"map form":  "services:\n  web:\n    image: nginx\n    depends_on:\n      tool:\n        condition: service_started\n  tool:\n    image: busybox\n",
+	} {
+		cfg, base := ignoreServicesConfig(t, "tool")
+		if err := os.WriteFile(filepath.Join(cfg.Stacks["default/web"].Root, "compose.yaml"), []byte(compose), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		err := cfg.normalizeAndValidate(base)
+		if !apperr.IsKind(err, apperr.InvalidInput) || !strings.Contains(err.Error(), "web depends on tool") {
+			t.Fatalf("%s: expected InvalidInput for web depending on ignored tool, got %v", name, err)
+		}
+	}
+}
+
+func TestNormalize_IgnoreServicesRejectsUnknownOrDuplicate(t *testing.T) {
+	for name, ignore := range map[string][]string{
+		"unknown":   {"worker"},
+		"duplicate": {"tool", "tool"},
+		"empty":     {" "},
+	} {
+		cfg, base := ignoreServicesConfig(t, ignore...)
+		err := cfg.normalizeAndValidate(base)
+		if err == nil || !apperr.IsKind(err, apperr.InvalidInput) {
+			t.Fatalf("%s: expected InvalidInput, got %v", name, err)
+		}
+	}
+}
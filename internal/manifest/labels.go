@@ -0,0 +1,41 @@
+package manifest
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+// labelKeyPattern follows docker's label key convention: alphanumerics
+// separated by dots, dashes, underscores or slashes.
+var labelKeyPattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._/-]*[A-Za-z0-9])?$`)
+
+// reservedLabelPrefixes are namespaces owned by dockform and compose; stack
+// labels under them would fight with the labels those tools maintain.
+var reservedLabelPrefixes = []string{"io.dockform.", "com.docker.compose."}
+
+// validateStackLabels checks the format of a stack's labels and that none
+// of them collides with a reserved namespace such as the identifier label.
+func validateStackLabels(stackKey string, labels map[string]string) error {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if !labelKeyPattern.MatchString(k) {
+			return apperr.New("manifest.validateStackLabels", apperr.InvalidInput, "stack %s: label key %q must start and end with a letter or digit and contain only letters, digits, '.', '-', '_' or '/'", stackKey, k)
+		}
+		for _, prefix := range reservedLabelPrefixes {
+			if strings.HasPrefix(k, prefix) {
+				return apperr.New("manifest.validateStackLabels", apperr.InvalidInput, "stack %s: label %s uses the reserved %s namespace", stackKey, k, strings.TrimSuffix(prefix, "."))
+			}
+		}
+		if strings.ContainsAny(labels[k], "\n\r\x00") {
+			return apperr.New("manifest.validateStackLabels", apperr.InvalidInput, "stack %s: label %s value must be a single line", stackKey, k)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+func TestValidateStackLabels(t *testing.T) {
+	if err := validateStackLabels("default/web", map[string]string{"team": "payments", "com.example/cost-center": "42", "empty": ""}); err != nil {
+		t.Fatalf("expected valid labels, got %v", err)
+	}
+	for name, labels := range map[string]map[string]string{
+		"bad key":        {"-team": "x"},
+		"space in key":   {"cost center": "x"},
+		"identifier":     {"io.dockform.identifier": "other"},
+		"compose owned":  {"com.docker.compose.project": "x"},
+		"multiline":      {"team": "a\nb"},
+		"equals in key":  {"a=b": "x"},
+		"trailing slash": {"team/": "x"},
+	} {
+		err := validateStackLabels("default/web", labels)
+		if err == nil || !apperr.IsKind(err, apperr.InvalidInput) {
+			t.Errorf("%s: expected InvalidInput, got %v", name, err)
+		}
+	}
+}
+
+func TestApplyStackDefaults_MergesLabels(t *testing.T) {
+	d := StackDefaults{Labels: map[string]string{"owner": "platform", "team": "core"}}
+	got := applyStackDefaults(Stack{Labels: map[string]string{"team": "payments"}}, d, "/base")
+	if got.Labels["owner"] != "platform" || got.Labels["team"] != "payments" {
+		t.Fatalf("expected defaults merged with the stack winning, got %v", got.Labels)
+	}
+}
@@ -24,6 +24,13 @@ var envVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
 
 // LoadWithWarnings reads and validates configuration and returns missing env var names instead of printing.
 func LoadWithWarnings(path string) (Config, []string, error) {
+	return LoadWithWarningsInDir(path, "")
+}
+
+// LoadWithWarningsInDir is LoadWithWarnings with relative paths in the manifest
+// resolved from baseDir instead of the manifest's own directory. An empty
+// baseDir keeps the default.
+func LoadWithWarningsInDir(path, baseDir string) (Config, []string, error) {
 	guessed, err := resolveConfigPath(path)
 	if err != nil {
 		return Config{}, nil, err
@@ -48,8 +55,13 @@ func LoadWithWarnings(path string) (Config, []string, error) {
 	if err := dec.Decode(&cfg); err != nil {
 		return Config{}, missing, apperr.New("manifest.Load", apperr.InvalidInput, "parse yaml: %s", yaml.FormatError(err, true, true))
 	}
+	cfg.Source = Source{File: guessed, Lines: sourceLines([]byte(interpolated))}
 
-	baseDir := filepath.Dir(guessedAbs)
+	if baseDir == "" {
+		baseDir = filepath.Dir(guessedAbs)
+	} else if baseDir, err = filepath.Abs(baseDir); err != nil {
+		return Config{}, missing, apperr.Wrap("manifest.Load", apperr.InvalidInput, err, "abs base dir")
+	}
 	cfg.BaseDir = baseDir
 
 	// Run convention discovery (always enabled; use stacks: block to override)
@@ -59,7 +71,7 @@ func LoadWithWarnings(path string) (Config, []string, error) {
 
 	// Normalize and validate the config
 	if err := cfg.normalizeAndValidate(baseDir); err != nil {
-		return Config{}, missing, err
+		return Config{}, missing, cfg.Source.annotate(err)
 	}
 
 	return cfg, missing, nil
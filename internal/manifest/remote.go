@@ -0,0 +1,221 @@
+package manifest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/goccy/go-yaml"
+)
+
+// RemoteKind tells how a remote manifest is fetched.
+type RemoteKind int
+
+const (
+	// RemoteHTTP is a single manifest file served over HTTP(S).
+	RemoteHTTP RemoteKind = iota + 1
+	// RemoteGit is a manifest inside a git repository, checked out at a ref.
+	RemoteGit
+)
+
+// RemoteManifest is a manifest fetched from a URL into the local cache.
+type RemoteManifest struct {
+	Kind RemoteKind
+	// Path is the local manifest file, or the checkout directory holding it.
+	Path string
+}
+
+// remoteHTTPClient fetches HTTP manifests.
+var remoteHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// maxRemoteManifestSize bounds how much of an HTTP response is read as a manifest.
+const maxRemoteManifestSize = 4 << 20
+
+// IsRemote reports whether the --manifest value is a URL rather than a path:
+// http(s)://... for a single file, git+<url>[//subdir][#ref] for a repository.
+func IsRemote(src string) bool {
+	return strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") || strings.HasPrefix(src, "git+")
+}
+
+// FetchRemote downloads the manifest at src into a cache entry under cacheDir
+// and returns where it landed. HTTP manifests are revalidated with their ETag
+// and git checkouts of a full commit SHA are reused as is. The fetched
+// manifest must parse as YAML.
+func FetchRemote(ctx context.Context, src, cacheDir string) (RemoteManifest, error) {
+	sum := sha256.Sum256([]byte(src))
+	entry := filepath.Join(cacheDir, hex.EncodeToString(sum[:8]))
+	if strings.HasPrefix(src, "git+") {
+		return fetchGit(ctx, strings.TrimPrefix(src, "git+"), entry)
+	}
+	return fetchHTTP(ctx, src, entry)
+}
+
+// DefaultRemoteCacheDir returns where fetched manifests are cached.
+func DefaultRemoteCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", apperr.Wrap("manifest.DefaultRemoteCacheDir", apperr.Internal, err, "locate user cache directory")
+	}
+	return filepath.Join(dir, "dockform", "manifests"), nil
+}
+
+func fetchHTTP(ctx context.Context, url, entry string) (RemoteManifest, error) {
+	file := filepath.Join(entry, "dockform.yml")
+	etagFile := filepath.Join(entry, "etag")
+	res := RemoteManifest{Kind: RemoteHTTP, Path: file}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return RemoteManifest{}, apperr.Wrap("manifest.FetchRemote", apperr.InvalidInput, err, "invalid manifest URL %s", url)
+	}
+	if etag, err := os.ReadFile(etagFile); err == nil {
+		if _, err := os.Stat(file); err == nil {
+			req.Header.Set("If-None-Match", strings.TrimSpace(string(etag)))
+		}
+	}
+	resp, err := remoteHTTPClient.Do(req)
+	if err != nil {
+		return RemoteManifest{}, apperr.Wrap("manifest.FetchRemote", apperr.Unavailable, err, "fetch manifest from %s", url)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		return res, nil
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return RemoteManifest{}, apperr.New("manifest.FetchRemote", apperr.External, "fetch manifest from %s: %s", url, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteManifestSize+1))
+	if err != nil {
+		return RemoteManifest{}, apperr.Wrap("manifest.FetchRemote", apperr.Unavailable, err, "read manifest from %s", url)
+	}
+	if len(body) > maxRemoteManifestSize {
+		return RemoteManifest{}, apperr.New("manifest.FetchRemote", apperr.InvalidInput, "manifest from %s exceeds %d bytes", url, maxRemoteManifestSize)
+	}
+	if err := checkParses(body, url); err != nil {
+		return RemoteManifest{}, err
+	}
+
+	if err := os.MkdirAll(entry, 0o700); err != nil {
+		return RemoteManifest{}, apperr.Wrap("manifest.FetchRemote", apperr.Internal, err, "create cache directory")
+	}
+	if err := os.WriteFile(file, body, 0o600); err != nil {
+		return RemoteManifest{}, apperr.Wrap("manifest.FetchRemote", apperr.Internal, err, "cache manifest")
+	}
+	_ = os.Remove(etagFile)
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		_ = os.WriteFile(etagFile, []byte(etag), 0o600)
+	}
+	return res, nil
+}
+
+var commitSHA = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// parseGitSource splits repo[//subdir][#ref] into its parts; ref defaults to
+// HEAD, the remote's default branch.
+func parseGitSource(src string) (repo, subdir, ref string) {
+	repo, ref, _ = strings.Cut(src, "#")
+	if ref == "" {
+		ref = "HEAD"
+	}
+	scheme := ""
+	if i := strings.Index(repo, "://"); i >= 0 {
+		scheme, repo = repo[:i+3], repo[i+3:]
+	}
+	repo, subdir, _ = strings.Cut(repo, "//")
+	return scheme + repo, subdir, ref
+}
+
+// checkGitSource rejects a ref git would read as an option and a subdir that
+// leaves the checkout.
+func checkGitSource(src, subdir, ref string) error {
+	if strings.HasPrefix(ref, "-") {
+		return apperr.New("manifest.FetchRemote", apperr.InvalidInput, "invalid git ref %q in %s", ref, src)
+	}
+	if subdir != "" {
+		clean := filepath.Clean(filepath.FromSlash(subdir))
+		if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+			return apperr.New("manifest.FetchRemote", apperr.InvalidInput, "subdirectory %q in %s leaves the repository", subdir, src)
+		}
+	}
+	return nil
+}
+
+func fetchGit(ctx context.Context, src, entry string) (RemoteManifest, error) {
+	repo, subdir, ref := parseGitSource(src)
+	if err := checkGitSource(src, subdir, ref); err != nil {
+		return RemoteManifest{}, err
+	}
+	res := RemoteManifest{Kind: RemoteGit, Path: filepath.Join(entry, filepath.FromSlash(subdir))}
+
+	git := func(args ...string) (string, error) {
+		cmd := exec.CommandContext(ctx, "git", append([]string{"-C", entry}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			return "", apperr.Wrap("manifest.FetchRemote", apperr.External, err, "git %s: %s", args[0], strings.TrimSpace(string(out)))
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+
+	if _, err := os.Stat(filepath.Join(entry, ".git")); errors.Is(err, fs.ErrNotExist) {
+		if err := os.MkdirAll(entry, 0o700); err != nil {
+			return RemoteManifest{}, apperr.Wrap("manifest.FetchRemote", apperr.Internal, err, "create cache directory")
+		}
+		if _, err := git("init", "-q"); err != nil {
+			return RemoteManifest{}, err
+		}
+		if _, err := git("remote", "add", "origin", repo); err != nil {
+			return RemoteManifest{}, err
+		}
+	} else if commitSHA.MatchString(ref) {
+		// A commit never moves: reuse the checkout when it is already there.
+		if head, err := git("rev-parse", "HEAD"); err == nil && head == ref {
+			return res, nil
+		}
+	}
+
+	if _, err := git("fetch", "-q", "--depth", "1", "--", "origin", ref); err != nil {
+		return RemoteManifest{}, err
+	}
+	if _, err := git("checkout", "-q", "--force", "FETCH_HEAD"); err != nil {
+		return RemoteManifest{}, err
+	}
+
+	file, err := resolveConfigPath(res.Path)
+	if err != nil {
+		return RemoteManifest{}, err
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return RemoteManifest{}, apperr.Wrap("manifest.FetchRemote", apperr.NotFound, err, "read manifest from %s", src)
+	}
+	if err := checkParses(b, src); err != nil {
+		return RemoteManifest{}, err
+	}
+	return res, nil
+}
+
+// checkParses rejects fetched content that is not YAML, before it replaces a
+// cached copy or reaches the loader.
+func checkParses(b []byte, src string) error {
+	var doc any
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return apperr.New("manifest.FetchRemote", apperr.InvalidInput, "manifest from %s does not parse: %s", src, yaml.FormatError(err, false, true))
+	}
+	if _, ok := doc.(map[string]any); !ok {
+		return apperr.New("manifest.FetchRemote", apperr.InvalidInput, "manifest from %s is not a YAML mapping", src)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package manifest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+func TestIsRemote(t *testing.T) {
+	for src, want := range map[string]bool{
+		"https://cfg.example.com/dockform.yml":     true,
+		"http://cfg.internal/dockform.yml":         true,
+		"git+https://github.com/org/deploy.git#v1": true,
+		"dockform.yml":                 false,
+		"./configs/https/dockform.yml": false,
+		"/srv/deploy":                  false,
+	} {
+		if got := IsRemote(src); got != want {
+			t.Errorf("IsRemote(%q) = %v, want %v", src, got, want)
+		}
+	}
+}
+
+func TestParseGitSource(t *testing.T) {
+	tests := []struct {
+		src, repo, subdir, ref string
+	}{
+		{"https://github.com/org/deploy.git", "https://github.com/org/deploy.git", "", "HEAD"},
+		{"https://github.com/org/deploy.git#v1.2", "https://github.com/org/deploy.git", "", "v1.2"},
+		{"https://github.com/org/deploy.git//envs/prod#main", "https://github.com/org/deploy.git", "envs/prod", "main"},
+		{"ssh://git@host/deploy.git//prod", "ssh://git@host/deploy.git", "prod", "HEAD"},
+	}
+	for _, tt := range tests {
+		repo, subdir, ref := parseGitSource(tt.src)
+		if repo != tt.repo || subdir != tt.subdir || ref != tt.ref {
+			t.Errorf("parseGitSource(%q) = %q, %q, %q; want %q, %q, %q", tt.src, repo, subdir, ref, tt.repo, tt.subdir, tt.ref)
+		}
+	}
+}
+
+func TestFetchRemote_HTTPRevalidatesWithETag(t *testing.T) {
+	var fetches, notModified atomic.Int32
+	body := "identifier: demo\n"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	cache := t.TempDir()
+	for i := 0; i < 2; i++ {
+		got, err := FetchRemote(context.Background(), srv.URL+"/dockform.yml", cache)
+		if err != nil {
+			t.Fatalf("fetch %d: %v", i, err)
+		}
+		b, err := os.ReadFile(got.Path)
+		if err != nil || string(b) != body {
+			t.Fatalf("fetch %d: expected cached manifest, got %q (%v)", i, b, err)
+		}
+	}
+	if fetches.Load() != 2 || notModified.Load() != 1 {
+		t.Fatalf("expected second fetch to be revalidated, got %d fetches, %d not modified", fetches.Load(), notModified.Load())
+	}
+}
+
+func TestFetchRemote_HTTPRejectsUnparseableContent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<html>login required</html>\n"))
+	}))
+	defer srv.Close()
+
+	_, err := FetchRemote(context.Background(), srv.URL, t.TempDir())
+	if !apperr.IsKind(err, apperr.InvalidInput) {
+		t.Fatalf("expected InvalidInput for non-YAML content, got %v", err)
+	}
+}
+
+func TestFetchRemote_HTTPRejectsOversizedBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("identifier: demo\n# " + strings.Repeat("x", maxRemoteManifestSize) + "\n"))
+	}))
+	defer srv.Close()
+
+	_, err := FetchRemote(context.Background(), srv.URL, t.TempDir())
+	if !apperr.IsKind(err, apperr.InvalidInput) || !strings.Contains(err.Error(), "exceeds") {
+		t.Fatalf("expected InvalidInput for an oversized manifest, got %v", err)
+	}
+}
+
+func TestFetchRemote_GitRejectsUnsafeSource(t *testing.T) {
+	for _, src := range []string{
+		"git+https://github.com/org/deploy.git#--upload-pack=touch /tmp/x",
+		"git+https://github.com/org/deploy.git//../../etc#main",
+		"git+https://github.com/org/deploy.git//prod/../..",
+	} {
+		cache := t.TempDir()
+		_, err := FetchRemote(context.Background(), src, cache)
+		if !apperr.IsKind(err, apperr.InvalidInput) {
+			t.Errorf("FetchRemote(%q): expected InvalidInput, got %v", src, err)
+		}
+		if entries, _ := os.ReadDir(cache); len(entries) != 0 {
+			t.Errorf("FetchRemote(%q): expected nothing cached, got %d entries", src, len(entries))
+		}
+	}
+}
+
+func TestFetchRemote_HTTPErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	_, err := FetchRemote(context.Background(), srv.URL, t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("expected error naming the status, got %v", err)
+	}
+}
+
+func TestFetchRemote_GitChecksOutRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(repo, "prod"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "prod", "dockform.yml"), []byte("identifier: v1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run("init", "-q")
+	run("add", ".")
+	run("commit", "-q", "-m", "v1")
+	run("tag", "v1")
+	if err := os.WriteFile(filepath.Join(repo, "prod", "dockform.yml"), []byte("identifier: v2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run("commit", "-q", "-am", "v2")
+
+	cache := t.TempDir()
+	got, err := FetchRemote(context.Background(), "git+file://"+repo+"//prod#v1", cache)
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	b, err := os.ReadFile(filepath.Join(got.Path, "dockform.yml"))
+	if err != nil || string(b) != "identifier: v1\n" {
+		t.Fatalf("expected manifest at tag v1, got %q (%v)", b, err)
+	}
+}
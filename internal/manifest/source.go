@@ -0,0 +1,138 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+// Source records where a manifest was read from and the line each of its
+// mapping keys is declared on, so problems can point at the offending line.
+// Keys are addressed by dotted paths of map keys, e.g. "identifier",
+// "stacks.default/web.env-file" or "stacks.default/web.filesets.assets".
+type Source struct {
+	File  string         // manifest path as given, e.g. dockform.yml
+	Lines map[string]int // key path -> 1-based line
+}
+
+// Line returns the line of path, or of its nearest ancestor declared in the
+// manifest, such as the stack of a field left to discovery. It returns 0 when
+// neither is declared.
+func (s Source) Line(path string) int {
+	for path != "" {
+		if line, ok := s.Lines[path]; ok {
+			return line
+		}
+		i := strings.LastIndex(path, ".")
+		if i < 0 {
+			break
+		}
+		path = path[:i]
+	}
+	return 0
+}
+
+// Locate renders the position of path as "file:line", or "" when it is unknown.
+func (s Source) Locate(path string) string {
+	line := s.Line(path)
+	if s.File == "" || line == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", s.File, line)
+}
+
+// FilesetPath returns the key path a fileset is declared at, given its
+// context/stack/fileset key.
+func FilesetPath(filesetKey string) string {
+	i := strings.LastIndex(filesetKey, "/")
+	if i < 0 {
+		return "filesets." + filesetKey
+	}
+	return "stacks." + filesetKey[:i] + ".filesets." + filesetKey[i+1:]
+}
+
+// sourceLines parses data and returns the line of every mapping key by path.
+// A manifest that does not parse yields no lines; decoding reports the error.
+func sourceLines(data []byte) map[string]int {
+	lines := map[string]int{}
+	file, err := parser.ParseBytes(data, 0)
+	if err != nil {
+		return lines
+	}
+	var walk func(prefix string, node ast.Node)
+	walk = func(prefix string, node ast.Node) {
+		switch n := node.(type) {
+		case *ast.MappingNode:
+			for _, v := range n.Values {
+				walk(prefix, v)
+			}
+		case *ast.MappingValueNode:
+			key := n.Key.String()
+			if s, ok := n.Key.(*ast.StringNode); ok {
+				key = s.Value
+			}
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			if _, seen := lines[path]; !seen {
+				lines[path] = n.Key.GetToken().Position.Line
+			}
+			walk(path, n.Value)
+		case *ast.AnchorNode:
+			walk(prefix, n.Value)
+		case *ast.TagNode:
+			walk(prefix, n.Value)
+		}
+	}
+	for _, doc := range file.Docs {
+		walk("", doc.Body)
+	}
+	return lines
+}
+
+// keyError ties a manifest validation error to the key path it concerns.
+type keyError struct {
+	path string
+	err  error
+}
+
+func (e *keyError) Error() string { return e.err.Error() }
+
+func (e *keyError) Unwrap() error { return e.err }
+
+// atKey marks err as concerning the manifest key at path.
+func atKey(path string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &keyError{path: path, err: err}
+}
+
+// annotate prefixes a validation error with the file and line of the key it
+// concerns, e.g. "dockform.yml:42: fileset default/web/assets: target_path
+// must be an absolute path". Errors whose key is not in the manifest are
+// returned as they are.
+func (s Source) annotate(err error) error {
+	var ke *keyError
+	if !errors.As(err, &ke) {
+		return err
+	}
+	loc := s.Locate(ke.path)
+	if loc == "" {
+		return ke.err
+	}
+	kind, msg := apperr.InvalidInput, ke.err.Error()
+	var e *apperr.E
+	if errors.As(ke.err, &e) {
+		kind = e.Kind
+		if e.Msg != "" {
+			msg = e.Msg
+		}
+	}
+	return apperr.Wrap("manifest.Load", kind, ke.err, "%s: %s", loc, msg)
+}
@@ -0,0 +1,70 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+func TestSourceLines(t *testing.T) {
+	yml := `identifier: demo
+contexts:
+  default: {}
+stacks:
+  default/web:
+    root: web
+    env-file:
+      - app.env
+    filesets:
+      assets:
+        source: ./assets
+        target_path: relative
+`
+	src := Source{File: "dockform.yml", Lines: sourceLines([]byte(yml))}
+	for path, want := range map[string]int{
+		"identifier":                       1,
+		"contexts.default":                 3,
+		"stacks.default/web":               5,
+		"stacks.default/web.env-file":      7,
+		"stacks.default/web.filesets":      9,
+		"stacks.default/web.root.missing":  6,
+		"stacks.default/api.root":          4,
+		"stacks.default/web.health_wait":   5,
+		"filesets.default/web/assets":      0,
+		"stacks.default/web.filesets.none": 9,
+	} {
+		if got := src.Line(path); got != want {
+			t.Errorf("Line(%q) = %d, want %d", path, got, want)
+		}
+	}
+	if got := src.Locate(FilesetPath("default/web/assets") + ".target_path"); got != "dockform.yml:12" {
+		t.Fatalf("Locate fileset target_path = %q, want dockform.yml:12", got)
+	}
+	if got := (Source{}).Locate("identifier"); got != "" {
+		t.Fatalf("expected no location without a file, got %q", got)
+	}
+}
+
+func TestLoad_ValidationErrorNamesManifestLine(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "assets"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "dockform.yml")
+	yml := "identifier: demo\ncontexts:\n  default: {}\nstacks:\n  default/web:\n    root: web\n    filesets:\n      assets:\n        source: ./assets\n        target_volume: data\n        target_path: relative\n"
+	if err := os.WriteFile(path, []byte(yml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Load(path)
+	want := path + ":11: fileset default/web/assets: target_path must be an absolute path"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected error containing %q, got: %v", want, err)
+	}
+	if !apperr.IsKind(err, apperr.InvalidInput) {
+		t.Fatalf("expected InvalidInput, got: %v", err)
+	}
+}
@@ -21,6 +21,9 @@ type Config struct {
 	Contexts    map[string]ContextConfig    `yaml:"contexts" validate:"required"`
 	Deployments map[string]DeploymentConfig `yaml:"deployments"`
 
+	// Settings merged into every stack unless the stack overrides them
+	Defaults StackDefaults `yaml:"defaults"`
+
 	// Explicit overrides (optional - discovery finds most of this automatically)
 	// Stack keys are in "context/stack" format (e.g., "hetzner-one/traefik")
 	Stacks map[string]Stack `yaml:"stacks" validate:"dive"`
@@ -28,6 +31,7 @@ type Config struct {
 	// Computed
 	BaseDir  string `yaml:"-"`
 	Targeted bool   `yaml:"-"` // True when config was filtered by --stack/--context/--deployment
+	Source   Source `yaml:"-"` // Manifest file and the line of each key
 
 	// Discovered resources (populated by convention discovery)
 	DiscoveredStacks   map[string]Stack       `yaml:"-"` // context/stack -> Stack
@@ -106,11 +110,37 @@ type Stack struct {
 	Project     *Project               `yaml:"project"`     // Compose project name override
 	Filesets    map[string]FilesetSpec `yaml:"filesets"`    // Fileset overrides/declarations
 
+	Healthchecks map[string]HealthcheckOverride `yaml:"healthchecks"` // Per-service healthcheck injection
+	HealthWait   string                         `yaml:"health_wait"`  // After compose up, wait this long (e.g. 60s) for started services to become healthy
+	// RecreateOnEnvChange labels services with a hash of their resolved
+	// environment (env files, inline env and SOPS secrets), so plan reports a
+	// service whose environment changed as drifted and apply recreates it.
+	// Turning it on changes every service once.
+	RecreateOnEnvChange bool        `yaml:"recreate_on_env_change"`
+	Hooks               *StackHooks `yaml:"hooks"` // Commands run around compose up
+
+	IgnoreServices []string          `yaml:"ignore_services"` // Compose services dockform leaves unmanaged
+	Labels         map[string]string `yaml:"labels"`          // Labels set on every container of the stack
+
 	// Computed fields
 	Context     string   `yaml:"-"` // Which context this belongs to (from key prefix)
 	EnvInline   []string `yaml:"-"` // Merged inline env vars
 	SopsSecrets []string `yaml:"-"` // Merged SOPS secret paths
 	RootAbs     string   `yaml:"-"` // Absolute path to stack root
+
+	// ComposeOverride is a compose file generated from the stack's
+	// ignore_services and healthchecks, layered on top of Files when compose
+	// runs.
+	ComposeOverride []byte `yaml:"-"`
+}
+
+// StackHooks are commands run around a stack's apply. Each hook is an argv array
+// executed on the host from the stack root, or in a one-off container of Service
+// when set. Hooks only run when the stack is actually applied.
+type StackHooks struct {
+	PreApply  [][]string `yaml:"pre_apply"`  // Run before compose up; a failure skips the stack
+	PostApply [][]string `yaml:"post_apply"` // Run after all services came up
+	Service   string     `yaml:"service"`    // Run hooks via `docker compose run` in this service
 }
 
 // Project allows overriding the Compose project name.
@@ -122,6 +152,10 @@ type Project struct {
 type Environment struct {
 	Files  []string `yaml:"files"`
 	Inline []string `yaml:"inline"`
+	// Resolve makes dockform resolve the stack's env files and inline variables
+	// itself and hand the result to compose, so compose interpolates the same
+	// values dockform hashes regardless of the caller's shell environment.
+	Resolve bool `yaml:"resolve"`
 }
 
 // SopsConfig configures SOPS provider(s) for secret decryption.
@@ -145,11 +179,18 @@ type SopsPgpConfig struct {
 	PinentryMode string   `yaml:"pinentry_mode"`
 	Recipients   []string `yaml:"recipients"`
 	Passphrase   string   `yaml:"passphrase"`
+
+	// Computed fields
+	GnupgHome string `yaml:"-"` // --gnupg-home override of KeyringDir for this invocation
 }
 
 // Secrets holds secret sources (SOPS-encrypted files).
 type Secrets struct {
-	Sops []string `yaml:"sops"`
+	// Sops lists SOPS-encrypted files whose keys become environment variables.
+	// Dotenv (.env) files are used as they are; YAML (.yaml, .yml) and JSON
+	// (.json) files are flattened, so db.password becomes DB_PASSWORD.
+	Sops   []string `yaml:"sops"`
+	Docker []string `yaml:"docker"` // Existing docker (swarm) secrets the stack depends on; verified, never decrypted
 }
 
 // NetworkSpec allows configuring Docker network driver and options.
@@ -175,6 +216,10 @@ type Ownership struct {
 	FileMode         string `yaml:"file_mode"`         // octal string "0644" or "644"
 	DirMode          string `yaml:"dir_mode"`          // octal string "0755" or "755"
 	PreserveExisting bool   `yaml:"preserve_existing"` // if true, only apply to new/updated paths
+	// UIDMap and GIDMap map user and group names to numeric IDs, used instead of
+	// resolving the names with getent in the helper image.
+	UIDMap map[string]string `yaml:"uid_map"`
+	GIDMap map[string]string `yaml:"gid_map"`
 }
 
 // FilesetSpec defines a local directory to sync into a Docker volume at a target path.
@@ -184,8 +229,17 @@ type FilesetSpec struct {
 	TargetPath      string         `yaml:"target_path"`
 	RestartServices RestartTargets `yaml:"restart_services"`
 	ApplyMode       string         `yaml:"apply_mode"`
-	Exclude         []string       `yaml:"exclude"`
-	Ownership       *Ownership     `yaml:"ownership"`
+	// Exclude lists doublestar globs matched against paths relative to Source,
+	// e.g. "logs/**" or "**/*.tmp"; a leading "/" is allowed and ignored.
+	Exclude   []string   `yaml:"exclude"`
+	Ownership *Ownership `yaml:"ownership"`
+	// Template renders source files as Go templates over the stack's resolved
+	// environment before they are indexed and synced.
+	Template bool `yaml:"template"`
+	// HashStrategy is "strict" (default) to hash every file on each plan, or
+	// "fast" to reuse the hash in the remote index for files whose size and
+	// modification time are unchanged.
+	HashStrategy string `yaml:"hash_strategy"`
 
 	// Computed fields
 	SourceAbs string `yaml:"-"`
@@ -293,7 +347,9 @@ func (c *Config) GetAllStacks() map[string]Stack {
 			if len(v.Profiles) > 0 {
 				merged.Profiles = v.Profiles
 			}
-			if v.Environment != nil {
+			// Once normalized, the discovered stack already holds the
+			// overlay's environment merged with defaults; keep that.
+			if v.Environment != nil && merged.Environment == nil {
 				merged.Environment = v.Environment
 			}
 			if v.Secrets != nil {
@@ -302,6 +358,32 @@ func (c *Config) GetAllStacks() map[string]Stack {
 			if v.Project != nil {
 				merged.Project = v.Project
 			}
+			if len(v.Healthchecks) > 0 {
+				merged.Healthchecks = v.Healthchecks
+			}
+			if v.HealthWait != "" {
+				merged.HealthWait = v.HealthWait
+			}
+			if v.RecreateOnEnvChange {
+				merged.RecreateOnEnvChange = true
+			}
+			if v.Hooks != nil {
+				merged.Hooks = v.Hooks
+			}
+			if len(v.IgnoreServices) > 0 {
+				merged.IgnoreServices = v.IgnoreServices
+			}
+			if len(v.Labels) > 0 {
+				// Merge per key so labels from defaults survive.
+				labels := make(map[string]string, len(existing.Labels)+len(v.Labels))
+				for lk, lv := range existing.Labels {
+					labels[lk] = lv
+				}
+				for lk, lv := range v.Labels {
+					labels[lk] = lv
+				}
+				merged.Labels = labels
+			}
 			result[k] = merged
 		} else {
 			// No discovered stack: use explicit stack as fallback
@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gcstr/dockform/internal/apperr"
 )
@@ -45,7 +46,7 @@ func (c *Config) normalizeAndValidate(baseDir string) error {
 
 	// Require identifier
 	if strings.TrimSpace(c.Identifier) == "" {
-		return apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "identifier is required at the top level of the manifest")
+		return atKey("identifier", apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "identifier is required at the top level of the manifest"))
 	}
 
 	// Require at least one context
@@ -56,10 +57,10 @@ func (c *Config) normalizeAndValidate(baseDir string) error {
 	// Validate context configurations
 	for contextName, ctxCfg := range c.Contexts {
 		if !contextKeyRegex.MatchString(contextName) {
-			return apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "invalid context key %q: must match ^[a-z0-9_-]+$", contextName)
+			return atKey("contexts."+contextName, apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "invalid context key %q: must match ^[a-z0-9_-]+$", contextName))
 		}
 		if ctxCfg.Host != "" && strings.TrimSpace(ctxCfg.Host) == "" {
-			return apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "context %q: host cannot be whitespace-only", contextName)
+			return atKey("contexts."+contextName+".host", apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "context %q: host cannot be whitespace-only", contextName))
 		}
 	}
 
@@ -68,17 +69,17 @@ func (c *Config) normalizeAndValidate(baseDir string) error {
 		// Validate referenced contexts exist
 		for _, ctxName := range deploy.Contexts {
 			if _, ok := c.Contexts[ctxName]; !ok {
-				return apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "deployment %s: references unknown context %q", deployName, ctxName)
+				return atKey("deployments."+deployName+".contexts", apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "deployment %s: references unknown context %q", deployName, ctxName))
 			}
 		}
 		// Validate referenced stacks format (context/stack)
 		for _, stackKey := range deploy.Stacks {
 			context, _, err := ParseStackKey(stackKey)
 			if err != nil {
-				return apperr.Wrap("manifest.normalizeAndValidate", apperr.InvalidInput, err, "deployment %s: invalid stack reference", deployName)
+				return atKey("deployments."+deployName+".stacks", apperr.Wrap("manifest.normalizeAndValidate", apperr.InvalidInput, err, "deployment %s: invalid stack reference", deployName))
 			}
 			if _, ok := c.Contexts[context]; !ok {
-				return apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "deployment %s: stack %q references unknown context %q", deployName, stackKey, context)
+				return atKey("deployments."+deployName+".stacks", apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "deployment %s: stack %q references unknown context %q", deployName, stackKey, context))
 			}
 		}
 	}
@@ -87,17 +88,17 @@ func (c *Config) normalizeAndValidate(baseDir string) error {
 	for stackKey, stack := range c.Stacks {
 		context, stackName, err := ParseStackKey(stackKey)
 		if err != nil {
-			return apperr.Wrap("manifest.normalizeAndValidate", apperr.InvalidInput, err, "invalid stack key")
+			return atKey("stacks."+stackKey, apperr.Wrap("manifest.normalizeAndValidate", apperr.InvalidInput, err, "invalid stack key"))
 		}
 
 		// Validate context exists
 		if _, ok := c.Contexts[context]; !ok {
-			return apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "stack %s: references unknown context %q", stackKey, context)
+			return atKey("stacks."+stackKey, apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "stack %s: references unknown context %q", stackKey, context))
 		}
 
 		// Validate stack name format
 		if !appKeyRegex.MatchString(stackName) {
-			return apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "invalid stack name %q in key %q: must match ^[a-z0-9_.-]+$", stackName, stackKey)
+			return atKey("stacks."+stackKey, apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "invalid stack name %q in key %q: must match ^[a-z0-9_.-]+$", stackName, stackKey))
 		}
 
 		// Set the context reference
@@ -118,6 +119,8 @@ func (c *Config) normalizeAndValidate(baseDir string) error {
 			stack.Context = context
 		}
 
+		stack = applyStackDefaults(stack, c.Defaults, baseDir)
+
 		// Resolve root if not absolute
 		if stack.Root != "" && !filepath.IsAbs(stack.Root) {
 			stack.Root = filepath.Clean(filepath.Join(baseDir, stack.Root))
@@ -179,18 +182,74 @@ func (c *Config) normalizeAndValidate(baseDir string) error {
 			}
 		}
 
-		// Validate SOPS secrets have .env extension
+		if stack.Secrets != nil {
+			for _, name := range stack.Secrets.Docker {
+				if strings.TrimSpace(name) == "" {
+					return atKey("stacks."+stackKey+".secrets.docker", apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "stack %s: secrets.docker entries must not be empty", stackKey))
+				}
+			}
+		}
+
+		// Validate SOPS secrets have an extension naming their format
 		for _, sp := range stack.SopsSecrets {
-			if !strings.HasSuffix(strings.ToLower(sp), ".env") {
-				return apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "stack %s: secrets file %s must have .env extension", stackKey, sp)
+			switch strings.ToLower(filepath.Ext(sp)) {
+			case ".env", ".yaml", ".yml", ".json":
+			default:
+				return atKey("stacks."+stackKey+".secrets.sops", apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "stack %s: secrets file %s must have a .env, .yaml, .yml or .json extension", stackKey, sp))
 			}
 		}
 
 		// Validate bind mounts - check for relative path bind mounts that won't work with remote contexts
 		if err := validateBindMountsInComposeFile(stackKey, stack); err != nil {
-			return err
+			return atKey("stacks."+stackKey, err)
+		}
+
+		// A resolved environment is built from the env files, so they must exist.
+		if stack.Environment != nil && stack.Environment.Resolve {
+			for _, f := range stack.EnvFile {
+				pth := f
+				if !filepath.IsAbs(pth) {
+					pth = filepath.Join(stack.Root, pth)
+				}
+				if _, err := os.Stat(pth); err != nil {
+					return atKey("stacks."+stackKey+".environment.resolve", apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "stack %s: environment.resolve requires env file %s to exist", stackKey, pth))
+				}
+			}
 		}
 
+		if err := validateHooks(stackKey, stack.Hooks); err != nil {
+			return atKey("stacks."+stackKey+".hooks", err)
+		}
+
+		if stack.HealthWait != "" {
+			if d, err := time.ParseDuration(stack.HealthWait); err != nil || d <= 0 {
+				return atKey("stacks."+stackKey+".health_wait", apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "stack %s: health_wait must be a positive duration like 60s, got %q", stackKey, stack.HealthWait))
+			}
+		}
+
+		if err := validateStackLabels(stackKey, stack.Labels); err != nil {
+			return atKey("stacks."+stackKey+".labels", err)
+		}
+
+		// Ignored services and healthcheck overrides are rendered into a compose
+		// override document layered on top of the stack's own files when compose
+		// runs; nothing is written while loading.
+		if len(stack.IgnoreServices) > 0 {
+			if err := validateIgnoreServices(stackKey, stack); err != nil {
+				return atKey("stacks."+stackKey+".ignore_services", err)
+			}
+		}
+		if len(stack.Healthchecks) > 0 {
+			if err := validateHealthchecks(stackKey, stack); err != nil {
+				return atKey("stacks."+stackKey+".healthchecks", err)
+			}
+		}
+		override, err := renderComposeOverride(stack)
+		if err != nil {
+			return apperr.Wrap("manifest.normalizeAndValidate", apperr.Internal, err, "stack %s: render compose override", stackKey)
+		}
+		stack.ComposeOverride = override
+
 		// Update the stack in discovered (which will be merged in GetAllStacks)
 		if _, isDiscovered := c.DiscoveredStacks[stackKey]; isDiscovered {
 			c.DiscoveredStacks[stackKey] = stack
@@ -199,12 +258,18 @@ func (c *Config) normalizeAndValidate(baseDir string) error {
 		}
 	}
 
+	if c.Defaults.Project != nil && c.Defaults.Project.Name != "" {
+		if err := validateProjectNames(c.GetAllStacks()); err != nil {
+			return err
+		}
+	}
+
 	// Merge explicit filesets from stacks into DiscoveredFilesets
 	for stackKey, stack := range c.GetAllStacks() {
 		if len(stack.Filesets) == 0 {
 			continue
 		}
-		ctx, _, err := ParseStackKey(stackKey)
+		ctx, stackName, err := ParseStackKey(stackKey)
 		if err != nil {
 			continue
 		}
@@ -230,6 +295,12 @@ func (c *Config) normalizeAndValidate(baseDir string) error {
 				if fs.Ownership != nil {
 					existing.Ownership = fs.Ownership
 				}
+				if fs.Template {
+					existing.Template = true
+				}
+				if fs.HashStrategy != "" {
+					existing.HashStrategy = fs.HashStrategy
+				}
 				if fs.RestartServices.Attached || len(fs.RestartServices.Services) > 0 {
 					existing.RestartServices = fs.RestartServices
 				}
@@ -237,7 +308,7 @@ func (c *Config) normalizeAndValidate(baseDir string) error {
 			} else {
 				// No discovered fileset: insert as-is with context/stack set
 				fs.Context = ctx
-				fs.Stack = stackKey
+				fs.Stack = stackName
 				c.DiscoveredFilesets[fsKey] = fs
 			}
 		}
@@ -247,7 +318,7 @@ func (c *Config) normalizeAndValidate(baseDir string) error {
 	if c.Sops != nil {
 		// Migration error: top-level recipients deprecated
 		if len(c.Sops.Recipients) > 0 {
-			return apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "sops.recipients is no longer supported; move entries under sops.age.recipients or sops.pgp.recipients")
+			return atKey("sops.recipients", apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "sops.recipients is no longer supported; move entries under sops.age.recipients or sops.pgp.recipients"))
 		}
 		// Validate age
 		if c.Sops.Age != nil {
@@ -259,7 +330,7 @@ func (c *Config) normalizeAndValidate(baseDir string) error {
 					continue
 				}
 				if !strings.HasPrefix(v, "age1") {
-					return apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "sops.age.recipients: invalid age recipient format: %s", r)
+					return atKey("sops.age.recipients", apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "sops.age.recipients: invalid age recipient format: %s", r))
 				}
 			}
 		}
@@ -270,7 +341,7 @@ func (c *Config) normalizeAndValidate(baseDir string) error {
 				mode = "default"
 			}
 			if mode != "default" && mode != "loopback" {
-				return apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "sops.pgp.pinentry_mode must be 'default' or 'loopback'")
+				return atKey("sops.pgp.pinentry_mode", apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "sops.pgp.pinentry_mode must be 'default' or 'loopback'"))
 			}
 			c.Sops.Pgp.PinentryMode = mode
 		}
@@ -278,12 +349,13 @@ func (c *Config) normalizeAndValidate(baseDir string) error {
 
 	// Validate and normalize discovered filesets
 	for filesetKey, fs := range c.DiscoveredFilesets {
+		path := FilesetPath(filesetKey)
 		// Validate source
 		if strings.TrimSpace(fs.Source) == "" {
-			return apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "fileset %s: source path is required", filesetKey)
+			return atKey(path+".source", apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "fileset %s: source path is required", filesetKey))
 		}
 		if fs.TargetVolume == "" {
-			return apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "fileset %s: target_volume is required", filesetKey)
+			return atKey(path+".target_volume", apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "fileset %s: target_volume is required", filesetKey))
 		}
 
 		// target_path must be an absolute Unix path since it's used inside containers
@@ -292,7 +364,7 @@ func (c *Config) normalizeAndValidate(baseDir string) error {
 			fs.TargetPath = "/"
 		}
 		if !strings.HasPrefix(fs.TargetPath, "/") {
-			return apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "fileset %s: target_path must be an absolute path", filesetKey)
+			return atKey(path+".target_path", apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "fileset %s: target_path must be an absolute path", filesetKey))
 		}
 
 		// apply_mode: default to hot, validate values
@@ -301,13 +373,26 @@ func (c *Config) normalizeAndValidate(baseDir string) error {
 			mode = "hot"
 		}
 		if mode != "hot" && mode != "cold" {
-			return apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "fileset %s: apply_mode must be 'hot' or 'cold'", filesetKey)
+			return atKey(path+".apply_mode", apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "fileset %s: apply_mode must be 'hot' or 'cold'", filesetKey))
 		}
 		fs.ApplyMode = mode
 
+		// hash_strategy: default to strict, validate values
+		strategy := strings.ToLower(strings.TrimSpace(fs.HashStrategy))
+		if strategy == "" {
+			strategy = "strict"
+		}
+		if strategy != "strict" && strategy != "fast" {
+			return atKey(path+".hash_strategy", apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "fileset %s: hash_strategy must be 'strict' or 'fast'", filesetKey))
+		}
+		if strategy == "fast" && fs.Template {
+			return atKey(path+".hash_strategy", apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "fileset %s: hash_strategy 'fast' cannot be used with template, which hashes rendered output", filesetKey))
+		}
+		fs.HashStrategy = strategy
+
 		// Validate and normalize ownership if provided
 		if err := validateOwnership(filesetKey, &fs); err != nil {
-			return err
+			return atKey(path+".ownership", err)
 		}
 
 		// Resolve source to absolute path if needed
@@ -364,6 +449,14 @@ func validateOwnership(filesetName string, fs *FilesetSpec) error {
 		o.Group = trimmed // Persist trimmed value
 	}
 
+	// Validate and normalize the name -> numeric ID maps
+	if err := validateIDMap(filesetName, "uid_map", o.UIDMap); err != nil {
+		return err
+	}
+	if err := validateIDMap(filesetName, "gid_map", o.GIDMap); err != nil {
+		return err
+	}
+
 	// Validate and normalize file_mode if provided
 	if o.FileMode != "" {
 		trimmed := strings.TrimSpace(o.FileMode)
@@ -391,6 +484,22 @@ func validateOwnership(filesetName string, fs *FilesetSpec) error {
 	return nil
 }
 
+// validateIDMap checks that every key of a uid_map or gid_map is a POSIX name
+// and every value a numeric ID, trimming the values in place.
+func validateIDMap(filesetName, field string, ids map[string]string) error {
+	for name, id := range ids {
+		if numeric, err := validateUserOrGroup(name); err != nil || numeric {
+			return apperr.New("manifest.validateOwnership", apperr.InvalidInput, "fileset %s: %s key %q must be a user or group name", filesetName, field, name)
+		}
+		trimmed := strings.TrimSpace(id)
+		if !numericIDRegex.MatchString(trimmed) {
+			return apperr.New("manifest.validateOwnership", apperr.InvalidInput, "fileset %s: %s value for %s must be a numeric ID, got %q", filesetName, field, name, id)
+		}
+		ids[name] = trimmed
+	}
+	return nil
+}
+
 // validateUserOrGroup validates a user or group identifier.
 // Returns (isNumeric, error).
 func validateUserOrGroup(s string) (bool, error) {
@@ -440,3 +549,18 @@ func parseOctalMode(s string) (uint32, error) {
 
 	return uint32(val), nil
 }
+
+// validateHooks ensures every hook command is a non-empty argv array.
+func validateHooks(stackKey string, hooks *StackHooks) error {
+	if hooks == nil {
+		return nil
+	}
+	for phase, cmds := range map[string][][]string{"pre_apply": hooks.PreApply, "post_apply": hooks.PostApply} {
+		for i, argv := range cmds {
+			if len(argv) == 0 || strings.TrimSpace(argv[0]) == "" {
+				return apperr.New("manifest.normalizeAndValidate", apperr.InvalidInput, "stack %s: hooks.%s[%d]: command must be a non-empty array", stackKey, phase, i)
+			}
+		}
+	}
+	return nil
+}
@@ -41,6 +41,70 @@ func TestNormalize_DefaultsAndFiles(t *testing.T) {
 	}
 }
 
+func TestNormalize_ExplicitFilesetRecordsStackName(t *testing.T) {
+	base := t.TempDir()
+	cfg := Config{
+		Identifier: "test",
+		Contexts:   map[string]ContextConfig{"default": {}},
+		Stacks: map[string]Stack{
+			"default/web": {
+				Root: "web",
+				Filesets: map[string]FilesetSpec{
+					"assets": {Source: "assets", TargetVolume: "web_assets", TargetPath: "/srv"},
+				},
+			},
+		},
+	}
+	if err := cfg.normalizeAndValidate(base); err != nil {
+		t.Fatalf("normalizeAndValidate: %v", err)
+	}
+	fs, ok := cfg.DiscoveredFilesets["default/web/assets"]
+	if !ok {
+		t.Fatal("expected fileset default/web/assets")
+	}
+	// Same shape as discovered filesets, so targeting can rebuild the stack key.
+	if fs.Context != "default" || fs.Stack != "web" {
+		t.Fatalf("expected context default and stack web, got %q and %q", fs.Context, fs.Stack)
+	}
+}
+
+func TestNormalize_ValidatesHashStrategy(t *testing.T) {
+	tests := []struct {
+		strategy string
+		template bool
+		want     string // normalized value, or "" when invalid
+	}{
+		{strategy: "", want: "strict"},
+		{strategy: " Fast ", want: "fast"},
+		{strategy: "strict", template: true, want: "strict"},
+		{strategy: "fast", template: true},
+		{strategy: "mtime"},
+	}
+	for _, tt := range tests {
+		cfg := Config{
+			Identifier: "test",
+			Contexts:   map[string]ContextConfig{"default": {}},
+			DiscoveredFilesets: map[string]FilesetSpec{
+				"default/web/assets": {Source: "assets", TargetVolume: "web_assets", HashStrategy: tt.strategy, Template: tt.template},
+			},
+		}
+		err := cfg.normalizeAndValidate(t.TempDir())
+		if tt.want == "" {
+			if err == nil || !apperr.IsKind(err, apperr.InvalidInput) {
+				t.Errorf("hash_strategy %q (template %v): expected InvalidInput, got %v", tt.strategy, tt.template, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("hash_strategy %q: unexpected error %v", tt.strategy, err)
+			continue
+		}
+		if got := cfg.DiscoveredFilesets["default/web/assets"].HashStrategy; got != tt.want {
+			t.Errorf("hash_strategy %q normalized to %q, want %q", tt.strategy, got, tt.want)
+		}
+	}
+}
+
 func TestNormalize_InvalidStackKey(t *testing.T) {
 	cfg := Config{
 		Identifier: "test",
@@ -131,7 +195,7 @@ func TestNormalize_SopsSecretsValidation(t *testing.T) {
 			"default":  {},
 		},
 		Stacks: map[string]Stack{
-			"default/web": {Root: "app", SopsSecrets: []string{"secrets.env"}},
+			"default/web": {Root: "app", SopsSecrets: []string{"secrets.env", "secrets.yaml", "db.yml", "api.json"}},
 		},
 	}
 	if err := cfg.normalizeAndValidate(base); err != nil {
@@ -546,6 +610,31 @@ func TestValidateOwnership(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid_id_maps",
+			ownership: &Ownership{
+				User:   "app",
+				Group:  "app",
+				UIDMap: map[string]string{"app": "10001"},
+				GIDMap: map[string]string{"app": " 10001 "},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid_uid_map_value",
+			ownership: &Ownership{
+				User:   "app",
+				UIDMap: map[string]string{"app": "app"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid_gid_map_key",
+			ownership: &Ownership{
+				GIDMap: map[string]string{"1000": "1000"},
+			},
+			wantErr: true,
+		},
 		{
 			name: "preserve_existing_flag",
 			ownership: &Ownership{
@@ -748,3 +837,31 @@ func TestGetAllSopsSecrets(t *testing.T) {
 		t.Errorf("missing expected secrets: %v", secrets)
 	}
 }
+
+func TestNormalize_RejectsEmptyHookCommand(t *testing.T) {
+	cfg := Config{
+		Identifier: "test",
+		Contexts:   map[string]ContextConfig{"default": {}},
+		Stacks: map[string]Stack{
+			"default/web": {Root: "app", Hooks: &StackHooks{PostApply: [][]string{{"echo", "ok"}, {}}}},
+		},
+	}
+	err := cfg.normalizeAndValidate(t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "hooks.post_apply[1]") {
+		t.Fatalf("expected empty hook command error, got %v", err)
+	}
+}
+
+func TestNormalize_ResolvedEnvRequiresEnvFiles(t *testing.T) {
+	cfg := Config{
+		Identifier: "test",
+		Contexts:   map[string]ContextConfig{"default": {}},
+		Stacks: map[string]Stack{
+			"default/web": {Root: "app", EnvFile: []string{"missing.env"}, Environment: &Environment{Resolve: true}},
+		},
+	}
+	err := cfg.normalizeAndValidate(t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "environment.resolve requires env file") {
+		t.Fatalf("expected missing env file error, got %v", err)
+	}
+}
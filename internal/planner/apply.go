@@ -2,9 +2,14 @@ package planner
 
 import (
 	"context"
+	"slices"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/drain"
 	"github.com/gcstr/dockform/internal/logger"
 	"github.com/gcstr/dockform/internal/manifest"
 )
@@ -12,15 +17,23 @@ import (
 // Apply creates missing top-level resources with labels and performs compose up, labeling containers with identifier.
 // This method detects the current state fresh, which may duplicate work if a plan was already built.
 // Consider using ApplyWithPlan if you have a pre-built plan to avoid redundant state detection.
-func (p *Planner) Apply(ctx context.Context, cfg manifest.Config) error {
+func (p *Planner) Apply(ctx context.Context, cfg manifest.Config) ([]StackApplyResult, error) {
 	return p.ApplyWithPlan(ctx, cfg, nil)
 }
 
 // ApplyWithPlan applies the desired state for all contexts, optionally reusing execution context from a pre-built plan.
 // If plan is non-nil and contains ExecutionContext, this avoids redundant Docker API calls, SOPS decryption,
 // and compose config parsing by reusing the state detection results from BuildPlan.
-func (p *Planner) ApplyWithPlan(ctx context.Context, cfg manifest.Config, plan *Plan) error {
+// It returns one result per stack it processed, sorted by context and stack,
+// including the stacks processed before a failure.
+// A graceful stop requested through the drain controller on ctx ends apply
+// between stacks and filesets; the unit in progress is completed first.
+func (p *Planner) ApplyWithPlan(ctx context.Context, cfg manifest.Config, plan *Plan) ([]StackApplyResult, error) {
+	ctx = p.withSecretsCache(ctx)
 	log := logger.FromContext(ctx).With("component", "planner")
+	p.results = &applyResults{}
+	p.diffPlan = plan
+	defer drain.FromContext(ctx).Begin()()
 
 	// Get all stacks and filesets
 	allStacks := cfg.GetAllStacks()
@@ -54,16 +67,19 @@ func (p *Planner) ApplyWithPlan(ctx context.Context, cfg manifest.Config, plan *
 		return p.applyContext(ctx, cfg, contextName, contextConfig, client, contextExecCtx)
 	})
 	if err != nil {
-		return st.Fail(err)
+		return p.results.stackResults(), st.Fail(err)
 	}
 
 	st.OK(true)
-	return nil
+	return p.results.stackResults(), nil
 }
 
 // applyContext applies changes for a single context.
 func (p *Planner) applyContext(ctx context.Context, cfg manifest.Config, contextName string, contextConfig manifest.ContextConfig, client DockerClient, execCtx *ContextExecutionContext) error {
 	log := logger.FromContext(ctx).With("component", "planner", "context", contextName)
+	if err := stopIfDraining(ctx, "planner.Apply", "context "+contextName); err != nil {
+		return err
+	}
 
 	// Get stacks and filesets for this context
 	contextStacks := cfg.GetStacksForContext(contextName)
@@ -91,12 +107,27 @@ func (p *Planner) applyContext(ctx context.Context, cfg manifest.Config, context
 	}
 
 	// Create missing volumes
+	p.printInlineDiff(contextName, func(rp *ResourcePlan) ResourcePlan { return ResourcePlan{Volumes: rp.Volumes} })
 	resourceManager := NewResourceManagerWithClient(client, progress)
 	existingVolumes, err := resourceManager.EnsureVolumesExistForContext(ctx, cfg, contextName, labels)
 	if err != nil {
 		return st.Fail(err)
 	}
 
+	// Fileset fast path: sync filesets and restart their services without
+	// touching networks or stacks.
+	if p.onlyFilesets {
+		restartPending, err := p.newFilesetManager(contextName, client, progress).SyncFilesetsForContext(ctx, cfg, contextName, existingVolumes, execCtx)
+		if err != nil {
+			return st.Fail(err)
+		}
+		if err := p.restartPendingServices(ctx, cfg, contextName, client, progress, restartPending, execCtx); err != nil {
+			return st.Fail(err)
+		}
+		st.OK(true)
+		return nil
+	}
+
 	// Create missing networks
 	existingNetworks := map[string]struct{}{}
 	if execCtx != nil {
@@ -111,12 +142,18 @@ func (p *Planner) applyContext(ctx context.Context, cfg manifest.Config, context
 			existingNetworks[n] = struct{}{}
 		}
 	}
+	p.printInlineDiff(contextName, func(rp *ResourcePlan) ResourcePlan { return ResourcePlan{Networks: rp.Networks} })
 	if err := resourceManager.EnsureNetworksExistForContext(ctx, cfg, contextName, labels, existingNetworks); err != nil {
 		return st.Fail(err)
 	}
+	if p.recreateNetworks {
+		if err := resourceManager.RecreateDriftedNetworksForContext(ctx, cfg, contextName, labels, existingNetworks); err != nil {
+			return st.Fail(err)
+		}
+	}
 
 	// Synchronize filesets
-	filesetManager := NewFilesetManagerWithClient(client, progress)
+	filesetManager := p.newFilesetManager(contextName, client, progress)
 	restartPending, err := filesetManager.SyncFilesetsForContext(ctx, cfg, contextName, existingVolumes, execCtx)
 	if err != nil {
 		return st.Fail(err)
@@ -128,8 +165,7 @@ func (p *Planner) applyContext(ctx context.Context, cfg manifest.Config, context
 	}
 
 	// Restart services that need it
-	restartManager := NewRestartManagerWithClient(client, p.pr, progress)
-	if err := restartManager.RestartPendingServices(ctx, restartPending); err != nil {
+	if err := p.restartPendingServices(ctx, cfg, contextName, client, progress, restartPending, execCtx); err != nil {
 		return st.Fail(err)
 	}
 
@@ -137,8 +173,65 @@ func (p *Planner) applyContext(ctx context.Context, cfg manifest.Config, context
 	return nil
 }
 
+// newFilesetManager returns a fileset manager for contextName configured from
+// the planner's apply options.
+func (p *Planner) newFilesetManager(contextName string, client DockerClient, progress ProgressReporter) *FilesetManager {
+	return NewFilesetManagerWithClient(client, progress).
+		WithNoRestart(p.noRestart).
+		WithRestartMounting(p.restartMounting).
+		WithParallelism(p.filesetParallelism).
+		WithConcurrency(p.filesetSyncConcurrency()).
+		WithStrictOwnership(p.strictOwnership).
+		withResults(p.results).
+		withBeforeSync(func(name string) {
+			p.printInlineDiff(contextName, func(rp *ResourcePlan) ResourcePlan {
+				return ResourcePlan{Filesets: map[string][]Resource{name: rp.Filesets[name]}}
+			})
+		})
+}
+
+// filesetSyncConcurrency is how many filesets apply syncs at once: one when
+// parallel processing is disabled or in a dry run, whose recorded operations
+// must follow a single reproducible order, the fileset parallelism otherwise.
+func (p *Planner) filesetSyncConcurrency() int {
+	if !p.parallel || p.dryRun != nil {
+		return 1
+	}
+	return p.filesetParallelism
+}
+
+// restartPendingServices restarts services whose filesets changed, unless
+// restarts are disabled, in which case the skipped services are reported.
+func (p *Planner) restartPendingServices(ctx context.Context, cfg manifest.Config, contextName string, client DockerClient, progress ProgressReporter, restartPending map[string]struct{}, execCtx *ContextExecutionContext) error {
+	if p.noRestart {
+		if len(restartPending) > 0 && p.pr != nil {
+			p.pr.Info("skipping restart of %s (--no-restart)", strings.Join(sortedKeys(restartPending), ", "))
+		}
+		return nil
+	}
+	var deps map[string][]string
+	var stackByProject map[string]string
+	if len(restartPending) > 0 {
+		deps, stackByProject = restartDependencies(ctx, cfg, contextName, client, execCtx)
+	}
+	// Untargeted applies restart a service wherever it runs in the context;
+	// targeted ones only touch the compose projects of the targeted stacks.
+	var projects map[string]struct{}
+	if cfg.Targeted {
+		projects = make(map[string]struct{}, len(stackByProject))
+		for project := range stackByProject {
+			projects[project] = struct{}{}
+		}
+	}
+	rm := NewRestartManagerWithClient(client, p.pr, progress).WithDependencies(deps).WithProjects(projects)
+	err := rm.RestartPendingServices(ctx, restartPending)
+	p.results.addRestarted(contextName, rm.Restarted(), stackByProject)
+	return err
+}
+
 // applyStackChangesForContext processes stacks for a context and performs compose up for those that need updates.
 func (p *Planner) applyStackChangesForContext(ctx context.Context, cfg manifest.Config, contextName string, stacks map[string]manifest.Stack, identifier string, client DockerClient, restartPending map[string]struct{}, progress ProgressReporter, execCtx *ContextExecutionContext) error {
+	log := logger.FromContext(ctx).With("component", "planner", "context", contextName)
 	detector := NewServiceStateDetector(client)
 
 	// Process stacks in sorted order for deterministic behavior
@@ -149,82 +242,270 @@ func (p *Planner) applyStackChangesForContext(ctx context.Context, cfg manifest.
 	sort.Strings(stackNames)
 
 	for _, stackName := range stackNames {
-		stack := stacks[stackName]
-
-		var services []ServiceInfo
-		var inline []string
-		var needsApply bool
-
-		// Check if we have pre-computed execution data from BuildPlan
-		if execCtx != nil && execCtx.Stacks[stackName] != nil {
-			// Reuse pre-computed data to avoid redundant state detection
-			log := logger.FromContext(ctx)
-			log.Info("apply_stack_reuse_cache", "context", contextName, "stack", stackName, "msg", "reusing execution context from plan")
-			execData := execCtx.Stacks[stackName]
-			services = execData.Services
-			inline = execData.InlineEnv
-			needsApply = execData.NeedsApply
-		} else {
-			// Fallback: detect state fresh (original behavior)
-			var err error
-			services, err = detector.DetectAllServicesState(ctx, stackName, stack, identifier, cfg.Sops)
-			if err != nil {
-				return apperr.Wrap("planner.Apply", apperr.External, err, "failed to detect service states for stack %s/%s", contextName, stackName)
-			}
-			inline, err = detector.BuildInlineEnv(ctx, stack, cfg.Sops)
-			if err != nil {
-				return apperr.Wrap("planner.Apply", apperr.External, err, "failed to build inline env for stack %s/%s", contextName, stackName)
+		if err := stopIfDraining(ctx, "planner.Apply", "stack "+manifest.MakeStackKey(contextName, stackName)); err != nil {
+			return err
+		}
+		began := time.Now()
+		res := StackApplyResult{Context: contextName, Stack: stackName}
+		err := p.applyStack(ctx, log, cfg, contextName, stackName, stacks[stackName], identifier, client, progress, execCtx, detector, &res)
+		res.Duration = time.Since(began)
+		res.Err = err
+		p.results.addStack(res)
+		if err != nil {
+			return err
+		}
+		if p.pruneEachStack != nil {
+			if err := p.pruneStackOrphans(ctx, cfg, contextName, stackName, stacks[stackName], client, execCtx, *p.pruneEachStack); err != nil {
+				return err
 			}
-			needsApply = NeedsApply(services)
 		}
+	}
+
+	return nil
+}
+
+// applyStack performs compose up for a single stack when any of its services
+// needs it, recording what happened to each service in res.
+func (p *Planner) applyStack(ctx context.Context, log logger.Logger, cfg manifest.Config, contextName, stackName string, stack manifest.Stack, identifier string, client DockerClient, progress ProgressReporter, execCtx *ContextExecutionContext, detector *ServiceStateDetector, res *StackApplyResult) error {
+	ctx = dockercli.WithStack(ctx, stack)
+	var services []ServiceInfo
+	var inline []string
+	var needsApply bool
+
+	// Check if we have pre-computed execution data from BuildPlan
+	if execCtx != nil && execCtx.Stacks[stackName] != nil {
+		// Reuse pre-computed data to avoid redundant state detection
+		log.Info("apply_stack_reuse_cache", "context", contextName, "stack", stackName, "msg", "reusing execution context from plan")
+		execData := execCtx.Stacks[stackName]
+		services = execData.Services
+		inline = execData.InlineEnv
+		needsApply = execData.NeedsApply
+	} else {
+		// Fallback: detect state fresh (original behavior)
+		var err error
+		services, err = detector.DetectAllServicesState(ctx, stackName, stack, identifier, cfg.Sops)
+		if err != nil {
+			return apperr.Wrap("planner.Apply", apperr.External, err, "failed to detect service states for stack %s/%s", contextName, stackName)
+		}
+		inline, err = detector.BuildInlineEnv(ctx, stack, cfg.Sops)
+		if err != nil {
+			return apperr.Wrap("planner.Apply", apperr.External, err, "failed to build inline env for stack %s/%s", contextName, stackName)
+		}
+		services = p.markUpdatedImages(ctx, client, stack, inline, services)
+		needsApply = NeedsApply(services)
+	}
 
-		if len(services) == 0 {
-			continue // No services to manage
+	if len(services) == 0 {
+		return nil // No services to manage
+	}
+
+	// Get project name
+	proj := ""
+	if stack.Project != nil {
+		proj = stack.Project.Name
+	}
+
+	// Check if any services need updates
+	if !needsApply {
+		res.Skipped = GetServiceNames(services)
+		// Up-to-date stacks are only touched by --relabel-all.
+		return p.relabelStack(ctx, client, contextName, stackName, stack, proj, inline, res.Skipped)
+	}
+	for _, svc := range services {
+		switch svc.State {
+		case ServiceMissing:
+			res.Created = append(res.Created, svc.Name)
+		case ServiceRunning:
+			res.Skipped = append(res.Skipped, svc.Name)
+		default:
+			res.Updated = append(res.Updated, svc.Name)
 		}
+	}
 
-		// Check if any services need updates
-		if !needsApply {
-			continue // All services are up-to-date
+	p.printInlineDiff(contextName, func(rp *ResourcePlan) ResourcePlan {
+		return ResourcePlan{Stacks: map[string][]Resource{stackName: rp.Stacks[stackName]}}
+	})
+
+	var hooks manifest.StackHooks
+	if stack.Hooks != nil {
+		hooks = *stack.Hooks
+	}
+	if err := p.runStackHooks(ctx, client, contextName, stackName, stack, "pre_apply", hooks.PreApply, proj, inline); err != nil {
+		return err
+	}
+
+	// Remember what recreated services ran so they can be rolled back.
+	var previousImages map[string]string
+	if p.healthTimeout > 0 {
+		var err error
+		if previousImages, err = recordPreviousImages(ctx, client, services); err != nil {
+			return err
 		}
+	}
 
-		// Get project name
-		proj := ""
-		if stack.Project != nil {
-			proj = stack.Project.Name
+	// Compose up starts stopped containers as they are; with
+	// --recreate-stopped they are removed first so up creates them anew.
+	if p.recreateStopped {
+		if err := removeStoppedContainers(ctx, client, services); err != nil {
+			return apperr.Wrap("planner.Apply", apperr.External, err, "recreate stopped services for stack %s/%s", contextName, stackName)
 		}
+	}
 
-		// Perform compose up
+	// Perform compose up, one service at a time in dependency order when
+	// changed services depend on each other.
+	order, err := recreationOrder(ctx, client, stack, services, inline)
+	if err != nil {
+		return apperr.Wrap("planner.Apply", apperr.External, err, "load service dependencies for stack %s/%s", contextName, stackName)
+	}
+	var upErr error
+	if len(order) > 0 {
+		upErr = composeUpInOrder(ctx, client, log, contextName, stackName, stack, proj, inline, order, progress)
+	} else {
 		if progress != nil {
 			progress.SetAction("docker compose up for " + contextName + "/" + stackName)
 		}
-		if _, err := client.ComposeUp(ctx, stack.Root, stack.Files, stack.Profiles, stack.EnvFile, proj, inline); err != nil {
-			return apperr.Wrap("planner.Apply", apperr.External, err, "compose up %s/%s", contextName, stackName)
+		st := logger.StartStep(log, "compose_up", stackName, "resource_kind", "stack", "project", proj)
+		upCtx, flush := streamComposeOutput(ctx, log, proj)
+		_, upErr = client.ComposeUp(upCtx, stack.Root, stack.Files, stack.Profiles, stack.EnvFile, proj, inline)
+		flush()
+		if upErr != nil {
+			_ = st.Fail(upErr)
+		} else {
+			st.OK(true)
 		}
+	}
 
-		// Best-effort: ensure identifier label is present on containers
-		if identifier != "" {
-			items, err := client.ComposePs(ctx, stack.Root, stack.Files, stack.Profiles, stack.EnvFile, proj, inline)
-			if err != nil {
-				return apperr.Wrap("planner.Apply", apperr.External, err, "list compose containers for stack %s/%s", contextName, stackName)
-			}
-			var labelErrs []error
-			for _, it := range items {
-				labels, err := client.InspectContainerLabels(ctx, it.Name, []string{"io.dockform.identifier"})
-				if err != nil {
-					labelErrs = append(labelErrs, apperr.Wrap("planner.Apply", apperr.External, err, "inspect identifier label for container %s", it.Name))
-					continue
-				}
-				if v, ok := labels["io.dockform.identifier"]; !ok || v != identifier {
-					if err := client.UpdateContainerLabels(ctx, it.Name, map[string]string{"io.dockform.identifier": identifier}); err != nil {
-						labelErrs = append(labelErrs, apperr.Wrap("planner.Apply", apperr.External, err, "update identifier label for container %s", it.Name))
-					}
-				}
-			}
-			if err := apperr.Aggregate("planner.Apply", apperr.External, "failed to apply identifier labels to one or more containers", labelErrs...); err != nil {
-				return err
-			}
+	// Inspect each service's container so failures name the service instead of
+	// surfacing only the aggregate compose error.
+	statuses, statusErr := client.ComposeServiceStatuses(ctx, stack.Root, stack.Files, stack.Profiles, stack.EnvFile, proj, GetServiceNames(services), inline)
+	if statusErr == nil {
+		p.results.addServices(contextName, stackName, statuses)
+	}
+	failed := failedServiceSummary(statuses)
+	if upErr != nil {
+		if failed != "" {
+			return apperr.Wrap("planner.Apply", apperr.External, upErr, "compose up %s/%s: %s", contextName, stackName, failed)
 		}
+		return apperr.Wrap("planner.Apply", apperr.External, upErr, "compose up %s/%s", contextName, stackName)
+	}
+	if statusErr != nil {
+		return apperr.Wrap("planner.Apply", apperr.External, statusErr, "inspect services for stack %s/%s", contextName, stackName)
+	}
+	if err := p.rollbackUnhealthyServices(ctx, client, contextName, stackName, stack, proj, inline, previousImages, progress); err != nil {
+		return err
+	}
+	if failed != "" {
+		return apperr.New("planner.Apply", apperr.External, "stack %s/%s: %s", contextName, stackName, failed)
+	}
+	if err := waitForStartedServices(ctx, client, log, contextName, stackName, stack, proj, inline, append(append([]string(nil), res.Created...), res.Updated...), previousImages, progress); err != nil {
+		return err
 	}
 
+	if err := p.runStackHooks(ctx, client, contextName, stackName, stack, "post_apply", hooks.PostApply, proj, inline); err != nil {
+		return err
+	}
+
+	return p.relabelStack(ctx, client, contextName, stackName, stack, proj, inline, res.Skipped)
+}
+
+// stopIfDraining returns an error wrapping context.Canceled when a graceful
+// stop was requested, so apply ends before starting next instead of midway
+// through it.
+func stopIfDraining(ctx context.Context, op, next string) error {
+	if !drain.Requested(ctx) {
+		return nil
+	}
+	return apperr.Wrap(op, apperr.Precondition, context.Canceled, "interrupted: stopped before %s", next)
+}
+
+// composeUpInOrder brings the given services up one at a time without their
+// dependencies, stopping at the first failure.
+func composeUpInOrder(ctx context.Context, client DockerClient, log logger.Logger, contextName, stackName string, stack manifest.Stack, proj string, inline []string, order []string, progress ProgressReporter) error {
+	for _, svc := range order {
+		if progress != nil {
+			progress.SetAction("docker compose up for " + contextName + "/" + stackName + " service " + svc)
+		}
+		st := logger.StartStep(log, "compose_up", stackName, "resource_kind", "stack", "project", proj, "service", svc)
+		upCtx, flush := streamComposeOutput(ctx, log, proj)
+		_, err := client.ComposeUpServices(upCtx, stack.Root, stack.Files, stack.Profiles, stack.EnvFile, proj, []string{svc}, inline)
+		flush()
+		if err != nil {
+			return st.Fail(apperr.Wrap("planner.Apply", apperr.External, err, "service %s", svc))
+		}
+		st.OK(true)
+	}
 	return nil
 }
+
+// relabelStack recreates the given services of a stack when --relabel-all is
+// set, so their containers carry the current identifier and stack labels.
+// Labels travel with the labeled compose project and Docker cannot change
+// them on a running container, so recreating is the only way to reapply them
+// to containers compose considers up to date. The containers replaced by the
+// recreate, told apart by their changed IDs, are counted in the apply results.
+func (p *Planner) relabelStack(ctx context.Context, client DockerClient, contextName, stackName string, stack manifest.Stack, proj string, inline []string, services []string) error {
+	if !p.relabelAll || len(services) == 0 {
+		return nil
+	}
+	before, err := serviceContainerIDs(ctx, client, stack, proj, inline, services)
+	if err != nil {
+		return apperr.Wrap("planner.Apply", apperr.External, err, "list compose containers for stack %s/%s", contextName, stackName)
+	}
+	if _, err := client.ComposeRecreateServices(ctx, stack.Root, stack.Files, stack.Profiles, stack.EnvFile, proj, services, inline); err != nil {
+		return apperr.Wrap("planner.Apply", apperr.External, err, "recreate services of stack %s/%s to reapply labels", contextName, stackName)
+	}
+	after, err := serviceContainerIDs(ctx, client, stack, proj, inline, services)
+	if err != nil {
+		return apperr.Wrap("planner.Apply", apperr.External, err, "list compose containers for stack %s/%s", contextName, stackName)
+	}
+	recreated := 0
+	for name, id := range before {
+		if newID, ok := after[name]; ok && newID != id {
+			recreated++
+		}
+	}
+	p.results.addRelabeled(recreated)
+	return nil
+}
+
+// serviceContainerIDs maps the name of each container of the given services
+// of a compose project to its ID.
+func serviceContainerIDs(ctx context.Context, client DockerClient, stack manifest.Stack, proj string, inline []string, services []string) (map[string]string, error) {
+	items, err := client.ComposePs(ctx, stack.Root, stack.Files, stack.Profiles, stack.EnvFile, proj, inline)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]string, len(items))
+	for _, it := range items {
+		if slices.Contains(services, it.Service) {
+			ids[it.Name] = it.ID
+		}
+	}
+	return ids, nil
+}
+
+// removeStoppedContainers removes the containers of stopped services so the
+// following compose up recreates them.
+func removeStoppedContainers(ctx context.Context, client DockerClient, services []ServiceInfo) error {
+	for _, svc := range services {
+		if svc.State != ServiceStopped || svc.Container == nil || svc.Container.Name == "" {
+			continue
+		}
+		if err := client.RemoveContainer(ctx, svc.Container.Name, true); err != nil {
+			return apperr.Wrap("planner.removeStoppedContainers", apperr.External, err, "remove stopped container %s", svc.Container.Name)
+		}
+	}
+	return nil
+}
+
+// failedServiceSummary describes the services that did not come up, e.g.
+// "service worker exited (code 1)", or returns "" when all are healthy.
+func failedServiceSummary(statuses []dockercli.ServiceStatus) string {
+	var parts []string
+	for _, st := range statuses {
+		if st.Failed() {
+			parts = append(parts, "service "+st.String())
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,53 @@
+package planner
+
+import (
+	"strings"
+
+	"github.com/gcstr/dockform/internal/manifest"
+	"github.com/gcstr/dockform/internal/ui"
+)
+
+// printInlineDiff prints the changes pick selects from contextName's plan,
+// when inline diffs are enabled. Deletions are left out: apply never deletes
+// a whole resource, prune does.
+func (p *Planner) printInlineDiff(contextName string, pick func(rp *ResourcePlan) ResourcePlan) {
+	if !p.inlineDiff || p.pr == nil || p.diffPlan == nil {
+		return
+	}
+	cp := p.diffPlan.ByContext[contextName]
+	if cp == nil || cp.Resources == nil {
+		return
+	}
+	sub := pick(cp.Resources)
+	sub.Volumes = pendingChanges(sub.Volumes)
+	sub.Networks = pendingChanges(sub.Networks)
+	stacks := make(map[string][]Resource, len(sub.Stacks))
+	for name, rs := range sub.Stacks {
+		if rs = pendingChanges(rs); len(rs) > 0 {
+			stacks[manifest.MakeStackKey(contextName, name)] = rs
+		}
+	}
+	sub.Stacks = stacks
+	filesets := make(map[string][]Resource, len(sub.Filesets))
+	for name, rs := range sub.Filesets {
+		if countNoop(rs) < len(rs) {
+			filesets[name] = rs
+		}
+	}
+	sub.Filesets = filesets
+	if len(sub.Volumes)+len(sub.Networks)+len(sub.Stacks)+len(sub.Filesets) == 0 {
+		return
+	}
+	p.pr.Plain("%s", strings.TrimRight(ui.RenderNestedSections(changesOnlySections(&sub)), "\n"))
+}
+
+// pendingChanges returns the resources in rs that apply creates or updates.
+func pendingChanges(rs []Resource) []Resource {
+	var out []Resource
+	for _, r := range rs {
+		if r.Action != ActionNoop && r.Action != ActionDelete {
+			out = append(out, r)
+		}
+	}
+	return out
+}
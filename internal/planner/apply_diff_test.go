@@ -0,0 +1,54 @@
+package planner
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/ui"
+)
+
+func TestApplyWithPlan_InlineDiffPrintsChangesInApplyOrder(t *testing.T) {
+	d := newMockDocker()
+	cfg := onlyFilesetsConfig(t)
+	p := NewWithDocker(d)
+	plan, err := p.BuildPlan(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("build plan: %v", err)
+	}
+
+	var out bytes.Buffer
+	p.WithPrinter(ui.StdPrinter{Out: &out, Err: &out}).WithInlineDiff(true)
+	if _, err := p.ApplyWithPlan(context.Background(), cfg, plan); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	got := ui.StripANSI(out.String())
+	volume := strings.Index(got, "data will be created")
+	network := strings.Index(got, "frontend will be created")
+	fileset := strings.Index(got, "index.html")
+	if volume < 0 || network < 0 || fileset < 0 {
+		t.Fatalf("expected volume, network and fileset changes in output, got:\n%s", got)
+	}
+	if volume >= network || network >= fileset {
+		t.Fatalf("expected changes printed in apply order (volumes, networks, filesets), got:\n%s", got)
+	}
+}
+
+func TestApplyWithPlan_NoInlineDiffByDefault(t *testing.T) {
+	d := newMockDocker()
+	cfg := onlyFilesetsConfig(t)
+	p := NewWithDocker(d)
+	plan, err := p.BuildPlan(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("build plan: %v", err)
+	}
+	var out bytes.Buffer
+	p.WithPrinter(ui.StdPrinter{Out: &out, Err: &out})
+	if _, err := p.ApplyWithPlan(context.Background(), cfg, plan); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if strings.Contains(out.String(), "will be created") {
+		t.Fatalf("expected no inline diff without WithInlineDiff, got:\n%s", out.String())
+	}
+}
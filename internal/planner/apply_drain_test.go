@@ -0,0 +1,67 @@
+package planner
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/drain"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+// drainOnUpDocker requests a graceful stop while compose up is in flight.
+type drainOnUpDocker struct {
+	*mockDockerClient
+	dc *drain.Controller
+}
+
+func (d drainOnUpDocker) ComposeUp(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project string, inline []string) (string, error) {
+	d.dc.Request()
+	return d.mockDockerClient.ComposeUp(ctx, root, files, profiles, envFiles, project, inline)
+}
+
+func TestApplyStackChanges_DrainFinishesCurrentStack(t *testing.T) {
+	dc := drain.New()
+	d := drainOnUpDocker{mockDockerClient: newMockDocker(), dc: dc}
+	stacks := map[string]manifest.Stack{
+		"a": {Root: t.TempDir(), Files: []string{"compose.yml"}},
+		"b": {Root: t.TempDir(), Files: []string{"compose.yml"}},
+	}
+	execCtx := &ContextExecutionContext{
+		ContextName: "default",
+		Stacks: map[string]*StackExecutionData{
+			"a": {Services: []ServiceInfo{{Name: "web", State: ServiceMissing}}, NeedsApply: true},
+			"b": {Services: []ServiceInfo{{Name: "api", State: ServiceMissing}}, NeedsApply: true},
+		},
+	}
+
+	p := NewWithDocker(d.mockDockerClient)
+	p.results = &applyResults{}
+	ctx := drain.WithController(context.Background(), dc)
+	err := p.applyStackChangesForContext(ctx, manifest.Config{}, "default", stacks, "", d, nil, nil, execCtx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a cancellation error after draining, got: %v", err)
+	}
+	if d.composeUps != 1 {
+		t.Fatalf("expected only the in-flight stack to be brought up, got %d ups", d.composeUps)
+	}
+	results := p.results.stackResults()
+	if len(results) != 1 || results[0].Stack != "a" || results[0].Err != nil {
+		t.Fatalf("expected the in-flight stack to complete, got: %#v", results)
+	}
+}
+
+func TestPrune_SkippedWhenDraining(t *testing.T) {
+	d := newMockDocker()
+	d.volumes = []string{"orphan"}
+	dc := drain.New()
+	dc.Request()
+	ctx := drain.WithController(context.Background(), dc)
+	cfg := manifest.Config{Identifier: "demo", Contexts: map[string]manifest.ContextConfig{"default": {}}}
+	if err := NewWithDocker(d).Prune(ctx, cfg); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected prune to stop when draining, got: %v", err)
+	}
+	if len(d.removedVolumes) != 0 {
+		t.Fatalf("expected nothing removed, got %v", d.removedVolumes)
+	}
+}
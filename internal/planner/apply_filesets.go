@@ -1,11 +1,14 @@
 package planner
 
 import (
-	"bytes"
 	"context"
+	"errors"
+	"io"
 	"sort"
+	"sync"
 
 	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/dockercli"
 	"github.com/gcstr/dockform/internal/filesets"
 	"github.com/gcstr/dockform/internal/logger"
 	"github.com/gcstr/dockform/internal/manifest"
@@ -14,8 +17,16 @@ import (
 
 // FilesetManager handles synchronization of filesets into Docker volumes.
 type FilesetManager struct {
-	docker   DockerClient
-	progress ProgressReporter
+	docker          DockerClient
+	progress        ProgressReporter
+	noRestart       bool
+	restartMounting bool
+	parallelism     int
+	concurrency     int
+	strictOwnership bool
+	results         *applyResults
+	beforeSync      func(name string)
+	mu              sync.Mutex // serializes beforeSync across concurrent syncs
 }
 
 // NewFilesetManager creates a new fileset manager.
@@ -28,6 +39,58 @@ func NewFilesetManagerWithClient(client DockerClient, progress ProgressReporter)
 	return &FilesetManager{docker: client, progress: progress}
 }
 
+// WithParallelism caps how many filesets are indexed and have their remote
+// index read concurrently before syncing. Values below 1 use
+// DefaultFilesetParallelism.
+func (fm *FilesetManager) WithParallelism(n int) *FilesetManager {
+	fm.parallelism = n
+	return fm
+}
+
+// WithConcurrency caps how many filesets are synced into their volumes at
+// once. Filesets targeting the same volume, and cold-mode filesets, are
+// still synced one at a time. Values below 2 sync every fileset in turn.
+func (fm *FilesetManager) WithConcurrency(n int) *FilesetManager {
+	fm.concurrency = n
+	return fm
+}
+
+// WithNoRestart makes the manager leave target services running: cold-mode
+// filesets are synced without stopping their services, and every target
+// service is reported back as restart-pending so the caller can surface it.
+func (fm *FilesetManager) WithNoRestart(noRestart bool) *FilesetManager {
+	fm.noRestart = noRestart
+	return fm
+}
+
+// WithRestartMounting makes the manager treat filesets without
+// restart_services as if they declared the services mounting their target
+// volume, so those are restarted (or stopped, in cold mode) on a change.
+func (fm *FilesetManager) WithRestartMounting(enabled bool) *FilesetManager {
+	fm.restartMounting = enabled
+	return fm
+}
+
+// WithStrictOwnership makes a fileset sync fail when ownership or permissions
+// could not be applied to some of its paths, instead of reporting them.
+func (fm *FilesetManager) WithStrictOwnership(strict bool) *FilesetManager {
+	fm.strictOwnership = strict
+	return fm
+}
+
+// withResults records per-path ownership failures into r.
+func (fm *FilesetManager) withResults(r *applyResults) *FilesetManager {
+	fm.results = r
+	return fm
+}
+
+// withBeforeSync calls fn with the name of each fileset about to be synced.
+// Filesets that are already up to date are not reported.
+func (fm *FilesetManager) withBeforeSync(fn func(name string)) *FilesetManager {
+	fm.beforeSync = fn
+	return fm
+}
+
 // SyncFilesetsForContext synchronizes filesets for a specific context into their target volumes.
 // Returns services that need restart.
 func (fm *FilesetManager) SyncFilesetsForContext(ctx context.Context, cfg manifest.Config, contextName string, existingVolumes map[string]struct{}, execCtx *ContextExecutionContext) (map[string]struct{}, error) {
@@ -50,165 +113,249 @@ func (fm *FilesetManager) SyncFilesetsForContext(ctx context.Context, cfg manife
 	}
 	sort.Strings(filesetNames)
 
+	// Validate resolved sources before doing any work
 	for _, name := range filesetNames {
-		fileset := contextFilesets[name]
-
-		if fileset.SourceAbs == "" {
+		if contextFilesets[name].SourceAbs == "" {
 			return nil, apperr.New("filesetmanager.SyncFilesetsForContext", apperr.InvalidInput, "fileset %s: resolved source path is empty", name)
 		}
+	}
 
-		var local, remote filesets.Index
-		var diff filesets.Diff
-
-		// Try to reuse cached data from plan execution context
-		if execCtx != nil && execCtx.Filesets != nil && execCtx.Filesets[name] != nil {
-			log.Info("fileset_sync_reuse_cache", "fileset", name, "msg", "reusing indexes and diff from plan")
-			execData := execCtx.Filesets[name]
-			local = execData.LocalIndex
-			remote = execData.RemoteIndex
-			diff = execData.Diff
-		} else {
-			// Fallback: compute indexes and diff fresh (original behavior)
-			var err error
-			local, err = filesets.BuildLocalIndex(fileset.SourceAbs, fileset.TargetPath, fileset.Exclude)
+	// Index and read remote state for all filesets up front (bounded), then
+	// sync them. Filesets sharing a target volume, and all cold-mode
+	// filesets, are synced one at a time in name order so tar extraction
+	// and service stops never race; the rest run concurrently up to the
+	// manager's concurrency.
+	prepared := fm.prepareFilesets(ctx, cfg, filesetNames, contextFilesets, existingVolumes, execCtx)
+
+	var mu sync.Mutex
+	var failed bool
+	errs := map[string]error{}
+	// Groups are keyed by their first fileset for forEachBounded.
+	groups := syncGroups(filesetNames, contextFilesets, fm.concurrency)
+	groupKeys := make([]string, 0, len(groups))
+	byKey := make(map[string][]string, len(groups))
+	for _, g := range groups {
+		groupKeys = append(groupKeys, g[0])
+		byKey[g[0]] = g
+	}
+	forEachBounded(groupKeys, max(fm.concurrency, 1), func(key string) {
+		for _, name := range byKey[key] {
+			mu.Lock()
+			stop := failed
+			mu.Unlock()
+			if stop {
+				return
+			}
+			err := stopIfDraining(ctx, "planner.Apply", "fileset "+name)
+			var targets []string
+			if err == nil {
+				targets, err = fm.syncFileset(ctx, log, cfg, contextName, name, contextFilesets[name], prepared[name], execCtx)
+			}
+			mu.Lock()
 			if err != nil {
-				return nil, apperr.Wrap("filesetmanager.SyncFilesetsForContext", apperr.Internal, err, "index local filesets for %s", name)
+				failed = true
+				errs[name] = err
 			}
-
-			// Only read from volume if it exists to avoid implicit creation
-			raw := ""
-			if _, volumeExists := existingVolumes[fileset.TargetVolume]; volumeExists {
-				raw, err = fm.docker.ReadFileFromVolume(ctx, fileset.TargetVolume, fileset.TargetPath, filesets.IndexFileName)
-				if err != nil {
-					return nil, apperr.Wrap("filesetmanager.SyncFilesetsForContext", apperr.External, err, "read index file for fileset %s", name)
-				}
+			for _, svc := range targets {
+				restartPending[svc] = struct{}{}
 			}
-			remote, err = filesets.ParseIndexJSON(raw)
+			mu.Unlock()
 			if err != nil {
-				return nil, apperr.Wrap("filesetmanager.SyncFilesetsForContext", apperr.External, err, "parse remote index for fileset %s", name)
+				return
 			}
-			diff = filesets.DiffIndexes(local, remote)
 		}
+	})
+	// Report the first failure in name order so errors stay deterministic.
+	for _, name := range filesetNames {
+		if err := errs[name]; err != nil {
+			return nil, err
+		}
+	}
 
-		// If completely equal, skip this fileset
-		if local.TreeHash == remote.TreeHash {
-			st := logger.StartStep(log, "fileset_sync", name, "resource_kind", "fileset", "target_volume", fileset.TargetVolume)
-			st.OK(false) // No changes needed
-			continue
+	return restartPending, nil
+}
+
+// syncGroups splits names into groups synced one fileset at a time. Filesets
+// targeting the same volume share a group, as do all cold-mode filesets,
+// since their service stops and starts must not interleave. With a
+// concurrency of 1 or less every fileset is in a single group, preserving
+// plain name order.
+func syncGroups(names []string, specs map[string]manifest.FilesetSpec, concurrency int) [][]string {
+	if concurrency <= 1 {
+		return [][]string{names}
+	}
+	// A hot fileset sharing a volume with a cold one joins the cold group.
+	coldVolumes := map[string]bool{}
+	for _, name := range names {
+		if specs[name].ApplyMode == "cold" {
+			coldVolumes[specs[name].TargetVolume] = true
 		}
+	}
+	index := map[string]int{}
+	var groups [][]string
+	for _, name := range names {
+		key := "volume " + specs[name].TargetVolume
+		if coldVolumes[specs[name].TargetVolume] {
+			key = "cold"
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], name)
+	}
+	return groups
+}
+
+// syncFileset syncs one prepared fileset into its volume and returns the
+// services to queue for restart.
+func (fm *FilesetManager) syncFileset(ctx context.Context, log logger.Logger, cfg manifest.Config, contextName, name string, fileset manifest.FilesetSpec, prep *preparedFileset, execCtx *ContextExecutionContext) ([]string, error) {
+	if prep.err != nil {
+		return nil, prep.err
+	}
+	if execCtx != nil && execCtx.Filesets != nil && execCtx.Filesets[name] != nil {
+		log.Info("fileset_sync_reuse_cache", "fileset", name, "msg", "reusing indexes and diff from plan")
+	}
+	renderer := prep.renderer
+	local, remote, diff := prep.data.LocalIndex, prep.data.RemoteIndex, prep.data.Diff
+
+	// If completely equal, skip this fileset
+	if local.TreeHash == remote.TreeHash {
+		st := logger.StartStep(log, "fileset_sync", name, "resource_kind", "fileset", "target_volume", fileset.TargetVolume)
+		st.OK(false) // No changes needed
+		return nil, nil
+	}
+
+	if fm.beforeSync != nil {
+		fm.mu.Lock()
+		fm.beforeSync(name)
+		fm.mu.Unlock()
+	}
+
+	// Determine apply mode (default hot)
+	isCold := fileset.ApplyMode == "cold"
+
+	// Compute target services to restart/stop based on restart_services semantics
+	targetServices, err := resolveTargetServices(ctx, fm.docker, withMountingServices(fileset, fm.restartMounting))
+	if err != nil {
+		return nil, apperr.Wrap("filesetmanager.SyncFilesetsForContext", apperr.External, err, "resolve target services for fileset %s", name)
+	}
 
-		// Determine apply mode (default hot)
-		isCold := fileset.ApplyMode == "cold"
+	// With --no-restart, cold-mode content is written under running
+	// containers; warn so the deferred stop/start is not silent.
+	if isCold && fm.noRestart && len(targetServices) > 0 {
+		log.Warn("fileset_cold_no_restart", "fileset", name, "services", targetServices,
+			"msg", "content changed under running containers; services were not stopped")
+	}
 
-		// Compute target services to restart/stop based on restart_services semantics
-		targetServices, err := resolveTargetServices(ctx, fm.docker, fileset)
+	// For cold mode, stop targets (if any) before syncing
+	var stoppedContainers []string
+	if isCold && !fm.noRestart && len(targetServices) > 0 {
+		if fm.progress != nil {
+			fm.progress.SetAction("stopping services for fileset " + name)
+		}
+		// Get all containers and find ones matching the target services
+		items, err := fm.docker.ListComposeContainersAll(ctx)
 		if err != nil {
-			return nil, apperr.Wrap("filesetmanager.SyncFilesetsForContext", apperr.External, err, "resolve target services for fileset %s", name)
+			return nil, apperr.Wrap("filesetmanager.SyncFilesetsForContext", apperr.External, err, "list compose containers for cold fileset %s", name)
 		}
-
-		// For cold mode, stop targets (if any) before syncing
-		var stoppedContainers []string
-		if isCold && len(targetServices) > 0 {
-			if fm.progress != nil {
-				fm.progress.SetAction("stopping services for fileset " + name)
-			}
-			// Get all containers and find ones matching the target services
-			items, err := fm.docker.ListComposeContainersAll(ctx)
-			if err != nil {
-				return nil, apperr.Wrap("filesetmanager.SyncFilesetsForContext", apperr.External, err, "list compose containers for cold fileset %s", name)
+		var containersToStop []dockercli.PsBrief
+		for _, svc := range targetServices {
+			if svc == "" {
+				continue
 			}
-			var containersToStop []string
-			for _, svc := range targetServices {
-				if svc == "" {
-					continue
-				}
-				for _, it := range items {
-					if it.Service == svc {
-						containersToStop = append(containersToStop, it.Name)
-						stoppedContainers = append(stoppedContainers, it.Name)
-						break
-					}
-				}
-			}
-			if len(containersToStop) > 0 {
-				if err := fm.docker.StopContainers(ctx, containersToStop); err != nil {
-					return nil, apperr.Wrap("filesetmanager.SyncFilesetsForContext", apperr.External, err, "stop cold-mode containers for fileset %s", name)
+			for _, it := range items {
+				if it.Service == svc {
+					containersToStop = append(containersToStop, it)
+					break
 				}
 			}
 		}
-
-		restartColdContainersOnFailure := func(baseErr error) error {
-			if !isCold || len(stoppedContainers) == 0 {
-				return baseErr
-			}
-			restartErr := fm.docker.StartContainers(ctx, stoppedContainers)
-			if restartErr == nil {
-				return baseErr
+		// Honor each service's stop_signal and stop_grace_period.
+		stopOpts := fm.coldStopOptions(ctx, cfg, contextName, execCtx, containersToStop)
+		for _, it := range containersToStop {
+			if err := fm.docker.StopContainers(ctx, []string{it.Name}, stopOpts[it.Name]); err != nil {
+				return nil, apperr.Wrap("filesetmanager.SyncFilesetsForContext", apperr.External, err, "stop cold-mode containers for fileset %s", name)
 			}
-			return apperr.Aggregate(
-				"filesetmanager.SyncFilesetsForContext",
-				apperr.External,
-				"fileset sync failed and cold-mode service restart also failed",
-				baseErr,
-				apperr.Wrap("filesetmanager.SyncFilesetsForContext", apperr.External, restartErr, "restart cold-mode containers for fileset %s", name),
-			)
+			stoppedContainers = append(stoppedContainers, it.Name)
 		}
+	}
 
-		// Start logging the sync operation
-		st := logger.StartStep(log, "fileset_sync", name,
-			"resource_kind", "fileset",
-			"target_volume", fileset.TargetVolume,
-			"apply_mode", fileset.ApplyMode,
-			"files_changed", len(diff.ToCreate)+len(diff.ToUpdate),
-			"files_deleted", len(diff.ToDelete))
-
-		// Sync files (create + update)
-		if err := fm.syncFilesetFiles(ctx, name, fileset, diff); err != nil {
-			return nil, st.Fail(restartColdContainersOnFailure(err))
+	restartColdContainersOnFailure := func(baseErr error) error {
+		if !isCold || len(stoppedContainers) == 0 {
+			return baseErr
 		}
-
-		// Delete removed files
-		if err := fm.deleteFilesetFiles(ctx, name, fileset, diff); err != nil {
-			return nil, st.Fail(restartColdContainersOnFailure(err))
+		restartErr := fm.docker.StartContainers(ctx, stoppedContainers)
+		if restartErr == nil {
+			return baseErr
 		}
+		return apperr.Aggregate(
+			"filesetmanager.SyncFilesetsForContext",
+			apperr.External,
+			"fileset sync failed and cold-mode service restart also failed",
+			baseErr,
+			apperr.Wrap("filesetmanager.SyncFilesetsForContext", apperr.External, restartErr, "restart cold-mode containers for fileset %s", name),
+		)
+	}
 
-		// Write updated index
-		if err := fm.writeFilesetIndex(ctx, name, fileset, local); err != nil {
-			return nil, st.Fail(restartColdContainersOnFailure(err))
-		}
+	// Start logging the sync operation
+	st := logger.StartStep(log, "fileset_sync", name,
+		"resource_kind", "fileset",
+		"target_volume", fileset.TargetVolume,
+		"apply_mode", fileset.ApplyMode,
+		"files_changed", len(diff.ToCreate)+len(diff.ToUpdate),
+		"files_deleted", len(diff.ToDelete))
+
+	// Sync files (create + update)
+	if err := fm.syncFilesetFiles(ctx, name, fileset, diff, renderer); err != nil {
+		return nil, st.Fail(restartColdContainersOnFailure(err))
+	}
 
-		// Apply ownership if configured
-		if err := fm.applyOwnership(ctx, name, fileset, diff); err != nil {
-			return nil, st.Fail(restartColdContainersOnFailure(err))
-		}
+	// Delete removed files
+	if err := fm.deleteFilesetFiles(ctx, name, fileset, diff); err != nil {
+		return nil, st.Fail(restartColdContainersOnFailure(err))
+	}
 
-		// For cold mode, start previously stopped containers again
-		if isCold && len(stoppedContainers) > 0 {
-			if fm.progress != nil {
-				fm.progress.SetAction("starting services for fileset " + name)
-			}
-			if err := fm.docker.StartContainers(ctx, stoppedContainers); err != nil {
-				return nil, st.Fail(apperr.Wrap("filesetmanager.SyncFilesetsForContext", apperr.External, err, "restart cold-mode containers for fileset %s", name))
-			}
-		}
+	// Write updated index
+	if err := fm.writeFilesetIndex(ctx, name, fileset, local); err != nil {
+		return nil, st.Fail(restartColdContainersOnFailure(err))
+	}
 
-		st.OK(true) // Fileset was successfully synced
+	// Apply ownership if configured
+	if err := fm.applyOwnership(ctx, name, fileset, diff); err != nil {
+		return nil, st.Fail(restartColdContainersOnFailure(err))
+	}
 
-		// Queue services for restart only for hot mode
-		if !isCold {
-			for _, svc := range targetServices {
-				if svc != "" {
-					restartPending[svc] = struct{}{}
-				}
-			}
+	// For cold mode, start previously stopped containers again
+	if isCold && len(stoppedContainers) > 0 {
+		if fm.progress != nil {
+			fm.progress.SetAction("starting services for fileset " + name)
+		}
+		if err := fm.docker.StartContainers(ctx, stoppedContainers); err != nil {
+			return nil, st.Fail(apperr.Wrap("filesetmanager.SyncFilesetsForContext", apperr.External, err, "restart cold-mode containers for fileset %s", name))
 		}
 	}
 
-	return restartPending, nil
+	st.OK(true) // Fileset was successfully synced
+
+	// Queue services for restart only for hot mode. With --no-restart, cold
+	// targets are queued too so the caller reports them as skipped.
+	if isCold && !fm.noRestart {
+		return nil, nil
+	}
+	var queued []string
+	for _, svc := range targetServices {
+		if svc != "" {
+			queued = append(queued, svc)
+		}
+	}
+	return queued, nil
 }
 
 // syncFilesetFiles handles create and update operations for fileset files.
-func (fm *FilesetManager) syncFilesetFiles(ctx context.Context, name string, fileset manifest.FilesetSpec, diff filesets.Diff) error {
+func (fm *FilesetManager) syncFilesetFiles(ctx context.Context, name string, fileset manifest.FilesetSpec, diff filesets.Diff, renderer *filesets.Renderer) error {
 	// Build tar for create+update
 	paths := make([]string, 0, len(diff.ToCreate)+len(diff.ToUpdate))
 	for _, f := range diff.ToCreate {
@@ -229,13 +376,33 @@ func (fm *FilesetManager) syncFilesetFiles(ctx context.Context, name string, fil
 		fm.progress.SetAction("syncing fileset " + name)
 	}
 
-	var buf bytes.Buffer
-	if err := util.TarFilesToWriter(fileset.SourceAbs, paths, &buf); err != nil {
-		return apperr.Wrap("filesetmanager.syncFilesetFiles", apperr.Internal, err, "build tar for fileset %s", name)
+	var transform func(rel string, data []byte) ([]byte, error)
+	if renderer != nil {
+		transform = renderer.Render
+	}
+
+	// Stream the archive into the helper container's stdin as it is built,
+	// so memory use does not grow with the fileset's size.
+	pr, pw := io.Pipe()
+	tarDone := make(chan error, 1)
+	go func() {
+		err := util.TarTransformedFilesToWriter(fileset.SourceAbs, paths, transform, pw)
+		_ = pw.CloseWithError(err)
+		tarDone <- err
+	}()
+	extractErr := fm.docker.ExtractTarToVolume(ctx, fileset.TargetVolume, fileset.TargetPath, pr)
+	// Unblock the writer if extraction stopped reading early.
+	_ = pr.CloseWithError(io.ErrClosedPipe)
+	tarErr := <-tarDone
+	if errors.Is(tarErr, io.ErrClosedPipe) {
+		tarErr = nil // extraction ended first; its result decides
 	}
 
-	if err := fm.docker.ExtractTarToVolume(ctx, fileset.TargetVolume, fileset.TargetPath, &buf); err != nil {
-		return apperr.Wrap("filesetmanager.syncFilesetFiles", apperr.External, err, "extract tar for fileset %s", name)
+	if tarErr != nil {
+		return apperr.Wrap("filesetmanager.syncFilesetFiles", apperr.Internal, tarErr, "build tar for fileset %s", name)
+	}
+	if extractErr != nil {
+		return apperr.Wrap("filesetmanager.syncFilesetFiles", apperr.External, extractErr, "extract tar for fileset %s", name)
 	}
 
 	return nil
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gcstr/dockform/internal/apperr"
 	"github.com/gcstr/dockform/internal/dockercli"
@@ -99,3 +100,30 @@ func TestSyncFilesetsForContext_ColdFailureRestartFailureReturnsAggregate(t *tes
 		t.Fatalf("expected external error kind, got: %v", err)
 	}
 }
+
+func TestSyncFilesetsForContext_ColdStopHonorsStopSignal(t *testing.T) {
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "index.html"), []byte("hello"), 0o644); err != nil {
+		t.Fatalf("write test file: %v", err)
+	}
+
+	cfg := coldFilesetConfig(t, src)
+	cfg.Stacks = map[string]manifest.Stack{"default/demo": {Root: "/stacks/demo", Files: []string{"compose.yml"}}}
+
+	mockDocker := newMockDocker()
+	mockDocker.containers = []dockercli.PsBrief{{Project: "demo", Service: "web", Name: "demo-web-1"}}
+	mockDocker.composeDocs = map[string]dockercli.ComposeConfigDoc{
+		"/stacks/demo": {Name: "demo", Services: map[string]dockercli.ComposeService{
+			"web": {Image: "nginx", StopSignal: "SIGQUIT", StopGrace: "1m30s"},
+		}},
+	}
+
+	fm := NewFilesetManager(mockDocker, nil)
+	if _, err := fm.SyncFilesetsForContext(context.Background(), cfg, "default", map[string]struct{}{"data": {}}, nil); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	want := dockercli.StopOptions{Signal: "SIGQUIT", Timeout: 90 * time.Second}
+	if got := mockDocker.stopOptions["demo-web-1"]; got != want {
+		t.Fatalf("stop options = %+v, want %+v", got, want)
+	}
+}
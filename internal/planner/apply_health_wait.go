@@ -0,0 +1,88 @@
+package planner
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/logger"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+// waitForStartedServices waits, when the stack sets health_wait, for every
+// replica of each service compose up created or recreated to become healthy.
+// Services in waited were already waited for by the health rollback and are
+// left out. Replicas are waited for concurrently with WaitHealthy, so the
+// timeout covers the whole stack; services that do not make it fail the stack
+// with their last state and health check output.
+func waitForStartedServices(ctx context.Context, client DockerClient, log logger.Logger, contextName, stackName string, stack manifest.Stack, proj string, inline []string, started []string, waited map[string]string, progress ProgressReporter) error {
+	if stack.HealthWait == "" {
+		return nil
+	}
+	timeout, err := time.ParseDuration(stack.HealthWait)
+	if err != nil {
+		return apperr.Wrap("planner.Apply", apperr.InvalidInput, err, "stack %s/%s: invalid health_wait %q", contextName, stackName, stack.HealthWait)
+	}
+	var services []string
+	for _, svc := range started {
+		if _, ok := waited[svc]; !ok {
+			services = append(services, svc)
+		}
+	}
+	if len(services) == 0 {
+		return nil
+	}
+	sort.Strings(services)
+
+	if progress != nil {
+		progress.SetAction("waiting for " + contextName + "/" + stackName + " to become healthy")
+	}
+	st := logger.StartStep(log, "stack_health_wait", stackName, "resource_kind", "stack", "services", strings.Join(services, ","), "timeout", timeout.String())
+
+	// A service without any container (e.g. scaled to zero) has nothing to
+	// wait for.
+	statuses, err := client.ComposeServiceStatuses(dockercli.WithStack(ctx, stack), stack.Root, stack.Files, stack.Profiles, stack.EnvFile, proj, services, inline)
+	if err != nil {
+		return st.Fail(apperr.Wrap("planner.Apply", apperr.External, err, "stack %s/%s: list containers to wait for", contextName, stackName))
+	}
+	var replicas []dockercli.ServiceStatus
+	for _, s := range statuses {
+		if s.Container != "" && s.State != "missing" {
+			replicas = append(replicas, s)
+		}
+	}
+
+	errs := make([]error, len(replicas))
+	var wg sync.WaitGroup
+	for i, r := range replicas {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = client.WaitHealthy(ctx, r.Container, timeout)
+		}()
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return st.Fail(apperr.Wrap("planner.Apply", apperr.Timeout, ctx.Err(), "stack %s/%s: wait for services to become healthy", contextName, stackName))
+	}
+
+	var failed, reasons []string
+	for i, r := range replicas {
+		if errs[i] == nil {
+			continue
+		}
+		if len(failed) == 0 || failed[len(failed)-1] != r.Service {
+			failed = append(failed, r.Service)
+		}
+		reasons = append(reasons, r.Service+": "+apperr.DeepestMessage(errs[i]))
+	}
+	if len(failed) == 0 {
+		st.OK(false)
+		return nil
+	}
+	return st.Fail(apperr.New("planner.Apply", apperr.External, "stack %s/%s: %s did not become healthy (%s)", contextName, stackName, strings.Join(failed, ", "), strings.Join(reasons, "; ")))
+}
@@ -0,0 +1,103 @@
+package planner
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+func applyWithHealthWait(t *testing.T, p *Planner, d *mockDockerClient, healthWait string) error {
+	t.Helper()
+	stacks := map[string]manifest.Stack{"app": {Root: t.TempDir(), Files: []string{"compose.yml"}, HealthWait: healthWait}}
+	execCtx := &ContextExecutionContext{
+		ContextName: "default",
+		Stacks: map[string]*StackExecutionData{
+			"app": {
+				Services: []ServiceInfo{
+					{Name: "web", State: ServiceDrifted, Container: &dockercli.ComposePsItem{Name: "app-web-1"}},
+					{Name: "db", State: ServiceMissing},
+					{Name: "cache", State: ServiceRunning, Container: &dockercli.ComposePsItem{Name: "app-cache-1"}},
+				},
+				NeedsApply: true,
+			},
+		},
+	}
+	p.results = &applyResults{}
+	return p.applyStackChangesForContext(context.Background(), manifest.Config{}, "default", stacks, "", d, nil, nil, execCtx)
+}
+
+func TestHealthWait_WaitsForStartedServices(t *testing.T) {
+	d := newMockDocker()
+	if err := applyWithHealthWait(t, NewWithDocker(d), d, "30s"); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	// The first request is the status check right after compose up.
+	if want := []string{"web,db,cache", "db,web"}; !slices.Equal(d.statusRequests, want) {
+		t.Fatalf("expected a wait for the created and recreated services only, got %v", d.statusRequests)
+	}
+	slices.Sort(d.healthWaits)
+	if want := []string{"db", "web"}; !slices.Equal(d.healthWaits, want) {
+		t.Fatalf("expected WaitHealthy for each started container, got %v", d.healthWaits)
+	}
+}
+
+func TestHealthWait_DisabledByDefault(t *testing.T) {
+	d := newMockDocker()
+	if err := applyWithHealthWait(t, NewWithDocker(d), d, ""); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if len(d.statusRequests) != 1 {
+		t.Fatalf("expected no health wait without health_wait, got %v", d.statusRequests)
+	}
+}
+
+func TestHealthWait_SkipsServicesTheRollbackWaitedFor(t *testing.T) {
+	d := newMockDocker()
+	if err := applyWithHealthWait(t, NewWithDocker(d).WithHealthRollback(time.Minute), d, "30s"); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if want := []string{"web,db,cache", "web", "db"}; !slices.Equal(d.statusRequests, want) {
+		t.Fatalf("expected web waited for once by the rollback and db by health_wait, got %v", d.statusRequests)
+	}
+}
+
+func TestHealthWait_UnhealthyReplicaFailsApply(t *testing.T) {
+	d := newMockDocker()
+	d.serviceStatuses = []dockercli.ServiceStatus{
+		{Service: "db", Container: "app-db-1", State: "running", Health: "healthy"},
+		{Service: "db", Container: "app-db-2", State: "running", Health: "unhealthy"},
+		{Service: "web", Container: "app-web-1", State: "running"},
+	}
+	d.healthWaitErrors = map[string]error{
+		"app-db-2": apperr.New("dockercli.WaitHealthy", apperr.Timeout, "container app-db-2 did not become healthy within 20ms: unhealthy; last health check: pg_isready: no response"),
+	}
+	err := applyWithHealthWait(t, NewWithDocker(d), d, "20ms")
+	want := "stack default/app: db did not become healthy (db: container app-db-2 did not become healthy within 20ms: unhealthy; last health check: pg_isready: no response)"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected error naming the unhealthy service, got: %v", err)
+	}
+	slices.Sort(d.healthWaits)
+	if want := []string{"app-db-1", "app-db-2", "app-web-1"}; !slices.Equal(d.healthWaits, want) {
+		t.Fatalf("expected every replica waited for, got %v", d.healthWaits)
+	}
+}
+
+func TestHealthWait_ServiceWithoutContainersIsDone(t *testing.T) {
+	d := newMockDocker()
+	d.serviceStatuses = []dockercli.ServiceStatus{
+		{Service: "web", Container: "app-web-1", State: "running"},
+		{Service: "db", State: "missing"},
+	}
+	if err := applyWithHealthWait(t, NewWithDocker(d), d, "20ms"); err != nil {
+		t.Fatalf("expected a service without containers to count as done, got: %v", err)
+	}
+	if want := []string{"app-web-1"}; !slices.Equal(d.healthWaits, want) {
+		t.Fatalf("expected only the running replica waited for, got %v", d.healthWaits)
+	}
+}
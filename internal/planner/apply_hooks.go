@@ -0,0 +1,87 @@
+package planner
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/logger"
+	"github.com/gcstr/dockform/internal/manifest"
+	"github.com/gcstr/dockform/internal/ui"
+	"github.com/gcstr/dockform/internal/util"
+)
+
+// runHostCommand executes a hook on the host. Tests replace it.
+var runHostCommand = func(ctx context.Context, dir string, env []string, argv []string) (string, error) {
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	return out.String(), err
+}
+
+// runStackHooks runs the hook commands of one phase (pre_apply or post_apply) for
+// a stack in order, stopping at the first failure. Output is captured in the log
+// and included in the error when a hook fails.
+func (p *Planner) runStackHooks(ctx context.Context, client DockerClient, contextName, stackName string, stack manifest.Stack, phase string, cmds [][]string, project string, inline []string) error {
+	if len(cmds) == 0 {
+		return nil
+	}
+	log := logger.FromContext(ctx).With("component", "hooks", "context", contextName, "stack", stackName)
+	pr := p.pr
+	if pr == nil {
+		pr = ui.NoopPrinter{}
+	}
+	stackKey := manifest.MakeStackKey(contextName, stackName)
+	service := ""
+	if stack.Hooks != nil {
+		service = stack.Hooks.Service
+	}
+
+	// Host hooks run with the stack's resolved environment: its env files
+	// merged with the inline variables and secrets. Compose provides it to
+	// service hooks itself.
+	hostEnv := inline
+	if service == "" && (stack.Environment == nil || !stack.Environment.Resolve) {
+		env, err := resolveStackEnv(stack, inline)
+		if err != nil {
+			return apperr.Wrap("planner.runStackHooks", apperr.InvalidInput, err, "%s hooks for stack %s: read env files: %v", phase, stackKey, err)
+		}
+		hostEnv = env
+	}
+
+	for i, argv := range cmds {
+		cmdline := strings.Join(argv, " ")
+		if p.dryRun != nil && service == "" {
+			p.dryRun.record(contextName, "run "+phase+" hook", "%s: %s", stackKey, cmdline)
+			continue
+		}
+
+		st := logger.StartStep(log, "stack_hook", stackKey, "phase", phase, "index", i, "command", cmdline)
+		pr.Info("running %s hook for %s: %s", phase, stackKey, cmdline)
+
+		var out string
+		var err error
+		if service != "" {
+			out, err = client.ComposeRun(dockercli.WithStack(ctx, stack), stack.Root, stack.Files, stack.Profiles, stack.EnvFile, project, service, argv, inline)
+		} else {
+			out, err = runHostCommand(ctx, stack.Root, hostEnv, argv)
+		}
+		if err != nil {
+			detail := strings.TrimSpace(out)
+			if detail != "" {
+				return st.Fail(apperr.Wrap("planner.runStackHooks", apperr.External, err, "%s hook %q for stack %s failed: %s", phase, cmdline, stackKey, util.Truncate(detail, 512)))
+			}
+			return st.Fail(apperr.Wrap("planner.runStackHooks", apperr.External, err, "%s hook %q for stack %s failed", phase, cmdline, stackKey))
+		}
+		st.OK(true, "output", util.Truncate(strings.TrimSpace(out), 2048))
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package planner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+func hookTestExecCtx() *ContextExecutionContext {
+	return &ContextExecutionContext{
+		ContextName: "default",
+		Stacks: map[string]*StackExecutionData{
+			"app": {
+				Services:   []ServiceInfo{{Name: "web", State: ServiceMissing}},
+				InlineEnv:  []string{"DB_URL=postgres://db"},
+				NeedsApply: true,
+			},
+		},
+	}
+}
+
+func TestApplyStackChanges_RunsHostHooksAroundComposeUp(t *testing.T) {
+	var calls []string
+	orig := runHostCommand
+	runHostCommand = func(ctx context.Context, dir string, env []string, argv []string) (string, error) {
+		calls = append(calls, strings.Join(argv, " ")+" env="+strings.Join(env, ","))
+		return "ok", nil
+	}
+	defer func() { runHostCommand = orig }()
+
+	d := newMockDocker()
+	stacks := map[string]manifest.Stack{"app": {
+		Root:  t.TempDir(),
+		Files: []string{"compose.yml"},
+		Hooks: &manifest.StackHooks{
+			PreApply:  [][]string{{"./migrate.sh", "up"}},
+			PostApply: [][]string{{"./flush-cache.sh"}},
+		},
+	}}
+
+	p := NewWithDocker(d)
+	if err := p.applyStackChangesForContext(context.Background(), manifest.Config{}, "default", stacks, "", d, nil, nil, hookTestExecCtx()); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	want := []string{"./migrate.sh up env=DB_URL=postgres://db", "./flush-cache.sh env=DB_URL=postgres://db"}
+	if strings.Join(calls, "|") != strings.Join(want, "|") {
+		t.Fatalf("hook calls = %v, want %v", calls, want)
+	}
+}
+
+func TestApplyStackChanges_PreApplyFailureSkipsStack(t *testing.T) {
+	orig := runHostCommand
+	runHostCommand = func(ctx context.Context, dir string, env []string, argv []string) (string, error) {
+		return "migration 42 failed", errors.New("exit status 1")
+	}
+	defer func() { runHostCommand = orig }()
+
+	d := newMockDocker()
+	d.serviceStatuses = nil
+	stacks := map[string]manifest.Stack{"app": {
+		Root:  t.TempDir(),
+		Files: []string{"compose.yml"},
+		Hooks: &manifest.StackHooks{PreApply: [][]string{{"./migrate.sh"}}},
+	}}
+
+	p := NewWithDocker(d)
+	p.results = &applyResults{}
+	err := p.applyStackChangesForContext(context.Background(), manifest.Config{}, "default", stacks, "", d, nil, nil, hookTestExecCtx())
+	if err == nil || !strings.Contains(err.Error(), "pre_apply hook") || !strings.Contains(err.Error(), "migration 42 failed") {
+		t.Fatalf("expected pre_apply failure with captured output, got: %v", err)
+	}
+	if len(p.ServiceResults()) != 0 {
+		t.Fatalf("compose up must not run after a failed pre_apply hook")
+	}
+}
+
+func TestApplyStackChanges_ServiceHooksUseComposeRun(t *testing.T) {
+	d := newMockDocker()
+	stacks := map[string]manifest.Stack{"app": {
+		Root:  t.TempDir(),
+		Files: []string{"compose.yml"},
+		Hooks: &manifest.StackHooks{Service: "web", PreApply: [][]string{{"rake", "db:migrate"}}},
+	}}
+
+	if err := NewWithDocker(d).applyStackChangesForContext(context.Background(), manifest.Config{}, "default", stacks, "", d, nil, nil, hookTestExecCtx()); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if len(d.composeRuns) != 1 || d.composeRuns[0] != "web: rake db:migrate" {
+		t.Fatalf("expected hook to run via compose run, got %v", d.composeRuns)
+	}
+}
+
+func TestApplyStackChanges_HostHooksSeeEnvFileValues(t *testing.T) {
+	var got []string
+	orig := runHostCommand
+	runHostCommand = func(ctx context.Context, dir string, env []string, argv []string) (string, error) {
+		got = env
+		return "ok", nil
+	}
+	defer func() { runHostCommand = orig }()
+
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "app.env"), []byte("MIGRATIONS_DIR=/srv/migrations\nDB_URL=postgres://from-file\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	d := newMockDocker()
+	stacks := map[string]manifest.Stack{"app": {
+		Root:    root,
+		Files:   []string{"compose.yml"},
+		EnvFile: []string{"app.env"},
+		Hooks:   &manifest.StackHooks{PreApply: [][]string{{"./migrate.sh"}}},
+	}}
+
+	if err := NewWithDocker(d).applyStackChangesForContext(context.Background(), manifest.Config{}, "default", stacks, "", d, nil, nil, hookTestExecCtx()); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	// The variable only the env file defines reaches the hook; inline wins
+	// over the env file.
+	if want := "MIGRATIONS_DIR=/srv/migrations,DB_URL=postgres://db"; strings.Join(got, ",") != want {
+		t.Fatalf("hook env = %v, want %s", got, want)
+	}
+}
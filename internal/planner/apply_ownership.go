@@ -2,6 +2,7 @@ package planner
 
 import (
 	"context"
+	"fmt"
 	"path"
 	"strings"
 
@@ -57,10 +58,16 @@ func (fm *FilesetManager) applyOwnership(ctx context.Context, name string, files
 		}
 	}
 
-	// Log all stderr output (warnings go here)
-	if result.Stderr != "" {
-		for _, line := range util.SplitNonEmptyLines(result.Stderr) {
-			log.Warn("ownership script warning", "fileset", name, "message", line)
+	// Log all stderr output (warnings go here); failed paths are collected
+	failures, other := parseOwnershipFailures(name, result.Stderr)
+	for _, line := range other {
+		log.Warn("ownership script warning", "fileset", name, "message", line)
+	}
+	if len(failures) > 0 {
+		log.Warn("ownership applied with failures", "fileset", name, "failures", len(failures))
+		fm.results.addOwnershipFailures(failures)
+		if fm.strictOwnership {
+			return apperr.New("filesetmanager.applyOwnership", apperr.External, "fileset %s: %s", name, DescribeOwnershipFailures(failures))
 		}
 	}
 
@@ -112,18 +119,23 @@ func buildOwnershipScript(targetPath string, ownership *manifest.Ownership, diff
 
 	var script strings.Builder
 	script.WriteString("set -e\n") // Exit on error
+	// Failed chmod/chown calls do not abort the script; each failed path is
+	// reported on stderr behind a marker so the caller can list them.
+	script.WriteString("ownership_failed() { printf '" + ownershipFailureMarker + "\\t%s\\t%s\\n' \"$1\" \"$2\" >&2; }\n")
 
 	// Resolve user and group IDs
 	var uid, gid string
-	if ownership.User != "" {
+	user := mappedID(ownership.UIDMap, ownership.User)
+	group := mappedID(ownership.GIDMap, ownership.Group)
+	if user != "" {
 		script.WriteString("# Resolve user ID\n")
 		// Check if numeric
-		if isNumeric(ownership.User) {
-			uid = ownership.User
+		if isNumeric(user) {
+			uid = user
 			script.WriteString("UID_VAL='" + shellEscape(uid) + "'\n")
 		} else {
 			// Try to resolve name (escape the username)
-			escapedUser := shellEscape(ownership.User)
+			escapedUser := shellEscape(user)
 			script.WriteString("if getent passwd '" + escapedUser + "' >/dev/null 2>&1; then\n")
 			script.WriteString("  UID_VAL=$(getent passwd '" + escapedUser + "' | cut -d: -f3)\n")
 			script.WriteString("else\n")
@@ -133,14 +145,14 @@ func buildOwnershipScript(targetPath string, ownership *manifest.Ownership, diff
 		}
 	}
 
-	if ownership.Group != "" {
+	if group != "" {
 		script.WriteString("# Resolve group ID\n")
-		if isNumeric(ownership.Group) {
-			gid = ownership.Group
+		if isNumeric(group) {
+			gid = group
 			script.WriteString("GID_VAL='" + shellEscape(gid) + "'\n")
 		} else {
 			// Try to resolve name (escape the group name)
-			escapedGroup := shellEscape(ownership.Group)
+			escapedGroup := shellEscape(group)
 			script.WriteString("if getent group '" + escapedGroup + "' >/dev/null 2>&1; then\n")
 			script.WriteString("  GID_VAL=$(getent group '" + escapedGroup + "' | cut -d: -f3)\n")
 			script.WriteString("else\n")
@@ -195,7 +207,7 @@ func buildPreserveExistingScript(script *strings.Builder, rootPath string, owner
 		escapedDirMode := shellEscape(ownership.DirMode)
 		for dir := range updatedDirs {
 			escapedDir := shellEscape(dir)
-			script.WriteString("[ -d '" + escapedDir + "' ] && chmod '" + escapedDirMode + "' '" + escapedDir + "' 2>/dev/null || true\n")
+			script.WriteString("[ -d '" + escapedDir + "' ] && { chmod '" + escapedDirMode + "' '" + escapedDir + "' 2>/dev/null || ownership_failed chmod '" + escapedDir + "'; }\n")
 		}
 	}
 
@@ -205,7 +217,7 @@ func buildPreserveExistingScript(script *strings.Builder, rootPath string, owner
 		escapedFileMode := shellEscape(ownership.FileMode)
 		for _, f := range updatedFiles {
 			escapedFile := shellEscape(f)
-			script.WriteString("[ -f '" + escapedFile + "' ] && chmod '" + escapedFileMode + "' '" + escapedFile + "' 2>/dev/null || true\n")
+			script.WriteString("[ -f '" + escapedFile + "' ] && { chmod '" + escapedFileMode + "' '" + escapedFile + "' 2>/dev/null || ownership_failed chmod '" + escapedFile + "'; }\n")
 		}
 	}
 
@@ -219,23 +231,25 @@ func buildPreserveExistingScript(script *strings.Builder, rootPath string, owner
 		}
 		for _, p := range allPaths {
 			escapedPath := shellEscape(p)
-			script.WriteString("  [ -e '" + escapedPath + "' ] && chown \"$UID_VAL:$GID_VAL\" '" + escapedPath + "' 2>/dev/null || true\n")
+			script.WriteString("  [ -e '" + escapedPath + "' ] && { chown \"$UID_VAL:$GID_VAL\" '" + escapedPath + "' 2>/dev/null || ownership_failed chown '" + escapedPath + "'; }\n")
 		}
 		script.WriteString("elif [ -n \"${UID_VAL:-}\" ]; then\n")
 		for _, p := range allPaths {
 			escapedPath := shellEscape(p)
-			script.WriteString("  [ -e '" + escapedPath + "' ] && chown \"$UID_VAL\" '" + escapedPath + "' 2>/dev/null || true\n")
+			script.WriteString("  [ -e '" + escapedPath + "' ] && { chown \"$UID_VAL\" '" + escapedPath + "' 2>/dev/null || ownership_failed chown '" + escapedPath + "'; }\n")
 		}
 		script.WriteString("elif [ -n \"${GID_VAL:-}\" ]; then\n")
 		for _, p := range allPaths {
 			escapedPath := shellEscape(p)
-			script.WriteString("  [ -e '" + escapedPath + "' ] && chown \":$GID_VAL\" '" + escapedPath + "' 2>/dev/null || true\n")
+			script.WriteString("  [ -e '" + escapedPath + "' ] && { chown \":$GID_VAL\" '" + escapedPath + "' 2>/dev/null || ownership_failed chown '" + escapedPath + "'; }\n")
 		}
 		script.WriteString("fi\n")
 	}
 }
 
 // buildRecursiveOwnershipScript generates ownership script for recursive mode.
+// Each change runs in bulk first; only when that fails is the subtree walked
+// again path by path, so the paths that could not be changed get reported.
 func buildRecursiveOwnershipScript(script *strings.Builder, rootPath string, ownership *manifest.Ownership) {
 	script.WriteString("# Apply to entire subtree\n")
 	escapedRootPath := shellEscape(rootPath)
@@ -243,27 +257,45 @@ func buildRecursiveOwnershipScript(script *strings.Builder, rootPath string, own
 	// Apply directory mode
 	if ownership.DirMode != "" {
 		escapedDirMode := shellEscape(ownership.DirMode)
-		script.WriteString("find '" + escapedRootPath + "' -type d -exec chmod '" + escapedDirMode + "' {} + 2>/dev/null || true\n")
+		script.WriteString("find '" + escapedRootPath + "' -type d -exec chmod '" + escapedDirMode + "' {} + 2>/dev/null || " +
+			perPathFallback("find '"+escapedRootPath+"' -type d", "chmod", "'"+escapedDirMode+"'") + "\n")
 	}
 
 	// Apply file mode
 	if ownership.FileMode != "" {
 		escapedFileMode := shellEscape(ownership.FileMode)
-		script.WriteString("find '" + escapedRootPath + "' -type f -exec chmod '" + escapedFileMode + "' {} + 2>/dev/null || true\n")
+		script.WriteString("find '" + escapedRootPath + "' -type f -exec chmod '" + escapedFileMode + "' {} + 2>/dev/null || " +
+			perPathFallback("find '"+escapedRootPath+"' -type f", "chmod", "'"+escapedFileMode+"'") + "\n")
 	}
 
 	// Apply ownership recursively
 	if ownership.User != "" || ownership.Group != "" {
+		list := "find '" + escapedRootPath + "'"
 		script.WriteString("if [ -n \"${UID_VAL:-}\" ] && [ -n \"${GID_VAL:-}\" ]; then\n")
-		script.WriteString("  chown -R \"$UID_VAL:$GID_VAL\" '" + escapedRootPath + "' 2>/dev/null || true\n")
+		script.WriteString("  chown -R \"$UID_VAL:$GID_VAL\" '" + escapedRootPath + "' 2>/dev/null || " + perPathFallback(list, "chown", "\"$UID_VAL:$GID_VAL\"") + "\n")
 		script.WriteString("elif [ -n \"${UID_VAL:-}\" ]; then\n")
-		script.WriteString("  chown -R \"$UID_VAL\" '" + escapedRootPath + "' 2>/dev/null || true\n")
+		script.WriteString("  chown -R \"$UID_VAL\" '" + escapedRootPath + "' 2>/dev/null || " + perPathFallback(list, "chown", "\"$UID_VAL\"") + "\n")
 		script.WriteString("elif [ -n \"${GID_VAL:-}\" ]; then\n")
-		script.WriteString("  chown -R \":$GID_VAL\" '" + escapedRootPath + "' 2>/dev/null || true\n")
+		script.WriteString("  chown -R \":$GID_VAL\" '" + escapedRootPath + "' 2>/dev/null || " + perPathFallback(list, "chown", "\":$GID_VAL\"") + "\n")
 		script.WriteString("fi\n")
 	}
 }
 
+// perPathFallback renders a loop that runs op with arg on every path printed
+// by list, reporting each path it fails on.
+func perPathFallback(list, op, arg string) string {
+	return list + " | while IFS= read -r p; do " + op + " " + arg + " \"$p\" 2>/dev/null || ownership_failed " + op + " \"$p\"; done"
+}
+
+// mappedID returns the numeric ID a uid_map or gid_map assigns to name, or name
+// itself when it is not mapped.
+func mappedID(ids map[string]string, name string) string {
+	if id, ok := ids[name]; ok && id != "" {
+		return id
+	}
+	return name
+}
+
 // isNumeric checks if a string contains only digits.
 func isNumeric(s string) bool {
 	if s == "" {
@@ -282,3 +314,54 @@ func isNumeric(s string) bool {
 func shellEscape(s string) string {
 	return util.ShellEscape(s)
 }
+
+// ownershipFailureMarker prefixes the stderr lines the ownership script writes
+// for each path it could not change: marker, operation and path, tab separated.
+const ownershipFailureMarker = "DOCKFORM_OWNERSHIP_FAILED"
+
+// OwnershipFailure is a fileset path whose mode or owner could not be applied.
+type OwnershipFailure struct {
+	Fileset string
+	Op      string // chmod or chown
+	Path    string
+}
+
+// parseOwnershipFailures splits the ownership script's stderr into the failed
+// paths it reported and the remaining lines.
+func parseOwnershipFailures(fileset, stderr string) ([]OwnershipFailure, []string) {
+	var failures []OwnershipFailure
+	var other []string
+	for _, line := range util.SplitNonEmptyLines(stderr) {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) == 3 && parts[0] == ownershipFailureMarker {
+			failures = append(failures, OwnershipFailure{Fileset: fileset, Op: parts[1], Path: parts[2]})
+			continue
+		}
+		other = append(other, line)
+	}
+	return failures, other
+}
+
+// maxListedOwnershipFailures caps the paths DescribeOwnershipFailures lists.
+const maxListedOwnershipFailures = 5
+
+// DescribeOwnershipFailures summarizes failed paths for the user, e.g.
+// "ownership applied with 2 failures on paths: /app/a (chown), /app/b (chmod)".
+func DescribeOwnershipFailures(failures []OwnershipFailure) string {
+	listed := make([]string, 0, maxListedOwnershipFailures)
+	for i, f := range failures {
+		if i == maxListedOwnershipFailures {
+			break
+		}
+		listed = append(listed, fmt.Sprintf("%s (%s)", f.Path, f.Op))
+	}
+	noun := "failures"
+	if len(failures) == 1 {
+		noun = "failure"
+	}
+	msg := fmt.Sprintf("ownership applied with %d %s on paths: %s", len(failures), noun, strings.Join(listed, ", "))
+	if extra := len(failures) - len(listed); extra > 0 {
+		msg += fmt.Sprintf(" and %d more", extra)
+	}
+	return msg
+}
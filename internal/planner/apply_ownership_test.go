@@ -65,10 +65,10 @@ func TestBuildOwnershipScript_PreserveExistingTargetsOnlyChangedPaths(t *testing
 	if strings.Contains(script, "chown -R") {
 		t.Fatalf("preserve_existing should not use recursive chown, got:\n%s", script)
 	}
-	if !strings.Contains(script, "[ -f '/app/a.txt' ] && chmod '0644' '/app/a.txt'") {
+	if !strings.Contains(script, "[ -f '/app/a.txt' ] && { chmod '0644' '/app/a.txt' 2>/dev/null || ownership_failed chmod '/app/a.txt'; }") {
 		t.Fatalf("expected chmod for created file, got:\n%s", script)
 	}
-	if !strings.Contains(script, "[ -f '/app/nested/b.txt' ] && chmod '0644' '/app/nested/b.txt'") {
+	if !strings.Contains(script, "[ -f '/app/nested/b.txt' ] && { chmod '0644' '/app/nested/b.txt' 2>/dev/null || ownership_failed chmod '/app/nested/b.txt'; }") {
 		t.Fatalf("expected chmod for nested file, got:\n%s", script)
 	}
 	if !strings.Contains(script, "chown \"$UID_VAL:$GID_VAL\" '/app/c.txt'") {
@@ -130,3 +130,70 @@ func TestApplyOwnership_ReturnsErrorWhenScriptExecutionFails(t *testing.T) {
 		t.Fatalf("expected one volume script run, got runs=%d", mockDocker.runVolumeScriptRuns)
 	}
 }
+
+func TestBuildOwnershipScript_RecursiveModeReportsFailedPaths(t *testing.T) {
+	script, err := buildOwnershipScript("/app", &manifest.Ownership{User: "1000", FileMode: "0644"}, filesets.Diff{})
+	if err != nil {
+		t.Fatalf("build script: %v", err)
+	}
+	if strings.Contains(script, "|| true") {
+		t.Fatalf("failures must be reported, not swallowed:\n%s", script)
+	}
+	if !strings.Contains(script, "-exec chmod '0644' {} + 2>/dev/null || find '/app' -type f | while IFS= read -r p; do chmod '0644' \"$p\" 2>/dev/null || ownership_failed chmod \"$p\"; done") {
+		t.Fatalf("expected a per-path fallback after the bulk chmod, got:\n%s", script)
+	}
+}
+
+func TestParseOwnershipFailures(t *testing.T) {
+	stderr := "DOCKFORM_OWNERSHIP_FAILED\tchown\t/app/a b.txt\nsome warning\nDOCKFORM_OWNERSHIP_FAILED\tchmod\t/app/c\n"
+	failures, other := parseOwnershipFailures("assets", stderr)
+	if len(failures) != 2 || failures[0] != (OwnershipFailure{Fileset: "assets", Op: "chown", Path: "/app/a b.txt"}) || failures[1].Op != "chmod" {
+		t.Fatalf("unexpected failures %+v", failures)
+	}
+	if len(other) != 1 || other[0] != "some warning" {
+		t.Fatalf("expected unmarked lines passed through, got %v", other)
+	}
+	if got := DescribeOwnershipFailures(failures); got != "ownership applied with 2 failures on paths: /app/a b.txt (chown), /app/c (chmod)" {
+		t.Fatalf("unexpected description %q", got)
+	}
+}
+
+func TestApplyOwnership_PartialFailures(t *testing.T) {
+	spec := manifest.FilesetSpec{TargetVolume: "data", TargetPath: "/app", Ownership: &manifest.Ownership{User: "1000"}}
+
+	mockDocker := newMockDocker()
+	mockDocker.runVolumeScriptStderr = "DOCKFORM_OWNERSHIP_FAILED\tchown\t/app/locked\n"
+	results := &applyResults{}
+	fm := NewFilesetManager(mockDocker, nil).withResults(results)
+	if err := fm.applyOwnership(context.Background(), "assets", spec, filesets.Diff{}); err != nil {
+		t.Fatalf("expected failures to be reported, not returned: %v", err)
+	}
+	if len(results.ownership) != 1 || results.ownership[0].Path != "/app/locked" {
+		t.Fatalf("expected the failed path recorded, got %+v", results.ownership)
+	}
+
+	fm = NewFilesetManager(mockDocker, nil).WithStrictOwnership(true)
+	err := fm.applyOwnership(context.Background(), "assets", spec, filesets.Diff{})
+	if err == nil || !strings.Contains(err.Error(), "ownership applied with 1 failure on paths: /app/locked (chown)") {
+		t.Fatalf("expected strict ownership to fail with the path, got %v", err)
+	}
+}
+
+func TestBuildOwnershipScript_UsesIDMapsInsteadOfGetent(t *testing.T) {
+	ownership := &manifest.Ownership{
+		User:   "app",
+		Group:  "staff",
+		UIDMap: map[string]string{"app": "10001"},
+		GIDMap: map[string]string{"other": "20000"},
+	}
+	script, err := buildOwnershipScript("/app", ownership, filesets.Diff{})
+	if err != nil {
+		t.Fatalf("build script: %v", err)
+	}
+	if !strings.Contains(script, "UID_VAL='10001'") || strings.Contains(script, "getent passwd") {
+		t.Fatalf("expected mapped numeric uid without getent, got:\n%s", script)
+	}
+	if !strings.Contains(script, "getent group 'staff'") {
+		t.Fatalf("expected unmapped group to be resolved with getent, got:\n%s", script)
+	}
+}
@@ -0,0 +1,66 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+// upCountingDocker records how many compose ups ran before each removal.
+type upCountingDocker struct {
+	*mockDockerClient
+}
+
+func (d upCountingDocker) RemoveContainer(ctx context.Context, name string, force bool) error {
+	return d.mockDockerClient.RemoveContainer(ctx, fmt.Sprintf("%s@%d", name, d.composeUps), force)
+}
+
+func TestApplyStackChanges_PruneEachStack(t *testing.T) {
+	d := upCountingDocker{mockDockerClient: newMockDocker()}
+	rootA, rootB := t.TempDir(), t.TempDir()
+	d.composeDocs = map[string]dockercli.ComposeConfigDoc{
+		rootA: {Name: "a", Services: map[string]dockercli.ComposeService{"web": {}}},
+		rootB: {Name: "b", Services: map[string]dockercli.ComposeService{"api": {}}},
+	}
+	d.containers = []dockercli.PsBrief{
+		{Project: "a", Service: "web", Name: "a-web-1"},
+		{Project: "a", Service: "old", Name: "a-old-1"},
+		{Project: "b", Service: "old", Name: "b-old-1"},
+		{Project: "b", Service: "debug", Name: "b-debug-1"},
+		{Project: "other", Service: "old", Name: "other-old-1"},
+	}
+	stacks := map[string]manifest.Stack{
+		"a": {Root: rootA, Files: []string{"compose.yml"}},
+		"b": {Root: rootB, Files: []string{"compose.yml"}, IgnoreServices: []string{"debug"}},
+	}
+	execCtx := &ContextExecutionContext{
+		ContextName: "default",
+		Stacks: map[string]*StackExecutionData{
+			"a": {Services: []ServiceInfo{{Name: "web", State: ServiceMissing}}, NeedsApply: true},
+			"b": {Services: []ServiceInfo{{Name: "api", State: ServiceMissing}}, NeedsApply: true},
+		},
+	}
+
+	p := NewWithDocker(d.mockDockerClient)
+	if err := p.applyStackChangesForContext(context.Background(), manifest.Config{}, "default", stacks, "", d, nil, nil, execCtx); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if len(d.removedContainers) != 0 {
+		t.Fatalf("expected no per-stack prune by default, got %v", d.removedContainers)
+	}
+
+	d.composeUps = 0
+	p = NewWithDocker(d.mockDockerClient).WithPruneEachStack(CleanupOptions{Strict: true})
+	if err := p.applyStackChangesForContext(context.Background(), manifest.Config{}, "default", stacks, "", d, nil, nil, execCtx); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	// Each stack's orphans go right after its own compose up.
+	want := []string{"a-old-1@1", "b-old-1@2"}
+	if !reflect.DeepEqual(d.removedContainers, want) {
+		t.Fatalf("expected %v removed, got %v", want, d.removedContainers)
+	}
+}
@@ -0,0 +1,79 @@
+package planner
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+func relabelMock() (*mockDockerClient, manifest.Config) {
+	d := newMockDocker()
+	d.composePsItems = []dockercli.ComposePsItem{
+		{ID: "a1", Name: "website-nginx-1", Service: "nginx", State: "running"},
+		{ID: "b2", Name: "website-php-1", Service: "php", State: "running"},
+	}
+	d.containerLabels = map[string]map[string]string{
+		"website-nginx-1": {"com.docker.compose.config-hash": "mock-hash", "io.dockform.identifier": "test-id"},
+		"website-php-1":   {"com.docker.compose.config-hash": "mock-hash", "io.dockform.identifier": "test-id"},
+	}
+	d.composeDocs = map[string]dockercli.ComposeConfigDoc{
+		"/tmp/website": {Services: map[string]dockercli.ComposeService{"nginx": {}, "php": {}}},
+	}
+	cfg := manifest.Config{
+		Identifier: "test-id",
+		Contexts:   map[string]manifest.ContextConfig{"default": {}},
+		Stacks: map[string]manifest.Stack{
+			"default/website": {Root: "/tmp/website", Files: []string{"compose.yaml"}, Labels: map[string]string{"team": "payments"}},
+		},
+	}
+	return d, cfg
+}
+
+func TestApply_UpToDateStackNotRecreatedByDefault(t *testing.T) {
+	d, cfg := relabelMock()
+	p := NewWithDocker(d)
+	if _, err := p.Apply(context.Background(), cfg); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if d.composeUps != 0 || len(d.composeRecreates) != 0 {
+		t.Fatalf("expected the up-to-date stack to be left alone, got %d ups and recreates %v", d.composeUps, d.composeRecreates)
+	}
+	if n := p.RelabeledContainers(); n != 0 {
+		t.Fatalf("expected no relabeled containers, got %d", n)
+	}
+}
+
+func TestApply_RelabelAllRecreatesUpToDateServices(t *testing.T) {
+	d, cfg := relabelMock()
+	p := NewWithDocker(d).WithRelabelAll(true)
+	if _, err := p.Apply(context.Background(), cfg); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if d.composeUps != 0 {
+		t.Fatalf("expected the up-to-date stack not to be brought up, got %d ups", d.composeUps)
+	}
+	if !slices.Equal(d.composeRecreates, []string{"nginx,php"}) {
+		t.Fatalf("expected both services to be recreated once, got %v", d.composeRecreates)
+	}
+	if n := p.RelabeledContainers(); n != 2 {
+		t.Fatalf("expected every managed container to be recreated, got %d", n)
+	}
+}
+
+func TestApply_RelabelAllCountsOnlyReplacedContainers(t *testing.T) {
+	d, cfg := relabelMock()
+	d.recreateKeepsIDs = true
+	p := NewWithDocker(d).WithRelabelAll(true)
+	if _, err := p.Apply(context.Background(), cfg); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(d.composeRecreates) != 1 {
+		t.Fatalf("expected the services to be recreated once, got %v", d.composeRecreates)
+	}
+	if n := p.RelabeledContainers(); n != 0 {
+		t.Fatalf("expected containers compose left in place not to be counted, got %d", n)
+	}
+}
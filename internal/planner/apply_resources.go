@@ -93,7 +93,7 @@ func (rm *ResourceManager) EnsureNetworksExistForContext(ctx context.Context, cf
 	}
 
 	// Get desired networks for this context
-	for netName := range contextConfig.Networks {
+	for netName, spec := range contextConfig.Networks {
 		if _, exists := existingNetworks[netName]; exists {
 			continue // Already exists
 		}
@@ -105,7 +105,7 @@ func (rm *ResourceManager) EnsureNetworksExistForContext(ctx context.Context, cf
 		st := logger.StartStep(log, "network_create", netName,
 			"resource_kind", "network")
 
-		if err := rm.docker.CreateNetwork(ctx, netName, labels); err != nil {
+		if err := rm.docker.CreateNetwork(ctx, netName, labels, networkCreateOpts(spec)); err != nil {
 			return st.Fail(apperr.Wrap("resourcemanager.EnsureNetworksExistForContext", apperr.External, err, "create network %s", netName))
 		}
 
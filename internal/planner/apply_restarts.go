@@ -2,9 +2,13 @@ package planner
 
 import (
 	"context"
+	"sort"
+	"strings"
 
 	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/dockercli"
 	"github.com/gcstr/dockform/internal/logger"
+	"github.com/gcstr/dockform/internal/manifest"
 	"github.com/gcstr/dockform/internal/ui"
 )
 
@@ -13,6 +17,14 @@ type RestartManager struct {
 	docker   DockerClient
 	printer  ui.Printer
 	progress ProgressReporter
+	// deps maps each compose service to the services it depends_on, so
+	// dependencies are restarted before their dependents.
+	deps map[string][]string
+	// projects, when set, limits restarts to containers of these compose
+	// projects, so a targeted apply leaves other stacks' containers alone.
+	projects map[string]struct{}
+	// restarted records the containers restarted so far.
+	restarted []dockercli.PsBrief
 }
 
 // NewRestartManager creates a new restart manager.
@@ -25,7 +37,26 @@ func NewRestartManagerWithClient(client DockerClient, printer ui.Printer, progre
 	return &RestartManager{docker: client, printer: printer, progress: progress}
 }
 
-// RestartPendingServices restarts all services queued for restart after fileset updates.
+// WithDependencies sets the compose depends_on graph used to order restarts.
+func (rm *RestartManager) WithDependencies(deps map[string][]string) *RestartManager {
+	rm.deps = deps
+	return rm
+}
+
+// WithProjects limits restarts to containers of the given compose projects.
+func (rm *RestartManager) WithProjects(projects map[string]struct{}) *RestartManager {
+	rm.projects = projects
+	return rm
+}
+
+// Restarted returns the containers RestartPendingServices restarted.
+func (rm *RestartManager) Restarted() []dockercli.PsBrief {
+	return rm.restarted
+}
+
+// RestartPendingServices restarts all services queued for restart after fileset
+// updates, dependencies first. If the dependency graph has a cycle the services
+// are restarted in name order instead.
 func (rm *RestartManager) RestartPendingServices(ctx context.Context, restartPending map[string]struct{}) error {
 	if len(restartPending) == 0 {
 		return nil
@@ -45,10 +76,21 @@ func (rm *RestartManager) RestartPendingServices(ctx context.Context, restartPen
 		pr = ui.NoopPrinter{}
 	}
 
+	order, cycle := restartOrder(rm.deps, restartPending)
+	if cycle != nil {
+		pr.Warn("dependency cycle between %s; restarting in name order", strings.Join(cycle, ", "))
+		log.Warn("restart_dependency_cycle", "services", cycle)
+	}
+
 	// Restart each pending service
-	for svc := range restartPending {
+	for _, svc := range order {
 		found := false
 		for _, it := range items {
+			if rm.projects != nil {
+				if _, ok := rm.projects[it.Project]; !ok {
+					continue
+				}
+			}
 			if it.Service == svc {
 				found = true
 				st := logger.StartStep(log, "service_restart", svc, "resource_kind", "service", "container", it.Name)
@@ -62,6 +104,7 @@ func (rm *RestartManager) RestartPendingServices(ctx context.Context, restartPen
 					return st.Fail(apperr.Wrap("restartmanager.RestartPendingServices", apperr.External, err, "restart service %s", svc))
 				}
 
+				rm.restarted = append(rm.restarted, it)
 				st.OK(true)
 				break
 			}
@@ -76,3 +119,112 @@ func (rm *RestartManager) RestartPendingServices(ctx context.Context, restartPen
 
 	return nil
 }
+
+// restartDependencies returns the depends_on graph of every stack in the
+// context, keyed by service name as fileset restart_services are, along with
+// the stack each compose project belongs to. Stacks whose compose config
+// cannot be resolved are left out, so their services restart without ordering.
+func restartDependencies(ctx context.Context, cfg manifest.Config, contextName string, client DockerClient, execCtx *ContextExecutionContext) (map[string][]string, map[string]string) {
+	log := logger.FromContext(ctx).With("component", "restart", "context", contextName)
+	deps := map[string][]string{}
+	projects := map[string]string{}
+	stacks := cfg.GetStacksForContext(contextName)
+	for _, stackName := range sortedKeys(stacks) {
+		stack := stacks[stackName]
+		var inline []string
+		if execCtx != nil && execCtx.Stacks[stackName] != nil {
+			inline = execCtx.Stacks[stackName].InlineEnv
+		} else {
+			env, err := NewServiceStateDetector(client).BuildInlineEnv(ctx, stack, cfg.Sops)
+			if err != nil {
+				log.Debug("restart_dependencies_skipped", "stack", stackName, "error", err.Error())
+				continue
+			}
+			inline = env
+		}
+		doc, err := client.ComposeConfigFull(dockercli.WithStack(ctx, stack), stack.Root, stack.Files, stack.Profiles, stack.EnvFile, inline)
+		if err != nil {
+			log.Debug("restart_dependencies_skipped", "stack", stackName, "error", err.Error())
+			continue
+		}
+		for name, svc := range doc.Services {
+			deps[name] = append(deps[name], svc.DependsOn...)
+		}
+		project := doc.Name
+		if stack.Project != nil && stack.Project.Name != "" {
+			project = stack.Project.Name
+		}
+		if project != "" {
+			projects[project] = stackName
+		}
+	}
+	return deps, projects
+}
+
+// restartOrder returns the pending services so that each one comes after the
+// pending services it depends on, directly or through services that are not
+// being restarted. When the dependencies reachable from the pending services
+// form a cycle, it returns them in name order along with the services on the
+// cycle.
+func restartOrder(deps map[string][]string, pending map[string]struct{}) ([]string, []string) {
+	names := make([]string, 0, len(pending))
+	for name := range pending {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if cycle := dependencyCycle(deps, names); cycle != nil {
+		return names, cycle
+	}
+
+	// DependencyOrder only orders services that are keys of the graph, so
+	// pending services compose does not list are added without dependencies.
+	graph := make(map[string][]string, len(deps)+len(names))
+	for name, d := range deps {
+		graph[name] = d
+	}
+	for _, name := range names {
+		if _, ok := graph[name]; !ok {
+			graph[name] = nil
+		}
+	}
+	return DependencyOrder(graph, names), nil
+}
+
+// dependencyCycle returns the services on the first depends_on cycle reachable
+// from roots, sorted by name, or nil when there is none.
+func dependencyCycle(deps map[string][]string, roots []string) []string {
+	state := map[string]int{} // 1 = visiting, 2 = done
+	var path []string
+	var cycle []string
+	var visit func(name string) bool
+	visit = func(name string) bool {
+		state[name] = 1
+		path = append(path, name)
+		for _, dep := range deps[name] {
+			switch state[dep] {
+			case 1:
+				for i, s := range path {
+					if s == dep {
+						cycle = append([]string(nil), path[i:]...)
+						sort.Strings(cycle)
+						return true
+					}
+				}
+			case 0:
+				if visit(dep) {
+					return true
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = 2
+		return false
+	}
+	for _, name := range roots {
+		if state[name] == 0 && visit(name) {
+			return cycle
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package planner
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/gcstr/dockform/internal/dockercli"
@@ -158,3 +159,68 @@ func TestRestartManager_RestartPendingServices_WithMock(t *testing.T) {
 		})
 	}
 }
+
+func TestRestartManager_RestartsInDependencyOrder(t *testing.T) {
+	mockDocker := newMockDocker()
+	mockDocker.containers = []dockercli.PsBrief{
+		{Service: "app", Name: "app-1"},
+		{Service: "db", Name: "db-1"},
+		{Service: "worker", Name: "worker-1"},
+	}
+	// app depends on db through cache, which is not being restarted.
+	deps := map[string][]string{
+		"app":    {"cache"},
+		"cache":  {"db"},
+		"db":     nil,
+		"worker": {"app"},
+	}
+	pending := map[string]struct{}{"worker": {}, "app": {}, "db": {}}
+
+	if err := NewRestartManager(mockDocker, nil, nil).WithDependencies(deps).RestartPendingServices(context.Background(), pending); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(mockDocker.restartedContainers, ","); got != "db-1,app-1,worker-1" {
+		t.Fatalf("restart order = %s, want db-1,app-1,worker-1", got)
+	}
+}
+
+func TestRestartManager_WithProjectsLeavesOtherProjectsAlone(t *testing.T) {
+	mockDocker := newMockDocker()
+	mockDocker.containers = []dockercli.PsBrief{
+		{Project: "admin", Service: "web", Name: "admin-web-1"},
+		{Project: "shop", Service: "web", Name: "shop-web-1"},
+	}
+	projects := map[string]struct{}{"shop": {}}
+
+	if err := NewRestartManager(mockDocker, nil, nil).WithProjects(projects).RestartPendingServices(context.Background(), map[string]struct{}{"web": {}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(mockDocker.restartedContainers, ","); got != "shop-web-1" {
+		t.Fatalf("restarted = %s, want shop-web-1", got)
+	}
+}
+
+func TestRestartOrder_CycleFallsBackToNameOrder(t *testing.T) {
+	deps := map[string][]string{
+		"web": {"api"},
+		"api": {"web"},
+		"db":  nil,
+	}
+	pending := map[string]struct{}{"web": {}, "db": {}, "extra": {}}
+
+	order, cycle := restartOrder(deps, pending)
+	if got := strings.Join(order, ","); got != "db,extra,web" {
+		t.Fatalf("order = %s, want db,extra,web", got)
+	}
+	if got := strings.Join(cycle, ","); got != "api,web" {
+		t.Fatalf("cycle = %s, want api,web", got)
+	}
+
+	order, cycle = restartOrder(map[string][]string{"web": {"db"}}, map[string]struct{}{"web": {}, "db": {}, "alpha": {}})
+	if cycle != nil {
+		t.Fatalf("unexpected cycle %v", cycle)
+	}
+	if got := strings.Join(order, ","); got != "alpha,db,web" {
+		t.Fatalf("order = %s, want alpha,db,web", got)
+	}
+}
@@ -0,0 +1,186 @@
+package planner
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+// ServiceApplyResult is the observed outcome of a service after compose up.
+type ServiceApplyResult struct {
+	Context string
+	Stack   string
+	dockercli.ServiceStatus
+}
+
+// StackKey returns the "context/stack" key the result belongs to.
+func (r ServiceApplyResult) StackKey() string { return manifest.MakeStackKey(r.Context, r.Stack) }
+
+// StackApplyResult is the outcome of applying one stack. Services are split by
+// what apply did to them: Created had no container, Updated were recreated or
+// started, Skipped were already up to date, and Restarted were restarted after
+// a fileset they mount changed. Err is set when the stack failed to apply.
+type StackApplyResult struct {
+	Context   string
+	Stack     string
+	Created   []string
+	Updated   []string
+	Skipped   []string
+	Restarted []string
+	Duration  time.Duration
+	Err       error
+}
+
+// StackKey returns the "context/stack" key the result belongs to.
+func (r StackApplyResult) StackKey() string { return manifest.MakeStackKey(r.Context, r.Stack) }
+
+// Changed reports whether apply created or updated any service of the stack.
+func (r StackApplyResult) Changed() bool { return len(r.Created) > 0 || len(r.Updated) > 0 }
+
+// applyResults collects per-service results across concurrently applied contexts.
+type applyResults struct {
+	mu        sync.Mutex
+	stacks    []StackApplyResult
+	services  []ServiceApplyResult
+	relabeled int
+	ownership []OwnershipFailure
+}
+
+func (r *applyResults) addServices(contextName, stackName string, statuses []dockercli.ServiceStatus) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range statuses {
+		r.services = append(r.services, ServiceApplyResult{Context: contextName, Stack: stackName, ServiceStatus: s})
+	}
+}
+
+func (r *applyResults) addStack(res StackApplyResult) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stacks = append(r.stacks, res)
+}
+
+// addRestarted attributes the containers restarted after fileset updates to
+// the stacks of contextName whose compose projects they belong to. Containers
+// of projects that are not a stack of the context are left out.
+func (r *applyResults) addRestarted(contextName string, restarted []dockercli.PsBrief, stackByProject map[string]string) {
+	if r == nil || len(restarted) == 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, it := range restarted {
+		stackName, ok := stackByProject[it.Project]
+		if !ok {
+			continue
+		}
+		idx := -1
+		for i := range r.stacks {
+			if r.stacks[i].Context == contextName && r.stacks[i].Stack == stackName {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			// Only a fileset of the stack changed, so apply left it alone
+			// until now.
+			r.stacks = append(r.stacks, StackApplyResult{Context: contextName, Stack: stackName})
+			idx = len(r.stacks) - 1
+		}
+		res := &r.stacks[idx]
+		if !slices.Contains(res.Restarted, it.Service) {
+			res.Restarted = append(res.Restarted, it.Service)
+			sort.Strings(res.Restarted)
+		}
+	}
+}
+
+// stackResults returns the recorded stack results sorted by context and stack.
+func (r *applyResults) stackResults() []StackApplyResult {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]StackApplyResult, len(r.stacks))
+	copy(out, r.stacks)
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Context != out[j].Context {
+			return out[i].Context < out[j].Context
+		}
+		return out[i].Stack < out[j].Stack
+	})
+	return out
+}
+
+func (r *applyResults) addRelabeled(n int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.relabeled += n
+}
+
+func (r *applyResults) addOwnershipFailures(failures []OwnershipFailure) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ownership = append(r.ownership, failures...)
+}
+
+// OwnershipFailures returns the fileset paths the last apply could not apply
+// ownership or permissions to.
+func (p *Planner) OwnershipFailures() []OwnershipFailure {
+	if p.results == nil {
+		return nil
+	}
+	p.results.mu.Lock()
+	defer p.results.mu.Unlock()
+	return append([]OwnershipFailure(nil), p.results.ownership...)
+}
+
+// RelabeledContainers returns how many containers the last apply recreated to
+// reapply their labels.
+func (p *Planner) RelabeledContainers() int {
+	if p.results == nil {
+		return 0
+	}
+	p.results.mu.Lock()
+	defer p.results.mu.Unlock()
+	return p.results.relabeled
+}
+
+// ServiceResults returns the per-service results recorded by the last apply,
+// sorted by context, stack and service.
+func (p *Planner) ServiceResults() []ServiceApplyResult {
+	if p.results == nil {
+		return nil
+	}
+	p.results.mu.Lock()
+	defer p.results.mu.Unlock()
+	out := make([]ServiceApplyResult, len(p.results.services))
+	copy(out, p.results.services)
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Context != out[j].Context {
+			return out[i].Context < out[j].Context
+		}
+		if out[i].Stack != out[j].Stack {
+			return out[i].Stack < out[j].Stack
+		}
+		return out[i].Service < out[j].Service
+	})
+	return out
+}
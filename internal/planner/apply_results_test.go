@@ -0,0 +1,130 @@
+package planner
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+func TestApplyStackChanges_NamesFailingService(t *testing.T) {
+	d := newMockDocker()
+	d.serviceStatuses = []dockercli.ServiceStatus{
+		{Service: "web", Container: "app-web-1", State: "running"},
+		{Service: "worker", Container: "app-worker-1", State: "exited", ExitCode: 1},
+	}
+	stacks := map[string]manifest.Stack{"app": {Root: t.TempDir(), Files: []string{"compose.yml"}}}
+	execCtx := &ContextExecutionContext{
+		ContextName: "default",
+		Stacks: map[string]*StackExecutionData{
+			"app": {
+				Services:   []ServiceInfo{{Name: "web", State: ServiceMissing}, {Name: "worker", State: ServiceMissing}},
+				NeedsApply: true,
+			},
+		},
+	}
+
+	p := NewWithDocker(d)
+	p.results = &applyResults{}
+	err := p.applyStackChangesForContext(context.Background(), manifest.Config{}, "default", stacks, "", d, nil, nil, execCtx)
+	if err == nil || !strings.Contains(err.Error(), "service worker exited (code 1)") {
+		t.Fatalf("expected error naming the failing service, got: %v", err)
+	}
+	if strings.Contains(err.Error(), "service web") {
+		t.Fatalf("healthy service must not be reported as failed: %v", err)
+	}
+
+	results := p.ServiceResults()
+	if len(results) != 2 || results[0].StackKey() != "default/app" || results[0].String() != "web started" {
+		t.Fatalf("unexpected service results: %#v", results)
+	}
+}
+
+func TestApplyStackChanges_RecordsStackResults(t *testing.T) {
+	d := newMockDocker()
+	stacks := map[string]manifest.Stack{
+		"app":  {Root: t.TempDir(), Files: []string{"compose.yml"}},
+		"idle": {Root: t.TempDir(), Files: []string{"compose.yml"}},
+	}
+	execCtx := &ContextExecutionContext{
+		ContextName: "default",
+		Stacks: map[string]*StackExecutionData{
+			"app": {
+				Services:   []ServiceInfo{{Name: "web", State: ServiceMissing}, {Name: "db", State: ServiceRunning}, {Name: "worker", State: ServiceDrifted}},
+				NeedsApply: true,
+			},
+			"idle": {Services: []ServiceInfo{{Name: "cron", State: ServiceRunning}}},
+		},
+	}
+
+	p := NewWithDocker(d)
+	p.results = &applyResults{}
+	if err := p.applyStackChangesForContext(context.Background(), manifest.Config{}, "default", stacks, "", d, nil, nil, execCtx); err != nil {
+		t.Fatalf("apply stacks: %v", err)
+	}
+	p.results.addRestarted("default", []dockercli.PsBrief{{Project: "app", Service: "db", Name: "app-db-1"}}, map[string]string{"app": "app", "idle": "idle"})
+
+	results := p.results.stackResults()
+	if len(results) != 2 || results[0].StackKey() != "default/app" || results[1].StackKey() != "default/idle" {
+		t.Fatalf("unexpected stack results: %#v", results)
+	}
+	app := results[0]
+	if !reflect.DeepEqual(app.Created, []string{"web"}) || !reflect.DeepEqual(app.Updated, []string{"worker"}) || !reflect.DeepEqual(app.Skipped, []string{"db"}) {
+		t.Fatalf("unexpected service split for app: %#v", app)
+	}
+	if !reflect.DeepEqual(app.Restarted, []string{"db"}) || !app.Changed() || app.Err != nil {
+		t.Fatalf("unexpected app result: %#v", app)
+	}
+	if idle := results[1]; idle.Changed() || !reflect.DeepEqual(idle.Skipped, []string{"cron"}) {
+		t.Fatalf("unexpected idle result: %#v", idle)
+	}
+}
+
+func TestApplyResults_RestartsAreAttributedByProject(t *testing.T) {
+	r := &applyResults{}
+	r.addStack(StackApplyResult{Context: "default", Stack: "admin", Skipped: []string{"web"}})
+	r.addStack(StackApplyResult{Context: "default", Stack: "shop", Skipped: []string{"web"}})
+	r.addStack(StackApplyResult{Context: "other", Stack: "shop", Skipped: []string{"web"}})
+
+	restarted := []dockercli.PsBrief{
+		{Project: "shop", Service: "web", Name: "shop-web-1"},
+		{Project: "blog", Service: "web", Name: "blog-web-1"},
+		{Project: "stray", Service: "web", Name: "stray-web-1"},
+	}
+	r.addRestarted("default", restarted, map[string]string{"admin": "admin", "shop": "shop", "blog": "blog"})
+
+	got := map[string][]string{}
+	for _, res := range r.stackResults() {
+		got[res.StackKey()] = res.Restarted
+	}
+	want := map[string][]string{"default/admin": nil, "default/blog": {"web"}, "default/shop": {"web"}, "other/shop": nil}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("restarted by stack = %v, want %v", got, want)
+	}
+}
+
+func TestApplyStackChanges_RecordsFailedStackError(t *testing.T) {
+	d := newMockDocker()
+	d.serviceStatuses = []dockercli.ServiceStatus{{Service: "web", Container: "app-web-1", State: "exited", ExitCode: 1}}
+	stacks := map[string]manifest.Stack{"app": {Root: t.TempDir(), Files: []string{"compose.yml"}}}
+	execCtx := &ContextExecutionContext{
+		ContextName: "default",
+		Stacks: map[string]*StackExecutionData{
+			"app": {Services: []ServiceInfo{{Name: "web", State: ServiceMissing}}, NeedsApply: true},
+		},
+	}
+
+	p := NewWithDocker(d)
+	p.results = &applyResults{}
+	err := p.applyStackChangesForContext(context.Background(), manifest.Config{}, "default", stacks, "", d, nil, nil, execCtx)
+	if err == nil {
+		t.Fatalf("expected apply error")
+	}
+	results := p.results.stackResults()
+	if len(results) != 1 || results[0].Err != err {
+		t.Fatalf("expected failed stack result carrying the error, got: %#v", results)
+	}
+}
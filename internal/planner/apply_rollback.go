@@ -0,0 +1,131 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/logger"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+// healthPollInterval is how often recreated services are inspected while
+// waiting for them to become healthy.
+var healthPollInterval = 2 * time.Second
+
+// recordPreviousImages returns the image ID currently run by each service that
+// compose up is about to recreate, keyed by service name. Services without a
+// container have nothing to roll back to and are left out.
+func recordPreviousImages(ctx context.Context, client DockerClient, services []ServiceInfo) (map[string]string, error) {
+	previous := map[string]string{}
+	for _, svc := range services {
+		if svc.State != ServiceDrifted || svc.Container == nil || svc.Container.Name == "" {
+			continue
+		}
+		image, err := client.InspectContainerImage(ctx, svc.Container.Name)
+		if err != nil {
+			return nil, apperr.Wrap("planner.recordPreviousImages", apperr.External, err, "inspect image of container %s", svc.Container.Name)
+		}
+		if image != "" {
+			previous[svc.Name] = image
+		}
+	}
+	return previous, nil
+}
+
+// waitForHealthy polls services until each is healthy or has failed, or until
+// timeout elapses. A service is only healthy once all of its replicas are; one
+// without any container (e.g. scaled to zero) or that ran to completion has
+// nothing to wait for. It returns the last status of every service that did
+// not become healthy, with the output of its last health check.
+func waitForHealthy(ctx context.Context, client DockerClient, stack manifest.Stack, proj string, inline []string, services []string, timeout time.Duration) (map[string]dockercli.ServiceStatus, error) {
+	unhealthy := map[string]dockercli.ServiceStatus{}
+	pending := append([]string(nil), services...)
+	deadline := time.Now().Add(timeout)
+	for {
+		statuses, err := client.ComposeServiceStatuses(dockercli.WithStack(ctx, stack), stack.Root, stack.Files, stack.Profiles, stack.EnvFile, proj, pending, inline)
+		if err != nil {
+			return nil, apperr.Wrap("planner.waitForHealthy", apperr.External, err, "inspect services of %s", stack.Root)
+		}
+		pending = pending[:0]
+		for _, st := range statuses {
+			switch {
+			case st.Healthy(), st.Completed(), st.State == "missing":
+				delete(unhealthy, st.Service)
+			case st.Failed():
+				unhealthy[st.Service] = st
+			default:
+				pending = append(pending, st.Service)
+				unhealthy[st.Service] = st
+			}
+		}
+		if len(pending) == 0 || !time.Now().Before(deadline) {
+			for svc, st := range unhealthy {
+				if st.Health == "" || st.Container == "" {
+					continue
+				}
+				// Best-effort: the status alone still names the problem.
+				if line, err := client.LastHealthCheck(ctx, st.Container); err == nil {
+					st.HealthLog = line
+					unhealthy[svc] = st
+				}
+			}
+			return unhealthy, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, apperr.Wrap("planner.waitForHealthy", apperr.Timeout, ctx.Err(), "wait for services of %s", stack.Root)
+		case <-time.After(healthPollInterval):
+		}
+	}
+}
+
+// rollbackUnhealthyServices waits for the services recreated by a stack's
+// compose up to become healthy, and recreates each one that does not from the
+// image it ran before. Services that ran to completion, such as migrations
+// that exited 0, are kept. Any rollback fails the stack so the apply reports
+// it.
+func (p *Planner) rollbackUnhealthyServices(ctx context.Context, client DockerClient, contextName, stackName string, stack manifest.Stack, proj string, inline []string, previous map[string]string, progress ProgressReporter) error {
+	if p.healthTimeout <= 0 || len(previous) == 0 {
+		return nil
+	}
+	if progress != nil {
+		progress.SetAction("waiting for " + contextName + "/" + stackName + " to become healthy")
+	}
+	unhealthy, err := waitForHealthy(ctx, client, stack, proj, inline, sortedKeys(previous), p.healthTimeout)
+	if err != nil {
+		return err
+	}
+	if len(unhealthy) == 0 {
+		return nil
+	}
+
+	log := logger.FromContext(ctx).With("component", "health", "context", contextName, "stack", stackName)
+	var reasons, failures []string
+	for _, svc := range sortedKeys(unhealthy) {
+		reason := unhealthy[svc].HealthSummary()
+		reasons = append(reasons, reason)
+		if progress != nil {
+			progress.SetAction("rolling back " + contextName + "/" + stackName + "/" + svc)
+		}
+		st := logger.StartStep(log, "service_rollback", svc, "resource_kind", "service", "image", previous[svc], "reason", reason)
+		if _, err := client.ComposeUpServiceImage(dockercli.WithStack(ctx, stack), stack.Root, stack.Files, stack.Profiles, stack.EnvFile, proj, svc, previous[svc], inline); err != nil {
+			_ = st.Fail(err)
+			failures = append(failures, fmt.Sprintf("%s: %v", svc, err))
+			continue
+		}
+		st.OK(true)
+		if p.pr != nil {
+			p.pr.Warn("%s/%s: rolled back %s to its previous image (%s)", contextName, stackName, svc, reason)
+		}
+	}
+
+	msg := fmt.Sprintf("stack %s/%s: %s did not become healthy within %s (%s)", contextName, stackName, strings.Join(sortedKeys(unhealthy), ", "), p.healthTimeout, strings.Join(reasons, ", "))
+	if len(failures) > 0 {
+		return apperr.New("planner.Apply", apperr.External, "%s; rollback failed (%s)", msg, strings.Join(failures, "; "))
+	}
+	return apperr.New("planner.Apply", apperr.External, "%s; rolled back to previous image", msg)
+}
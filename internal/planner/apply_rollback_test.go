@@ -0,0 +1,93 @@
+package planner
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+func applyDriftedWeb(t *testing.T, d *mockDockerClient, timeout time.Duration) error {
+	t.Helper()
+	prev := healthPollInterval
+	healthPollInterval = time.Millisecond
+	t.Cleanup(func() { healthPollInterval = prev })
+
+	stacks := map[string]manifest.Stack{"app": {Root: t.TempDir(), Files: []string{"compose.yml"}}}
+	execCtx := &ContextExecutionContext{
+		ContextName: "default",
+		Stacks: map[string]*StackExecutionData{
+			"app": {
+				Services: []ServiceInfo{
+					{Name: "web", State: ServiceDrifted, Container: &dockercli.ComposePsItem{Name: "app-web-1"}},
+					{Name: "db", State: ServiceRunning, Container: &dockercli.ComposePsItem{Name: "app-db-1"}},
+				},
+				NeedsApply: true,
+			},
+		},
+	}
+	p := NewWithDocker(d).WithHealthRollback(timeout)
+	p.results = &applyResults{}
+	return p.applyStackChangesForContext(context.Background(), manifest.Config{}, "default", stacks, "", d, nil, nil, execCtx)
+}
+
+func TestHealthRollback_RollsBackUnhealthyService(t *testing.T) {
+	d := newMockDocker()
+	d.serviceStatuses = []dockercli.ServiceStatus{{Service: "web", Container: "app-web-1", State: "running", Health: "unhealthy"}}
+
+	err := applyDriftedWeb(t, d, 20*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "web did not become healthy within 20ms (web unhealthy)") || !strings.Contains(err.Error(), "rolled back to previous image") {
+		t.Fatalf("expected rollback error, got: %v", err)
+	}
+	if len(d.rolledBack) != 1 || d.rolledBack[0] != "web=sha256:app-web-1" {
+		t.Fatalf("expected web rolled back to its previous image, got %v", d.rolledBack)
+	}
+}
+
+func TestHealthRollback_FailedContainerRollsBackWithoutWaiting(t *testing.T) {
+	d := newMockDocker()
+	d.serviceStatuses = []dockercli.ServiceStatus{{Service: "web", Container: "app-web-1", State: "exited", ExitCode: 1}}
+
+	start := time.Now()
+	err := applyDriftedWeb(t, d, time.Minute)
+	if err == nil || !strings.Contains(err.Error(), "web exited (code 1)") {
+		t.Fatalf("expected rollback error naming the exit, got: %v", err)
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Fatalf("failed container should not wait for the health timeout")
+	}
+	if len(d.rolledBack) != 1 {
+		t.Fatalf("expected one rollback, got %v", d.rolledBack)
+	}
+}
+
+func TestHealthRollback_HealthyServiceIsKept(t *testing.T) {
+	d := newMockDocker()
+	d.serviceStatuses = []dockercli.ServiceStatus{{Service: "web", Container: "app-web-1", State: "running", Health: "healthy"}}
+
+	if err := applyDriftedWeb(t, d, time.Minute); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if len(d.rolledBack) != 0 {
+		t.Fatalf("expected no rollback, got %v", d.rolledBack)
+	}
+}
+
+func TestHealthRollback_CompletedOneShotServiceIsKept(t *testing.T) {
+	d := newMockDocker()
+	d.serviceStatuses = []dockercli.ServiceStatus{{Service: "web", Container: "app-web-1", State: "exited", ExitCode: 0}}
+
+	start := time.Now()
+	if err := applyDriftedWeb(t, d, time.Minute); err != nil {
+		t.Fatalf("expected a service that exited 0 to pass the health gate, got: %v", err)
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Fatalf("completed service should not wait for the health timeout")
+	}
+	if len(d.rolledBack) != 0 {
+		t.Fatalf("expected no rollback, got %v", d.rolledBack)
+	}
+}
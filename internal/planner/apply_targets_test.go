@@ -0,0 +1,57 @@
+package planner
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+// TestApply_TargetedLeavesOtherStacksAlone applies only default/shop, as
+// `dockform apply --stack default/shop` does, next to a running default/admin
+// stack whose containers share service names with shop's.
+func TestApply_TargetedLeavesOtherStacksAlone(t *testing.T) {
+	d := newMockDocker()
+	d.composeDocs = map[string]dockercli.ComposeConfigDoc{
+		"/tmp/shop": {Name: "shop", Services: map[string]dockercli.ComposeService{"web": {}}},
+	}
+	d.containers = []dockercli.PsBrief{
+		{Project: "admin", Service: "web", Name: "admin-web-1"},
+		{Project: "admin", Service: "old", Name: "admin-old-1"},
+		{Project: "shop", Service: "web", Name: "shop-web-1"},
+		{Project: "shop", Service: "old", Name: "shop-old-1"},
+	}
+	cfg := manifest.Config{
+		Identifier: "test-id",
+		Targeted:   true,
+		Contexts:   map[string]manifest.ContextConfig{"default": {}},
+		Stacks: map[string]manifest.Stack{
+			"default/shop": {Root: "/tmp/shop", Files: []string{"compose.yaml"}},
+		},
+	}
+
+	p := NewWithDocker(d).WithPruneEachStack(CleanupOptions{Strict: true})
+	if _, err := p.Apply(context.Background(), cfg); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if err := p.PruneWithPlanOptions(context.Background(), cfg, nil, CleanupOptions{Strict: true}); err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if want := []string{"shop-old-1"}; !reflect.DeepEqual(d.removedContainers, want) {
+		t.Fatalf("expected only %v removed, got %v", want, d.removedContainers)
+	}
+
+	// A fileset of the targeted stack restarting "web" restarts shop's web only.
+	p.results = &applyResults{}
+	if err := p.restartPendingServices(context.Background(), cfg, "default", d, nil, map[string]struct{}{"web": {}}, nil); err != nil {
+		t.Fatalf("restart: %v", err)
+	}
+	if want := []string{"shop-web-1"}; !reflect.DeepEqual(d.restartedContainers, want) {
+		t.Fatalf("expected only %v restarted, got %v", want, d.restartedContainers)
+	}
+	if len(d.stoppedContainers) != 0 || len(d.startedContainers) != 0 {
+		t.Fatalf("expected no containers stopped or started, got %v and %v", d.stoppedContainers, d.startedContainers)
+	}
+}
@@ -2,7 +2,10 @@ package planner
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
+	"github.com/gcstr/dockform/internal/apperr"
 	"github.com/gcstr/dockform/internal/logger"
 	"github.com/gcstr/dockform/internal/manifest"
 )
@@ -10,11 +13,17 @@ import (
 // BuildPlan produces a structured plan with resources organized by context and type.
 // For multi-context configs, it builds per-context plans and aggregates them.
 func (p *Planner) BuildPlan(ctx context.Context, cfg manifest.Config) (*Plan, error) {
+	ctx = p.withSecretsCache(ctx)
 	log := logger.FromContext(ctx).With("component", "planner")
 
 	// Get all stacks (discovered + explicit)
 	allStacks := cfg.GetAllStacks()
 	allFilesets := cfg.GetAllFilesets()
+	if p.planFileset != "" {
+		if _, ok := allFilesets[p.planFileset]; !ok {
+			return nil, apperr.New("planner.BuildPlan", apperr.InvalidInput, "unknown fileset %q (available: %s)", p.planFileset, strings.Join(sortedKeys(allFilesets), ", "))
+		}
+	}
 
 	st := logger.StartStep(log, "plan_build", "multi-context",
 		"resource_kind", "plan",
@@ -123,6 +132,41 @@ func (p *Planner) buildContextPlan(ctx context.Context, cfg manifest.Config, con
 			"networks_found", len(existingNetworks))
 	}
 
+	// Single fileset: only its content is planned, on the context it targets.
+	if p.planFileset != "" {
+		if spec, ok := contextFilesets[p.planFileset]; ok && client != nil {
+			single := map[string]manifest.FilesetSpec{p.planFileset: spec}
+			if err := p.buildFilesetResourcesForContext(ctx, cfg, single, existingVolumes, client, resourcePlan, execCtx); err != nil {
+				return nil, err
+			}
+		}
+		return &ContextPlan{ContextName: contextName, Identifier: cfg.Identifier, Resources: resourcePlan}, nil
+	}
+
+	// Fileset fast path: only volumes and fileset content are planned.
+	if p.onlyFilesets {
+		desired := map[string]struct{}{}
+		for _, fileset := range contextFilesets {
+			desired[fileset.TargetVolume] = struct{}{}
+		}
+		for volName := range contextConfig.Volumes {
+			desired[volName] = struct{}{}
+		}
+		for _, name := range sortedKeys(desired) {
+			action, desc := ActionCreate, ""
+			if _, exists := existingVolumes[name]; exists {
+				action, desc = ActionNoop, "exists"
+			}
+			resourcePlan.Volumes = append(resourcePlan.Volumes, NewResource(ResourceVolume, name, action, desc))
+		}
+		if client != nil && len(contextFilesets) > 0 {
+			if err := p.buildFilesetResourcesForContext(ctx, cfg, contextFilesets, existingVolumes, client, resourcePlan, execCtx); err != nil {
+				return nil, err
+			}
+		}
+		return &ContextPlan{ContextName: contextName, Identifier: cfg.Identifier, Resources: resourcePlan}, nil
+	}
+
 	// Plan volumes - combine volumes from filesets + explicit context volumes
 	desiredVolumes := map[string]struct{}{}
 	for _, fileset := range contextFilesets {
@@ -148,11 +192,11 @@ func (p *Planner) buildContextPlan(ctx context.Context, cfg manifest.Config, con
 		}
 	}
 	// Plan removals for labeled volumes no longer needed (skip when targeting specific stacks)
-	if !cfg.Targeted {
-		for name := range existingVolumes {
+	if !cfg.Targeted && p.pruneFilter.Allows(ResourceVolume) {
+		for _, name := range sortedKeys(existingVolumes) {
 			if _, want := desiredVolumes[name]; !want {
 				resourcePlan.Volumes = append(resourcePlan.Volumes,
-					NewResource(ResourceVolume, name, ActionDelete, ""))
+					planVolumeDeletion(ctx, client, name, resourcePlan))
 			}
 		}
 	}
@@ -170,8 +214,11 @@ func (p *Planner) buildContextPlan(ctx context.Context, cfg manifest.Config, con
 			_, exists = existingNetworks[name]
 		}
 		if exists {
-			resourcePlan.Networks = append(resourcePlan.Networks,
-				NewResource(ResourceNetwork, name, ActionNoop, "exists"))
+			res, err := p.planExistingNetwork(ctx, client, name, contextConfig.Networks[name], resourcePlan)
+			if err != nil {
+				return nil, err
+			}
+			resourcePlan.Networks = append(resourcePlan.Networks, res)
 		} else {
 			resourcePlan.Networks = append(resourcePlan.Networks,
 				NewResource(ResourceNetwork, name, ActionCreate, ""))
@@ -180,7 +227,7 @@ func (p *Planner) buildContextPlan(ctx context.Context, cfg manifest.Config, con
 	// Plan removals for labeled networks no longer needed (skip when targeting specific stacks).
 	// Compose-owned networks carry the identifier label but are managed by their
 	// stack's lifecycle, so they must not be reported as orphans (GH #54).
-	if !cfg.Targeted {
+	if !cfg.Targeted && p.pruneFilter.Allows(ResourceNetwork) {
 		var composeOwnedNetworks map[string]struct{}
 		if client != nil {
 			owned, err := p.getComposeOwnedNetworks(ctx, client)
@@ -202,14 +249,23 @@ func (p *Planner) buildContextPlan(ctx context.Context, cfg manifest.Config, con
 
 	// Track services that should be removed (orphan detection)
 	// Skip when targeting specific stacks — we only have a partial view of desired state
-	if client != nil && !cfg.Targeted {
+	if client != nil && !cfg.Targeted && p.pruneFilter.Allows(ResourceContainer) {
 		desiredServices, err := p.collectDesiredServicesForContext(ctx, cfg, contextStacks, client)
 		if err != nil {
 			return nil, err
 		}
 		if all, err := client.ListComposeContainersAll(ctx); err == nil {
+			// Containers of services behind an inactive compose profile are not
+			// orphans: they are still defined by their stack.
+			kept, err := profileScopedContainers(ctx, client, contextStacks, cfg.Sops, all, desiredServices)
+			if err != nil {
+				return nil, err
+			}
 			toDelete := map[string]map[string]struct{}{}
 			for _, it := range all {
+				if _, keep := kept[it.Name]; keep {
+					continue
+				}
 				if _, want := desiredServices[it.Service]; !want {
 					if toDelete[it.Project] == nil {
 						toDelete[it.Project] = map[string]struct{}{}
@@ -229,7 +285,7 @@ func (p *Planner) buildContextPlan(ctx context.Context, cfg manifest.Config, con
 
 	// Filesets: show per-file changes using remote index when available
 	if client != nil && len(contextFilesets) > 0 {
-		if err := p.buildFilesetResourcesForContext(ctx, contextFilesets, existingVolumes, client, resourcePlan, execCtx); err != nil {
+		if err := p.buildFilesetResourcesForContext(ctx, cfg, contextFilesets, existingVolumes, client, resourcePlan, execCtx); err != nil {
 			return nil, err
 		}
 	}
@@ -240,3 +296,20 @@ func (p *Planner) buildContextPlan(ctx context.Context, cfg manifest.Config, con
 		Resources:   resourcePlan,
 	}, nil
 }
+
+// planVolumeDeletion plans the removal of an unneeded volume. A volume that
+// still holds data, or whose contents cannot be checked, is flagged in the
+// plan so apply can refuse to delete it without --allow-data-loss.
+func planVolumeDeletion(ctx context.Context, client DockerClient, name string, rp *ResourcePlan) Resource {
+	empty, err := client.IsVolumeEmpty(ctx, name)
+	if err == nil && empty {
+		return NewResource(ResourceVolume, name, ActionDelete, "")
+	}
+	rp.DataLoss = append(rp.DataLoss, name)
+	if err != nil {
+		rp.Warnings = append(rp.Warnings, fmt.Sprintf("DESTRUCTIVE: volume %s could not be checked for data (%v) and will be deleted", name, err))
+		return NewResource(ResourceVolume, name, ActionDelete, "contents unknown")
+	}
+	rp.Warnings = append(rp.Warnings, fmt.Sprintf("DESTRUCTIVE: volume %s is non-empty and will be deleted", name))
+	return NewResource(ResourceVolume, name, ActionDelete, "non-empty")
+}
@@ -2,6 +2,8 @@ package planner
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/gcstr/dockform/internal/apperr"
@@ -10,53 +12,22 @@ import (
 )
 
 // buildFilesetResourcesForContext processes fileset diffs for a context and adds them to the plan.
-// Local indexes are built concurrently (CPU-only), but remote index reads run sequentially to
-// avoid overwhelming SSH-based Docker contexts with too many concurrent connections.
-func (p *Planner) buildFilesetResourcesForContext(ctx context.Context, filesetSpecs map[string]manifest.FilesetSpec, existingVolumes map[string]struct{}, client DockerClient, plan *ResourcePlan, execCtx *ContextExecutionContext) error {
+// Remote indexes are read first, in one batch, so filesets using the fast hash strategy can reuse
+// their hashes; local indexes are then built concurrently (CPU-only, bounded). Remote reads never
+// run concurrently, to avoid overwhelming SSH-based Docker contexts with too many connections.
+func (p *Planner) buildFilesetResourcesForContext(ctx context.Context, cfg manifest.Config, filesetSpecs map[string]manifest.FilesetSpec, existingVolumes map[string]struct{}, client DockerClient, plan *ResourcePlan, execCtx *ContextExecutionContext) error {
 	filesetNames := sortedKeys(filesetSpecs)
 	if len(filesetNames) == 0 {
 		return nil
 	}
 
-	// Phase 1: build all local indexes concurrently (filesystem-only, no SSH).
-	type localResult struct {
-		name  string
-		index filesets.Index
-		err   error
-	}
-	localCh := make(chan localResult, len(filesetNames))
-	var wg sync.WaitGroup
-	for _, name := range filesetNames {
-		wg.Add(1)
-		go func(name string) {
-			defer wg.Done()
-			a := filesetSpecs[name]
-			idx, err := filesets.BuildLocalIndex(a.SourceAbs, a.TargetPath, a.Exclude)
-			localCh <- localResult{name: name, index: idx, err: err}
-		}(name)
-	}
-	wg.Wait()
-	close(localCh)
-
-	localIndexes := make(map[string]filesets.Index, len(filesetNames))
-	var errs []error
-	for res := range localCh {
-		if res.err != nil {
-			plan.Filesets[res.name] = []Resource{
-				NewResource(ResourceFile, "", ActionUpdate, "unable to index local files"),
-			}
-			errs = append(errs, apperr.Wrap("planner.buildFilesetResourcesForContext", apperr.Internal, res.err, "build local fileset index for %s", res.name))
-			continue
-		}
-		localIndexes[res.name] = res.index
-	}
-
-	// Phase 2: batch-read remote indexes for all existing fileset volumes in a
+	// Phase 1: batch-read remote indexes for all existing fileset volumes in a
 	// single helper container (one boot per host instead of one per fileset).
+	// --no-fileset-read skips the read entirely.
 	volSet := map[string]struct{}{}
 	for _, name := range filesetNames {
-		if _, ok := localIndexes[name]; !ok {
-			continue
+		if p.skipFilesetRead {
+			break
 		}
 		a := filesetSpecs[name]
 		if _, exists := existingVolumes[a.TargetVolume]; exists {
@@ -64,6 +35,8 @@ func (p *Planner) buildFilesetResourcesForContext(ctx context.Context, filesetSp
 		}
 	}
 	indexByVolume := map[string]string{}
+	var errs []error
+	remoteReadFailed := false
 	if len(volSet) > 0 {
 		vols := sortedKeys(volSet)
 		m, err := client.ReadIndexFilesFromVolumes(ctx, vols, filesets.IndexFileName)
@@ -72,20 +45,47 @@ func (p *Planner) buildFilesetResourcesForContext(ctx context.Context, filesetSp
 			// This is an intentional trade-off of batching: a single failed batched read
 			// marks the WHOLE context's filesets as "unable to read remote index" (wider
 			// blast radius than the old per-fileset read), in exchange for one container
-			// boot per host instead of one per fileset.
-			for _, name := range filesetNames {
-				if _, ok := localIndexes[name]; !ok {
-					continue
-				}
-				a := filesetSpecs[name]
-				if _, exists := existingVolumes[a.TargetVolume]; exists {
-					plan.Filesets[name] = []Resource{NewResource(ResourceFile, "", ActionUpdate, "unable to read remote index")}
-				}
-			}
+			// boot per host instead of one per fileset. Local indexes are still built
+			// below so their errors are reported too.
+			remoteReadFailed = true
 			errs = append(errs, apperr.Wrap("planner.buildFilesetResourcesForContext", apperr.External, err, "read remote indexes (batched)"))
-			return apperr.Aggregate("planner.buildFilesetResourcesForContext", apperr.External, "one or more fileset analyses failed", errs...)
+		} else {
+			indexByVolume = m
 		}
-		indexByVolume = m
+	}
+
+	// Phase 2: build all local indexes concurrently, bounded by
+	// --parallel-filesets (filesystem-only, no SSH).
+	type localResult struct {
+		name  string
+		index filesets.Index
+		err   error
+	}
+	localCh := make(chan localResult, len(filesetNames))
+	forEachBounded(filesetNames, p.filesetParallelism, func(name string) {
+		a := filesetSpecs[name]
+		renderer, err := filesetRenderer(ctx, cfg, name, a)
+		if err != nil {
+			localCh <- localResult{name: name, err: err}
+			return
+		}
+		// An unreadable remote index only costs the fast path; it is reported below.
+		prev, _ := filesets.ParseIndexJSON(indexByVolume[a.TargetVolume])
+		idx, err := buildFilesetIndex(a, renderer, prev)
+		localCh <- localResult{name: name, index: idx, err: err}
+	})
+	close(localCh)
+
+	localIndexes := make(map[string]filesets.Index, len(filesetNames))
+	for res := range localCh {
+		if res.err != nil {
+			plan.Filesets[res.name] = []Resource{
+				NewResource(ResourceFile, "", ActionUpdate, "unable to index local files"),
+			}
+			errs = append(errs, apperr.Wrap("planner.buildFilesetResourcesForContext", apperr.Internal, res.err, "build local fileset index for %s", res.name))
+			continue
+		}
+		localIndexes[res.name] = res.index
 	}
 
 	for _, name := range filesetNames {
@@ -97,6 +97,14 @@ func (p *Planner) buildFilesetResourcesForContext(ctx context.Context, filesetSp
 
 		raw := ""
 		if _, volumeExists := existingVolumes[a.TargetVolume]; volumeExists {
+			if p.skipFilesetRead {
+				plan.Filesets[name] = []Resource{NewResource(ResourceFile, "", ActionUpdate, "changes unknown (skipped remote read)")}
+				continue
+			}
+			if remoteReadFailed {
+				plan.Filesets[name] = []Resource{NewResource(ResourceFile, "", ActionUpdate, "unable to read remote index")}
+				continue
+			}
 			raw = indexByVolume[a.TargetVolume]
 		}
 		remote, err := filesets.ParseIndexJSON(raw)
@@ -134,6 +142,9 @@ func (p *Planner) buildFilesetResourcesForContext(ctx context.Context, filesetSp
 				resources = append(resources,
 					NewResource(ResourceFile, "", ActionUpdate, "changes detected (details unavailable)"))
 			}
+			if p.noRestart {
+				resources = append(resources, p.deferredRestartNote(ctx, client, a)...)
+			}
 		}
 		plan.Filesets[name] = resources
 	}
@@ -141,6 +152,18 @@ func (p *Planner) buildFilesetResourcesForContext(ctx context.Context, filesetSp
 	return apperr.Aggregate("planner.buildFilesetResourcesForContext", apperr.External, "one or more fileset analyses failed", errs...)
 }
 
+// deferredRestartNote returns a no-op note listing the services a changed
+// fileset would have restarted (or stopped, in cold mode) when restarts are
+// disabled. Target resolution failures only drop the note.
+func (p *Planner) deferredRestartNote(ctx context.Context, client DockerClient, fs manifest.FilesetSpec) []Resource {
+	targets, err := resolveTargetServices(ctx, client, withMountingServices(fs, p.restartMounting))
+	if err != nil || len(targets) == 0 {
+		return nil
+	}
+	return []Resource{NewResource(ResourceFile, "", ActionNoop,
+		fmt.Sprintf("would restart %s (skipped: --no-restart)", strings.Join(targets, ", ")))}
+}
+
 // getExistingResourcesForClient fetches volumes and networks for a specific client
 func (p *Planner) getExistingResourcesForClient(ctx context.Context, client DockerClient) (volumes, networks map[string]struct{}, err error) {
 	volumes = map[string]struct{}{}
@@ -257,4 +280,7 @@ func (p *Planner) aggregateContextPlan(aggregated *ResourcePlan, contextPlan *Co
 
 	// Containers
 	aggregated.Containers = append(aggregated.Containers, dp.Containers...)
+
+	aggregated.Warnings = append(aggregated.Warnings, dp.Warnings...)
+	aggregated.DataLoss = append(aggregated.DataLoss, dp.DataLoss...)
 }
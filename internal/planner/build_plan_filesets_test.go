@@ -2,8 +2,14 @@ package planner
 
 import (
 	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/dockercli/fake"
 	"github.com/gcstr/dockform/internal/filesets"
 	"github.com/gcstr/dockform/internal/manifest"
 )
@@ -36,7 +42,7 @@ func TestBuildFilesetResources_BatchesRemoteIndexReads(t *testing.T) {
 	execCtx := &ContextExecutionContext{Filesets: map[string]*FilesetExecutionData{}}
 
 	p := &Planner{}
-	if err := p.buildFilesetResourcesForContext(context.Background(), specs, existing, m, plan, execCtx); err != nil {
+	if err := p.buildFilesetResourcesForContext(context.Background(), manifest.Config{}, specs, existing, m, plan, execCtx); err != nil {
 		t.Fatalf("buildFilesetResourcesForContext: %v", err)
 	}
 
@@ -48,3 +54,69 @@ func TestBuildFilesetResources_BatchesRemoteIndexReads(t *testing.T) {
 		t.Fatalf("expected a resource entry for vol1")
 	}
 }
+
+func TestBuildFilesetResources_SkipFilesetRead(t *testing.T) {
+	m := newMockDocker()
+	m.volumes = []string{"vol1"}
+
+	dir1 := t.TempDir()
+	dir2 := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir2, "app.conf"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	specs := map[string]manifest.FilesetSpec{
+		"ctx/s/vol1": {SourceAbs: dir1, TargetPath: "/data", TargetVolume: "vol1"},
+		"ctx/s/vol2": {SourceAbs: dir2, TargetPath: "/data", TargetVolume: "vol2"},
+	}
+	existing := map[string]struct{}{"vol1": {}}
+	plan := &ResourcePlan{Filesets: map[string][]Resource{}}
+	execCtx := &ContextExecutionContext{Filesets: map[string]*FilesetExecutionData{}}
+
+	p := (&Planner{}).WithSkipFilesetRead(true)
+	if err := p.buildFilesetResourcesForContext(context.Background(), manifest.Config{}, specs, existing, m, plan, execCtx); err != nil {
+		t.Fatalf("buildFilesetResourcesForContext: %v", err)
+	}
+
+	if m.readIndexBatchCalls != 0 {
+		t.Fatalf("expected no remote index read, got %d", m.readIndexBatchCalls)
+	}
+	got := plan.Filesets["ctx/s/vol1"]
+	if len(got) != 1 || got[0].Action != ActionUpdate || got[0].Details != "changes unknown (skipped remote read)" {
+		t.Fatalf("expected vol1 reported as changes unknown, got %+v", got)
+	}
+	got = plan.Filesets["ctx/s/vol2"]
+	if len(got) != 1 || got[0].Action != ActionCreate || got[0].Name != "app.conf" {
+		t.Fatalf("expected the missing volume to list files to create, got %+v", got)
+	}
+}
+
+func TestBuildFilesetResources_FailedRemoteReadStillReportsLocalErrors(t *testing.T) {
+	f := fake.New()
+	f.Volumes = []string{"vol1", "vol2"}
+	f.Errors["ReadIndexFilesFromVolumes"] = errors.New("helper container failed")
+
+	specs := map[string]manifest.FilesetSpec{
+		"ctx/s/vol1": {SourceAbs: t.TempDir(), TargetPath: "/data", TargetVolume: "vol1"},
+		"ctx/s/vol2": {SourceAbs: filepath.Join(t.TempDir(), "missing"), TargetPath: "/data", TargetVolume: "vol2"},
+	}
+	existing := map[string]struct{}{"vol1": {}, "vol2": {}}
+	plan := &ResourcePlan{Filesets: map[string][]Resource{}}
+	execCtx := &ContextExecutionContext{Filesets: map[string]*FilesetExecutionData{}}
+
+	err := (&Planner{}).buildFilesetResourcesForContext(context.Background(), manifest.Config{}, specs, existing, f, plan, execCtx)
+	var multi *apperr.MultiError
+	if !errors.As(err, &multi) || len(multi.Errors) != 2 {
+		t.Fatalf("expected the remote read and local index errors, got: %v", err)
+	}
+	for i, want := range []string{"read remote indexes", "build local fileset index for ctx/s/vol2"} {
+		if !strings.Contains(multi.Errors[i].Error(), want) {
+			t.Fatalf("expected error %d to contain %q, got: %v", i, want, multi.Errors[i])
+		}
+	}
+	if got := plan.Filesets["ctx/s/vol1"]; len(got) != 1 || got[0].Details != "unable to read remote index" {
+		t.Fatalf("unexpected vol1 resources: %+v", got)
+	}
+	if got := plan.Filesets["ctx/s/vol2"]; len(got) != 1 || got[0].Details != "unable to index local files" {
+		t.Fatalf("unexpected vol2 resources: %+v", got)
+	}
+}
@@ -131,3 +131,50 @@ func TestBuildPlan_Logging(t *testing.T) {
 		}
 	}
 }
+
+func TestApplyAndPrune_LogTimeline(t *testing.T) {
+	var logBuf bytes.Buffer
+	l, closer, err := logger.New(logger.Options{Out: &logBuf, Format: "json", Level: "info"})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	if closer != nil {
+		defer func() { _ = closer.Close() }()
+	}
+	ctx := logger.WithContext(context.Background(), l)
+
+	docker := newMockDocker()
+	docker.volumes = []string{"orphan-vol"}
+	cfg := manifest.Config{
+		Identifier: "test-app",
+		Contexts:   map[string]manifest.ContextConfig{"default": {}},
+		Stacks: map[string]manifest.Stack{
+			"default/website": {Root: "/tmp/website", Files: []string{"compose.yaml"}},
+		},
+	}
+	p := NewWithDocker(docker)
+	if _, err := p.Apply(ctx, cfg); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if err := p.Prune(ctx, cfg); err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+
+	timed := map[string]bool{}
+	for _, line := range strings.Split(strings.TrimSpace(logBuf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("parse log line %q: %v", line, err)
+		}
+		if entry["status"] == "ok" {
+			if _, ok := entry["duration_ms"]; ok {
+				timed[entry["action"].(string)+" "+entry["resource"].(string)] = true
+			}
+		}
+	}
+	for _, want := range []string{"compose_config website", "compose_ps website", "compose_up website", "volume_prune orphan-vol"} {
+		if !timed[want] {
+			t.Errorf("expected a timed %q step, got %v", want, timed)
+		}
+	}
+}
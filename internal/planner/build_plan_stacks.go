@@ -34,7 +34,8 @@ func (p *Planner) buildStackResourcesForContext(ctx context.Context, cfg manifes
 
 // serviceStatesToResources converts service states to plan resources.
 // This is the core conversion logic used by both sequential and parallel stack processing.
-func serviceStatesToResources(services []ServiceInfo) []Resource {
+// Stopped services are started by apply, or recreated when recreateStopped is set.
+func serviceStatesToResources(services []ServiceInfo, recreateStopped bool) []Resource {
 	var resources []Resource
 	for _, service := range services {
 		switch service.State {
@@ -45,8 +46,19 @@ func serviceStatesToResources(services []ServiceInfo) []Resource {
 			resources = append(resources,
 				NewResource(ResourceService, service.Name, ActionReconcile, "identifier mismatch"))
 		case ServiceDrifted:
+			details := "config drift"
+			if service.ImageUpdated {
+				details = "image updated"
+			}
+			resources = append(resources,
+				NewResource(ResourceService, service.Name, ActionUpdate, details))
+		case ServiceStopped:
+			details := "stopped, will start"
+			if recreateStopped {
+				details = "stopped, will be recreated"
+			}
 			resources = append(resources,
-				NewResource(ResourceService, service.Name, ActionUpdate, "config drift"))
+				NewResource(ResourceService, service.Name, ActionUpdate, details))
 		case ServiceRunning:
 			if service.DesiredHash != "" {
 				resources = append(resources,
@@ -92,6 +104,7 @@ func (p *Planner) buildStackResourcesSequentialForContext(ctx context.Context, c
 		if err != nil {
 			return apperr.Wrap("planner.buildStackResourcesSequentialForContext", apperr.External, err, "detect service state for stack %s/%s", contextName, stackName)
 		}
+		services = p.markUpdatedImages(ctx, client, stack, inline, services)
 		if len(services) == 0 {
 			plan.Stacks[stackName] = fallbackStackResource()
 			continue
@@ -104,7 +117,7 @@ func (p *Planner) buildStackResourcesSequentialForContext(ctx context.Context, c
 			NeedsApply: NeedsApply(services),
 		}
 
-		plan.Stacks[stackName] = serviceStatesToResources(services)
+		plan.Stacks[stackName] = p.annotateImageProblems(ctx, client, stack, inline, serviceStatesToResources(services, p.recreateStopped))
 	}
 
 	return nil
@@ -159,6 +172,7 @@ func (p *Planner) buildStackResourcesParallelForContext(ctx context.Context, cfg
 				}
 				return
 			}
+			services = p.markUpdatedImages(ctx, client, stack, inline, services)
 
 			var resources []Resource
 			var execData *StackExecutionData
@@ -172,7 +186,7 @@ func (p *Planner) buildStackResourcesParallelForContext(ctx context.Context, cfg
 					InlineEnv:  inline,
 					NeedsApply: NeedsApply(services),
 				}
-				resources = serviceStatesToResources(services)
+				resources = p.annotateImageProblems(ctx, client, stack, inline, serviceStatesToResources(services, p.recreateStopped))
 			}
 
 			resultsChan <- stackResult{stackName: stackName, resources: resources, execData: execData}
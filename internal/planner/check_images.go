@@ -0,0 +1,60 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/logger"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+// annotateImageProblems marks the services of a stack that compose will start
+// but whose image is neither present nor pullable. Services that build their
+// image, or whose action is a no-op, are not checked. Failing to read the
+// compose config leaves the resources as they are; the apply reports it.
+func (p *Planner) annotateImageProblems(ctx context.Context, client DockerClient, stack manifest.Stack, inline []string, resources []Resource) []Resource {
+	if !p.checkImages || client == nil {
+		return resources
+	}
+	doc, err := client.ComposeConfigFull(dockercli.WithStack(ctx, stack), stack.Root, stack.Files, stack.Profiles, stack.EnvFile, inline)
+	if err != nil {
+		logger.FromContext(ctx).Debug("check_images_skipped", "root", stack.Root, "error", err.Error())
+		return resources
+	}
+
+	checked := map[string]string{}
+	for i, res := range resources {
+		if res.Type != ResourceService || res.Action == ActionNoop {
+			continue
+		}
+		svc, ok := doc.Services[res.Name]
+		if !ok || svc.Image == "" || svc.Build != nil {
+			continue
+		}
+		problem, seen := checked[svc.Image]
+		if !seen {
+			problem = imageProblem(ctx, client, svc.Image)
+			checked[svc.Image] = problem
+		}
+		resources[i].Problem = problem
+	}
+	return resources
+}
+
+// imageProblem describes why image cannot be used, or returns "" when it can.
+func imageProblem(ctx context.Context, client DockerClient, image string) string {
+	availability, err := client.CheckImageAvailability(ctx, image)
+	switch availability {
+	case dockercli.ImagePresent, dockercli.ImagePullable:
+		return ""
+	case dockercli.ImageMissing:
+		return fmt.Sprintf("image %s not found", image)
+	case dockercli.ImageUnauthorized:
+		return fmt.Sprintf("image %s: registry access denied", image)
+	}
+	if err != nil {
+		logger.FromContext(ctx).Debug("check_image_failed", "image", image, "error", err.Error())
+	}
+	return fmt.Sprintf("image %s could not be checked", image)
+}
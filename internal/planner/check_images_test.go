@@ -0,0 +1,40 @@
+package planner
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+	"github.com/gcstr/dockform/internal/ui"
+)
+
+func TestBuildPlan_CheckImagesAnnotatesMissingImage(t *testing.T) {
+	cfg := manifest.Config{
+		Identifier: "demo",
+		Contexts:   map[string]manifest.ContextConfig{"default": {}},
+		Stacks: map[string]manifest.Stack{
+			"default/website": {Root: t.TempDir() + "/website", Files: []string{"compose.yml"}},
+		},
+	}
+	d := newMockDocker()
+	d.imageAvailability = map[string]dockercli.ImageAvailability{"nginx:latest": dockercli.ImageMissing}
+
+	plan, err := NewWithDocker(d).BuildPlan(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("BuildPlan: %v", err)
+	}
+	if out := ui.StripANSI(plan.String()); strings.Contains(out, "not found") {
+		t.Fatalf("images must not be checked without WithCheckImages, got:\n%s", out)
+	}
+
+	plan, err = NewWithDocker(d).WithCheckImages(true).BuildPlan(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("BuildPlan: %v", err)
+	}
+	out := ui.StripANSI(plan.String())
+	if !strings.Contains(out, "nginx will be created (image nginx:latest not found)") {
+		t.Fatalf("expected the service annotated with its missing image, got:\n%s", out)
+	}
+}
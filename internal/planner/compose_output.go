@@ -0,0 +1,59 @@
+package planner
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/logger"
+)
+
+// composeOutput forwards the output of a running compose command to the log
+// one line at a time, so image pulls and per-service "Creating"/"Starting"
+// lines reach the rolling log as they happen instead of after compose exits.
+type composeOutput struct {
+	mu      sync.Mutex
+	log     logger.Logger
+	project string
+	pending []byte
+}
+
+// streamComposeOutput returns a context under which docker commands stream
+// their output to log, and a func that logs any unterminated last line. Call
+// it once the command has returned.
+func streamComposeOutput(ctx context.Context, log logger.Logger, project string) (context.Context, func()) {
+	w := &composeOutput{log: log, project: project}
+	return dockercli.WithOutput(ctx, w), w.flush
+}
+
+// Write logs every complete line in p and keeps the rest for the next write.
+// Carriage returns end a line too, as progress output redraws with them.
+func (w *composeOutput) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending = append(w.pending, p...)
+	for {
+		i := bytes.IndexAny(w.pending, "\r\n")
+		if i < 0 {
+			break
+		}
+		w.emit(string(w.pending[:i]))
+		w.pending = w.pending[i+1:]
+	}
+	return len(p), nil
+}
+
+func (w *composeOutput) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.emit(string(w.pending))
+	w.pending = nil
+}
+
+func (w *composeOutput) emit(line string) {
+	if line = strings.TrimSpace(line); line != "" {
+		w.log.Info("compose_output", "project", w.project, "line", line)
+	}
+}
@@ -0,0 +1,53 @@
+package planner
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/logger"
+)
+
+func TestComposeOutput_LogsEachLineAsItArrives(t *testing.T) {
+	var logBuf bytes.Buffer
+	l, closer, err := logger.New(logger.Options{Out: &logBuf, Format: "json", Level: "info"})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	if closer != nil {
+		defer func() { _ = closer.Close() }()
+	}
+	w := &composeOutput{log: l, project: "shop"}
+
+	_, _ = w.Write([]byte(" web Pulling \n db Pul"))
+	if got := composeOutputLines(t, logBuf.String()); strings.Join(got, "|") != "web Pulling" {
+		t.Fatalf("expected the complete line logged right away, got %q", got)
+	}
+	_, _ = w.Write([]byte("ling\r\n\n Container shop-web-1  Starting"))
+	w.flush()
+
+	want := []string{"web Pulling", "db Pulling", "Container shop-web-1  Starting"}
+	if got := composeOutputLines(t, logBuf.String()); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("logged lines = %q, want %q", got, want)
+	}
+}
+
+// composeOutputLines returns the line field of every compose_output entry.
+func composeOutputLines(t *testing.T, logs string) []string {
+	t.Helper()
+	var lines []string
+	for _, raw := range strings.Split(strings.TrimSpace(logs), "\n") {
+		if raw == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(raw), &entry); err != nil {
+			t.Fatalf("parse log line %q: %v", raw, err)
+		}
+		if entry["msg"] == "compose_output" && entry["project"] == "shop" {
+			lines = append(lines, entry["line"].(string))
+		}
+	}
+	return lines
+}
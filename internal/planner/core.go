@@ -1,8 +1,12 @@
 package planner
 
 import (
+	"context"
+	"time"
+
 	"github.com/gcstr/dockform/internal/dockercli"
 	"github.com/gcstr/dockform/internal/manifest"
+	"github.com/gcstr/dockform/internal/secrets"
 	"github.com/gcstr/dockform/internal/ui"
 )
 
@@ -16,23 +20,118 @@ type Planner struct {
 	factory *dockercli.DefaultClientFactory
 
 	pr            ui.Printer
+	events        Events
 	spinner       *ui.Spinner
 	spinnerPrefix string // Prefix for dynamic spinner labels (e.g., "Applying", "Destroying")
 	parallel      bool
+
+	// dryRun, when set, intercepts mutating docker calls and records them instead.
+	dryRun *DryRunRecorder
+
+	// results collects per-service outcomes of the last apply.
+	results *applyResults
+
+	// destroyExclude lists volumes and networks that destroy must keep.
+	destroyExclude DestroyExclusions
+
+	// pruneFilter limits the resource kinds prune and destroy may remove.
+	pruneFilter PruneFilter
+
+	// onlyFilesets limits plan and apply to volumes and filesets, skipping all
+	// compose work for stacks.
+	onlyFilesets bool
+
+	// planFileset, when set, limits BuildPlan to the content of this one
+	// fileset, skipping every other resource.
+	planFileset string
+
+	// noRestart skips restarting services after their filesets changed.
+	noRestart bool
+
+	// restartMounting restarts the services mounting a changed fileset's
+	// target volume when the fileset declares no restart_services.
+	restartMounting bool
+
+	// recreateNetworks makes apply recreate managed networks whose driver,
+	// options or IPAM differ from their spec.
+	recreateNetworks bool
+
+	// recreateStopped makes apply recreate managed containers that exist but
+	// are not running instead of just starting them.
+	recreateStopped bool
+
+	// relabelAll makes apply recreate the services of up-to-date stacks so
+	// every managed container carries the current labels.
+	relabelAll bool
+
+	// strictOwnership makes apply fail a fileset whose ownership could not be
+	// applied to every path.
+	strictOwnership bool
+
+	// allowDataLoss lets prune delete managed volumes that still hold data.
+	allowDataLoss bool
+
+	// pruneEachStack, when set, makes apply remove each stack's orphaned
+	// containers right after the stack converges, handling errors as it says.
+	pruneEachStack *CleanupOptions
+
+	// skipFilesetRead makes BuildPlan skip reading the remote fileset indexes
+	// and report filesets with an existing target volume as changes unknown.
+	skipFilesetRead bool
+
+	// checkImages makes BuildPlan verify that the images of services it will
+	// start are present or pullable.
+	checkImages bool
+
+	// recreateIfImageUpdated makes BuildPlan treat running services whose
+	// image tag now resolves to a different local image as drifted.
+	recreateIfImageUpdated bool
+
+	// filesetParallelism caps how many filesets are indexed concurrently
+	// during plan and sync; zero means DefaultFilesetParallelism.
+	filesetParallelism int
+
+	// healthTimeout, when non-zero, waits that long for recreated services to
+	// become healthy and rolls unhealthy ones back to their previous image.
+	healthTimeout time.Duration
+
+	// inlineDiff makes apply print each resource's planned change right
+	// before acting on it; diffPlan is the plan those changes come from.
+	inlineDiff bool
+	diffPlan   *Plan
+
+	// secrets caches decrypted SOPS files across the plan, apply and prune
+	// phases run by this planner.
+	secrets *secrets.DecryptCache
 }
 
-func New() *Planner { return &Planner{parallel: true} }
+func New() *Planner { return &Planner{parallel: true, secrets: secrets.NewDecryptCache()} }
 
-func NewWithDocker(client DockerClient) *Planner { return &Planner{docker: client, parallel: true} }
+func NewWithDocker(client DockerClient) *Planner {
+	return &Planner{docker: client, parallel: true, secrets: secrets.NewDecryptCache()}
+}
 
 // NewWithFactory creates a planner using a client factory for multi-context support.
 func NewWithFactory(factory *dockercli.DefaultClientFactory) *Planner {
-	return &Planner{factory: factory, parallel: true}
+	return &Planner{factory: factory, parallel: true, secrets: secrets.NewDecryptCache()}
+}
+
+// withSecretsCache returns ctx carrying the planner's decryption cache, unless
+// ctx already carries one, and reporting to the planner's events.
+func (p *Planner) withSecretsCache(ctx context.Context) context.Context {
+	ctx = p.withEvents(ctx)
+	if p.secrets == nil || secrets.DecryptCacheFrom(ctx) != nil {
+		return ctx
+	}
+	return secrets.WithDecryptCache(ctx, p.secrets)
 }
 
 // WithPrinter sets the output printer for user-facing messages during apply/prune.
 func (p *Planner) WithPrinter(pr ui.Printer) *Planner {
 	p.pr = pr
+	if p.events != nil {
+		p.pr = eventsPrinter{inner: pr, events: p.events}
+	}
 	return p
 }
 
@@ -49,13 +148,156 @@ func (p *Planner) WithParallel(enabled bool) *Planner {
 	return p
 }
 
+// WithDryRun makes the planner record mutating docker operations into rec instead
+// of executing them. Read operations still reach the daemon.
+func (p *Planner) WithDryRun(rec *DryRunRecorder) *Planner {
+	p.dryRun = rec
+	return p
+}
+
+// WithOnlyFilesets restricts BuildPlan and Apply to the fileset fast path:
+// target volumes are ensured and filesets synced, but stacks, networks and
+// orphan services are neither inspected nor changed.
+func (p *Planner) WithOnlyFilesets(enabled bool) *Planner {
+	p.onlyFilesets = enabled
+	return p
+}
+
+// WithPlanFileset restricts BuildPlan to a single fileset: its local index is
+// built and diffed against the remote one, and no other resource is inspected.
+// BuildPlan fails when the manifest declares no fileset with that name.
+func (p *Planner) WithPlanFileset(name string) *Planner {
+	p.planFileset = name
+	return p
+}
+
+// WithInlineDiff makes ApplyWithPlan print the planned changes of each
+// resource, rendered like the changes-only plan, immediately before applying
+// it. Nothing is printed when apply is not given a plan.
+func (p *Planner) WithInlineDiff(enabled bool) *Planner {
+	p.inlineDiff = enabled
+	return p
+}
+
+// WithNoRestart disables restarting the services a fileset declares in
+// restart_services after its content changed.
+func (p *Planner) WithNoRestart(enabled bool) *Planner {
+	p.noRestart = enabled
+	return p
+}
+
+// WithRestartMountingServices makes apply restart the services that mount a
+// changed fileset's target volume when the fileset has no restart_services.
+func (p *Planner) WithRestartMountingServices(enabled bool) *Planner {
+	p.restartMounting = enabled
+	return p
+}
+
+// WithRecreateNetworks makes plan and apply recreate existing networks that
+// drifted from their spec instead of only warning about them. Attached
+// containers are disconnected for the duration of the recreate.
+func (p *Planner) WithRecreateNetworks(enabled bool) *Planner {
+	p.recreateNetworks = enabled
+	return p
+}
+
+// WithRecreateStopped makes apply remove and recreate the containers of
+// services that are up-to-date but not running, instead of starting the
+// existing containers again.
+func (p *Planner) WithRecreateStopped(enabled bool) *Planner {
+	p.recreateStopped = enabled
+	return p
+}
+
+// WithRelabelAll makes apply recreate the services compose considers up to
+// date, so every managed container carries the current identifier and stack
+// labels. Changed services are recreated with them anyway.
+func (p *Planner) WithRelabelAll(enabled bool) *Planner {
+	p.relabelAll = enabled
+	return p
+}
+
+// WithStrictOwnership makes apply fail when a fileset's ownership or
+// permissions could not be applied to some paths; by default those paths are
+// only reported through OwnershipFailures.
+func (p *Planner) WithStrictOwnership(enabled bool) *Planner {
+	p.strictOwnership = enabled
+	return p
+}
+
+// WithAllowDataLoss lets prune delete managed volumes that are not empty. By
+// default such volumes are kept and reported as prune errors.
+func (p *Planner) WithAllowDataLoss(enabled bool) *Planner {
+	p.allowDataLoss = enabled
+	return p
+}
+
+// WithPruneEachStack makes apply remove the containers of services a stack no
+// longer defines as soon as that stack converges, instead of leaving them
+// running until the end-of-run prune. opts controls whether prune errors fail
+// the apply.
+func (p *Planner) WithPruneEachStack(opts CleanupOptions) *Planner {
+	p.pruneEachStack = &opts
+	return p
+}
+
+// WithSkipFilesetRead makes BuildPlan skip the helper containers that read the
+// remote fileset indexes. Filesets whose target volume exists are reported as
+// changes unknown; those without one still list every file to create.
+func (p *Planner) WithSkipFilesetRead(enabled bool) *Planner {
+	p.skipFilesetRead = enabled
+	return p
+}
+
+// WithCheckImages makes BuildPlan check, without pulling, that every service
+// it plans to start has an image that is present locally or pullable, and
+// annotate the services whose image is not.
+func (p *Planner) WithCheckImages(enabled bool) *Planner {
+	p.checkImages = enabled
+	return p
+}
+
+// WithRecreateIfImageUpdated makes BuildPlan compare the image each running
+// service's container was created from with the image its tag resolves to
+// locally now, and plan a recreate when they differ. This rolls out rebuilt or
+// re-pulled mutable tags such as :latest, which leave the config hash as is.
+func (p *Planner) WithRecreateIfImageUpdated(enabled bool) *Planner {
+	p.recreateIfImageUpdated = enabled
+	return p
+}
+
+// WithFilesetParallelism caps how many filesets are indexed, and have their
+// remote index read, concurrently while planning and syncing. During apply it
+// also caps how many filesets are synced into their volumes at once, unless
+// parallel processing is disabled.
+func (p *Planner) WithFilesetParallelism(n int) *Planner {
+	p.filesetParallelism = n
+	return p
+}
+
+// WithHealthRollback makes apply wait up to timeout for every service it
+// recreated to become healthy, rolling back those that do not to the image
+// they ran before. A zero timeout disables the check.
+func (p *Planner) WithHealthRollback(timeout time.Duration) *Planner {
+	p.healthTimeout = timeout
+	return p
+}
+
 // getClientForContext returns the Docker client for a specific context.
 // It first checks if a factory is configured, then falls back to the single client.
 func (p *Planner) getClientForContext(contextName string, cfg *manifest.Config) DockerClient {
+	var client DockerClient
 	if p.factory != nil {
-		return p.factory.GetClientForContext(contextName, cfg)
+		client = p.factory.GetClientForContext(contextName, cfg)
+	} else if p.docker != nil {
+		// Fallback to single client for backward compatibility
+		client = p.docker
+	} else {
+		return nil
+	}
+	if p.dryRun != nil {
+		return newDryRunClient(client, contextName, p.dryRun)
 	}
-	// Fallback to single client for backward compatibility
-	return p.docker
+	return client
 }
 
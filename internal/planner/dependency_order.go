@@ -0,0 +1,100 @@
+package planner
+
+import (
+	"context"
+	"sort"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+// DependencyOrder returns the services of deps so that every service comes
+// after the services it depends on, breaking ties by name. When only is
+// non-empty, just those services are returned, in the same relative order.
+// Dependency cycles (which compose rejects) are broken by name.
+func DependencyOrder(deps map[string][]string, only []string) []string {
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var order []string
+	state := map[string]int{} // 1 = visiting, 2 = done
+	var visit func(name string)
+	visit = func(name string) {
+		if state[name] != 0 {
+			return
+		}
+		state[name] = 1
+		for _, dep := range deps[name] {
+			if _, ok := deps[dep]; ok {
+				visit(dep)
+			}
+		}
+		state[name] = 2
+		order = append(order, name)
+	}
+	for _, name := range names {
+		visit(name)
+	}
+
+	if len(only) == 0 {
+		return order
+	}
+	keep := make(map[string]bool, len(only))
+	for _, s := range only {
+		keep[s] = true
+	}
+	filtered := order[:0]
+	for _, name := range order {
+		if keep[name] {
+			filtered = append(filtered, name)
+		}
+	}
+	return filtered
+}
+
+// recreationOrder returns the services of a stack that need an up, dependencies
+// first, when one of them depends on another. A single compose up recreates
+// changed services in no guaranteed order, so a dependent with restart: always
+// can crash-loop while its dependency is being replaced; bringing them up one
+// at a time avoids that. It returns nil when a single compose up is enough.
+func recreationOrder(ctx context.Context, client DockerClient, stack manifest.Stack, services []ServiceInfo, inline []string) ([]string, error) {
+	changed := map[string]bool{}
+	for _, svc := range services {
+		if svc.State != ServiceRunning {
+			changed[svc.Name] = true
+		}
+	}
+	if len(changed) < 2 {
+		return nil, nil
+	}
+
+	doc, err := client.ComposeConfigFull(dockercli.WithStack(ctx, stack), stack.Root, stack.Files, stack.Profiles, stack.EnvFile, inline)
+	if err != nil {
+		return nil, err
+	}
+	deps := make(map[string][]string, len(doc.Services))
+	related := false
+	for name, svc := range doc.Services {
+		deps[name] = svc.DependsOn
+		if !changed[name] {
+			continue
+		}
+		for _, dep := range svc.DependsOn {
+			if changed[dep] {
+				related = true
+			}
+		}
+	}
+	if !related {
+		return nil, nil
+	}
+
+	only := make([]string, 0, len(changed))
+	for name := range changed {
+		only = append(only, name)
+	}
+	return DependencyOrder(deps, only), nil
+}
@@ -0,0 +1,91 @@
+package planner
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+func dependencyMock(services map[string]dockercli.ComposeService) (*mockDockerClient, manifest.Config) {
+	d := newMockDocker()
+	d.composeDocs = map[string]dockercli.ComposeConfigDoc{
+		"/tmp/app": {Services: services},
+	}
+	cfg := manifest.Config{
+		Identifier: "test-id",
+		Contexts:   map[string]manifest.ContextConfig{"default": {}},
+		Stacks: map[string]manifest.Stack{
+			"default/app": {Root: "/tmp/app", Files: []string{"compose.yaml"}},
+		},
+	}
+	return d, cfg
+}
+
+func TestApply_RecreatesInDependencyOrder(t *testing.T) {
+	d, cfg := dependencyMock(map[string]dockercli.ComposeService{
+		"web":    {DependsOn: dockercli.ComposeDependsOn{"api"}},
+		"api":    {DependsOn: dockercli.ComposeDependsOn{"cache", "db"}},
+		"db":     {},
+		"cache":  {},
+		"worker": {DependsOn: dockercli.ComposeDependsOn{"db"}},
+	})
+	if _, err := NewWithDocker(d).Apply(context.Background(), cfg); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if d.composeUps != 0 {
+		t.Fatalf("expected no whole-stack compose up when ordering services, got %d", d.composeUps)
+	}
+	want := []string{"cache", "db", "api", "web", "worker"}
+	if !reflect.DeepEqual(d.composeServiceUps, want) {
+		t.Fatalf("expected services brought up dependencies first %v, got %v", want, d.composeServiceUps)
+	}
+}
+
+func TestApply_OnlyChangedServicesOrdered(t *testing.T) {
+	d, cfg := dependencyMock(map[string]dockercli.ComposeService{
+		"web": {DependsOn: dockercli.ComposeDependsOn{"api"}},
+		"api": {DependsOn: dockercli.ComposeDependsOn{"db"}},
+		"db":  {},
+	})
+	d.composePsItems = []dockercli.ComposePsItem{{Name: "app-web-1", Service: "web", State: "running"}}
+	d.containerLabels = map[string]map[string]string{
+		"app-web-1": {"com.docker.compose.config-hash": "mock-hash", "io.dockform.identifier": "test-id"},
+	}
+	if _, err := NewWithDocker(d).Apply(context.Background(), cfg); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	want := []string{"db", "api"}
+	if !reflect.DeepEqual(d.composeServiceUps, want) {
+		t.Fatalf("expected only the changed services in dependency order %v, got %v", want, d.composeServiceUps)
+	}
+}
+
+func TestApply_UnrelatedChangesUseSingleComposeUp(t *testing.T) {
+	d, cfg := dependencyMock(map[string]dockercli.ComposeService{
+		"web": {},
+		"db":  {},
+	})
+	if _, err := NewWithDocker(d).Apply(context.Background(), cfg); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if d.composeUps != 1 || len(d.composeServiceUps) != 0 {
+		t.Fatalf("expected a single compose up, got %d ups and per-service ups %v", d.composeUps, d.composeServiceUps)
+	}
+}
+
+func TestDependencyOrder_FiltersAndBreaksCycles(t *testing.T) {
+	deps := map[string][]string{
+		"a": {"b"},
+		"b": {"a"},
+		"c": {"a", "missing"},
+	}
+	if got := DependencyOrder(deps, nil); !reflect.DeepEqual(got, []string{"b", "a", "c"}) {
+		t.Fatalf("unexpected order: %v", got)
+	}
+	if got := DependencyOrder(deps, []string{"c", "b"}); !reflect.DeepEqual(got, []string{"b", "c"}) {
+		t.Fatalf("unexpected filtered order: %v", got)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/dockercli"
 	"github.com/gcstr/dockform/internal/logger"
 	"github.com/gcstr/dockform/internal/manifest"
 )
@@ -21,6 +22,10 @@ type destroyScope struct {
 	targeted bool
 	// projects is the set of "context/project" keys belonging to targeted stacks.
 	projects map[string]bool
+	// exclude lists volumes and networks kept via --exclude.
+	exclude DestroyExclusions
+	// filter limits destroy to the resource kinds given via --prune-filter.
+	filter PruneFilter
 }
 
 // allowsStack reports whether a discovered compose project on contextName is in scope.
@@ -34,6 +39,30 @@ func (s destroyScope) allowsStack(contextName, project string) bool {
 	return s.projects[manifest.MakeStackKey(contextName, project)]
 }
 
+// listContainers lists labeled containers, or none when --prune-filter leaves
+// containers out.
+func (s destroyScope) listContainers(ctx context.Context, client DockerClient) ([]dockercli.PsBrief, error) {
+	if !s.filter.Allows(ResourceContainer) {
+		return nil, nil
+	}
+	return client.ListComposeContainersAll(ctx)
+}
+
+// listVolumes lists labeled volumes, or none when --prune-filter leaves volumes
+// out.
+func (s destroyScope) listVolumes(ctx context.Context, client DockerClient) ([]string, error) {
+	if !s.filter.Allows(ResourceVolume) {
+		return nil, nil
+	}
+	return client.ListVolumes(ctx)
+}
+
+// removesNetworks reports whether networks are in scope. Context-level networks
+// are shared infrastructure, so a targeted destroy never removes them.
+func (s destroyScope) removesNetworks() bool {
+	return !s.targeted && s.filter.Allows(ResourceNetwork)
+}
+
 // newDestroyScope computes the destroy scope from a (possibly targeted) config.
 // The targeted config's Stacks/DiscoveredStacks have already been filtered by
 // ResolveTargets, so they describe exactly the stacks in scope.
@@ -59,6 +88,8 @@ func newDestroyScope(cfg *manifest.Config) destroyScope {
 // BuildDestroyPlan creates a plan to destroy all managed resources.
 // Unlike BuildPlan, this discovers all labeled resources regardless of configuration.
 func (p *Planner) BuildDestroyPlan(ctx context.Context, cfg manifest.Config) (*Plan, error) {
+	ctx = p.withSecretsCache(ctx)
+
 	if p.docker == nil && p.factory == nil {
 		return nil, apperr.New("planner.BuildDestroyPlan", apperr.Precondition, "docker client not configured")
 	}
@@ -74,6 +105,8 @@ func (p *Planner) BuildDestroyPlan(ctx context.Context, cfg manifest.Config) (*P
 		volumeToFileset[fs.TargetVolume] = fsName
 	}
 	scope := newDestroyScope(&cfg)
+	scope.exclude = p.destroyExclude
+	scope.filter = p.pruneFilter
 
 	var mu sync.Mutex
 
@@ -111,7 +144,7 @@ func (p *Planner) buildDestroyPlanForContext(ctx context.Context, client DockerC
 	}
 
 	// Discover all labeled containers and group by stack
-	containers, err := client.ListComposeContainersAll(ctx)
+	containers, err := scope.listContainers(ctx, client)
 	if err != nil {
 		return nil, apperr.Wrap("planner.BuildDestroyPlan", apperr.External, err, "context %s: list containers", contextName)
 	}
@@ -143,35 +176,49 @@ func (p *Planner) buildDestroyPlanForContext(ctx context.Context, client DockerC
 
 	// Discover all labeled networks. Context-level networks are shared
 	// infrastructure, so a scoped (targeted) destroy never removes them.
-	if !scope.targeted {
+	if scope.removesNetworks() {
 		networks, err := client.ListNetworks(ctx)
 		if err != nil {
 			return nil, apperr.Wrap("planner.BuildDestroyPlan", apperr.External, err, "context %s: list networks", contextName)
 		}
 		for _, network := range networks {
+			if scope.exclude.Networks[network] {
+				rp.Networks = append(rp.Networks, NewResource(ResourceNetwork, network, ActionNoop, "will be kept (excluded)"))
+				continue
+			}
 			res := NewResource(ResourceNetwork, network, ActionDelete, "will be destroyed")
 			rp.Networks = append(rp.Networks, res)
 		}
 	}
 
 	// Discover all labeled volumes
-	volumes, err := client.ListVolumes(ctx)
+	volumes, err := scope.listVolumes(ctx, client)
 	if err != nil {
 		return nil, apperr.Wrap("planner.BuildDestroyPlan", apperr.External, err, "context %s: list volumes", contextName)
 	}
 
 	for _, volume := range volumes {
+		excluded := scope.exclude.Volumes[volume]
 		if filesetName, hasFileset := volumeToFileset[volume]; hasFileset {
 			if _, exists := rp.Filesets[filesetName]; !exists {
 				rp.Filesets[filesetName] = []Resource{}
 			}
 			fsConfig := allFilesets[filesetName]
+			if excluded {
+				details := fmt.Sprintf("volume %s at %s will be kept (excluded)", volume, fsConfig.TargetPath)
+				rp.Filesets[filesetName] = append(rp.Filesets[filesetName], NewResource(ResourceFile, "", ActionNoop, details))
+				continue
+			}
 			details := fmt.Sprintf("volume %s at %s will be destroyed", volume, fsConfig.TargetPath)
 			res := NewResource(ResourceFile, "", ActionDelete, details)
 			rp.Filesets[filesetName] = append(rp.Filesets[filesetName], res)
 		} else if !scope.targeted {
 			// Non-fileset volumes are shared/context-level: only removed in a
 			// full (untargeted) destroy.
+			if excluded {
+				rp.Volumes = append(rp.Volumes, NewResource(ResourceVolume, volume, ActionNoop, "will be kept (excluded)"))
+				continue
+			}
 			res := NewResource(ResourceVolume, volume, ActionDelete, "will be destroyed")
 			rp.Volumes = append(rp.Volumes, res)
 		}
@@ -200,6 +247,8 @@ func (p *Planner) Destroy(ctx context.Context, cfg manifest.Config) error {
 
 // DestroyWithOptions executes the destruction of all managed resources with explicit cleanup options.
 func (p *Planner) DestroyWithOptions(ctx context.Context, cfg manifest.Config, opts CleanupOptions) error {
+	ctx = p.withSecretsCache(ctx)
+
 	if p.docker == nil && p.factory == nil {
 		return apperr.New("planner.Destroy", apperr.Precondition, "docker client not configured")
 	}
@@ -210,6 +259,8 @@ func (p *Planner) DestroyWithOptions(ctx context.Context, cfg manifest.Config, o
 		volumeToFileset[fs.TargetVolume] = fsName
 	}
 	scope := newDestroyScope(&cfg)
+	scope.exclude = p.destroyExclude
+	scope.filter = p.pruneFilter
 
 	// Destroy mutates state (removes containers/networks/volumes), so contexts
 	// always run to completion: a failure on one host must never cancel
@@ -233,7 +284,7 @@ func (p *Planner) destroyContext(ctx context.Context, client DockerClient, conte
 	var errs []error
 
 	// Step 1: Remove containers
-	allContainers, err := client.ListComposeContainersAll(ctx)
+	allContainers, err := scope.listContainers(ctx, client)
 	if err != nil {
 		errs = append(errs, apperr.Wrap("planner.destroyContext", apperr.External, err, "context %s: list containers", contextName))
 		if verboseErrors {
@@ -288,7 +339,7 @@ func (p *Planner) destroyContext(ctx context.Context, client DockerClient, conte
 
 	// Step 2: Remove networks. Context-level networks are shared infrastructure,
 	// so a scoped (targeted) destroy never removes them.
-	if !scope.targeted {
+	if scope.removesNetworks() {
 		networks, err := client.ListNetworks(ctx)
 		if err != nil {
 			errs = append(errs, apperr.Wrap("planner.destroyContext", apperr.External, err, "context %s: list networks", contextName))
@@ -299,6 +350,10 @@ func (p *Planner) destroyContext(ctx context.Context, client DockerClient, conte
 			}
 		}
 		for _, network := range networks {
+			if scope.exclude.Networks[network] {
+				log.Info("destroy_network_excluded", "network", network)
+				continue
+			}
 			if p.spinner != nil {
 				p.spinner.SetLabel(fmt.Sprintf("removing network %s on %s", network, contextName))
 			}
@@ -315,7 +370,7 @@ func (p *Planner) destroyContext(ctx context.Context, client DockerClient, conte
 
 	// Step 3: Remove volumes. Under a scoped destroy, only the targeted stacks'
 	// fileset volumes are removed; shared/context-level volumes are preserved.
-	volumes, err := client.ListVolumes(ctx)
+	volumes, err := scope.listVolumes(ctx, client)
 	if err != nil {
 		errs = append(errs, apperr.Wrap("planner.destroyContext", apperr.External, err, "context %s: list volumes", contextName))
 		if verboseErrors {
@@ -325,6 +380,10 @@ func (p *Planner) destroyContext(ctx context.Context, client DockerClient, conte
 		}
 	}
 	for _, volume := range volumes {
+		if scope.exclude.Volumes[volume] {
+			log.Info("destroy_volume_excluded", "volume", volume)
+			continue
+		}
 		if scope.targeted {
 			if _, isFileset := volumeToFileset[volume]; !isFileset {
 				continue
@@ -0,0 +1,43 @@
+package planner
+
+import (
+	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+// DestroyExclusions lists volumes and networks that a destroy must keep even
+// though they carry the identifier label.
+type DestroyExclusions struct {
+	Volumes  map[string]bool
+	Networks map[string]bool
+}
+
+// ParseDestroyExclusions parses --exclude values of the form "volume:<name>" or
+// "network:<name>".
+func ParseDestroyExclusions(values []string) (DestroyExclusions, error) {
+	ex := DestroyExclusions{Volumes: map[string]bool{}, Networks: map[string]bool{}}
+	for _, v := range values {
+		kind, name, ok := strings.Cut(strings.TrimSpace(v), ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return DestroyExclusions{}, apperr.New("planner.ParseDestroyExclusions", apperr.InvalidInput, "invalid --exclude %q: expected volume:<name> or network:<name>", v)
+		}
+		switch strings.ToLower(kind) {
+		case "volume":
+			ex.Volumes[name] = true
+		case "network":
+			ex.Networks[name] = true
+		default:
+			return DestroyExclusions{}, apperr.New("planner.ParseDestroyExclusions", apperr.InvalidInput, "invalid --exclude %q: unknown resource type %q (supported: volume, network)", v, kind)
+		}
+	}
+	return ex, nil
+}
+
+// WithDestroyExclusions makes destroy keep the given volumes and networks. They
+// are still listed in the destroy plan, marked as kept.
+func (p *Planner) WithDestroyExclusions(ex DestroyExclusions) *Planner {
+	p.destroyExclude = ex
+	return p
+}
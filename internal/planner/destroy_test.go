@@ -2,6 +2,7 @@ package planner
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/gcstr/dockform/internal/dockercli"
@@ -167,3 +168,51 @@ func TestDestroy_ScopedToStack(t *testing.T) {
 		t.Errorf("Expected only nginx-config volume removed, got %v", got)
 	}
 }
+
+func TestDestroy_ExcludedResourcesAreKept(t *testing.T) {
+	mock := newMockDocker()
+	mock.containers = []dockercli.PsBrief{{Project: "app", Service: "web", Name: "app-web-1"}}
+	mock.networks = []string{"app-net", "shared-net"}
+	mock.volumes = []string{"data", "shared-data"}
+
+	cfg := manifest.Config{
+		Identifier:         "test",
+		Contexts:           map[string]manifest.ContextConfig{"default": {}},
+		DiscoveredFilesets: map[string]manifest.FilesetSpec{},
+	}
+
+	ex, err := ParseDestroyExclusions([]string{"volume:shared-data", "network:shared-net"})
+	if err != nil {
+		t.Fatalf("parse exclusions: %v", err)
+	}
+	p := NewWithDocker(mock).WithDestroyExclusions(ex)
+
+	plan, err := p.BuildDestroyPlan(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("BuildDestroyPlan: %v", err)
+	}
+	out := plan.String()
+	for _, want := range []string{"shared-data will be kept (excluded)", "shared-net will be kept (excluded)", "data will be deleted"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected plan to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	if err := p.Destroy(context.Background(), cfg); err != nil {
+		t.Fatalf("Destroy failed: %v", err)
+	}
+	if got := mock.removedVolumes; len(got) != 1 || got[0] != "data" {
+		t.Errorf("expected only data volume removed, got %v", got)
+	}
+	if got := mock.removedNetworks; len(got) != 1 || got[0] != "app-net" {
+		t.Errorf("expected only app-net network removed, got %v", got)
+	}
+}
+
+func TestParseDestroyExclusions_RejectsInvalid(t *testing.T) {
+	for _, v := range []string{"shared-data", "volume:", "container:web"} {
+		if _, err := ParseDestroyExclusions([]string{v}); err == nil {
+			t.Errorf("expected error for %q", v)
+		}
+	}
+}
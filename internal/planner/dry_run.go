@@ -0,0 +1,183 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+)
+
+// DryRunOp describes a single mutating Docker operation that a dry run intercepted.
+type DryRunOp struct {
+	Context string
+	Action  string
+	Target  string
+}
+
+// String renders the operation as a single human-readable line.
+func (o DryRunOp) String() string {
+	if o.Context == "" {
+		return fmt.Sprintf("%s %s", o.Action, o.Target)
+	}
+	return fmt.Sprintf("[%s] %s %s", o.Context, o.Action, o.Target)
+}
+
+// DryRunRecorder collects the operations intercepted by dry-run clients.
+// It is safe for concurrent use because contexts are applied in parallel.
+type DryRunRecorder struct {
+	mu  sync.Mutex
+	ops []DryRunOp
+}
+
+// NewDryRunRecorder creates an empty recorder.
+func NewDryRunRecorder() *DryRunRecorder { return &DryRunRecorder{} }
+
+func (r *DryRunRecorder) record(contextName, action, format string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ops = append(r.ops, DryRunOp{Context: contextName, Action: action, Target: fmt.Sprintf(format, args...)})
+}
+
+// Ops returns the recorded operations, grouped by context in a stable order while
+// preserving the execution order within each context.
+func (r *DryRunRecorder) Ops() []DryRunOp {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]DryRunOp, len(r.ops))
+	copy(out, r.ops)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Context < out[j].Context })
+	return out
+}
+
+// dryRunClient wraps a DockerClient so that read operations reach the daemon while
+// mutating operations are recorded and reported as successful without side effects.
+type dryRunClient struct {
+	DockerClient
+	contextName string
+	rec         *DryRunRecorder
+}
+
+func newDryRunClient(inner DockerClient, contextName string, rec *DryRunRecorder) DockerClient {
+	return &dryRunClient{DockerClient: inner, contextName: contextName, rec: rec}
+}
+
+func (c *dryRunClient) CreateVolume(ctx context.Context, name string, labels map[string]string) error {
+	c.rec.record(c.contextName, "create volume", "%s", name)
+	return nil
+}
+
+func (c *dryRunClient) RemoveVolume(ctx context.Context, name string) error {
+	c.rec.record(c.contextName, "remove volume", "%s", name)
+	return nil
+}
+
+func (c *dryRunClient) WriteFileToVolume(ctx context.Context, volumeName, targetPath, relFile, content string) error {
+	c.rec.record(c.contextName, "write file", "%s:%s", volumeName, joinVolumePath(targetPath, relFile))
+	return nil
+}
+
+func (c *dryRunClient) ExtractTarToVolume(ctx context.Context, volumeName, targetPath string, tarReader io.Reader) error {
+	c.rec.record(c.contextName, "sync files", "%s:%s", volumeName, targetPath)
+	return nil
+}
+
+func (c *dryRunClient) RemovePathsFromVolume(ctx context.Context, volumeName, targetPath string, relPaths []string) error {
+	c.rec.record(c.contextName, "remove files", "%s:%s (%d paths)", volumeName, targetPath, len(relPaths))
+	return nil
+}
+
+func (c *dryRunClient) RunVolumeScript(ctx context.Context, volumeName, targetPath, script string, env []string) (dockercli.VolumeScriptResult, error) {
+	c.rec.record(c.contextName, "run volume script", "%s:%s", volumeName, targetPath)
+	return dockercli.VolumeScriptResult{}, nil
+}
+
+func (c *dryRunClient) CreateNetwork(ctx context.Context, name string, labels map[string]string, opts ...dockercli.NetworkCreateOpts) error {
+	c.rec.record(c.contextName, "create network", "%s", name)
+	return nil
+}
+
+func (c *dryRunClient) RemoveNetwork(ctx context.Context, name string) error {
+	c.rec.record(c.contextName, "remove network", "%s", name)
+	return nil
+}
+
+func (c *dryRunClient) ConnectNetwork(ctx context.Context, network, container string, endpoint dockercli.NetworkEndpoint) error {
+	c.rec.record(c.contextName, "connect network", "%s to %s", network, container)
+	return nil
+}
+
+func (c *dryRunClient) DisconnectNetwork(ctx context.Context, network, container string) error {
+	c.rec.record(c.contextName, "disconnect network", "%s from %s", network, container)
+	return nil
+}
+
+func (c *dryRunClient) RestartContainer(ctx context.Context, name string) error {
+	c.rec.record(c.contextName, "restart container", "%s", name)
+	return nil
+}
+
+func (c *dryRunClient) StopContainers(ctx context.Context, names []string, opts ...dockercli.StopOptions) error {
+	if len(names) > 0 {
+		c.rec.record(c.contextName, "stop containers", "%s", strings.Join(names, ", "))
+	}
+	return nil
+}
+
+func (c *dryRunClient) StartContainers(ctx context.Context, names []string) error {
+	if len(names) > 0 {
+		c.rec.record(c.contextName, "start containers", "%s", strings.Join(names, ", "))
+	}
+	return nil
+}
+
+func (c *dryRunClient) RemoveContainer(ctx context.Context, name string, force bool) error {
+	c.rec.record(c.contextName, "remove container", "%s", name)
+	return nil
+}
+
+func (c *dryRunClient) ComposeUp(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project string, inline []string) (string, error) {
+	target := root
+	if project != "" {
+		target = project + " (" + root + ")"
+	}
+	c.rec.record(c.contextName, "docker compose up", "%s", target)
+	return "", nil
+}
+
+func (c *dryRunClient) ComposeUpServices(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project string, services []string, inline []string) (string, error) {
+	c.rec.record(c.contextName, "docker compose up", "%s (no deps)", strings.Join(services, ", "))
+	return "", nil
+}
+
+func (c *dryRunClient) ComposeRecreateServices(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project string, services []string, inline []string) (string, error) {
+	c.rec.record(c.contextName, "docker compose up", "%s (force recreate)", strings.Join(services, ", "))
+	return "", nil
+}
+
+func (c *dryRunClient) ComposeUpServiceImage(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project, service, image string, inline []string) (string, error) {
+	c.rec.record(c.contextName, "docker compose up", "%s with image %s", service, image)
+	return "", nil
+}
+
+func (c *dryRunClient) ComposeRun(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project, service string, command []string, inline []string) (string, error) {
+	c.rec.record(c.contextName, "docker compose run", "%s: %s", service, strings.Join(command, " "))
+	return "", nil
+}
+
+// ComposeServiceStatuses reports every service as started, since the compose up it
+// would follow was only recorded.
+func (c *dryRunClient) ComposeServiceStatuses(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project string, services []string, inline []string) ([]dockercli.ServiceStatus, error) {
+	out := make([]dockercli.ServiceStatus, 0, len(services))
+	for _, svc := range services {
+		out = append(out, dockercli.ServiceStatus{Service: svc, State: "running"})
+	}
+	return out, nil
+}
+
+func joinVolumePath(targetPath, relFile string) string {
+	return strings.TrimRight(targetPath, "/") + "/" + strings.TrimLeft(relFile, "/")
+}
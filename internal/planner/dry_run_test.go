@@ -0,0 +1,93 @@
+package planner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+func TestApply_DryRun_RecordsWithoutMutating(t *testing.T) {
+	d := newMockDocker()
+	cfg := manifest.Config{
+		Identifier: "test-id",
+		Contexts: map[string]manifest.ContextConfig{
+			"default": {
+				Volumes:  map[string]manifest.TopLevelResourceSpec{"data": {}},
+				Networks: map[string]manifest.NetworkSpec{"web": {}},
+			},
+		},
+	}
+
+	rec := NewDryRunRecorder()
+	if _, err := NewWithDocker(d).WithDryRun(rec).Apply(context.Background(), cfg); err != nil {
+		t.Fatalf("dry-run apply: %v", err)
+	}
+
+	if len(d.createdVolumes) != 0 || len(d.createdNetworks) != 0 {
+		t.Fatalf("dry run must not mutate; created volumes=%v networks=%v", d.createdVolumes, d.createdNetworks)
+	}
+
+	var lines []string
+	for _, op := range rec.Ops() {
+		lines = append(lines, op.String())
+	}
+	got := strings.Join(lines, "\n")
+	for _, want := range []string{"[default] create volume data", "[default] create network web"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected recorded op %q; got:\n%s", want, got)
+		}
+	}
+}
+
+func TestApply_DryRun_RecordsFilesetSyncsAndRestartsInOrder(t *testing.T) {
+	d := newMockDocker()
+	d.containers = []dockercli.PsBrief{{Project: "app", Service: "web", Name: "app-web-1"}}
+	cfg := manifest.Config{
+		Identifier:         "test-id",
+		Contexts:           map[string]manifest.ContextConfig{"default": {}},
+		DiscoveredFilesets: map[string]manifest.FilesetSpec{},
+	}
+	for name, volume := range map[string]string{"a": "va", "b": "vb"} {
+		src := t.TempDir()
+		if err := os.WriteFile(filepath.Join(src, name+".txt"), []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cfg.DiscoveredFilesets[name] = manifest.FilesetSpec{
+			Context: "default", SourceAbs: src, TargetVolume: volume, TargetPath: "/" + name,
+			RestartServices: manifest.RestartTargets{Services: []string{"web"}},
+		}
+	}
+
+	rec := NewDryRunRecorder()
+	// Parallel fileset syncs still record one reproducible sequence.
+	p := NewWithDocker(d).WithDryRun(rec).WithFilesetParallelism(4)
+	if _, err := p.Apply(context.Background(), cfg); err != nil {
+		t.Fatalf("dry-run apply: %v", err)
+	}
+
+	var lines []string
+	for _, op := range rec.Ops() {
+		lines = append(lines, op.String())
+	}
+	got := strings.Join(lines, "\n")
+	want := []string{
+		"[default] sync files va:/a",
+		"[default] write file va:/a/.dockform-index.json",
+		"[default] sync files vb:/b",
+		"[default] write file vb:/b/.dockform-index.json",
+		"[default] restart container app-web-1",
+	}
+	last := -1
+	for _, w := range want {
+		i := strings.Index(got, w)
+		if i <= last {
+			t.Fatalf("expected %q after the previous operations; got:\n%s", w, got)
+		}
+		last = i
+	}
+}
@@ -0,0 +1,173 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gcstr/dockform/internal/logger"
+	"github.com/gcstr/dockform/internal/ui"
+)
+
+// Events receives the planner's progress as it works, so callers embedding
+// the planner can render it however they like. Methods may be called
+// concurrently when contexts are processed in parallel.
+type Events interface {
+	// ResourceStarted is called when the planner begins work on a resource.
+	ResourceStarted(e ResourceEvent)
+	// ResourceFinished is called when that work succeeded or failed.
+	ResourceFinished(e ResourceEvent)
+	// Warning is called with every warning shown to the user.
+	Warning(message string)
+}
+
+// ResourceEvent describes one step of work on a resource. Changed, Duration
+// and Err are only set when the step finished.
+type ResourceEvent struct {
+	Action   string // What the step does, e.g. "network_create"
+	Kind     string // Resource kind, e.g. "network" or "stack"
+	Name     string // Resource name
+	Context  string // Docker context the resource lives in, when known
+	Changed  bool
+	Duration time.Duration
+	Err      string // Failure message; empty when the step succeeded
+}
+
+// WithEvents makes the planner report its progress to ev.
+func (p *Planner) WithEvents(ev Events) *Planner {
+	inner := p.pr
+	if ep, ok := inner.(eventsPrinter); ok {
+		inner = ep.inner
+	}
+	p.events = ev
+	p.pr = eventsPrinter{inner: inner, events: ev}
+	return p
+}
+
+// withEvents returns ctx with its logger extended to turn step records into
+// resource events, when events are set.
+func (p *Planner) withEvents(ctx context.Context) context.Context {
+	if p.events == nil {
+		return ctx
+	}
+	if _, ok := logger.FromContext(ctx).(*eventsLogger); ok {
+		return ctx
+	}
+	return logger.WithContext(ctx, &eventsLogger{inner: logger.FromContext(ctx), events: p.events, kinds: &stepKinds{m: map[string]string{}}})
+}
+
+// eventsPrinter forwards to the wrapped printer and reports warnings as
+// events.
+type eventsPrinter struct {
+	inner  ui.Printer
+	events Events
+}
+
+func (p eventsPrinter) printer() ui.Printer {
+	if p.inner == nil {
+		return ui.NoopPrinter{}
+	}
+	return p.inner
+}
+
+func (p eventsPrinter) Plain(format string, a ...any) { p.printer().Plain(format, a...) }
+func (p eventsPrinter) Info(format string, a ...any)  { p.printer().Info(format, a...) }
+func (p eventsPrinter) Error(format string, a ...any) { p.printer().Error(format, a...) }
+
+func (p eventsPrinter) Warn(format string, a ...any) {
+	p.printer().Warn(format, a...)
+	p.events.Warning(fmt.Sprintf(format, a...))
+}
+
+// eventsLogger forwards to the wrapped logger and turns the started, ok and
+// failed records of logger steps into resource events.
+type eventsLogger struct {
+	inner  logger.Logger
+	events Events
+	fields []any
+	kinds  *stepKinds
+}
+
+// stepKinds remembers the resource kind of each started step, since only the
+// started record carries the step's extra fields.
+type stepKinds struct {
+	mu sync.Mutex
+	m  map[string]string
+}
+
+func (l *eventsLogger) Debug(msg string, keyvals ...any) { l.inner.Debug(msg, keyvals...) }
+func (l *eventsLogger) Warn(msg string, keyvals ...any)  { l.inner.Warn(msg, keyvals...) }
+
+func (l *eventsLogger) Info(msg string, keyvals ...any) {
+	l.inner.Info(msg, keyvals...)
+	l.step(keyvals)
+}
+
+func (l *eventsLogger) Error(msg string, keyvals ...any) {
+	l.inner.Error(msg, keyvals...)
+	l.step(keyvals)
+}
+
+func (l *eventsLogger) With(keyvals ...any) logger.Logger {
+	fields := append(append([]any(nil), l.fields...), keyvals...)
+	return &eventsLogger{inner: l.inner.With(keyvals...), events: l.events, fields: fields, kinds: l.kinds}
+}
+
+// step reports a logger step record as a resource event. Other records, and
+// the steps tracing single docker CLI invocations, are ignored.
+func (l *eventsLogger) step(keyvals []any) {
+	status := stringField("status", keyvals, nil)
+	if status != "started" && status != "ok" && status != "failed" {
+		return
+	}
+	e := ResourceEvent{
+		Action:  stringField("action", keyvals, nil),
+		Kind:    stringField("resource_kind", keyvals, l.fields),
+		Name:    stringField("resource", keyvals, nil),
+		Context: stringField("context", keyvals, l.fields),
+	}
+	key := e.Action + "\x00" + e.Name + "\x00" + e.Context
+	l.kinds.mu.Lock()
+	if status == "started" {
+		l.kinds.m[key] = e.Kind
+	} else if e.Kind == "" {
+		e.Kind = l.kinds.m[key]
+		delete(l.kinds.m, key)
+	}
+	l.kinds.mu.Unlock()
+	if e.Kind == "process" {
+		return
+	}
+	if status == "started" {
+		l.events.ResourceStarted(e)
+		return
+	}
+	e.Err = stringField("error", keyvals, nil)
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		switch keyvals[i] {
+		case "changed":
+			e.Changed, _ = keyvals[i+1].(bool)
+		case "duration_ms":
+			if ms, ok := keyvals[i+1].(int64); ok {
+				e.Duration = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	l.events.ResourceFinished(e)
+}
+
+// stringField returns the string value of key in keyvals, falling back to the
+// fields attached with With.
+func stringField(key string, keyvals, fallback []any) string {
+	for _, kv := range [][]any{keyvals, fallback} {
+		for i := 0; i+1 < len(kv); i += 2 {
+			if k, ok := kv[i].(string); ok && k == key {
+				if v, ok := kv[i+1].(string); ok {
+					return v
+				}
+			}
+		}
+	}
+	return ""
+}
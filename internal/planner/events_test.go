@@ -0,0 +1,63 @@
+package planner
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/logger"
+)
+
+type recordedEvents struct {
+	mu       sync.Mutex
+	started  []ResourceEvent
+	finished []ResourceEvent
+	warnings []string
+}
+
+func (r *recordedEvents) ResourceStarted(e ResourceEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.started = append(r.started, e)
+}
+
+func (r *recordedEvents) ResourceFinished(e ResourceEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.finished = append(r.finished, e)
+}
+
+func (r *recordedEvents) Warning(message string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.warnings = append(r.warnings, message)
+}
+
+func TestWithEvents_ReportsStepsAndWarnings(t *testing.T) {
+	rec := &recordedEvents{}
+	p := NewWithDocker(newMockDocker()).WithEvents(rec)
+	log := logger.FromContext(p.withEvents(context.Background())).With("context", "prod")
+
+	logger.StartStep(log, "network_create", "web", "resource_kind", "network").OK(true)
+	_ = logger.StartStep(log, "volume_ensure", "data", "resource_kind", "volume").Fail(errors.New("boom"))
+	logger.StartStep(log, "docker", "volume ls", "resource_kind", "process").OK(false)
+	log.Info("plain message", "k", "v")
+	p.pr.Warn("service %s not found.", "db")
+
+	if len(rec.started) != 2 || len(rec.finished) != 2 {
+		t.Fatalf("expected two started and two finished events, got %+v / %+v", rec.started, rec.finished)
+	}
+	if s := rec.started[0]; s.Action != "network_create" || s.Kind != "network" || s.Name != "web" || s.Context != "prod" {
+		t.Fatalf("unexpected started event: %+v", s)
+	}
+	if f := rec.finished[0]; !f.Changed || f.Kind != "network" || f.Err != "" {
+		t.Fatalf("unexpected finished event: %+v", f)
+	}
+	if f := rec.finished[1]; f.Kind != "volume" || f.Err != "boom" || f.Changed {
+		t.Fatalf("unexpected failed event: %+v", f)
+	}
+	if len(rec.warnings) != 1 || rec.warnings[0] != "service db not found." {
+		t.Fatalf("unexpected warnings: %v", rec.warnings)
+	}
+}
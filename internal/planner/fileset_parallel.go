@@ -0,0 +1,103 @@
+package planner
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/filesets"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+// DefaultFilesetParallelism is how many filesets are indexed concurrently
+// when no explicit limit is configured.
+var DefaultFilesetParallelism = min(runtime.NumCPU(), 4)
+
+// effectiveFilesetParallelism returns n, or the default when n is not positive.
+func effectiveFilesetParallelism(n int) int {
+	if n > 0 {
+		return n
+	}
+	return DefaultFilesetParallelism
+}
+
+// forEachBounded calls fn for every name with at most limit calls in flight,
+// and returns once all of them finished.
+func forEachBounded(names []string, limit int, fn func(name string)) {
+	sem := make(chan struct{}, effectiveFilesetParallelism(limit))
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(name)
+		}(name)
+	}
+	wg.Wait()
+}
+
+// preparedFileset holds what a sync needs for one fileset before it mutates
+// anything: its renderer, both indexes and their diff.
+type preparedFileset struct {
+	renderer *filesets.Renderer
+	data     FilesetExecutionData
+	err      error
+}
+
+// buildFilesetIndex indexes a fileset's source. With the fast hash strategy,
+// files unchanged in size and modification time since remote was written keep
+// their hash from it instead of being read.
+func buildFilesetIndex(fileset manifest.FilesetSpec, renderer *filesets.Renderer, remote filesets.Index) (filesets.Index, error) {
+	if fileset.HashStrategy == "fast" && renderer == nil {
+		return filesets.BuildFastIndex(fileset.SourceAbs, fileset.TargetPath, fileset.Exclude, remote)
+	}
+	return filesets.BuildRenderedIndex(fileset.SourceAbs, fileset.TargetPath, fileset.Exclude, renderer)
+}
+
+// prepareFilesets renders, indexes and reads the remote index of every named
+// fileset concurrently, bounded by the manager's parallelism. Indexes and diffs
+// cached in execCtx by the plan are reused instead of recomputed.
+func (fm *FilesetManager) prepareFilesets(ctx context.Context, cfg manifest.Config, names []string, specs map[string]manifest.FilesetSpec, existingVolumes map[string]struct{}, execCtx *ContextExecutionContext) map[string]*preparedFileset {
+	out := make(map[string]*preparedFileset, len(names))
+	for _, name := range names {
+		out[name] = &preparedFileset{}
+	}
+	forEachBounded(names, fm.parallelism, func(name string) {
+		res := out[name]
+		fileset := specs[name]
+		if res.renderer, res.err = filesetRenderer(ctx, cfg, name, fileset); res.err != nil {
+			res.err = apperr.Wrap("filesetmanager.SyncFilesetsForContext", apperr.InvalidInput, res.err, "template variables for fileset %s", name)
+			return
+		}
+		if execCtx != nil && execCtx.Filesets != nil && execCtx.Filesets[name] != nil {
+			res.data = *execCtx.Filesets[name]
+			return
+		}
+
+		// Only read from volume if it exists to avoid implicit creation
+		raw := ""
+		if _, volumeExists := existingVolumes[fileset.TargetVolume]; volumeExists {
+			var err error
+			raw, err = fm.docker.ReadFileFromVolume(ctx, fileset.TargetVolume, fileset.TargetPath, filesets.IndexFileName)
+			if err != nil {
+				res.err = apperr.Wrap("filesetmanager.SyncFilesetsForContext", apperr.External, err, "read index file for fileset %s", name)
+				return
+			}
+		}
+		remote, err := filesets.ParseIndexJSON(raw)
+		if err != nil {
+			res.err = apperr.Wrap("filesetmanager.SyncFilesetsForContext", apperr.External, err, "parse remote index for fileset %s", name)
+			return
+		}
+		local, err := buildFilesetIndex(fileset, res.renderer, remote)
+		if err != nil {
+			res.err = apperr.Wrap("filesetmanager.SyncFilesetsForContext", apperr.Internal, err, "index local filesets for %s", name)
+			return
+		}
+		res.data = FilesetExecutionData{LocalIndex: local, RemoteIndex: remote, Diff: filesets.DiffIndexes(local, remote)}
+	})
+	return out
+}
@@ -0,0 +1,140 @@
+package planner
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+func TestForEachBounded_CapsConcurrency(t *testing.T) {
+	names := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	var inFlight, peak atomic.Int32
+	var mu sync.Mutex
+	seen := map[string]bool{}
+
+	forEachBounded(names, 3, func(name string) {
+		n := inFlight.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		inFlight.Add(-1)
+		mu.Lock()
+		seen[name] = true
+		mu.Unlock()
+	})
+
+	if len(seen) != len(names) {
+		t.Fatalf("expected every fileset visited, got %v", seen)
+	}
+	if p := peak.Load(); p > 3 || p < 2 {
+		t.Fatalf("expected at most 3 (and some) concurrent calls, peak was %d", p)
+	}
+}
+
+func TestEffectiveFilesetParallelism(t *testing.T) {
+	if got := effectiveFilesetParallelism(0); got != DefaultFilesetParallelism {
+		t.Fatalf("expected default for 0, got %d", got)
+	}
+	if got := effectiveFilesetParallelism(7); got != 7 {
+		t.Fatalf("expected explicit limit kept, got %d", got)
+	}
+}
+
+func TestSyncGroups(t *testing.T) {
+	specs := map[string]manifest.FilesetSpec{
+		"a": {TargetVolume: "v1"},
+		"b": {TargetVolume: "v2"},
+		"c": {TargetVolume: "v1"},
+		"d": {TargetVolume: "v3", ApplyMode: "cold"},
+		"e": {TargetVolume: "v4", ApplyMode: "cold"},
+		"f": {TargetVolume: "v3"},
+	}
+	names := []string{"a", "b", "c", "d", "e", "f"}
+	want := [][]string{{"a", "c"}, {"b"}, {"d", "e", "f"}}
+	if got := syncGroups(names, specs, 4); !reflect.DeepEqual(got, want) {
+		t.Fatalf("groups = %v, want %v", got, want)
+	}
+	if got := syncGroups(names, specs, 1); !reflect.DeepEqual(got, [][]string{names}) {
+		t.Fatalf("expected a single group without concurrency, got %v", got)
+	}
+}
+
+// extractTrackingDocker records how many extractions overlap, overall and
+// per volume. Volume writes are serialized since the mock is not thread-safe.
+type extractTrackingDocker struct {
+	*mockDockerClient
+	mu       sync.Mutex
+	inFlight map[string]int
+	active   int
+	peak     int
+	overlaps []string // volumes extracted into by two syncs at once
+	writes   int
+}
+
+func (d *extractTrackingDocker) ExtractTarToVolume(ctx context.Context, volumeName, targetPath string, tarReader io.Reader) error {
+	d.mu.Lock()
+	d.inFlight[volumeName]++
+	if d.inFlight[volumeName] > 1 {
+		d.overlaps = append(d.overlaps, volumeName)
+	}
+	d.active++
+	d.peak = max(d.peak, d.active)
+	d.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.inFlight[volumeName]--
+	d.active--
+	return d.mockDockerClient.ExtractTarToVolume(ctx, volumeName, targetPath, tarReader)
+}
+
+func (d *extractTrackingDocker) WriteFileToVolume(ctx context.Context, volumeName, targetPath, relFile, content string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.writes++
+	return d.mockDockerClient.WriteFileToVolume(ctx, volumeName, targetPath, relFile, content)
+}
+
+func TestSyncFilesetsForContext_ConcurrentAcrossVolumes(t *testing.T) {
+	cfg := manifest.Config{
+		Identifier:         "demo",
+		Contexts:           map[string]manifest.ContextConfig{"default": {}},
+		DiscoveredFilesets: map[string]manifest.FilesetSpec{},
+	}
+	for name, volume := range map[string]string{"a": "v1", "b": "v1", "c": "v2", "d": "v3"} {
+		src := t.TempDir()
+		if err := os.WriteFile(filepath.Join(src, name+".txt"), []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cfg.DiscoveredFilesets[name] = manifest.FilesetSpec{Context: "default", SourceAbs: src, TargetVolume: volume, TargetPath: "/" + name}
+	}
+
+	d := &extractTrackingDocker{mockDockerClient: newMockDocker(), inFlight: map[string]int{}}
+	fm := NewFilesetManager(d, nil).WithConcurrency(3)
+	if _, err := fm.SyncFilesetsForContext(context.Background(), cfg, "default", map[string]struct{}{}, nil); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if len(d.overlaps) > 0 {
+		t.Fatalf("expected filesets on the same volume to be serialized, overlapped on %v", d.overlaps)
+	}
+	if d.peak < 2 {
+		t.Fatalf("expected filesets on different volumes to sync concurrently, peak was %d", d.peak)
+	}
+	if d.writes != 4 {
+		t.Fatalf("expected an index written per fileset, got %d writes", d.writes)
+	}
+}
@@ -0,0 +1,41 @@
+package planner
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/filesets"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+// filesetRenderer returns the template renderer for a fileset marked
+// template: true, with variables from its stack's resolved environment (env
+// files, inline env and SOPS secrets). It returns nil for plain filesets.
+func filesetRenderer(ctx context.Context, cfg manifest.Config, key string, fileset manifest.FilesetSpec) (*filesets.Renderer, error) {
+	if !fileset.Template {
+		return nil, nil
+	}
+	// Fileset keys are <context>/<stack>/<fileset>.
+	stackKey := key
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		stackKey = key[:i]
+	}
+	stack, ok := cfg.GetAllStacks()[stackKey]
+	if !ok {
+		return nil, apperr.New("planner.filesetRenderer", apperr.NotFound, "fileset %s: stack %s not found for template variables", key, stackKey)
+	}
+	env, err := NewServiceStateDetector(nil).BuildInlineEnv(ctx, stack, cfg.Sops)
+	if err != nil {
+		return nil, err
+	}
+	// BuildInlineEnv already merged the env files when the stack resolves its
+	// environment; otherwise merge them here, reading env files as compose
+	// does so templates see the values the containers get.
+	if stack.Environment == nil || !stack.Environment.Resolve {
+		if env, err = resolveStackEnv(stack, env); err != nil {
+			return nil, err
+		}
+	}
+	return filesets.NewRenderer(env), nil
+}
@@ -0,0 +1,118 @@
+package planner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/filesets"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+func templatedFilesetConfig(t *testing.T, host string) manifest.Config {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "stack.env"), []byte("HOST="+host+"\n"), 0o644); err != nil {
+		t.Fatalf("write env file: %v", err)
+	}
+	src := filepath.Join(root, "config")
+	if err := os.MkdirAll(src, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "app.conf"), []byte("server_name {{ .HOST }};\nlisten {{ .PORT }};\n"), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "logo.bin"), []byte("{{ .HOST }}\x00\x01"), 0o644); err != nil {
+		t.Fatalf("write binary: %v", err)
+	}
+	return manifest.Config{
+		Identifier: "demo",
+		Contexts:   map[string]manifest.ContextConfig{"default": {}},
+		Stacks: map[string]manifest.Stack{
+			"default/web": {Root: root, EnvFile: []string{"stack.env"}, EnvInline: []string{"PORT=8080"}},
+		},
+		DiscoveredFilesets: map[string]manifest.FilesetSpec{
+			"default/web/config": {Context: "default", SourceAbs: src, TargetVolume: "web_config", TargetPath: "/etc/app", ApplyMode: "hot", Template: true},
+		},
+	}
+}
+
+func TestSyncFilesetsForContext_RendersTemplates(t *testing.T) {
+	cfg := templatedFilesetConfig(t, "example.org")
+	d := newMockDocker()
+
+	if _, err := NewFilesetManager(d, nil).SyncFilesetsForContext(context.Background(), cfg, "default", map[string]struct{}{}, nil); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if got := d.extractedFiles["web_config:app.conf"]; got != "server_name example.org;\nlisten 8080;\n" {
+		t.Fatalf("expected rendered app.conf, got %q", got)
+	}
+	if got := d.extractedFiles["web_config:logo.bin"]; got != "{{ .HOST }}\x00\x01" {
+		t.Fatalf("expected binary file copied verbatim, got %q", got)
+	}
+}
+
+func TestBuildPlan_TemplateVariableChangeIsDrift(t *testing.T) {
+	cfg := templatedFilesetConfig(t, "example.org")
+	d := newMockDocker()
+	d.volumes = []string{"web_config"}
+
+	renderer, err := filesetRenderer(context.Background(), cfg, "default/web/config", cfg.DiscoveredFilesets["default/web/config"])
+	if err != nil {
+		t.Fatalf("renderer: %v", err)
+	}
+	if got := renderer.Vars["HOST"]; got != "example.org" {
+		t.Fatalf("expected HOST from the stack env file, got %q", got)
+	}
+
+	// The volume holds what was rendered for another host.
+	other := templatedFilesetConfig(t, "example.com")
+	otherRenderer, err := filesetRenderer(context.Background(), other, "default/web/config", other.DiscoveredFilesets["default/web/config"])
+	if err != nil {
+		t.Fatalf("renderer: %v", err)
+	}
+	d.volumeFiles = map[string]string{"web_config": mustRenderedIndexJSON(t, other.DiscoveredFilesets["default/web/config"], otherRenderer)}
+
+	plan, err := NewWithDocker(d).BuildPlan(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("BuildPlan: %v", err)
+	}
+	if out := plan.String(); !strings.Contains(out, "app.conf") || strings.Contains(out, "logo.bin") {
+		t.Fatalf("expected only the rendered app.conf to differ, got:\n%s", out)
+	}
+}
+
+func mustRenderedIndexJSON(t *testing.T, fs manifest.FilesetSpec, r *filesets.Renderer) string {
+	t.Helper()
+	idx, err := filesets.BuildRenderedIndex(fs.SourceAbs, fs.TargetPath, fs.Exclude, r)
+	if err != nil {
+		t.Fatalf("index: %v", err)
+	}
+	js, err := idx.ToJSON()
+	if err != nil {
+		t.Fatalf("index json: %v", err)
+	}
+	return js
+}
+
+func TestFilesetRenderer_KeepsDollarValues(t *testing.T) {
+	cfg := templatedFilesetConfig(t, "example.org")
+	stack := cfg.Stacks["default/web"]
+	if err := os.WriteFile(filepath.Join(stack.Root, "stack.env"), []byte("HOST=example.org\nPASS=pa$$word\nURL=${HOST:-none}/x\n"), 0o644); err != nil {
+		t.Fatalf("write env file: %v", err)
+	}
+	stack.EnvInline = []string{"TOKEN=a$b${c}"}
+	cfg.Stacks["default/web"] = stack
+
+	renderer, err := filesetRenderer(context.Background(), cfg, "default/web/config", cfg.DiscoveredFilesets["default/web/config"])
+	if err != nil {
+		t.Fatalf("renderer: %v", err)
+	}
+	for key, want := range map[string]string{"PASS": "pa$word", "URL": "example.org/x", "TOKEN": "a$b${c}"} {
+		if got := renderer.Vars[key]; got != want {
+			t.Fatalf("%s: got %q, want %q", key, got, want)
+		}
+	}
+}
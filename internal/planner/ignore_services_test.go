@@ -0,0 +1,52 @@
+package planner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+func TestGetPlannedServices_SkipsIgnoredServices(t *testing.T) {
+	d := newMockDocker()
+	d.composeDocs = map[string]dockercli.ComposeConfigDoc{
+		"/tmp/website": {Services: map[string]dockercli.ComposeService{"nginx": {}, "tool": {}}},
+	}
+	stack := manifest.Stack{Root: "/tmp/website", Files: []string{"compose.yaml"}, IgnoreServices: []string{"tool"}}
+
+	got, err := NewServiceStateDetector(d).GetPlannedServices(context.Background(), stack, nil)
+	if err != nil {
+		t.Fatalf("GetPlannedServices: %v", err)
+	}
+	if len(got) != 1 || got[0] != "nginx" {
+		t.Fatalf("expected only nginx to be planned, got %v", got)
+	}
+}
+
+func TestPlanner_Prune_KeepsIgnoredServiceContainers(t *testing.T) {
+	d := newMockDocker()
+	d.composeDocs = map[string]dockercli.ComposeConfigDoc{
+		"/tmp/website": {Services: map[string]dockercli.ComposeService{"nginx": {}}},
+	}
+	d.containers = []dockercli.PsBrief{
+		{Name: "website-nginx-1", Project: "website", Service: "nginx"},
+		{Name: "website-tool-run-1", Project: "website", Service: "tool"},
+		{Name: "old-svc-1", Project: "old", Service: "old"},
+	}
+	d.volumes = []string{}
+	cfg := manifest.Config{
+		Identifier: "test",
+		Contexts:   map[string]manifest.ContextConfig{"default": {}},
+		Stacks: map[string]manifest.Stack{
+			"default/website": {Root: "/tmp/website", Files: []string{"compose.yaml"}, IgnoreServices: []string{"tool"}},
+		},
+	}
+
+	if err := NewWithDocker(d).Prune(context.Background(), cfg); err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if len(d.removedContainers) != 1 || d.removedContainers[0] != "old-svc-1" {
+		t.Fatalf("expected only the orphan removed, got %v", d.removedContainers)
+	}
+}
@@ -0,0 +1,58 @@
+package planner
+
+import (
+	"context"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/logger"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+// markUpdatedImages marks as drifted the up-to-date services of a stack whose
+// container runs a different image than the service's image reference now
+// resolves to locally, e.g. after a :latest tag was rebuilt or re-pulled.
+// Compose up recreates such containers. Services without an image reference,
+// or whose image is not present locally, are left as they are.
+func (p *Planner) markUpdatedImages(ctx context.Context, client DockerClient, stack manifest.Stack, inline []string, services []ServiceInfo) []ServiceInfo {
+	if !p.recreateIfImageUpdated || client == nil {
+		return services
+	}
+	log := logger.FromContext(ctx)
+	doc, err := client.ComposeConfigFull(dockercli.WithStack(ctx, stack), stack.Root, stack.Files, stack.Profiles, stack.EnvFile, inline)
+	if err != nil {
+		log.Debug("image_update_check_skipped", "root", stack.Root, "error", err.Error())
+		return services
+	}
+
+	resolved := map[string]string{}
+	for i, svc := range services {
+		if (svc.State != ServiceRunning && svc.State != ServiceStopped) || svc.Container == nil || svc.Container.Name == "" {
+			continue
+		}
+		image := doc.Services[svc.Name].Image
+		if image == "" {
+			continue
+		}
+		want, seen := resolved[image]
+		if !seen {
+			if want, err = client.ImageID(ctx, image); err != nil {
+				log.Debug("image_update_check_failed", "image", image, "error", err.Error())
+				want = ""
+			}
+			resolved[image] = want
+		}
+		if want == "" {
+			continue
+		}
+		running, err := client.InspectContainerImage(ctx, svc.Container.Name)
+		if err != nil || running == "" {
+			continue
+		}
+		if running != want {
+			log.Info("image_updated", "service", svc.Name, "container", svc.Container.Name, "running_image", running, "resolved_image", want)
+			services[i].State = ServiceDrifted
+			services[i].ImageUpdated = true
+		}
+	}
+	return services
+}
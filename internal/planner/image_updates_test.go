@@ -0,0 +1,57 @@
+package planner
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+)
+
+func TestApply_RecreateIfImageUpdated(t *testing.T) {
+	d, cfg := stoppedServiceMock()
+	d.composePsItems[0].State = "running"
+	// The container runs sha256:website-nginx-1 while nginx:latest was re-pulled.
+	d.imageIDs = map[string]string{"nginx:latest": "sha256:rebuilt"}
+
+	plan, err := NewWithDocker(d).BuildPlan(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("BuildPlan: %v", err)
+	}
+	if out := plan.String(); strings.Contains(out, "image updated") {
+		t.Fatalf("expected no image check without the option, got:\n%s", out)
+	}
+
+	p := NewWithDocker(d).WithRecreateIfImageUpdated(true)
+	plan, err = p.BuildPlan(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("BuildPlan: %v", err)
+	}
+	if out := plan.String(); !strings.Contains(out, "nginx will be updated (image updated)") {
+		t.Fatalf("expected the service with an updated image to be recreated, got:\n%s", out)
+	}
+	if _, err := p.Apply(context.Background(), cfg); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if d.composeUps != 1 {
+		t.Errorf("expected compose up to recreate the service, got %d ups", d.composeUps)
+	}
+}
+
+func TestMarkUpdatedImages_SameOrUnknownImageIsUpToDate(t *testing.T) {
+	d, cfg := stoppedServiceMock()
+	stack := cfg.Stacks["default/website"]
+	p := NewWithDocker(d).WithRecreateIfImageUpdated(true)
+	running := func() []ServiceInfo {
+		return []ServiceInfo{{Name: "nginx", State: ServiceRunning, Container: &dockercli.ComposePsItem{Name: "website-nginx-1"}}}
+	}
+
+	// Image not present locally: nothing to compare against.
+	if got := p.markUpdatedImages(context.Background(), d, stack, nil, running()); got[0].State != ServiceRunning {
+		t.Fatalf("expected unknown image to leave the service running, got %v", got[0].State)
+	}
+	d.imageIDs = map[string]string{"nginx:latest": "sha256:website-nginx-1"}
+	if got := p.markUpdatedImages(context.Background(), d, stack, nil, running()); got[0].State != ServiceRunning || got[0].ImageUpdated {
+		t.Fatalf("expected matching image to leave the service running, got %+v", got[0])
+	}
+}
@@ -3,6 +3,7 @@ package planner
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/gcstr/dockform/internal/dockercli"
 )
@@ -14,6 +15,7 @@ type DockerClient interface {
 	ListVolumes(ctx context.Context) ([]string, error)
 	CreateVolume(ctx context.Context, name string, labels map[string]string) error
 	RemoveVolume(ctx context.Context, name string) error
+	IsVolumeEmpty(ctx context.Context, volumeName string) (bool, error)
 
 	// Volume file operations
 	ReadFileFromVolume(ctx context.Context, volumeName, targetPath, relFile string) (string, error)
@@ -31,26 +33,41 @@ type DockerClient interface {
 	CreateNetwork(ctx context.Context, name string, labels map[string]string, opts ...dockercli.NetworkCreateOpts) error
 	RemoveNetwork(ctx context.Context, name string) error
 	InspectNetwork(ctx context.Context, name string) (dockercli.NetworkInspect, error)
+	ContainerNetworkEndpoint(ctx context.Context, container, network string) (dockercli.NetworkEndpoint, error)
+	ConnectNetwork(ctx context.Context, network, container string, endpoint dockercli.NetworkEndpoint) error
+	DisconnectNetwork(ctx context.Context, network, container string) error
 
 	// Container operations
 	ListComposeContainersAll(ctx context.Context) ([]dockercli.PsBrief, error)
 	ListContainersUsingVolume(ctx context.Context, volumeName string) ([]string, error)
 	ListRunningContainersUsingVolume(ctx context.Context, volumeName string) ([]string, error)
 	RestartContainer(ctx context.Context, name string) error
-	StopContainers(ctx context.Context, names []string) error
+	StopContainers(ctx context.Context, names []string, opts ...dockercli.StopOptions) error
 	StartContainers(ctx context.Context, names []string) error
 	RemoveContainer(ctx context.Context, name string, force bool) error
-	UpdateContainerLabels(ctx context.Context, containerName string, labels map[string]string) error
 	InspectContainerLabels(ctx context.Context, containerName string, keys []string) (map[string]string, error)
 	InspectMultipleContainerLabels(ctx context.Context, containerNames []string, keys []string) (map[string]map[string]string, error)
+	InspectContainerImage(ctx context.Context, containerName string) (string, error)
+	LastHealthCheck(ctx context.Context, name string) (string, error)
+	WaitHealthy(ctx context.Context, name string, timeout time.Duration) error
+
+	// Image operations
+	CheckImageAvailability(ctx context.Context, imageRef string) (dockercli.ImageAvailability, error)
+	ImageID(ctx context.Context, imageRef string) (string, error)
 
 	// Compose operations
+	ComposeVersion(ctx context.Context) (string, error)
 	ComposeConfigFull(ctx context.Context, root string, files []string, profiles []string, envFiles []string, inline []string) (dockercli.ComposeConfigDoc, error)
 	ComposeConfigServices(ctx context.Context, root string, files []string, profiles []string, envFiles []string, inline []string) ([]string, error)
 	ComposeConfigHash(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project, serviceName, identifier string, inline []string) (string, error)
 	ComposeConfigHashes(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project string, services []string, identifier string, inline []string) (map[string]string, error)
 	ComposePs(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project string, inline []string) ([]dockercli.ComposePsItem, error)
 	ComposeUp(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project string, inline []string) (string, error)
+	ComposeUpServices(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project string, services []string, inline []string) (string, error)
+	ComposeRecreateServices(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project string, services []string, inline []string) (string, error)
+	ComposeUpServiceImage(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project, service, image string, inline []string) (string, error)
+	ComposeRun(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project, service string, command []string, inline []string) (string, error)
+	ComposeServiceStatuses(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project string, services []string, inline []string) ([]dockercli.ServiceStatus, error)
 }
 
 // Ensure that dockercli.Client implements DockerClient interface
@@ -1,9 +1,13 @@
 package planner
 
 import (
+	"archive/tar"
 	"context"
 	"io"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gcstr/dockform/internal/dockercli"
 )
@@ -11,13 +15,22 @@ import (
 // mockDockerClient provides a mock implementation of DockerClient for testing.
 type mockDockerClient struct {
 	// Mock data to return
-	volumes         []string
-	networks        []string
-	composeNetworks []string // subset of networks owned by a compose stack
-	containers      []dockercli.PsBrief
-	composePsItems  []dockercli.ComposePsItem
-	volumeFiles     map[string]string            // volumeName -> file content
-	containerLabels map[string]map[string]string // containerName -> labels
+	volumes           []string
+	networks          []string
+	composeNetworks   []string // subset of networks owned by a compose stack
+	containers        []dockercli.PsBrief
+	composePsItems    []dockercli.ComposePsItem
+	serviceStatuses   []dockercli.ServiceStatus              // nil: every service reports running
+	volumeFiles       map[string]string                      // volumeName -> file content
+	containerLabels   map[string]map[string]string           // containerName -> labels
+	healthChecks      map[string]string                      // containerName -> last health check output
+	networkInspects   map[string]dockercli.NetworkInspect    // networkName -> inspect result
+	networkEndpoints  map[string]dockercli.NetworkEndpoint   // "container/network" -> endpoint
+	composeDocs       map[string]dockercli.ComposeConfigDoc  // root -> compose config, overrides the website default
+	imageAvailability map[string]dockercli.ImageAvailability // imageRef -> availability (default present)
+	imageIDs          map[string]string                      // imageRef -> local image ID (default unknown)
+	composeVersion    string                                 // reported compose plugin version
+	nonEmptyVolumes   map[string]bool                        // volumes IsVolumeEmpty reports as holding data
 
 	// Track operations performed
 	createdVolumes      []string
@@ -25,14 +38,28 @@ type mockDockerClient struct {
 	restartedContainers []string
 	startedContainers   []string
 	stoppedContainers   []string
+	stopOptions         map[string]dockercli.StopOptions // container -> options it was stopped with
 	removedContainers   []string
 	removedVolumes      []string
 	removedNetworks     []string
 	writtenFiles        map[string]string   // fileName -> content
 	extractedTars       []string            // volume names that had tars extracted
+	extractedFiles      map[string]string   // "volume:path" -> content of each extracted file
 	removedPaths        map[string][]string // volumeName -> removed paths
 	runVolumeScriptRuns int
+	composeRuns         []string // "service: command" per ComposeRun call
+	composeUps          int
+	composeServiceUps   []string // services per ComposeUpServices call, in call order
+	composeRecreates    []string // services per ComposeRecreateServices call, in call order
+	recreateKeepsIDs    bool     // ComposeRecreateServices leaves the containers in place
+	statusRequests      []string // services per ComposeServiceStatuses call, in call order
+	healthMu            sync.Mutex
+	healthWaits         []string         // containers WaitHealthy was called for
+	healthWaitErrors    map[string]error // containerName -> WaitHealthy result
+	rolledBack          []string         // "service=image" per ComposeUpServiceImage call
 	readIndexBatchCalls int
+	networkOpts         map[string]dockercli.NetworkCreateOpts // networkName -> create options
+	networkConnects     []string                               // "connect|disconnect network container"
 
 	// Control behavior
 	listVolumesError             error
@@ -48,8 +75,12 @@ type mockDockerClient struct {
 	extractTarError              error
 	removePathsError             error
 	runVolumeScriptError         error
+	runVolumeScriptStderr        string
+	composeRunError              error
+	composeConfigError           error
 	containersUsingVolume        []string
 	runningContainersUsingVolume []string
+	profileServices              map[string]dockercli.ComposeService // added to compose config when all profiles are enabled
 }
 
 // newMockDocker creates a new mock Docker client with sensible defaults.
@@ -128,7 +159,7 @@ func (m *mockDockerClient) RunVolumeScript(ctx context.Context, volumeName, targ
 	if m.runVolumeScriptError != nil {
 		return dockercli.VolumeScriptResult{}, m.runVolumeScriptError
 	}
-	return dockercli.VolumeScriptResult{Stdout: "Ownership applied successfully\n"}, nil
+	return dockercli.VolumeScriptResult{Stdout: "Ownership applied successfully\n", Stderr: m.runVolumeScriptStderr}, nil
 }
 
 func (m *mockDockerClient) WriteFileToVolume(ctx context.Context, volumeName, targetPath, relFile, content string) error {
@@ -147,6 +178,21 @@ func (m *mockDockerClient) ExtractTarToVolume(ctx context.Context, volumeName, t
 		return m.extractTarError
 	}
 	m.extractedTars = append(m.extractedTars, volumeName)
+	tr := tar.NewReader(tarReader)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			break
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		b, _ := io.ReadAll(tr)
+		if m.extractedFiles == nil {
+			m.extractedFiles = map[string]string{}
+		}
+		m.extractedFiles[volumeName+":"+hdr.Name] = string(b)
+	}
 	return nil
 }
 
@@ -181,6 +227,12 @@ func (m *mockDockerClient) CreateNetwork(ctx context.Context, name string, label
 		return m.createNetworkError
 	}
 	m.createdNetworks = append(m.createdNetworks, name)
+	if len(opts) > 0 {
+		if m.networkOpts == nil {
+			m.networkOpts = map[string]dockercli.NetworkCreateOpts{}
+		}
+		m.networkOpts[name] = opts[0]
+	}
 	m.networks = append(m.networks, name)
 	return nil
 }
@@ -198,9 +250,33 @@ func (m *mockDockerClient) RemoveNetwork(ctx context.Context, name string) error
 }
 
 func (m *mockDockerClient) InspectNetwork(ctx context.Context, name string) (dockercli.NetworkInspect, error) {
+	if ni, ok := m.networkInspects[name]; ok {
+		return ni, nil
+	}
 	return dockercli.NetworkInspect{Name: name}, nil
 }
 
+func (m *mockDockerClient) ContainerNetworkEndpoint(ctx context.Context, container, network string) (dockercli.NetworkEndpoint, error) {
+	return m.networkEndpoints[container+"/"+network], nil
+}
+
+func (m *mockDockerClient) ConnectNetwork(ctx context.Context, network, container string, endpoint dockercli.NetworkEndpoint) error {
+	entry := "connect " + network + " " + container
+	for _, a := range endpoint.Aliases {
+		entry += " alias=" + a
+	}
+	if endpoint.IPv4Address != "" {
+		entry += " ip=" + endpoint.IPv4Address
+	}
+	m.networkConnects = append(m.networkConnects, entry)
+	return nil
+}
+
+func (m *mockDockerClient) DisconnectNetwork(ctx context.Context, network, container string) error {
+	m.networkConnects = append(m.networkConnects, "disconnect "+network+" "+container)
+	return nil
+}
+
 // Container operations
 func (m *mockDockerClient) ListComposeContainersAll(ctx context.Context) ([]dockercli.PsBrief, error) {
 	if m.listComposeContainersError != nil {
@@ -245,11 +321,19 @@ func (m *mockDockerClient) RestartContainer(ctx context.Context, name string) er
 	return nil
 }
 
-func (m *mockDockerClient) StopContainers(ctx context.Context, names []string) error {
+func (m *mockDockerClient) StopContainers(ctx context.Context, names []string, opts ...dockercli.StopOptions) error {
 	if m.stopContainersError != nil {
 		return m.stopContainersError
 	}
 	m.stoppedContainers = append(m.stoppedContainers, names...)
+	if len(opts) > 0 {
+		if m.stopOptions == nil {
+			m.stopOptions = map[string]dockercli.StopOptions{}
+		}
+		for _, n := range names {
+			m.stopOptions[n] = opts[0]
+		}
+	}
 	return nil
 }
 
@@ -266,19 +350,6 @@ func (m *mockDockerClient) RemoveContainer(ctx context.Context, name string, for
 	return nil
 }
 
-func (m *mockDockerClient) UpdateContainerLabels(ctx context.Context, containerName string, labels map[string]string) error {
-	if m.containerLabels == nil {
-		m.containerLabels = make(map[string]map[string]string)
-	}
-	if m.containerLabels[containerName] == nil {
-		m.containerLabels[containerName] = make(map[string]string)
-	}
-	for k, v := range labels {
-		m.containerLabels[containerName][k] = v
-	}
-	return nil
-}
-
 func (m *mockDockerClient) InspectContainerLabels(ctx context.Context, containerName string, keys []string) (map[string]string, error) {
 	result := make(map[string]string)
 	if containerLabels, exists := m.containerLabels[containerName]; exists {
@@ -292,14 +363,28 @@ func (m *mockDockerClient) InspectContainerLabels(ctx context.Context, container
 }
 
 // Compose operations (minimal implementations for testing)
+func (m *mockDockerClient) ComposeVersion(ctx context.Context) (string, error) {
+	return m.composeVersion, nil
+}
+
 func (m *mockDockerClient) ComposeConfigFull(ctx context.Context, root string, files []string, profiles []string, envFiles []string, inline []string) (dockercli.ComposeConfigDoc, error) {
+	if m.composeConfigError != nil {
+		return dockercli.ComposeConfigDoc{}, m.composeConfigError
+	}
+	if doc, ok := m.composeDocs[root]; ok {
+		return doc, nil
+	}
 	// Return a valid config with nginx service for website directory
 	if strings.Contains(root, "website") {
-		return dockercli.ComposeConfigDoc{
-			Services: map[string]dockercli.ComposeService{
-				"nginx": {Image: "nginx:latest"},
-			},
-		}, nil
+		services := map[string]dockercli.ComposeService{
+			"nginx": {Image: "nginx:latest"},
+		}
+		if len(profiles) == 1 && profiles[0] == "*" {
+			for name, svc := range m.profileServices {
+				services[name] = svc
+			}
+		}
+		return dockercli.ComposeConfigDoc{Services: services}, nil
 	}
 	return dockercli.ComposeConfigDoc{}, nil
 }
@@ -317,9 +402,79 @@ func (m *mockDockerClient) ComposePs(ctx context.Context, root string, files []s
 }
 
 func (m *mockDockerClient) ComposeUp(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project string, inline []string) (string, error) {
+	m.composeUps++
+	return "compose up output", nil
+}
+
+func (m *mockDockerClient) IsVolumeEmpty(ctx context.Context, volumeName string) (bool, error) {
+	return !m.nonEmptyVolumes[volumeName], nil
+}
+
+func (m *mockDockerClient) ComposeUpServices(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project string, services []string, inline []string) (string, error) {
+	m.composeServiceUps = append(m.composeServiceUps, strings.Join(services, ","))
+	return "compose up output", nil
+}
+
+func (m *mockDockerClient) ComposeRecreateServices(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project string, services []string, inline []string) (string, error) {
+	m.composeRecreates = append(m.composeRecreates, strings.Join(services, ","))
+	if !m.recreateKeepsIDs {
+		for i, it := range m.composePsItems {
+			if slices.Contains(services, it.Service) {
+				m.composePsItems[i].ID = it.ID + "-recreated"
+			}
+		}
+	}
 	return "compose up output", nil
 }
 
+func (m *mockDockerClient) ComposeUpServiceImage(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project, service, image string, inline []string) (string, error) {
+	m.rolledBack = append(m.rolledBack, service+"="+image)
+	return "", nil
+}
+
+func (m *mockDockerClient) InspectContainerImage(ctx context.Context, containerName string) (string, error) {
+	return "sha256:" + containerName, nil
+}
+
+func (m *mockDockerClient) LastHealthCheck(ctx context.Context, name string) (string, error) {
+	return m.healthChecks[name], nil
+}
+
+func (m *mockDockerClient) WaitHealthy(ctx context.Context, name string, timeout time.Duration) error {
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+	m.healthWaits = append(m.healthWaits, name)
+	return m.healthWaitErrors[name]
+}
+
+func (m *mockDockerClient) CheckImageAvailability(ctx context.Context, imageRef string) (dockercli.ImageAvailability, error) {
+	if a, ok := m.imageAvailability[imageRef]; ok {
+		return a, nil
+	}
+	return dockercli.ImagePresent, nil
+}
+
+func (m *mockDockerClient) ImageID(ctx context.Context, imageRef string) (string, error) {
+	return m.imageIDs[imageRef], nil
+}
+
+func (m *mockDockerClient) ComposeRun(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project, service string, command []string, inline []string) (string, error) {
+	m.composeRuns = append(m.composeRuns, service+": "+strings.Join(command, " "))
+	return "", m.composeRunError
+}
+
+func (m *mockDockerClient) ComposeServiceStatuses(ctx context.Context, root string, files []string, profiles []string, envFiles []string, project string, services []string, inline []string) ([]dockercli.ServiceStatus, error) {
+	m.statusRequests = append(m.statusRequests, strings.Join(services, ","))
+	if m.serviceStatuses != nil {
+		return m.serviceStatuses, nil
+	}
+	out := make([]dockercli.ServiceStatus, 0, len(services))
+	for _, svc := range services {
+		out = append(out, dockercli.ServiceStatus{Service: svc, Container: svc, State: "running"})
+	}
+	return out, nil
+}
+
 // Batch container operations
 func (m *mockDockerClient) InspectContainerLabelsBatch(ctx context.Context, containers []string, labelKeys []string) (map[string]map[string]string, error) {
 	result := make(map[string]map[string]string)
@@ -0,0 +1,194 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/logger"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+// networkCreateOpts translates a manifest network spec into docker network
+// create flags.
+func networkCreateOpts(spec manifest.NetworkSpec) dockercli.NetworkCreateOpts {
+	return dockercli.NetworkCreateOpts{
+		Driver:       spec.Driver,
+		Options:      spec.Options,
+		Internal:     spec.Internal,
+		Attachable:   spec.Attachable,
+		IPv6:         spec.IPv6,
+		Subnet:       spec.Subnet,
+		Gateway:      spec.Gateway,
+		IPRange:      spec.IPRange,
+		AuxAddresses: spec.AuxAddresses,
+	}
+}
+
+// networkDrift lists how an existing network differs from its spec, one
+// "field: actual → desired" entry per difference. Fields the spec leaves
+// unset are not compared, since docker fills them with its own defaults.
+func networkDrift(spec manifest.NetworkSpec, actual dockercli.NetworkInspect) []string {
+	var diffs []string
+	differs := func(field, have, want string) {
+		if have == "" {
+			have = "unset"
+		}
+		diffs = append(diffs, fmt.Sprintf("%s: %s → %s", field, have, want))
+	}
+
+	if spec.Driver != "" && spec.Driver != actual.Driver {
+		differs("driver", actual.Driver, spec.Driver)
+	}
+	for _, k := range sortedKeys(spec.Options) {
+		if actual.Options[k] != spec.Options[k] {
+			differs("option "+k, actual.Options[k], spec.Options[k])
+		}
+	}
+	if spec.Internal != actual.Internal {
+		differs("internal", fmt.Sprint(actual.Internal), fmt.Sprint(spec.Internal))
+	}
+	if spec.Attachable != actual.Attachable {
+		differs("attachable", fmt.Sprint(actual.Attachable), fmt.Sprint(spec.Attachable))
+	}
+	if spec.IPv6 != actual.EnableIPv6 {
+		differs("ipv6", fmt.Sprint(actual.EnableIPv6), fmt.Sprint(spec.IPv6))
+	}
+
+	// Compare IPAM against the pool holding the desired subnet, or the first
+	// pool when no subnet is pinned.
+	var pool dockercli.NetworkInspectIPAMConfig
+	if len(actual.IPAM.Config) > 0 {
+		pool = actual.IPAM.Config[0]
+	}
+	for _, c := range actual.IPAM.Config {
+		if spec.Subnet != "" && c.Subnet == spec.Subnet {
+			pool = c
+			break
+		}
+	}
+	if spec.Subnet != "" && spec.Subnet != pool.Subnet {
+		differs("subnet", pool.Subnet, spec.Subnet)
+	}
+	if spec.Gateway != "" && spec.Gateway != pool.Gateway {
+		differs("gateway", pool.Gateway, spec.Gateway)
+	}
+	if spec.IPRange != "" && spec.IPRange != pool.IPRange {
+		differs("ip_range", pool.IPRange, spec.IPRange)
+	}
+	for _, host := range sortedKeys(spec.AuxAddresses) {
+		if pool.AuxAddresses[host] != spec.AuxAddresses[host] {
+			differs("aux_address "+host, pool.AuxAddresses[host], spec.AuxAddresses[host])
+		}
+	}
+	return diffs
+}
+
+// RecreateDriftedNetworksForContext recreates every existing network of the
+// context whose actual configuration differs from its spec. Docker cannot
+// update a network in place, so each one is detached from its containers,
+// removed, created again from the spec and reattached.
+func (rm *ResourceManager) RecreateDriftedNetworksForContext(ctx context.Context, cfg manifest.Config, contextName string, labels map[string]string, existingNetworks map[string]struct{}) error {
+	log := logger.FromContext(ctx).With("component", "resourcemanager", "context", contextName)
+
+	if rm.docker == nil {
+		return apperr.New("resourcemanager.RecreateDriftedNetworksForContext", apperr.Precondition, "docker client not configured")
+	}
+
+	contextConfig, ok := cfg.Contexts[contextName]
+	if !ok {
+		return nil
+	}
+
+	for _, netName := range sortedKeys(contextConfig.Networks) {
+		if _, exists := existingNetworks[netName]; !exists {
+			continue
+		}
+		spec := contextConfig.Networks[netName]
+		actual, err := rm.docker.InspectNetwork(ctx, netName)
+		if err != nil {
+			return apperr.Wrap("resourcemanager.RecreateDriftedNetworksForContext", apperr.External, err, "inspect network %s", netName)
+		}
+		diffs := networkDrift(spec, actual)
+		if len(diffs) == 0 {
+			continue
+		}
+
+		if rm.progress != nil {
+			rm.progress.SetAction("recreating network " + netName)
+		}
+		st := logger.StartStep(log, "network_recreate", netName,
+			"resource_kind", "network", "drift", strings.Join(diffs, "; "))
+		if err := rm.recreateNetwork(ctx, netName, spec, labels, actual); err != nil {
+			return st.Fail(err)
+		}
+		st.OK(true)
+	}
+	return nil
+}
+
+// recreateNetwork replaces a network with one built from spec, reattaching the
+// containers that were connected to it with the aliases (compose's service
+// names among them) and static addresses they had.
+func (rm *ResourceManager) recreateNetwork(ctx context.Context, name string, spec manifest.NetworkSpec, labels map[string]string, actual dockercli.NetworkInspect) error {
+	containers := make([]string, 0, len(actual.Containers))
+	for _, c := range actual.Containers {
+		if c.Name != "" {
+			containers = append(containers, c.Name)
+		}
+	}
+	sort.Strings(containers)
+
+	endpoints := make(map[string]dockercli.NetworkEndpoint, len(containers))
+	for _, c := range containers {
+		ep, err := rm.docker.ContainerNetworkEndpoint(ctx, c, name)
+		if err != nil {
+			return apperr.Wrap("resourcemanager.recreateNetwork", apperr.External, err, "inspect endpoint of container %s on network %s", c, name)
+		}
+		endpoints[c] = ep
+	}
+
+	for _, c := range containers {
+		if err := rm.docker.DisconnectNetwork(ctx, name, c); err != nil {
+			return apperr.Wrap("resourcemanager.recreateNetwork", apperr.External, err, "disconnect container %s from network %s", c, name)
+		}
+	}
+	if err := rm.docker.RemoveNetwork(ctx, name); err != nil {
+		return apperr.Wrap("resourcemanager.recreateNetwork", apperr.External, err, "remove network %s", name)
+	}
+	if err := rm.docker.CreateNetwork(ctx, name, labels, networkCreateOpts(spec)); err != nil {
+		return apperr.Wrap("resourcemanager.recreateNetwork", apperr.External, err, "create network %s (disconnected containers: %s)", name, strings.Join(containers, ", "))
+	}
+	for _, c := range containers {
+		if err := rm.docker.ConnectNetwork(ctx, name, c, endpoints[c]); err != nil {
+			return apperr.Wrap("resourcemanager.recreateNetwork", apperr.External, err, "reconnect container %s to network %s", c, name)
+		}
+	}
+	return nil
+}
+
+// planExistingNetwork plans a network that already exists. A network that
+// drifted from its spec is recreated when enabled; otherwise the drift is only
+// reported, since recreating a network detaches every container from it.
+func (p *Planner) planExistingNetwork(ctx context.Context, client DockerClient, name string, spec manifest.NetworkSpec, rp *ResourcePlan) (Resource, error) {
+	if client == nil {
+		return NewResource(ResourceNetwork, name, ActionNoop, "exists"), nil
+	}
+	actual, err := client.InspectNetwork(ctx, name)
+	if err != nil {
+		return Resource{}, apperr.Wrap("planner.BuildPlan", apperr.External, err, "inspect network %s", name)
+	}
+	diffs := networkDrift(spec, actual)
+	if len(diffs) == 0 {
+		return NewResource(ResourceNetwork, name, ActionNoop, "exists"), nil
+	}
+	drift := strings.Join(diffs, ", ")
+	if p.recreateNetworks {
+		return NewResource(ResourceNetwork, name, ActionReconcile, "recreate: "+drift), nil
+	}
+	rp.Warnings = append(rp.Warnings, fmt.Sprintf("network %s differs from its spec (%s); remove and recreate it manually, or apply with --recreate-networks", name, drift))
+	return NewResource(ResourceNetwork, name, ActionNoop, "exists, differs from spec"), nil
+}
@@ -0,0 +1,102 @@
+package planner
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+	"github.com/gcstr/dockform/internal/ui"
+)
+
+func TestNetworkDrift(t *testing.T) {
+	spec := manifest.NetworkSpec{
+		Driver:  "bridge",
+		Options: map[string]string{"com.docker.network.bridge.enable_icc": "false"},
+		Subnet:  "10.10.0.0/24",
+	}
+	actual := dockercli.NetworkInspect{
+		Driver: "bridge",
+		IPAM:   dockercli.NetworkInspectIPAM{Config: []dockercli.NetworkInspectIPAMConfig{{Subnet: "172.18.0.0/16", Gateway: "172.18.0.1"}}},
+	}
+
+	got := strings.Join(networkDrift(spec, actual), "; ")
+	want := "option com.docker.network.bridge.enable_icc: unset → false; subnet: 172.18.0.0/16 → 10.10.0.0/24"
+	if got != want {
+		t.Fatalf("networkDrift = %q, want %q", got, want)
+	}
+
+	actual.Options = map[string]string{"com.docker.network.bridge.enable_icc": "false"}
+	actual.IPAM.Config = append(actual.IPAM.Config, dockercli.NetworkInspectIPAMConfig{Subnet: "10.10.0.0/24"})
+	if diffs := networkDrift(spec, actual); len(diffs) != 0 {
+		t.Fatalf("expected no drift once the network matches, got %v", diffs)
+	}
+}
+
+func driftedNetworkMock() (*mockDockerClient, manifest.Config) {
+	d := newMockDocker()
+	d.networks = []string{"web"}
+	d.networkInspects = map[string]dockercli.NetworkInspect{
+		"web": {
+			Name:   "web",
+			Driver: "bridge",
+			Containers: map[string]struct {
+				Name string `json:"Name"`
+			}{"abc": {Name: "app-web-1"}},
+		},
+	}
+	d.networkEndpoints = map[string]dockercli.NetworkEndpoint{
+		"app-web-1/web": {Aliases: []string{"app-web-1", "web"}, IPv4Address: "10.10.0.5"},
+	}
+	cfg := manifest.Config{
+		Identifier: "test-id",
+		Contexts: map[string]manifest.ContextConfig{
+			"default": {Networks: map[string]manifest.NetworkSpec{"web": {Driver: "bridge", Internal: true}}},
+		},
+	}
+	return d, cfg
+}
+
+func TestBuildPlan_WarnsAboutNetworkDrift(t *testing.T) {
+	d, cfg := driftedNetworkMock()
+
+	plan, err := NewWithDocker(d).BuildPlan(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("BuildPlan: %v", err)
+	}
+	if c, u, del := plan.Resources.CountActions(); c+u+del != 0 {
+		t.Fatalf("drift alone must not plan changes, got create=%d update=%d delete=%d", c, u, del)
+	}
+	out := ui.StripANSI(plan.Render(PlanRenderOptions{}))
+	if !strings.Contains(out, "network web differs from its spec (internal: false → true)") || !strings.Contains(out, "--recreate-networks") {
+		t.Fatalf("expected drift warning in plan, got:\n%s", out)
+	}
+}
+
+func TestApply_RecreateNetworks(t *testing.T) {
+	d, cfg := driftedNetworkMock()
+	p := NewWithDocker(d).WithRecreateNetworks(true)
+
+	plan, err := p.BuildPlan(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("BuildPlan: %v", err)
+	}
+	if _, u, _ := plan.Resources.CountActions(); u != 1 {
+		t.Fatalf("expected the network to be planned for recreation, got:\n%s", plan.String())
+	}
+
+	if _, err := p.Apply(context.Background(), cfg); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(d.removedNetworks) != 1 || len(d.createdNetworks) != 1 {
+		t.Fatalf("expected web removed and created again, got removed=%v created=%v", d.removedNetworks, d.createdNetworks)
+	}
+	if !d.networkOpts["web"].Internal {
+		t.Errorf("expected web recreated from its spec, got %+v", d.networkOpts["web"])
+	}
+	want := []string{"disconnect web app-web-1", "connect web app-web-1 alias=app-web-1 alias=web ip=10.10.0.5"}
+	if strings.Join(d.networkConnects, ",") != strings.Join(want, ",") {
+		t.Errorf("expected containers detached and reattached with their aliases and address, got %v", d.networkConnects)
+	}
+}
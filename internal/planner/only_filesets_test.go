@@ -0,0 +1,158 @@
+package planner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+func onlyFilesetsConfig(t *testing.T) manifest.Config {
+	t.Helper()
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "index.html"), []byte("hello"), 0o644); err != nil {
+		t.Fatalf("write test file: %v", err)
+	}
+	return manifest.Config{
+		Identifier: "demo",
+		Contexts: map[string]manifest.ContextConfig{
+			"default": {Networks: map[string]manifest.NetworkSpec{"frontend": {}}},
+		},
+		Stacks: map[string]manifest.Stack{
+			"default/web": {Root: t.TempDir(), Files: []string{"compose.yml"}},
+		},
+		DiscoveredFilesets: map[string]manifest.FilesetSpec{
+			"assets": {
+				Context:         "default",
+				SourceAbs:       src,
+				TargetVolume:    "data",
+				TargetPath:      "/opt/data",
+				RestartServices: manifest.RestartTargets{Services: []string{"web"}},
+			},
+		},
+	}
+}
+
+func TestOnlyFilesets_PlanSkipsStacksAndNetworks(t *testing.T) {
+	d := newMockDocker()
+	p := NewWithDocker(d).WithOnlyFilesets(true)
+
+	plan, err := p.BuildPlan(context.Background(), onlyFilesetsConfig(t))
+	if err != nil {
+		t.Fatalf("build plan: %v", err)
+	}
+	rp := plan.Resources
+	if len(rp.Stacks) != 0 || len(rp.Networks) != 0 {
+		t.Fatalf("expected no stack or network resources, got stacks=%v networks=%v", rp.Stacks, rp.Networks)
+	}
+	if len(rp.Volumes) != 1 || rp.Volumes[0].Name != "data" || rp.Volumes[0].Action != ActionCreate {
+		t.Fatalf("expected the fileset volume to be created, got %v", rp.Volumes)
+	}
+	if len(rp.Filesets["assets"]) == 0 {
+		t.Fatalf("expected fileset changes in plan, got %v", rp.Filesets)
+	}
+}
+
+func TestOnlyFilesets_ApplySyncsAndRestartsWithoutCompose(t *testing.T) {
+	d := newMockDocker()
+	d.containers = []dockercli.PsBrief{{Project: "web", Service: "web", Name: "web-web-1"}}
+	p := NewWithDocker(d).WithOnlyFilesets(true)
+
+	if _, err := p.Apply(context.Background(), onlyFilesetsConfig(t)); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if d.composeUps != 0 || len(d.createdNetworks) != 0 {
+		t.Fatalf("expected no compose up or network creation, got ups=%d networks=%v", d.composeUps, d.createdNetworks)
+	}
+	if len(d.createdVolumes) != 1 || len(d.extractedTars) != 1 {
+		t.Fatalf("expected volume creation and fileset sync, got volumes=%v tars=%v", d.createdVolumes, d.extractedTars)
+	}
+	if len(d.restartedContainers) != 1 || d.restartedContainers[0] != "web-web-1" {
+		t.Fatalf("expected restart of fileset service, got %v", d.restartedContainers)
+	}
+}
+
+func TestOnlyFilesets_NoRestartSkipsRestart(t *testing.T) {
+	d := newMockDocker()
+	d.containers = []dockercli.PsBrief{{Project: "web", Service: "web", Name: "web-web-1"}}
+	p := NewWithDocker(d).WithOnlyFilesets(true).WithNoRestart(true)
+
+	if _, err := p.Apply(context.Background(), onlyFilesetsConfig(t)); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if len(d.extractedTars) != 1 {
+		t.Fatalf("expected fileset sync, got %v", d.extractedTars)
+	}
+	if len(d.restartedContainers) != 0 {
+		t.Fatalf("expected no restarts with --no-restart, got %v", d.restartedContainers)
+	}
+}
+
+func TestNoRestart_ColdFilesetSyncsWithoutStopping(t *testing.T) {
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "index.html"), []byte("hello"), 0o644); err != nil {
+		t.Fatalf("write test file: %v", err)
+	}
+	d := newMockDocker()
+	d.containers = []dockercli.PsBrief{{Project: "demo", Service: "web", Name: "demo-web-1"}}
+
+	pending, err := NewFilesetManager(d, nil).WithNoRestart(true).SyncFilesetsForContext(
+		context.Background(), coldFilesetConfig(t, src), "default", map[string]struct{}{"data": {}}, nil)
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if len(d.stoppedContainers) != 0 || len(d.startedContainers) != 0 {
+		t.Fatalf("expected no stop/start with --no-restart, got stopped=%v started=%v", d.stoppedContainers, d.startedContainers)
+	}
+	if len(d.extractedTars) != 1 || len(d.writtenFiles) == 0 {
+		t.Fatalf("expected files and index to be written, got tars=%v files=%v", d.extractedTars, d.writtenFiles)
+	}
+	if _, ok := pending["web"]; !ok || len(pending) != 1 {
+		t.Fatalf("expected web reported as deferred restart, got %v", pending)
+	}
+}
+
+func TestNoRestart_PlanNotesDeferredRestarts(t *testing.T) {
+	d := newMockDocker()
+	p := NewWithDocker(d).WithOnlyFilesets(true).WithNoRestart(true)
+
+	plan, err := p.BuildPlan(context.Background(), onlyFilesetsConfig(t))
+	if err != nil {
+		t.Fatalf("build plan: %v", err)
+	}
+	out := RenderResourcePlanOpts(plan.Resources, PlanRenderOptions{})
+	if !strings.Contains(out, "would restart web (skipped: --no-restart)") {
+		t.Fatalf("expected deferred restart note in plan, got:\n%s", out)
+	}
+}
+
+func TestRestartMounting_RestartsServicesWithoutRestartServices(t *testing.T) {
+	cfg := onlyFilesetsConfig(t)
+	fs := cfg.DiscoveredFilesets["assets"]
+	fs.RestartServices = manifest.RestartTargets{}
+	cfg.DiscoveredFilesets["assets"] = fs
+
+	d := newMockDocker()
+	d.containers = []dockercli.PsBrief{{Project: "web", Service: "web", Name: "web-web-1"}}
+	pending, err := NewFilesetManager(d, nil).SyncFilesetsForContext(context.Background(), cfg, "default", map[string]struct{}{"data": {}}, nil)
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("expected no restarts without restart_services, got %v", pending)
+	}
+
+	d = newMockDocker()
+	d.containers = []dockercli.PsBrief{{Project: "web", Service: "web", Name: "web-web-1"}}
+	pending, err = NewFilesetManager(d, nil).WithRestartMounting(true).SyncFilesetsForContext(context.Background(), cfg, "default", map[string]struct{}{"data": {}}, nil)
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if _, ok := pending["web"]; !ok || len(pending) != 1 {
+		t.Fatalf("expected mounting service web to be restarted, got %v", pending)
+	}
+}
@@ -0,0 +1,49 @@
+package planner
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+func TestPlanFileset_PlansOnlyThatFileset(t *testing.T) {
+	cfg := onlyFilesetsConfig(t)
+	other := cfg.DiscoveredFilesets["assets"]
+	other.TargetVolume = "other"
+	cfg.DiscoveredFilesets["other"] = other
+	cfg.Contexts["default"] = manifest.ContextConfig{
+		Networks: map[string]manifest.NetworkSpec{"frontend": {}},
+		Volumes:  map[string]manifest.TopLevelResourceSpec{"db": {}},
+	}
+
+	d := newMockDocker()
+	plan, err := NewWithDocker(d).WithPlanFileset("assets").BuildPlan(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("build plan: %v", err)
+	}
+	rp := plan.Resources
+	if len(rp.Stacks) != 0 || len(rp.Networks) != 0 || len(rp.Containers) != 0 {
+		t.Fatalf("expected no stack, network or container resources, got stacks=%v networks=%v containers=%v", rp.Stacks, rp.Networks, rp.Containers)
+	}
+	for _, v := range rp.Volumes {
+		if v.Name == "db" || v.Name == "data" || v.Name == "other" {
+			t.Fatalf("expected no volume resources, got %v", rp.Volumes)
+		}
+	}
+	if len(rp.Filesets["assets"]) == 0 {
+		t.Fatalf("expected changes for the selected fileset, got %v", rp.Filesets)
+	}
+	if _, ok := rp.Filesets["other"]; ok {
+		t.Fatalf("expected other filesets to be skipped, got %v", rp.Filesets)
+	}
+}
+
+func TestPlanFileset_UnknownNameFails(t *testing.T) {
+	_, err := NewWithDocker(newMockDocker()).WithPlanFileset("missing").BuildPlan(context.Background(), onlyFilesetsConfig(t))
+	if !apperr.IsKind(err, apperr.InvalidInput) || !strings.Contains(err.Error(), `unknown fileset "missing"`) || !strings.Contains(err.Error(), "assets") {
+		t.Fatalf("expected invalid input naming the unknown and available filesets, got: %v", err)
+	}
+}
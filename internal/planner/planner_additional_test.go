@@ -82,7 +82,7 @@ func TestBuildPlan_ComposeConfigError(t *testing.T) {
 func TestApply_Precondition_NoDocker(t *testing.T) {
 	// With multi-context architecture, empty config is a no-op (no contexts to process)
 	cfg := manifest.Config{}
-	err := New().Apply(context.Background(), cfg)
+	_, err := New().Apply(context.Background(), cfg)
 	if err != nil {
 		t.Fatalf("expected no error for empty config, got: %v", err)
 	}
@@ -93,7 +93,7 @@ func TestApply_Precondition_NoDocker(t *testing.T) {
 		Contexts:   map[string]manifest.ContextConfig{"default": {}},
 		Stacks:     map[string]manifest.Stack{"default/app": {Root: t.TempDir(), Files: []string{"compose.yml"}}},
 	}
-	err = New().Apply(context.Background(), cfg)
+	_, err = New().Apply(context.Background(), cfg)
 	if err == nil || !strings.Contains(err.Error(), "docker client not available") {
 		t.Fatalf("expected docker client error for context with no client, got: %v", err)
 	}
@@ -110,7 +110,7 @@ func TestApply_ComposeConfigError(t *testing.T) {
 		Stacks:     map[string]manifest.Stack{"default/app": {Root: t.TempDir(), Files: []string{"compose.yml"}}},
 	}
 	d := dockercli.New("")
-	err := NewWithDocker(d).Apply(context.Background(), cfg)
+	_, err := NewWithDocker(d).Apply(context.Background(), cfg)
 	if err == nil || !strings.Contains(err.Error(), "failed to detect service states") {
 		t.Fatalf("expected service detection error, got: %v", err)
 	}
@@ -176,7 +176,7 @@ exit 0
 			t.Logf("unsetenv DOCKER_STUB_LOG: %v", err)
 		}
 	}()
-	if err := NewWithDocker(d).Apply(context.Background(), cfg); err != nil {
+	if _, err := NewWithDocker(d).Apply(context.Background(), cfg); err != nil {
 		t.Fatalf("Apply: %v", err)
 	}
 	// Assert that 'up' was not called
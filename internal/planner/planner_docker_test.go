@@ -29,6 +29,9 @@ case "$cmd" in
     if [ "$sub" = "ls" ]; then echo "nOld"; exit 0; fi
     if [ "$sub" = "rm" ]; then echo "rm network $1" >> "$log"; exit 0; fi
     ;;
+  run)
+    # IsVolumeEmpty helper container
+    echo "empty"; exit 0 ;;
   container)
     if [ "$1" = "rm" ]; then shift; echo "rm container $*" >> "$log"; exit 0; fi
     ;;
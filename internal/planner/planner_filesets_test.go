@@ -195,7 +195,7 @@ func TestApply_Filesets_SyncAndRestart(t *testing.T) {
 		},
 	}
 	d := dockercli.New("").WithIdentifier("demo")
-	if err := NewWithDocker(d).Apply(context.Background(), cfg); err != nil {
+	if _, err := NewWithDocker(d).Apply(context.Background(), cfg); err != nil {
 		t.Fatalf("apply: %v", err)
 	}
 	b, _ := os.ReadFile(log)
@@ -245,7 +245,7 @@ func TestApply_PropagatesVolumeListError(t *testing.T) {
 		Stacks:     map[string]manifest.Stack{},
 	}
 	d := dockercli.New("").WithIdentifier("demo")
-	err := NewWithDocker(d).Apply(context.Background(), cfg)
+	_, err := NewWithDocker(d).Apply(context.Background(), cfg)
 	if err == nil || !strings.Contains(err.Error(), "list volumes") {
 		t.Fatalf("expected list volumes error, got: %v", err)
 	}
@@ -0,0 +1,90 @@
+package planner
+
+import (
+	"context"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+// allComposeProfiles enables every profile defined in a compose project.
+const allComposeProfiles = "*"
+
+// collectProfileScopedServicesForStack records the services a stack defines
+// under at least one compose profile, mapped to those profiles. Containers of
+// such services may be running from a profile the manifest does not activate,
+// and must not be mistaken for orphans.
+func collectProfileScopedServicesForStack(ctx context.Context, client DockerClient, stack manifest.Stack, sopsConfig *manifest.SopsConfig, scoped map[string][]string) error {
+	detector := NewServiceStateDetector(client)
+	inline, err := detector.BuildInlineEnv(ctx, stack, sopsConfig)
+	if err != nil {
+		return apperr.Wrap("planner.collectProfileScopedServicesForStack", apperr.External, err, "build inline env for stack %s", stack.Root)
+	}
+	doc, err := client.ComposeConfigFull(dockercli.WithStack(ctx, stack), stack.Root, stack.Files, []string{allComposeProfiles}, stack.EnvFile, inline)
+	if err != nil {
+		return apperr.Wrap("planner.collectProfileScopedServicesForStack", apperr.External, err, "list profile-scoped services for stack %s", stack.Root)
+	}
+	for name, svc := range doc.Services {
+		if len(svc.Profiles) > 0 {
+			scoped[name] = append(scoped[name], svc.Profiles...)
+		}
+	}
+	return nil
+}
+
+// profileScopedOrphans returns the containers whose service is not desired but
+// belongs to an inactive profile of a managed stack. When a container carries
+// the compose profiles label, at least one of its profiles must be defined for
+// the service; otherwise the service being profile-scoped is enough.
+func profileScopedOrphans(containers []dockercli.PsBrief, desired map[string]struct{}, scoped map[string][]string) map[string]struct{} {
+	kept := map[string]struct{}{}
+	for _, it := range containers {
+		if _, want := desired[it.Service]; want {
+			continue
+		}
+		profiles, ok := scoped[it.Service]
+		if !ok {
+			continue
+		}
+		if len(it.Profiles) == 0 || sharesProfile(it.Profiles, profiles) {
+			kept[it.Name] = struct{}{}
+		}
+	}
+	return kept
+}
+
+func sharesProfile(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// profileScopedContainers returns the names of containers that must survive
+// orphan detection because their service sits behind an inactive profile of
+// one of the given stacks. Profile discovery only runs when some container's
+// service is missing from desired.
+func profileScopedContainers(ctx context.Context, client DockerClient, stacks map[string]manifest.Stack, sopsConfig *manifest.SopsConfig, containers []dockercli.PsBrief, desired map[string]struct{}) (map[string]struct{}, error) {
+	undesired := false
+	for _, it := range containers {
+		if _, want := desired[it.Service]; !want {
+			undesired = true
+			break
+		}
+	}
+	if !undesired {
+		return map[string]struct{}{}, nil
+	}
+	scoped := map[string][]string{}
+	for _, name := range sortedKeys(stacks) {
+		if err := collectProfileScopedServicesForStack(ctx, client, stacks[name], sopsConfig, scoped); err != nil {
+			return nil, err
+		}
+	}
+	return profileScopedOrphans(containers, desired, scoped), nil
+}
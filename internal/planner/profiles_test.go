@@ -0,0 +1,84 @@
+package planner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+func profileScopedConfig(t *testing.T) manifest.Config {
+	t.Helper()
+	return manifest.Config{
+		Identifier: "demo",
+		Contexts:   map[string]manifest.ContextConfig{"default": {}},
+		Stacks: map[string]manifest.Stack{
+			"default/website": {Root: t.TempDir() + "/website", Files: []string{"compose.yml"}},
+		},
+	}
+}
+
+func profileScopedDocker() *mockDockerClient {
+	d := newMockDocker()
+	d.profileServices = map[string]dockercli.ComposeService{
+		"debug": {Image: "busybox", Profiles: []string{"tools"}},
+	}
+	d.containers = []dockercli.PsBrief{
+		{Project: "website", Service: "nginx", Name: "website-nginx-1"},
+		{Project: "website", Service: "debug", Name: "website-debug-1"},
+		{Project: "website", Service: "legacy", Name: "website-legacy-1"},
+	}
+	return d
+}
+
+func TestPrune_KeepsContainerOfInactiveProfile(t *testing.T) {
+	d := profileScopedDocker()
+	if err := NewWithDocker(d).Prune(context.Background(), profileScopedConfig(t)); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if len(d.removedContainers) != 1 || d.removedContainers[0] != "website-legacy-1" {
+		t.Fatalf("expected only the undefined service to be pruned, got %v", d.removedContainers)
+	}
+}
+
+func TestPrune_ProfileLabelMustMatchDefinedProfile(t *testing.T) {
+	d := profileScopedDocker()
+	d.containers[1].Profiles = []string{"other"}
+	if err := NewWithDocker(d).Prune(context.Background(), profileScopedConfig(t)); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if len(d.removedContainers) != 2 {
+		t.Fatalf("expected container with unknown profile label to be pruned, got %v", d.removedContainers)
+	}
+
+	d = profileScopedDocker()
+	d.containers[1].Profiles = []string{"tools"}
+	if err := NewWithDocker(d).Prune(context.Background(), profileScopedConfig(t)); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if len(d.removedContainers) != 1 || d.removedContainers[0] != "website-legacy-1" {
+		t.Fatalf("expected labeled profile container to be kept, got %v", d.removedContainers)
+	}
+}
+
+func TestBuildPlan_DoesNotDeleteProfileScopedService(t *testing.T) {
+	plan, err := NewWithDocker(profileScopedDocker()).BuildPlan(context.Background(), profileScopedConfig(t))
+	if err != nil {
+		t.Fatalf("build plan: %v", err)
+	}
+	for _, res := range plan.Resources.Stacks["default/website"] {
+		if res.Name == "debug" && res.Action == ActionDelete {
+			t.Fatalf("profile-scoped service must not be planned for deletion: %v", plan.Resources.Stacks["default/website"])
+		}
+	}
+	found := false
+	for _, res := range plan.Resources.Stacks["default/website"] {
+		if res.Name == "legacy" && res.Action == ActionDelete {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected undefined service to be planned for deletion, got %v", plan.Resources.Stacks["default/website"])
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/logger"
 	"github.com/gcstr/dockform/internal/manifest"
 )
 
@@ -21,6 +23,8 @@ func (p *Planner) PruneWithPlan(ctx context.Context, cfg manifest.Config, plan *
 
 // PruneWithPlanOptions removes unmanaged resources using explicit cleanup behavior options.
 func (p *Planner) PruneWithPlanOptions(ctx context.Context, cfg manifest.Config, plan *Plan, opts CleanupOptions) error {
+	ctx = p.withSecretsCache(ctx)
+
 	// Skip pruning when targeting specific stacks — we only have a partial view of desired state
 	if cfg.Targeted {
 		return nil
@@ -29,6 +33,9 @@ func (p *Planner) PruneWithPlanOptions(ctx context.Context, cfg manifest.Config,
 	if p.docker == nil && p.factory == nil {
 		return apperr.New("planner.Prune", apperr.Precondition, "docker client not configured")
 	}
+	if err := stopIfDraining(ctx, "planner.Prune", "pruning"); err != nil {
+		return err
+	}
 
 	// Prune mutates state (removes containers/networks/volumes), so contexts
 	// always run to completion: a failure on one host must never cancel
@@ -46,15 +53,16 @@ func (p *Planner) PruneWithPlanOptions(ctx context.Context, cfg manifest.Config,
 
 // pruneContext removes unmanaged resources for a single context.
 func (p *Planner) pruneContext(ctx context.Context, cfg manifest.Config, contextName string, client DockerClient, plan *Plan) error {
+	log := logger.FromContext(ctx).With("component", "prune", "context", contextName)
 	contextStacks := cfg.GetStacksForContext(contextName)
 	contextFilesets := cfg.GetFilesetsForContext(contextName)
 
 	// Desired services set for this context
 	desiredServices := map[string]struct{}{}
 	var errs []error
-	canPruneContainers := true
+	canPruneContainers := p.pruneFilter.Allows(ResourceContainer)
 
-	if plan != nil && plan.ExecutionContext != nil {
+	if canPruneContainers && plan != nil && plan.ExecutionContext != nil {
 		if contextCtx := plan.ExecutionContext.ByContext[contextName]; contextCtx != nil {
 			for stackName, stack := range contextStacks {
 				if execData := contextCtx.Stacks[stackName]; execData != nil && execData.Services != nil {
@@ -76,7 +84,7 @@ func (p *Planner) pruneContext(ctx context.Context, cfg manifest.Config, context
 				}
 			}
 		}
-	} else {
+	} else if canPruneContainers {
 		for _, stack := range contextStacks {
 			if err := collectDesiredServicesForStack(ctx, client, stack, cfg.Sops, desiredServices); err != nil {
 				canPruneContainers = false
@@ -85,16 +93,35 @@ func (p *Planner) pruneContext(ctx context.Context, cfg manifest.Config, context
 		}
 	}
 
+	// Services a stack ignores are never orphans, even when started by hand.
+	for _, stack := range contextStacks {
+		for _, name := range stack.IgnoreServices {
+			desiredServices[name] = struct{}{}
+		}
+	}
+
 	// Remove labeled containers not in desired set
 	if canPruneContainers {
 		all, err := client.ListComposeContainersAll(ctx)
 		if err != nil {
 			errs = append(errs, apperr.Wrap("planner.pruneContext", apperr.External, err, "list managed containers for context %s", contextName))
 		} else {
-			for _, it := range all {
-				if _, want := desiredServices[it.Service]; !want {
-					if err := client.RemoveContainer(ctx, it.Name, true); err != nil {
-						errs = append(errs, apperr.Wrap("planner.pruneContext", apperr.External, err, "remove unmanaged container %s in context %s", it.Name, contextName))
+			// Keep containers of services behind an inactive compose profile.
+			kept, err := profileScopedContainers(ctx, client, contextStacks, cfg.Sops, all, desiredServices)
+			if err != nil {
+				errs = append(errs, err)
+			} else {
+				for _, it := range all {
+					if _, keep := kept[it.Name]; keep {
+						continue
+					}
+					if _, want := desiredServices[it.Service]; !want {
+						st := logger.StartStep(log, "container_prune", it.Name, "resource_kind", "container", "service", it.Service)
+						if err := client.RemoveContainer(ctx, it.Name, true); err != nil {
+							errs = append(errs, st.Fail(apperr.Wrap("planner.pruneContext", apperr.External, err, "remove unmanaged container %s in context %s", it.Name, contextName)))
+						} else {
+							st.OK(true)
+						}
 					}
 				}
 			}
@@ -102,58 +129,151 @@ func (p *Planner) pruneContext(ctx context.Context, cfg manifest.Config, context
 	}
 
 	// Remove labeled volumes not needed by any fileset or explicit context config
-	desiredVolumes := map[string]struct{}{}
-	for _, fileset := range contextFilesets {
-		desiredVolumes[fileset.TargetVolume] = struct{}{}
-	}
-	// Add explicit volumes from context config
-	if contextConfig, ok := cfg.Contexts[contextName]; ok {
-		for volName := range contextConfig.Volumes {
-			desiredVolumes[volName] = struct{}{}
+	if p.pruneFilter.Allows(ResourceVolume) {
+		desiredVolumes := map[string]struct{}{}
+		for _, fileset := range contextFilesets {
+			desiredVolumes[fileset.TargetVolume] = struct{}{}
 		}
-	}
-	vols, err := client.ListVolumes(ctx)
-	if err != nil {
-		errs = append(errs, apperr.Wrap("planner.pruneContext", apperr.External, err, "list managed volumes for context %s", contextName))
-	} else {
-		for _, v := range vols {
-			if _, want := desiredVolumes[v]; !want {
-				if err := client.RemoveVolume(ctx, v); err != nil {
-					errs = append(errs, apperr.Wrap("planner.pruneContext", apperr.External, err, "remove unmanaged volume %s in context %s", v, contextName))
+		// Add explicit volumes from context config
+		if contextConfig, ok := cfg.Contexts[contextName]; ok {
+			for volName := range contextConfig.Volumes {
+				desiredVolumes[volName] = struct{}{}
+			}
+		}
+		vols, err := client.ListVolumes(ctx)
+		if err != nil {
+			errs = append(errs, apperr.Wrap("planner.pruneContext", apperr.External, err, "list managed volumes for context %s", contextName))
+		} else {
+			for _, v := range vols {
+				if _, want := desiredVolumes[v]; !want {
+					if !p.allowDataLoss {
+						if empty, err := client.IsVolumeEmpty(ctx, v); err != nil || !empty {
+							errs = append(errs, apperr.New("planner.pruneContext", apperr.Precondition, "volume %s in context %s is not empty; kept (use --allow-data-loss to delete it)", v, contextName))
+							continue
+						}
+					}
+					st := logger.StartStep(log, "volume_prune", v, "resource_kind", "volume")
+					if err := client.RemoveVolume(ctx, v); err != nil {
+						errs = append(errs, st.Fail(apperr.Wrap("planner.pruneContext", apperr.External, err, "remove unmanaged volume %s in context %s", v, contextName)))
+					} else {
+						st.OK(true)
+					}
 				}
 			}
 		}
 	}
 
 	// Remove labeled networks not defined in context config
-	desiredNetworks := map[string]struct{}{}
-	if contextConfig, ok := cfg.Contexts[contextName]; ok {
-		for netName := range contextConfig.Networks {
-			desiredNetworks[netName] = struct{}{}
+	if p.pruneFilter.Allows(ResourceNetwork) {
+		desiredNetworks := map[string]struct{}{}
+		if contextConfig, ok := cfg.Contexts[contextName]; ok {
+			for netName := range contextConfig.Networks {
+				desiredNetworks[netName] = struct{}{}
+			}
+		}
+		nets, err := client.ListNetworks(ctx)
+		if err != nil {
+			errs = append(errs, apperr.Wrap("planner.pruneContext", apperr.External, err, "list managed networks for context %s", contextName))
+		} else {
+			// Compose-owned networks carry the identifier label but are managed by
+			// their stack's lifecycle, so they must not be pruned as orphans (GH #54).
+			composeOwned, err := p.getComposeOwnedNetworks(ctx, client)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			existing := make(map[string]struct{}, len(nets))
+			for _, n := range nets {
+				existing[n] = struct{}{}
+			}
+			for _, n := range orphanNetworks(existing, desiredNetworks, composeOwned) {
+				st := logger.StartStep(log, "network_prune", n, "resource_kind", "network")
+				if err := client.RemoveNetwork(ctx, n); err != nil {
+					errs = append(errs, st.Fail(apperr.Wrap("planner.pruneContext", apperr.External, err, "remove unmanaged network %s in context %s", n, contextName)))
+				} else {
+					st.OK(true)
+				}
+			}
 		}
 	}
-	nets, err := client.ListNetworks(ctx)
-	if err != nil {
-		errs = append(errs, apperr.Wrap("planner.pruneContext", apperr.External, err, "list managed networks for context %s", contextName))
+
+	return apperr.Aggregate("planner.pruneContext", apperr.External, fmt.Sprintf("prune for context %s failed for one or more resources", contextName), errs...)
+}
+
+// pruneStackOrphans removes the containers of a stack's compose project whose
+// service the stack no longer defines, so a renamed service's old container
+// stops holding ports and other resources before the rest of the apply runs.
+// Errors are surfaced according to opts, like the end-of-run prune.
+func (p *Planner) pruneStackOrphans(ctx context.Context, cfg manifest.Config, contextName, stackName string, stack manifest.Stack, client DockerClient, execCtx *ContextExecutionContext, opts CleanupOptions) error {
+	if !p.pruneFilter.Allows(ResourceContainer) {
+		return nil
+	}
+	log := logger.FromContext(ctx).With("component", "prune", "context", contextName, "stack", stackName)
+	stackKey := manifest.MakeStackKey(contextName, stackName)
+
+	var inline []string
+	if execCtx != nil && execCtx.Stacks[stackName] != nil {
+		inline = execCtx.Stacks[stackName].InlineEnv
 	} else {
-		// Compose-owned networks carry the identifier label but are managed by
-		// their stack's lifecycle, so they must not be pruned as orphans (GH #54).
-		composeOwned, err := p.getComposeOwnedNetworks(ctx, client)
+		env, err := NewServiceStateDetector(client).BuildInlineEnv(ctx, stack, cfg.Sops)
 		if err != nil {
-			errs = append(errs, err)
-		}
-		existing := make(map[string]struct{}, len(nets))
-		for _, n := range nets {
-			existing[n] = struct{}{}
+			return handleCleanupError(ctx, apperr.Wrap("planner.pruneStackOrphans", apperr.External, err, "build inline env for stack %s", stackKey), opts, "prune")
 		}
-		for _, n := range orphanNetworks(existing, desiredNetworks, composeOwned) {
-			if err := client.RemoveNetwork(ctx, n); err != nil {
-				errs = append(errs, apperr.Wrap("planner.pruneContext", apperr.External, err, "remove unmanaged network %s in context %s", n, contextName))
-			}
+		inline = env
+	}
+	doc, err := client.ComposeConfigFull(dockercli.WithStack(ctx, stack), stack.Root, stack.Files, stack.Profiles, stack.EnvFile, inline)
+	if err != nil {
+		return handleCleanupError(ctx, apperr.Wrap("planner.pruneStackOrphans", apperr.External, err, "list planned services for stack %s", stackKey), opts, "prune")
+	}
+	project := doc.Name
+	if stack.Project != nil && stack.Project.Name != "" {
+		project = stack.Project.Name
+	}
+	if project == "" {
+		// Without the project name the stack's containers can't be told apart
+		// from other stacks'; leave them to the end-of-run prune.
+		return nil
+	}
+
+	desired := map[string]struct{}{}
+	for name := range doc.Services {
+		desired[name] = struct{}{}
+	}
+	for _, name := range stack.IgnoreServices {
+		desired[name] = struct{}{}
+	}
+
+	all, err := client.ListComposeContainersAll(ctx)
+	if err != nil {
+		return handleCleanupError(ctx, apperr.Wrap("planner.pruneStackOrphans", apperr.External, err, "list managed containers for context %s", contextName), opts, "prune")
+	}
+	var containers []dockercli.PsBrief
+	for _, it := range all {
+		if it.Project == project {
+			containers = append(containers, it)
 		}
 	}
+	kept, err := profileScopedContainers(ctx, client, map[string]manifest.Stack{stackName: stack}, cfg.Sops, containers, desired)
+	if err != nil {
+		return handleCleanupError(ctx, err, opts, "prune")
+	}
 
-	return apperr.Aggregate("planner.pruneContext", apperr.External, fmt.Sprintf("prune for context %s failed for one or more resources", contextName), errs...)
+	var errs []error
+	for _, it := range containers {
+		if _, keep := kept[it.Name]; keep {
+			continue
+		}
+		if _, want := desired[it.Service]; want {
+			continue
+		}
+		st := logger.StartStep(log, "container_prune", it.Name, "resource_kind", "container", "service", it.Service)
+		if err := client.RemoveContainer(ctx, it.Name, true); err != nil {
+			errs = append(errs, st.Fail(apperr.Wrap("planner.pruneStackOrphans", apperr.External, err, "remove orphaned container %s of stack %s", it.Name, stackKey)))
+		} else {
+			st.OK(true)
+		}
+	}
+	err = apperr.Aggregate("planner.pruneStackOrphans", apperr.External, fmt.Sprintf("prune for stack %s failed for one or more containers", stackKey), errs...)
+	return handleCleanupError(ctx, err, opts, "prune")
 }
 
 // collectDesiredServicesForStack collects service names for a single stack by querying compose config.
@@ -0,0 +1,56 @@
+package planner
+
+import (
+	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+// PruneFilter restricts prune and destroy to a set of resource kinds. The zero
+// value allows every kind.
+type PruneFilter struct {
+	kinds map[ResourceType]bool
+}
+
+// ParsePruneFilter parses --prune-filter values such as "containers" or
+// "networks,volumes". Singular forms are accepted too. An empty list yields a
+// filter that allows everything.
+func ParsePruneFilter(values []string) (PruneFilter, error) {
+	f := PruneFilter{}
+	for _, v := range values {
+		kind := strings.ToLower(strings.TrimSpace(v))
+		if kind == "" {
+			continue
+		}
+		if f.kinds == nil {
+			f.kinds = map[ResourceType]bool{}
+		}
+		switch kind {
+		case "containers", "container":
+			f.kinds[ResourceContainer] = true
+		case "networks", "network":
+			f.kinds[ResourceNetwork] = true
+		case "volumes", "volume":
+			f.kinds[ResourceVolume] = true
+		default:
+			return PruneFilter{}, apperr.New("planner.ParsePruneFilter", apperr.InvalidInput, "invalid --prune-filter %q: unknown resource type (supported: containers, networks, volumes)", v)
+		}
+	}
+	return f, nil
+}
+
+// Allows reports whether resources of kind may be removed. Service containers
+// count as containers, and fileset volumes as volumes.
+func (f PruneFilter) Allows(kind ResourceType) bool {
+	if len(f.kinds) == 0 {
+		return true
+	}
+	return f.kinds[kind]
+}
+
+// WithPruneFilter limits what prune, destroy and the removals shown in their
+// plans may touch to the kinds f allows.
+func (p *Planner) WithPruneFilter(f PruneFilter) *Planner {
+	p.pruneFilter = f
+	return p
+}
@@ -0,0 +1,118 @@
+package planner
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+func TestParsePruneFilter(t *testing.T) {
+	f, err := ParsePruneFilter([]string{"containers", " Network "})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if !f.Allows(ResourceContainer) || !f.Allows(ResourceNetwork) || f.Allows(ResourceVolume) {
+		t.Errorf("unexpected filter %+v", f)
+	}
+
+	all, err := ParsePruneFilter(nil)
+	if err != nil {
+		t.Fatalf("parse empty: %v", err)
+	}
+	for _, kind := range []ResourceType{ResourceContainer, ResourceNetwork, ResourceVolume} {
+		if !all.Allows(kind) {
+			t.Errorf("empty filter should allow %s", kind)
+		}
+	}
+
+	if _, err := ParsePruneFilter([]string{"images"}); !apperr.IsKind(err, apperr.InvalidInput) {
+		t.Errorf("expected InvalidInput for unknown kind, got %v", err)
+	}
+}
+
+func TestPlanner_Prune_FilterContainersOnly(t *testing.T) {
+	mock := newMockDocker()
+	mock.containers = []dockercli.PsBrief{{Name: "orphan-container", Project: "old", Service: "orphan-svc"}}
+	mock.volumes = []string{"orphan-vol"}
+	mock.networks = []string{"orphan-net"}
+
+	f, _ := ParsePruneFilter([]string{"containers"})
+	p := NewWithDocker(mock).WithPruneFilter(f)
+	cfg := manifest.Config{
+		Identifier: "test",
+		Contexts:   map[string]manifest.ContextConfig{"default": {}},
+	}
+
+	if err := p.Prune(context.Background(), cfg); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if len(mock.removedContainers) != 1 {
+		t.Errorf("expected the orphan container removed, got %v", mock.removedContainers)
+	}
+	if len(mock.removedVolumes) != 0 || len(mock.removedNetworks) != 0 {
+		t.Errorf("expected volumes and networks untouched, got volumes=%v networks=%v", mock.removedVolumes, mock.removedNetworks)
+	}
+}
+
+func TestPlanner_BuildPlan_FilterHidesExcludedRemovals(t *testing.T) {
+	mock := newMockDocker()
+	mock.volumes = []string{"orphan-vol"}
+	mock.networks = []string{"orphan-net"}
+
+	f, _ := ParsePruneFilter([]string{"networks"})
+	p := NewWithDocker(mock).WithPruneFilter(f)
+	cfg := manifest.Config{
+		Identifier: "test",
+		Contexts:   map[string]manifest.ContextConfig{"default": {}},
+	}
+
+	plan, err := p.BuildPlan(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("BuildPlan: %v", err)
+	}
+	out := plan.String()
+	if !strings.Contains(out, "orphan-net") {
+		t.Errorf("expected network removal in plan, got:\n%s", out)
+	}
+	if strings.Contains(out, "orphan-vol") {
+		t.Errorf("expected no volume removal in plan, got:\n%s", out)
+	}
+}
+
+func TestDestroy_FilterVolumesOut(t *testing.T) {
+	mock := newMockDocker()
+	mock.containers = []dockercli.PsBrief{{Project: "app", Service: "web", Name: "app-web-1"}}
+	mock.networks = []string{"app-net"}
+	mock.volumes = []string{"data"}
+
+	cfg := manifest.Config{
+		Identifier:         "test",
+		Contexts:           map[string]manifest.ContextConfig{"default": {}},
+		DiscoveredFilesets: map[string]manifest.FilesetSpec{},
+	}
+
+	f, _ := ParsePruneFilter([]string{"containers", "networks"})
+	p := NewWithDocker(mock).WithPruneFilter(f)
+
+	plan, err := p.BuildDestroyPlan(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("BuildDestroyPlan: %v", err)
+	}
+	if out := plan.String(); strings.Contains(out, "data") {
+		t.Errorf("expected volumes left out of the destroy plan, got:\n%s", out)
+	}
+
+	if err := p.Destroy(context.Background(), cfg); err != nil {
+		t.Fatalf("Destroy failed: %v", err)
+	}
+	if len(mock.removedVolumes) != 0 {
+		t.Errorf("expected no volumes removed, got %v", mock.removedVolumes)
+	}
+	if len(mock.removedContainers) != 1 || len(mock.removedNetworks) != 1 {
+		t.Errorf("expected container and network removed, got containers=%v networks=%v", mock.removedContainers, mock.removedNetworks)
+	}
+}
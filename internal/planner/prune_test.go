@@ -2,6 +2,8 @@ package planner
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/gcstr/dockform/internal/apperr"
@@ -64,6 +66,61 @@ func TestPlanner_Prune_RemovesOrphanedVolumes(t *testing.T) {
 	}
 }
 
+func TestPlanner_Prune_KeepsNonEmptyVolumesUnlessDataLossAllowed(t *testing.T) {
+	cfg := manifest.Config{
+		Identifier: "test",
+		Contexts:   map[string]manifest.ContextConfig{"default": {}},
+	}
+
+	mock := newMockDocker()
+	mock.volumes = []string{"data-vol", "empty-vol"}
+	mock.nonEmptyVolumes = map[string]bool{"data-vol": true}
+	err := NewWithDocker(mock).Prune(context.Background(), cfg)
+	if cause := errors.Unwrap(err); !apperr.IsKind(cause, apperr.Precondition) || !strings.Contains(cause.Error(), "data-vol") {
+		t.Fatalf("expected Precondition error for data-vol, got %v", err)
+	}
+	if len(mock.removedVolumes) != 1 || mock.removedVolumes[0] != "empty-vol" {
+		t.Errorf("expected only empty-vol to be removed, got %v", mock.removedVolumes)
+	}
+
+	mock = newMockDocker()
+	mock.volumes = []string{"data-vol"}
+	mock.nonEmptyVolumes = map[string]bool{"data-vol": true}
+	if err := NewWithDocker(mock).WithAllowDataLoss(true).Prune(context.Background(), cfg); err != nil {
+		t.Fatalf("Prune with data loss allowed failed: %v", err)
+	}
+	if len(mock.removedVolumes) != 1 || mock.removedVolumes[0] != "data-vol" {
+		t.Errorf("expected data-vol to be removed, got %v", mock.removedVolumes)
+	}
+}
+
+func TestBuildPlan_FlagsNonEmptyVolumeDeletion(t *testing.T) {
+	mock := newMockDocker()
+	mock.volumes = []string{"data-vol", "empty-vol"}
+	mock.nonEmptyVolumes = map[string]bool{"data-vol": true}
+	cfg := manifest.Config{
+		Identifier: "test",
+		Contexts:   map[string]manifest.ContextConfig{"default": {}},
+	}
+
+	plan, err := NewWithDocker(mock).BuildPlan(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("BuildPlan failed: %v", err)
+	}
+	if got := plan.Resources.DataLoss; len(got) != 1 || got[0] != "data-vol" {
+		t.Fatalf("expected data loss for data-vol only, got %v", got)
+	}
+	found := false
+	for _, w := range plan.Resources.Warnings {
+		if w == "DESTRUCTIVE: volume data-vol is non-empty and will be deleted" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected DESTRUCTIVE warning, got %v", plan.Resources.Warnings)
+	}
+}
+
 func TestPlanner_Prune_RemovesOrphanedContainers(t *testing.T) {
 	mock := newMockDocker()
 	mock.containers = []dockercli.PsBrief{
@@ -0,0 +1,200 @@
+package planner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/manifest"
+	"github.com/gcstr/dockform/internal/secrets"
+)
+
+// resolveStackEnv merges a stack's env files and inline variables (later wins)
+// into one fully-resolved environment. Env file values are interpolated the way
+// compose reads an env file: against the variables resolved before them, then
+// the process environment, leaving single-quoted values alone. Inline values
+// and decrypted secrets are taken literally, as compose takes process
+// environment.
+//
+// The result is passed to compose as process environment, which takes
+// precedence over both the caller's shell and --env-file, so compose cannot
+// re-interpolate a variable differently from what dockform resolved.
+func resolveStackEnv(stack manifest.Stack, inline []string) ([]string, error) {
+	values := map[string]string{}
+	var order []string
+	set := func(key, val string) {
+		if key == "" {
+			return
+		}
+		if _, seen := values[key]; !seen {
+			order = append(order, key)
+		}
+		values[key] = val
+	}
+	lookup := func(name string) (string, bool) {
+		if v, ok := values[name]; ok {
+			return v, true
+		}
+		return os.LookupEnv(name)
+	}
+
+	for _, f := range stack.EnvFile {
+		pth := f
+		if !filepath.IsAbs(pth) {
+			pth = filepath.Join(stack.Root, pth)
+		}
+		entries, err := secrets.ReadDotenvEntries(pth)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			val := e.Value
+			if !e.Literal {
+				if val, err = interpolateCompose(val, lookup); err != nil {
+					return nil, apperr.Wrap("planner.resolveStackEnv", apperr.InvalidInput, err, "env file %s: variable %s: %v", pth, e.Key, err)
+				}
+			}
+			set(e.Key, val)
+		}
+	}
+	for _, kv := range inline {
+		key, val, ok := strings.Cut(kv, "=")
+		if ok {
+			set(key, val)
+		}
+	}
+
+	out := make([]string, 0, len(order))
+	for _, key := range order {
+		out = append(out, key+"="+values[key])
+	}
+	return out, nil
+}
+
+// interpolateCompose expands $VAR and ${VAR} references in s following compose
+// rules: $$ is a literal $, and a braced reference may carry a modifier:
+//
+//	${VAR:-default}  default when VAR is unset or empty
+//	${VAR-default}   default when VAR is unset
+//	${VAR:?message}  error when VAR is unset or empty
+//	${VAR?message}   error when VAR is unset
+//	${VAR:+other}    other when VAR is set and not empty
+//	${VAR+other}     other when VAR is set
+//
+// Defaults, messages and alternatives are interpolated themselves. Unset
+// variables without a modifier expand to the empty string.
+func interpolateCompose(s string, lookup func(string) (string, bool)) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+1 >= len(s) {
+			return "", fmt.Errorf("invalid interpolation %q: trailing $ (use $$ for a literal $)", s)
+		}
+		switch next := s[i+1]; {
+		case next == '$':
+			b.WriteByte('$')
+			i++
+		case next == '{':
+			end := matchingBrace(s, i+1)
+			if end < 0 {
+				return "", fmt.Errorf("invalid interpolation %q: unclosed ${", s)
+			}
+			val, err := expandBraced(s[i+2:end], lookup)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(val)
+			i = end
+		case isVarStart(next):
+			j := i + 2
+			for j < len(s) && isVarChar(s[j]) {
+				j++
+			}
+			val, _ := lookup(s[i+1 : j])
+			b.WriteString(val)
+			i = j - 1
+		default:
+			return "", fmt.Errorf("invalid interpolation %q (use $$ for a literal $)", s)
+		}
+	}
+	return b.String(), nil
+}
+
+// expandBraced expands the inside of a ${...} reference.
+func expandBraced(expr string, lookup func(string) (string, bool)) (string, error) {
+	n := 0
+	for n < len(expr) && isVarChar(expr[n]) {
+		n++
+	}
+	name, rest := expr[:n], expr[n:]
+	if name == "" || !isVarStart(name[0]) {
+		return "", fmt.Errorf("invalid interpolation ${%s}: bad variable name", expr)
+	}
+	val, set := lookup(name)
+	if rest == "" {
+		return val, nil
+	}
+
+	op := rest[:1]
+	if strings.HasPrefix(rest, ":") && len(rest) > 1 {
+		op = rest[:2]
+	}
+	arg := rest[len(op):]
+	switch op {
+	case ":-", "-":
+		if set && (op == "-" || val != "") {
+			return val, nil
+		}
+		return interpolateCompose(arg, lookup)
+	case ":?", "?":
+		if set && (op == "?" || val != "") {
+			return val, nil
+		}
+		msg, err := interpolateCompose(arg, lookup)
+		if err != nil {
+			return "", err
+		}
+		if msg == "" {
+			msg = "required variable " + name + " is missing a value"
+		}
+		return "", errors.New(msg)
+	case ":+", "+":
+		if set && (op == "+" || val != "") {
+			return interpolateCompose(arg, lookup)
+		}
+		return "", nil
+	}
+	return "", fmt.Errorf("invalid interpolation ${%s}", expr)
+}
+
+// matchingBrace returns the index of the } closing the { at open, allowing
+// nested ${...} in defaults, or -1.
+func matchingBrace(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isVarStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isVarChar(c byte) bool {
+	return isVarStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,87 @@
+package planner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+func TestInterpolateCompose(t *testing.T) {
+	vars := map[string]string{"SET": "v", "EMPTY": ""}
+	lookup := func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+	cases := []struct{ in, want string }{
+		{"plain", "plain"},
+		{"pa$$word", "pa$word"},
+		{"$$SET", "$SET"},
+		{"$SET-x", "v-x"},
+		{"${SET}x", "vx"},
+		{"${UNSET}", ""},
+		{"${UNSET:-dflt}", "dflt"},
+		{"${EMPTY:-dflt}", "dflt"},
+		{"${EMPTY-dflt}", ""},
+		{"${UNSET-dflt}", "dflt"},
+		{"${SET:-dflt}", "v"},
+		{"${UNSET:-${SET}/x}", "v/x"},
+		{"${SET:+alt}", "alt"},
+		{"${EMPTY:+alt}", ""},
+		{"${EMPTY+alt}", "alt"},
+		{"${UNSET+alt}", ""},
+		{"${EMPTY?boom}", ""},
+	}
+	for _, c := range cases {
+		got, err := interpolateCompose(c.in, lookup)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", c.in, err)
+		}
+		if got != c.want {
+			t.Fatalf("%q: got %q, want %q", c.in, got, c.want)
+		}
+	}
+
+	for _, in := range []string{"${UNSET:?needs a value}", "${EMPTY:?}", "${UNSET?}", "a$", "${SET", "$-x", "${1X}"} {
+		if _, err := interpolateCompose(in, lookup); err == nil {
+			t.Fatalf("%q: expected an error", in)
+		}
+	}
+	if _, err := interpolateCompose("${UNSET:?needs a value}", lookup); !strings.Contains(err.Error(), "needs a value") {
+		t.Fatalf("expected the custom message, got %v", err)
+	}
+}
+
+func TestResolveStackEnv_FollowsComposeRules(t *testing.T) {
+	t.Setenv("B", "")
+	root := t.TempDir()
+	env := "PASS=pa$$word\nWITH_DEFAULT=${B:-dflt}\nQUOTED='lit$eral${B}'\nREF=${PASS}\n"
+	if err := os.WriteFile(filepath.Join(root, "app.env"), []byte(env), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stack := manifest.Stack{Root: root, EnvFile: []string{"app.env"}}
+
+	// Inline values and decrypted secrets reach compose as process
+	// environment, which compose never interpolates.
+	got, err := resolveStackEnv(stack, []string{"INLINE=a$b", "SECRET=s3$$cr${et}"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "PASS=pa$word\nWITH_DEFAULT=dflt\nQUOTED=lit$eral${B}\nREF=pa$word\nINLINE=a$b\nSECRET=s3$$cr${et}"
+	if s := strings.Join(got, "\n"); s != want {
+		t.Fatalf("resolved env mismatch\n got: %q\nwant: %q", s, want)
+	}
+}
+
+func TestResolveStackEnv_RequiredVariable(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "app.env"), []byte("DB=${DOCKFORM_TEST_UNSET_DB:?set the database}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := resolveStackEnv(manifest.Stack{Root: root, EnvFile: []string{"app.env"}}, nil)
+	if err == nil || !strings.Contains(err.Error(), "set the database") || !strings.Contains(err.Error(), "DB") {
+		t.Fatalf("expected the required-variable error naming DB, got %v", err)
+	}
+}
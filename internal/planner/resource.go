@@ -38,6 +38,7 @@ type Resource struct {
 	Details    string        // Optional details about the action
 	Parent     string        // For nested resources (e.g., fileset name for files)
 	ChangeType ui.ChangeType // Maps to UI change type for rendering
+	Problem    string        // Optional issue expected to make the action fail, e.g. an unavailable image
 }
 
 // ResourcePlan represents a structured plan with resources organized by type
@@ -47,6 +48,8 @@ type ResourcePlan struct {
 	Stacks     map[string][]Resource // Stack name -> services
 	Filesets   map[string][]Resource // Fileset name -> file changes
 	Containers []Resource            // Orphaned containers to remove
+	Warnings   []string              // Issues the plan reports but will not act on
+	DataLoss   []string              // Volumes the plan deletes that still hold data
 }
 
 // NewResource creates a new resource with the appropriate change type
@@ -91,6 +94,10 @@ func (r Resource) FormatAction() string {
 	case ActionCreate:
 		return "will be created"
 	case ActionUpdate:
+		// Services say why they change, e.g. config drift or a stopped container.
+		if r.Type == ResourceService && r.Details != "" {
+			return fmt.Sprintf("will be updated (%s)", r.Details)
+		}
 		return "will be updated"
 	case ActionDelete:
 		return "will be deleted"
@@ -242,6 +249,7 @@ func renderResourcePlanFull(rp *ResourcePlan) string {
 		sections = append(sections, ui.NestedSection{Title: "Containers", Items: items})
 	}
 
+	sections = append(sections, warningsSection(rp))
 	return appendPlanSummary(ui.RenderNestedSections(sections), rp)
 }
 
@@ -249,12 +257,26 @@ func renderResourcePlanFull(rp *ResourcePlan) string {
 // "italic-name action-text" format used by Volumes, Networks, Containers, and
 // Stacks flat items.
 func formatResourceLine(res Resource) ui.DiffLine {
+	msg := fmt.Sprintf("%s %s", ui.Italic(res.Name), res.FormatAction())
+	if res.Problem != "" {
+		msg += " " + ui.RedText("("+res.Problem+")")
+	}
 	return ui.DiffLine{
 		Type:    res.ChangeType,
-		Message: fmt.Sprintf("%s %s", ui.Italic(res.Name), res.FormatAction()),
+		Message: msg,
 	}
 }
 
+// warningsSection lists the plan warnings; it renders nothing when there are
+// none.
+func warningsSection(rp *ResourcePlan) ui.NestedSection {
+	sec := ui.NestedSection{Title: "Warnings"}
+	for _, w := range rp.Warnings {
+		sec.Items = append(sec.Items, ui.DiffLine{Type: ui.Change, Message: ui.YellowText(w)})
+	}
+	return sec
+}
+
 // appendPlanSummary appends a plan summary line to result when there are any
 // creates, updates, or deletes.
 func appendPlanSummary(result string, rp *ResourcePlan) string {
@@ -322,9 +344,21 @@ func totalUnits(rp *ResourcePlan) int {
 // count of unchanged (no-op) resources per section.
 func renderResourcePlanChangesOnly(rp *ResourcePlan) string {
 	if c, u, d := rp.CountActions(); c == 0 && u == 0 && d == 0 {
-		return fmt.Sprintf("No changes. %d resources up to date.", totalUnits(rp))
+		out := fmt.Sprintf("No changes. %d resources up to date.", totalUnits(rp))
+		if len(rp.Warnings) > 0 {
+			out += "\n" + ui.RenderNestedSections([]ui.NestedSection{warningsSection(rp)})
+		}
+		return out
 	}
 
+	sections := changesOnlySections(rp)
+	sections = append(sections, warningsSection(rp))
+	return appendPlanSummary(ui.RenderNestedSections(sections), rp)
+}
+
+// changesOnlySections builds the changes-only sections of rp, one per
+// resource kind present, without warnings or the summary line.
+func changesOnlySections(rp *ResourcePlan) []ui.NestedSection {
 	var sections []ui.NestedSection
 
 	buildFlatSection := func(title string, resources []Resource) {
@@ -400,10 +434,12 @@ func renderResourcePlanChangesOnly(rp *ResourcePlan) string {
 				continue
 			}
 
-			var changedFiles []Resource
+			var changedFiles, notes []Resource
 			for _, item := range items {
 				if item.Action != ActionNoop {
 					changedFiles = append(changedFiles, item)
+				} else if item.Details != "" {
+					notes = append(notes, item)
 				}
 			}
 
@@ -424,6 +460,10 @@ func renderResourcePlanChangesOnly(rp *ResourcePlan) string {
 				})
 			}
 
+			for _, note := range notes {
+				diffLines = append(diffLines, ui.DiffLine{Type: ui.Info, Message: note.Details})
+			}
+
 			changedFilesetSections = append(changedFilesetSections, ui.NestedSection{Title: filesetName, Items: diffLines})
 		}
 
@@ -436,19 +476,48 @@ func renderResourcePlanChangesOnly(rp *ResourcePlan) string {
 
 	buildFlatSection("Containers", rp.Containers)
 
-	return appendPlanSummary(ui.RenderNestedSections(sections), rp)
+	return sections
 }
 
 // CountActions counts the number of each action type in the plan
 func (rp *ResourcePlan) CountActions() (create, update, delete int) {
+	c := rp.Counts()
+	return c.Create, c.Update, c.Remove
+}
+
+// PlanCounts tallies a plan's resources by what will happen to them.
+type PlanCounts struct {
+	Create    int
+	Update    int
+	Remove    int
+	Unchanged int
+}
+
+// String renders the tally as e.g. "3 to create, 1 to update, 2 to remove, 5
+// unchanged".
+func (c PlanCounts) String() string {
+	return fmt.Sprintf("%d to create, %d to update, %d to remove, %d unchanged", c.Create, c.Update, c.Remove, c.Unchanged)
+}
+
+// Counts tallies the plan's resources by action. Fileset files count one by
+// one, while a fileset without file changes counts as a single unchanged
+// resource, as in the changes-only rendering. A fileset whose changes are
+// unknown counts as neither.
+func (rp *ResourcePlan) Counts() PlanCounts {
+	var c PlanCounts
+	if rp == nil {
+		return c
+	}
 	countResource := func(res Resource) {
 		switch res.Action {
 		case ActionCreate:
-			create++
+			c.Create++
 		case ActionUpdate, ActionReconcile:
-			update++
+			c.Update++
 		case ActionDelete:
-			delete++
+			c.Remove++
+		case ActionNoop:
+			c.Unchanged++
 		}
 	}
 
@@ -464,18 +533,35 @@ func (rp *ResourcePlan) CountActions() (create, update, delete int) {
 		}
 	}
 	for _, items := range rp.Filesets {
+		unchanged := true
 		for _, res := range items {
 			// Only count actual file operations, not status messages
 			if res.Name != "" && res.Action != ActionNoop {
 				countResource(res)
 			}
+			unchanged = unchanged && res.Action == ActionNoop
+		}
+		if unchanged {
+			c.Unchanged++
 		}
 	}
 	for _, res := range rp.Containers {
 		countResource(res)
 	}
 
-	return create, update, delete
+	return c
+}
+
+// HasChanges reports whether any resource has an action other than no-op.
+// Unlike CountActions it also counts fileset status lines such as "changes
+// unknown", since those may hide drift. Warnings never count as changes.
+func (rp *ResourcePlan) HasChanges() bool {
+	for _, res := range rp.AllResources() {
+		if res.Action != ActionNoop {
+			return true
+		}
+	}
+	return false
 }
 
 // AllResources returns all resources from the plan as a flat list (for testing)
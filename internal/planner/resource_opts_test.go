@@ -244,3 +244,93 @@ func TestRenderResourcePlanOpts_FullMatchesLegacy(t *testing.T) {
 		t.Errorf("RenderResourcePlanOpts(Full=true) output differs from RenderResourcePlan\nlegacy:\n%s\nopts:\n%s", legacy, opts)
 	}
 }
+
+func TestPlan_RenderByContext(t *testing.T) {
+	hostA := &ResourcePlan{
+		Volumes: []Resource{NewResource(ResourceVolume, "data-a", ActionCreate, "")},
+		Stacks:  map[string][]Resource{"web": {NewResource(ResourceService, "nginx", ActionUpdate, "config drift")}},
+	}
+	hostB := &ResourcePlan{
+		Networks: []Resource{NewResource(ResourceNetwork, "net-b", ActionDelete, "")},
+		Stacks:   map[string][]Resource{},
+	}
+	agg := &ResourcePlan{Stacks: map[string][]Resource{}, Filesets: map[string][]Resource{}}
+	p := &Planner{}
+	p.aggregateContextPlan(agg, &ContextPlan{ContextName: "a", Resources: hostA})
+	p.aggregateContextPlan(agg, &ContextPlan{ContextName: "b", Resources: hostB})
+	plan := &Plan{
+		Resources: agg,
+		ByContext: map[string]*ContextPlan{
+			"b": {ContextName: "b", Resources: hostB},
+			"a": {ContextName: "a", Resources: hostA},
+		},
+	}
+
+	out := ui.StripANSI(plan.RenderByContext(PlanRenderOptions{}, func(name string) string { return "== " + name }))
+	ia, ib := strings.Index(out, "== a"), strings.Index(out, "== b")
+	if ia < 0 || ib < 0 || ia > ib {
+		t.Fatalf("expected headers for a then b, got:\n%s", out)
+	}
+	if i := strings.Index(out, "data-a"); i < ia || i > ib {
+		t.Errorf("expected data-a under context a, got:\n%s", out)
+	}
+	if i := strings.Index(out, "net-b"); i < ib {
+		t.Errorf("expected net-b under context b, got:\n%s", out)
+	}
+	if !strings.Contains(out, "a/web") {
+		t.Errorf("expected context-prefixed stack key, got:\n%s", out)
+	}
+	if !strings.Contains(out, "All contexts\nPlan: 1 to create, 1 to change, and 1 to destroy") {
+		t.Errorf("expected combined summary, got:\n%s", out)
+	}
+}
+
+func TestResourcePlan_HasChanges(t *testing.T) {
+	rp := &ResourcePlan{
+		Volumes:  []Resource{NewResource(ResourceVolume, "data", ActionNoop, "exists")},
+		Stacks:   map[string][]Resource{"web": {NewResource(ResourceService, "nginx", ActionNoop, "up-to-date")}},
+		Filesets: map[string][]Resource{"site": {NewResource(ResourceFile, "", ActionNoop, "no file changes")}},
+		Warnings: []string{"env var FOO is not set"},
+	}
+	if rp.HasChanges() {
+		t.Fatalf("expected no changes for a no-op plan with warnings")
+	}
+	rp.Filesets["site"] = []Resource{NewResource(ResourceFile, "", ActionUpdate, "changes unknown (skipped remote read)")}
+	if !rp.HasChanges() {
+		t.Fatalf("expected unknown fileset changes to count as changes")
+	}
+}
+
+func TestResourcePlan_Counts(t *testing.T) {
+	rp := &ResourcePlan{
+		Volumes: []Resource{
+			NewResource(ResourceVolume, "data", ActionNoop, "exists"),
+			NewResource(ResourceVolume, "cache", ActionCreate, ""),
+		},
+		Networks: []Resource{NewResource(ResourceNetwork, "old", ActionDelete, "")},
+		Stacks: map[string][]Resource{"web": {
+			NewResource(ResourceService, "nginx", ActionReconcile, "config drift"),
+			NewResource(ResourceService, "app", ActionNoop, "up-to-date"),
+		}},
+		Filesets: map[string][]Resource{
+			"site":    {NewResource(ResourceFile, "", ActionNoop, "no file changes")},
+			"assets":  {NewResource(ResourceFile, "a.css", ActionCreate, ""), NewResource(ResourceFile, "b.css", ActionDelete, "")},
+			"unknown": {NewResource(ResourceFile, "", ActionUpdate, "changes unknown (skipped remote read)")},
+		},
+		Containers: []Resource{NewResource(ResourceContainer, "orphan", ActionDelete, "")},
+	}
+	got := rp.Counts()
+	want := PlanCounts{Create: 2, Update: 1, Remove: 3, Unchanged: 3}
+	if got != want {
+		t.Fatalf("Counts() = %+v, want %+v", got, want)
+	}
+	if s := got.String(); s != "2 to create, 1 to update, 3 to remove, 3 unchanged" {
+		t.Fatalf("String() = %q", s)
+	}
+	if c, u, d := rp.CountActions(); c != 2 || u != 1 || d != 3 {
+		t.Fatalf("CountActions() = %d, %d, %d; want it to agree with Counts", c, u, d)
+	}
+	if (*ResourcePlan)(nil).Counts() != (PlanCounts{}) {
+		t.Fatalf("expected a nil plan to count nothing")
+	}
+}
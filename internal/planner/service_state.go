@@ -4,10 +4,12 @@ import (
 	"context"
 	"path/filepath"
 	"sort"
+	"strings"
 	"sync"
 
 	"github.com/gcstr/dockform/internal/apperr"
 	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/logger"
 	"github.com/gcstr/dockform/internal/manifest"
 	"github.com/gcstr/dockform/internal/secrets"
 )
@@ -24,6 +26,8 @@ const (
 	ServiceDrifted
 	// ServiceIdentifierMismatch indicates the service is running but has wrong identifier label
 	ServiceIdentifierMismatch
+	// ServiceStopped indicates the service's container exists and is up-to-date but is not running
+	ServiceStopped
 )
 
 // ServiceInfo contains information about a service's desired and actual state.
@@ -33,7 +37,10 @@ type ServiceInfo struct {
 	State       ServiceState
 	DesiredHash string
 	RunningHash string
-	Container   *dockercli.ComposePsItem // nil if not running
+	Container   *dockercli.ComposePsItem // nil if no container exists
+	// ImageUpdated is set when the service drifted only because its image tag
+	// now resolves to a different image than its container runs.
+	ImageUpdated bool
 }
 
 // ServiceStateDetector handles detection of service state changes.
@@ -53,15 +60,18 @@ func (d *ServiceStateDetector) WithParallel(enabled bool) *ServiceStateDetector
 	return d
 }
 
-// GetPlannedServices returns the list of services defined in the stack's compose files.
+// GetPlannedServices returns the list of services defined in the stack's compose
+// files, leaving out the stack's ignore_services.
 func (d *ServiceStateDetector) GetPlannedServices(ctx context.Context, stack manifest.Stack, inline []string) ([]string, error) {
 	if d.docker == nil {
 		return nil, nil
 	}
+	ctx = dockercli.WithStack(ctx, stack)
 
 	// Prefer cheap service listing first
 	services, err := d.docker.ComposeConfigServices(ctx, stack.Root, stack.Files, stack.Profiles, stack.EnvFile, inline)
 	if err == nil && len(services) > 0 {
+		services = withoutIgnoredServices(stack, services)
 		sort.Strings(services)
 		return services, nil
 	}
@@ -75,10 +85,27 @@ func (d *ServiceStateDetector) GetPlannedServices(ctx context.Context, stack man
 	for name := range doc.Services {
 		out = append(out, name)
 	}
+	out = withoutIgnoredServices(stack, out)
 	sort.Strings(out)
 	return out, nil
 }
 
+// withoutIgnoredServices drops the stack's ignore_services from services. The
+// manifest already moves them behind an inactive profile; filtering here keeps
+// them out of drift detection even when one of their own profiles is active.
+func withoutIgnoredServices(stack manifest.Stack, services []string) []string {
+	if len(stack.IgnoreServices) == 0 {
+		return services
+	}
+	out := services[:0:0]
+	for _, name := range services {
+		if !stack.IsIgnoredService(name) {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
 // BuildInlineEnv constructs the inline environment variables for a stack, including SOPS secrets.
 func (d *ServiceStateDetector) BuildInlineEnv(ctx context.Context, stack manifest.Stack, sopsConfig *manifest.SopsConfig) ([]string, error) {
 	inline := append([]string(nil), stack.EnvInline...)
@@ -88,6 +115,7 @@ func (d *ServiceStateDetector) BuildInlineEnv(ctx context.Context, stack manifes
 	pgpAgent := false
 	pgpMode := ""
 	pgpPass := ""
+	pgpHome := ""
 	if sopsConfig != nil && sopsConfig.Age != nil {
 		ageKeyFile = sopsConfig.Age.KeyFile
 	}
@@ -96,6 +124,7 @@ func (d *ServiceStateDetector) BuildInlineEnv(ctx context.Context, stack manifes
 		pgpAgent = sopsConfig.Pgp.UseAgent
 		pgpMode = sopsConfig.Pgp.PinentryMode
 		pgpPass = sopsConfig.Pgp.Passphrase
+		pgpHome = sopsConfig.Pgp.GnupgHome
 	}
 
 	for _, pth0 := range stack.SopsSecrets {
@@ -103,12 +132,13 @@ func (d *ServiceStateDetector) BuildInlineEnv(ctx context.Context, stack manifes
 		if pth != "" && !filepath.IsAbs(pth) {
 			pth = filepath.Join(stack.Root, pth)
 		}
-		pairs, err := secrets.DecryptAndParse(ctx, pth, secrets.SopsOptions{
+		pairs, err := secrets.DecryptCacheFrom(ctx).DecryptAndParse(ctx, pth, secrets.SopsOptions{
 			AgeKeyFile:      ageKeyFile,
 			PgpKeyringDir:   pgpDir,
 			PgpUseAgent:     pgpAgent,
 			PgpPinentryMode: pgpMode,
 			PgpPassphrase:   pgpPass,
+			GnupgHome:       pgpHome,
 		})
 		if err != nil {
 			return nil, apperr.Wrap("servicestate.BuildInlineEnv", apperr.External, err, "decrypt sops secret %s", pth)
@@ -116,11 +146,23 @@ func (d *ServiceStateDetector) BuildInlineEnv(ctx context.Context, stack manifes
 		inline = append(inline, pairs...)
 	}
 
+	if stack.Environment != nil && stack.Environment.Resolve {
+		return resolveStackEnv(stack, inline)
+	}
 	return inline, nil
 }
 
-// GetRunningServices returns a map of currently running services for the stack.
+// GetRunningServices returns a map of the services of the stack that have a
+// container, including stopped ones. Compose ps errors are treated as "no
+// running services" rather than a hard error.
 func (d *ServiceStateDetector) GetRunningServices(ctx context.Context, stack manifest.Stack, inline []string) (map[string]dockercli.ComposePsItem, error) {
+	running, _ := d.listServiceContainers(ctx, stack, inline)
+	return running, nil
+}
+
+// listServiceContainers maps each service of the stack to its container. On
+// error it returns an empty map alongside the error.
+func (d *ServiceStateDetector) listServiceContainers(ctx context.Context, stack manifest.Stack, inline []string) (map[string]dockercli.ComposePsItem, error) {
 	running := map[string]dockercli.ComposePsItem{}
 
 	if d.docker == nil {
@@ -132,10 +174,9 @@ func (d *ServiceStateDetector) GetRunningServices(ctx context.Context, stack man
 		proj = stack.Project.Name
 	}
 
-	items, err := d.docker.ComposePs(ctx, stack.Root, stack.Files, stack.Profiles, stack.EnvFile, proj, inline)
+	items, err := d.docker.ComposePs(dockercli.WithStack(ctx, stack), stack.Root, stack.Files, stack.Profiles, stack.EnvFile, proj, inline)
 	if err != nil {
-		// Treat compose ps errors as "no running services" rather than hard error
-		return running, nil
+		return running, err
 	}
 
 	for _, item := range items {
@@ -145,8 +186,26 @@ func (d *ServiceStateDetector) GetRunningServices(ctx context.Context, stack man
 	return running, nil
 }
 
+// composeConfigError describes a compose config failure for a stack. Schema
+// errors usually mean the file uses a key newer than the installed compose
+// plugin, so the message names the plugin version and suggests an upgrade
+// instead of passing on compose's raw validation error alone.
+func (d *ServiceStateDetector) composeConfigError(ctx context.Context, stackName string, err error) error {
+	if !dockercli.IsComposeSchemaError(err) {
+		return apperr.Wrap("servicestate.DetectAllServicesState", apperr.External, err, "failed to get planned services for stack %s", stackName)
+	}
+	version := "unknown version"
+	if v, verr := d.docker.ComposeVersion(ctx); verr == nil && strings.TrimSpace(v) != "" {
+		version = strings.TrimSpace(v)
+	}
+	return apperr.New("servicestate.DetectAllServicesState", apperr.Precondition,
+		"stack %s uses a compose file feature the installed compose plugin (%s) does not support: %s; upgrade docker compose (`dockform doctor` shows the plugin version)",
+		stackName, version, strings.TrimSpace(apperr.DeepestMessage(err)))
+}
+
 // DetectServiceState determines the state of a single service.
 func (d *ServiceStateDetector) DetectServiceState(ctx context.Context, serviceName, stackName string, stack manifest.Stack, identifier string, inline []string, running map[string]dockercli.ComposePsItem) (ServiceInfo, error) {
+	ctx = dockercli.WithStack(ctx, stack)
 	return d.detectServiceStateFast(ctx, serviceName, stackName, stack, identifier, inline, running, nil, nil)
 }
 
@@ -228,31 +287,55 @@ func (d *ServiceStateDetector) detectServiceStateFast(ctx context.Context, servi
 		}
 	}
 
+	// An up-to-date container that exited or was stopped only needs starting.
+	if !containerRunning(container) {
+		info.State = ServiceStopped
+	}
+
 	return info, nil
 }
 
+// containerRunning reports whether a compose container is running. An empty
+// state is treated as running, as older compose versions may omit it.
+func containerRunning(item dockercli.ComposePsItem) bool {
+	switch strings.ToLower(strings.TrimSpace(item.State)) {
+	case "", "running", "restarting":
+		return true
+	}
+	return false
+}
+
 // DetectAllServicesState analyzes the state of all services in a stack.
 func (d *ServiceStateDetector) DetectAllServicesState(ctx context.Context, stackName string, stack manifest.Stack, identifier string, sopsConfig *manifest.SopsConfig) ([]ServiceInfo, error) {
+	// Stack labels are part of the desired config hash.
+	ctx = dockercli.WithStack(ctx, stack)
 	// Build inline environment
 	inline, err := d.BuildInlineEnv(ctx, stack, sopsConfig)
 	if err != nil {
 		return nil, err
 	}
 
+	log := logger.FromContext(ctx).With("component", "servicestate", "context", stack.Context)
+
 	// Get planned services
+	st := logger.StartStep(log, "compose_config", stackName, "resource_kind", "stack")
 	plannedServices, err := d.GetPlannedServices(ctx, stack, inline)
 	if err != nil {
-		return nil, apperr.Wrap("servicestate.DetectAllServicesState", apperr.External, err, "failed to get planned services for stack %s", stackName)
+		return nil, st.Fail(d.composeConfigError(ctx, stackName, err))
 	}
+	st.OK(false, "services", len(plannedServices))
 
 	if len(plannedServices) == 0 {
 		return nil, nil
 	}
 
-	// Get running services
-	running, err := d.GetRunningServices(ctx, stack, inline)
+	// Get running services; a failed compose ps leaves every service missing.
+	st = logger.StartStep(log, "compose_ps", stackName, "resource_kind", "stack")
+	running, err := d.listServiceContainers(ctx, stack, inline)
 	if err != nil {
-		return nil, apperr.Wrap("servicestate.DetectAllServicesState", apperr.External, err, "failed to get running services for stack %s", stackName)
+		_ = st.Fail(err)
+	} else {
+		st.OK(false, "containers", len(running))
 	}
 
 	// Precompute desired hashes for all planned services (reuse overlay once)
@@ -262,8 +345,12 @@ func (d *ServiceStateDetector) DetectAllServicesState(ctx context.Context, stack
 		if stack.Project != nil {
 			proj = stack.Project.Name
 		}
+		st = logger.StartStep(log, "compose_config_hash", stackName, "resource_kind", "stack", "services", len(plannedServices))
 		if hashes, err := d.docker.ComposeConfigHashes(ctx, stack.Root, stack.Files, stack.Profiles, stack.EnvFile, proj, plannedServices, identifier, inline); err == nil {
 			desiredHashes = hashes
+			st.OK(false)
+		} else {
+			_ = st.Fail(err)
 		}
 	}
 
@@ -2,8 +2,13 @@ package planner
 
 import (
 	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/gcstr/dockform/internal/apperr"
 	"github.com/gcstr/dockform/internal/dockercli"
 	"github.com/gcstr/dockform/internal/manifest"
 )
@@ -32,6 +37,34 @@ func TestServiceStateDetector_BuildInlineEnv(t *testing.T) {
 	}
 }
 
+func TestServiceStateDetector_BuildInlineEnv_Resolved(t *testing.T) {
+	// The caller's shell disagrees with the env file; compose would normally let
+	// the process env win, so the resolved env must pin the env file's value.
+	t.Setenv("APP_MODE", "from-shell")
+	t.Setenv("REGION", "eu")
+
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "app.env"), []byte("APP_MODE=from-file\nDB_HOST=db\nDB_URL=postgres://${DB_HOST}/${REGION}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	app := manifest.Stack{
+		Root:        root,
+		EnvFile:     []string{"app.env"},
+		EnvInline:   []string{"DB_HOST=primary"},
+		Environment: &manifest.Environment{Resolve: true},
+	}
+
+	result, err := NewServiceStateDetector(nil).BuildInlineEnv(context.Background(), app, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := strings.Join(result, "\n")
+	want := "APP_MODE=from-file\nDB_HOST=primary\nDB_URL=postgres://db/eu"
+	if got != want {
+		t.Fatalf("resolved env mismatch\n got: %q\nwant: %q", got, want)
+	}
+}
+
 func TestServiceStateDetector_DetectServiceState_Missing(t *testing.T) {
 	detector := NewServiceStateDetector(nil)
 
@@ -159,3 +192,25 @@ func TestGetServiceNames(t *testing.T) {
 		}
 	}
 }
+
+func TestDetectAllServicesState_ComposeSchemaError(t *testing.T) {
+	docker := newMockDocker()
+	docker.composeVersion = "2.17.3"
+	docker.composeConfigError = apperr.Wrap("dockercli.Exec", apperr.External, errors.New("exit status 15"),
+		"validating compose.yaml: services.web Additional property develop is not allowed")
+
+	stack := manifest.Stack{Root: "/tmp/website", Files: []string{"compose.yaml"}}
+	_, err := NewServiceStateDetector(docker).DetectAllServicesState(context.Background(), "website", stack, "", nil)
+	if err == nil {
+		t.Fatalf("expected the schema error to be surfaced")
+	}
+	if !apperr.IsKind(err, apperr.Precondition) {
+		t.Errorf("expected a precondition error, got %v", err)
+	}
+	msg := apperr.DeepestMessage(err)
+	for _, want := range []string{"stack website", "(2.17.3)", "Additional property develop", "upgrade docker compose"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in %q", want, msg)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package planner
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+func stoppedServiceMock() (*mockDockerClient, manifest.Config) {
+	d := newMockDocker()
+	d.composePsItems = []dockercli.ComposePsItem{
+		{Name: "website-nginx-1", Service: "nginx", State: "exited", ExitCode: 137},
+	}
+	d.containerLabels = map[string]map[string]string{
+		"website-nginx-1": {
+			"com.docker.compose.config-hash": "mock-hash",
+			"io.dockform.identifier":         "test-id",
+		},
+	}
+	cfg := manifest.Config{
+		Identifier: "test-id",
+		Contexts:   map[string]manifest.ContextConfig{"default": {}},
+		Stacks: map[string]manifest.Stack{
+			"default/website": {Root: "/tmp/website", Files: []string{"compose.yaml"}},
+		},
+	}
+	return d, cfg
+}
+
+func TestBuildPlan_StoppedServiceWillStart(t *testing.T) {
+	d, cfg := stoppedServiceMock()
+	p := NewWithDocker(d)
+
+	plan, err := p.BuildPlan(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("BuildPlan: %v", err)
+	}
+	if out := plan.String(); !strings.Contains(out, "nginx will be updated (stopped, will start)") {
+		t.Fatalf("expected the exited service to be planned to start, got:\n%s", out)
+	}
+
+	if _, err := p.Apply(context.Background(), cfg); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if d.composeUps != 1 {
+		t.Errorf("expected compose up to start the stopped service, got %d ups", d.composeUps)
+	}
+	if len(d.removedContainers) != 0 {
+		t.Errorf("expected the stopped container to be kept, got removed %v", d.removedContainers)
+	}
+}
+
+func TestApply_RecreateStopped(t *testing.T) {
+	d, cfg := stoppedServiceMock()
+	p := NewWithDocker(d).WithRecreateStopped(true)
+
+	plan, err := p.BuildPlan(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("BuildPlan: %v", err)
+	}
+	if out := plan.String(); !strings.Contains(out, "nginx will be updated (stopped, will be recreated)") {
+		t.Fatalf("expected the exited service to be planned for recreation, got:\n%s", out)
+	}
+
+	if _, err := p.Apply(context.Background(), cfg); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if strings.Join(d.removedContainers, ",") != "website-nginx-1" || d.composeUps != 1 {
+		t.Errorf("expected the stopped container removed before compose up, got removed=%v ups=%d", d.removedContainers, d.composeUps)
+	}
+}
+
+func TestBuildPlan_RunningServiceUpToDate(t *testing.T) {
+	d, cfg := stoppedServiceMock()
+	d.composePsItems[0].State = "running"
+
+	plan, err := NewWithDocker(d).BuildPlan(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("BuildPlan: %v", err)
+	}
+	if c, u, del := plan.Resources.CountActions(); c+u+del != 0 {
+		t.Fatalf("expected no changes for a running up-to-date service, got:\n%s", plan.String())
+	}
+}
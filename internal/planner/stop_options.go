@@ -0,0 +1,69 @@
+package planner
+
+import (
+	"context"
+	"time"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/logger"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+// coldStopOptions returns, per container name, the stop signal and grace
+// period its compose service declares (stop_signal, stop_grace_period), so
+// cold-mode stops shut services down the way compose would. Containers whose
+// stack or service cannot be resolved are left out and get docker's defaults.
+func (fm *FilesetManager) coldStopOptions(ctx context.Context, cfg manifest.Config, contextName string, execCtx *ContextExecutionContext, containers []dockercli.PsBrief) map[string]dockercli.StopOptions {
+	log := logger.FromContext(ctx).With("component", "fileset", "context", contextName)
+	out := map[string]dockercli.StopOptions{}
+	if len(containers) == 0 {
+		return out
+	}
+
+	stacks := cfg.GetStacksForContext(contextName)
+	for _, stackName := range sortedKeys(stacks) {
+		stack := stacks[stackName]
+		var inline []string
+		if execCtx != nil && execCtx.Stacks[stackName] != nil {
+			inline = execCtx.Stacks[stackName].InlineEnv
+		} else {
+			env, err := NewServiceStateDetector(fm.docker).BuildInlineEnv(ctx, stack, cfg.Sops)
+			if err != nil {
+				log.Debug("stop_options_skipped", "stack", stackName, "error", err.Error())
+				continue
+			}
+			inline = env
+		}
+		doc, err := fm.docker.ComposeConfigFull(dockercli.WithStack(ctx, stack), stack.Root, stack.Files, stack.Profiles, stack.EnvFile, inline)
+		if err != nil {
+			log.Debug("stop_options_skipped", "stack", stackName, "error", err.Error())
+			continue
+		}
+		project := doc.Name
+		if stack.Project != nil && stack.Project.Name != "" {
+			project = stack.Project.Name
+		}
+
+		for _, c := range containers {
+			if c.Project != project {
+				continue
+			}
+			svc, ok := doc.Services[c.Service]
+			if !ok {
+				continue
+			}
+			opts := dockercli.StopOptions{Signal: svc.StopSignal}
+			if svc.StopGrace != "" {
+				if d, err := time.ParseDuration(svc.StopGrace); err == nil {
+					opts.Timeout = d
+				} else {
+					log.Debug("stop_grace_period_invalid", "service", c.Service, "value", svc.StopGrace)
+				}
+			}
+			if opts != (dockercli.StopOptions{}) {
+				out[c.Name] = opts
+			}
+		}
+	}
+	return out
+}
@@ -8,6 +8,16 @@ import (
 	"github.com/gcstr/dockform/internal/manifest"
 )
 
+// withMountingServices returns fs with restart_services set to the services
+// attached to its target volume when it declares none and enabled is set, so
+// a content change reaches containers that only mount the volume.
+func withMountingServices(fs manifest.FilesetSpec, enabled bool) manifest.FilesetSpec {
+	if enabled && !fs.RestartServices.Attached && len(fs.RestartServices.Services) == 0 {
+		fs.RestartServices = manifest.RestartTargets{Attached: true}
+	}
+	return fs
+}
+
 // resolveTargetServices determines the list of service names to act on for a fileset,
 // based on the restart_services setting (attached sentinel or explicit list).
 func resolveTargetServices(ctx context.Context, docker DockerClient, fs manifest.FilesetSpec) ([]string, error) {
@@ -107,3 +107,17 @@ func TestResolveTargetServices_AttachedResolvesAndSortsServices(t *testing.T) {
 		t.Fatalf("unexpected services: got=%#v want=%#v", got, want)
 	}
 }
+
+func TestWithMountingServices_OnlyFillsEmptyRestartServices(t *testing.T) {
+	empty := manifest.FilesetSpec{TargetVolume: "data"}
+	if got := withMountingServices(empty, false); got.RestartServices.Attached {
+		t.Fatal("expected restart_services untouched when disabled")
+	}
+	if got := withMountingServices(empty, true); !got.RestartServices.Attached {
+		t.Fatal("expected attached discovery for empty restart_services")
+	}
+	explicit := manifest.FilesetSpec{RestartServices: manifest.RestartTargets{Services: []string{"web"}}}
+	if got := withMountingServices(explicit, true); got.RestartServices.Attached {
+		t.Fatal("expected explicit restart_services to win")
+	}
+}
@@ -2,8 +2,11 @@ package planner
 
 import (
 	"sort"
+	"strings"
 
 	"github.com/gcstr/dockform/internal/filesets"
+	"github.com/gcstr/dockform/internal/manifest"
+	"github.com/gcstr/dockform/internal/ui"
 )
 
 // Plan represents a structured plan with resources organized by context and type.
@@ -85,6 +88,42 @@ func (pln *Plan) Render(opts PlanRenderOptions) string {
 	return RenderResourcePlanOpts(pln.Resources, opts)
 }
 
+// RenderByContext renders the plan segmented by context: for each context, the
+// text returned by header followed by that context's own resources. A combined
+// summary closes the output when the plan spans several contexts.
+func (pln *Plan) RenderByContext(opts PlanRenderOptions, header func(contextName string) string) string {
+	if pln.Resources == nil || len(pln.ByContext) == 0 {
+		return pln.Render(opts)
+	}
+	names := pln.GetContextNames()
+	parts := make([]string, 0, len(names)+1)
+	for _, name := range names {
+		cp := pln.ByContext[name]
+		body := "No resources."
+		if cp != nil && cp.Resources != nil {
+			body = RenderResourcePlanOpts(withContextStackKeys(name, cp.Resources), opts)
+		}
+		parts = append(parts, header(name)+"\n"+body)
+	}
+	if len(names) > 1 {
+		if create, update, del := pln.Resources.CountActions(); create+update+del > 0 {
+			parts = append(parts, "│ All contexts\n"+ui.FormatPlanSummary(create, update, del))
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// withContextStackKeys returns a shallow copy of rp whose stack keys carry the
+// context prefix, matching the keys used in the aggregated plan.
+func withContextStackKeys(contextName string, rp *ResourcePlan) *ResourcePlan {
+	out := *rp
+	out.Stacks = make(map[string][]Resource, len(rp.Stacks))
+	for name, resources := range rp.Stacks {
+		out.Stacks[manifest.MakeStackKey(contextName, name)] = resources
+	}
+	return &out
+}
+
 // GetContextExecutionContext returns the execution context for a specific context.
 func (pln *Plan) GetContextExecutionContext(contextName string) *ContextExecutionContext {
 	if pln.ExecutionContext == nil || pln.ExecutionContext.ByContext == nil {
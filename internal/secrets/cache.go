@@ -0,0 +1,92 @@
+package secrets
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DecryptCache memoizes DecryptAndParse for the length of one command, so a
+// secrets file read by several phases (plan, apply, prune) is decrypted once.
+// Entries are keyed by absolute path and key material and are dropped when the
+// file's modification time or size changes. It is safe for concurrent use; a
+// nil *DecryptCache decrypts on every call.
+type DecryptCache struct {
+	mu      sync.Mutex
+	entries map[decryptKey]decryptEntry
+}
+
+type decryptKey struct {
+	path string // absolute path of the secrets file
+	keys string // digest of the SopsOptions used to decrypt it
+}
+
+type decryptEntry struct {
+	modTime time.Time
+	size    int64
+	pairs   []string
+}
+
+// NewDecryptCache returns an empty cache.
+func NewDecryptCache() *DecryptCache {
+	return &DecryptCache{entries: map[decryptKey]decryptEntry{}}
+}
+
+// DecryptAndParse is DecryptAndParse served from the cache while the file is
+// unchanged since it was last decrypted with the same options.
+func (c *DecryptCache) DecryptAndParse(ctx context.Context, path string, opts SopsOptions) ([]string, error) {
+	if c == nil {
+		return DecryptAndParse(ctx, path, opts)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return DecryptAndParse(ctx, path, opts)
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		// Let DecryptAndParse report the missing or unreadable file.
+		return DecryptAndParse(ctx, path, opts)
+	}
+	key := decryptKey{path: abs, keys: optionsDigest(opts)}
+
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && e.modTime.Equal(info.ModTime()) && e.size == info.Size() {
+		return append([]string(nil), e.pairs...), nil
+	}
+
+	pairs, err := DecryptAndParse(ctx, path, opts)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.entries[key] = decryptEntry{modTime: info.ModTime(), size: info.Size(), pairs: pairs}
+	c.mu.Unlock()
+	return append([]string(nil), pairs...), nil
+}
+
+// optionsDigest identifies the key material in opts without keeping the
+// passphrase around in plain text.
+func optionsDigest(opts SopsOptions) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%#v", opts)))
+	return hex.EncodeToString(sum[:])
+}
+
+type decryptCacheKey struct{}
+
+// WithDecryptCache returns a context carrying c, which DecryptCacheFrom returns.
+func WithDecryptCache(ctx context.Context, c *DecryptCache) context.Context {
+	return context.WithValue(ctx, decryptCacheKey{}, c)
+}
+
+// DecryptCacheFrom returns the cache carried by ctx, or nil when there is none.
+func DecryptCacheFrom(ctx context.Context) *DecryptCache {
+	c, _ := ctx.Value(decryptCacheKey{}).(*DecryptCache)
+	return c
+}
@@ -0,0 +1,96 @@
+package secrets
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSops puts a sops on PATH that prints the file (its last argument) as-is
+// and logs each call, returning a func listing the arguments of every run.
+func fakeSops(t *testing.T) func() []string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake sops script requires a POSIX shell")
+	}
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	script := "#!/bin/sh\necho \"$*\" >> " + calls + "\nfor f; do :; done\ncat \"$f\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "sops"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return func() []string {
+		b, _ := os.ReadFile(calls)
+		if len(b) == 0 {
+			return nil
+		}
+		return strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	}
+}
+
+func TestDecryptCache_DecryptsOncePerFileAndKeys(t *testing.T) {
+	calls := fakeSops(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "secrets.env")
+	if err := os.WriteFile(path, []byte("TOKEN=one\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	opts := SopsOptions{AgeKeyFile: filepath.Join(dir, "age.key")}
+	c := NewDecryptCache()
+
+	for i := 0; i < 3; i++ {
+		pairs, err := c.DecryptAndParse(ctx, path, opts)
+		if err != nil {
+			t.Fatalf("decrypt: %v", err)
+		}
+		if len(pairs) != 1 || pairs[0] != "TOKEN=one" {
+			t.Fatalf("unexpected pairs: %v", pairs)
+		}
+	}
+	if n := len(calls()); n != 1 {
+		t.Fatalf("expected one sops run, got %d", n)
+	}
+
+	// Other key material decrypts again.
+	if _, err := c.DecryptAndParse(ctx, path, SopsOptions{AgeKeyFile: filepath.Join(dir, "other.key")}); err != nil {
+		t.Fatalf("decrypt: %v", err)
+	}
+	if n := len(calls()); n != 2 {
+		t.Fatalf("expected a second sops run for other keys, got %d", n)
+	}
+
+	// A changed file is decrypted again.
+	if err := os.WriteFile(path, []byte("TOKEN=two\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	pairs, err := c.DecryptAndParse(ctx, path, opts)
+	if err != nil {
+		t.Fatalf("decrypt: %v", err)
+	}
+	if len(pairs) != 1 || pairs[0] != "TOKEN=two" {
+		t.Fatalf("expected the changed file to be decrypted again, got %v", pairs)
+	}
+	if n := len(calls()); n != 3 {
+		t.Fatalf("expected a third sops run after the change, got %d", n)
+	}
+}
+
+func TestDecryptCacheFrom(t *testing.T) {
+	if DecryptCacheFrom(context.Background()) != nil {
+		t.Fatal("expected no cache on a bare context")
+	}
+	c := NewDecryptCache()
+	if got := DecryptCacheFrom(WithDecryptCache(context.Background(), c)); got != c {
+		t.Fatal("expected the cache set on the context")
+	}
+}
@@ -0,0 +1,55 @@
+package secrets
+
+import (
+	"sort"
+	"strings"
+)
+
+// KeyDiff lists the keys that differ between two sets of dotenv pairs.
+// Values are never carried, so a KeyDiff is safe to print.
+type KeyDiff struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// Empty reports whether both sides hold the same keys with the same values.
+func (d KeyDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffPairs compares two lists of key=value pairs as returned by DecryptAndParse
+// and reports added, removed and changed keys, each sorted. When a key repeats,
+// the last value wins, matching how dotenv files are loaded.
+func DiffPairs(before, after []string) KeyDiff {
+	old := pairsToMap(before)
+	cur := pairsToMap(after)
+	var d KeyDiff
+	for k, v := range cur {
+		prev, ok := old[k]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, k)
+		case prev != v:
+			d.Changed = append(d.Changed, k)
+		}
+	}
+	for k := range old {
+		if _, ok := cur[k]; !ok {
+			d.Removed = append(d.Removed, k)
+		}
+	}
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	sort.Strings(d.Changed)
+	return d
+}
+
+func pairsToMap(pairs []string) map[string]string {
+	m := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		k, v, _ := strings.Cut(p, "=")
+		m[k] = v
+	}
+	return m
+}
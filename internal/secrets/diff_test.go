@@ -0,0 +1,30 @@
+package secrets
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffPairs_ReportsAddedRemovedChanged(t *testing.T) {
+	before := []string{"KEEP=1", "GONE=x", "ROTATE=old", "DUP=a", "DUP=b"}
+	after := []string{"ROTATE=new", "KEEP=1", "NEW_B=y", "NEW_A=z", "DUP=b"}
+	d := DiffPairs(before, after)
+	if !reflect.DeepEqual(d.Added, []string{"NEW_A", "NEW_B"}) {
+		t.Fatalf("added: %#v", d.Added)
+	}
+	if !reflect.DeepEqual(d.Removed, []string{"GONE"}) {
+		t.Fatalf("removed: %#v", d.Removed)
+	}
+	if !reflect.DeepEqual(d.Changed, []string{"ROTATE"}) {
+		t.Fatalf("changed: %#v", d.Changed)
+	}
+	if d.Empty() {
+		t.Fatalf("expected non-empty diff")
+	}
+}
+
+func TestDiffPairs_IdenticalIsEmpty(t *testing.T) {
+	if d := DiffPairs([]string{"A=1", "B=2"}, []string{"B=2", "A=1"}); !d.Empty() {
+		t.Fatalf("expected empty diff, got %#v", d)
+	}
+}
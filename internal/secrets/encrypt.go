@@ -2,6 +2,7 @@ package secrets
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -12,6 +13,7 @@ import (
 
 	age "filippo.io/age"
 	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/goccy/go-yaml"
 )
 
 // AgeRecipientsFromKeyFile reads an age identity file and returns the corresponding recipient(s).
@@ -66,6 +68,53 @@ func AgeRecipientsFromKeyFile(ageKeyFile string) ([]string, error) {
 // Age recipients are passed with --age, PGP recipients are passed with --pgp.
 // This function is safe for concurrent use across multiple goroutines.
 func EncryptDotenvFileWithSops(ctx context.Context, path string, ageRecipients []string, ageKeyFile string, pgpRecipients []string, pgpKeyringDir string, pgpUseAgent bool, pgpPinentryMode string, pgpPassphrase string) error {
+	return EncryptDotenvFile(ctx, path, SopsOptions{
+		AgeKeyFile:      ageKeyFile,
+		AgeRecipients:   ageRecipients,
+		PgpKeyringDir:   pgpKeyringDir,
+		PgpUseAgent:     pgpUseAgent,
+		PgpPinentryMode: pgpPinentryMode,
+		PgpPassphrase:   pgpPassphrase,
+		PgpRecipients:   pgpRecipients,
+	})
+}
+
+// EncryptDotenvFile is EncryptDotenvFileWithSops with its recipients and key
+// settings, including a GnupgHome override, taken from opts.
+func EncryptDotenvFile(ctx context.Context, path string, opts SopsOptions) error {
+	return encryptFile(ctx, path, FormatDotenv, opts)
+}
+
+// EncryptFile encrypts a plaintext secrets file in place like EncryptDotenvFile,
+// reading and writing it in the format its extension names (see FormatForPath).
+func EncryptFile(ctx context.Context, path string, opts SopsOptions) error {
+	return encryptFile(ctx, path, FormatForPath(path), opts)
+}
+
+// ReencryptFile decrypts a SOPS-encrypted secrets file and encrypts it again in
+// place for the recipients in opts, keeping its format. If encryption fails the
+// encrypted original is put back rather than leaving plaintext behind.
+func ReencryptFile(ctx context.Context, path string, opts SopsOptions) error {
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return apperr.Wrap("secrets.ReencryptFile", apperr.NotFound, err, "read %s", path)
+	}
+	plain, err := DecryptFile(ctx, path, opts)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, plain, 0o600); err != nil {
+		return apperr.Wrap("secrets.ReencryptFile", apperr.Internal, err, "write %s", path)
+	}
+	if err := EncryptFile(ctx, path, opts); err != nil {
+		_ = os.WriteFile(path, original, 0o600)
+		return err
+	}
+	return nil
+}
+
+func encryptFile(ctx context.Context, path, format string, opts SopsOptions) error {
+	ageRecipients, pgpRecipients := opts.AgeRecipients, opts.PgpRecipients
 	totalRecips := len(ageRecipients) + len(pgpRecipients)
 	if totalRecips == 0 {
 		return apperr.New("secrets.EncryptDotenvFileWithSops", apperr.InvalidInput, "no recipients provided")
@@ -80,12 +129,7 @@ func EncryptDotenvFileWithSops(ctx context.Context, path string, ageRecipients [
 	}
 
 	// Build environment for subprocess without mutating global state
-	env := buildSopsEnv(SopsOptions{
-		AgeKeyFile:      ageKeyFile,
-		PgpKeyringDir:   pgpKeyringDir,
-		PgpUseAgent:     pgpUseAgent,
-		PgpPinentryMode: pgpPinentryMode,
-	})
+	env := buildSopsEnv(opts)
 
 	// Build args with a single --age flag carrying a comma-separated list
 	validAgeRecipients := make([]string, 0, len(ageRecipients))
@@ -101,7 +145,7 @@ func EncryptDotenvFileWithSops(ctx context.Context, path string, ageRecipients [
 		validAgeRecipients = append(validAgeRecipients, r)
 	}
 
-	args := []string{"--encrypt", "--input-type", "dotenv", "--output-type", "dotenv", "--in-place"}
+	args := []string{"--encrypt", "--input-type", format, "--output-type", format, "--in-place"}
 	if len(validAgeRecipients) > 0 {
 		args = append(args, "--age", strings.Join(validAgeRecipients, ","))
 	}
@@ -117,3 +161,36 @@ func EncryptDotenvFileWithSops(ctx context.Context, path string, ageRecipients [
 	}
 	return nil
 }
+
+// IsSopsEncrypted reports whether a secrets file already carries the metadata
+// SOPS adds when it encrypts one: sops_mac, sops_version, ... lines in dotenv
+// files and a top-level sops key in YAML and JSON files.
+func IsSopsEncrypted(path string) (bool, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false, apperr.Wrap("secrets.IsSopsEncrypted", apperr.NotFound, err, "read %s", path)
+	}
+	switch FormatForPath(path) {
+	case FormatYAML:
+		var doc map[string]any
+		if yaml.Unmarshal(b, &doc) != nil {
+			return false, nil
+		}
+		_, ok := doc["sops"]
+		return ok, nil
+	case FormatJSON:
+		var doc map[string]json.RawMessage
+		if json.Unmarshal(b, &doc) != nil {
+			return false, nil
+		}
+		_, ok := doc["sops"]
+		return ok, nil
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "sops_mac=") || strings.HasPrefix(line, "sops_version=") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
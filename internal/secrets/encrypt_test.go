@@ -91,6 +91,28 @@ func TestEncryptDotenvFileWithSops_Success_AndDecryptable(t *testing.T) {
 	}
 }
 
+func TestReencryptFile_KeepsYAMLFormat(t *testing.T) {
+	calls := fakeSops(t)
+	dir := t.TempDir()
+	keyPath, recip := writeTempAgeKey(t, dir, true)
+	path := filepath.Join(dir, "secrets.yaml")
+	doc := "db:\n  user: app\n  password: s3cret\n"
+	if err := os.WriteFile(path, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	opts := SopsOptions{AgeKeyFile: keyPath, AgeRecipients: []string{recip}}
+	if err := ReencryptFile(context.Background(), path, opts); err != nil {
+		t.Fatalf("reencrypt: %v", err)
+	}
+	runs := calls()
+	if len(runs) != 2 || !strings.HasPrefix(runs[0], "--decrypt --input-type yaml --output-type yaml") || !strings.HasPrefix(runs[1], "--encrypt --input-type yaml --output-type yaml") {
+		t.Fatalf("expected a YAML decrypt and encrypt, got %q", runs)
+	}
+	if b, _ := os.ReadFile(path); string(b) != doc {
+		t.Fatalf("expected the YAML document to be encrypted as is, got:\n%s", b)
+	}
+}
+
 func TestEncryptDotenvFileWithSops_NoRecipients_Error(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "x.env")
@@ -129,3 +151,39 @@ func TestAgeRecipientsFromKeyFile_ErrorPaths(t *testing.T) {
 		t.Fatalf("expected invalid input error for malformed key file, got: %v", err)
 	}
 }
+
+func TestIsSopsEncrypted(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "plain.env")
+	enc := filepath.Join(dir, "enc.env")
+	if err := os.WriteFile(plain, []byte("TOKEN=abc\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(enc, []byte("TOKEN=ENC[AES256_GCM,data:x]\nsops_version=3.9.0\nsops_mac=ENC[AES256_GCM,data:y]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := IsSopsEncrypted(plain); err != nil || got {
+		t.Fatalf("plain file: got %v, %v", got, err)
+	}
+	if got, err := IsSopsEncrypted(enc); err != nil || !got {
+		t.Fatalf("encrypted file: got %v, %v", got, err)
+	}
+	for name, content := range map[string]string{
+		"plain.yaml": "db:\n  password: abc\n",
+		"enc.yaml":   "db:\n  password: ENC[AES256_GCM,data:x]\nsops:\n  mac: ENC[AES256_GCM,data:y]\n",
+		"plain.json": `{"db": {"password": "abc"}}`,
+		"enc.json":   `{"db": {"password": "ENC[AES256_GCM,data:x]"}, "sops": {"mac": "ENC[AES256_GCM,data:y]"}}`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		want := strings.HasPrefix(name, "enc.")
+		if got, err := IsSopsEncrypted(path); err != nil || got != want {
+			t.Fatalf("%s: got %v, %v; want %v", name, got, err, want)
+		}
+	}
+	if _, err := IsSopsEncrypted(filepath.Join(dir, "missing.env")); !apperr.IsKind(err, apperr.NotFound) {
+		t.Fatalf("expected NotFound for a missing file, got %v", err)
+	}
+}
@@ -23,6 +23,25 @@ type SopsOptions struct {
 	PgpPinentryMode string // "default" | "loopback"
 	PgpPassphrase   string // interpolated already; not logged
 	PgpRecipients   []string
+	// GnupgHome is the GnuPG home for this invocation (--gnupg-home). It takes
+	// precedence over PgpKeyringDir, e.g. to use an ephemeral keyring on CI.
+	GnupgHome string
+}
+
+// GnupgHomeDir returns the GnuPG home sops is run with, GnupgHome or else
+// PgpKeyringDir, with a leading ~/ expanded. It is "" when neither is set and
+// gpg uses its default home.
+func (o SopsOptions) GnupgHomeDir() string {
+	dir := strings.TrimSpace(o.GnupgHome)
+	if dir == "" {
+		dir = strings.TrimSpace(o.PgpKeyringDir)
+	}
+	if strings.HasPrefix(dir, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, dir[2:])
+		}
+	}
+	return dir
 }
 
 // buildSopsEnv builds environment variables for SOPS without mutating global process environment.
@@ -60,13 +79,7 @@ func buildSopsEnv(opts SopsOptions) []string {
 	}
 
 	// Prepare environment for SOPS/PGP (GnuPG)
-	if strings.TrimSpace(opts.PgpKeyringDir) != "" {
-		dir := opts.PgpKeyringDir
-		if strings.HasPrefix(dir, "~/") {
-			if home, err := os.UserHomeDir(); err == nil {
-				dir = filepath.Join(home, dir[2:])
-			}
-		}
+	if dir := opts.GnupgHomeDir(); dir != "" {
 		setEnv("GNUPGHOME", dir)
 
 		// Loopback handling: request loopback mode if configured
@@ -78,17 +91,31 @@ func buildSopsEnv(opts SopsOptions) []string {
 	return env
 }
 
-// DecryptAndParse returns key=value pairs from a SOPS-encrypted dotenv file.
-// Only dotenv format is supported. If no SOPS backends are configured, the file is treated as plaintext.
+// DecryptAndParse returns key=value pairs from a SOPS-encrypted secrets file.
+// The format follows the extension (see FormatForPath): dotenv files are read
+// as they are, YAML and JSON files are flattened as FlattenKeys describes.
+// If no SOPS backends are configured, the file is treated as plaintext.
 // This function is safe for concurrent use across multiple goroutines.
 func DecryptAndParse(ctx context.Context, path string, opts SopsOptions) ([]string, error) {
+	out, err := DecryptFile(ctx, path, opts)
+	if err != nil {
+		return nil, err
+	}
+	return parsePayload(path, FormatForPath(path), out)
+}
+
+// DecryptFile returns the decrypted content of a SOPS-encrypted secrets file
+// in the file's own format (see FormatForPath). If no SOPS backends are
+// configured, the file is returned as it is.
+func DecryptFile(ctx context.Context, path string, opts SopsOptions) ([]byte, error) {
+	format := FormatForPath(path)
 	// If neither Age nor PGP is configured, treat as plaintext
-	if strings.TrimSpace(opts.AgeKeyFile) == "" && strings.TrimSpace(opts.PgpKeyringDir) == "" {
+	if strings.TrimSpace(opts.AgeKeyFile) == "" && opts.GnupgHomeDir() == "" {
 		b, err := os.ReadFile(path)
 		if err != nil {
 			return nil, apperr.Wrap("secrets.DecryptAndParse", apperr.NotFound, err, "read plaintext file %s", path)
 		}
-		return parseDotenv(string(b)), nil
+		return b, nil
 	}
 
 	// Ensure sops binary exists
@@ -100,7 +127,7 @@ func DecryptAndParse(ctx context.Context, path string, opts SopsOptions) ([]stri
 	env := buildSopsEnv(opts)
 
 	// Decrypt file using system sops
-	cmd := exec.CommandContext(ctx, "sops", "--decrypt", "--input-type", "dotenv", path)
+	cmd := exec.CommandContext(ctx, "sops", "--decrypt", "--input-type", format, "--output-type", format, path)
 	cmd.Env = env
 	out, err := cmd.Output()
 	if err != nil {
@@ -109,12 +136,49 @@ func DecryptAndParse(ctx context.Context, path string, opts SopsOptions) ([]stri
 		}
 		return nil, apperr.Wrap("secrets.DecryptAndParse", apperr.External, err, "sops decrypt %s", path)
 	}
+	return out, nil
+}
 
-	return parseDotenv(string(out)), nil
+// ReadDotenvFile returns key=value pairs from a plaintext dotenv file.
+func ReadDotenvFile(path string) ([]string, error) {
+	entries, err := ReadDotenvEntries(path)
+	if err != nil {
+		return nil, err
+	}
+	pairs := make([]string, 0, len(entries))
+	for _, e := range entries {
+		pairs = append(pairs, e.Key+"="+e.Value)
+	}
+	return pairs, nil
+}
+
+// DotenvEntry is one variable of a dotenv file. Literal reports a
+// single-quoted value, which compose does not interpolate.
+type DotenvEntry struct {
+	Key     string
+	Value   string
+	Literal bool
+}
+
+// ReadDotenvEntries returns the variables of a plaintext dotenv file in order.
+func ReadDotenvEntries(path string) ([]DotenvEntry, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, apperr.Wrap("secrets.ReadDotenvFile", apperr.NotFound, err, "read env file %s", path)
+	}
+	return parseDotenvEntries(string(b)), nil
 }
 
 func parseDotenv(s string) []string {
 	var pairs []string
+	for _, e := range parseDotenvEntries(s) {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", e.Key, e.Value))
+	}
+	return pairs
+}
+
+func parseDotenvEntries(s string) []DotenvEntry {
+	var entries []DotenvEntry
 	for _, line := range strings.Split(s, "\n") {
 		line = strings.TrimSpace(line)
 		if line == "" || strings.HasPrefix(line, "#") {
@@ -129,12 +193,13 @@ func parseDotenv(s string) []string {
 		}
 		key := strings.TrimSpace(kv[0])
 		val := strings.TrimSpace(kv[1])
+		literal := len(val) >= 2 && strings.HasPrefix(val, "'") && strings.HasSuffix(val, "'")
 		val = strings.Trim(val, `"`)
 		val = strings.Trim(val, `'`)
 		if key == "" {
 			continue
 		}
-		pairs = append(pairs, fmt.Sprintf("%s=%s", key, val))
+		entries = append(entries, DotenvEntry{Key: key, Value: val, Literal: literal})
 	}
-	return pairs
+	return entries
 }
@@ -78,3 +78,22 @@ func TestBuildSopsEnv_DoesNotSetLoopbackWhenAgentEnabled(t *testing.T) {
 		t.Fatalf("did not expect SOPS_GPG_EXEC when pgp agent is enabled, got: %q", got)
 	}
 }
+
+func TestBuildSopsEnv_GnupgHomeOverridesKeyringDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	opts := SopsOptions{PgpKeyringDir: "/keyrings/team", GnupgHome: "~/ci-gnupg", PgpPinentryMode: "loopback"}
+	env := buildSopsEnv(opts)
+	want := filepath.Join(home, "ci-gnupg")
+	if got := envValue(env, "GNUPGHOME"); got != want {
+		t.Fatalf("GNUPGHOME = %q, want %q", got, want)
+	}
+	if got := envValue(env, "SOPS_GPG_EXEC"); got != "gpg --pinentry-mode loopback" {
+		t.Fatalf("expected loopback to still apply with an override, got %q", got)
+	}
+	if got := (SopsOptions{PgpKeyringDir: "/keyrings/team"}).GnupgHomeDir(); got != "/keyrings/team" {
+		t.Fatalf("expected keyring_dir without an override, got %q", got)
+	}
+}
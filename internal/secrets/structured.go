@@ -0,0 +1,163 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/goccy/go-yaml"
+)
+
+// Secrets file formats, named as sops names its --input-type values.
+const (
+	FormatDotenv = "dotenv"
+	FormatYAML   = "yaml"
+	FormatJSON   = "json"
+)
+
+// FormatForPath returns the format of a secrets file from its extension:
+// .yaml and .yml are YAML, .json is JSON and anything else is dotenv.
+func FormatForPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".json":
+		return FormatJSON
+	default:
+		return FormatDotenv
+	}
+}
+
+// parsePayload turns a decrypted secrets file into KEY=value pairs according
+// to its format. YAML and JSON documents are flattened as FlattenKeys does.
+func parsePayload(path, format string, data []byte) ([]string, error) {
+	var doc any
+	// Parse errors never quote the payload, which holds the secrets.
+	switch format {
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, apperr.Wrap("secrets.DecryptAndParse", apperr.InvalidInput, err, "secrets file %s: decrypted payload is not valid yaml: %s", path, yaml.FormatError(err, false, false))
+		}
+	case FormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&doc); err != nil {
+			return nil, apperr.Wrap("secrets.DecryptAndParse", apperr.InvalidInput, err, "secrets file %s: decrypted payload is not valid json: %v", path, err)
+		}
+	default:
+		return parseDotenv(string(data)), nil
+	}
+	if doc == nil {
+		return nil, nil
+	}
+	if _, ok := doc.(map[string]any); !ok {
+		return nil, apperr.New("secrets.DecryptAndParse", apperr.InvalidInput, "secrets file %s: %s payload must be a mapping of keys to values", path, format)
+	}
+	pairs, err := FlattenKeys(doc)
+	if err != nil {
+		return nil, apperr.Wrap("secrets.DecryptAndParse", apperr.InvalidInput, err, "secrets file %s: %s", path, err.Error())
+	}
+	return pairs, nil
+}
+
+// FlattenKeys turns a decoded YAML or JSON document into sorted KEY=value
+// pairs. Nested keys are joined with "_", list items use their index, and the
+// result is upper-cased with every character other than a letter, digit or
+// "_" replaced by "_": {"db": {"password": "x"}} and {"db.password": "x"}
+// both become DB_PASSWORD=x, and {"hosts": ["a", "b"]} becomes HOSTS_0=a and
+// HOSTS_1=b. Scalars are written as they appear in the document and null as
+// an empty value. Two keys flattening to the same name are an error.
+func FlattenKeys(doc any) ([]string, error) {
+	values := map[string]string{}
+	sources := map[string]string{}
+	var walk func(prefix, source string, v any) error
+	walk = func(prefix, source string, v any) error {
+		join := func(key string) (string, string) {
+			if prefix == "" {
+				return envName(key), key
+			}
+			return prefix + "_" + envName(key), source + "." + key
+		}
+		switch t := v.(type) {
+		case map[string]any:
+			for k, child := range t {
+				name, src := join(k)
+				if err := walk(name, src, child); err != nil {
+					return err
+				}
+			}
+		case map[any]any:
+			for k, child := range t {
+				name, src := join(fmt.Sprint(k))
+				if err := walk(name, src, child); err != nil {
+					return err
+				}
+			}
+		case []any:
+			for i, child := range t {
+				name, src := join(strconv.Itoa(i))
+				if err := walk(name, src, child); err != nil {
+					return err
+				}
+			}
+		default:
+			if prev, dup := sources[prefix]; dup {
+				a, b := prev, source
+				if b < a {
+					a, b = b, a
+				}
+				return fmt.Errorf("keys %s and %s both flatten to %s", a, b, prefix)
+			}
+			sources[prefix] = source
+			values[prefix] = scalarString(t)
+		}
+		return nil
+	}
+	if err := walk("", "", doc); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, name+"="+values[name])
+	}
+	return pairs, nil
+}
+
+// envName upper-cases key and replaces what an environment variable name
+// cannot hold with "_".
+func envName(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+}
+
+// scalarString renders a YAML or JSON scalar as an environment value.
+func scalarString(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		return fmt.Sprint(t)
+	}
+}
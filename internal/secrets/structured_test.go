@@ -0,0 +1,82 @@
+package secrets
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+func TestDecryptAndParse_FlattensStructuredFormats(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+		want    []string
+	}{
+		{
+			file:    "secrets.yaml",
+			content: "db:\n  password: s3cret\n  port: 5432\napi-key: abc\nfeature.enabled: true\nempty: null\n",
+			want:    []string{"API_KEY=abc", "DB_PASSWORD=s3cret", "DB_PORT=5432", "EMPTY=", "FEATURE_ENABLED=true"},
+		},
+		{
+			file:    "secrets.json",
+			content: `{"db": {"password": "s3cret", "ratio": 0.25}, "hosts": ["a", "b"], "big": 12345678901234567890}`,
+			want:    []string{"BIG=12345678901234567890", "DB_PASSWORD=s3cret", "DB_RATIO=0.25", "HOSTS_0=a", "HOSTS_1=b"},
+		},
+		{
+			file:    "secrets.env",
+			content: "db.password=kept-as-is\n",
+			want:    []string{"db.password=kept-as-is"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := DecryptAndParse(context.Background(), path, SopsOptions{})
+			if err != nil {
+				t.Fatalf("DecryptAndParse: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("pairs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecryptAndParse_RejectsMalformedStructuredPayload(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+		wantErr string
+	}{
+		{file: "secrets.json", content: `{"db": `, wantErr: "decrypted payload is not valid json"},
+		{file: "secrets.yaml", content: "db: [unclosed\n", wantErr: "decrypted payload is not valid yaml"},
+		{file: "list.yaml", content: "- a\n- b\n", wantErr: "yaml payload must be a mapping"},
+		{file: "dup.yaml", content: "db:\n  password: a\ndb_password: b\n", wantErr: "keys db.password and db_password both flatten to DB_PASSWORD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			_, err := DecryptAndParse(context.Background(), path, SopsOptions{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), path) {
+				t.Fatalf("expected the error to name %s, got %v", path, err)
+			}
+			if !apperr.IsKind(err, apperr.InvalidInput) {
+				t.Fatalf("expected InvalidInput, got %v", err)
+			}
+		})
+	}
+}
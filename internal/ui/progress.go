@@ -92,13 +92,15 @@ func (p *Progress) Stop() {
 }
 
 // SetAction sets the action text shown under the progress bar and re-renders.
+// It is safe to call from concurrent fileset syncs.
 func (p *Progress) SetAction(text string) {
 	p.mu.Lock()
+	defer p.mu.Unlock()
 	p.action = text
-	p.mu.Unlock()
 	p.render()
 }
 
+// render draws the bar and action line; callers hold p.mu.
 func (p *Progress) render() {
 	if !p.enabled || p.out == nil || p.total <= 0 {
 		return
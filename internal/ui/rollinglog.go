@@ -13,6 +13,7 @@ import (
 	"github.com/charmbracelet/x/ansi"
 	"golang.org/x/term"
 
+	"github.com/gcstr/dockform/internal/drain"
 	"github.com/gcstr/dockform/internal/logger"
 )
 
@@ -97,14 +98,24 @@ func RunWithRollingLog(ctx context.Context, fn func(ctx context.Context) (string
 
 	// Monitor for UI cancellation (Ctrl+C in Bubble Tea) and parent context cancellation
 	go func() {
-		select {
-		case <-cancelCh:
-			// User pressed Ctrl+C in the UI
-			cancel()
-			p.Send(interrupted{})
-		case <-ctx.Done():
-			// Parent context was cancelled (e.g., signal from OS)
-			p.Send(interrupted{})
+		dc := drain.FromContext(ctx)
+		for {
+			select {
+			case <-cancelCh:
+				// User pressed Ctrl+C in the UI. The first press lets a running
+				// apply finish its current resource; the next one cancels.
+				if !dc.Draining() && dc.Request() {
+					p.Send(appendLog{line: "stopping after the current resource; press Ctrl+C again to abort"})
+					continue
+				}
+				cancel()
+				p.Send(interrupted{})
+				return
+			case <-ctx.Done():
+				// Parent context was cancelled (e.g., signal from OS)
+				p.Send(interrupted{})
+				return
+			}
 		}
 	}()
 
@@ -92,6 +92,13 @@ func TarDirectoryToWriter(localDir string, targetPrefix string, w io.Writer) err
 // - directories will be created implicitly for files; directory headers are included as needed.
 // - symlinks are ignored; non-regular special files are skipped.
 func TarFilesToWriter(localRoot string, files []string, w io.Writer) error {
+	return TarTransformedFilesToWriter(localRoot, files, nil, w)
+}
+
+// TarTransformedFilesToWriter is TarFilesToWriter with each regular file's
+// content passed through transform (when non-nil) before it is archived. rel is
+// the slash-separated path of the file in the archive.
+func TarTransformedFilesToWriter(localRoot string, files []string, transform func(rel string, data []byte) ([]byte, error), w io.Writer) error {
 	tw := tar.NewWriter(w)
 	defer func() { _ = tw.Close() }()
 	localRoot = filepath.Clean(localRoot)
@@ -175,11 +182,28 @@ func TarFilesToWriter(localRoot string, files []string, w io.Writer) error {
 			// Skip non-regular files
 			continue
 		}
+		hdr.Typeflag = tar.TypeReg
+		if transform != nil {
+			data, err := os.ReadFile(abs)
+			if err != nil {
+				return err
+			}
+			if data, err = transform(name, data); err != nil {
+				return err
+			}
+			hdr.Size = int64(len(data))
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if _, err := tw.Write(data); err != nil {
+				return err
+			}
+			continue
+		}
 		f, err := os.Open(abs)
 		if err != nil {
 			return err
 		}
-		hdr.Typeflag = tar.TypeReg
 		hdr.Size = info.Size()
 		if err := tw.WriteHeader(hdr); err != nil {
 			_ = f.Close()
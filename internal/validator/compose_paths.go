@@ -0,0 +1,80 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// composeFileNames are the file names compose looks for by default.
+var composeFileNames = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+// suggestComposePath looks for the file a missing compose path most likely
+// meant, and returns it relative to the stack root. Compose resolves files
+// against the project directory (the stack root), so a path written relative
+// to the manifest, one that repeats the root's own directories, or one with a
+// different default file name is a common mistake. It returns "" when nothing
+// plausible exists.
+func suggestComposePath(baseDir, root, file string) string {
+	if root == "" || filepath.IsAbs(file) {
+		return ""
+	}
+	var candidates []string
+
+	// Written relative to the manifest instead of the stack root.
+	if baseDir != "" {
+		candidates = append(candidates, filepath.Join(baseDir, file))
+	}
+	// Leading directories that duplicate the root, e.g. "website/compose.yaml"
+	// under root "website".
+	parts := strings.Split(filepath.ToSlash(filepath.Clean(file)), "/")
+	for i := 1; i < len(parts); i++ {
+		candidates = append(candidates, filepath.Join(root, filepath.Join(parts[i:]...)))
+	}
+	// Another default compose file name next to where the file was expected.
+	if isComposeFileName(filepath.Base(file)) {
+		dir := filepath.Dir(filepath.Join(root, file))
+		for _, name := range composeFileNames {
+			candidates = append(candidates, filepath.Join(dir, name))
+		}
+	}
+
+	for _, c := range candidates {
+		st, err := os.Stat(c)
+		if err != nil || st.IsDir() {
+			continue
+		}
+		rel, err := filepath.Rel(root, c)
+		if err != nil {
+			return c
+		}
+		return filepath.ToSlash(rel)
+	}
+	return ""
+}
+
+func isComposeFileName(name string) bool {
+	for _, n := range composeFileNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkComposeYAML checks that a compose file is well-formed YAML. It is the
+// offline stand-in for `docker compose config`, which also resolves the file.
+func checkComposeYAML(root, file string) error {
+	p := file
+	if !filepath.IsAbs(p) && root != "" {
+		p = filepath.Join(root, p)
+	}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return err
+	}
+	var doc map[string]any
+	return yaml.Unmarshal(b, &doc)
+}
@@ -0,0 +1,157 @@
+package validator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+// dependencyCycle builds the graph of what each resource needs before it can
+// come up and reports the first cycle as an error naming its path. A service
+// needs the services it depends_on and the volumes it mounts; a volume needs
+// the filesets that sync into it. A fileset needs the restart_services it
+// restarts once synced, except those mounting its own target volume, which is
+// what restarting them is for (so restart_services: attached adds nothing). A
+// stack's services need its pre_apply hooks, which need the volumes of the
+// hook service they run in.
+func dependencyCycle(cfg manifest.Config, docs map[string]dockercli.ComposeConfigDoc) error {
+	allStacks := cfg.GetAllStacks()
+	allFilesets := cfg.GetAllFilesets()
+	edges := map[string][]string{}
+	addEdge := func(from, to string) {
+		edges[from] = append(edges[from], to)
+		if _, ok := edges[to]; !ok {
+			edges[to] = nil
+		}
+	}
+
+	volumeNode := func(contextName, name string) string { return "volume " + contextName + "/" + name }
+	serviceNode := func(stackKey, svc string) string { return "service " + stackKey + "/" + svc }
+	for name, fs := range allFilesets {
+		if fs.TargetVolume == "" {
+			continue
+		}
+		addEdge(volumeNode(fs.Context, fs.TargetVolume), "fileset "+name)
+	}
+
+	// mounts records the volume nodes each service mounts.
+	mounts := map[string]map[string]bool{}
+	for stackKey, doc := range docs {
+		contextName, stackName, err := manifest.ParseStackKey(stackKey)
+		if err != nil {
+			continue
+		}
+		st := allStacks[stackKey]
+		project := stackName
+		if st.Project != nil && st.Project.Name != "" {
+			project = st.Project.Name
+		}
+		var hooks, hookService string
+		if st.Hooks != nil && st.Hooks.Service != "" && len(st.Hooks.PreApply) > 0 {
+			hooks, hookService = "hooks "+stackKey, st.Hooks.Service
+		}
+		for svcName, svc := range doc.Services {
+			from := serviceNode(stackKey, svcName)
+			if _, ok := edges[from]; !ok {
+				edges[from] = nil
+			}
+			for _, dep := range svc.DependsOn {
+				addEdge(from, serviceNode(stackKey, dep))
+			}
+			for _, m := range svc.Volumes {
+				if m.Type != "volume" || m.Source == "" {
+					continue
+				}
+				// Compose prefixes stack-local volumes with the project name;
+				// filesets name the volume as it exists on the daemon.
+				for _, v := range []string{m.Source, project + "_" + m.Source} {
+					vn := volumeNode(contextName, v)
+					if _, ok := edges[vn]; !ok {
+						continue
+					}
+					addEdge(from, vn)
+					if mounts[from] == nil {
+						mounts[from] = map[string]bool{}
+					}
+					mounts[from][vn] = true
+					if hooks != "" && svcName == hookService {
+						addEdge(hooks, vn)
+					}
+				}
+			}
+			// The hook service itself only runs as a one-off container for
+			// the hooks, so it does not wait for them.
+			if hooks != "" && svcName != hookService {
+				addEdge(from, hooks)
+			}
+		}
+	}
+
+	// Restarts are by service name across the fileset's context.
+	for name, fs := range allFilesets {
+		target := volumeNode(fs.Context, fs.TargetVolume)
+		for _, svc := range fs.RestartServices.Services {
+			for stackKey, doc := range docs {
+				contextName, _, err := manifest.ParseStackKey(stackKey)
+				if err != nil || contextName != fs.Context {
+					continue
+				}
+				if _, ok := doc.Services[svc]; !ok {
+					continue
+				}
+				if n := serviceNode(stackKey, svc); !mounts[n][target] {
+					addEdge("fileset "+name, n)
+				}
+			}
+		}
+	}
+
+	// Depth-first search in sorted order so the reported cycle is stable.
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := map[string]int{}
+	var stack []string
+	var cycle []string
+	var visit func(n string) bool
+	visit = func(n string) bool {
+		state[n] = visiting
+		stack = append(stack, n)
+		next := append([]string(nil), edges[n]...)
+		sort.Strings(next)
+		for _, m := range next {
+			switch state[m] {
+			case visiting:
+				for i, s := range stack {
+					if s == m {
+						cycle = append(append([]string(nil), stack[i:]...), m)
+						return true
+					}
+				}
+			case unvisited:
+				if visit(m) {
+					return true
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[n] = done
+		return false
+	}
+	nodes := make([]string, 0, len(edges))
+	for n := range edges {
+		nodes = append(nodes, n)
+	}
+	sort.Strings(nodes)
+	for _, n := range nodes {
+		if state[n] == unvisited && visit(n) {
+			return apperr.New("validator.Validate", apperr.InvalidInput, "dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package validator
+
+import (
+	"context"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+// dockerSecretNames returns the docker secrets a stack declares it depends on.
+func dockerSecretNames(stack manifest.Stack) []string {
+	if stack.Secrets == nil {
+		return nil
+	}
+	return stack.Secrets.Docker
+}
+
+// validateDockerSecrets checks that every docker secret a stack depends on exists
+// on its context. Dockform never reads these secrets; compose mounts them.
+// known caches the secret list per context across stacks.
+func validateDockerSecrets(ctx context.Context, client *dockercli.Client, contextName, stackKey string, names []string, known map[string]map[string]struct{}) error {
+	if len(names) == 0 {
+		return nil
+	}
+	secrets, ok := known[contextName]
+	if !ok {
+		// Skip the secret listing on daemons known not to be in swarm mode, where
+		// it can only fail.
+		if caps, err := client.Ping(ctx); err == nil && !caps.Swarm {
+			return apperr.New("validator.Validate", apperr.Precondition, "stack %s: docker secrets require swarm mode, but the daemon on context %s is not a swarm node", stackKey, contextName)
+		}
+		list, err := client.ListSecrets(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return apperr.Wrap("validator.Validate", apperr.External, err, "stack %s: list docker secrets on context %s (docker secrets require a swarm manager)", stackKey, contextName)
+		}
+		secrets = make(map[string]struct{}, len(list))
+		for _, s := range list {
+			secrets[s] = struct{}{}
+		}
+		known[contextName] = secrets
+	}
+	for _, name := range names {
+		if _, ok := secrets[name]; !ok {
+			return apperr.New("validator.Validate", apperr.NotFound, "stack %s: docker secret %s not found on context %s", stackKey, name, contextName)
+		}
+	}
+	return nil
+}
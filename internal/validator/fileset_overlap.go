@@ -0,0 +1,77 @@
+package validator
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+// filesetOverlapWarnings cross-references fileset targets against the parsed compose
+// mounts of every stack in the same context. A fileset that syncs into a path a
+// service mounts read-write can overwrite application-generated files, and files
+// removed from the fileset source are deleted from the volume on the next apply.
+func filesetOverlapWarnings(cfg manifest.Config, docs map[string]dockercli.ComposeConfigDoc) []string {
+	if len(docs) == 0 {
+		return nil
+	}
+	allStacks := cfg.GetAllStacks()
+
+	var warnings []string
+	for name, fs := range cfg.GetAllFilesets() {
+		if fs.TargetVolume == "" {
+			continue
+		}
+		for stackKey, doc := range docs {
+			contextName, stackName, err := manifest.ParseStackKey(stackKey)
+			if err != nil || (fs.Context != "" && contextName != fs.Context) {
+				continue
+			}
+			project := stackName
+			if st, ok := allStacks[stackKey]; ok && st.Project != nil && st.Project.Name != "" {
+				project = st.Project.Name
+			}
+			for svcName, svc := range doc.Services {
+				for _, m := range svc.Volumes {
+					if m.ReadOnly || m.Type != "volume" {
+						continue
+					}
+					if m.Source != fs.TargetVolume && project+"_"+m.Source != fs.TargetVolume {
+						continue
+					}
+					mountPath := "/"
+					if m.Volume != nil && m.Volume.Subpath != "" {
+						mountPath = m.Volume.Subpath
+					}
+					if !volumePathsOverlap(fs.TargetPath, mountPath) {
+						continue
+					}
+					warnings = append(warnings, fmt.Sprintf(
+						"fileset %s syncs to %s:%s, which service %s in stack %s mounts read-write at %s; "+
+							"files it writes there may be overwritten or deleted on apply. "+
+							"Use a dedicated target_path the service does not write to, or mount the volume read-only",
+						name, fs.TargetVolume, cleanVolumePath(fs.TargetPath), svcName, stackKey, m.Target))
+				}
+			}
+		}
+	}
+	sort.Strings(warnings)
+	return warnings
+}
+
+// volumePathsOverlap reports whether two paths inside a volume are equal or one
+// contains the other.
+func volumePathsOverlap(a, b string) bool {
+	a, b = cleanVolumePath(a), cleanVolumePath(b)
+	if a == "/" || b == "/" || a == b {
+		return true
+	}
+	return strings.HasPrefix(a, b+"/") || strings.HasPrefix(b, a+"/")
+}
+
+func cleanVolumePath(p string) string {
+	return path.Clean("/" + strings.TrimSpace(p))
+}
@@ -0,0 +1,61 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+// platformWarnings flags services pinned to a platform their context's daemon
+// does not run natively: the image then runs under emulation, or not at all.
+// Contexts whose capabilities cannot be detected are skipped.
+func platformWarnings(ctx context.Context, docs map[string]dockercli.ComposeConfigDoc, clientFor func(contextName string) *dockercli.Client) []string {
+	caps := map[string]*dockercli.Capabilities{}
+	stackKeys := make([]string, 0, len(docs))
+	for k := range docs {
+		stackKeys = append(stackKeys, k)
+	}
+	sort.Strings(stackKeys)
+
+	var warnings []string
+	for _, stackKey := range stackKeys {
+		contextName, _, err := manifest.ParseStackKey(stackKey)
+		if err != nil {
+			continue
+		}
+		doc := docs[stackKey]
+		services := make([]string, 0, len(doc.Services))
+		for name, svc := range doc.Services {
+			if svc.Platform != "" {
+				services = append(services, name)
+			}
+		}
+		if len(services) == 0 {
+			continue
+		}
+		sort.Strings(services)
+
+		c, seen := caps[contextName]
+		if !seen {
+			if client := clientFor(contextName); client != nil {
+				if detected, err := client.Ping(ctx); err == nil {
+					c = &detected
+				}
+			}
+			caps[contextName] = c
+		}
+		if c == nil {
+			continue
+		}
+		for _, name := range services {
+			platform := doc.Services[name].Platform
+			if !c.MatchesPlatform(platform) {
+				warnings = append(warnings, fmt.Sprintf("stack %s service %s: platform %s does not match the %s daemon on context %s; the image will run under emulation or fail to start", stackKey, name, platform, c.Platform(), contextName))
+			}
+		}
+	}
+	return warnings
+}
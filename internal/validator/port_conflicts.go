@@ -0,0 +1,193 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+// portBinding is one host port published by a service.
+type portBinding struct {
+	stackKey string
+	service  string
+	hostIP   string
+	protocol string
+	port     int
+}
+
+func (b portBinding) owner() string { return b.stackKey + " (service " + b.service + ")" }
+
+// portConflicts detects host ports published by more than one service on the
+// same context. Bindings collide when protocol and port match and their host
+// IPs overlap; an unset or wildcard host IP overlaps every address. Published
+// ranges are expanded so partially overlapping ranges are caught too.
+func portConflicts(docs map[string]dockercli.ComposeConfigDoc) error {
+	byContext := map[string][]portBinding{}
+	for stackKey, doc := range docs {
+		contextName, _, err := manifest.ParseStackKey(stackKey)
+		if err != nil {
+			continue
+		}
+		for svcName, svc := range doc.Services {
+			for _, p := range svc.Ports {
+				byContext[contextName] = append(byContext[contextName], expandPublished(stackKey, svcName, p)...)
+			}
+		}
+	}
+
+	// Conflicting ports grouped by the pair of owners and protocol, so a range
+	// collision is reported once rather than port by port.
+	type pairKey struct{ a, b, protocol string }
+	conflicts := map[pairKey][]int{}
+	for _, bindings := range byContext {
+		byPort := map[string][]portBinding{}
+		for _, b := range bindings {
+			k := b.protocol + "/" + strconv.Itoa(b.port)
+			byPort[k] = append(byPort[k], b)
+		}
+		for _, group := range byPort {
+			for i := 0; i < len(group); i++ {
+				for j := i + 1; j < len(group); j++ {
+					x, y := group[i], group[j]
+					if x.owner() == y.owner() || !hostIPsOverlap(x.hostIP, y.hostIP) {
+						continue
+					}
+					a, b := x.owner(), y.owner()
+					if b < a {
+						a, b = b, a
+					}
+					k := pairKey{a: a, b: b, protocol: x.protocol}
+					conflicts[k] = append(conflicts[k], x.port)
+				}
+			}
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	msgs := make([]string, 0, len(conflicts))
+	for k, ports := range conflicts {
+		label, ranges := "port", formatPortRanges(ports)
+		if strings.ContainsAny(ranges, ",-") {
+			label = "ports"
+		}
+		msgs = append(msgs, fmt.Sprintf("host %s %s/%s published by both %s and %s", label, ranges, k.protocol, k.a, k.b))
+	}
+	sort.Strings(msgs)
+	return apperr.New("validator.Validate", apperr.Conflict, "%d host port conflict(s) between services: %s", len(msgs), strings.Join(msgs, "; "))
+}
+
+// PublishedPort is a host port a service of a stack publishes.
+type PublishedPort struct {
+	Stack    string
+	Service  string
+	HostIP   string // empty when published on every address
+	Protocol string
+	Port     int
+}
+
+// PublishedPorts returns the host ports the services of a stack publish, with
+// ranges expanded. Ports without a published host port are left out.
+func PublishedPorts(stackKey string, doc dockercli.ComposeConfigDoc) []PublishedPort {
+	var out []PublishedPort
+	for svcName, svc := range doc.Services {
+		for _, p := range svc.Ports {
+			for _, b := range expandPublished(stackKey, svcName, p) {
+				out = append(out, PublishedPort{Stack: b.stackKey, Service: b.service, HostIP: b.hostIP, Protocol: b.protocol, Port: b.port})
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Port != out[j].Port {
+			return out[i].Port < out[j].Port
+		}
+		if out[i].Protocol != out[j].Protocol {
+			return out[i].Protocol < out[j].Protocol
+		}
+		return out[i].Service < out[j].Service
+	})
+	return out
+}
+
+// HostIPsOverlap reports whether two bind addresses can collide; an unset or
+// wildcard address overlaps every address.
+func HostIPsOverlap(a, b string) bool { return hostIPsOverlap(a, b) }
+
+// expandPublished returns the host bindings of a compose port entry. Entries
+// without a published port get an ephemeral host port and never conflict.
+func expandPublished(stackKey, service string, p dockercli.ComposePort) []portBinding {
+	var published string
+	switch v := p.Published.(type) {
+	case string:
+		published = strings.TrimSpace(v)
+	case float64:
+		published = strconv.Itoa(int(v))
+	case int:
+		published = strconv.Itoa(v)
+	case uint64:
+		published = strconv.FormatUint(v, 10)
+	}
+	if published == "" {
+		return nil
+	}
+	lo, hi, ok := parsePortRange(published)
+	if !ok {
+		return nil
+	}
+	protocol := strings.ToLower(strings.TrimSpace(p.Protocol))
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	out := make([]portBinding, 0, hi-lo+1)
+	for port := lo; port <= hi; port++ {
+		out = append(out, portBinding{stackKey: stackKey, service: service, hostIP: strings.TrimSpace(p.HostIP), protocol: protocol, port: port})
+	}
+	return out
+}
+
+// parsePortRange parses "8080" or "8000-8010".
+func parsePortRange(s string) (int, int, bool) {
+	loStr, hiStr, isRange := strings.Cut(s, "-")
+	lo, err := strconv.Atoi(strings.TrimSpace(loStr))
+	if err != nil || lo <= 0 {
+		return 0, 0, false
+	}
+	if !isRange {
+		return lo, lo, true
+	}
+	hi, err := strconv.Atoi(strings.TrimSpace(hiStr))
+	if err != nil || hi < lo {
+		return 0, 0, false
+	}
+	return lo, hi, true
+}
+
+func hostIPsOverlap(a, b string) bool {
+	wildcard := func(ip string) bool { return ip == "" || ip == "0.0.0.0" || ip == "::" || ip == "[::]" }
+	return wildcard(a) || wildcard(b) || a == b
+}
+
+// formatPortRanges renders ports as a compact list, e.g. "80,8000-8002".
+func formatPortRanges(ports []int) string {
+	sort.Ints(ports)
+	var parts []string
+	for i := 0; i < len(ports); {
+		j := i
+		for j+1 < len(ports) && ports[j+1] <= ports[j]+1 {
+			j++
+		}
+		if ports[i] == ports[j] {
+			parts = append(parts, strconv.Itoa(ports[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", ports[i], ports[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,82 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gcstr/dockform/internal/apperr"
+)
+
+// Problem is one validation failure together with the manifest key path it
+// concerns, e.g. "stacks.default/web.env-file", and where that key is
+// declared. Its Error is that of the underlying error, so callers that only
+// print the error see the same message as before.
+type Problem struct {
+	Path     string
+	Location string // "dockform.yml:42"; empty when the key is not in the manifest (e.g. discovered)
+	Err      error
+}
+
+func (p *Problem) Error() string { return p.Err.Error() }
+
+func (p *Problem) Unwrap() error { return p.Err }
+
+// Message describes the problem without the operation prefix of apperr
+// errors, e.g. "stack default/web env file app.env".
+func (p *Problem) Message() string {
+	var e *apperr.E
+	if errors.As(p.Err, &e) && e.Msg != "" {
+		return e.Msg
+	}
+	return p.Err.Error()
+}
+
+// String renders the problem as "dockform.yml:42: message", falling back to
+// the key path when its line is unknown.
+func (p *Problem) String() string {
+	where := p.Location
+	if where == "" {
+		where = p.Path
+	}
+	return where + ": " + p.Message()
+}
+
+// Problems returns every validation problem carried by err, in the order they
+// were found, or nil when err holds none.
+func Problems(err error) []*Problem {
+	var multi *apperr.MultiError
+	if errors.As(err, &multi) {
+		var out []*Problem
+		for _, child := range multi.Errors {
+			var p *Problem
+			if errors.As(child, &p) {
+				out = append(out, p)
+			}
+		}
+		return out
+	}
+	var p *Problem
+	if errors.As(err, &p) {
+		return []*Problem{p}
+	}
+	return nil
+}
+
+// problemsError returns nil without problems and the problem itself when there
+// is just one. Several are aggregated into one error whose message lists each
+// with its manifest line.
+func problemsError(problems []error) error {
+	switch len(problems) {
+	case 0:
+		return nil
+	case 1:
+		return problems[0]
+	}
+	lines := make([]string, 0, len(problems))
+	for _, p := range Problems(&apperr.MultiError{Errors: problems}) {
+		lines = append(lines, p.String())
+	}
+	msg := fmt.Sprintf("%d problems found:\n  %s", len(problems), strings.Join(lines, "\n  "))
+	return apperr.Aggregate("validator.Validate", apperr.InvalidInput, msg, problems...)
+}
@@ -0,0 +1,81 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+)
+
+// UnusedDeclarations cross-references the volumes, networks and fileset target
+// volumes declared in the manifest against what compose services actually use,
+// and describes every declaration no service references. Those are usually typos
+// or stale config. docs holds the resolved compose config of each stack, as
+// returned by ValidateWithComposeDocs.
+func UnusedDeclarations(cfg manifest.Config, docs map[string]dockercli.ComposeConfigDoc) []string {
+	usedVolumes, usedNetworks := referencedResources(cfg, docs)
+
+	var out []string
+	for contextName, cc := range cfg.Contexts {
+		for name := range cc.Volumes {
+			if !usedVolumes[contextName][name] {
+				out = append(out, fmt.Sprintf("volume %s in context %s is declared but not used by any service", name, contextName))
+			}
+		}
+		for name := range cc.Networks {
+			if !usedNetworks[contextName][name] {
+				out = append(out, fmt.Sprintf("network %s in context %s is declared but not used by any service", name, contextName))
+			}
+		}
+	}
+	for name, fs := range cfg.GetAllFilesets() {
+		if fs.TargetVolume != "" && !usedVolumes[fs.Context][fs.TargetVolume] {
+			out = append(out, fmt.Sprintf("fileset %s targets volume %s, which no service in context %s mounts", name, fs.TargetVolume, fs.Context))
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// referencedResources returns, per context, the docker volume and network names
+// compose services reference. Each logical compose key counts under every name
+// it may resolve to: the key itself, its project-scoped name, and an explicit
+// top-level name.
+func referencedResources(cfg manifest.Config, docs map[string]dockercli.ComposeConfigDoc) (volumes, networks map[string]map[string]bool) {
+	volumes = map[string]map[string]bool{}
+	networks = map[string]map[string]bool{}
+	allStacks := cfg.GetAllStacks()
+	for stackKey, doc := range docs {
+		contextName, stackName, err := manifest.ParseStackKey(stackKey)
+		if err != nil {
+			continue
+		}
+		project := stackName
+		if st, ok := allStacks[stackKey]; ok && st.Project != nil && st.Project.Name != "" {
+			project = st.Project.Name
+		}
+		if volumes[contextName] == nil {
+			volumes[contextName] = map[string]bool{}
+			networks[contextName] = map[string]bool{}
+		}
+		mark := func(set map[string]bool, key string, top map[string]dockercli.ComposeTopLevelResource) {
+			set[key] = true
+			set[project+"_"+key] = true
+			if r, ok := top[key]; ok && r.Name != "" {
+				set[r.Name] = true
+			}
+		}
+		for _, svc := range doc.Services {
+			for _, m := range svc.Volumes {
+				if m.Type == "volume" && m.Source != "" {
+					mark(volumes[contextName], m.Source, doc.Volumes)
+				}
+			}
+			for _, n := range svc.Networks {
+				mark(networks[contextName], n, doc.Networks)
+			}
+		}
+	}
+	return volumes, networks
+}
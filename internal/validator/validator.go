@@ -2,24 +2,64 @@ package validator
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/gcstr/dockform/internal/apperr"
 	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/filesets"
 	"github.com/gcstr/dockform/internal/manifest"
 )
 
 // Validate performs comprehensive validation of the user config and environment.
 // For multi-context configs, it validates all stacks across each context.
 func Validate(ctx context.Context, cfg manifest.Config, factory *dockercli.DefaultClientFactory) error {
+	_, err := ValidateWithWarnings(ctx, cfg, factory)
+	return err
+}
+
+// ValidateWithWarnings performs the same validation as Validate and additionally
+// returns non-fatal warnings about risky but valid configurations.
+func ValidateWithWarnings(ctx context.Context, cfg manifest.Config, factory *dockercli.DefaultClientFactory) ([]string, error) {
+	warnings, _, err := validate(ctx, cfg, factory, false)
+	return warnings, err
+}
+
+// ValidateWithComposeDocs performs the same validation as ValidateWithWarnings
+// and also returns the compose config it resolved for each stack, by stack key,
+// so callers can inspect the services without running docker compose again.
+func ValidateWithComposeDocs(ctx context.Context, cfg manifest.Config, factory *dockercli.DefaultClientFactory) ([]string, map[string]dockercli.ComposeConfigDoc, error) {
+	return validate(ctx, cfg, factory, false)
+}
+
+// ValidateOffline validates the manifest without talking to any docker daemon:
+// identifier format, SOPS key presence, stack roots and the existence of
+// compose, env, secret and fileset paths. Compose files are only checked to be
+// well-formed YAML, since resolving them needs docker compose; checks that
+// depend on the resolved config (port conflicts, docker secrets, platforms)
+// are skipped.
+func ValidateOffline(ctx context.Context, cfg manifest.Config) ([]string, error) {
+	warnings, _, err := validate(ctx, cfg, nil, true)
+	return warnings, err
+}
+
+func validate(ctx context.Context, cfg manifest.Config, factory *dockercli.DefaultClientFactory, offline bool) ([]string, map[string]dockercli.ComposeConfigDoc, error) {
+	// Every problem is collected so one run reports all of them; only
+	// cancellation stops validation early.
+	var problems []error
+	report := func(path string, err error) {
+		problems = append(problems, &Problem{Path: path, Location: cfg.Source.Locate(path), Err: err})
+	}
+
 	// Validate identifier format (project-wide)
 	if cfg.Identifier != "" {
 		validIdent := regexp.MustCompile(`^[A-Za-z0-9-]+$`)
 		if !validIdent.MatchString(cfg.Identifier) {
-			return apperr.New("validator.Validate", apperr.InvalidInput, "identifier: must match [A-Za-z0-9-]+")
+			report("identifier", apperr.New("validator.Validate", apperr.InvalidInput, "identifier: must match [A-Za-z0-9-]+"))
 		}
 	}
 
@@ -44,56 +84,74 @@ func Validate(ctx context.Context, cfg manifest.Config, factory *dockercli.Defau
 	if hasSopsSecrets && cfg.Sops != nil && cfg.Sops.Age != nil {
 		// Check if key_file is empty - this indicates a missing environment variable
 		if cfg.Sops.Age.KeyFile == "" {
-			return apperr.New("validator.Validate", apperr.InvalidInput,
+			report("sops.age.key_file", apperr.New("validator.Validate", apperr.InvalidInput,
 				"SOPS age key_file is empty but SOPS secrets are configured; "+
 					"if using environment variable interpolation (e.g., ${AGE_KEY_FILE}), "+
-					"ensure the variable is set in your environment")
-		}
-
-		// Validate that the key file exists
-		key := cfg.Sops.Age.KeyFile
-		if strings.HasPrefix(key, "~/") {
-			if home, err := os.UserHomeDir(); err == nil {
-				key = filepath.Join(home, key[2:])
+					"ensure the variable is set in your environment"))
+		} else {
+			// Validate that the key file exists
+			key := cfg.Sops.Age.KeyFile
+			if strings.HasPrefix(key, "~/") {
+				if home, err := os.UserHomeDir(); err == nil {
+					key = filepath.Join(home, key[2:])
+				}
+			}
+			if _, err := os.Stat(key); err != nil {
+				report("sops.age.key_file", apperr.Wrap("validator.Validate", apperr.NotFound, err, "SOPS age key file %s not found", key))
 			}
-		}
-		if _, err := os.Stat(key); err != nil {
-			return apperr.Wrap("validator.Validate", apperr.NotFound, err, "SOPS age key file %s not found", key)
 		}
 	}
 
-	// 3) Validate all stacks (discovered + explicit)
-	for stackKey, stack := range allStacks {
+	// 3) Validate all stacks (discovered + explicit), in key order so
+	// problems are reported in a stable order.
+	composeDocs := map[string]dockercli.ComposeConfigDoc{}
+	dockerSecrets := map[string]map[string]struct{}{}
+	stackKeys := make([]string, 0, len(allStacks))
+	for stackKey := range allStacks {
+		stackKeys = append(stackKeys, stackKey)
+	}
+	sort.Strings(stackKeys)
+	for _, stackKey := range stackKeys {
+		stack := allStacks[stackKey]
+		path := "stacks." + stackKey
 		contextName, stackName, err := manifest.ParseStackKey(stackKey)
 		if err != nil {
-			return apperr.Wrap("validator.Validate", apperr.InvalidInput, err, "invalid stack key %s", stackKey)
+			report(path, apperr.Wrap("validator.Validate", apperr.InvalidInput, err, "invalid stack key %s", stackKey))
+			continue
 		}
 
 		// Get context config and client
 		_, ok := cfg.Contexts[contextName]
 		if !ok {
-			return apperr.New("validator.Validate", apperr.InvalidInput, "stack %s references unknown context %s", stackKey, contextName)
+			report(path, apperr.New("validator.Validate", apperr.InvalidInput, "stack %s references unknown context %s", stackKey, contextName))
+			continue
 		}
-		client := factory.GetClientForContext(contextName, &cfg)
-
-		// Root must exist
+		// Root must exist; without it none of the stack's files can be checked.
 		if stack.Root != "" {
 			if st, err := os.Stat(stack.Root); err != nil || !st.IsDir() {
 				if err != nil {
-					return apperr.Wrap("validator.Validate", apperr.NotFound, err, "stack %s root", stackKey)
+					report(path+".root", apperr.Wrap("validator.Validate", apperr.NotFound, err, "stack %s root", stackKey))
+				} else {
+					report(path+".root", apperr.New("validator.Validate", apperr.InvalidInput, "stack %s root is not a directory: %s", stackKey, stack.Root))
 				}
-				return apperr.New("validator.Validate", apperr.InvalidInput, "stack %s root is not a directory: %s", stackKey, stack.Root)
+				continue
 			}
 		}
 
 		// Compose files
+		filesFound := true
 		for _, f := range stack.Files {
 			p := f
 			if !filepath.IsAbs(p) && stack.Root != "" {
 				p = filepath.Join(stack.Root, p)
 			}
 			if _, err := os.Stat(p); err != nil {
-				return apperr.Wrap("validator.Validate", apperr.NotFound, err, "stack %s compose file %s", stackKey, f)
+				filesFound = false
+				if hint := suggestComposePath(cfg.BaseDir, stack.Root, f); hint != "" {
+					report(path+".files", apperr.Wrap("validator.Validate", apperr.NotFound, err, "stack %s compose file %s not found in root %s (files are relative to the stack root); did you mean %q?", stackKey, f, stack.Root, hint))
+				} else {
+					report(path+".files", apperr.Wrap("validator.Validate", apperr.NotFound, err, "stack %s compose file %s", stackKey, f))
+				}
 			}
 		}
 
@@ -102,17 +160,29 @@ func Validate(ctx context.Context, cfg manifest.Config, factory *dockercli.Defau
 		// slow decryption and key availability issues. This means stacks relying on SOPS
 		// secrets for variable interpolation may fail validation but work at apply.
 		// See TECHNICAL_DEBT.md for details.
-		if len(stack.Files) > 0 && stack.Root != "" {
-			if _, err := client.ComposeConfigFull(ctx, stack.Root, stack.Files, stack.Profiles, stack.EnvFile, []string{}); err != nil {
+		switch {
+		case !filesFound:
+			// Missing files were reported above; parsing would only repeat them.
+		case offline:
+			for _, f := range stack.Files {
+				if err := checkComposeYAML(stack.Root, f); err != nil {
+					report(path+".files", apperr.Wrap("validator.Validate", apperr.InvalidInput, err, "invalid compose file %s for stack %s", f, stackName))
+				}
+			}
+		case len(stack.Files) > 0 && stack.Root != "":
+			client := factory.GetClientForContext(contextName, &cfg)
+			doc, err := client.ComposeConfigFull(dockercli.WithStack(ctx, stack), stack.Root, stack.Files, stack.Profiles, stack.EnvFile, []string{})
+			if err != nil {
 				if ctx.Err() != nil {
-					return ctx.Err()
+					return nil, nil, ctx.Err()
 				}
 				if len(stack.Files) == 1 {
-					return apperr.Wrap("validator.Validate", apperr.External, err, "invalid compose file %s for stack %s", stack.Files[0], stackName)
-				} else if len(stack.Files) > 1 {
-					return apperr.Wrap("validator.Validate", apperr.External, err, "invalid compose files %v for stack %s", stack.Files, stackName)
+					report(path+".files", apperr.Wrap("validator.Validate", apperr.External, err, "invalid compose file %s for stack %s", stack.Files[0], stackName))
+				} else {
+					report(path+".files", apperr.Wrap("validator.Validate", apperr.External, err, "invalid compose files %v for stack %s", stack.Files, stackName))
 				}
-				return apperr.Wrap("validator.Validate", apperr.External, err, "invalid compose file for stack %s", stackName)
+			} else {
+				composeDocs[stackKey] = doc
 			}
 		}
 
@@ -123,7 +193,7 @@ func Validate(ctx context.Context, cfg manifest.Config, factory *dockercli.Defau
 				p = filepath.Join(stack.Root, p)
 			}
 			if _, err := os.Stat(p); err != nil {
-				return apperr.Wrap("validator.Validate", apperr.NotFound, err, "stack %s env file %s", stackKey, e)
+				report(path+".env-file", apperr.Wrap("validator.Validate", apperr.NotFound, err, "stack %s env file %s", stackKey, e))
 			}
 		}
 
@@ -137,81 +207,180 @@ func Validate(ctx context.Context, cfg manifest.Config, factory *dockercli.Defau
 				p = filepath.Join(stack.Root, p)
 			}
 			if _, err := os.Stat(p); err != nil {
-				return apperr.Wrap("validator.Validate", apperr.NotFound, err, "stack %s sops secret %s", stackKey, sp)
+				report(path+".secrets.sops", apperr.Wrap("validator.Validate", apperr.NotFound, err, "stack %s sops secret %s", stackKey, sp))
+			}
+		}
+
+		if !offline {
+			client := factory.GetClientForContext(contextName, &cfg)
+			if err := validateDockerSecrets(ctx, client, contextName, stackKey, dockerSecretNames(stack), dockerSecrets); err != nil {
+				if ctx.Err() != nil {
+					return nil, nil, ctx.Err()
+				}
+				report(path, err)
 			}
 		}
 	}
 
+	// Services on the same daemon can't publish the same host port.
+	if err := portConflicts(composeDocs); err != nil {
+		report("stacks", err)
+	}
+	if err := dependencyCycle(cfg, composeDocs); err != nil {
+		report("stacks", err)
+	}
+
 	// 4) Validate discovered filesets
-	for name, fs := range cfg.GetAllFilesets() {
+	allFilesets := cfg.GetAllFilesets()
+	filesetNames := make([]string, 0, len(allFilesets))
+	for name := range allFilesets {
+		filesetNames = append(filesetNames, name)
+	}
+	sort.Strings(filesetNames)
+	for _, name := range filesetNames {
+		fs := allFilesets[name]
+		path := manifest.FilesetPath(name) + ".source"
 		if fs.SourceAbs == "" {
-			return apperr.New("validator.Validate", apperr.InvalidInput, "fileset %s: source path is required", name)
+			report(path, apperr.New("validator.Validate", apperr.InvalidInput, "fileset %s: source path is required", name))
+			continue
 		}
 		st, err := os.Stat(fs.SourceAbs)
 		if err != nil {
-			return apperr.Wrap("validator.Validate", apperr.NotFound, err, "fileset %s source", name)
+			report(path, apperr.Wrap("validator.Validate", apperr.NotFound, err, "fileset %s source", name))
+			continue
 		}
 		if !st.IsDir() {
-			return apperr.New("validator.Validate", apperr.InvalidInput, "fileset %s source is not a directory: %s", name, fs.SourceAbs)
+			report(path, apperr.New("validator.Validate", apperr.InvalidInput, "fileset %s source is not a directory: %s", name, fs.SourceAbs))
+			continue
 		}
+		if fs.Template {
+			if err := filesets.CheckTemplates(fs.SourceAbs, fs.Exclude); err != nil {
+				report(path, apperr.Wrap("validator.Validate", apperr.InvalidInput, err, "fileset %s has an invalid template", name))
+			}
+		}
+	}
+
+	if err := problemsError(problems); err != nil {
+		return nil, nil, err
 	}
 
-	return nil
+	warnings := filesetOverlapWarnings(cfg, composeDocs)
+	if offline {
+		return warnings, composeDocs, nil
+	}
+	warnings = append(warnings, platformWarnings(ctx, composeDocs, func(contextName string) *dockercli.Client {
+		return factory.GetClientForContext(contextName, &cfg)
+	})...)
+	return warnings, composeDocs, nil
 }
 
 // ValidateContext validates a single context's configuration.
 // This is useful for targeted validation when using --context flag.
+// Like Validate, it reports every problem it finds rather than the first.
 func ValidateContext(ctx context.Context, cfg manifest.Config, contextName string, client *dockercli.Client) error {
 	_, ok := cfg.Contexts[contextName]
 	if !ok {
 		return apperr.New("validator.ValidateContext", apperr.InvalidInput, "unknown context: %s", contextName)
 	}
 
-	// Check context is reachable
-	if err := client.CheckDaemon(ctx); err != nil {
+	var problems []error
+	report := func(path string, err error) {
+		problems = append(problems, &Problem{Path: path, Location: cfg.Source.Locate(path), Err: err})
+	}
+
+	// Check context is reachable, bounded so a host that silently drops the
+	// connection fails fast instead of hanging validation. Without a daemon
+	// only the stack files can be checked.
+	timeout, ok := client.ProbeTimeout()
+	if !ok {
+		timeout = dockercli.DefaultProbeTimeout
+	}
+	probeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	reachable := true
+	if err := client.CheckDaemon(probeCtx); err != nil {
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
-		return apperr.Wrap("validator.ValidateContext", apperr.Unavailable, err, "context %s", contextName)
+		reachable = false
+		if errors.Is(probeCtx.Err(), context.DeadlineExceeded) {
+			report("contexts."+contextName, apperr.New("validator.ValidateContext", apperr.Unavailable, "context %s: daemon unreachable (timeout after %s)", contextName, timeout))
+		} else {
+			report("contexts."+contextName, apperr.Wrap("validator.ValidateContext", apperr.Unavailable, err, "context %s", contextName))
+		}
 	}
 
 	// Identifier validation is done at project level, not per-context
 
-	// Validate stacks for this context
-	for stackName, stack := range cfg.GetStacksForContext(contextName) {
+	// Validate stacks for this context, in name order so problems are
+	// reported in a stable order.
+	stacks := cfg.GetStacksForContext(contextName)
+	stackNames := make([]string, 0, len(stacks))
+	for stackName := range stacks {
+		stackNames = append(stackNames, stackName)
+	}
+	sort.Strings(stackNames)
+	composeDocs := map[string]dockercli.ComposeConfigDoc{}
+	dockerSecrets := map[string]map[string]struct{}{}
+	for _, stackName := range stackNames {
+		stack := stacks[stackName]
 		stackKey := manifest.MakeStackKey(contextName, stackName)
+		path := "stacks." + stackKey
 
 		// Root must exist
 		if stack.Root != "" {
 			if st, err := os.Stat(stack.Root); err != nil || !st.IsDir() {
 				if err != nil {
-					return apperr.Wrap("validator.ValidateDaemon", apperr.NotFound, err, "stack %s root", stackKey)
+					report(path+".root", apperr.Wrap("validator.ValidateDaemon", apperr.NotFound, err, "stack %s root", stackKey))
+				} else {
+					report(path+".root", apperr.New("validator.ValidateDaemon", apperr.InvalidInput, "stack %s root is not a directory: %s", stackKey, stack.Root))
 				}
-				return apperr.New("validator.ValidateDaemon", apperr.InvalidInput, "stack %s root is not a directory: %s", stackKey, stack.Root)
+				continue
 			}
 		}
 
 		// Compose files
+		filesFound := true
 		for _, f := range stack.Files {
 			p := f
 			if !filepath.IsAbs(p) && stack.Root != "" {
 				p = filepath.Join(stack.Root, p)
 			}
 			if _, err := os.Stat(p); err != nil {
-				return apperr.Wrap("validator.ValidateDaemon", apperr.NotFound, err, "stack %s compose file %s", stackKey, f)
+				filesFound = false
+				report(path+".files", apperr.Wrap("validator.ValidateDaemon", apperr.NotFound, err, "stack %s compose file %s", stackKey, f))
 			}
 		}
+		if !reachable {
+			continue
+		}
 
 		// Validate compose file syntax
-		if len(stack.Files) > 0 && stack.Root != "" {
-			if _, err := client.ComposeConfigFull(ctx, stack.Root, stack.Files, stack.Profiles, []string{}, []string{}); err != nil {
+		if filesFound && len(stack.Files) > 0 && stack.Root != "" {
+			doc, err := client.ComposeConfigFull(dockercli.WithStack(ctx, stack), stack.Root, stack.Files, stack.Profiles, []string{}, []string{})
+			if err != nil {
 				if ctx.Err() != nil {
 					return ctx.Err()
 				}
-				return apperr.Wrap("validator.ValidateDaemon", apperr.External, err, "invalid compose file for stack %s", stackKey)
+				report(path+".files", apperr.Wrap("validator.ValidateDaemon", apperr.External, err, "invalid compose file for stack %s", stackKey))
+			} else {
+				composeDocs[stackKey] = doc
+			}
+		}
+
+		if err := validateDockerSecrets(ctx, client, contextName, stackKey, dockerSecretNames(stack), dockerSecrets); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
 			}
+			report(path, err)
 		}
 	}
 
-	return nil
+	if err := portConflicts(composeDocs); err != nil {
+		report("stacks", err)
+	}
+	if err := dependencyCycle(cfg, composeDocs); err != nil {
+		report("stacks", err)
+	}
+	return problemsError(problems)
 }
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/gcstr/dockform/internal/apperr"
 	"github.com/gcstr/dockform/internal/dockercli"
 	"github.com/gcstr/dockform/internal/manifest"
 )
@@ -568,3 +569,571 @@ stacks:
 		t.Errorf("identifier mismatch: expected 'my-project', got '%s'", cfg.Identifier)
 	}
 }
+
+func TestFilesetOverlapWarnings(t *testing.T) {
+	cfg := manifest.Config{
+		Contexts: map[string]manifest.ContextConfig{"default": {}},
+		DiscoveredFilesets: map[string]manifest.FilesetSpec{
+			"default/app/config": {TargetVolume: "app_config", TargetPath: "/", Context: "default", Stack: "app"},
+			"default/app/assets": {TargetVolume: "app_assets", TargetPath: "/static", Context: "default", Stack: "app"},
+		},
+	}
+	docs := map[string]dockercli.ComposeConfigDoc{
+		"default/app": {Services: map[string]dockercli.ComposeService{
+			"web": {Volumes: []dockercli.ComposeServiceVolume{
+				{Type: "volume", Source: "config", Target: "/etc/app"},
+				{Type: "volume", Source: "app_assets", Target: "/srv/uploads", Volume: &dockercli.ComposeVolumeOptions{Subpath: "uploads"}},
+			}},
+			"reader": {Volumes: []dockercli.ComposeServiceVolume{
+				{Type: "volume", Source: "config", Target: "/config", ReadOnly: true},
+			}},
+		}},
+	}
+
+	warnings := filesetOverlapWarnings(cfg, docs)
+	if len(warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %d: %v", len(warnings), warnings)
+	}
+	if !strings.Contains(warnings[0], "default/app/config") || !strings.Contains(warnings[0], "service web") {
+		t.Fatalf("unexpected warning: %s", warnings[0])
+	}
+}
+
+func TestVolumePathsOverlap(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want bool
+	}{
+		{"/", "/data", true},
+		{"/data/config", "data/config", true},
+		{"/data", "/data/config", true},
+		{"/data/config", "/data/cache", false},
+		{"/data2", "/data", false},
+	}
+	for _, c := range cases {
+		if got := volumePathsOverlap(c.a, c.b); got != c.want {
+			t.Errorf("volumePathsOverlap(%q, %q) = %v, want %v", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestValidate_DockerSecrets(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell stub")
+	}
+	dir := t.TempDir()
+	stub := `#!/bin/sh
+if [ "$1" = "secret" ] && [ "$2" = "ls" ]; then
+  echo "db_password"
+  exit 0
+fi
+exit 0
+`
+	if err := os.WriteFile(filepath.Join(dir, "docker"), []byte(stub), 0o755); err != nil {
+		t.Fatalf("write stub: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	tmp := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmp, "web"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "web", "compose.yaml"), []byte("services: {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	load := func(secret string) manifest.Config {
+		yml := "identifier: test-id\ncontexts:\n  default: {}\nstacks:\n  default/web:\n    root: web\n    files: [compose.yaml]\n    secrets:\n      docker: [" + secret + "]\n"
+		if err := os.WriteFile(filepath.Join(tmp, "dockform.yml"), []byte(yml), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := manifest.Load(tmp)
+		if err != nil {
+			t.Fatalf("load: %v", err)
+		}
+		return cfg
+	}
+
+	if err := Validate(context.Background(), load("db_password"), dockercli.NewClientFactory()); err != nil {
+		t.Fatalf("expected existing docker secret to validate, got %v", err)
+	}
+	err := Validate(context.Background(), load("api_token"), dockercli.NewClientFactory())
+	if err == nil || !strings.Contains(err.Error(), "docker secret api_token not found on context default") {
+		t.Fatalf("expected missing docker secret error, got %v", err)
+	}
+}
+
+// withCapabilitiesStub puts a docker stub on PATH that reports a linux/amd64
+// daemon whose swarm node state is swarmState.
+func withCapabilitiesStub(t *testing.T, swarmState string) {
+	t.Helper()
+	dir := t.TempDir()
+	stub := `#!/bin/sh
+case "$1" in
+  version) echo '{"Version":"27.1.1","ApiVersion":"1.46","Os":"linux","Arch":"amd64"}' ;;
+  info) echo "` + swarmState + `" ;;
+esac
+exit 0
+`
+	if err := os.WriteFile(filepath.Join(dir, "docker"), []byte(stub), 0o755); err != nil {
+		t.Fatalf("write stub: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestValidate_DockerSecretsRequireSwarm(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell stub")
+	}
+	withCapabilitiesStub(t, "inactive")
+
+	tmp := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmp, "web"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "web", "compose.yaml"), []byte("services: {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	yml := "identifier: test-id\ncontexts:\n  default: {}\nstacks:\n  default/web:\n    root: web\n    files: [compose.yaml]\n    secrets:\n      docker: [db_password]\n"
+	if err := os.WriteFile(filepath.Join(tmp, "dockform.yml"), []byte(yml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := manifest.Load(tmp)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	err = Validate(context.Background(), cfg, dockercli.NewClientFactory())
+	if err == nil || !apperr.IsKind(err, apperr.Precondition) || !strings.Contains(err.Error(), "require swarm mode") {
+		t.Fatalf("expected swarm precondition error, got %v", err)
+	}
+}
+
+func TestPlatformWarnings(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell stub")
+	}
+	withCapabilitiesStub(t, "inactive")
+
+	docs := map[string]dockercli.ComposeConfigDoc{
+		"default/web": {Services: map[string]dockercli.ComposeService{
+			"native":   {Platform: "linux/amd64"},
+			"emulated": {Platform: "linux/arm64/v8"},
+			"unpinned": {},
+		}},
+	}
+	client := dockercli.New("")
+	warnings := platformWarnings(context.Background(), docs, func(string) *dockercli.Client { return client })
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "stack default/web service emulated: platform linux/arm64/v8 does not match the linux/amd64 daemon") {
+		t.Fatalf("expected a single emulation warning, got %v", warnings)
+	}
+}
+
+func TestUnusedDeclarations(t *testing.T) {
+	cfg := manifest.Config{
+		Contexts: map[string]manifest.ContextConfig{
+			"default": {
+				Volumes:  map[string]manifest.TopLevelResourceSpec{"pgdata": {}, "stale": {}},
+				Networks: map[string]manifest.NetworkSpec{"proxy": {}, "typo-net": {}},
+			},
+		},
+		Stacks: map[string]manifest.Stack{"default/app": {}},
+		DiscoveredFilesets: map[string]manifest.FilesetSpec{
+			"default/app/config": {TargetVolume: "app_config", Context: "default"},
+			"default/app/assets": {TargetVolume: "assets", Context: "default"},
+		},
+	}
+	docs := map[string]dockercli.ComposeConfigDoc{
+		"default/app": {
+			Services: map[string]dockercli.ComposeService{
+				"db":  {Volumes: []dockercli.ComposeServiceVolume{{Type: "volume", Source: "db"}}},
+				"web": {Volumes: []dockercli.ComposeServiceVolume{{Type: "volume", Source: "config"}}, Networks: dockercli.ComposeServiceNetworks{"edge"}},
+			},
+			Volumes:  map[string]dockercli.ComposeTopLevelResource{"db": {Name: "pgdata"}},
+			Networks: map[string]dockercli.ComposeTopLevelResource{"edge": {Name: "proxy"}},
+		},
+	}
+
+	got := UnusedDeclarations(cfg, docs)
+	want := []string{
+		"fileset default/app/assets targets volume assets, which no service in context default mounts",
+		"network typo-net in context default is declared but not used by any service",
+		"volume stale in context default is declared but not used by any service",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unused declarations mismatch\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func TestValidateWithComposeDocs_ReturnsResolvedStacks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell stub")
+	}
+	dir := t.TempDir()
+	stub := `#!/bin/sh
+if [ "$1" = "compose" ]; then
+  echo '{"services":{"web":{"image":"nginx","volumes":[{"type":"volume","source":"data"}]}}}'
+fi
+exit 0
+`
+	if err := os.WriteFile(filepath.Join(dir, "docker"), []byte(stub), 0o755); err != nil {
+		t.Fatalf("write stub: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	tmp := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmp, "web"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "web", "compose.yaml"), []byte("services: {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	yml := "identifier: test-id\ncontexts:\n  default:\n    volumes:\n      data: {}\n      stale: {}\nstacks:\n  default/web:\n    root: web\n    files: [compose.yaml]\n"
+	if err := os.WriteFile(filepath.Join(tmp, "dockform.yml"), []byte(yml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := manifest.Load(tmp)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	_, docs, err := ValidateWithComposeDocs(context.Background(), cfg, dockercli.NewClientFactory())
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if _, ok := docs["default/web"].Services["web"]; !ok {
+		t.Fatalf("expected the resolved compose config of default/web, got %+v", docs)
+	}
+	unused := UnusedDeclarations(cfg, docs)
+	if len(unused) != 1 || !strings.Contains(unused[0], "volume stale") {
+		t.Fatalf("expected only the stale volume reported, got %v", unused)
+	}
+}
+
+func TestPortConflicts(t *testing.T) {
+	svc := func(ports ...dockercli.ComposePort) dockercli.ComposeService {
+		return dockercli.ComposeService{Ports: ports}
+	}
+	docs := map[string]dockercli.ComposeConfigDoc{
+		"default/web": {Services: map[string]dockercli.ComposeService{
+			"nginx": svc(dockercli.ComposePort{Target: 80, Published: "8080", Protocol: "tcp"},
+				dockercli.ComposePort{Target: 53, Published: "5353", Protocol: "udp"}),
+			"range": svc(dockercli.ComposePort{Target: 9000, Published: "9000-9005"}),
+		}},
+		"default/api": {Services: map[string]dockercli.ComposeService{
+			"app":   svc(dockercli.ComposePort{Target: 3000, Published: float64(8080)}),
+			"dns":   svc(dockercli.ComposePort{Target: 53, Published: "5353", Protocol: "tcp"}), // different protocol
+			"batch": svc(dockercli.ComposePort{Target: 9000, Published: "9004-9010"}),
+			"local": svc(dockercli.ComposePort{Target: 1, Published: "7000", HostIP: "127.0.0.1"}),
+			"temp":  svc(dockercli.ComposePort{Target: 1}), // ephemeral host port
+		}},
+		"default/admin": {Services: map[string]dockercli.ComposeService{
+			"other": svc(dockercli.ComposePort{Target: 1, Published: "7000", HostIP: "127.0.0.2"}), // different IP
+		}},
+		"remote/web": {Services: map[string]dockercli.ComposeService{
+			"nginx": svc(dockercli.ComposePort{Target: 80, Published: "8080"}), // different daemon
+		}},
+	}
+
+	err := portConflicts(docs)
+	if err == nil {
+		t.Fatalf("expected port conflicts")
+	}
+	msg := err.Error()
+	for _, want := range []string{
+		"2 host port conflict(s)",
+		"host port 8080/tcp published by both default/api (service app) and default/web (service nginx)",
+		"host ports 9004-9005/tcp published by both default/api (service batch) and default/web (service range)",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Fatalf("expected %q in error, got: %s", want, msg)
+		}
+	}
+	for _, unwanted := range []string{"5353", "7000", "remote/"} {
+		if strings.Contains(msg, unwanted) {
+			t.Fatalf("did not expect %q in error, got: %s", unwanted, msg)
+		}
+	}
+
+	delete(docs, "default/api")
+	if err := portConflicts(docs); err != nil {
+		t.Fatalf("expected no conflicts, got %v", err)
+	}
+}
+
+func TestValidate_MissingComposeFile_SuggestsRootRelativePath(t *testing.T) {
+	defer withStubDocker(t)()
+	tmp := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmp, "stacks", "website"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "stacks", "website", "compose.yaml"), []byte("services: {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// The file is listed relative to the manifest, but compose resolves it
+	// against the stack root.
+	yml := "identifier: test-id\ncontexts:\n  default: {}\nstacks:\n  default/website:\n    root: stacks/website\n    files:\n      - stacks/website/compose.yaml\n"
+	if err := os.WriteFile(filepath.Join(tmp, "dockform.yml"), []byte(yml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := manifest.Load(tmp)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	err = Validate(context.Background(), cfg, dockercli.NewClientFactory())
+	if err == nil || !apperr.IsKind(err, apperr.NotFound) || !strings.Contains(err.Error(), `did you mean "compose.yaml"?`) {
+		t.Fatalf("expected a root-relative suggestion, got %v", err)
+	}
+}
+
+func TestSuggestComposePath(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "website")
+	if err := os.MkdirAll(filepath.Join(root, "deploy"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{"compose.yml", "deploy/override.yaml"} {
+		if err := os.WriteFile(filepath.Join(root, f), []byte("services: {}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := map[string]string{
+		"website/deploy/override.yaml": "deploy/override.yaml", // relative to the manifest
+		"compose.yaml":                 "compose.yml",          // another default name
+		"missing.yaml":                 "",
+	}
+	for file, want := range cases {
+		if got := suggestComposePath(base, root, file); got != want {
+			t.Errorf("suggestComposePath(%q) = %q, want %q", file, got, want)
+		}
+	}
+}
+
+func TestValidateOffline_ChecksFilesWithoutDocker(t *testing.T) {
+	// No docker stub: offline validation must not run the docker CLI at all.
+	t.Setenv("PATH", t.TempDir())
+	tmp := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmp, "website"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	compose := filepath.Join(tmp, "website", "docker-compose.yaml")
+	if err := os.WriteFile(compose, []byte("services:\n  web:\n    image: nginx\n"), 0o644); err != nil {
+		t.Fatalf("write compose: %v", err)
+	}
+	yml := "identifier: test-id\ncontexts:\n  default: {}\nstacks:\n  default/website:\n    root: website\n    files:\n      - docker-compose.yaml\n"
+	if err := os.WriteFile(filepath.Join(tmp, "dockform.yml"), []byte(yml), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	cfg, err := manifest.Load(tmp)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if _, err := ValidateOffline(context.Background(), cfg); err != nil {
+		t.Fatalf("expected offline validation to pass, got: %v", err)
+	}
+
+	if err := os.WriteFile(compose, []byte("services:\n  web: [\n"), 0o644); err != nil {
+		t.Fatalf("write compose: %v", err)
+	}
+	_, err = ValidateOffline(context.Background(), cfg)
+	if !apperr.IsKind(err, apperr.InvalidInput) || !strings.Contains(err.Error(), "invalid compose file docker-compose.yaml") {
+		t.Fatalf("expected malformed compose to be rejected, got: %v", err)
+	}
+}
+
+func TestDependencyCycle(t *testing.T) {
+	cfg := manifest.Config{
+		Contexts: map[string]manifest.ContextConfig{"default": {}},
+		DiscoveredFilesets: map[string]manifest.FilesetSpec{
+			"default/app/config": {TargetVolume: "app_config", TargetPath: "/", Context: "default", Stack: "app",
+				RestartServices: manifest.RestartTargets{Services: []string{"web"}}},
+		},
+	}
+	docs := map[string]dockercli.ComposeConfigDoc{
+		"default/app": {Services: map[string]dockercli.ComposeService{
+			"web": {DependsOn: []string{"db"}, Volumes: []dockercli.ComposeServiceVolume{
+				{Type: "volume", Source: "config", Target: "/etc/app"},
+			}},
+			"db": {},
+		}},
+	}
+
+	// A fileset restarting the service that mounts its volume is not a cycle.
+	if err := dependencyCycle(cfg, docs); err != nil {
+		t.Fatalf("expected no cycle, got %v", err)
+	}
+
+	docs["default/app"].Services["db"] = dockercli.ComposeService{DependsOn: []string{"cache"}}
+	docs["default/app"].Services["cache"] = dockercli.ComposeService{DependsOn: []string{"web"}}
+	err := dependencyCycle(cfg, docs)
+	if err == nil {
+		t.Fatalf("expected dependency cycle")
+	}
+	if !apperr.IsKind(err, apperr.InvalidInput) {
+		t.Fatalf("expected InvalidInput, got %v", err)
+	}
+	want := "dependency cycle: service default/app/cache -> service default/app/web -> service default/app/db -> service default/app/cache"
+	if !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected %q in error, got: %s", want, err)
+	}
+}
+
+func TestDependencyCycle_FilesetRestartingServiceThatNeedsItsVolume(t *testing.T) {
+	cfg := manifest.Config{
+		Contexts: map[string]manifest.ContextConfig{"default": {}},
+		DiscoveredFilesets: map[string]manifest.FilesetSpec{
+			"default/app/config": {TargetVolume: "app_config", TargetPath: "/", Context: "default", Stack: "app",
+				RestartServices: manifest.RestartTargets{Services: []string{"web"}}},
+		},
+	}
+	docs := map[string]dockercli.ComposeConfigDoc{
+		"default/app": {Services: map[string]dockercli.ComposeService{
+			"web": {DependsOn: []string{"db"}},
+			"db": {Volumes: []dockercli.ComposeServiceVolume{
+				{Type: "volume", Source: "config", Target: "/etc/db"},
+			}},
+		}},
+	}
+
+	err := dependencyCycle(cfg, docs)
+	if err == nil {
+		t.Fatalf("expected dependency cycle")
+	}
+	if !apperr.IsKind(err, apperr.InvalidInput) {
+		t.Fatalf("expected InvalidInput, got %v", err)
+	}
+	want := "dependency cycle: fileset default/app/config -> service default/app/web -> service default/app/db -> volume default/app_config -> fileset default/app/config"
+	if !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected %q in error, got: %s", want, err)
+	}
+}
+
+func TestDependencyCycle_ThroughPreApplyHooks(t *testing.T) {
+	cfg := manifest.Config{
+		Contexts: map[string]manifest.ContextConfig{"default": {}},
+		Stacks: map[string]manifest.Stack{
+			"default/app": {Hooks: &manifest.StackHooks{PreApply: [][]string{{"migrate"}}, Service: "tools"}},
+		},
+		DiscoveredFilesets: map[string]manifest.FilesetSpec{
+			"default/app/config": {TargetVolume: "app_config", TargetPath: "/", Context: "default", Stack: "app",
+				RestartServices: manifest.RestartTargets{Services: []string{"web"}}},
+		},
+	}
+	docs := map[string]dockercli.ComposeConfigDoc{
+		"default/app": {Services: map[string]dockercli.ComposeService{
+			"web": {},
+			"tools": {Volumes: []dockercli.ComposeServiceVolume{
+				{Type: "volume", Source: "config", Target: "/etc/app"},
+			}},
+		}},
+	}
+
+	err := dependencyCycle(cfg, docs)
+	if err == nil {
+		t.Fatalf("expected dependency cycle")
+	}
+	want := "dependency cycle: fileset default/app/config -> service default/app/web -> hooks default/app -> volume default/app_config -> fileset default/app/config"
+	if !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected %q in error, got: %s", want, err)
+	}
+
+	// Without the restart nothing needs the fileset but the volume.
+	fs := cfg.DiscoveredFilesets["default/app/config"]
+	fs.RestartServices = manifest.RestartTargets{}
+	cfg.DiscoveredFilesets["default/app/config"] = fs
+	if err := dependencyCycle(cfg, docs); err != nil {
+		t.Fatalf("expected no cycle, got %v", err)
+	}
+}
+
+func TestValidate_ReportsEveryProblem(t *testing.T) {
+	defer withStubDocker(t)()
+	tmp := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmp, "website"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "website", "docker-compose.yaml"), []byte("services: {}\n"), 0o644); err != nil {
+		t.Fatalf("write compose: %v", err)
+	}
+	yml := `identifier: test-id
+contexts:
+  default: {}
+stacks:
+  default/website:
+    root: website
+    files:
+      - docker-compose.yaml
+    env-file:
+      - missing.env
+  default/api:
+    root: api
+    files:
+      - compose.yaml
+`
+	if err := os.WriteFile(filepath.Join(tmp, "dockform.yml"), []byte(yml), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	cfg, err := manifest.Load(tmp)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	err = Validate(context.Background(), cfg, dockercli.NewClientFactory())
+	problems := Problems(err)
+	if len(problems) != 2 {
+		t.Fatalf("expected 2 problems, got %d: %v", len(problems), err)
+	}
+	if problems[0].Path != "stacks.default/api.root" || problems[1].Path != "stacks.default/website.env-file" {
+		t.Fatalf("unexpected problem paths: %s, %s", problems[0].Path, problems[1].Path)
+	}
+	manifestPath := filepath.Join(tmp, "dockform.yml")
+	if problems[0].Location != manifestPath+":12" || problems[1].Location != manifestPath+":9" {
+		t.Fatalf("unexpected problem locations: %s, %s", problems[0].Location, problems[1].Location)
+	}
+	if !strings.Contains(err.Error(), "2 problems found") || !strings.Contains(err.Error(), manifestPath+":9: stack default/website env file missing.env") {
+		t.Fatalf("expected the error to list every problem, got: %v", err)
+	}
+	if !apperr.IsKind(err, apperr.InvalidInput) {
+		t.Fatalf("expected InvalidInput, got: %v", err)
+	}
+}
+
+func TestValidateContext_ReportsEveryProblem(t *testing.T) {
+	defer withStubDocker(t)()
+	tmp := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmp, "website"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	yml := `identifier: test-id
+contexts:
+  default: {}
+stacks:
+  default/website:
+    root: website
+    files:
+      - docker-compose.yaml
+  default/api:
+    root: api
+    files:
+      - compose.yaml
+`
+	if err := os.WriteFile(filepath.Join(tmp, "dockform.yml"), []byte(yml), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	cfg, err := manifest.Load(tmp)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	err = ValidateContext(context.Background(), cfg, "default", dockercli.New(""))
+	problems := Problems(err)
+	if len(problems) != 2 {
+		t.Fatalf("expected 2 problems, got %d: %v", len(problems), err)
+	}
+	if problems[0].Path != "stacks.default/api.root" || problems[1].Path != "stacks.default/website.files" {
+		t.Fatalf("unexpected problem paths: %s, %s", problems[0].Path, problems[1].Path)
+	}
+	manifestPath := filepath.Join(tmp, "dockform.yml")
+	if problems[0].Location != manifestPath+":10" || problems[1].Location != manifestPath+":7" {
+		t.Fatalf("unexpected problem locations: %s, %s", problems[0].Location, problems[1].Location)
+	}
+}
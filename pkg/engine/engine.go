@@ -0,0 +1,153 @@
+// Package engine exposes dockform's core operations — plan, apply, prune and
+// destroy — without cobra, for embedding dockform in other programs such as a
+// web UI. User-facing messages go to an io.Writer and progress is reported as
+// structured events.
+package engine
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/gcstr/dockform/internal/dockercli"
+	"github.com/gcstr/dockform/internal/manifest"
+	"github.com/gcstr/dockform/internal/planner"
+	"github.com/gcstr/dockform/internal/ui"
+	"github.com/gcstr/dockform/internal/validator"
+)
+
+// Options configures an Engine.
+type Options struct {
+	// Out receives user-facing messages (infos, warnings, errors). Defaults to io.Discard.
+	Out io.Writer
+	// OnEvent receives structured progress events. Optional.
+	OnEvent func(Event)
+	// Sequential disables parallel processing across contexts and stacks.
+	Sequential bool
+}
+
+// CleanupOptions controls how Prune and Destroy treat removal failures.
+type CleanupOptions struct {
+	// Strict makes removal failures fail the call; otherwise they are
+	// reported as warnings.
+	Strict bool
+}
+
+// StackResult is the outcome of applying one stack.
+type StackResult struct {
+	Context   string
+	Stack     string
+	Created   []string // Services created
+	Updated   []string // Services recreated
+	Skipped   []string // Services left as they were
+	Restarted []string // Services restarted for changed filesets
+	Duration  time.Duration
+	Err       error
+}
+
+// Engine runs dockform operations for one loaded manifest.
+type Engine struct {
+	cfg     manifest.Config
+	factory *dockercli.DefaultClientFactory
+	planner *planner.Planner
+	pr      ui.Printer
+	onEvent func(Event)
+}
+
+// Load reads and normalizes the manifest at path and returns an Engine for it.
+// Warnings about unset environment variables are written to opts.Out.
+func Load(path string, opts Options) (*Engine, error) {
+	cfg, missing, err := manifest.LoadWithWarnings(path)
+	if err != nil {
+		return nil, err
+	}
+	e := newEngine(cfg, opts)
+	for _, name := range missing {
+		e.warn("environment variable " + name + " is not set; replacing with empty string")
+	}
+	return e, nil
+}
+
+func newEngine(cfg manifest.Config, opts Options) *Engine {
+	out := opts.Out
+	if out == nil {
+		out = io.Discard
+	}
+	factory := dockercli.NewClientFactory()
+	pr := ui.StdPrinter{Out: out, Err: out}
+	p := planner.NewWithFactory(factory).WithPrinter(pr).WithParallel(!opts.Sequential)
+	if opts.OnEvent != nil {
+		p = p.WithEvents(eventHandler(opts.OnEvent))
+	}
+	return &Engine{cfg: cfg, factory: factory, planner: p, pr: pr, onEvent: opts.OnEvent}
+}
+
+// warn prints a warning of the engine's own and reports it as an event, as
+// the planner does with its warnings.
+func (e *Engine) warn(msg string) {
+	e.pr.Warn("%s", msg)
+	if e.onEvent != nil {
+		eventHandler(e.onEvent).Warning(msg)
+	}
+}
+
+// Identifier returns the manifest's identifier, which scopes every resource
+// the engine manages.
+func (e *Engine) Identifier() string { return e.cfg.Identifier }
+
+// Validate checks the manifest and environment. Non-fatal warnings are
+// printed and reported as warning events.
+func (e *Engine) Validate(ctx context.Context) error {
+	warnings, err := validator.ValidateWithWarnings(ctx, e.cfg, e.factory)
+	if err != nil {
+		return err
+	}
+	for _, w := range warnings {
+		e.warn(w)
+	}
+	return nil
+}
+
+// BuildPlan computes the changes needed to reach the desired state.
+func (e *Engine) BuildPlan(ctx context.Context) (*Plan, error) {
+	p, err := e.planner.BuildPlan(ctx, e.cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Plan{p: p}, nil
+}
+
+// Apply executes a plan built by BuildPlan and returns the outcome of each
+// stack. It does not prune; call Prune with the same plan afterwards to remove
+// orphaned resources.
+func (e *Engine) Apply(ctx context.Context, plan *Plan) ([]StackResult, error) {
+	results, err := e.planner.ApplyWithPlan(ctx, e.cfg, plan.unwrap())
+	out := make([]StackResult, 0, len(results))
+	for _, r := range results {
+		out = append(out, StackResult{
+			Context: r.Context, Stack: r.Stack,
+			Created: r.Created, Updated: r.Updated, Skipped: r.Skipped, Restarted: r.Restarted,
+			Duration: r.Duration, Err: r.Err,
+		})
+	}
+	return out, err
+}
+
+// Prune removes labeled resources the manifest no longer declares.
+func (e *Engine) Prune(ctx context.Context, plan *Plan, opts CleanupOptions) error {
+	return e.planner.PruneWithPlanOptions(ctx, e.cfg, plan.unwrap(), planner.CleanupOptions{Strict: opts.Strict})
+}
+
+// BuildDestroyPlan lists every labeled resource Destroy would remove.
+func (e *Engine) BuildDestroyPlan(ctx context.Context) (*Plan, error) {
+	p, err := e.planner.BuildDestroyPlan(ctx, e.cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Plan{p: p}, nil
+}
+
+// Destroy removes every labeled resource of the manifest's identifier.
+func (e *Engine) Destroy(ctx context.Context, opts CleanupOptions) error {
+	return e.planner.DestroyWithOptions(ctx, e.cfg, planner.CleanupOptions{Strict: opts.Strict})
+}
@@ -0,0 +1,45 @@
+package engine_test
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/gcstr/dockform/internal/cli/clitest"
+	"github.com/gcstr/dockform/pkg/engine"
+)
+
+func TestEngine_BuildPlanEmitsEvents(t *testing.T) {
+	defer clitest.WithStubDocker(t)()
+	var mu sync.Mutex
+	var events []engine.Event
+	var out bytes.Buffer
+
+	eng, err := engine.Load(clitest.BasicConfigPath(t), engine.Options{Out: &out, OnEvent: func(e engine.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, e)
+	}})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	plan, err := eng.BuildPlan(context.Background())
+	if err != nil {
+		t.Fatalf("build plan: %v", err)
+	}
+	if len(plan.Changes()) == 0 || plan.String() == "" {
+		t.Fatalf("expected a plan with resources, got %q", plan.String())
+	}
+	var started, finished bool
+	for _, e := range events {
+		if e.Action != "plan_build" {
+			continue
+		}
+		started = started || e.Kind == engine.EventStarted
+		finished = finished || e.Kind == engine.EventFinished
+	}
+	if !started || !finished {
+		t.Fatalf("expected plan_build started and finished events, got %+v", events)
+	}
+}
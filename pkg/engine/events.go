@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/gcstr/dockform/internal/planner"
+)
+
+// EventKind classifies an Event.
+type EventKind string
+
+const (
+	EventStarted  EventKind = "started"  // Work on a resource began
+	EventFinished EventKind = "finished" // Work on a resource succeeded
+	EventFailed   EventKind = "failed"   // Work on a resource failed; Error holds why
+	EventWarning  EventKind = "warning"  // A non-fatal problem was reported; Message holds it
+)
+
+// Event is a structured progress notification. OnEvent may be called
+// concurrently because contexts are processed in parallel.
+type Event struct {
+	Kind         EventKind
+	Action       string        // What the step does, e.g. "network_create"
+	ResourceKind string        // e.g. "network", "volume" or "stack"
+	Resource     string        // Resource name
+	Context      string        // Docker context of the resource, when known
+	Changed      bool          // Whether a finished step changed anything
+	Duration     time.Duration // Step duration for finished and failed events
+	Error        string        // Failure message for failed events
+	Message      string        // Warning text for warning events
+}
+
+// eventHandler adapts an OnEvent callback to the planner's events.
+type eventHandler func(Event)
+
+func (h eventHandler) ResourceStarted(e planner.ResourceEvent) {
+	h(Event{Kind: EventStarted, Action: e.Action, ResourceKind: e.Kind, Resource: e.Name, Context: e.Context})
+}
+
+func (h eventHandler) ResourceFinished(e planner.ResourceEvent) {
+	ev := Event{
+		Kind: EventFinished, Action: e.Action, ResourceKind: e.Kind, Resource: e.Name, Context: e.Context,
+		Changed: e.Changed, Duration: e.Duration, Error: e.Err,
+	}
+	if e.Err != "" {
+		ev.Kind = EventFailed
+	}
+	h(ev)
+}
+
+func (h eventHandler) Warning(message string) {
+	h(Event{Kind: EventWarning, Message: message})
+}
@@ -0,0 +1,46 @@
+package engine
+
+import "github.com/gcstr/dockform/internal/planner"
+
+// Plan is a computed set of changes, as returned by BuildPlan and
+// BuildDestroyPlan.
+type Plan struct {
+	p *planner.Plan
+}
+
+// unwrap returns the planner's plan, or nil for a nil Plan.
+func (p *Plan) unwrap() *planner.Plan {
+	if p == nil {
+		return nil
+	}
+	return p.p
+}
+
+// Change is one resource of a plan and the action planned for it.
+type Change struct {
+	Kind    string // "volume", "network", "service", "container", "fileset" or "file"
+	Name    string // e.g. "data" for a volume or "web/nginx" for a service
+	Parent  string // Fileset of a file change; empty otherwise
+	Action  string // "create", "update", "delete", "reconcile" or "no-op"
+	Details string
+}
+
+// String renders the plan's pending changes as the plan command prints them.
+func (p *Plan) String() string {
+	return p.p.Render(planner.PlanRenderOptions{})
+}
+
+// HasChanges reports whether applying the plan would change anything.
+func (p *Plan) HasChanges() bool {
+	return p.p.Resources.HasChanges()
+}
+
+// Changes lists every resource of the plan, unchanged ones included.
+func (p *Plan) Changes() []Change {
+	all := p.p.Resources.AllResources()
+	out := make([]Change, 0, len(all))
+	for _, r := range all {
+		out = append(out, Change{Kind: string(r.Type), Name: r.Name, Parent: r.Parent, Action: string(r.Action), Details: r.Details})
+	}
+	return out
+}